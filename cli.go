@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	"togo/internal/query"
+	"togo/internal/service"
+	"togo/internal/termlink"
+)
+
+// command is a CLI subcommand handler. It receives the arguments following the
+// subcommand name and returns a process exit code.
+type command func(args []string, stdout, stderr io.Writer) int
+
+// commands maps subcommand names to their handlers. Running togo without a
+// subcommand is equivalent to "togo ui".
+var commands = map[string]command{
+	"ui":       runUI,
+	"init":     runInit,
+	"list":     runList,
+	"filter":   runFilter,
+	"backup":   runBackup,
+	"export":   runExport,
+	"import":   runImport,
+	"archive":  runArchive,
+	"doctor":   runDoctor,
+	"undo":     runUndo,
+	"rollover": runRollover,
+	"remind":   runRemind,
+	"stats":    runStats,
+	"review":   runReview,
+	"tag":      runTag,
+	"audit":    runAudit,
+	"xref":     runXRef,
+	"widget":   runWidget,
+	"snooze":   runSnooze,
+}
+
+// launchTUI starts the interactive program. It is a variable so tests can
+// observe the model that would be launched without a terminal.
+var launchTUI = func(m model) error {
+	_, err := tea.NewProgram(m).Run()
+	return err
+}
+
+// run dispatches args to a subcommand and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	args, err := parseGlobalFlags(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo: %v\n", err)
+		return 2
+	}
+
+	name := "ui"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "togo: unknown command %q\n\n", name)
+		printUsage(stderr)
+		return 2
+	}
+	if name != "init" {
+		if err := ensureConfigured(stdout); err != nil {
+			fmt.Fprintf(stderr, "togo: %v\n", err)
+			return 1
+		}
+	}
+	return cmd(args, stdout, stderr)
+}
+
+// globalFlags maps the spellings of the flags accepted before the command
+// name to their canonical form.
+var globalFlags = map[string]string{
+	"--journal": "--journal", "-journal": "--journal",
+	"--data-dir": "--data-dir", "-data-dir": "--data-dir",
+}
+
+// parseGlobalFlags consumes the flags that may precede the command name,
+// recording them for the command, and returns the remaining arguments.
+func parseGlobalFlags(args []string) ([]string, error) {
+	journalFlag, dataDirFlag = "", ""
+	for len(args) > 0 {
+		name, value, hasValue := strings.Cut(args[0], "=")
+		name, ok := globalFlags[name]
+		if !ok {
+			break
+		}
+		args = args[1:]
+		if !hasValue {
+			if len(args) == 0 {
+				return nil, errors.New(name + " needs a value")
+			}
+			value, args = args[0], args[1:]
+		}
+		switch name {
+		case "--journal":
+			if err := journals.ValidateName(value); err != nil {
+				return nil, err
+			}
+			journalFlag = value
+		case "--data-dir":
+			if value == "" {
+				return nil, errors.New("--data-dir needs a directory")
+			}
+			dataDirFlag = value
+		}
+	}
+	return args, nil
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage: togo [--journal NAME] [--data-dir DIR] [command] [flags]
+
+Commands:
+  ui       open the interactive task list (default)
+  init     create the configuration file interactively
+  list     print the tasks matching a saved filter or a query
+  filter   list, save and delete named filters
+  backup   list and restore journal backups
+  export   write tasks in another tool's format
+  import   read tasks from another tool's format
+  archive  move long-completed tasks into yearly archive files
+  doctor   check the journal for damage and repair it
+  undo     revert the latest change, or redo it with --redo
+  rollover return unfinished today tasks to the pool, pull in scheduled ones
+  remind   list today's coming reminders, or notify of them with --daemon
+  stats    report completions, task age, deferrals and tags over a range
+  review   walk through stale, overdue and often deferred tasks one by one
+  tag      list tags, and rename or merge them on every task
+  audit    list the changes made to the journal since a day
+  xref     find the tasks imported from another tool's record by its ID
+  widget   summarize today's list and any review reminders in a few lines
+  snooze   hide a task from the lists for a while, like 2d or until tomorrow
+  help     show this message
+
+Global flags:
+  --journal NAME  use the named journal instead of the configured default
+  --data-dir DIR  keep journals in DIR; overrides $TOGO_DATA_DIR, which
+                  overrides data_dir in the configuration file
+`)
+}
+
+// View names accepted by "togo ui --view".
+const (
+	viewList  = "list"
+	viewBoard = "board"
+)
+
+// uiOptions configures the initial state of the TUI.
+type uiOptions struct {
+	// query is a filter expression applied before the first render.
+	query string
+	// view selects the initial view.
+	view string
+	// demo shows sample tasks held in memory instead of the journal.
+	demo bool
+}
+
+// runUI launches the TUI, optionally pre-applying a filter and view:
+//
+//	togo ui --query "tag:work due<friday" --view board
+//
+// With --demo it shows a sandbox of sample tasks that is discarded on exit.
+func runUI(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ui", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var opts uiOptions
+	fs.StringVar(&opts.query, "query", "", "filter expression applied on launch")
+	savedName := fs.String("filter", "", "name of a saved filter applied on launch")
+	fs.StringVar(&opts.view, "view", viewList, "initial view: list or board")
+	fs.BoolVar(&opts.demo, "demo", false, "explore sample tasks without touching the journal")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo ui: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	if opts.view != viewList && opts.view != viewBoard {
+		fmt.Fprintf(stderr, "togo ui: unknown view %q (want %s or %s)\n", opts.view, viewList, viewBoard)
+		return 2
+	}
+
+	saved, err := loadSavedFilters()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo ui: %v\n", err)
+		return 1
+	}
+	if *savedName != "" {
+		if opts.query != "" {
+			fmt.Fprintln(stderr, "togo ui: --filter and --query cannot be combined")
+			return 2
+		}
+		f, ok := saved.Lookup(*savedName)
+		if !ok {
+			fmt.Fprintf(stderr, "togo ui: no saved filter named %q\n", *savedName)
+			return 2
+		}
+		opts.query = f.Query
+	}
+
+	filter, err := query.Parse(opts.query)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo ui: %v\n", err)
+		return 2
+	}
+	if err := filter.Validate(); err != nil {
+		fmt.Fprintf(stderr, "togo ui: warning: query can never match: %v\n", err)
+	}
+
+	m := initializeModel()
+	m.opts = opts
+	m.terminal = stdout
+	m.filter = filter
+	m.saved = saved
+	if opts.demo {
+		repo, err := demoRepository()
+		if err != nil {
+			fmt.Fprintf(stderr, "togo ui: %v\n", err)
+			return 1
+		}
+		m.tasks = service.New(repo, nil)
+		m.links = config.Default().Linker(os.Getenv)
+		m = m.refresh()
+	} else {
+		session, err := openSession()
+		if err != nil {
+			fmt.Fprintf(stderr, "togo ui: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if err := session.finish(); err != nil {
+				fmt.Fprintf(stderr, "togo ui: warning: %v\n", err)
+			}
+		}()
+		if m, err = session.attach(m); err != nil {
+			fmt.Fprintf(stderr, "togo ui: %v\n", err)
+			return 1
+		}
+	}
+	m.whatsNew = pendingWhatsNew(stderr)
+	if err := launchTUI(m); err != nil {
+		fmt.Fprintf(stderr, "Alas, there's been an error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// outputLinker returns how output written to w links URLs and external
+// refs: with hyperlinks only when w is a terminal that renders them.
+func outputLinker(cfg config.Config, w io.Writer) termlink.Linker {
+	links := cfg.Linker(os.Getenv)
+	if f, ok := w.(*os.File); !ok || !isTerminal(f) {
+		links.Enabled = false
+	}
+	return links
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
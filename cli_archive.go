@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"togo/internal/archive"
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/jsonstore"
+)
+
+// runArchive implements "togo archive": move done tasks completed long
+// ago out of the journal into yearly archive files beside it.
+//
+//	togo archive
+//	togo archive --older-than 30 --dry-run
+func runArchive(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	days := fs.Int("older-than", -1, "archive tasks completed more than this many days ago (default: archive_after_days)")
+	dryRun := fs.Bool("dry-run", false, "report what would be archived without changing anything")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo archive: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo archive: %v\n", err)
+		return 1
+	}
+	age := cfg.ArchiveAge()
+	if *days >= 0 {
+		age = time.Duration(*days) * 24 * time.Hour
+	} else if age == 0 {
+		fmt.Fprintln(stderr, "togo archive: archiving is disabled; set archive_after_days or pass --older-than")
+		return 2
+	}
+	cutoff := taskmodel.Now().Add(-age)
+
+	if *dryRun {
+		repo, err := openRepository(cfg, activeJournal(cfg))
+		if err != nil {
+			fmt.Fprintf(stderr, "togo archive: %v\n", err)
+			return 1
+		}
+		defer closeRepository(repo)
+
+		done := taskmodel.StatusDone
+		tasks, err := repo.List(taskmodel.TaskFilter{Status: &done})
+		if err != nil {
+			fmt.Fprintf(stderr, "togo archive: %v\n", err)
+			return 1
+		}
+		var due []*taskmodel.Task
+		for _, t := range tasks {
+			if archive.Finished(t).Before(cutoff) {
+				due = append(due, t)
+			}
+		}
+		fmt.Fprintf(stdout, "Would archive %d %s.\n", len(due), plural(len(due), "task"))
+		for _, t := range due {
+			fmt.Fprintf(stdout, "  - %s\n", t.Title)
+		}
+		return 0
+	}
+
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo archive: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo archive: warning: %v\n", err)
+		}
+	}()
+	n, err := tasks.ArchiveDone(cutoff)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo archive: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Archived %d %s.\n", n, plural(n, "task"))
+	return 0
+}
+
+// journalArchive returns the named journal's archive. Its files are JSON
+// journals whatever the backend, encrypted like the journal.
+func journalArchive(cfg config.Config, name string) (*archive.Archive, error) {
+	path, err := journalPath(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := journalKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return archive.New(archive.Dir(path), func(path string) (repository.TaskRepository, error) {
+		repo := jsonstore.New(path)
+		if keyring != nil {
+			repo.EnableEncryption(keyring)
+		}
+		return repo, nil
+	}), nil
+}
+
+// searchJournal returns the active journal's tasks matching filter and,
+// with includeArchive, its archived tasks too, ordered and paged together.
+func searchJournal(filter taskmodel.TaskFilter, includeArchive bool) ([]*taskmodel.Task, error) {
+	if !includeArchive {
+		return listJournal(filter)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	a, err := journalArchive(cfg, activeJournal(cfg))
+	if err != nil {
+		return nil, err
+	}
+	unpaged := filter
+	unpaged.Limit, unpaged.Offset = 0, 0
+	tasks, err := listJournal(unpaged)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := a.List(unpaged)
+	if err != nil {
+		return nil, err
+	}
+	return repository.Query(append(tasks, archived...), filter), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/archive"
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+func TestRunArchive(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -200)
+	seedJournal(t,
+		testutil.NewTask().WithTitle("File taxes").WithCreatedAt(old).WithStatus(taskmodel.StatusDone),
+		testutil.NewTask().WithTitle("Call plumber").WithStatus(taskmodel.StatusDone),
+		testutil.NewTask().WithTitle("Renew passport").WithCreatedAt(old),
+	)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"archive", "--dry-run"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if want := "Would archive 1 task.\n  - File taxes\n"; stdout.String() != want {
+		t.Errorf("dry run stdout = %q, want %q", stdout.String(), want)
+	}
+	if n := journalCount(t); n != 3 {
+		t.Errorf("dry run left %d tasks, want 3", n)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"archive"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if want := "Archived 1 task.\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if n := journalCount(t); n != 2 {
+		t.Errorf("journal holds %d tasks, want 2", n)
+	}
+	path, err := journalPath(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	year := filepath.Join(archive.Dir(path), old.Format("2006")+".json")
+	if _, err := os.Stat(year); err != nil {
+		t.Errorf("archive file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "journal only", args: []string{"export", "--format", "csv", "--columns", "title", "--query", "status:done"}, want: "title\r\nCall plumber\r\n"},
+		{name: "with archive", args: []string{"export", "--format", "csv", "--columns", "title", "--query", "status:done", "--include-archive"}, want: "title\r\nFile taxes\r\nCall plumber\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != 0 {
+				t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunArchive_Errors(t *testing.T) {
+	seedJournal(t)
+	cfg := config.Default()
+	cfg.ArchiveAfterDays = 0
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want string
+	}{
+		{name: "disabled", args: nil, code: 2, want: "archiving is disabled"},
+		{name: "explicit age", args: []string{"--older-than", "30"}, code: 0},
+		{name: "arguments", args: []string{"now"}, code: 2, want: "unexpected arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"archive"}, tt.args...), &stdout, &stderr); code != tt.code {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("stderr = %q, want it to mention %q", stderr.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_ArchivesOnOpen(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -200)
+	seedJournal(t,
+		testutil.NewTask().WithTitle("File taxes").WithCreatedAt(old).WithStatus(taskmodel.StatusDone),
+		testutil.NewTask().WithTitle("Call plumber").WithStatus(taskmodel.StatusDone),
+	)
+	session, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := session.attach(initializeModel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.finish(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Archived 1 completed task."; m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+	if !slices.Equal(titles(m), []string{"Call plumber"}) {
+		t.Errorf("listed %q, want only the recent task", titles(m))
+	}
+	if n := journalCount(t); n != 1 {
+		t.Errorf("journal holds %d tasks, want 1", n)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"togo/internal/audit"
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+)
+
+// runAudit implements "togo audit": list the changes made to the journal
+// since a day such as yesterday, 2025-11-01 or -1w, oldest first, with
+// where each was made and the fields it changed.
+//
+// The task may be named by its ID in any form the journal shows, such as
+// "#12"; a deleted task is matched by ID prefix.
+//
+//	togo audit [--since today] [--task ID]
+func runAudit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	since := fs.String("since", "today", "first day to list changes of")
+	task := fs.String("task", "", "list only the changes to this task, by ID, ID prefix or #N")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo audit: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	from, err := taskmodel.ParseRelativeDate(*since, taskmodel.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "togo audit: --since: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo audit: %v\n", err)
+		return 1
+	}
+	log, err := journalAudit(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo audit: %v\n", err)
+		return 1
+	}
+	prefix, err := auditTask(cfg, *task)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo audit: --task %v\n", err)
+		return 1
+	}
+	entries, err := log.Read(from)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo audit: %v\n", err)
+		return 1
+	}
+
+	listed := 0
+	for _, e := range entries {
+		if !strings.HasPrefix(strings.ToLower(e.TaskID.String()), prefix) {
+			continue
+		}
+		fmt.Fprint(stdout, auditLines(e))
+		listed++
+	}
+	if listed == 0 {
+		fmt.Fprintf(stdout, "No changes since %s.\n", from.Format(time.DateOnly))
+	}
+	return 0
+}
+
+// auditTask returns the ID prefix the changes listed for input share: the
+// whole ID of the task input resolves to, or input itself when no task in
+// the journal matches, as for a task since deleted.
+func auditTask(cfg config.Config, input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		return "", err
+	}
+	defer closeService()
+	t, err := tasks.ResolveTask(input)
+	switch {
+	case errors.Is(err, taskmodel.ErrTaskNotFound):
+		return strings.ToLower(input), nil
+	case err != nil:
+		return "", err
+	}
+	return t.ID.String(), nil
+}
+
+// auditLines describes an audit entry: a line saying when, where and what
+// was done to which task, then a line for each field it changed.
+func auditLines(e audit.Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %-4s %-6s %s %q (%s)\n", e.At.Local().Format("2006-01-02 15:04"), e.Source, e.Op, e.Action, e.Title, e.TaskID.Short())
+	for _, c := range e.Changes {
+		fmt.Fprintf(&b, "    %s\n", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+func TestRunAudit(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 30, 0, 0, time.Local)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	seedJournal(t, testutil.NewTask().WithTitle("Review PR").WithTags("work").WithSeq(1))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tag", "rename", "work", "job"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("tag rename: exit code %d (stderr: %s)", code, stderr.String())
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{name: "since today", args: []string{"audit", "--since", "today"}, want: []string{
+			`2025-11-12 09:30  cli  update rename tag "Review PR" (`,
+			"    tags: work → job\n",
+		}},
+		{name: "default since", args: []string{"audit"}, want: []string{`update rename tag "Review PR"`}},
+		{name: "task by number", args: []string{"audit", "--task", "#1"}, want: []string{`update rename tag "Review PR"`}},
+		{name: "other task", args: []string{"audit", "--task", "zzz"}, want: []string{"No changes since 2025-11-12.\n"}},
+		{name: "since tomorrow", args: []string{"audit", "--since", "tomorrow"}, want: []string{"No changes since 2025-11-13.\n"}},
+		{name: "bad since", args: []string{"audit", "--since", "someday"}, wantCode: 2},
+		{name: "extra argument", args: []string{"audit", "work"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout = %q, want it to contain %q", stdout.String(), want)
+				}
+			}
+		})
+	}
+}
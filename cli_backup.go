@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"text/tabwriter"
+
+	"togo/internal/backup"
+	"togo/internal/config"
+	"togo/internal/filelock"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/jsonstore"
+)
+
+// runBackup implements "togo backup": list the journal's backups and
+// restore one of them.
+//
+//	togo backup list
+//	togo backup restore 2024-06-14T090000Z.json
+func runBackup(args []string, stdout, stderr io.Writer) int {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo backup: %v\n", err)
+		return 1
+	}
+	if cfg.Backend != config.BackendJSON {
+		fmt.Fprintf(stderr, "togo backup: only json journals are backed up, not %s ones\n", cfg.Backend)
+		return 1
+	}
+	journal, err := journalPath(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo backup: %v\n", err)
+		return 1
+	}
+	dir := backupDir(journal)
+
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	switch {
+	case sub == "list" && len(args) == 0:
+		backups, err := backup.List(dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "togo backup: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, b := range backups {
+			fmt.Fprintf(w, "%s\t%s\t%d bytes\n", b.Name, b.Time.Local().Format("Mon Jan 2 15:04"), b.Size)
+		}
+		w.Flush()
+		return 0
+	case sub == "restore" && len(args) == 1:
+		// Changes a crash left in the write-ahead log are recovered into
+		// the journal first, so the backup of it taken by Restore includes
+		// them; a journal too damaged to read is restored regardless.
+		if _, err := os.Stat(jsonstore.LogPath(journal)); err == nil {
+			if err := settleJournal(cfg); err != nil {
+				fmt.Fprintf(stderr, "togo backup: warning: pending changes not recovered: %v\n", err)
+			}
+		}
+		lock, err := filelock.Acquire(journal+".lock", filelock.Exclusive)
+		if err != nil {
+			fmt.Fprintf(stderr, "togo backup: %v\n", err)
+			return 1
+		}
+		defer lock.Release()
+		if err := backup.Restore(dir, args[0], journal, taskmodel.Now()); err != nil {
+			fmt.Fprintf(stderr, "togo backup: %v\n", err)
+			return 1
+		}
+		// The log must not replay over the restored journal.
+		if err := os.Remove(jsonstore.LogPath(journal)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stderr, "togo backup: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Restored %s; the replaced journal was backed up first.\n", args[0])
+		return 0
+	default:
+		fmt.Fprintln(stderr, "usage: togo backup [list | restore NAME]")
+		return 2
+	}
+}
+
+// settleJournal opens and reads the active journal, which recovers any
+// changes interrupted by a crash, then closes it.
+func settleJournal(cfg config.Config) error {
+	repo, err := openRepository(cfg, activeJournal(cfg))
+	if err != nil {
+		return err
+	}
+	_, err = repo.Count(taskmodel.TaskFilter{})
+	if cerr := closeRepository(repo); err == nil {
+		err = cerr
+	}
+	return err
+}
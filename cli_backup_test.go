@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/backup"
+	"togo/internal/config"
+)
+
+// withDataDir configures a temporary data directory and returns the path of
+// the JSON journal inside it.
+func withDataDir(t *testing.T) string {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DataDir = t.TempDir()
+	if err := config.Save(withConfigPath(t), cfg); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(cfg.DataDir, "tasks.json")
+}
+
+func TestRunBackup_ListAndRestore(t *testing.T) {
+	journal := withDataDir(t)
+	if err := os.WriteFile(journal, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	b, _, err := backup.Create(journal, backupDir(journal), time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(journal, []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"backup", "list"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("list: exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), b.Name) || !strings.Contains(stdout.String(), "3 bytes") {
+		t.Errorf("list output = %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"backup", "restore", b.Name}, &stdout, &stderr); code != 0 {
+		t.Fatalf("restore: exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(journal); string(data) != "old" {
+		t.Errorf("journal after restore = %q, want old", data)
+	}
+	if backups, _ := backup.List(backupDir(journal)); len(backups) != 2 {
+		t.Errorf("expected the replaced journal to be backed up, got %+v", backups)
+	}
+}
+
+func TestRunBackup_Errors(t *testing.T) {
+	withDataDir(t)
+
+	tests := []struct {
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{args: []string{"backup", "restore", "2020-01-01T000000Z.json"}, wantCode: 1, wantErr: "no such backup"},
+		{args: []string{"backup", "restore"}, wantCode: 2, wantErr: "usage"},
+		{args: []string{"backup", "prune"}, wantCode: 2, wantErr: "usage"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+			t.Errorf("%v: exit code %d, want %d", tt.args, code, tt.wantCode)
+		}
+		if !strings.Contains(stderr.String(), tt.wantErr) {
+			t.Errorf("%v: stderr = %q, want containing %q", tt.args, stderr.String(), tt.wantErr)
+		}
+	}
+}
+
+func TestRunBackup_OtherBackend(t *testing.T) {
+	cfg := config.Default()
+	cfg.DataDir, cfg.Backend = t.TempDir(), config.BackendBolt
+	if err := config.Save(withConfigPath(t), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"backup", "list"}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code %d, want 1", code)
+	}
+	if want := "only json journals are backed up, not bolt ones"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr = %q, want containing %q", stderr.String(), want)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/memstore"
+)
+
+// demoTask describes one task of the --demo sandbox.
+type demoTask struct {
+	title  string
+	tags   []string
+	status taskmodel.TaskStatus
+	dueIn  int // days from today; 0 means no due date
+}
+
+// demoTasks is the sample journal shown by "togo ui --demo".
+var demoTasks = []demoTask{
+	{title: "Review pull requests", tags: []string{"work"}, status: taskmodel.StatusToday},
+	{title: "Buy groceries", tags: []string{"errands"}, status: taskmodel.StatusToday, dueIn: 1},
+	{title: "Renew passport", tags: []string{"admin"}, status: taskmodel.StatusPool, dueIn: 14},
+	{title: "Plan team offsite", tags: []string{"work"}, status: taskmodel.StatusPool, dueIn: 5},
+	{title: "Learn a new recipe", tags: []string{"someday"}, status: taskmodel.StatusPool},
+	{title: "Call the dentist", status: taskmodel.StatusDone},
+}
+
+// demoRepository returns an in-memory repository seeded with demoTasks, so
+// the TUI can be explored without touching the real journal.
+func demoRepository() (*memstore.Repository, error) {
+	repo := memstore.New()
+	now := taskmodel.Now()
+	for i, d := range demoTasks {
+		task, err := taskmodel.NewTask(d.title, d.tags)
+		if err != nil {
+			return nil, err
+		}
+		task.Seq, task.Status = i+1, d.status
+		if d.status == taskmodel.StatusDone {
+			task.CompletedAt = &now
+		}
+		if d.dueIn > 0 {
+			due := taskmodel.StartOfDay(now).AddDate(0, 0, d.dueIn)
+			task.DueDate = &due
+		}
+		if err := repo.Save(task); err != nil {
+			return nil, err
+		}
+	}
+	return repo, nil
+}
+
+// addSampleTasks adds demoTasks to the active journal, for a new user to
+// try things out on, and returns how many it added.
+func addSampleTasks(cfg config.Config) (n int, err error) {
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = errors.Join(err, closeService()) }()
+	now := taskmodel.Now()
+	for _, d := range demoTasks {
+		task, err := tasks.AddTask(d.title, d.tags)
+		if err != nil {
+			return n, err
+		}
+		n++
+		if d.dueIn > 0 {
+			due := taskmodel.StartOfDay(now).AddDate(0, 0, d.dueIn)
+			if _, err := tasks.EditTask(task.ID, func(t *taskmodel.Task) error {
+				t.DueDate = &due
+				return nil
+			}); err != nil {
+				return n, err
+			}
+		}
+		switch d.status {
+		case taskmodel.StatusToday:
+			_, _, err = tasks.MoveToToday(task.ID)
+		case taskmodel.StatusDone:
+			_, err = tasks.CompleteTask(task.ID)
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"togo/internal/doctor"
+	"togo/internal/notestore"
+)
+
+// runDoctor implements "togo doctor": check the journal for damage and
+// repair it, asking first for each fix when run in a terminal.
+//
+//	togo doctor --fsck
+//	togo doctor --fsck --fix
+func runDoctor(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fsck := fs.Bool("fsck", false, "check every stored task for invalid data, duplicates and missing notes")
+	fix := fs.Bool("fix", false, "apply every available fix without asking")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo doctor: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	if !*fsck {
+		fmt.Fprintln(stderr, "togo doctor: choose a check to run: --fsck")
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+		return 1
+	}
+	path, err := journalPath(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+		return 1
+	}
+	journal, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo doctor: warning: %v\n", err)
+		}
+	}()
+
+	tasks, err := journal.StoredTasks()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+		return 1
+	}
+	notes := notestore.New(notesDir(path), 0)
+	problems, err := doctor.Check(tasks, notes)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+		return 1
+	}
+	if len(problems) == 0 {
+		fmt.Fprintf(stdout, "Checked %d %s: no problems found.\n", len(tasks), plural(len(tasks), "task"))
+		return 0
+	}
+
+	var chosen []doctor.Problem
+	in := bufio.NewReader(stdin)
+	ask := !*fix && isInteractive()
+	for _, p := range problems {
+		fmt.Fprintln(stdout, p)
+		if !p.Fixable() {
+			fmt.Fprintln(stdout, "    fix by hand")
+			continue
+		}
+		if !ask {
+			fmt.Fprintf(stdout, "    fix: %s\n", p.Fix)
+			if *fix {
+				chosen = append(chosen, p)
+			}
+			continue
+		}
+		answer, err := prompt(in, stdout, "    "+p.Fix+"?", "n", []string{"y", "n"})
+		if err != nil {
+			fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+			return 1
+		}
+		if strings.HasPrefix(strings.ToLower(answer), "y") {
+			chosen = append(chosen, p)
+		}
+	}
+
+	if len(chosen) > 0 {
+		if err := journal.RepairTasks(tasks, doctor.Repair(tasks, chosen)); err != nil {
+			fmt.Fprintf(stderr, "togo doctor: %v\n", err)
+			return 1
+		}
+	}
+	left := len(problems) - len(chosen)
+	switch {
+	case len(chosen) > 0:
+		fmt.Fprintf(stdout, "Fixed %d of %d %s.\n", len(chosen), len(problems), plural(len(problems), "problem"))
+	case ask:
+		fmt.Fprintf(stdout, "Found %d %s; nothing was changed.\n", len(problems), plural(len(problems), "problem"))
+	default:
+		fixable := 0
+		for _, p := range problems {
+			if p.Fixable() {
+				fixable++
+			}
+		}
+		fmt.Fprintf(stdout, "Found %d %s; run with --fix to repair %d of them.\n", len(problems), plural(len(problems), "problem"), fixable)
+	}
+	if left > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+)
+
+func TestRunDoctor(t *testing.T) {
+	seedJournal(t)
+	conf, _ := configPath()
+	if err := config.Save(conf, config.Default()); err != nil {
+		t.Fatal(err)
+	}
+	path, err := journalPath(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := taskmodel.NewTaskID().String()
+	damaged := `{"version":1,"tasks":[
+		{"id":"` + id + `","title":"Renew passport","status":"pool","created_at":"2024-06-10T09:00:00Z"},
+		{"id":"` + id + `","title":"Renew passport today","status":"today","created_at":"2024-06-10T09:00:00Z"},
+		{"id":"` + taskmodel.NewTaskID().String() + `","title":"Call plumber","status":"waiting","created_at":"2024-06-11T09:00:00Z"}]}`
+
+	tests := []struct {
+		name        string
+		args        []string
+		interactive bool
+		input       string
+		code        int
+		want        string
+		// tasks is how many tasks the journal loads with afterwards, or -1
+		// when it should still fail to load.
+		tasks int
+	}{
+		{name: "report", code: 1, want: "Found 2 problems; run with --fix to repair 2 of them.", tasks: -1},
+		{name: "fix", args: []string{"--fix"}, code: 0, want: "Fixed 2 of 2 problems.", tasks: 3},
+		{name: "ask", interactive: true, input: "y\nn\n", code: 1, want: "Fixed 1 of 2 problems.", tasks: -1},
+		{name: "decline", interactive: true, input: "n\nn\n", code: 1, want: "nothing was changed", tasks: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(damaged), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			origInteractive := isInteractive
+			isInteractive = func() bool { return tt.interactive }
+			t.Cleanup(func() { isInteractive = origInteractive })
+			withStdin(t, tt.input)
+
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"doctor", "--fsck"}, tt.args...), &stdout, &stderr); code != tt.code {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("stdout = %q, want it to mention %q", stdout.String(), tt.want)
+			}
+			tasks, err := listJournal(taskmodel.TaskFilter{})
+			switch {
+			case tt.tasks < 0 && err == nil:
+				t.Errorf("journal loads after %s", tt.name)
+			case tt.tasks >= 0 && (err != nil || len(tasks) != tt.tasks):
+				t.Errorf("journal loads %d tasks, %v; want %d", len(tasks), err, tt.tasks)
+			}
+		})
+	}
+}
+
+func TestRunDoctor_Healthy(t *testing.T) {
+	seedJournal(t)
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"doctor", "--fsck"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if want := "Checked 0 tasks: no problems found.\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if code := run([]string{"doctor"}, &stdout, &stderr); code != 2 {
+		t.Errorf("doctor without a check exited %d, want 2", code)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"togo/internal/ical"
+	taskmodel "togo/internal/model"
+	"togo/internal/query"
+	"togo/internal/taskcsv"
+	"togo/internal/todotxt"
+)
+
+// exportOptions carries format-specific settings from the command line.
+type exportOptions struct {
+	// columns lists the CSV columns to write, in order.
+	columns []string
+	// ids selects how the CSV ref column shows task IDs.
+	ids taskmodel.IDDisplayMode
+}
+
+// exporter writes tasks in one export format.
+type exporter func(w io.Writer, tasks []*taskmodel.Task, opts exportOptions) error
+
+// exporters maps the names accepted by "togo export --format" to their
+// writers.
+var exporters = map[string]exporter{
+	"ical": func(w io.Writer, tasks []*taskmodel.Task, _ exportOptions) error {
+		return ical.Write(w, tasks, taskmodel.Now())
+	},
+	"todotxt": func(w io.Writer, tasks []*taskmodel.Task, _ exportOptions) error {
+		return todotxt.Write(w, tasks)
+	},
+	"csv": func(w io.Writer, tasks []*taskmodel.Task, opts exportOptions) error {
+		return taskcsv.Write(w, tasks, opts.columns, opts.ids)
+	},
+}
+
+// runExport implements "togo export": write the journal's tasks, or those
+// matching a query, in another tool's format.
+//
+//	togo export --format todotxt --output todo.txt --query "-someday"
+//	togo export --format csv --columns id,title,status,due,tags
+//	togo export --format ical --output tasks.ics
+//	togo export --format csv --query "tax" --include-archive
+//	togo export --format todotxt --sort-title
+func runExport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	formats := slices.Sorted(maps.Keys(exporters))
+	format := fs.String("format", "", "output format: "+strings.Join(formats, ", "))
+	output := fs.String("output", "-", "file to write, or - for standard output")
+	expr := fs.String("query", "", "only export tasks matching this filter expression")
+	includeArchive := fs.Bool("include-archive", false, "also export archived tasks")
+	columns := fs.String("columns", "", "CSV columns to write: "+strings.Join(taskcsv.Names(), ","))
+	byTitle := fs.Bool("sort-title", false, "order tasks by title, as the collation setting says, instead of by creation")
+	var opts exportOptions
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo export: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	write, ok := exporters[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "togo export: unknown format %q (want %s)\n", *format, strings.Join(formats, ", "))
+		return 2
+	}
+	opts.columns = taskcsv.DefaultColumns
+	if *columns != "" {
+		if *format != "csv" {
+			fmt.Fprintln(stderr, "togo export: --columns only applies to --format csv")
+			return 2
+		}
+		var err error
+		if opts.columns, err = taskcsv.ParseColumns(*columns); err != nil {
+			fmt.Fprintf(stderr, "togo export: %v\n", err)
+			return 2
+		}
+	}
+	filter, err := query.Parse(*expr)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo export: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo export: %v\n", err)
+		return 1
+	}
+	opts.ids = cfg.IDDisplay
+	tasks, err := searchJournal(filter, *includeArchive)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo export: %v\n", err)
+		return 1
+	}
+	if *byTitle {
+		cfg.TitleOrder(activeJournal(cfg)).SortTasks(tasks)
+	}
+
+	err = writeOutput(*output, stdout, func(w io.Writer) error {
+		return write(w, tasks, opts)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "togo export: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeOutput runs fn on the file at path, or on stdout when path is "-".
+func writeOutput(path string, stdout io.Writer, fn func(io.Writer) error) error {
+	if path == "-" {
+		return fn(stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// seedJournal saves builders into the default journal of a fresh data
+// directory.
+func seedJournal(t *testing.T, builders ...*testutil.TaskBuilder) {
+	t.Helper()
+	withConfigPath(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	repo, err := openRepository(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeRepository(repo)
+	testutil.MustSeed(t, repo, testutil.Tasks(builders...)...)
+}
+
+func TestRunExport_TodoTxt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 9, 0, 0, 0, time.UTC) }
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Renew passport").WithTags("admin").WithCreatedAt(day(1)),
+		testutil.NewTask().WithTitle("Someday maybe").WithTags("someday").WithCreatedAt(day(2)),
+	)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"export", "--format", "todotxt", "--query", "-someday"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if want := "2024-06-01 Renew passport +admin\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+
+	out := filepath.Join(t.TempDir(), "todo.txt")
+	stdout.Reset()
+	if code := run([]string{"export", "--format=todotxt", "--output", out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(out); strings.Count(string(data), "\n") != 2 || stdout.Len() != 0 {
+		t.Errorf("file = %q, stdout = %q", data, stdout.String())
+	}
+}
+
+func TestRunExport_SortTitle(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 9, 0, 0, 0, time.UTC) }
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Zucker kaufen").WithCreatedAt(day(1)),
+		testutil.NewTask().WithTitle("Äpfel kaufen").WithCreatedAt(day(2)),
+		testutil.NewTask().WithTitle("Brot backen").WithCreatedAt(day(3)),
+	)
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Collation = "de"
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"export", "--format", "todotxt", "--sort-title"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	want := "2024-06-02 Äpfel kaufen\n2024-06-03 Brot backen\n2024-06-01 Zucker kaufen\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunExport_CSV(t *testing.T) {
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Renew passport, again").WithTags("admin", "travel").
+			WithDue(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)).WithSeq(3),
+	)
+	cfg := config.Default()
+	cfg.IDDisplay = taskmodel.IDDisplaySequence
+	path, _ := configPath()
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			args: []string{"export", "--format", "csv", "--columns", "title,due,tags"},
+			want: "title,due,tags\r\n\"Renew passport, again\",2024-06-30T00:00:00Z,\"admin,travel\"\r\n",
+		},
+		{
+			args: []string{"export", "--format", "csv"},
+			want: "id,title,status,due,tags\r\n",
+		},
+		{
+			args: []string{"export", "--format", "csv", "--columns", "ref,title"},
+			want: "ref,title\r\n#3,\"Renew passport, again\"\r\n",
+		},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d (stderr: %s)", tt.args, code, stderr.String())
+		}
+		if !strings.HasPrefix(stdout.String(), tt.want) {
+			t.Errorf("%v: stdout = %q, want starting %q", tt.args, stdout.String(), tt.want)
+		}
+	}
+}
+
+func TestRunExport_ICal(t *testing.T) {
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Renew passport").WithDue(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)),
+		testutil.NewTask().WithTitle("Someday maybe"),
+	)
+
+	out := filepath.Join(t.TempDir(), "tasks.ics")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"export", "--format", "ical", "--output", out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	data, _ := os.ReadFile(out)
+	if strings.Count(string(data), "BEGIN:VTODO") != 1 || !strings.Contains(string(data), "SUMMARY:Renew passport\r\n") {
+		t.Errorf("calendar =\n%s", data)
+	}
+}
+
+func TestRunExport_Errors(t *testing.T) {
+	seedJournal(t)
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"export"}, wantErr: `unknown format ""`},
+		{args: []string{"export", "--format", "docx"}, wantErr: "want csv, ical, todotxt"},
+		{args: []string{"export", "--format", "todotxt", "--columns", "title"}, wantErr: "only applies to --format csv"},
+		{args: []string{"export", "--format", "csv", "--columns", "title,colour"}, wantErr: `unknown column "colour"`},
+		{args: []string{"export", "--format", "todotxt", "--query", `"open`}, wantErr: "unterminated quote"},
+		{args: []string{"export", "--format", "todotxt", "extra"}, wantErr: "unexpected arguments"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != 2 {
+			t.Errorf("%v: exit code %d, want 2", tt.args, code)
+		}
+		if !strings.Contains(stderr.String(), tt.wantErr) {
+			t.Errorf("%v: stderr = %q, want containing %q", tt.args, stderr.String(), tt.wantErr)
+		}
+	}
+}
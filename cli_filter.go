@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"togo/internal/query"
+)
+
+// savedFiltersPath locates the saved filters file beside the configuration.
+func savedFiltersPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return query.DefaultSavedPath(path), nil
+}
+
+// loadSavedFilters reads the user's saved filters.
+func loadSavedFilters() (query.SavedFilters, error) {
+	path, err := savedFiltersPath()
+	if err != nil {
+		return nil, err
+	}
+	return query.LoadSaved(path)
+}
+
+// runFilter implements "togo filter": list, save and delete named filters.
+func runFilter(args []string, stdout, stderr io.Writer) int {
+	path, err := savedFiltersPath()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo filter: %v\n", err)
+		return 1
+	}
+	saved, err := query.LoadSaved(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo filter: %v\n", err)
+		return 1
+	}
+
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	switch {
+	case sub == "list" && len(args) == 0:
+		for i, f := range saved {
+			key := " "
+			if i < query.MaxHotkeys {
+				key = fmt.Sprint(i + 1)
+			}
+			fmt.Fprintf(stdout, "%s  %-12s %s\n", key, f.Name, f.Query)
+		}
+		return 0
+	case sub == "save" && len(args) >= 2:
+		if err := saved.Set(args[0], strings.Join(args[1:], " ")); err != nil {
+			fmt.Fprintf(stderr, "togo filter: %v\n", err)
+			return 2
+		}
+	case sub == "delete" && len(args) == 1:
+		if !saved.Delete(args[0]) {
+			fmt.Fprintf(stderr, "togo filter: no saved filter named %q\n", args[0])
+			return 1
+		}
+	default:
+		fmt.Fprint(stderr, `Usage:
+  togo filter [list]             show saved filters and their hotkeys
+  togo filter save NAME QUERY    save QUERY under NAME
+  togo filter delete NAME        remove a saved filter
+`)
+		return 2
+	}
+
+	if err := query.StoreSaved(path, saved); err != nil {
+		fmt.Fprintf(stderr, "togo filter: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"togo/internal/query"
+)
+
+func TestRunFilter_SaveListDelete(t *testing.T) {
+	withConfigPath(t)
+
+	steps := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{args: []string{"filter", "save", "inbox", "status:pool", "-someday"}},
+		{args: []string{"filter", "save", "work", "+work"}},
+		{args: []string{"filter"}, wantStdout: "1  inbox        status:pool -someday\n2  work         +work\n"},
+		{args: []string{"filter", "delete", "inbox"}},
+		{args: []string{"filter", "list"}, wantStdout: "1  work         +work\n"},
+	}
+
+	for _, step := range steps {
+		var stdout, stderr bytes.Buffer
+		if code := run(step.args, &stdout, &stderr); code != step.wantCode {
+			t.Fatalf("%v: expected exit code %d, got %d (stderr: %s)", step.args, step.wantCode, code, stderr.String())
+		}
+		if stdout.String() != step.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", step.args, stdout.String(), step.wantStdout)
+		}
+	}
+}
+
+func TestRunFilter_Errors(t *testing.T) {
+	withConfigPath(t)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStderr string
+	}{
+		{"bad query", []string{"filter", "save", "inbox", "status:later"}, 2, "status must be"},
+		{"bad name", []string{"filter", "save", "In Box", "+work"}, 2, "invalid filter name"},
+		{"missing query", []string{"filter", "save", "inbox"}, 2, "Usage"},
+		{"unknown filter", []string{"filter", "delete", "inbox"}, 1, `no saved filter named "inbox"`},
+		{"unknown subcommand", []string{"filter", "rename"}, 2, "Usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("expected exit code %d, got %d", tt.wantCode, code)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_UIWithSavedFilter(t *testing.T) {
+	withConfigPath(t)
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"filter", "save", "work", "+work"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("saving filter failed: %s", stderr.String())
+	}
+	launched := stubLaunch(t, nil)
+
+	if code := run([]string{"ui", "--filter", "work"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if launched.opts.query != "+work" || len(launched.filter.Tags) != 1 {
+		t.Errorf("expected saved filter applied, got query %q filter %+v", launched.opts.query, launched.filter)
+	}
+	if _, ok := launched.saved.Lookup("work"); !ok {
+		t.Error("expected saved filters loaded into the model")
+	}
+
+	for _, args := range [][]string{
+		{"ui", "--filter", "home"},
+		{"ui", "--filter", "work", "--query", "+home"},
+	} {
+		stderr.Reset()
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("%v: expected exit code 2, got %d", args, code)
+		}
+	}
+}
+
+func TestSavedFilterHotkeys(t *testing.T) {
+	m := initializeModel()
+	m.saved = query.SavedFilters{{Name: "inbox", Query: "status:pool"}, {Name: "work", Query: "+work"}, {Name: "later", Query: "status:later"}}
+
+	nm, _ := m.Update(keyMsg("2"))
+	got := nm.(model)
+	if got.opts.query != "+work" || len(got.filter.Tags) != 1 || got.filter.Tags[0] != "work" {
+		t.Fatalf("expected key 2 to apply the work filter, got query %q", got.opts.query)
+	}
+
+	nm, _ = got.Update(keyMsg("5"))
+	if nm.(model).opts.query != "+work" {
+		t.Errorf("expected unbound key to keep the current filter")
+	}
+
+	nm, _ = got.Update(keyMsg("3"))
+	got = nm.(model)
+	if got.opts.query != "+work" || len(got.filter.Tags) != 1 {
+		t.Errorf("expected a filter that does not parse to keep the current one, got query %q", got.opts.query)
+	}
+	if !strings.HasPrefix(got.notice, `Cannot apply the saved filter "later": `) {
+		t.Errorf("notice = %q, want the parse error reported", got.notice)
+	}
+}
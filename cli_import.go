@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"togo/internal/checklist"
+	"togo/internal/ical"
+	"togo/internal/importer"
+	taskmodel "togo/internal/model"
+	"togo/internal/taskcsv"
+	"togo/internal/todoist"
+	"togo/internal/todotxt"
+)
+
+// importOptions carries format-specific settings from the command line.
+type importOptions struct {
+	// loc is the time zone of dates that carry none.
+	loc *time.Location
+	// mapping names the CSV headers of task fields.
+	mapping taskcsv.Mapping
+	// profile adapts CSV import to another tool's export.
+	profile *taskcsv.Profile
+	// chooseMapping, if set, lets the user review the CSV column mapping.
+	chooseMapping func(header []string, m taskcsv.Mapping) (taskcsv.Mapping, error)
+	// project names the project of a Todoist CSV backup.
+	project string
+}
+
+// parser reads one import format into drafts. Formats that can skip a bad
+// record and carry on report it in skipped.
+type parser func(r io.Reader, opts importOptions) (drafts []importer.Draft, skipped []*importer.LineError, err error)
+
+// parsers maps the names accepted by "togo import --format" to their
+// readers.
+var parsers = map[string]parser{
+	"csv": func(r io.Reader, opts importOptions) ([]importer.Draft, []*importer.LineError, error) {
+		return taskcsv.Parse(r, taskcsv.Options{
+			Mapping: opts.mapping,
+			Loc:     opts.loc,
+			Profile: opts.profile,
+			Choose:  opts.chooseMapping,
+		})
+	},
+	"ical": func(r io.Reader, opts importOptions) ([]importer.Draft, []*importer.LineError, error) {
+		return ical.Parse(r, opts.loc)
+	},
+	"markdown": func(r io.Reader, _ importOptions) ([]importer.Draft, []*importer.LineError, error) {
+		drafts, err := checklist.Parse(r)
+		return drafts, nil, err
+	},
+	"todoist": func(r io.Reader, opts importOptions) ([]importer.Draft, []*importer.LineError, error) {
+		return todoist.Parse(r, opts.project, opts.loc)
+	},
+	"todotxt": func(r io.Reader, opts importOptions) ([]importer.Draft, []*importer.LineError, error) {
+		drafts, err := todotxt.Parse(r, opts.loc)
+		return drafts, nil, err
+	},
+}
+
+// runImport implements "togo import": read tasks from another tool's
+// format into the active journal. Tasks imported before are updated with
+// what changed in their source (see importer.Refresh), so importing a file
+// again only adds what is new.
+//
+//	togo import --format todotxt --dry-run todo.txt
+//	togo import --format csv --map "title=Summary,due=Due Date" issues.csv
+//	togo import --profile jira issues.csv
+//	togo import --format ical https://example.com/tasks.ics
+//	togo import --format todoist "Home Admin.csv"
+//
+// CSV columns named after task fields are mapped automatically; --map
+// names the rest, and --profile maps the CSV export of a known tool, such
+// as JIRA or Trello. Without either, a terminal user is asked to confirm
+// the mapping. Rows that cannot be read are skipped and listed afterwards.
+//
+// Todoist projects become tags. A CSV backup holds one project, named by
+// --project or else by the file name.
+func runImport(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	formats := slices.Sorted(maps.Keys(parsers))
+	format := fs.String("format", "", "input format: "+strings.Join(formats, ", "))
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without changing the journal")
+	mapSpec := fs.String("map", "", "CSV column mapping: FIELD=HEADER,…")
+	profileName := fs.String("profile", "", "CSV export to read: "+strings.Join(taskcsv.ProfileNames(), ", ")+" (implies --format csv)")
+	opts := importOptions{loc: time.Local}
+	fs.StringVar(&opts.project, "project", "", "project of a Todoist CSV backup (default: the file name)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: togo import --format FORMAT|--profile PROFILE [--dry-run] [--map FIELD=HEADER,…] FILE|URL|-")
+		return 2
+	}
+	if *profileName != "" {
+		p, ok := taskcsv.LookupProfile(*profileName)
+		switch {
+		case !ok:
+			fmt.Fprintf(stderr, "togo import: unknown profile %q (want %s)\n", *profileName, strings.Join(taskcsv.ProfileNames(), ", "))
+			return 2
+		case *format != "" && *format != "csv":
+			fmt.Fprintln(stderr, "togo import: --profile only applies to --format csv")
+			return 2
+		}
+		*format, opts.profile = "csv", p
+	}
+	parse, ok := parsers[*format]
+	if !ok {
+		fmt.Fprintf(stderr, "togo import: unknown format %q (want %s)\n", *format, strings.Join(formats, ", "))
+		return 2
+	}
+
+	if opts.project == "" && fs.Arg(0) != "-" {
+		base := filepath.Base(fs.Arg(0))
+		opts.project = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	switch {
+	case *mapSpec != "" && *format != "csv":
+		fmt.Fprintln(stderr, "togo import: --map only applies to --format csv")
+		return 2
+	case *mapSpec != "":
+		var err error
+		if opts.mapping, err = taskcsv.ParseMapping(*mapSpec); err != nil {
+			fmt.Fprintf(stderr, "togo import: %v\n", err)
+			return 2
+		}
+	case *format == "csv" && opts.profile == nil && fs.Arg(0) != "-" && isInteractive():
+		opts.chooseMapping = func(header []string, m taskcsv.Mapping) (taskcsv.Mapping, error) {
+			return promptMapping(stdin, stdout, header, m)
+		}
+	}
+
+	imported, skipped, err := readImport(fs.Arg(0), parse, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo import: %v\n", err)
+		return 1
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo import: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo import: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo import: warning: %v\n", err)
+		}
+	}()
+
+	create, update, unchanged, err := tasks.PlanImport(imported)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo import: %v\n", err)
+		return 1
+	}
+	if *dryRun {
+		fmt.Fprintln(stdout, importSummary(true, len(create), len(update), len(unchanged)))
+		for _, t := range create {
+			fmt.Fprintf(stdout, "  + %s\n", t.Title)
+		}
+		for _, t := range update {
+			fmt.Fprintf(stdout, "  ~ %s\n", t.Title)
+		}
+		printSkipped(stdout, skipped)
+		return 0
+	}
+	if err := tasks.ImportTasks(create, update); err != nil {
+		fmt.Fprintf(stderr, "togo import: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, importSummary(false, len(create), len(update), len(unchanged)))
+	printSkipped(stdout, skipped)
+	return 0
+}
+
+// importSummary describes what an import did, or would do in a dry run:
+// "Imported 2 tasks; 1 already present.", mentioning updates when there
+// are any, as in "Imported 0 tasks, updated 1; 2 already present."
+func importSummary(dryRun bool, create, update, unchanged int) string {
+	imported, updated := "Imported", "updated"
+	if dryRun {
+		imported, updated = "Would import", "update"
+	}
+	s := fmt.Sprintf("%s %d %s", imported, create, plural(create, "task"))
+	if update > 0 {
+		s += fmt.Sprintf(", %s %d", updated, update)
+	}
+	return s + fmt.Sprintf("; %d already present.", unchanged)
+}
+
+// printSkipped lists the records an import left out, if any.
+func printSkipped(w io.Writer, skipped []*importer.LineError) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Skipped %d %s:\n", len(skipped), plural(len(skipped), "record"))
+	for _, e := range skipped {
+		fmt.Fprintf(w, "  %v\n", e)
+	}
+}
+
+// promptMapping asks which CSV header holds each importable field,
+// offering m's choice as the default. "-" leaves a field unmapped.
+func promptMapping(in io.Reader, out io.Writer, header []string, m taskcsv.Mapping) (taskcsv.Mapping, error) {
+	r := bufio.NewReader(in)
+	fmt.Fprintf(out, "Columns: %s\n", strings.Join(header, ", "))
+	chosen := taskcsv.Mapping{}
+	for _, name := range taskcsv.Importable() {
+		answer, err := prompt(r, out, "Column for "+name+" (- for none)", m[name], nil)
+		if err != nil {
+			return nil, err
+		}
+		if answer != "" && answer != "-" {
+			chosen[name] = answer
+		}
+	}
+	return chosen, nil
+}
+
+// readImport parses the file at path, the feed at an http(s) or webcal
+// URL, or stdin for "-", into validated tasks and the records the format
+// skipped.
+func readImport(path string, parse parser, opts importOptions) ([]*taskmodel.Task, []*importer.LineError, error) {
+	r, err := openImport(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Close()
+	drafts, skipped, err := parse(r, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]*taskmodel.Task, len(drafts))
+	for i, d := range drafts {
+		if tasks[i], err = d.Task(); err != nil {
+			return nil, nil, fmt.Errorf("record %d (%q): %w", i+1, d.Title, err)
+		}
+	}
+	return tasks, skipped, nil
+}
+
+// feedTimeout bounds how long fetching an import feed may take.
+const feedTimeout = 30 * time.Second
+
+// openImport opens the input named on the command line.
+func openImport(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	url := path
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return os.Open(path)
+	}
+
+	client := http.Client{Timeout: feedTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %s", path, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// plural returns noun, with an "s" unless n is one.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
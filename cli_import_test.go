@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+)
+
+// journalCount returns how many tasks the default journal holds.
+func journalCount(t *testing.T) int {
+	t.Helper()
+	repo, err := openRepository(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeRepository(repo)
+	n, err := repo.Count(taskmodel.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestRunImport_TodoTxt(t *testing.T) {
+	seedJournal(t)
+	file := filepath.Join(t.TempDir(), "todo.txt")
+	content := "(A) 2024-06-01 Renew passport +admin due:2024-06-30\nx 2024-06-02 Call the dentist\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		args       []string
+		wantStdout string
+		wantCount  int
+	}{
+		{
+			args:       []string{"import", "--format", "todotxt", "--dry-run", file},
+			wantStdout: "Would import 2 tasks; 0 already present.\n  + Renew passport\n  + Call the dentist\n",
+		},
+		{args: []string{"import", "--format", "todotxt", file}, wantStdout: "Imported 2 tasks; 0 already present.\n", wantCount: 2},
+		{args: []string{"import", "--format", "todotxt", file}, wantStdout: "Imported 0 tasks; 2 already present.\n", wantCount: 2},
+	}
+	for _, step := range steps {
+		var stdout, stderr bytes.Buffer
+		if code := run(step.args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d (stderr: %s)", step.args, code, stderr.String())
+		}
+		if stdout.String() != step.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", step.args, stdout.String(), step.wantStdout)
+		}
+		if got := journalCount(t); got != step.wantCount {
+			t.Errorf("%v: journal holds %d tasks, want %d", step.args, got, step.wantCount)
+		}
+	}
+}
+
+func TestRunImport_Errors(t *testing.T) {
+	seedJournal(t)
+	withStdin(t, "Fine\nBad due:soon\n")
+
+	tests := []struct {
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{args: []string{"import", "--format", "todotxt"}, wantCode: 2, wantErr: "usage"},
+		{args: []string{"import", "--format", "xml", "-"}, wantCode: 2, wantErr: "unknown format"},
+		{args: []string{"import", "--format", "todotxt", "-"}, wantCode: 1, wantErr: "line 2: due date"},
+		{args: []string{"import", "--format", "todotxt", "missing.txt"}, wantCode: 1, wantErr: "missing.txt"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+			t.Errorf("%v: exit code %d, want %d", tt.args, code, tt.wantCode)
+		}
+		if !strings.Contains(stderr.String(), tt.wantErr) {
+			t.Errorf("%v: stderr = %q, want containing %q", tt.args, stderr.String(), tt.wantErr)
+		}
+	}
+	if journalCount(t) != 0 {
+		t.Error("a failed import changed the journal")
+	}
+}
+
+func TestRunImport_Markdown(t *testing.T) {
+	seedJournal(t)
+	withStdin(t, "## Errands\n- [ ] Renew passport #admin\n- [x] Call the dentist\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--format", "markdown", "-"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	if got := journalCount(t); got != 2 {
+		t.Errorf("journal holds %d tasks, want 2", got)
+	}
+}
+
+func TestRunImport_CSV(t *testing.T) {
+	seedJournal(t)
+	file := filepath.Join(t.TempDir(), "issues.csv")
+	content := "Summary,Created,Due\n" +
+		"Renew passport,2024-06-01,2024-06-30\n" +
+		"Renew passport,2024-06-01,\n" +
+		"Call the dentist,yesterday,\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		// content, if set, replaces the file before the step.
+		content    string
+		args       []string
+		wantStdout string
+		wantCount  int
+	}{
+		{
+			args: []string{"import", "--format", "csv", "--map", "title=Summary", file},
+			wantStdout: "Imported 1 task; 1 already present.\n" +
+				"Skipped 1 record:\n  line 4: created: \"yesterday\" is not a date such as 2024-06-30 or 2024-06-30T17:00:00Z\n",
+			wantCount: 1,
+		},
+		{
+			args: []string{"import", "--format", "csv", "--dry-run", "--map", "title=Summary", file},
+			wantStdout: "Would import 0 tasks; 2 already present.\n" +
+				"Skipped 1 record:\n  line 4: created: \"yesterday\" is not a date such as 2024-06-30 or 2024-06-30T17:00:00Z\n",
+			wantCount: 1,
+		},
+		{
+			content:    "Summary,Created,Due\nRenew passport,2024-06-01,2024-07-15\n",
+			args:       []string{"import", "--format", "csv", "--dry-run", "--map", "title=Summary", file},
+			wantStdout: "Would import 0 tasks, update 1; 0 already present.\n  ~ Renew passport\n",
+			wantCount:  1,
+		},
+		{
+			args:       []string{"import", "--format", "csv", "--map", "title=Summary", file},
+			wantStdout: "Imported 0 tasks, updated 1; 0 already present.\n",
+			wantCount:  1,
+		},
+	}
+	for _, step := range steps {
+		if step.content != "" {
+			if err := os.WriteFile(file, []byte(step.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var stdout, stderr bytes.Buffer
+		if code := run(step.args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d (stderr: %s)", step.args, code, stderr.String())
+		}
+		if stdout.String() != step.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", step.args, stdout.String(), step.wantStdout)
+		}
+		if got := journalCount(t); got != step.wantCount {
+			t.Errorf("%v: journal holds %d tasks, want %d", step.args, got, step.wantCount)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--format", "todotxt", "--map", "title=Summary", file}, &stdout, &stderr); code != 2 ||
+		!strings.Contains(stderr.String(), "only applies to --format csv") {
+		t.Errorf("--map with todotxt: exit code %d, stderr %q", code, stderr.String())
+	}
+}
+
+func TestRunImport_CSVInteractiveMapping(t *testing.T) {
+	seedJournal(t)
+	path, _ := configPath()
+	if err := config.Save(path, config.Default()); err != nil {
+		t.Fatal(err)
+	}
+	origInteractive := isInteractive
+	isInteractive = func() bool { return true }
+	t.Cleanup(func() { isInteractive = origInteractive })
+	file := filepath.Join(t.TempDir(), "cards.csv")
+	if err := os.WriteFile(file, []byte("Card Name,Notes\nBook flights,window seat\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Map the title, then reject the automatic notes mapping.
+	withStdin(t, "Card Name\n-\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--format", "csv", file}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	for _, want := range []string{"Columns: Card Name, Notes", "Column for notes (- for none) [Notes]: ", "Imported 1 task"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("stdout = %q, want containing %q", stdout.String(), want)
+		}
+	}
+	tasks, err := listJournal(taskmodel.TaskFilter{})
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "Book flights" || tasks[0].Notes != "" {
+		t.Errorf("journal = %v, %v", tasks, err)
+	}
+}
+
+func TestRunImport_ICalFeed(t *testing.T) {
+	seedJournal(t)
+	calendar := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:feed-1\r\nSUMMARY:Renew passport\r\nDUE;VALUE=DATE:20240630\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:feed-2\r\nSUMMARY:Dropped\r\nSTATUS:CANCELLED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tasks.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, calendar)
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--format", "ical", srv.URL + "/tasks.ics"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	want := "Imported 1 task; 0 already present.\nSkipped 1 record:\n  line 7: todo was cancelled\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if got := journalCount(t); got != 1 {
+		t.Errorf("journal holds %d tasks, want 1", got)
+	}
+
+	stderr.Reset()
+	if code := run([]string{"import", "--format", "ical", srv.URL + "/missing.ics"}, &stdout, &stderr); code != 1 ||
+		!strings.Contains(stderr.String(), "404 Not Found") {
+		t.Errorf("missing feed: exit code %d, stderr %q", code, stderr.String())
+	}
+}
+
+func TestRunImport_Todoist(t *testing.T) {
+	seedJournal(t)
+	file := filepath.Join(t.TempDir(), "Home Admin.csv")
+	content := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+		"task,Renew passport @travel,,1,1,Sam,,2024-06-30,en,UTC\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--format", "todoist", file}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	tasks, err := listJournal(taskmodel.TaskFilter{})
+	if err != nil || len(tasks) != 1 || strings.Join(tasks[0].Tags, ",") != "Home-Admin,travel" {
+		t.Errorf("journal = %v, %v", tasks, err)
+	}
+}
+
+func TestRunImport_Profile(t *testing.T) {
+	seedJournal(t)
+	withStdin(t, "Summary,Issue key,Status,Labels,Labels\nFix login,WEB-12,In Progress,auth,frontend\n")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"import", "--profile", "jira", "-"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	tasks, err := listJournal(taskmodel.TaskFilter{})
+	if err != nil || len(tasks) != 1 || tasks[0].Status != taskmodel.StatusToday || strings.Join(tasks[0].Tags, ",") != "auth,frontend" {
+		t.Errorf("journal = %v, %v", tasks, err)
+	}
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"import", "--profile", "asana", "-"}, wantErr: `unknown profile "asana" (want jira, trello)`},
+		{args: []string{"import", "--profile", "jira", "--format", "todotxt", "-"}, wantErr: "only applies to --format csv"},
+	}
+	for _, tt := range tests {
+		stderr.Reset()
+		if code := run(tt.args, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), tt.wantErr) {
+			t.Errorf("%v: exit code %d, stderr %q", tt.args, code, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"togo/internal/config"
+)
+
+// configPath locates the configuration file. It is a variable so tests can
+// point it at a temporary directory.
+var configPath = config.DefaultPath
+
+// stdin is the source of interactive answers.
+var stdin io.Reader = os.Stdin
+
+// isInteractive reports whether stdin is a terminal, in which case first-run
+// setup may prompt the user.
+var isInteractive = func() bool {
+	return isTerminal(os.Stdin)
+}
+
+// runInit implements "togo init": walk through first-run choices and write a
+// commented configuration file.
+func runInit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	force := fs.Bool("force", false, "overwrite an existing configuration file")
+	defaults := fs.Bool("defaults", false, "write the default configuration without prompting")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	path, err := configPath()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo init: %v\n", err)
+		return 1
+	}
+	if _, err := os.Stat(path); err == nil && !*force {
+		fmt.Fprintf(stderr, "togo init: %s already exists (use --force to overwrite)\n", path)
+		return 1
+	}
+
+	cfg, samples := config.Default(), false
+	if !*defaults {
+		cfg, samples, err = runSetupWizard(stdin, stdout, cfg)
+		if err != nil {
+			fmt.Fprintf(stderr, "togo init: %v\n", err)
+			return 1
+		}
+	}
+	if err := config.Save(path, cfg); err != nil {
+		fmt.Fprintf(stderr, "togo init: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Wrote %s\n", path)
+	if samples {
+		if err := reportSampleTasks(stdout, path); err != nil {
+			fmt.Fprintf(stderr, "togo init: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// ensureConfigured runs the setup wizard when no configuration file exists
+// and the session is interactive. Non-interactive invocations, such as
+// scripts, silently use the defaults.
+func ensureConfigured(stdout io.Writer) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if !isInteractive() {
+		return nil
+	}
+
+	fmt.Fprintln(stdout, "Welcome to togo! Let's set things up. Press enter to accept a default.")
+	cfg, samples, err := runSetupWizard(stdin, stdout, config.Default())
+	if err != nil {
+		return err
+	}
+	if err := config.Save(path, cfg); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Wrote %s\n", path)
+	if samples {
+		if err := reportSampleTasks(stdout, path); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(stdout)
+	return nil
+}
+
+// reportSampleTasks adds the sample tasks to the journal of the
+// configuration just written to path and says how many it added.
+func reportSampleTasks(stdout io.Writer, path string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	n, err := addSampleTasks(cfg)
+	if err != nil {
+		return fmt.Errorf("adding sample tasks: %w", err)
+	}
+	fmt.Fprintf(stdout, "Added %d sample %s to the %s journal.\n", n, plural(n, "task"), activeJournal(cfg))
+	return nil
+}
+
+// runSetupWizard prompts for each interactive setting, starting from cfg,
+// and then whether to add sample tasks, which it reports in samples. An
+// empty answer keeps the current value; running out of input accepts the
+// remaining defaults.
+func runSetupWizard(in io.Reader, out io.Writer, cfg config.Config) (_ config.Config, samples bool, err error) {
+	r := bufio.NewReader(in)
+
+	questions := []struct {
+		key     string
+		prompt  string
+		current string
+		choices []string
+	}{
+		{"data_dir", "Data directory (empty for the platform default)", cfg.DataDir, nil},
+		{"backend", "Storage backend", cfg.Backend, []string{config.BackendJSON, config.BackendBolt, config.BackendEvents}},
+		{"theme", "Theme", cfg.Theme, []string{config.ThemeAuto, config.ThemeDark, config.ThemeLight}},
+		{"keymap", "Key bindings", cfg.Keymap, []string{config.KeymapVim, config.KeymapArrows}},
+	}
+
+	for _, q := range questions {
+		for {
+			answer, err := prompt(r, out, q.prompt, q.current, q.choices)
+			if err != nil {
+				return config.Config{}, false, err
+			}
+			if err := cfg.Set(q.key, answer); err != nil {
+				fmt.Fprintf(out, "  %v\n", err)
+				continue
+			}
+			break
+		}
+	}
+
+	for {
+		answer, err := prompt(r, out, "Add sample tasks to try things out", "no", []string{"yes", "no"})
+		if err != nil {
+			return config.Config{}, false, err
+		}
+		switch strings.ToLower(answer) {
+		case "yes", "y":
+			return cfg, true, nil
+		case "no", "n":
+			return cfg, false, nil
+		}
+		fmt.Fprintf(out, "  want yes or no, got %q\n", answer)
+	}
+}
+
+// prompt asks a single question and returns the trimmed answer, or current
+// if the answer is empty or input is exhausted.
+func prompt(r *bufio.Reader, out io.Writer, question, current string, choices []string) (string, error) {
+	fmt.Fprint(out, question)
+	if len(choices) > 0 {
+		fmt.Fprintf(out, " (%s)", strings.Join(choices, "/"))
+	}
+	if current != "" {
+		fmt.Fprintf(out, " [%s]", current)
+	}
+	fmt.Fprint(out, ": ")
+
+	line, err := r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if errors.Is(err, io.EOF) && line == "" {
+		fmt.Fprintln(out)
+		return current, nil
+	}
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return current, nil
+	}
+	return answer, nil
+}
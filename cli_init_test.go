@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"togo/internal/config"
+)
+
+// withConfigPath points configPath at a fresh temporary file for one test.
+func withConfigPath(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "togo", "config")
+	orig := configPath
+	configPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { configPath = orig })
+	return path
+}
+
+// withStdin replaces the interactive input for one test.
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	orig := stdin
+	stdin = strings.NewReader(input)
+	t.Cleanup(func() { stdin = orig })
+}
+
+func TestRunSetupWizard_Answers(t *testing.T) {
+	input := "/data/togo\n\ndark\narrows\n"
+	var out bytes.Buffer
+
+	cfg, _, err := runSetupWizard(strings.NewReader(input), &out, config.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DataDir != "/data/togo" {
+		t.Errorf("expected data dir /data/togo, got %q", cfg.DataDir)
+	}
+	if cfg.Backend != config.BackendJSON {
+		t.Errorf("expected default backend, got %q", cfg.Backend)
+	}
+	if cfg.Theme != config.ThemeDark || cfg.Keymap != config.KeymapArrows {
+		t.Errorf("expected dark/arrows, got %q/%q", cfg.Theme, cfg.Keymap)
+	}
+}
+
+func TestRunSetupWizard_RepromptsOnInvalidAnswer(t *testing.T) {
+	input := "\n\nneon\nlight\n\n"
+	var out bytes.Buffer
+
+	cfg, _, err := runSetupWizard(strings.NewReader(input), &out, config.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Theme != config.ThemeLight {
+		t.Errorf("expected light theme after retry, got %q", cfg.Theme)
+	}
+	if !strings.Contains(out.String(), `got "neon"`) {
+		t.Errorf("expected validation message in output, got:\n%s", out.String())
+	}
+}
+
+func TestRunSetupWizard_KeepsEncryptedJournalOnJSON(t *testing.T) {
+	input := "\nbolt\njson\n\n\n\n"
+	var out bytes.Buffer
+	start := config.Default()
+	start.Encryption = config.EncryptionPassphrase
+
+	cfg, _, err := runSetupWizard(strings.NewReader(input), &out, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Backend != config.BackendJSON {
+		t.Errorf("expected json backend after retry, got %q", cfg.Backend)
+	}
+	if !strings.Contains(out.String(), "bolt journals cannot be encrypted") {
+		t.Errorf("expected encryption message in output, got:\n%s", out.String())
+	}
+}
+
+func TestRunSetupWizard_EOFAcceptsDefaults(t *testing.T) {
+	var out bytes.Buffer
+
+	cfg, _, err := runSetupWizard(strings.NewReader(""), &out, config.Default())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != config.Default() {
+		t.Errorf("expected defaults on empty input, got %+v", cfg)
+	}
+}
+
+func TestRunSetupWizard_SampleTasks(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "\n\n\n\nyes\n", want: true},
+		{input: "\n\n\n\nmaybe\nn\n", want: false},
+		{input: "\n\n\n\n", want: false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		_, samples, err := runSetupWizard(strings.NewReader(tt.input), &out, config.Default())
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.input, err)
+		}
+		if samples != tt.want {
+			t.Errorf("%q: samples = %v, want %v (output:\n%s)", tt.input, samples, tt.want, out.String())
+		}
+	}
+}
+
+func TestPrompt_ShowsChoicesAndDefault(t *testing.T) {
+	var out bytes.Buffer
+	r := bufio.NewReader(strings.NewReader("\n"))
+
+	got, err := prompt(r, &out, "Theme", "auto", []string{"auto", "dark"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "auto" {
+		t.Errorf("expected default answer, got %q", got)
+	}
+	if out.String() != "Theme (auto/dark) [auto]: " {
+		t.Errorf("unexpected prompt %q", out.String())
+	}
+}
+
+func TestRunInit_WritesCommentedConfig(t *testing.T) {
+	path := withConfigPath(t)
+	withStdin(t, "\n\nlight\n\n")
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"init"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected config file to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "# Color theme") {
+		t.Errorf("expected comments in config, got:\n%s", data)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != config.ThemeLight {
+		t.Errorf("expected light theme, got %q", cfg.Theme)
+	}
+}
+
+func TestRunInit_AddsSampleTasks(t *testing.T) {
+	withConfigPath(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	withStdin(t, "\n\n\n\nyes\n")
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"init"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := fmt.Sprintf("Added %d sample tasks to the default journal.", len(demoTasks))
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected %q, got %q", want, stdout.String())
+	}
+	if n := journalCount(t); n != len(demoTasks) {
+		t.Errorf("journal holds %d tasks, want %d", n, len(demoTasks))
+	}
+}
+
+func TestRunInit_RefusesToOverwrite(t *testing.T) {
+	path := withConfigPath(t)
+	if err := config.Save(path, config.Default()); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"init", "--defaults"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "--force") {
+		t.Errorf("expected hint about --force, got %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"init", "--defaults", "--force"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected --force to succeed, got %d (stderr: %s)", code, stderr.String())
+	}
+}
+
+func TestRun_FirstRunInvokesWizardWhenInteractive(t *testing.T) {
+	path := withConfigPath(t)
+	withStdin(t, "\n\ndark\n\n")
+	stubLaunch(t, nil)
+	origInteractive := isInteractive
+	isInteractive = func() bool { return true }
+	t.Cleanup(func() { isInteractive = origInteractive })
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Welcome to togo") {
+		t.Errorf("expected welcome message, got %q", stdout.String())
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != config.ThemeDark {
+		t.Errorf("expected wizard answers to be saved, got theme %q", cfg.Theme)
+	}
+
+	// A second launch must not prompt again.
+	stdout.Reset()
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if strings.Contains(stdout.String(), "Welcome to togo") {
+		t.Error("wizard ran again although a configuration exists")
+	}
+}
+
+func TestRun_FirstRunSkipsWizardWhenNotInteractive(t *testing.T) {
+	path := withConfigPath(t)
+	stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no config to be written non-interactively, stat err = %v", err)
+	}
+}
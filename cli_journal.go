@@ -0,0 +1,540 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"togo/internal/audit"
+	"togo/internal/config"
+	"togo/internal/conflict"
+	"togo/internal/editlock"
+	"togo/internal/encryption"
+	"togo/internal/hooks"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/notestore"
+	"togo/internal/persist"
+	"togo/internal/remind"
+	"togo/internal/repository"
+	"togo/internal/repository/boltstore"
+	"togo/internal/repository/cachestore"
+	"togo/internal/repository/eventstore"
+	"togo/internal/repository/jsonstore"
+	"togo/internal/service"
+	"togo/internal/theme"
+	"togo/internal/watch"
+)
+
+// journalFlag is the journal chosen with --journal for this invocation;
+// empty means the configured default.
+var journalFlag string
+
+// dataDirFlag is the data directory chosen with --data-dir for this
+// invocation; empty means $TOGO_DATA_DIR or the configured one.
+var dataDirFlag string
+
+// dataDirEnv names the environment variable overriding the configured
+// data directory.
+const dataDirEnv = "TOGO_DATA_DIR"
+
+// loadConfig reads the user's configuration, falling back to the defaults
+// when there is none. The data directory is, in order of precedence, the
+// --data-dir flag, $TOGO_DATA_DIR, the data_dir setting, or the platform
+// default; the file is never rewritten with an override. The model's
+// settings are applied for the rest of the process.
+func loadConfig() (config.Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return config.Config{}, err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, err
+	}
+	if dir := os.Getenv(dataDirEnv); dir != "" {
+		cfg.DataDir = dir
+	}
+	if dataDirFlag != "" {
+		cfg.DataDir = dataDirFlag
+	}
+	applySettings(cfg)
+	return cfg, nil
+}
+
+// applySettings configures the thresholds the model package checks tasks
+// against, and the weights it scores them with, from cfg.
+func applySettings(cfg config.Config) {
+	taskmodel.SetDeferWarnThreshold(cfg.DeferWarnThreshold)
+	taskmodel.SetUrgencyWeights(cfg.UrgencyWeights())
+	taskmodel.SetLimits(cfg.Limits())
+	taskmodel.SetStaleThreshold(cfg.StaleThreshold())
+}
+
+// activeJournal names the journal this invocation works on.
+func activeJournal(cfg config.Config) string {
+	if journalFlag != "" {
+		return journalFlag
+	}
+	return cfg.Journal
+}
+
+// journalPath returns the location of the named journal's storage file for
+// the configured backend.
+func journalPath(cfg config.Config, name string) (string, error) {
+	dir, err := cfg.JournalDir()
+	if err != nil {
+		return "", err
+	}
+	file := jsonstore.FileName
+	switch cfg.Backend {
+	case config.BackendBolt:
+		file = boltstore.FileName
+	case config.BackendEvents:
+		file = eventstore.FileName
+	}
+	return filepath.Join(journals.Dir(dir, name), file), nil
+}
+
+// backupDir returns where backups of the journal at journal are kept.
+func backupDir(journal string) string {
+	return filepath.Join(filepath.Dir(journal), "backups")
+}
+
+// notesDir returns the directory holding the notes the journal at journal
+// keeps as files.
+func notesDir(journal string) string {
+	return filepath.Join(filepath.Dir(journal), notestore.DirName)
+}
+
+// openRepository opens the named journal with the configured backend,
+// backups, encryption and note files. Callers close it with
+// closeRepository.
+func openRepository(cfg config.Config, name string) (repository.TaskRepository, error) {
+	return openRepositoryWith(cfg, name, jsonstore.New)
+}
+
+// openDebouncedRepository opens the named journal as openRepository does,
+// except that a JSON journal coalesces bursts of changes, such as the TUI
+// makes, into one write; the write-ahead log keeps them meanwhile. Failed
+// background writes go to onError.
+func openDebouncedRepository(cfg config.Config, name string, onError func(error)) (repository.TaskRepository, error) {
+	return openRepositoryWith(cfg, name, func(path string) *jsonstore.Repository {
+		return jsonstore.NewDebounced(path, persist.DefaultDelay, persist.DefaultMaxWait, onError)
+	})
+}
+
+// openRepositoryWith opens the named journal as openRepository does,
+// creating a JSON journal's repository with newJSON.
+func openRepositoryWith(cfg config.Config, name string, newJSON func(path string) *jsonstore.Repository) (repository.TaskRepository, error) {
+	path, err := journalPath(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Backend {
+	case config.BackendBolt:
+		repo, err := boltstore.Open(path)
+		if errors.Is(err, boltstore.ErrInUse) {
+			return nil, fmt.Errorf("%w; the bolt backend lets one togo at a time open a journal, so quit the other one first", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	case config.BackendEvents:
+		return eventstore.New(path), nil
+	}
+
+	repo := newJSON(path)
+	repo.EnableBackups(backupDir(path), cfg.Backups())
+	keyring, err := journalKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Note files are plaintext, so the notes of an encrypted journal stay
+	// in it; references already made are still read.
+	threshold := cfg.NotesSplitThreshold
+	if keyring != nil {
+		repo.EnableEncryption(keyring)
+		threshold = 0
+	}
+	repo.EnableNoteFiles(notestore.New(notesDir(path), threshold))
+	return repo, nil
+}
+
+// closeRepository releases repo if its backend holds resources.
+func closeRepository(repo repository.TaskRepository) error {
+	if c, ok := repo.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// listJournal returns the active journal's tasks matching filter, holding
+// only those in memory where the backend allows.
+func listJournal(filter taskmodel.TaskFilter) ([]*taskmodel.Task, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := openRepository(cfg, activeJournal(cfg))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRepository(repo)
+	return repository.Scan(repo, filter)
+}
+
+// journalSession keeps one journal open for the TUI at a time, so it can
+// switch between journals, and watches it for changes made by other
+// processes.
+type journalSession struct {
+	cfg config.Config
+	// name, current and tasks are the open journal's name, repository and
+	// the service working on it.
+	name    string
+	current repository.TaskRepository
+	tasks   *service.TaskService
+	watcher *watch.Watcher
+	// changes receives a value when the open journal changed on disk, and
+	// writeErrs the failures of the writes made in the background.
+	changes   chan struct{}
+	writeErrs chan error
+	// bus publishes the changes made to whichever journal is open, so
+	// subscribers last across journal switches.
+	bus *service.Bus
+	// hooks runs the user's hook scripts for the changes on bus.
+	hooks *hooks.Runner
+	// locks shares the open journal's edit locks with other processes,
+	// which see this one as device.
+	locks  *editlock.Table
+	device string
+}
+
+// openSession prepares a session using the user's configuration. Nothing
+// is opened until attach.
+func openSession() (*journalSession, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	runner, err := userHooks()
+	if err != nil {
+		return nil, err
+	}
+	s := &journalSession{cfg: cfg, changes: make(chan struct{}, 1), writeErrs: make(chan error, 1), bus: service.NewBus(), hooks: runner, device: editlock.Device()}
+	s.bus.Subscribe(runner.Handle)
+	return s, nil
+}
+
+// attach opens the active journal and lists it, with its siblings, in m.
+func (s *journalSession) attach(m model) (model, error) {
+	name := activeJournal(s.cfg)
+	dir, err := s.cfg.JournalDir()
+	if err != nil {
+		return m, err
+	}
+	names, err := journals.List(dir)
+	if err != nil {
+		return m, err
+	}
+	if !slices.Contains(names, name) {
+		names = append(names, name)
+		slices.Sort(names[1:])
+	}
+	tasks, err := s.open(name)
+	if err != nil {
+		return m, err
+	}
+	m.journal, m.journals, m.tasks, m.openJournal = name, names, tasks, s.open
+	m.changes, m.writeErrs, m.conflictsOf = s.changes, s.writeErrs, s.sidecar
+	m.rollover = s.cfg.Rollover == config.RolloverAuto
+	if s.cfg.ArchiveAfterDays > 0 {
+		m.archiveDone = s.archiveDone
+	}
+	m.reviewPolicy, m.markReviewed = reviewPolicy(s.cfg), s.markReviewed
+	m.nudgePolicy, m.lastReviewed = s.cfg.NudgePolicy(), s.lastReviewed
+	m.planPolicy = planPolicy(s.cfg)
+	m.idDisplay = s.cfg.IDDisplay
+	m.theme, m.keymap = theme.For(s.cfg.Theme, os.Getenv), s.cfg.Keymap
+	m.links = s.cfg.Linker(os.Getenv)
+	m.titleOrder, m.titleOrderOf = s.cfg.TitleOrder(name), s.cfg.TitleOrder
+	m.celebrator = s.cfg.Celebrator()
+	m.claimEdit = s.claim
+	if s.cfg.Notifications == config.NotificationsOn {
+		m.reminders = remind.NewScheduler(desktopNotifier(), taskmodel.Now())
+	}
+	return m.refresh().startDay(), nil
+}
+
+// open switches the session to the named journal, closing the previous one,
+// and returns the service running its use cases. The journal is read
+// through a cache, since the TUI lists and filters it on every keystroke.
+func (s *journalSession) open(name string) (*service.TaskService, error) {
+	history, err := journalHistory(s.cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := journalArchive(s.cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	log, err := journalAudit(s.cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := openDebouncedRepository(s.cfg, name, s.writeFailed)
+	if err != nil {
+		return nil, err
+	}
+	path, err := journalPath(s.cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	repo := cachestore.New(backend)
+	s.close()
+	s.name, s.current = name, repo
+	s.locks = editlock.Open(editlock.Path(path), taskmodel.DefaultEditLease)
+	s.watcher = s.watch(name)
+	tasks := service.New(repo, s.bus)
+	tasks.SetHistory(history)
+	tasks.SetArchive(archive)
+	tasks.SetTodayLimit(todayLimit(s.cfg))
+	tasks.SetAudit(log, audit.SourceTUI, s.writeFailed)
+	tasks.SetSequence(service.SequencePath(path))
+	s.tasks = tasks
+	return tasks, nil
+}
+
+// sidecar returns the named journal's conflict sidecar, encrypted as the
+// journal is, or nil when the journal cannot be located.
+func (s *journalSession) sidecar(name string) *conflict.Sidecar {
+	path, err := journalPath(s.cfg, name)
+	if err != nil {
+		return nil
+	}
+	keyring, err := journalKeyring(s.cfg)
+	if err != nil {
+		return nil
+	}
+	return conflict.Open(conflict.SidecarPath(path), keyring)
+}
+
+// userHooks returns a runner for the hook scripts beside the configuration
+// file.
+func userHooks() (*hooks.Runner, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	return hooks.New(hooks.DefaultDir(path), hooks.DefaultTimeout), nil
+}
+
+// openService opens the named journal for a command that changes it, with
+// its undo history, archive, audit log and sequence numbers and the
+// user's hooks. The returned function closes the journal and waits for
+// the hooks, returning their failures and those of writing the audit log.
+func openService(cfg config.Config, name string) (*service.TaskService, func() error, error) {
+	history, err := journalHistory(cfg, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	archive, err := journalArchive(cfg, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	log, err := journalAudit(cfg, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	runner, err := userHooks()
+	if err != nil {
+		return nil, nil, err
+	}
+	path, err := journalPath(cfg, name)
+	if err != nil {
+		runner.Close()
+		return nil, nil, err
+	}
+	repo, err := openRepository(cfg, name)
+	if err != nil {
+		runner.Close()
+		return nil, nil, err
+	}
+	bus := service.NewBus()
+	bus.Subscribe(runner.Handle)
+	tasks := service.New(repo, bus)
+	tasks.SetHistory(history)
+	tasks.SetArchive(archive)
+	tasks.SetTodayLimit(todayLimit(cfg))
+	var auditErrs []error
+	tasks.SetAudit(log, audit.SourceCLI, func(err error) { auditErrs = append(auditErrs, err) })
+	tasks.SetSequence(service.SequencePath(path))
+	return tasks, func() error {
+		return errors.Join(closeRepository(repo), runner.Close(), errors.Join(auditErrs...))
+	}, nil
+}
+
+// todayLimit converts the today list limit settings into the service's.
+func todayLimit(cfg config.Config) service.TodayLimit {
+	return service.TodayLimit{Max: cfg.TodayLimit, Block: cfg.TodayLimitMode == config.TodayLimitBlock}
+}
+
+// planPolicy shapes the TUI's plans for the day by the daily capacity and
+// urgency settings.
+func planPolicy(cfg config.Config) service.PlanPolicy {
+	return service.PlanPolicy{Capacity: cfg.DailyCapacity, Weights: cfg.UrgencyWeights()}
+}
+
+// archiveDone moves the open journal's tasks completed more than
+// archive_after_days ago into its archive, returning how many it moved.
+func (s *journalSession) archiveDone() (int, error) {
+	return s.tasks.ArchiveDone(taskmodel.Now().Add(-s.cfg.ArchiveAge()))
+}
+
+// markReviewed records that the open journal was reviewed now.
+func (s *journalSession) markReviewed() error {
+	path, err := journalPath(s.cfg, s.name)
+	if err != nil {
+		return err
+	}
+	return service.MarkReviewed(service.ReviewedPath(path), taskmodel.Now())
+}
+
+// lastReviewed returns when the open journal was last reviewed, or the
+// zero time if it never was.
+func (s *journalSession) lastReviewed() (time.Time, error) {
+	path, err := journalPath(s.cfg, s.name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return service.LastReviewed(service.ReviewedPath(path))
+}
+
+// journalHistory returns the named journal's undo history, shared by every
+// process using the journal and encrypted as the journal is.
+func journalHistory(cfg config.Config, name string) (*service.History, error) {
+	path, err := journalPath(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := journalKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return service.OpenHistory(service.HistoryPath(path), service.DefaultHistoryLimit, keyring), nil
+}
+
+// journalAudit returns the named journal's audit log, encrypted as the
+// journal is.
+func journalAudit(cfg config.Config, name string) (*audit.Log, error) {
+	path, err := journalPath(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := journalKeyring(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Open(audit.Path(path), keyring), nil
+}
+
+// writeFailed reports a failed background write to the TUI, unless one is
+// already waiting to be shown.
+func (s *journalSession) writeFailed(err error) {
+	select {
+	case s.writeErrs <- err:
+	default:
+	}
+}
+
+// watch starts watching the named journal's files, signalling s.changes.
+// Watching is a convenience, so it returns nil when it cannot start.
+func (s *journalSession) watch(name string) *watch.Watcher {
+	path, err := journalPath(s.cfg, name)
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0o700) != nil {
+		return nil
+	}
+	w, err := watch.Watch([]string{path, jsonstore.LogPath(path)}, watch.DefaultSettle, func() {
+		select {
+		case s.changes <- struct{}{}:
+		default:
+		}
+	}, nil)
+	if err != nil {
+		return nil
+	}
+	return w
+}
+
+// claim takes the edit lock on the task with the given ID in the open
+// journal, releasing the one held before, and returns the locks other
+// processes hold.
+func (s *journalSession) claim(id taskmodel.TaskID) (map[taskmodel.TaskID]taskmodel.EditLock, error) {
+	if s.locks == nil {
+		return nil, nil
+	}
+	return s.locks.Claim(id, s.device)
+}
+
+// close releases the session's journal and its edit lock.
+func (s *journalSession) close() error {
+	if s.watcher != nil {
+		s.watcher.Close()
+		s.watcher = nil
+	}
+	var err error
+	if s.locks != nil {
+		err = s.locks.Release(s.device)
+		s.locks = nil
+	}
+	if s.current == nil {
+		return err
+	}
+	return errors.Join(err, closeRepository(s.current))
+}
+
+// finish closes the session's journal and waits for its hooks to finish,
+// returning their failures.
+func (s *journalSession) finish() error {
+	return errors.Join(s.close(), s.hooks.Close())
+}
+
+// passphraseEnv names the environment variable holding the journal
+// passphrase.
+const passphraseEnv = "TOGO_PASSPHRASE"
+
+// journalKeyring returns the key the journal is encrypted with, or nil when
+// encryption is off. A missing key file is generated.
+func journalKeyring(cfg config.Config) (*encryption.Keyring, error) {
+	switch cfg.Encryption {
+	case config.EncryptionPassphrase:
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, errors.New("encryption is set to passphrase but $" + passphraseEnv + " is empty")
+		}
+		return encryption.Passphrase(passphrase)
+	case config.EncryptionKeyFile:
+		path := cfg.EncryptionKeyFile
+		if path == "" {
+			conf, err := configPath()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(filepath.Dir(conf), "journal.key")
+		}
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			if err := encryption.GenerateKeyFile(path); err != nil {
+				return nil, err
+			}
+		}
+		return encryption.LoadKeyFile(path)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/boltstore"
+	"togo/internal/repository/eventstore"
+	"togo/internal/repository/jsonstore"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestJournalKeyring(t *testing.T) {
+	conf := withConfigPath(t)
+
+	cfg := config.Default()
+	if k, err := journalKeyring(cfg); k != nil || err != nil {
+		t.Errorf("encryption off: got %v, %v", k, err)
+	}
+
+	cfg.Encryption = config.EncryptionPassphrase
+	t.Setenv(passphraseEnv, "")
+	if _, err := journalKeyring(cfg); err == nil || !strings.Contains(err.Error(), passphraseEnv) {
+		t.Errorf("missing passphrase: error = %v", err)
+	}
+
+	cfg.Encryption = config.EncryptionKeyFile
+	first, err := journalKeyring(cfg)
+	if err != nil {
+		t.Fatalf("key file: %v", err)
+	}
+	sealed, err := first.Encrypt([]byte("tasks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := journalKeyring(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := second.Decrypt(sealed); err != nil || string(got) != "tasks" {
+		t.Errorf("expected the generated key file to be reused, got %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(conf), "journal.key")); err != nil {
+		t.Errorf("expected the key beside the configuration: %v", err)
+	}
+}
+
+func TestRun_JournalFlag(t *testing.T) {
+	withConfigPath(t)
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantCode     int
+		wantJournal  string
+		wantJournals []string
+	}{
+		{name: "configured default", args: []string{"ui"}, wantJournal: "default", wantJournals: []string{"default"}},
+		{name: "separate value", args: []string{"--journal", "work", "ui"}, wantJournal: "work", wantJournals: []string{"default", "work"}},
+		{name: "joined value, implicit ui", args: []string{"--journal=personal"}, wantJournal: "personal", wantJournals: []string{"default", "personal"}},
+		{name: "invalid name", args: []string{"--journal", "My Work"}, wantCode: 2},
+		{name: "missing name", args: []string{"--journal"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			launched := stubLaunch(t, nil)
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+			if launched.journal != tt.wantJournal || !slices.Equal(launched.journals, tt.wantJournals) {
+				t.Errorf("launched journal %q of %v, want %q of %v", launched.journal, launched.journals, tt.wantJournal, tt.wantJournals)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Settings(t *testing.T) {
+	path := withConfigPath(t)
+	t.Cleanup(func() { applySettings(config.Default()) })
+
+	tests := []struct {
+		name  string
+		set   func(cfg *config.Config)
+		check func(t *testing.T)
+	}{
+		{
+			name: "defer warning threshold",
+			set:  func(cfg *config.Config) { cfg.DeferWarnThreshold = 1 },
+			check: func(t *testing.T) {
+				task := testutil.NewTask().Build()
+				if warning, _ := task.Defer(nil); warning != nil {
+					t.Errorf("first deferral warned: %v", warning)
+				}
+				if warning, _ := task.Defer(nil); warning == nil || warning.Threshold != 1 {
+					t.Errorf("second deferral warning = %v, want one at threshold 1", warning)
+				}
+			},
+		},
+		{
+			name: "urgency weights",
+			set: func(cfg *config.Config) {
+				cfg.UrgencyDue, cfg.UrgencyPriority, cfg.UrgencyAge, cfg.UrgencyDeferred = 0, 0, 0, 0
+				cfg.UrgencyTags = "next:15"
+			},
+			check: func(t *testing.T) {
+				now := taskmodel.Now()
+				task := testutil.NewTask().WithTags("next").WithDue(now).WithPriority(taskmodel.PriorityHigh).Build()
+				if got := task.Urgency(now); got != 15 {
+					t.Errorf("Urgency() = %v, want 15 from the tag alone", got)
+				}
+			},
+		},
+		{
+			name: "size limits",
+			set:  func(cfg *config.Config) { cfg.MaxTitleLength = 5 },
+			check: func(t *testing.T) {
+				if _, err := taskmodel.NewTask("too long", nil); err == nil {
+					t.Error("NewTask accepted a title over the configured limit")
+				}
+				if _, err := taskmodel.NewTask("short", nil); err != nil {
+					t.Errorf("NewTask(short) = %v", err)
+				}
+			},
+		},
+		{
+			name: "stale threshold",
+			set:  func(cfg *config.Config) { cfg.StaleAfterDays = 2 },
+			check: func(t *testing.T) {
+				touched := taskmodel.Now().Add(-3 * 24 * time.Hour)
+				task := testutil.NewTask().WithCreatedAt(touched).Build()
+				if !task.IsStale() {
+					t.Error("task untouched for 3 days is not stale after 2")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tt.set(&cfg)
+			if err := config.Save(path, cfg); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadConfig(); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t)
+		})
+	}
+}
+
+func TestRun_DataDir(t *testing.T) {
+	withConfigPath(t)
+	dirs := map[string]string{}
+	for _, title := range []string{"configured", "environment", "flag"} {
+		cfg := config.Default()
+		cfg.DataDir = t.TempDir()
+		dirs[title] = cfg.DataDir
+		repo, err := openRepository(cfg, journals.Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.MustSeed(t, repo, testutil.NewTask().WithTitle(title).Build())
+		if title == "configured" {
+			path, _ := configPath()
+			if err := config.Save(path, cfg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want string
+	}{
+		{name: "configured", want: "configured"},
+		{name: "environment", env: dirs["environment"], want: "environment"},
+		{name: "flag", args: []string{"--data-dir", dirs["flag"]}, want: "flag"},
+		{name: "flag over environment", env: dirs["environment"], args: []string{"--data-dir=" + dirs["flag"]}, want: "flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(dataDirEnv, tt.env)
+			var stdout, stderr bytes.Buffer
+			args := append(tt.args, "export", "--format", "csv", "--columns", "title")
+			if code := run(args, &stdout, &stderr); code != 0 {
+				t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+			}
+			if want := "title\r\n" + tt.want + "\r\n"; stdout.String() != want {
+				t.Errorf("stdout = %q, want %q", stdout.String(), want)
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--data-dir"}, &stdout, &stderr); code != 2 {
+		t.Errorf("--data-dir without a value exited %d, want 2", code)
+	}
+}
+
+func TestModel_SwitchJournal(t *testing.T) {
+	repos := map[string]*memstore.Repository{"default": memstore.New(), "work": memstore.New()}
+	testutil.MustSeed(t, repos["work"], testutil.NewTask().WithTitle("Ship the release").Build())
+
+	m := initializeModel()
+	m.journal, m.journals, m.tasks = "default", []string{"default", "work"}, service.New(repos["default"], nil)
+	m.openJournal = func(name string) (*service.TaskService, error) { return service.New(repos[name], nil), nil }
+	m = m.refresh()
+
+	nm, _ := m.Update(keyMsg("J"))
+	got := nm.(model)
+	if got.journal != "work" || !slices.Equal(titles(got), []string{"Ship the release"}) {
+		t.Fatalf("after J: journal %q with %q", got.journal, titles(got))
+	}
+	if !strings.Contains(got.View(), "Journal: work") {
+		t.Errorf("expected the view to name the journal; got:\n%s", got.View())
+	}
+
+	nm, _ = got.Update(keyMsg("J"))
+	if got := nm.(model); got.journal != "default" || !strings.Contains(got.View(), "No tasks.") {
+		t.Errorf("expected J to wrap around to the empty default journal, got %q:\n%s", got.journal, got.View())
+	}
+}
+
+func TestModel_SwitchJournal_Error(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Water the plants").Build())
+	m := initializeModel()
+	m.journal, m.journals, m.tasks = "default", []string{"default", "work"}, service.New(repo, nil)
+	m.openJournal = func(name string) (*service.TaskService, error) { return nil, boltstore.ErrInUse }
+	m = m.refresh()
+
+	nm, _ := m.Update(keyMsg("J"))
+	got := nm.(model)
+	if got.journal != "default" || !slices.Equal(titles(got), []string{"Water the plants"}) {
+		t.Errorf("after a failed J: journal %q with %q, want the default journal still open", got.journal, titles(got))
+	}
+	if view := got.View(); !strings.Contains(view, `Cannot open the journal "work": `+boltstore.ErrInUse.Error()) {
+		t.Errorf("view does not report the failure:\n%s", view)
+	}
+}
+
+func TestModel_SwitchJournal_TitleOrder(t *testing.T) {
+	repos := map[string]*memstore.Repository{"default": memstore.New(), "work": memstore.New()}
+	for _, repo := range repos {
+		testutil.MustSeed(t, repo, testutil.Tasks(testutil.NewTask().WithTitle("Zucker kaufen"), testutil.NewTask().WithTitle("Äpfel kaufen"))...)
+	}
+	cfg := config.Default()
+	cfg.JournalCollation = "work=de"
+
+	m := initializeModel()
+	m.journal, m.journals, m.tasks = "default", []string{"default", "work"}, service.New(repos["default"], nil)
+	m.openJournal = func(name string) (*service.TaskService, error) { return service.New(repos[name], nil), nil }
+	m.byTitle, m.titleOrder, m.titleOrderOf = true, cfg.TitleOrder("default"), cfg.TitleOrder
+	m = m.refresh()
+	if got, want := titles(m), []string{"Zucker kaufen", "Äpfel kaufen"}; !slices.Equal(got, want) {
+		t.Fatalf("default journal in byte order = %q, want %q", got, want)
+	}
+
+	nm, _ := m.Update(keyMsg("J"))
+	if got, want := titles(nm.(model)), []string{"Äpfel kaufen", "Zucker kaufen"}; !slices.Equal(got, want) {
+		t.Errorf("work journal in German order = %q, want %q", got, want)
+	}
+}
+
+func TestOpenRepository_Backends(t *testing.T) {
+	tests := []struct {
+		backend string
+		file    string
+	}{
+		{config.BackendJSON, jsonstore.FileName},
+		{config.BackendBolt, boltstore.FileName},
+		{config.BackendEvents, eventstore.FileName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			cfg := config.Default()
+			cfg.Backend = tt.backend
+			repo, err := openRepository(cfg, journals.Default)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := testutil.NewTask().Build()
+			err = repo.Save(task)
+			closeRepository(repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path, _ := journalPath(cfg, journals.Default)
+			if filepath.Base(path) != tt.file {
+				t.Errorf("journalPath() = %s, want a %s file", path, tt.file)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("journal not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestSession_ReloadsExternalChanges(t *testing.T) {
+	withConfigPath(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	session, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.close()
+	other, err := openRepository(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.MustSeed(t, other,
+		testutil.NewTask().WithTitle("Renew passport").Build(),
+		testutil.NewTask().WithTitle("File taxes").Build(),
+	)
+
+	m, err := session.attach(initializeModel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.cursor = 1
+
+	testutil.MustSeed(t, other, testutil.NewTask().WithTitle("Call plumber").Build())
+	select {
+	case <-m.changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+	nm, cmd := m.Update(journalChangedMsg{})
+	got := nm.(model)
+	if want := []string{"Renew passport", "File taxes", "Call plumber"}; !slices.Equal(titles(got), want) {
+		t.Errorf("list after reload = %q, want %q", titles(got), want)
+	}
+	if got.cursor != 1 {
+		t.Errorf("reload moved the cursor to %d, want it kept on File taxes", got.cursor)
+	}
+	if cmd == nil {
+		t.Error("reload stopped waiting for further changes")
+	}
+}
+
+func TestSession_DebouncesWrites(t *testing.T) {
+	withConfigPath(t)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	seedJournal(t, testutil.NewTask().WithTitle("Renew passport"))
+	session, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := session.attach(initializeModel())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nm, _ := m.Update(keyMsg("x"))
+	if notice := nm.(model).notice; notice != "Completed." {
+		t.Fatalf("notice = %q, want Completed.", notice)
+	}
+	done := taskmodel.StatusDone
+	count := func() int {
+		t.Helper()
+		tasks, err := listJournal(taskmodel.TaskFilter{Status: &done})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(tasks)
+	}
+	if n := count(); n != 1 {
+		t.Errorf("another process sees %d done tasks before the write, want 1 from the log", n)
+	}
+	if err := session.finish(); err != nil {
+		t.Fatal(err)
+	}
+	path, err := journalPath(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(jsonstore.LogPath(path)); !os.IsNotExist(err) {
+		t.Errorf("write-ahead log left after closing: %v", err)
+	}
+	if n := count(); n != 1 {
+		t.Errorf("%d done tasks in the journal after closing, want 1", n)
+	}
+}
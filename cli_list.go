@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/query"
+)
+
+// runList implements "togo list": print the tasks matching a saved filter,
+// a query, or both, in the order the TUI lists them, with the record each
+// imported task came from.
+//
+//	togo list
+//	togo list --filter inbox
+//	togo list --filter this-week +work
+//	togo list --sort-title status:today
+func runList(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("filter", "", "start from the saved filter of this name")
+	byTitle := fs.Bool("sort-title", false, "order tasks by title, as the collation setting says")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	expr := strings.Join(fs.Args(), " ")
+	if *name != "" {
+		saved, err := loadSavedFilters()
+		if err != nil {
+			fmt.Fprintf(stderr, "togo list: %v\n", err)
+			return 1
+		}
+		f, ok := saved.Lookup(*name)
+		if !ok {
+			fmt.Fprintf(stderr, "togo list: no saved filter named %q\n", *name)
+			return 2
+		}
+		expr = strings.TrimSpace(f.Query + " " + expr)
+	}
+	filter, err := query.Parse(expr)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo list: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo list: %v\n", err)
+		return 1
+	}
+	tasks, err := listJournal(filter)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo list: %v\n", err)
+		return 1
+	}
+	if *byTitle {
+		cfg.TitleOrder(activeJournal(cfg)).SortTasks(tasks)
+	} else {
+		sortPool(tasks, taskmodel.Now())
+	}
+	taskmodel.SortPinnedFirst(tasks)
+
+	links := outputLinker(cfg, stdout)
+	for _, t := range tasks {
+		line := fmt.Sprintf("%s %s  %s", cfg.IDDisplay.Format(t), links.Linkify(t.Title), t.Status)
+		if t.Pinned {
+			line += "  pinned"
+		}
+		if t.ExternalRef != nil {
+			line += "  " + links.Ref(*t.ExternalRef)
+		}
+		fmt.Fprintln(stdout, line)
+	}
+	return 0
+}
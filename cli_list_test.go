@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+func TestRunList(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	visa := testutil.NewTask().WithTitle("Apply for a visa").WithTags("admin").WithCreatedAt(now.Add(-3 * time.Hour))
+	dentist := testutil.NewTask().WithTitle("Call the dentist").WithTags("admin").WithDue(now).WithCreatedAt(now.Add(-2 * time.Hour))
+	milk := testutil.NewTask().WithTitle("Buy milk").WithStatus(taskmodel.StatusToday).Pinned().WithCreatedAt(now.Add(-time.Hour))
+	invoice := testutil.NewTask().WithTitle("Send the invoice").WithStatus(taskmodel.StatusDone).WithExternalRef(taskmodel.ExternalRef{System: "jira", ID: "FIN-12"})
+	seedJournal(t, visa, dentist, milk, invoice)
+	line := func(b *testutil.TaskBuilder, rest string) string {
+		task := b.Build()
+		return config.Default().IDDisplay.Format(task) + " " + task.Title + "  " + rest + "\n"
+	}
+
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{args: []string{"filter", "save", "admin", "+admin"}},
+		{args: []string{"list"}, wantStdout: line(milk, "today  pinned") + line(dentist, "pool") + line(visa, "pool") + line(invoice, "done  jira:FIN-12")},
+		{args: []string{"list", "--filter", "admin"}, wantStdout: line(dentist, "pool") + line(visa, "pool")},
+		{args: []string{"list", "--filter", "admin", "--sort-title"}, wantStdout: line(visa, "pool") + line(dentist, "pool")},
+		{args: []string{"list", "--filter", "admin", "visa"}, wantStdout: line(visa, "pool")},
+		{args: []string{"list", "status:today"}, wantStdout: line(milk, "today  pinned")},
+		{args: []string{"list", "status:done"}, wantStdout: line(invoice, "done  jira:FIN-12")},
+		{args: []string{"list", "--filter", "errands"}, wantCode: 2},
+		{args: []string{"list", "status:later"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+			t.Fatalf("%v: exit code %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, stderr.String())
+		}
+		if stdout.String() != tt.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", tt.args, stdout.String(), tt.wantStdout)
+		}
+	}
+}
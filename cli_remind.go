@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/remind"
+)
+
+// desktopNotifier shows reminders on the desktop. It is a variable so
+// tests can observe notifications without a desktop.
+var desktopNotifier = remind.Desktop
+
+// runRemind implements "togo remind": list the reminders and due times
+// still to come today or, with --daemon, stay in the background showing a
+// desktop notification as each is reached, until interrupted.
+//
+//	togo remind
+//	togo remind --daemon [--interval 1m]
+func runRemind(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("remind", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	daemon := fs.Bool("daemon", false, "keep running and notify as reminders are reached")
+	interval := fs.Duration("interval", time.Minute, "how often the daemon checks for reminders")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo remind: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(stderr, "togo remind: --interval must be positive\n")
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo remind: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo remind: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo remind: warning: %v\n", err)
+		}
+	}()
+	list := func() ([]*taskmodel.Task, error) {
+		return tasks.ListTasks(taskmodel.TaskFilter{})
+	}
+
+	now := taskmodel.Now()
+	if !*daemon {
+		listed, err := list()
+		if err != nil {
+			fmt.Fprintf(stderr, "togo remind: %v\n", err)
+			return 1
+		}
+		// Tasks due tomorrow are reached at midnight, which is not today.
+		endOfDay := taskmodel.StartOfDay(now).AddDate(0, 0, 1).Add(-time.Nanosecond)
+		due := remind.Between(listed, now, endOfDay)
+		if len(due) == 0 {
+			fmt.Fprintln(stdout, "No reminders left today.")
+		}
+		for _, r := range due {
+			fmt.Fprintln(stdout, reminderLine(r))
+		}
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(stdout, "Watching %s for reminders; press Ctrl+C to stop.\n", activeJournal(cfg))
+	remind.NewScheduler(desktopNotifier(), now).Run(ctx, *interval, list, func(due []remind.Reminder, err error) {
+		for _, r := range due {
+			fmt.Fprintln(stdout, reminderLine(r))
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "togo remind: warning: %v\n", err)
+		}
+	})
+	return 0
+}
+
+// reminderLine describes a reminder in one line.
+func reminderLine(r remind.Reminder) string {
+	return fmt.Sprintf("%s %-8s %s", r.At.Format("15:04"), r.Kind, r.Task.Title)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/remind"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunRemind(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.Local)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+
+	tests := []struct {
+		name  string
+		tasks []*testutil.TaskBuilder
+		want  string
+	}{
+		{
+			name: "reminders left",
+			tasks: []*testutil.TaskBuilder{
+				testutil.NewTask().WithTitle("Call mum").WithReminder(now.Add(9*time.Hour + 30*time.Minute)),
+				testutil.NewTask().WithTitle("Stand-up").WithReminder(now.Add(-time.Hour)),
+				testutil.NewTask().WithTitle("File taxes").WithDue(taskmodel.StartOfDay(now).AddDate(0, 0, 1)),
+			},
+			want: "18:30 reminder Call mum\n",
+		},
+		{
+			name:  "none left",
+			tasks: []*testutil.TaskBuilder{testutil.NewTask().WithTitle("Stand-up").WithReminder(now.Add(-time.Hour))},
+			want:  "No reminders left today.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedJournal(t, tt.tasks...)
+			var stdout, stderr bytes.Buffer
+			if code := run([]string{"remind"}, &stdout, &stderr); code != 0 {
+				t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunRemind_BadFlags(t *testing.T) {
+	withConfigPath(t)
+	for _, args := range [][]string{{"remind", "--interval", "0s"}, {"remind", "now"}} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestModel_Reminders(t *testing.T) {
+	now := time.Now()
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Call mum").WithReminder(now.Add(-time.Second)).Build())
+	var shown []string
+	notifier := remind.NotifierFunc(func(_ context.Context, title, body string) error {
+		shown = append(shown, title+": "+body)
+		return nil
+	})
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m.reminders = remind.NewScheduler(notifier, now.Add(-time.Minute))
+
+	nm, _ := m.Update(m.checkReminders()())
+	m = nm.(model)
+	if len(shown) != 1 || shown[0] != "togo: reminder: Call mum" {
+		t.Errorf("notified %q, want the reminder once", shown)
+	}
+	if m.notice != "Reminder: Call mum." {
+		t.Errorf("notice = %q, want the reminder", m.notice)
+	}
+	if msg := m.checkReminders()(); len(msg.(remindedMsg).due) != 0 {
+		t.Errorf("second check notified %+v again", msg)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+)
+
+// reviewDecisions are the answers "togo review" accepts for each task,
+// also by their first letter.
+var reviewDecisions = []string{"keep", "reschedule", "archive", "delete", "skip", "quit"}
+
+// reviewPolicy chooses the tasks a review goes through from the staleness
+// and deferral warning settings.
+func reviewPolicy(cfg config.Config) service.ReviewPolicy {
+	return service.ReviewPolicy{StaleAfter: cfg.StaleThreshold(), DeferredAtLeast: cfg.DeferWarnThreshold}
+}
+
+// reviewTally counts the decisions made in a review.
+type reviewTally struct {
+	kept, rescheduled, archived, deleted, skipped int
+}
+
+// decide applies decision, one of reviewDecisions other than quit, to the
+// task with the given ID, rescheduling it to day, and counts it.
+func (t *reviewTally) decide(tasks *service.TaskService, id taskmodel.TaskID, decision string, day time.Time) error {
+	var err error
+	switch decision {
+	case "keep":
+		if _, err = tasks.KeepTask(id); err == nil {
+			t.kept++
+		}
+	case "reschedule":
+		if _, err = tasks.RescheduleTask(id, day); err == nil {
+			t.rescheduled++
+		}
+	case "archive":
+		if err = tasks.ArchiveTask(id); err == nil {
+			t.archived++
+		}
+	case "delete":
+		if err = tasks.DeleteTask(id); err == nil {
+			t.deleted++
+		}
+	case "skip":
+		t.skipped++
+	default:
+		err = fmt.Errorf("unknown decision %q", decision)
+	}
+	return err
+}
+
+// total counts the tasks decided on.
+func (t reviewTally) total() int {
+	return t.kept + t.rescheduled + t.archived + t.deleted + t.skipped
+}
+
+// String summarizes the review in one line.
+func (t reviewTally) String() string {
+	n := t.total()
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		verb string
+	}{{t.kept, "kept"}, {t.rescheduled, "rescheduled"}, {t.archived, "archived"}, {t.deleted, "deleted"}, {t.skipped, "skipped"}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.verb))
+		}
+	}
+	if n == 0 {
+		return "Nothing reviewed."
+	}
+	return fmt.Sprintf("Reviewed %d %s: %s.", n, plural(n, "task"), strings.Join(parts, ", "))
+}
+
+// runReview implements "togo review": walk through the overdue tasks, the
+// tasks deferred at least defer_warn_threshold times and the pool tasks
+// untouched for stale_after_days, deciding one at a time whether to keep,
+// reschedule, archive or delete each. Finishing the review records when it
+// was done; quitting early does not.
+//
+//	togo review
+func runReview(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo review: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo review: %v\n", err)
+		return 1
+	}
+	path, err := journalPath(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo review: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo review: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo review: warning: %v\n", err)
+		}
+	}()
+
+	now := taskmodel.Now()
+	items, err := tasks.ReviewItems(reviewPolicy(cfg), now)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo review: %v\n", err)
+		return 1
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(stdout, "Nothing to review.")
+	}
+
+	in := bufio.NewReader(stdin)
+	var tally reviewTally
+	for i, item := range items {
+		fmt.Fprintf(stdout, "\n[%d/%d] %s\n  %s\n", i+1, len(items), item.Task.Title, item.Describe(now))
+		decision, day, err := askReview(in, stdout, now)
+		if err != nil {
+			fmt.Fprintf(stderr, "togo review: %v\n", err)
+			return 1
+		}
+		if decision == "quit" {
+			fmt.Fprintln(stdout, tally)
+			return 0
+		}
+		if err := tally.decide(tasks, item.Task.ID, decision, day); err != nil {
+			fmt.Fprintf(stderr, "togo review: %s: %v\n", item.Task.Title, err)
+			return 1
+		}
+	}
+	if len(items) > 0 {
+		fmt.Fprintln(stdout, tally)
+	}
+	if err := service.MarkReviewed(service.ReviewedPath(path), now); err != nil {
+		fmt.Fprintf(stderr, "togo review: warning: %v\n", err)
+	}
+	return 0
+}
+
+// askReview asks for the decision on a task until it gets one of
+// reviewDecisions and, to reschedule, the day to reschedule it to.
+// Exhausted input skips the task.
+func askReview(in *bufio.Reader, out io.Writer, now time.Time) (string, time.Time, error) {
+	for {
+		answer, err := prompt(in, out, "Decision", "skip", reviewDecisions)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		decision := ""
+		for _, d := range reviewDecisions {
+			if answer == d || answer == d[:1] {
+				decision = d
+			}
+		}
+		if decision == "" {
+			fmt.Fprintf(out, "  unknown decision %q\n", answer)
+			continue
+		}
+		if decision != "reschedule" {
+			return decision, time.Time{}, nil
+		}
+		for {
+			answer, err := prompt(in, out, "Reschedule to", "+1w", nil)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			day, err := taskmodel.ParseRelativeDate(answer, now)
+			if err == nil {
+				return decision, day, nil
+			}
+			fmt.Fprintf(out, "  %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunReview(t *testing.T) {
+	now := time.Date(2025, 11, 12, 20, 0, 0, 0, time.Local)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	longAgo := now.AddDate(0, -2, 0)
+	seed := func(t *testing.T) {
+		seedJournal(t,
+			testutil.NewTask().WithTitle("File taxes").WithDue(now.AddDate(0, 0, -2)),
+			testutil.NewTask().WithTitle("Call the bank").WithHistory(testutil.Deferred(now), testutil.Deferred(now), testutil.Deferred(now)),
+			testutil.NewTask().WithTitle("Learn the banjo").WithCreatedAt(longAgo),
+			testutil.NewTask().WithTitle("Water the plants"),
+		)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		want      []string
+		wantTitle []string
+		reviewed  bool
+	}{
+		{
+			name:      "decide each",
+			input:     "r\nfriday\nx\nkeep\nd\n",
+			want:      []string{"[1/3] File taxes\n  overdue since 2025-11-10\n", "unknown decision \"x\"", "Reviewed 3 tasks: 1 kept, 1 rescheduled, 1 deleted."},
+			wantTitle: []string{"Call the bank", "File taxes", "Water the plants"},
+			reviewed:  true,
+		},
+		{
+			name:      "archive and quit",
+			input:     "a\nq\n",
+			want:      []string{"Reviewed 1 task: 1 archived."},
+			wantTitle: []string{"Call the bank", "Learn the banjo", "Water the plants"},
+		},
+		{
+			name:      "input runs out",
+			input:     "",
+			want:      []string{"Reviewed 3 tasks: 3 skipped."},
+			wantTitle: []string{"Call the bank", "File taxes", "Learn the banjo", "Water the plants"},
+			reviewed:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed(t)
+			withStdin(t, tt.input)
+			var stdout, stderr bytes.Buffer
+			if code := run([]string{"review"}, &stdout, &stderr); code != 0 {
+				t.Fatalf("exit code %d, want 0 (stderr: %s)", code, stderr.String())
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("stdout lacks %q:\n%s", s, stdout.String())
+				}
+			}
+
+			cfg := config.Default()
+			repo, err := openRepository(cfg, journals.Default)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeRepository(repo)
+			tasks, err := repo.List(taskmodel.TaskFilter{IncludeSnoozed: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			slices.Sort(titles)
+			if got := strings.Join(titles, ", "); got != strings.Join(tt.wantTitle, ", ") {
+				t.Errorf("journal holds %s, want %s", got, strings.Join(tt.wantTitle, ", "))
+			}
+			path, _ := journalPath(cfg, journals.Default)
+			last, err := service.LastReviewed(service.ReviewedPath(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := !last.IsZero(); got != tt.reviewed {
+				t.Errorf("review recorded = %v, want %v", got, tt.reviewed)
+			}
+		})
+	}
+}
+
+func TestReviewWizard(t *testing.T) {
+	now := time.Date(2025, 11, 12, 20, 0, 0, 0, time.Local)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(
+		testutil.NewTask().WithTitle("File taxes").WithDue(now.AddDate(0, 0, -2)),
+		testutil.NewTask().WithTitle("Learn the banjo").WithCreatedAt(now.AddDate(0, -2, 0)),
+		testutil.NewTask().WithTitle("Water the plants"),
+	)...)
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m.reviewPolicy = service.ReviewPolicy{StaleAfter: 30 * 24 * time.Hour}
+	marked := 0
+	m.markReviewed = func() error { marked++; return nil }
+	m = m.refresh()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "r", want: "Review: task 1 of 2\n\n  File taxes\n  overdue since 2025-11-10"},
+		{key: "x", want: "File taxes"},
+		{key: "w", want: "Review: task 2 of 2\n\n  Learn the banjo\n  untouched for 61 days"},
+		{key: "d", want: "Reviewed 2 tasks: 1 rescheduled, 1 deleted."},
+		{key: "r", want: "Nothing to review."},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(keyMsg(tt.key))
+		m = nm.(model)
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("view after %q lacks %q:\n%s", tt.key, tt.want, view)
+		}
+	}
+	if marked != 2 {
+		t.Errorf("review recorded %d times, want 2", marked)
+	}
+	if tasks, _ := repo.List(taskmodel.TaskFilter{}); len(tasks) != 2 {
+		t.Errorf("journal holds %d tasks, want 2", len(tasks))
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+)
+
+// runRollover implements "togo rollover": create the recurring tasks that
+// came due, return the unfinished tasks of an earlier day's today list to
+// the pool and pull in the tasks scheduled for today, new ones included.
+// The TUI does this itself unless rollover is set to manual, and creates
+// recurring tasks either way.
+//
+//	togo rollover
+func runRollover(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("rollover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo rollover: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo rollover: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo rollover: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo rollover: warning: %v\n", err)
+		}
+	}()
+
+	now := taskmodel.Now()
+	created, err := tasks.Recur(now)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo rollover: %v\n", err)
+		return 1
+	}
+	if len(created) > 0 {
+		fmt.Fprintln(stdout, recurSummary(created))
+	}
+	r, err := tasks.Rollover(now)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo rollover: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, rolloverSummary(r))
+	for _, t := range r.Deferred {
+		fmt.Fprintf(stdout, "  - %s (back to the pool)\n", t.Title)
+	}
+	for _, t := range r.Pulled {
+		fmt.Fprintf(stdout, "  + %s\n", t.Title)
+	}
+	return 0
+}
+
+// rolloverSummary describes what a rollover moved in one line.
+func rolloverSummary(r service.Rollover) string {
+	var parts []string
+	if n := len(r.Deferred); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unfinished %s back to the pool", n, plural(n, "task")))
+	}
+	if n := len(r.Pulled); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d scheduled %s moved to today", n, plural(n, "task")))
+	}
+	if len(parts) == 0 {
+		return "Nothing to roll over."
+	}
+	return "Rolled over: " + strings.Join(parts, ", ") + "."
+}
+
+// recurSummary describes the recurring tasks created in one line.
+func recurSummary(created []*taskmodel.Task) string {
+	n := len(created)
+	return fmt.Sprintf("Created %d recurring %s.", n, plural(n, "task"))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunRollover(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Water the plants").WithHistory(testutil.Picked(yesterday)),
+		testutil.NewTask().WithTitle("Call the dentist").WithStatus(taskmodel.StatusToday),
+	)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "first run", want: "Rolled over: 1 unfinished task back to the pool.\n  - Water the plants (back to the pool)\n"},
+		{name: "same day", want: "Nothing to roll over.\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"rollover"}, &stdout, &stderr); code != 0 {
+			t.Fatalf("%s: exit code %d (stderr: %s)", tt.name, code, stderr.String())
+		}
+		if stdout.String() != tt.want {
+			t.Errorf("%s: stdout = %q, want %q", tt.name, stdout.String(), tt.want)
+		}
+	}
+}
+
+func TestRunRollover_Recurring(t *testing.T) {
+	today := taskmodel.StartOfDay(time.Now())
+	seedJournal(t, testutil.NewTask().WithTitle("Water the plants").
+		WithRecurrence(taskmodel.Recurrence{Every: 1, Unit: taskmodel.RecurDaily, Next: today}))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"rollover"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	want := "Created 1 recurring task.\nRolled over: 1 scheduled task moved to today.\n  + Water the plants\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestModel_RollOver(t *testing.T) {
+	repo := memstore.New()
+	now := time.Now()
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Water the plants").WithHistory(testutil.Picked(now.AddDate(0, 0, -1))).Build())
+	task := testutil.NewTask().WithTitle("Call the dentist").Build()
+	task.ScheduledFor = &now
+	testutil.MustSeed(t, repo, task)
+
+	tests := []struct {
+		rollover   bool
+		wantNotice string
+	}{
+		{rollover: false, wantNotice: ""},
+		{rollover: true, wantNotice: "Rolled over: 1 unfinished task back to the pool, 1 scheduled task moved to today."},
+	}
+	for _, tt := range tests {
+		m := initializeModel()
+		m.tasks = service.New(repo, nil)
+		m.rollover = tt.rollover
+		m = m.refresh().startDay()
+		if m.notice != tt.wantNotice {
+			t.Errorf("rollover %v: notice = %q, want %q", tt.rollover, m.notice, tt.wantNotice)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	taskmodel "togo/internal/model"
+)
+
+// runSnooze implements "togo snooze": hide a task from the default views
+// until a length of time has passed.
+//
+//	togo snooze 3f2a 2d
+//	togo snooze '#12' tomorrow
+func runSnooze(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("snooze", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: togo snooze ID LENGTH (like 2d, 3h, 1w, tomorrow or YYYY-MM-DD)")
+		return 2
+	}
+	until, err := taskmodel.ParseSnoozeUntil(fs.Arg(1), taskmodel.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "togo snooze: %v\n", err)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo snooze: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo snooze: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo snooze: warning: %v\n", err)
+		}
+	}()
+
+	t, err := tasks.ResolveTask(fs.Arg(0))
+	if err == nil {
+		t, err = tasks.SnoozeTask(t.ID, until)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "togo snooze: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Snoozed %q until %s.\n", t.Title, until.Format("2006-01-02 15:04"))
+	return 0
+}
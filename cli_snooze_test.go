@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+func TestRunSnooze(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	water := testutil.NewTask().WithTitle("Water plants").WithCreatedAt(now.Add(-time.Hour))
+	seedJournal(t,
+		water,
+		testutil.NewTask().WithTitle("File taxes").WithStatus(taskmodel.StatusDone).WithCreatedAt(now.Add(-time.Hour)),
+	)
+	id := water.Build().ID.Short()
+
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{args: []string{"snooze", id}, wantCode: 2},
+		{args: []string{"snooze", id, "soon"}, wantCode: 2},
+		{args: []string{"snooze", "ffffffff", "2d"}, wantCode: 1},
+		{args: []string{"snooze", id, "2d"}, wantStdout: "Snoozed \"Water plants\" until 2025-11-14 09:00.\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+			t.Fatalf("%v: exit code %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, stderr.String())
+		}
+		if stdout.String() != tt.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", tt.args, stdout.String(), tt.wantStdout)
+		}
+	}
+
+	listed, err := listJournal(taskmodel.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range listed {
+		if strings.Contains(task.Title, "Water") {
+			t.Errorf("snoozed task still listed: %v", task)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+)
+
+// Ranges accepted by "togo stats --range" and offered on the TUI's
+// statistics screen, which picks them by their first letter.
+var statsRanges = []string{"week", "month", "year", "all"}
+
+// statsFrom returns the first day of the named range ending on now's day,
+// or the zero time for all.
+func statsFrom(name string, now time.Time) (time.Time, error) {
+	today := taskmodel.StartOfDay(now)
+	switch name {
+	case "week":
+		return today.AddDate(0, 0, -6), nil
+	case "month":
+		return today.AddDate(0, -1, 1), nil
+	case "year":
+		return today.AddDate(-1, 0, 1), nil
+	case "all":
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown range %q; expected one of %s", name, strings.Join(statsRanges, ", "))
+	}
+}
+
+// runStats implements "togo stats": report how much was done over a range
+// of days and how it went. The range is the last week unless --range or
+// --since and --until, which take dates such as 2025-10-01, yesterday or
+// -2w, say otherwise.
+//
+//	togo stats [--range week|month|year|all]
+//	togo stats --since 2025-10-01 [--until yesterday]
+func runStats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rangeName := fs.String("range", "week", "range ending today: "+strings.Join(statsRanges, ", "))
+	since := fs.String("since", "", "first day of the range, instead of --range")
+	until := fs.String("until", "today", "last day of the range")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo stats: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	now := taskmodel.Now()
+	to, err := taskmodel.ParseRelativeDate(*until, now)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo stats: --until: %v\n", err)
+		return 2
+	}
+	from, err := statsFrom(*rangeName, to)
+	if *since != "" {
+		from, err = taskmodel.ParseRelativeDate(*since, now)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "togo stats: %v\n", err)
+		return 2
+	}
+	if from.After(to) {
+		fmt.Fprintf(stderr, "togo stats: --since is after --until\n")
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo stats: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo stats: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo stats: warning: %v\n", err)
+		}
+	}()
+	st, err := tasks.Stats(from, to)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo stats: %v\n", err)
+		return 1
+	}
+	fmt.Fprint(stdout, statsReport(st))
+	return 0
+}
+
+// statsReport renders statistics for the terminal: the totals, a bar
+// chart of completions per day, or per week for ranges longer than two
+// weeks, and the most completed tags.
+func statsReport(st service.Stats) string {
+	var b strings.Builder
+	days := len(st.PerDay)
+	fmt.Fprintf(&b, "%s to %s (%d %s)\n\n", st.From.Format(time.DateOnly), st.To.Format(time.DateOnly), days, plural(days, "day"))
+	fmt.Fprintf(&b, "Completed:      %d (%.1f a day)\n", st.Completed, float64(st.Completed)/float64(max(days, 1)))
+	fmt.Fprintf(&b, "Created:        %d\n", st.Created)
+	fmt.Fprintf(&b, "Open now:       %d\n", st.Open)
+	if st.Completed > 0 {
+		fmt.Fprintf(&b, "Average age:    %s at completion\n", formatAge(st.AverageAge))
+		fmt.Fprintf(&b, "Deferral rate:  %.0f%% (%d of %d deferred, %d %s)\n",
+			100*st.DeferralRate(), st.Deferred, st.Completed, st.Deferrals, plural(st.Deferrals, "deferral"))
+	}
+
+	counts, label, heading := st.PerDay, "Mon 01-02", "Completed per day:"
+	if days > 14 {
+		counts, label, heading = st.PerWeek, "week of 2006-01-02", "Completed per week:"
+	}
+	most := 0
+	for _, c := range counts {
+		most = max(most, c.Count)
+	}
+	fmt.Fprintf(&b, "\n%s\n", heading)
+	for _, c := range counts {
+		bar := 0
+		if most > 0 {
+			bar = (c.Count*statsBarWidth + most - 1) / most
+		}
+		fmt.Fprintf(&b, "  %s  %s %d\n", c.Day.Format(label), strings.Repeat("#", bar), c.Count)
+	}
+
+	if len(st.Tags) > 0 {
+		fmt.Fprintf(&b, "\nCompleted by tag:\n")
+		for _, tc := range st.Tags[:min(len(st.Tags), statsTopTags)] {
+			fmt.Fprintf(&b, "  %-16s %d\n", tc.Tag, tc.Count)
+		}
+	}
+	return b.String()
+}
+
+// statsBarWidth is the length of the longest bar in the chart, and
+// statsTopTags how many tags the report lists.
+const (
+	statsBarWidth = 30
+	statsTopTags  = 10
+)
+
+// formatAge renders a duration in days and hours, or hours and minutes
+// when shorter than a day, e.g. "3d4h" or "5h20m".
+func formatAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	d = d.Round(time.Hour)
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
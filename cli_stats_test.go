@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunStats(t *testing.T) {
+	now := time.Date(2025, 11, 12, 20, 0, 0, 0, time.Local)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	day := func(d, h int) time.Time { return time.Date(2025, 11, d, h, 0, 0, 0, time.Local) }
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Review PR").WithTags("work").WithCreatedAt(day(10, 9)).
+			WithHistory(testutil.Deferred(day(10, 18)), testutil.Completed(day(12, 9))),
+		testutil.NewTask().WithTitle("Water the plants").WithTags("home").WithCreatedAt(day(11, 9)).
+			WithHistory(testutil.Completed(day(11, 21))),
+		testutil.NewTask().WithTitle("File taxes").WithCreatedAt(day(1, 9)),
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{
+			name: "last week",
+			args: []string{"stats"},
+			want: []string{
+				"2025-11-06 to 2025-11-12 (7 days)",
+				"Completed:      2 (0.3 a day)",
+				"Open now:       1",
+				"Average age:    1d6h at completion",
+				"Deferral rate:  50% (1 of 2 deferred, 1 deferral)",
+				"  Tue 11-11  ############################## 1",
+				"  Wed 11-12  ############################## 1",
+				"  home             1",
+			},
+		},
+		{
+			name: "since and until",
+			args: []string{"stats", "--since", "2025-11-01", "--until", "yesterday"},
+			want: []string{"2025-11-01 to 2025-11-11 (11 days)", "Completed:      1 (0.1 a day)", "Created:        3"},
+		},
+		{
+			name: "by week",
+			args: []string{"stats", "--range", "month"},
+			want: []string{"Completed per week:", "  week of 2025-11-10  ############################## 2"},
+		},
+		{name: "unknown range", args: []string{"stats", "--range", "decade"}, wantCode: 2},
+		{name: "backwards", args: []string{"stats", "--since", "tomorrow"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(stdout.String(), line+"\n") {
+					t.Errorf("stdout lacks %q:\n%s", line, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestStatsScreen(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Water the plants").WithHistory(testutil.Completed(time.Now())).Build())
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m = m.refresh()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "s", want: "Statistics for the last week"},
+		{key: "y", want: "Statistics for the last year"},
+		{key: "a", want: "Statistics for all time"},
+		{key: "esc", want: "s: stats, r: review, p: plan, #: tags, /: search. Press q to quit."},
+	}
+	for _, tt := range tests {
+		msg := keyMsg(tt.key)
+		if tt.key == "esc" {
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		nm, _ := m.Update(msg)
+		m = nm.(model)
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("view after %q lacks %q:\n%s", tt.key, tt.want, view)
+		}
+	}
+	if view := m.View(); strings.Contains(view, "Completed:") {
+		t.Errorf("list still shows statistics:\n%s", view)
+	}
+}
+
+func TestStatsScreen_ComputedOnUpdate(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Water the plants").Build())
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m = m.refresh()
+	nm, _ := m.Update(keyMsg("s"))
+	m = nm.(model)
+
+	// Rendering shows what was computed when the screen opened, until the
+	// journal is reloaded.
+	testutil.MustSeed(t, repo, testutil.NewTask().WithTitle("Call mum").Build())
+	if view := m.View(); !strings.Contains(view, "Open now:       1\n") {
+		t.Errorf("view recomputed the statistics:\n%s", view)
+	}
+	nm, _ = m.Update(journalChangedMsg{})
+	if view := nm.(model).View(); !strings.Contains(view, "Open now:       2\n") {
+		t.Errorf("view after a reload lacks the new task:\n%s", view)
+	}
+}
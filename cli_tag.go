@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+)
+
+// runTag implements "togo tag": list the journal's tags, and rename or
+// merge them on every task at once.
+func runTag(args []string, stdout, stderr io.Writer) int {
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	if !(sub == "list" && len(args) == 0 || sub == "rename" && len(args) == 2 || sub == "merge" && len(args) >= 2) {
+		fmt.Fprint(stderr, `Usage:
+  togo tag [list]                 show tags and how many tasks carry them
+  togo tag rename OLD NEW         rename OLD, and the tags nested below it
+  togo tag merge TAG... INTO      replace each TAG by INTO
+`)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo tag: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo tag: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo tag: warning: %v\n", err)
+		}
+	}()
+
+	if sub == "list" {
+		tags, err := tasks.Tags()
+		if err != nil {
+			fmt.Fprintf(stderr, "togo tag: %v\n", err)
+			return 1
+		}
+		for _, tc := range tags {
+			fmt.Fprintf(stdout, "%-16s %d\n", tc.Tag, tc.Count)
+		}
+		return 0
+	}
+	summary, err := retag(tasks, args[:len(args)-1], args[len(args)-1])
+	if err != nil {
+		fmt.Fprintf(stderr, "togo tag: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, summary)
+	return 0
+}
+
+// retag renames the one tag in from to to, or merges the tags in from
+// into to, and describes what it did in one line.
+func retag(tasks *service.TaskService, from []string, to string) (string, error) {
+	var changed []*taskmodel.Task
+	var err error
+	verb := "Renamed"
+	if len(from) == 1 {
+		changed, err = tasks.RenameTag(from[0], to)
+	} else {
+		verb = "Merged"
+		changed, err = tasks.MergeTags(from, to)
+	}
+	if err != nil {
+		return "", err
+	}
+	n := len(changed)
+	return fmt.Sprintf("%s %s to %s on %d %s.", verb, strings.Join(from, ", "), to, n, plural(n, "task")), nil
+}
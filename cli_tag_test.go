@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunTag(t *testing.T) {
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Review PR").WithTags("work", "urgent"),
+		testutil.NewTask().WithTitle("Deploy").WithTags("work/backend"),
+		testutil.NewTask().WithTitle("Buy milk").WithTags("errand"),
+		testutil.NewTask().WithTitle("Mow the lawn").WithTags("chores"),
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{name: "list", args: []string{"tag"}, want: "chores           1\nerrand           1\nurgent           1\nwork             1\nwork/backend     1\n"},
+		{name: "rename", args: []string{"tag", "rename", "work", "job"}, want: "Renamed work to job on 2 tasks.\n"},
+		{name: "merge", args: []string{"tag", "merge", "errand", "chores", "home"}, want: "Merged errand, chores to home on 2 tasks.\n"},
+		{name: "list after", args: []string{"tag", "list"}, want: "home             2\njob              1\njob/backend      1\nurgent           1\n"},
+		{name: "unknown tag", args: []string{"tag", "rename", "play", "fun"}, wantCode: 1},
+		{name: "missing name", args: []string{"tag", "rename", "job"}, wantCode: 2},
+		{name: "unknown subcommand", args: []string{"tag", "drop", "job"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d (stderr: %s)", code, tt.wantCode, stderr.String())
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTagScreen(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(
+		testutil.NewTask().WithTitle("Review PR").WithTags("work", "urgent"),
+		testutil.NewTask().WithTitle("Deploy").WithTags("work"),
+		testutil.NewTask().WithTitle("Buy milk").WithTags("errand"),
+	)...)
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m = m.refresh()
+
+	tests := []struct {
+		keys []tea.KeyMsg
+		want string
+	}{
+		{keys: []tea.KeyMsg{keyMsg("#")}, want: "> [ ] work (2)\n  [ ] errand (1)\n  [ ] urgent (1)\n"},
+		{keys: []tea.KeyMsg{keyMsg("r"), {Type: tea.KeyCtrlU}, keyMsg("job")}, want: "Rename work to: job"},
+		{keys: []tea.KeyMsg{{Type: tea.KeyEnter}}, want: "> [ ] job (2)\n  [ ] errand (1)\n  [ ] urgent (1)\n\nRenamed work to job on 2 tasks.\n"},
+		{keys: []tea.KeyMsg{{Type: tea.KeySpace}, keyMsg("j"), {Type: tea.KeySpace}, keyMsg("m")}, want: "Merge job, errand to: errand"},
+		{keys: []tea.KeyMsg{{Type: tea.KeyEsc}}, want: "  [x] job (2)\n> [x] errand (1)\n"},
+		{keys: []tea.KeyMsg{keyMsg("m"), {Type: tea.KeyCtrlU}, keyMsg("deep work"), {Type: tea.KeyEnter}}, want: "validation failed for tag: contains spaces"},
+		{keys: []tea.KeyMsg{{Type: tea.KeyCtrlU}, keyMsg("todo"), {Type: tea.KeyEnter}}, want: "> [ ] todo (3)\n  [ ] urgent (1)\n\nMerged job, errand to todo on 3 tasks.\n"},
+		{keys: []tea.KeyMsg{{Type: tea.KeyEsc}}, want: "#: tags, /: search. Press q to quit."},
+	}
+	for _, tt := range tests {
+		for _, key := range tt.keys {
+			nm, _ := m.Update(key)
+			m = nm.(model)
+		}
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("view after %v lacks %q:\n%s", tt.keys, tt.want, view)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestMain keeps tests away from the user's real configuration and journals,
+// and disables interactive first-run setup.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "togo-test")
+	if err != nil {
+		panic(err)
+	}
+	configPath = func() (string, error) { return filepath.Join(dir, "config"), nil }
+	isInteractive = func() bool { return false }
+	whatsNewPath = func() (string, error) { return filepath.Join(dir, "last_version"), nil }
+	for _, v := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		os.Setenv(v, filepath.Join(dir, v))
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// stubLaunch replaces launchTUI for the duration of a test and records the
+// model it was called with.
+func stubLaunch(t *testing.T, err error) *model {
+	t.Helper()
+	var launched model
+	orig := launchTUI
+	launchTUI = func(m model) error {
+		launched = m
+		return err
+	}
+	t.Cleanup(func() { launchTUI = orig })
+	return &launched
+}
+
+func TestRun_NoArgsLaunchesDefaultUI(t *testing.T) {
+	launched := stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if launched.opts.view != viewList || launched.opts.query != "" {
+		t.Fatalf("expected default options, got %+v", launched.opts)
+	}
+}
+
+func TestRun_UIWithQueryAndView(t *testing.T) {
+	launched := stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"ui", "--query", "tag:work due<friday", "--view", "board"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if launched.opts.query != "tag:work due<friday" {
+		t.Errorf("expected query to be passed through, got %q", launched.opts.query)
+	}
+	if len(launched.filter.Tags) != 1 || launched.filter.Tags[0] != "work" || launched.filter.DueBefore == nil {
+		t.Errorf("expected query to be parsed into the filter, got %+v", launched.filter)
+	}
+	if launched.opts.view != viewBoard {
+		t.Errorf("expected board view, got %q", launched.opts.view)
+	}
+	if !strings.Contains(launched.View(), "Filter: tag:work due<friday") {
+		t.Errorf("expected view to show the active filter; got:\n%s", launched.View())
+	}
+}
+
+func TestRun_UIWarnsAboutContradictoryQuery(t *testing.T) {
+	stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"ui", "--query", "+work -work"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: query can never match") {
+		t.Errorf("expected a warning, got %q", stderr.String())
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	stubLaunch(t, nil)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{name: "unknown command", args: []string{"frobnicate"}, wantStderr: `unknown command "frobnicate"`},
+		{name: "unknown view", args: []string{"ui", "--view", "calendar"}, wantStderr: `unknown view "calendar"`},
+		{name: "unknown flag", args: []string{"ui", "--colour"}, wantStderr: "flag provided but not defined"},
+		{name: "stray argument", args: []string{"ui", "extra"}, wantStderr: "unexpected arguments: extra"},
+		{name: "malformed query", args: []string{"ui", "--query", "status:later"}, wantStderr: "status must be pool, today or done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != 2 {
+				t.Fatalf("expected exit code 2, got %d", code)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got %q", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_LaunchErrorExitsNonZero(t *testing.T) {
+	stubLaunch(t, errors.New("no tty"))
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"ui"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "no tty") {
+		t.Errorf("expected error on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"help"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "Usage: togo") {
+		t.Errorf("expected usage on stdout, got %q", stdout.String())
+	}
+}
+
+func TestRun_UIDemo(t *testing.T) {
+	launched := stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"ui", "--demo", "--query", "+work"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if launched.tasks == nil {
+		t.Fatal("expected a demo repository")
+	}
+	if len(launched.list) != 2 || !slices.Contains(titles(*launched), "Plan team offsite") {
+		t.Errorf("expected the demo work tasks, got %q", titles(*launched))
+	}
+	if !strings.Contains(launched.View(), "Demo mode") {
+		t.Errorf("expected the view to flag demo mode; got:\n%s", launched.View())
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"togo/internal/service"
+)
+
+// runUndo implements "togo undo": revert the latest change made to the
+// journal, from the TUI or another command, or make the latest undone
+// change again.
+//
+//	togo undo
+//	togo undo --redo
+func runUndo(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	redo := fs.Bool("redo", false, "make the latest undone change again")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo undo: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo undo: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo undo: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo undo: warning: %v\n", err)
+		}
+	}()
+
+	undo, verb := tasks.Undo, "Undid"
+	if *redo {
+		undo, verb = tasks.Redo, "Redid"
+	}
+	step, err := undo()
+	switch {
+	case errors.Is(err, service.ErrNothingToUndo):
+		fmt.Fprintln(stdout, "Nothing to undo.")
+		return 0
+	case errors.Is(err, service.ErrNothingToRedo):
+		fmt.Fprintln(stdout, "Nothing to redo.")
+		return 0
+	case err != nil:
+		fmt.Fprintf(stderr, "togo undo: %s: %v\n", step, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: %s.\n", verb, step)
+	return 0
+}
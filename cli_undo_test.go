@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"togo/internal/config"
+	"togo/internal/hooks"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunUndo(t *testing.T) {
+	seedJournal(t, testutil.NewTask().WithTitle("Water the plants").WithStatus(taskmodel.StatusToday))
+	session, err := openSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := session.open(journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed, err := tasks.ListTasks(taskmodel.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := listed[0].ID
+	if _, err := tasks.CompleteTask(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	status := func() taskmodel.TaskStatus {
+		t.Helper()
+		repo, err := openRepository(config.Default(), journals.Default)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeRepository(repo)
+		task, err := repo.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Status
+	}
+	tests := []struct {
+		args       []string
+		want       string
+		wantStatus taskmodel.TaskStatus
+	}{
+		{args: []string{"undo"}, want: "Undid: complete \"Water the plants\".\n", wantStatus: taskmodel.StatusToday},
+		{args: []string{"undo"}, want: "Nothing to undo.\n", wantStatus: taskmodel.StatusToday},
+		{args: []string{"undo", "--redo"}, want: "Redid: complete \"Water the plants\".\n", wantStatus: taskmodel.StatusDone},
+		{args: []string{"undo", "--redo"}, want: "Nothing to redo.\n", wantStatus: taskmodel.StatusDone},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d (stderr: %s)", tt.args, code, stderr.String())
+		}
+		if stdout.String() != tt.want {
+			t.Errorf("%v: stdout = %q, want %q", tt.args, stdout.String(), tt.want)
+		}
+		if got := status(); got != tt.wantStatus {
+			t.Errorf("%v: status = %q, want %q", tt.args, got, tt.wantStatus)
+		}
+	}
+}
+
+func TestRunUndo_Stale(t *testing.T) {
+	seedJournal(t, testutil.NewTask().WithTitle("Water the plants"))
+	repo, err := openRepository(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := journalHistory(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks := service.New(repo, nil)
+	tasks.SetHistory(history)
+	listed, err := tasks.ListTasks(taskmodel.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tasks.MoveToToday(listed[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.New(repo, nil).CompleteTask(listed[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	closeRepository(repo)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"undo"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1 (stdout: %s)", code, stdout.String())
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("changed since")) {
+		t.Errorf("stderr = %q, want it to say the task changed since", stderr.String())
+	}
+}
+
+func TestRunUndo_RunsHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	seedJournal(t, testutil.NewTask().WithTitle("Water the plants"))
+	tasks, closeService, err := openService(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tasks.AddTask("Repot the cactus", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeService(); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := hooks.DefaultDir(conf)
+	out := filepath.Join(t.TempDir(), "deleted.json")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "on-delete"), []byte("#!/bin/sh\ncat > "+out+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"undo"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("on-delete hook did not run: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"title":"Repot the cactus"`)) {
+		t.Errorf("on-delete hook got %s, want the undone task", data)
+	}
+}
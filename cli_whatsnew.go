@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"togo/internal/whatsnew"
+)
+
+// version is the running release. Builds may set it with
+// -ldflags "-X main.version=…"; otherwise the newest changelog entry is used.
+var version string
+
+// whatsNewPath locates the file recording the last version the user ran. It
+// is a variable so tests can point it at a temporary directory.
+var whatsNewPath = whatsnew.DefaultStatePath
+
+// currentVersion returns version, defaulting to the embedded changelog.
+func currentVersion() string {
+	if version != "" {
+		return version
+	}
+	return whatsnew.Current()
+}
+
+// pendingWhatsNew returns the what's-new screen for releases since the
+// version last run, or "" when there is nothing to announce, and records the
+// current version as seen. Problems with the state file are reported on
+// stderr but never prevent launching.
+func pendingWhatsNew(stderr io.Writer) string {
+	path, err := whatsNewPath()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo: locating state directory: %v\n", err)
+		return ""
+	}
+	lastSeen, err := whatsnew.LastSeen(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo: reading %s: %v\n", path, err)
+		return ""
+	}
+
+	current := currentVersion()
+	if lastSeen == current {
+		return ""
+	}
+	if err := whatsnew.MarkSeen(path, current); err != nil {
+		fmt.Fprintf(stderr, "togo: writing %s: %v\n", path, err)
+	}
+
+	entries := whatsnew.Since(whatsnew.Changelog(), lastSeen, current)
+	if len(entries) == 0 {
+		return ""
+	}
+	return whatsnew.Render(entries)
+}
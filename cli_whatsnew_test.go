@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"togo/internal/whatsnew"
+)
+
+// withWhatsNewPath points whatsNewPath at a fresh temporary file for one test.
+func withWhatsNewPath(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "togo", "last_version")
+	orig := whatsNewPath
+	whatsNewPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { whatsNewPath = orig })
+	return path
+}
+
+func TestPendingWhatsNew_FreshInstallShowsNothing(t *testing.T) {
+	path := withWhatsNewPath(t)
+	var stderr bytes.Buffer
+
+	if got := pendingWhatsNew(&stderr); got != "" {
+		t.Fatalf("expected no screen on fresh install, got:\n%s", got)
+	}
+	if seen, _ := whatsnew.LastSeen(path); seen != currentVersion() {
+		t.Fatalf("expected %s recorded as seen, got %q", currentVersion(), seen)
+	}
+}
+
+func TestPendingWhatsNew_AfterUpgradeShowsOnce(t *testing.T) {
+	path := withWhatsNewPath(t)
+	if err := whatsnew.MarkSeen(path, "0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+
+	got := pendingWhatsNew(&stderr)
+	if !strings.Contains(got, "What's new") || !strings.Contains(got, currentVersion()) {
+		t.Fatalf("expected what's-new screen for %s, got:\n%s", currentVersion(), got)
+	}
+	if again := pendingWhatsNew(&stderr); again != "" {
+		t.Fatalf("expected screen only once, got:\n%s", again)
+	}
+}
+
+func TestRun_UIShowsWhatsNewAfterUpgrade(t *testing.T) {
+	path := withWhatsNewPath(t)
+	if err := whatsnew.MarkSeen(path, "0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	launched := stubLaunch(t, nil)
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"ui"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if launched.whatsNew == "" {
+		t.Fatal("expected launched model to carry the what's-new screen")
+	}
+}
+
+func TestWhatsNewScreen_AnyKeyDismisses(t *testing.T) {
+	m := initializeModel()
+	m.whatsNew = "What's new in togo\n"
+
+	if view := m.View(); !strings.Contains(view, "Press any key") {
+		t.Fatalf("expected what's-new screen, got:\n%s", view)
+	}
+
+	nm, cmd := m.Update(keyMsg("q"))
+	got := nm.(model)
+	if cmd != nil {
+		t.Fatal("expected the dismissing key not to quit")
+	}
+	if got.whatsNew != "" || strings.Contains(got.View(), "What's new") {
+		t.Fatalf("expected screen dismissed, got:\n%s", got.View())
+	}
+}
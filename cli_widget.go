@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+)
+
+// runWidget implements "togo widget": print a short summary of today's
+// list for a status bar or desktop widget, followed by any review
+// reminders, one per line. Everything is worked out from the journal; no
+// network is involved.
+func runWidget(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("widget", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "togo widget: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	path, err := journalPath(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	lastReview, err := service.LastReviewed(service.ReviewedPath(path))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	tasks, closeService, err := openService(cfg, activeJournal(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := closeService(); err != nil {
+			fmt.Fprintf(stderr, "togo widget: warning: %v\n", err)
+		}
+	}()
+
+	now := taskmodel.Now()
+	status := taskmodel.StatusToday
+	today, err := tasks.ListTasks(taskmodel.TaskFilter{Status: &status})
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	nudges, err := tasks.ReviewNudges(cfg.NudgePolicy(), lastReview, now)
+	if err != nil {
+		fmt.Fprintf(stderr, "togo widget: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Today: %s\n", taskmodel.ComputeQuickStats(today, now).Footer())
+	for _, n := range nudges {
+		fmt.Fprintln(stdout, n.Message)
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	"togo/internal/journals"
+	taskmodel "togo/internal/model"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+func TestRunWidget(t *testing.T) {
+	now := time.Date(2024, 6, 10, 10, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Renew passport").WithEstimate(30*time.Minute).WithCreatedAt(now.AddDate(0, 0, -9)),
+	)
+	path, err := journalPath(config.Default(), journals.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		reviewed   time.Time
+		wantStdout string
+	}{
+		{
+			name: "never reviewed",
+			wantStdout: "Today: 0 tasks\n" +
+				"You haven't done a review yet — try togo review\n" +
+				"Your today list is empty — pick something from the pool\n",
+		},
+		{
+			name:     "reviewed yesterday",
+			reviewed: now.AddDate(0, 0, -1),
+			wantStdout: "Today: 0 tasks\n" +
+				"Your today list is empty — pick something from the pool\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.reviewed.IsZero() {
+				if err := service.MarkReviewed(service.ReviewedPath(path), tt.reviewed); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var stdout, stderr bytes.Buffer
+			if code := run([]string{"widget"}, &stdout, &stderr); code != 0 {
+				t.Fatalf("exit code %d (stderr: %s)", code, stderr.String())
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	taskmodel "togo/internal/model"
+)
+
+// runXRef implements "togo xref": find the tasks imported from a record
+// of another tool by the record's ID there, qualified by the tool's name
+// to search only its records.
+//
+//	togo xref PROJ-12
+//	togo xref jira:PROJ-12
+func runXRef(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("xref", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: togo xref [SYSTEM:]ID")
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "togo xref: %v\n", err)
+		return 1
+	}
+	tasks, err := listJournal(taskmodel.TaskFilter{})
+	if err != nil {
+		fmt.Fprintf(stderr, "togo xref: %v\n", err)
+		return 1
+	}
+	matches := taskmodel.BuildXRefTable(tasks).Lookup(fs.Arg(0))
+	if len(matches) == 0 {
+		fmt.Fprintf(stderr, "togo xref: no task was imported from %q\n", fs.Arg(0))
+		return 1
+	}
+	byID := make(map[taskmodel.TaskID]*taskmodel.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	links := outputLinker(cfg, stdout)
+	for _, e := range matches {
+		t := byID[e.TaskID]
+		fmt.Fprintf(stdout, "%s  %s %s  %s\n", links.Ref(e.Ref), cfg.IDDisplay.Format(t), links.Linkify(t.Title), t.Status)
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"togo/internal/config"
+	taskmodel "togo/internal/model"
+	"togo/internal/testutil"
+)
+
+func TestRunXRef(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 9, 0, 0, 0, time.UTC) }
+	jira := taskmodel.ExternalRef{System: "jira", ID: "PROJ-12"}
+	trello := taskmodel.ExternalRef{System: "trello", ID: "PROJ-12"}
+	seedJournal(t,
+		testutil.NewTask().WithTitle("Fix login").WithID(taskmodel.TaskIDFromExternal(jira)).WithExternalRef(jira).WithCreatedAt(day(1)),
+		testutil.NewTask().WithTitle("Plan offsite").WithID(taskmodel.TaskIDFromExternal(trello)).WithExternalRef(trello).WithCreatedAt(day(2)),
+		testutil.NewTask().WithTitle("Water plants").WithCreatedAt(day(3)),
+	)
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.RefURLs = "trello=https://trello.com/c/%s"
+	if err := config.Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{args: []string{"xref", "JIRA:PROJ-12"}, wantStdout: "jira:PROJ-12  " + taskmodel.TaskIDFromExternal(jira).Short() + " Fix login  pool\n"},
+		{args: []string{"xref", "PROJ-12"}, wantStdout: "jira:PROJ-12  " + taskmodel.TaskIDFromExternal(jira).Short() + " Fix login  pool\n" +
+			"trello:PROJ-12 <https://trello.com/c/PROJ-12>  " + taskmodel.TaskIDFromExternal(trello).Short() + " Plan offsite  pool\n"},
+		{args: []string{"xref", "proj-12"}, wantCode: 1},
+		{args: []string{"xref"}, wantCode: 2},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+			t.Fatalf("%v: exit code %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, stderr.String())
+		}
+		if stdout.String() != tt.wantStdout {
+			t.Errorf("%v: stdout = %q, want %q", tt.args, stdout.String(), tt.wantStdout)
+		}
+	}
+}
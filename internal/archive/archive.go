@@ -0,0 +1,216 @@
+// Package archive keeps completed tasks that are no longer needed day to
+// day out of the journal, in one file per year of completion beside it.
+// The journal, which is read in full on every start, then stays small no
+// matter how much history accumulates, while the archive can still be
+// searched when asked to.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// DirName is the archive directory's name beside the journal file.
+const DirName = "archive"
+
+// ext is the extension of yearly archive files, which are JSON journals.
+const ext = ".json"
+
+// Dir returns the archive directory of the journal stored at journal.
+func Dir(journal string) string {
+	return filepath.Join(filepath.Dir(journal), DirName)
+}
+
+// Opener opens the yearly archive file at path. It lets the caller store
+// archives the way it stores the journal, such as encrypted.
+type Opener func(path string) (repository.TaskRepository, error)
+
+// Archive is a directory of yearly archive files.
+type Archive struct {
+	dir  string
+	open Opener
+}
+
+// New returns the archive kept in dir, whose files are opened with open.
+// Nothing is read or created until used.
+func New(dir string, open Opener) *Archive {
+	return &Archive{dir: dir, open: open}
+}
+
+// Years returns the years the archive holds tasks for, oldest first.
+func (a *Archive) Years() ([]int, error) {
+	entries, err := os.ReadDir(a.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var years []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ext)
+		if !ok || len(name) != 4 || e.IsDir() {
+			continue
+		}
+		if year, err := strconv.Atoi(name); err == nil {
+			years = append(years, year)
+		}
+	}
+	slices.Sort(years)
+	return years, nil
+}
+
+// Compact moves the done tasks of repo finished before cutoff into the
+// archive file of the year they were finished, and returns how many were
+// moved. A task is copied to the archive before it is deleted from repo,
+// so an interruption can leave a task in both places but never in
+// neither; compacting again then completes the move.
+func (a *Archive) Compact(repo repository.TaskRepository, cutoff time.Time) (int, error) {
+	done := model.StatusDone
+	tasks, err := repo.List(model.TaskFilter{Status: &done})
+	if err != nil {
+		return 0, err
+	}
+	var old []*model.Task
+	for _, t := range tasks {
+		if Finished(t).Before(cutoff) {
+			old = append(old, t)
+		}
+	}
+	return a.move(repo, old)
+}
+
+// Move moves the tasks of repo with the given IDs into the archive, done
+// or not, filed under the year they were finished or last changed. Like
+// Compact, it copies before it deletes.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound, moving
+// nothing, when an ID is not in repo.
+func (a *Archive) Move(repo repository.TaskRepository, ids []model.TaskID) error {
+	tasks := make([]*model.Task, len(ids))
+	for i, id := range ids {
+		t, err := repo.Get(id)
+		if err != nil {
+			return err
+		}
+		tasks[i] = t
+	}
+	_, err := a.move(repo, tasks)
+	return err
+}
+
+// move copies tasks into the archive, year by year, deleting each year's
+// from repo once they are stored, and returns how many it moved.
+func (a *Archive) move(repo repository.TaskRepository, tasks []*model.Task) (int, error) {
+	byYear := map[int][]*model.Task{}
+	for _, t := range tasks {
+		year := Finished(t).Year()
+		byYear[year] = append(byYear[year], t)
+	}
+
+	moved := 0
+	for _, year := range slices.Sorted(maps.Keys(byYear)) {
+		if err := a.store(year, byYear[year]); err != nil {
+			return moved, err
+		}
+		ids := make([]model.TaskID, len(byYear[year]))
+		for i, t := range byYear[year] {
+			ids[i] = t.ID
+		}
+		if err := repo.DeleteMany(ids); err != nil {
+			return moved, err
+		}
+		moved += len(ids)
+	}
+	return moved, nil
+}
+
+// List returns the archived tasks matching filter, oldest first, with
+// paging applied across all years.
+func (a *Archive) List(filter model.TaskFilter) ([]*model.Task, error) {
+	years, err := a.Years()
+	if err != nil {
+		return nil, err
+	}
+	unpaged := filter
+	unpaged.Limit, unpaged.Offset = 0, 0
+	var all []*model.Task
+	for _, year := range years {
+		err := a.with(year, func(repo repository.TaskRepository) error {
+			tasks, err := repository.Scan(repo, unpaged)
+			all = append(all, tasks...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return repository.Query(all, filter), nil
+}
+
+// store saves tasks into the archive file of year. A copy an interrupted
+// compaction left there is replaced whatever its version, as the
+// journal's task is the one being moved.
+func (a *Archive) store(year int, tasks []*model.Task) error {
+	if err := os.MkdirAll(a.dir, 0o700); err != nil {
+		return err
+	}
+	return a.with(year, func(repo repository.TaskRepository) error {
+		copies := make([]*model.Task, len(tasks))
+		for i, t := range tasks {
+			copies[i] = t.Clone()
+			stored, err := repo.Get(t.ID)
+			switch {
+			case err == nil:
+				copies[i].Version = stored.Version
+			case !errors.Is(err, model.ErrTaskNotFound):
+				return err
+			}
+		}
+		return repo.SaveAll(copies)
+	})
+}
+
+// with runs fn on the opened archive file of year, closing it afterwards.
+func (a *Archive) with(year int, fn func(repository.TaskRepository) error) error {
+	path := filepath.Join(a.dir, fmt.Sprintf("%04d%s", year, ext))
+	repo, err := a.open(path)
+	if err != nil {
+		return err
+	}
+	err = fn(repo)
+	if c, ok := repo.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("archive %d: %w", year, err)
+	}
+	return nil
+}
+
+// Finished returns when t was finished: its completion time, or for tasks
+// completed before that was recorded, when it was last changed.
+func Finished(t *model.Task) time.Time {
+	switch {
+	case t.CompletedAt != nil:
+		return *t.CompletedAt
+	case !t.UpdatedAt.IsZero():
+		return t.UpdatedAt
+	default:
+		return t.CreatedAt
+	}
+}
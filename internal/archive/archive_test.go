@@ -0,0 +1,176 @@
+package archive
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/jsonstore"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+func openJSON(path string) (repository.TaskRepository, error) {
+	return jsonstore.New(path), nil
+}
+
+// done returns a task titled title that was completed at.
+func done(title string, at time.Time) *model.Task {
+	t := testutil.NewTask().WithTitle(title).WithStatus(model.StatusDone).Build()
+	t.CompletedAt = &at
+	return t
+}
+
+func titles(tasks []*model.Task) []string {
+	var out []string
+	for _, t := range tasks {
+		out = append(out, t.Title)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// TestArchive_Compact verifies only done tasks finished before the cutoff
+// move, into the file of the year they were finished, and stay searchable.
+func TestArchive_Compact(t *testing.T) {
+	cutoff := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := memstore.New()
+	testutil.MustSeed(t, repo,
+		done("File taxes", time.Date(2023, 4, 15, 9, 0, 0, 0, time.UTC)),
+		done("Book flights", time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)),
+		done("Call plumber", time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)),
+		testutil.NewTask().WithTitle("Renew passport").WithCreatedAt(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)).Build(),
+	)
+	a := New(filepath.Join(t.TempDir(), DirName), openJSON)
+
+	n, err := a.Compact(repo, cutoff)
+	if err != nil || n != 2 {
+		t.Fatalf("Compact() = %d, %v; want 2 moved", n, err)
+	}
+	left, err := repo.List(model.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(left), []string{"Call plumber", "Renew passport"}; !slices.Equal(got, want) {
+		t.Errorf("journal holds %q, want %q", got, want)
+	}
+	years, err := a.Years()
+	if err != nil || !slices.Equal(years, []int{2023, 2024}) {
+		t.Errorf("Years() = %v, %v; want [2023 2024]", years, err)
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []string
+	}{
+		{name: "all", want: []string{"Book flights", "File taxes"}},
+		{name: "text", filter: model.TaskFilter{Text: "taxes"}, want: []string{"File taxes"}},
+		{name: "paged across years", filter: model.TaskFilter{Offset: 1}, want: []string{"Book flights"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.List(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(titles(got), tt.want) {
+				t.Errorf("List() = %q, want %q", titles(got), tt.want)
+			}
+		})
+	}
+
+	if n, err := a.Compact(repo, cutoff); err != nil || n != 0 {
+		t.Errorf("second Compact() = %d, %v; want nothing to move", n, err)
+	}
+}
+
+// TestArchive_CompactResumes verifies compacting again finishes a move
+// that was interrupted after the tasks reached the archive.
+func TestArchive_CompactResumes(t *testing.T) {
+	cutoff := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, done("File taxes", time.Date(2023, 4, 15, 9, 0, 0, 0, time.UTC)))
+	a := New(filepath.Join(t.TempDir(), DirName), openJSON)
+	tasks, err := repo.List(model.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.store(2023, tasks); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := a.Compact(repo, cutoff); err != nil || n != 1 {
+		t.Fatalf("Compact() = %d, %v; want 1 moved", n, err)
+	}
+	archived, err := a.List(model.TaskFilter{})
+	if err != nil || len(archived) != 1 {
+		t.Errorf("archive holds %d tasks, %v; want 1", len(archived), err)
+	}
+}
+
+// TestArchive_Move verifies open tasks move into the file of the year
+// they were last changed, and an unknown ID moves nothing.
+func TestArchive_Move(t *testing.T) {
+	repo := memstore.New()
+	open := testutil.NewTask().WithTitle("Learn the cello").WithCreatedAt(time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)).Build()
+	kept := testutil.NewTask().WithTitle("Renew passport").Build()
+	testutil.MustSeed(t, repo, open, kept)
+	a := New(filepath.Join(t.TempDir(), DirName), openJSON)
+
+	if err := a.Move(repo, []model.TaskID{open.ID, model.NewTaskID()}); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Fatalf("Move() with an unknown ID error = %v, want ErrTaskNotFound", err)
+	}
+	if n, _ := repo.Count(model.TaskFilter{}); n != 2 {
+		t.Fatalf("failed Move() left %d tasks, want 2", n)
+	}
+
+	if err := a.Move(repo, []model.TaskID{open.ID}); err != nil {
+		t.Fatalf("Move() error: %v", err)
+	}
+	left, _ := repo.List(model.TaskFilter{})
+	if got := titles(left); !slices.Equal(got, []string{"Renew passport"}) {
+		t.Errorf("journal holds %q, want only the kept task", got)
+	}
+	if years, _ := a.Years(); !slices.Equal(years, []int{2023}) {
+		t.Errorf("Years() = %v, want [2023]", years)
+	}
+}
+
+// TestArchive_Empty verifies a missing archive directory lists nothing.
+func TestArchive_Empty(t *testing.T) {
+	a := New(filepath.Join(t.TempDir(), DirName), openJSON)
+	if years, err := a.Years(); err != nil || len(years) != 0 {
+		t.Errorf("Years() = %v, %v; want none", years, err)
+	}
+	if tasks, err := a.List(model.TaskFilter{}); err != nil || len(tasks) != 0 {
+		t.Errorf("List() = %v, %v; want none", tasks, err)
+	}
+}
+
+// TestFinished verifies the fallbacks for tasks without a completion time.
+func TestFinished(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(48 * time.Hour)
+	completed := created.Add(24 * time.Hour)
+	tests := []struct {
+		name string
+		task model.Task
+		want time.Time
+	}{
+		{name: "completed", task: model.Task{CreatedAt: created, UpdatedAt: updated, CompletedAt: &completed}, want: completed},
+		{name: "updated", task: model.Task{CreatedAt: created, UpdatedAt: updated}, want: updated},
+		{name: "created", task: model.Task{CreatedAt: created}, want: created},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Finished(&tt.task); !got.Equal(tt.want) {
+				t.Errorf("Finished() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
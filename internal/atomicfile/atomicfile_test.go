@@ -0,0 +1,49 @@
+package atomicfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWrite verifies a file is replaced only when its writer succeeds, and
+// that no temporary file is left behind either way.
+func TestWrite(t *testing.T) {
+	failed := errors.New("disk full")
+	tests := []struct {
+		name    string
+		write   func(w io.Writer) error
+		want    string
+		wantErr error
+	}{
+		{name: "replaces", write: func(w io.Writer) error {
+			_, err := io.WriteString(w, "new\n")
+			return err
+		}, want: "new\n"},
+		{name: "keeps old on failure", write: func(w io.Writer) error {
+			io.WriteString(w, "partial")
+			return failed
+		}, want: "old\n", wantErr: failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "nested")
+			path := filepath.Join(dir, "tasks.json")
+			if err := WriteFile(path, []byte("old\n")); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Write(path, tt.write); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() error = %v, want %v", err, tt.wantErr)
+			}
+			if data, _ := os.ReadFile(path); string(data) != tt.want {
+				t.Errorf("file = %q, want %q", data, tt.want)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("directory holds %d files, want 1", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package audit keeps an append-only log of the changes made to a
+// journal: which task was created, changed or deleted, when, from where
+// and how, so "togo audit" can answer what was changed and when. Unlike
+// the undo history, nothing is ever dropped from it.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"togo/internal/encryption"
+	"togo/internal/model"
+)
+
+// Ext is appended to the journal's path to name its audit log.
+const Ext = ".audit"
+
+// Path returns the path of the audit log of the journal at path.
+func Path(journal string) string {
+	return journal + Ext
+}
+
+// Sources of changes, recorded with each entry.
+const (
+	SourceTUI = "tui"
+	SourceCLI = "cli"
+	SourceAPI = "api"
+)
+
+// Op says what a change did to a task.
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Entry is one change to one task.
+type Entry struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"`
+
+	// Action names the use case, such as "complete" or "undo complete".
+	Action string `json:"action"`
+	Op     Op     `json:"op"`
+
+	TaskID model.TaskID `json:"task_id"`
+	Title  string       `json:"title"`
+
+	// Changes describes each field that changed, such as
+	// `status: pool → today`; for a created or deleted task, each field it
+	// had set.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// NewEntry describes the change from before to after, either nil for a
+// created or deleted task, that action made from source at at.
+func NewEntry(at time.Time, source, action string, before, after *model.Task) Entry {
+	e := Entry{At: at, Source: source, Action: action, Op: OpUpdate}
+	switch {
+	case before == nil:
+		e.Op, before = OpCreate, &model.Task{}
+		e.TaskID, e.Title = after.ID, after.Title
+	case after == nil:
+		e.Op, after = OpDelete, &model.Task{}
+		e.TaskID, e.Title = before.ID, before.Title
+	default:
+		e.TaskID, e.Title = after.ID, after.Title
+	}
+	for _, c := range before.Diff(after) {
+		e.Changes = append(e.Changes, c.String())
+	}
+	return e
+}
+
+// Log is the audit log kept in one file. It is safe for concurrent use;
+// each append is a single write, so processes sharing the file do not
+// interleave their entries.
+type Log struct {
+	path string
+	// keyring encrypts each entry as the journal is, when set, since
+	// entries hold task titles and contents.
+	keyring *encryption.Keyring
+
+	mu sync.Mutex
+}
+
+// Open returns the audit log in the file at path, encrypted with keyring
+// if it is not nil. A missing file holds no entries.
+func Open(path string, keyring *encryption.Keyring) *Log {
+	return &Log{path: path, keyring: keyring}
+}
+
+// Append adds entries to the end of the log, one line each, and syncs it.
+// With a keyring, each line is the entry encrypted and base64-encoded.
+func (l *Log) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if l.keyring != nil {
+			sealed, err := l.keyring.Encrypt(line)
+			if err != nil {
+				return err
+			}
+			line = []byte(base64.StdEncoding.EncodeToString(sealed))
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries made at since or later, oldest first. A last
+// line without a line break was cut short by a crash, and is ignored.
+func (l *Log) Read(since time.Time) ([]Entry, error) {
+	l.mu.Lock()
+	data, err := os.ReadFile(l.path)
+	l.mu.Unlock()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i+1 < len(data) {
+		data = data[:i+1]
+	}
+
+	var entries []Entry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] != '{' {
+			if l.keyring == nil {
+				return nil, fmt.Errorf("%s: log is encrypted but no key is configured", l.path)
+			}
+			sealed, err := base64.StdEncoding.DecodeString(string(line))
+			if err != nil {
+				return nil, fmt.Errorf("%s: line %d: %w", l.path, n, err)
+			}
+			if line, err = l.keyring.Decrypt(sealed); err != nil {
+				return nil, fmt.Errorf("%s: line %d: %w", l.path, n, err)
+			}
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", l.path, n, err)
+		}
+		if !e.At.Before(since) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/encryption"
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestNewEntry verifies entries describe created, changed and deleted
+// tasks by the fields that differ.
+func TestNewEntry(t *testing.T) {
+	at := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	before := testutil.NewTask().WithTitle("Water the plants").Build()
+	after := before.Clone()
+	after.Status = model.StatusToday
+
+	tests := []struct {
+		name          string
+		before, after *model.Task
+		wantOp        Op
+		wantChanges   []string
+	}{
+		{name: "update", before: before, after: after, wantOp: OpUpdate, wantChanges: []string{"status: pool → today"}},
+		{name: "create", after: before, wantOp: OpCreate, wantChanges: []string{`title: "" → "Water the plants"`, `status:  → pool`}},
+		{name: "delete", before: after, wantOp: OpDelete, wantChanges: []string{`title: "Water the plants" → ""`, `status: today → `}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEntry(at, SourceCLI, "move to today", tt.before, tt.after)
+			if e.Op != tt.wantOp || e.TaskID != before.ID || e.Title != "Water the plants" || !e.At.Equal(at) || e.Source != SourceCLI {
+				t.Errorf("NewEntry() = %+v", e)
+			}
+			if !slices.Equal(e.Changes, tt.wantChanges) {
+				t.Errorf("changes = %q, want %q", e.Changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+// TestLog_AppendRead verifies entries round-trip in order, filtered by
+// time, plain and encrypted, and that a line cut short is ignored.
+func TestLog_AppendRead(t *testing.T) {
+	keyring, err := encryption.Passphrase("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 9, 0, 0, 0, time.UTC) }
+	task := testutil.NewTask().WithTitle("Water the plants").Build()
+
+	for _, keyring := range []*encryption.Keyring{nil, keyring} {
+		path := Path(filepath.Join(t.TempDir(), "tasks.json"))
+		log := Open(path, keyring)
+		if got, err := log.Read(time.Time{}); err != nil || got != nil {
+			t.Fatalf("Read() of a missing log = %v, %v", got, err)
+		}
+		if err := log.Append(NewEntry(day(1), SourceTUI, "add", nil, task)); err != nil {
+			t.Fatal(err)
+		}
+		if err := log.Append(NewEntry(day(2), SourceCLI, "delete", task, nil), NewEntry(day(3), SourceCLI, "undo delete", nil, task)); err != nil {
+			t.Fatal(err)
+		}
+		f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		f.WriteString(`{"at":"2025-11-04T09:00:00Z","act`)
+		f.Close()
+
+		got, err := Open(path, keyring).Read(day(2))
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		var actions []string
+		for _, e := range got {
+			actions = append(actions, e.Action)
+		}
+		if !slices.Equal(actions, []string{"delete", "undo delete"}) {
+			t.Errorf("Read() = %q, want the delete and its undo", actions)
+		}
+		data, _ := os.ReadFile(path)
+		if encrypted := !bytes.Contains(data, []byte("Water the plants")); encrypted != (keyring != nil) {
+			t.Errorf("log encrypted = %v, want %v", encrypted, keyring != nil)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package backup keeps timestamped copies of the journal and thins them out
+// by age, so a bad edit or a corrupted write can be rolled back.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"togo/internal/atomicfile"
+)
+
+// timeLayout names backups after the moment they were taken, in UTC, so
+// names sort chronologically.
+const timeLayout = "2006-01-02T150405Z"
+
+// ErrNotFound is returned by Restore for a name that is not a backup.
+var ErrNotFound = errors.New("no such backup")
+
+// Policy says which backups Prune keeps. A backup is kept if any rule
+// keeps it; zero disables a rule.
+type Policy struct {
+	// Keep is how many of the most recent backups are kept regardless of
+	// age.
+	Keep int
+
+	// Daily is how many days, counting today, keep their newest backup.
+	Daily int
+
+	// Weekly is how many weeks, counting this one, keep their newest
+	// backup. Weeks start on Monday.
+	Weekly int
+}
+
+// DefaultPolicy keeps the last ten backups, one a day for a week and one a
+// week for a month.
+func DefaultPolicy() Policy {
+	return Policy{Keep: 10, Daily: 7, Weekly: 4}
+}
+
+// Backup is one stored copy of the journal.
+type Backup struct {
+	// Name identifies the backup within its directory.
+	Name string
+	// Time is when the backup was taken.
+	Time time.Time
+	// Size is the backup's length in bytes.
+	Size int64
+}
+
+// Create copies the file at journal into dir under a name derived from now
+// and returns the new backup. A missing journal has nothing to back up and
+// yields ok == false.
+func Create(journal, dir string, now time.Time) (b Backup, ok bool, err error) {
+	src, err := os.Open(journal)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Backup{}, false, nil
+	}
+	if err != nil {
+		return Backup{}, false, err
+	}
+	defer src.Close()
+
+	b = Backup{
+		Name: now.UTC().Format(timeLayout) + filepath.Ext(journal),
+		Time: now.UTC().Truncate(time.Second),
+	}
+	b.Size, err = copyAtomic(filepath.Join(dir, b.Name), src)
+	if err != nil {
+		return Backup{}, false, err
+	}
+	return b, true, nil
+}
+
+// List returns the backups in dir, newest first. Files that are not
+// backups are ignored, and a missing directory holds no backups.
+func List(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		name := e.Name()
+		t, err := time.Parse(timeLayout, strings.TrimSuffix(name, filepath.Ext(name)))
+		if err != nil || !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, Backup{Name: name, Time: t, Size: info.Size()})
+	}
+	slices.SortFunc(backups, func(a, b Backup) int { return b.Time.Compare(a.Time) })
+	return backups, nil
+}
+
+// Prune deletes the backups in dir that policy does not keep, judging days
+// and weeks in now's location, and returns what it deleted.
+func Prune(dir string, policy Policy, now time.Time) ([]Backup, error) {
+	backups, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := retained(backups, policy, now)
+	var removed []Backup
+	for i, b := range backups {
+		if keep[i] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, b.Name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+// retained marks which of backups, ordered newest first, policy keeps.
+func retained(backups []Backup, policy Policy, now time.Time) []bool {
+	keep := make([]bool, len(backups))
+	today := startOfDay(now)
+	thisWeek := startOfWeek(today)
+	days := map[time.Time]bool{}
+	weeks := map[time.Time]bool{}
+
+	for i, b := range backups {
+		if i < policy.Keep {
+			keep[i] = true
+		}
+		day := startOfDay(b.Time.In(now.Location()))
+		if !days[day] && day.After(today.AddDate(0, 0, -policy.Daily)) {
+			days[day] = true
+			keep[i] = true
+		}
+		week := startOfWeek(day)
+		if !weeks[week] && week.After(thisWeek.AddDate(0, 0, -7*policy.Weekly)) {
+			weeks[week] = true
+			keep[i] = true
+		}
+	}
+	return keep
+}
+
+// Restore replaces the file at journal with the named backup from dir,
+// first backing up the current journal so the restore can itself be undone.
+func Restore(dir, name, journal string, now time.Time) error {
+	if name != filepath.Base(name) {
+		return fmt.Errorf("%q: %w", name, ErrNotFound)
+	}
+	src, err := os.Open(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%q: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if _, _, err := Create(journal, dir, now); err != nil {
+		return err
+	}
+	_, err = copyAtomic(journal, src)
+	return err
+}
+
+// copyAtomic writes src to path via a temporary file and rename, returning
+// the number of bytes written.
+func copyAtomic(path string, src io.Reader) (int64, error) {
+	var n int64
+	err := atomicfile.Write(path, func(w io.Writer) (err error) {
+		n, err = io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// at returns a moment on 2024-06-dd (a Friday when dd is 14) in UTC.
+func at(day, hour int) time.Time {
+	return time.Date(2024, 6, day, hour, 0, 0, 0, time.UTC)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestCreateListRestore verifies backups are named by time, listed newest
+// first and restorable, with the replaced journal backed up too.
+func TestCreateListRestore(t *testing.T) {
+	root := t.TempDir()
+	journal := filepath.Join(root, "tasks.json")
+	dir := filepath.Join(root, "backups")
+
+	if _, ok, err := Create(journal, dir, at(14, 9)); err != nil || ok {
+		t.Fatalf("Create() of a missing journal = %v, %v; want nothing", ok, err)
+	}
+
+	writeFile(t, journal, "v1")
+	first, ok, err := Create(journal, dir, at(14, 9))
+	if err != nil || !ok {
+		t.Fatalf("Create() = %v, %v", ok, err)
+	}
+	if first.Name != "2024-06-14T090000Z.json" || first.Size != 2 {
+		t.Errorf("Create() = %+v", first)
+	}
+	writeFile(t, journal, "v2")
+	if _, _, err := Create(journal, dir, at(14, 10)); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "notes.txt"), "not a backup")
+
+	backups, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].Name != "2024-06-14T100000Z.json" || !backups[1].Time.Equal(at(14, 9)) {
+		t.Errorf("List() = %+v", backups)
+	}
+
+	writeFile(t, journal, "v3")
+	if err := Restore(dir, first.Name, journal, at(14, 11)); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	if got := readFile(t, journal); got != "v1" {
+		t.Errorf("journal after Restore() = %q, want v1", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "2024-06-14T110000Z.json")); got != "v3" {
+		t.Errorf("pre-restore backup = %q, want v3", got)
+	}
+
+	for _, name := range []string{"2020-01-01T000000Z.json", "../tasks.json"} {
+		if err := Restore(dir, name, journal, at(14, 12)); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Restore(%q) error = %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+// TestPrune verifies the retention tiers.
+func TestPrune(t *testing.T) {
+	now := at(14, 18) // Friday
+	tests := []struct {
+		name   string
+		policy Policy
+		taken  []time.Time
+		want   []time.Time
+	}{
+		{
+			name:   "keep last n",
+			policy: Policy{Keep: 2},
+			taken:  []time.Time{at(14, 9), at(14, 10), at(14, 11)},
+			want:   []time.Time{at(14, 11), at(14, 10)},
+		},
+		{
+			name:   "newest per day within the daily window",
+			policy: Policy{Daily: 2},
+			taken:  []time.Time{at(12, 9), at(13, 9), at(13, 10), at(14, 9), at(14, 10)},
+			want:   []time.Time{at(14, 10), at(13, 10)},
+		},
+		{
+			name:   "newest per week within the weekly window",
+			policy: Policy{Weekly: 2},
+			taken:  []time.Time{at(3, 9), at(5, 9), at(8, 9), at(11, 9), at(14, 9)},
+			want:   []time.Time{at(14, 9), at(8, 9)},
+		},
+		{
+			name:   "tiers combine",
+			policy: Policy{Keep: 1, Daily: 1, Weekly: 3},
+			taken:  []time.Time{at(1, 9), at(5, 9), at(13, 9), at(14, 8), at(14, 9)},
+			want:   []time.Time{at(14, 9), at(5, 9), at(1, 9)},
+		},
+		{
+			name:  "zero policy removes everything",
+			taken: []time.Time{at(14, 9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			journal := filepath.Join(root, "tasks.json")
+			dir := filepath.Join(root, "backups")
+			writeFile(t, journal, "{}")
+			for _, ts := range tt.taken {
+				if _, _, err := Create(journal, dir, ts); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed, err := Prune(dir, tt.policy, now)
+			if err != nil {
+				t.Fatalf("Prune() error: %v", err)
+			}
+			left, _ := List(dir)
+			if len(left) != len(tt.want) || len(removed) != len(tt.taken)-len(tt.want) {
+				t.Fatalf("Prune() left %+v, removed %+v", left, removed)
+			}
+			for i, b := range left {
+				if !b.Time.Equal(tt.want[i]) {
+					t.Errorf("left[%d] = %v, want %v", i, b.Time, tt.want[i])
+				}
+			}
+		})
+	}
+}
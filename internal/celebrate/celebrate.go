@@ -0,0 +1,158 @@
+// Package celebrate provides the optional feedback shown when a task is
+// completed: a terminal bell, a short confetti animation, or a user command.
+// It is off by default; a milestone variant marks completion streaks.
+package celebrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Mode selects the kind of celebration.
+type Mode string
+
+const (
+	ModeOff      Mode = "off"
+	ModeBell     Mode = "bell"
+	ModeConfetti Mode = "confetti"
+	ModeCommand  Mode = "command"
+)
+
+// Modes lists the accepted modes in the order they are offered to users.
+var Modes = []Mode{ModeOff, ModeBell, ModeConfetti, ModeCommand}
+
+// ParseMode validates a configured mode name.
+func ParseMode(s string) (Mode, error) {
+	m := Mode(s)
+	if !slices.Contains(Modes, m) {
+		return "", fmt.Errorf("unknown celebration %q", s)
+	}
+	return m, nil
+}
+
+// DefaultMilestones are the streak lengths, in days, that get the milestone
+// variant.
+var DefaultMilestones = []int{3, 7, 14, 30, 60, 100, 365}
+
+// Event describes a completion worth celebrating.
+type Event struct {
+	Title  string
+	Streak int
+
+	// Milestone is set when Streak is one of the configured milestones.
+	Milestone bool
+}
+
+// Message returns the short line shown alongside the celebration.
+func (e Event) Message() string {
+	if e.Milestone {
+		return fmt.Sprintf("%d-day streak! Keep it going.", e.Streak)
+	}
+	return "Done: " + e.Title
+}
+
+// Celebrator turns completions into feedback according to the user's
+// settings. The zero value celebrates nothing.
+type Celebrator struct {
+	Mode Mode
+
+	// Command is run through the shell in ModeCommand, with TOGO_TASK_TITLE,
+	// TOGO_STREAK and TOGO_MILESTONE ("1" or "0") in its environment.
+	Command string
+
+	// Milestones overrides DefaultMilestones when non-nil.
+	Milestones []int
+}
+
+// Event builds the event for completing title with the given streak.
+func (c Celebrator) Event(title string, streak int) Event {
+	milestones := c.Milestones
+	if milestones == nil {
+		milestones = DefaultMilestones
+	}
+	return Event{Title: title, Streak: streak, Milestone: slices.Contains(milestones, streak)}
+}
+
+// Enabled reports whether any feedback is configured.
+func (c Celebrator) Enabled() bool {
+	return c.Mode != "" && c.Mode != ModeOff
+}
+
+// Celebrate performs the non-visual part of the celebration: it rings the
+// bell on w (twice for milestones) or runs the configured command. Confetti
+// is animated by the caller using Frames.
+func (c Celebrator) Celebrate(ctx context.Context, w io.Writer, e Event) error {
+	switch c.Mode {
+	case ModeBell:
+		bell := "\a"
+		if e.Milestone {
+			bell = "\a\a"
+		}
+		_, err := io.WriteString(w, bell)
+		return err
+	case ModeCommand:
+		if strings.TrimSpace(c.Command) == "" {
+			return nil
+		}
+		cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+		cmd.Env = append(os.Environ(),
+			"TOGO_TASK_TITLE="+e.Title,
+			"TOGO_STREAK="+strconv.Itoa(e.Streak),
+			"TOGO_MILESTONE="+boolFlag(e.Milestone),
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("celebration command: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+func boolFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// confettiGlyphs are drawn at random positions in each frame.
+var confettiGlyphs = []rune{'*', '+', '·', '°', '✦', '✧'}
+
+// Frames renders a brief confetti animation of the given size. Milestones
+// get twice as many frames and denser confetti. The seed makes the output
+// reproducible.
+func Frames(e Event, width, height int, seed uint64) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	count, density := 6, 12
+	if e.Milestone {
+		count, density = 12, 6
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	frames := make([]string, count)
+	for f := range frames {
+		var b strings.Builder
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if rng.IntN(density) == 0 {
+					b.WriteRune(confettiGlyphs[rng.IntN(len(confettiGlyphs))])
+				} else {
+					b.WriteByte(' ')
+				}
+			}
+			if y < height-1 {
+				b.WriteByte('\n')
+			}
+		}
+		frames[f] = b.String()
+	}
+	return frames
+}
@@ -0,0 +1,135 @@
+package celebrate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCelebrator_Event verifies milestone detection with default and custom
+// milestones.
+func TestCelebrator_Event(t *testing.T) {
+	tests := []struct {
+		name       string
+		milestones []int
+		streak     int
+		want       bool
+	}{
+		{"default milestone", nil, 7, true},
+		{"default ordinary day", nil, 8, false},
+		{"custom milestone", []int{5}, 5, true},
+		{"custom excludes default", []int{5}, 7, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Celebrator{Milestones: tt.milestones}.Event("Write report", tt.streak)
+			if e.Milestone != tt.want {
+				t.Errorf("Milestone = %v, want %v", e.Milestone, tt.want)
+			}
+		})
+	}
+}
+
+// TestCelebrator_Celebrate_Bell verifies the bell rings once, twice for
+// milestones, and not at all when off.
+func TestCelebrator_Celebrate_Bell(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      Mode
+		milestone bool
+		want      string
+	}{
+		{"off", ModeOff, true, ""},
+		{"zero value", "", false, ""},
+		{"bell", ModeBell, false, "\a"},
+		{"milestone bell", ModeBell, true, "\a\a"},
+		{"confetti is drawn by the caller", ModeConfetti, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Celebrator{Mode: tt.mode}.Celebrate(context.Background(), &buf, Event{Milestone: tt.milestone})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestCelebrator_Celebrate_Command verifies the command receives the event
+// through its environment and that failures are reported.
+func TestCelebrator_Celebrate_Command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	c := Celebrator{
+		Mode:    ModeCommand,
+		Command: `printf '%s|%s|%s' "$TOGO_TASK_TITLE" "$TOGO_STREAK" "$TOGO_MILESTONE" > ` + out,
+	}
+
+	if err := c.Celebrate(context.Background(), nil, c.Event("Ship it", 7)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Ship it|7|1" {
+		t.Errorf("command saw %q", got)
+	}
+
+	failing := Celebrator{Mode: ModeCommand, Command: "echo nope >&2; exit 3"}
+	err = failing.Celebrate(context.Background(), nil, Event{})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected failure including output, got %v", err)
+	}
+}
+
+// TestFrames verifies the animation has the requested size, is reproducible
+// and is longer for milestones.
+func TestFrames(t *testing.T) {
+	frames := Frames(Event{}, 10, 3, 42)
+	if len(frames) != 6 {
+		t.Fatalf("got %d frames, want 6", len(frames))
+	}
+	for i, f := range frames {
+		lines := strings.Split(f, "\n")
+		if len(lines) != 3 {
+			t.Errorf("frame %d has %d lines, want 3", i, len(lines))
+		}
+		for _, l := range lines {
+			if n := len([]rune(l)); n != 10 {
+				t.Errorf("frame %d line width %d, want 10", i, n)
+			}
+		}
+	}
+
+	again := Frames(Event{}, 10, 3, 42)
+	if strings.Join(again, "") != strings.Join(frames, "") {
+		t.Error("Frames is not reproducible for the same seed")
+	}
+	if n := len(Frames(Event{Milestone: true}, 10, 3, 42)); n != 12 {
+		t.Errorf("milestone frames = %d, want 12", n)
+	}
+	if Frames(Event{}, 0, 3, 1) != nil {
+		t.Error("expected no frames for zero width")
+	}
+}
+
+// TestParseMode verifies known modes are accepted and others rejected.
+func TestParseMode(t *testing.T) {
+	for _, m := range Modes {
+		if got, err := ParseMode(string(m)); err != nil || got != m {
+			t.Errorf("ParseMode(%q) = %q, %v", m, got, err)
+		}
+	}
+	if _, err := ParseMode("fireworks"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,106 @@
+// Package checklist reads Markdown task lists, so existing notes can be
+// turned into a journal:
+//
+//	## Errands
+//	- [ ] Renew passport #admin due:2024-06-30 !h
+//	- [x] Call the dentist
+//	  * [ ] Book a follow-up
+//
+// Every checklist item becomes a task, whatever its bullet (-, * or +),
+// numbering or indentation; "[x]" marks it done. Item text uses the
+// quick-add syntax (see model.ParseQuickAdd) for tags, priority, energy,
+// due date and estimate. Items inside fenced code blocks are ignored.
+package checklist
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// System is the ExternalRef system of tasks imported from Markdown.
+const System = "markdown"
+
+// itemPattern matches a checklist item: indentation, a bullet or number,
+// the checkbox and the item text.
+var itemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*)$`)
+
+// Parse reads the checklist items in r as drafts. The first item that is
+// not a valid task stops parsing with an *importer.LineError.
+func Parse(r io.Reader) ([]importer.Draft, error) {
+	var (
+		drafts []importer.Draft
+		fence  string
+	)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if marker := fenceMarker(line); marker != "" {
+			switch {
+			case fence == "":
+				fence = marker
+			case strings.HasPrefix(marker, fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		m := itemPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d, err := parseItem(m[2], m[1] != " ")
+		if err != nil {
+			return nil, &importer.LineError{Line: n, Err: err}
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, sc.Err()
+}
+
+// parseItem converts the text of one checklist item.
+func parseItem(text string, done bool) (importer.Draft, error) {
+	task, err := model.ParseQuickAdd(text)
+	if err != nil {
+		return importer.Draft{}, err
+	}
+	d := importer.Draft{
+		Title:    task.Title,
+		Tags:     task.Tags,
+		Priority: task.Priority,
+		Energy:   task.Energy,
+		Estimate: task.Estimate,
+		Due:      task.DueDate,
+	}
+	if done {
+		d.Status = model.StatusDone
+	}
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	d.Ref = model.ExternalRef{System: System, ID: hex.EncodeToString(sum[:8])}
+	return d, nil
+}
+
+// fenceMarker returns the ``` or ~~~ run opening or closing a fenced code
+// block on line, or "" if line is not a fence.
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, c := range []string{"`", "~"} {
+		run := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if run >= 3 {
+			return trimmed[:run]
+		}
+	}
+	return ""
+}
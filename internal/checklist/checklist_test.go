@@ -0,0 +1,73 @@
+package checklist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// TestParse verifies which lines become tasks and how they are read.
+func TestParse(t *testing.T) {
+	input := strings.Join([]string{
+		"# Notes",
+		"",
+		"- [ ] Renew passport #admin due:2024-06-30 !h",
+		"- [x] Call the dentist",
+		"  * [X] Book a follow-up",
+		"1. [ ] Numbered item",
+		"+ [ ]  Extra   spaces",
+		"- plain bullet",
+		"- [] not a checkbox",
+		"```markdown",
+		"- [ ] example inside code",
+		"````",
+		"~~~",
+		"- [ ] inside tildes",
+		"~~~",
+		"Done with #hashtags in prose.",
+	}, "\n")
+
+	drafts, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	var titles []string
+	for _, d := range drafts {
+		titles = append(titles, d.Title)
+	}
+	want := "Renew passport|Call the dentist|Book a follow-up|Numbered item|Extra spaces"
+	if got := strings.Join(titles, "|"); got != want {
+		t.Fatalf("titles = %q, want %q", got, want)
+	}
+
+	first := drafts[0]
+	due := time.Date(2024, 6, 30, 0, 0, 0, 0, time.Local)
+	if first.Status != "" || first.Priority != model.PriorityHigh || first.Tags[0] != "admin" || !first.Due.Equal(due) || first.Ref.System != System {
+		t.Errorf("first draft = %+v", first)
+	}
+	if drafts[1].Status != model.StatusDone || drafts[2].Status != model.StatusDone {
+		t.Error("checked items should be done")
+	}
+}
+
+// TestParse_StableRefs verifies ticking an item off keeps its identity.
+func TestParse_StableRefs(t *testing.T) {
+	open, _ := Parse(strings.NewReader("- [ ] Renew passport #admin"))
+	done, _ := Parse(strings.NewReader("* [x] Renew  passport #admin"))
+	if open[0].Ref != done[0].Ref {
+		t.Errorf("refs differ: %v vs %v", open[0].Ref, done[0].Ref)
+	}
+}
+
+// TestParse_ReportsLine verifies errors name the offending line.
+func TestParse_ReportsLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("- [ ] Fine\n- [ ] Pay rent due:someday\n"))
+	var lineErr *importer.LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 {
+		t.Errorf("Parse() error = %v, want a LineError for line 2", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Package clipboard renders tasks for pasting into chat or email and places
+// the result on the system clipboard using the OSC 52 terminal escape, which
+// works over SSH and needs no platform helpers.
+package clipboard
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"togo/internal/model"
+)
+
+// Format selects how copied tasks are rendered.
+type Format int
+
+const (
+	// FormatMarkdown renders a Markdown checklist item per task.
+	FormatMarkdown Format = iota
+	// FormatPlain renders one plain-text line per task.
+	FormatPlain
+	// FormatJSON renders the task records as indented JSON.
+	FormatJSON
+
+	formatCount
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatMarkdown:
+		return "markdown"
+	case FormatPlain:
+		return "plain"
+	case FormatJSON:
+		return "json"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// Next returns the format following f, wrapping around after JSON.
+func (f Format) Next() Format {
+	return (f + 1) % formatCount
+}
+
+// dateLayout is used for due dates in text formats.
+const dateLayout = "2006-01-02"
+
+// Render formats tasks for the clipboard. A single task in JSON format is
+// rendered as an object; several as an array.
+func Render(tasks []*model.Task, f Format) (string, error) {
+	switch f {
+	case FormatMarkdown:
+		lines := make([]string, len(tasks))
+		for i, t := range tasks {
+			box := " "
+			if t.Status == model.StatusDone {
+				box = "x"
+			}
+			lines[i] = fmt.Sprintf("- [%s] %s%s", box, t.Title, describe(t, "#"))
+		}
+		return strings.Join(lines, "\n"), nil
+	case FormatPlain:
+		lines := make([]string, len(tasks))
+		for i, t := range tasks {
+			lines[i] = t.Title + describe(t, "")
+		}
+		return strings.Join(lines, "\n"), nil
+	case FormatJSON:
+		var v any = tasks
+		if len(tasks) == 1 {
+			v = tasks[0]
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown clipboard format %v", f)
+	}
+}
+
+// describe renders tags (with the given prefix) and the due date as a suffix.
+func describe(t *model.Task, tagPrefix string) string {
+	var b strings.Builder
+	if len(t.Tags) > 0 {
+		if tagPrefix == "" {
+			fmt.Fprintf(&b, " [%s]", strings.Join(t.Tags, ", "))
+		} else {
+			for _, tag := range t.Tags {
+				b.WriteString(" " + tagPrefix + tag)
+			}
+		}
+	}
+	if t.DueDate != nil {
+		fmt.Fprintf(&b, " (due %s)", t.DueDate.Format(dateLayout))
+	}
+	return b.String()
+}
+
+// Write places text on the clipboard by emitting an OSC 52 sequence to w,
+// which should be the terminal.
+func Write(w io.Writer, text string) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	_, err := io.WriteString(w, seq)
+	return err
+}
+
+// CycleWindow is how soon a repeated copy must follow the previous one to
+// advance to the next format instead of starting over.
+const CycleWindow = 2 * time.Second
+
+// Cycler picks the format for a copy action so that repeated presses cycle
+// Markdown → plain → JSON, while an isolated press always starts at Markdown.
+type Cycler struct {
+	last    time.Time
+	current Format
+}
+
+// Press records a copy action at now and returns the format to use.
+func (c *Cycler) Press(now time.Time) Format {
+	if !c.last.IsZero() && now.Sub(c.last) <= CycleWindow {
+		c.current = c.current.Next()
+	} else {
+		c.current = FormatMarkdown
+	}
+	c.last = now
+	return c.current
+}
@@ -0,0 +1,107 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+func fixtures() []*model.Task {
+	due := time.Date(2025, 11, 14, 17, 0, 0, 0, time.UTC)
+	done := due.Add(-time.Hour)
+	return []*model.Task{
+		{ID: model.NewTaskID(), Title: "Ship release", Status: model.StatusToday, Tags: []string{"work", "urgent"}, DueDate: &due},
+		{ID: model.NewTaskID(), Title: "Buy milk", Status: model.StatusDone, CompletedAt: &done},
+	}
+}
+
+// TestRender_TextFormats verifies Markdown and plain rendering.
+func TestRender_TextFormats(t *testing.T) {
+	tasks := fixtures()
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatMarkdown, "- [ ] Ship release #work #urgent (due 2025-11-14)\n- [x] Buy milk"},
+		{FormatPlain, "Ship release [work, urgent] (due 2025-11-14)\nBuy milk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			got, err := Render(tasks, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRender_JSON verifies single tasks render as objects and several as arrays.
+func TestRender_JSON(t *testing.T) {
+	tasks := fixtures()
+
+	single, err := Render(tasks[:1], FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(single), &obj); err != nil || obj["title"] != "Ship release" {
+		t.Errorf("single task JSON = %s (err %v)", single, err)
+	}
+
+	multi, err := Render(tasks, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var arr []map[string]any
+	if err := json.Unmarshal([]byte(multi), &arr); err != nil || len(arr) != 2 {
+		t.Errorf("multi task JSON = %s (err %v)", multi, err)
+	}
+}
+
+// TestWrite_EmitsOSC52 verifies the escape sequence carries the base64 text.
+func TestWrite_EmitsOSC52(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "hello ✓"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b]52;c;") || !strings.HasSuffix(out, "\a") {
+		t.Fatalf("unexpected sequence %q", out)
+	}
+	payload := strings.TrimSuffix(strings.TrimPrefix(out, "\x1b]52;c;"), "\a")
+	decoded, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || string(decoded) != "hello ✓" {
+		t.Errorf("payload decoded to %q (err %v)", decoded, err)
+	}
+}
+
+// TestCycler_Press verifies repeated presses cycle formats and a pause resets.
+func TestCycler_Press(t *testing.T) {
+	var c Cycler
+	start := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+
+	got := []Format{
+		c.Press(start),
+		c.Press(start.Add(500 * time.Millisecond)),
+		c.Press(start.Add(time.Second)),
+		c.Press(start.Add(1500 * time.Millisecond)),
+		c.Press(start.Add(10 * time.Second)),
+	}
+	want := []Format{FormatMarkdown, FormatPlain, FormatJSON, FormatMarkdown, FormatMarkdown}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("press %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
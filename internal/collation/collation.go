@@ -0,0 +1,70 @@
+// Package collation sorts task titles in the natural order of a language,
+// so "Äpfel" sorts with "Apfel" rather than after "Zucker", and "Task 10"
+// after "Task 9".
+package collation
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"togo/internal/model"
+)
+
+// Order compares titles. The zero value and a nil *Order compare by byte
+// order, which is what an empty locale setting selects.
+//
+// An Order reuses internal buffers and is not safe for concurrent use.
+type Order struct {
+	collator *collate.Collator
+	buf      collate.Buffer
+}
+
+// New returns an Order for the BCP 47 locale, such as "de", "sv" or
+// "pt-BR". An empty locale yields byte order. Digits compare numerically.
+func New(locale string) (*Order, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return &Order{}, nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, fmt.Errorf("unknown locale %q: %w", locale, err)
+	}
+	return &Order{collator: collate.New(tag, collate.Numeric)}, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether a sorts before, with or
+// after b.
+func (o *Order) Compare(a, b string) int {
+	if o == nil || o.collator == nil {
+		return strings.Compare(a, b)
+	}
+	return o.collator.CompareString(a, b)
+}
+
+// SortTasks orders tasks by title, keeping the existing order of tasks
+// whose titles collate equal.
+func (o *Order) SortTasks(tasks []*model.Task) {
+	if o == nil || o.collator == nil {
+		slices.SortStableFunc(tasks, func(a, b *model.Task) int {
+			return strings.Compare(a.Title, b.Title)
+		})
+		return
+	}
+
+	// Collation keys make each comparison a byte comparison instead of
+	// re-deriving collation elements for every pair.
+	keys := make(map[*model.Task][]byte, len(tasks))
+	for _, t := range tasks {
+		keys[t] = slices.Clone(o.collator.KeyFromString(&o.buf, t.Title))
+		o.buf.Reset()
+	}
+	slices.SortStableFunc(tasks, func(a, b *model.Task) int {
+		return bytes.Compare(keys[a], keys[b])
+	})
+}
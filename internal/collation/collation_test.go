@@ -0,0 +1,78 @@
+package collation
+
+import (
+	"slices"
+	"testing"
+
+	"togo/internal/model"
+)
+
+// TestOrder_SortTasks verifies locale-aware ordering against byte order.
+func TestOrder_SortTasks(t *testing.T) {
+	titles := []string{"Zucker kaufen", "Äpfel kaufen", "apfelsaft", "Task 10", "Task 9", "Œuvre lesen", "Ofen putzen"}
+
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{
+			locale: "",
+			want:   []string{"Ofen putzen", "Task 10", "Task 9", "Zucker kaufen", "apfelsaft", "Äpfel kaufen", "Œuvre lesen"},
+		},
+		{
+			locale: "de",
+			want:   []string{"Äpfel kaufen", "apfelsaft", "Œuvre lesen", "Ofen putzen", "Task 9", "Task 10", "Zucker kaufen"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("locale "+tt.locale, func(t *testing.T) {
+			order, err := New(tt.locale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tasks := make([]*model.Task, len(titles))
+			for i, title := range titles {
+				tasks[i] = &model.Task{Title: title}
+			}
+
+			order.SortTasks(tasks)
+
+			got := make([]string, len(tasks))
+			for i, task := range tasks {
+				got[i] = task.Title
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortTasks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOrder_Compare verifies locale-specific letters and the nil Order.
+func TestOrder_Compare(t *testing.T) {
+	sv, err := New("sv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Swedish sorts Ö after Z; German treats it like O.
+	if sv.Compare("Öl", "Zebra") <= 0 {
+		t.Error(`sv: expected "Öl" after "Zebra"`)
+	}
+	de, _ := New("de")
+	if de.Compare("Öl", "Zebra") >= 0 {
+		t.Error(`de: expected "Öl" before "Zebra"`)
+	}
+
+	var none *Order
+	if none.Compare("a", "b") != -1 {
+		t.Error("nil Order should compare by bytes")
+	}
+}
+
+// TestNew_InvalidLocale verifies malformed tags are rejected.
+func TestNew_InvalidLocale(t *testing.T) {
+	if _, err := New("not a locale!"); err == nil {
+		t.Error("expected error for malformed locale")
+	}
+}
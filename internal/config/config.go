@@ -0,0 +1,905 @@
+// Package config loads and stores user preferences for togo.
+//
+// The configuration file is a line-oriented "key = value" format with '#'
+// comments, so users can annotate it by hand without an external parser.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/atomicfile"
+	"togo/internal/backup"
+	"togo/internal/celebrate"
+	"togo/internal/collation"
+	"togo/internal/journals"
+	"togo/internal/model"
+	"togo/internal/paths"
+	"togo/internal/termlink"
+)
+
+// Storage backends accepted by the backend setting.
+const (
+	BackendJSON   = "json"
+	BackendBolt   = "bolt"
+	BackendEvents = "events"
+)
+
+// Encryption modes accepted by the encryption setting.
+const (
+	EncryptionOff        = "off"
+	EncryptionPassphrase = "passphrase"
+	EncryptionKeyFile    = "keyfile"
+)
+
+// Themes accepted by the theme setting.
+const (
+	ThemeAuto  = "auto"
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+)
+
+// Key binding profiles accepted by the keymap setting.
+const (
+	KeymapVim    = "vim"
+	KeymapArrows = "arrows"
+)
+
+// Rollover modes accepted by the rollover setting.
+const (
+	RolloverAuto   = "auto"
+	RolloverManual = "manual"
+)
+
+// Notification modes accepted by the notifications setting.
+const (
+	NotificationsOn  = "on"
+	NotificationsOff = "off"
+)
+
+// Today limit modes accepted by the today_limit_mode setting.
+const (
+	TodayLimitWarn  = "warn"
+	TodayLimitBlock = "block"
+)
+
+// Config holds user preferences. The zero value is not meaningful; use
+// Default() and override individual fields.
+type Config struct {
+	// DataDir is where the journal is stored. Empty means the platform
+	// default data directory.
+	DataDir string
+
+	// Journal names the journal opened when none is chosen with --journal.
+	Journal string
+
+	// NotesSplitThreshold is the note length in bytes from which notes are
+	// stored as separate Markdown files, by unencrypted JSON journals. Zero
+	// keeps all notes inline.
+	NotesSplitThreshold int
+
+	// Backend selects the storage engine.
+	Backend string
+
+	// Encryption selects how the journal is encrypted at rest. With
+	// passphrase the passphrase is read from the TOGO_PASSPHRASE
+	// environment variable. Only JSON journals are encrypted, so it must
+	// be off for the other backends.
+	Encryption string
+
+	// EncryptionKeyFile is the age key file used when Encryption is
+	// keyfile. Empty means "journal.key" beside the configuration file.
+	EncryptionKeyFile string
+
+	// BackupKeep, BackupDaily and BackupWeekly set how many journal backups
+	// are retained: the most recent ones, one per day and one per week (see
+	// backup.Policy). Only JSON journals are backed up.
+	BackupKeep   int
+	BackupDaily  int
+	BackupWeekly int
+
+	// Theme selects the TUI color palette.
+	Theme string
+
+	// Keymap selects the key binding profile.
+	Keymap string
+
+	// IDDisplay selects how task identities are shown in the TUI, CLI and
+	// exports.
+	IDDisplay model.IDDisplayMode
+
+	// DeferWarnThreshold is how many deferrals a task may accumulate before
+	// the UI warns about it. Zero disables the warning.
+	DeferWarnThreshold int
+
+	// MaxTitleLength, MaxNotesLength, MaxTags and MaxTagLength bound the
+	// size of task data (lengths in characters). Zero removes a limit.
+	MaxTitleLength int
+	MaxNotesLength int
+	MaxTags        int
+	MaxTagLength   int
+
+	// StaleAfterDays is how many days a pool task may go untouched before
+	// lists badge it as stale. Zero disables the badge.
+	StaleAfterDays int
+
+	// ArchiveAfterDays is how many days after completion a done task is
+	// moved out of the journal into the yearly archive, when the TUI opens
+	// the journal and by "togo archive". Zero disables archiving unless an
+	// age is given explicitly.
+	ArchiveAfterDays int
+
+	// Rollover selects when yesterday's unfinished today list returns to
+	// the pool: auto when the TUI opens a journal, or manual with only
+	// "togo rollover".
+	Rollover string
+
+	// Notifications selects whether the TUI shows desktop notifications
+	// when reminders and due times are reached.
+	Notifications string
+
+	// TodayLimit is how many tasks today's list may hold. Zero leaves it
+	// uncapped.
+	TodayLimit int
+
+	// TodayLimitMode selects what moving a task to a full today list does:
+	// warn and move it, or block the move.
+	TodayLimitMode string
+
+	// DailyCapacity is how much estimated effort a plan for the day puts
+	// on today's list. Zero leaves it unbounded.
+	DailyCapacity time.Duration
+
+	// ReviewIntervalDays is how many days may pass without a review before
+	// the TUI suggests one. Zero disables the nudge.
+	ReviewIntervalDays int
+
+	// EmptyTodayNudge is the time of day after which an empty today list is
+	// pointed out. Zero disables the nudge.
+	EmptyTodayNudge time.Duration
+
+	// UrgencyDue, UrgencyPriority, UrgencyAge and UrgencyDeferred weight the
+	// factors of the urgency score used to sort the pool.
+	UrgencyDue      float64
+	UrgencyPriority float64
+	UrgencyAge      float64
+	UrgencyDeferred float64
+
+	// UrgencyTags lists per-tag urgency weights as "tag:weight" pairs
+	// separated by commas, e.g. "next:15, someday:-3".
+	UrgencyTags string
+
+	// Collation is the BCP 47 locale used to sort titles, e.g. "de".
+	// Empty sorts by byte order.
+	Collation string
+
+	// JournalCollation overrides Collation for some journals, as
+	// "journal=locale" pairs separated by commas, e.g. "work=en, home=sv".
+	// An empty locale sorts that journal by byte order.
+	JournalCollation string
+
+	// RefURLs gives the address of another tool's records as
+	// "system=template" pairs separated by commas, "%s" standing for a
+	// record's ID, e.g. "jira=https://example.atlassian.net/browse/%s".
+	RefURLs string
+
+	// Celebration selects the feedback given when a task is completed.
+	Celebration celebrate.Mode
+
+	// CelebrationCommand is run on completion when Celebration is command.
+	CelebrationCommand string
+}
+
+// StaleThreshold converts the staleness setting into a duration.
+func (c Config) StaleThreshold() time.Duration {
+	return time.Duration(c.StaleAfterDays) * 24 * time.Hour
+}
+
+// ArchiveAge converts the archiving setting into a duration.
+func (c Config) ArchiveAge() time.Duration {
+	return time.Duration(c.ArchiveAfterDays) * 24 * time.Hour
+}
+
+// Limits converts the size limit settings into the model's limits.
+func (c Config) Limits() model.Limits {
+	return model.Limits{
+		TitleLength: c.MaxTitleLength,
+		NotesLength: c.MaxNotesLength,
+		Tags:        c.MaxTags,
+		TagLength:   c.MaxTagLength,
+	}
+}
+
+// UrgencyWeights converts the urgency settings into the model's weights.
+func (c Config) UrgencyWeights() model.UrgencyWeights {
+	// UrgencyTags was validated when it was set.
+	tags, _ := parseTagWeights(c.UrgencyTags)
+	return model.UrgencyWeights{
+		Due:      c.UrgencyDue,
+		Priority: c.UrgencyPriority,
+		Age:      c.UrgencyAge,
+		Deferred: c.UrgencyDeferred,
+		Tags:     tags,
+	}
+}
+
+// TitleOrder builds the order titles in the named journal sort in, as
+// JournalCollation says for it or else Collation.
+func (c Config) TitleOrder(journal string) *collation.Order {
+	// Both settings were validated when they were set; a nil Order sorts
+	// by byte order.
+	locale := c.Collation
+	if locales, _ := parseJournalCollation(c.JournalCollation); locales != nil {
+		if l, ok := locales[journal]; ok {
+			locale = l
+		}
+	}
+	order, _ := collation.New(locale)
+	return order
+}
+
+// Linker builds the hyperlink renderer for the terminal getenv, normally
+// os.Getenv, describes, linking external refs as RefURLs says.
+func (c Config) Linker(getenv func(string) string) termlink.Linker {
+	// RefURLs was validated when it was set.
+	urls, _ := parseRefURLs(c.RefURLs)
+	return termlink.Linker{Enabled: termlink.Supported(getenv), RefURLs: urls}
+}
+
+// Celebrator builds the completion feedback described by the settings.
+func (c Config) Celebrator() celebrate.Celebrator {
+	return celebrate.Celebrator{Mode: c.Celebration, Command: c.CelebrationCommand}
+}
+
+// NudgePolicy converts the review reminder settings into the model's policy.
+func (c Config) NudgePolicy() model.NudgePolicy {
+	return model.NudgePolicy{
+		ReviewInterval:  time.Duration(c.ReviewIntervalDays) * 24 * time.Hour,
+		EmptyTodayAfter: c.EmptyTodayNudge,
+	}
+}
+
+// Backups converts the backup retention settings into a policy.
+func (c Config) Backups() backup.Policy {
+	return backup.Policy{Keep: c.BackupKeep, Daily: c.BackupDaily, Weekly: c.BackupWeekly}
+}
+
+// JournalDir returns the directory holding the journal: DataDir, or the
+// platform's data directory when that is empty.
+func (c Config) JournalDir() (string, error) {
+	if c.DataDir != "" {
+		return c.DataDir, nil
+	}
+	return paths.Dir(paths.Data)
+}
+
+// Default returns the configuration used when no file exists.
+func Default() Config {
+	urgency := model.DefaultUrgencyWeights()
+	backups := backup.DefaultPolicy()
+	return Config{
+		Journal:            journals.Default,
+		Backend:            BackendJSON,
+		Encryption:         EncryptionOff,
+		BackupKeep:         backups.Keep,
+		BackupDaily:        backups.Daily,
+		BackupWeekly:       backups.Weekly,
+		Theme:              ThemeAuto,
+		Keymap:             KeymapVim,
+		IDDisplay:          model.DefaultIDDisplayMode,
+		DeferWarnThreshold: model.DefaultDeferWarnThreshold,
+		MaxTitleLength:     model.DefaultMaxTitleLength,
+		MaxNotesLength:     model.DefaultMaxNotesLength,
+		MaxTags:            model.DefaultMaxTags,
+		MaxTagLength:       model.DefaultMaxTagLength,
+		StaleAfterDays:     14,
+		ArchiveAfterDays:   90,
+		Rollover:           RolloverAuto,
+		Notifications:      NotificationsOn,
+		TodayLimitMode:     TodayLimitWarn,
+		DailyCapacity:      6 * time.Hour,
+		ReviewIntervalDays: 7,
+		EmptyTodayNudge:    9 * time.Hour,
+		UrgencyDue:         urgency.Due,
+		UrgencyPriority:    urgency.Priority,
+		UrgencyAge:         urgency.Age,
+		UrgencyDeferred:    urgency.Deferred,
+		Celebration:        celebrate.ModeOff,
+	}
+}
+
+// setting describes one configuration key: its documentation comment and
+// how to read and write it. Write emits settings in this order.
+type setting struct {
+	key     string
+	comment string
+	get     func(c *Config) string
+	set     func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{
+		key:     "data_dir",
+		comment: "Directory holding the journal, such as a synced folder. Empty uses the platform default. $TOGO_DATA_DIR and --data-dir override it.",
+		get:     func(c *Config) string { return c.DataDir },
+		set: func(c *Config, v string) error {
+			c.DataDir = v
+			return nil
+		},
+	},
+	{
+		key:     "journal",
+		comment: "Journal opened when --journal is not given, e.g. work or personal.",
+		get:     func(c *Config) string { return c.Journal },
+		set: func(c *Config, v string) error {
+			if err := journals.ValidateName(v); err != nil {
+				return err
+			}
+			c.Journal = v
+			return nil
+		},
+	},
+	{
+		key:     "notes_split_threshold",
+		comment: "Store notes this many bytes or longer as Markdown files in the data directory, for unencrypted JSON journals. 0 keeps notes inline.",
+		get:     func(c *Config) string { return strconv.Itoa(c.NotesSplitThreshold) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.NotesSplitThreshold, v)
+		},
+	},
+	{
+		key:     "backend",
+		comment: "Storage backend: json, bolt (one togo at a time, the TUI included) or events (an append-only change log).",
+		get:     func(c *Config) string { return c.Backend },
+		set: func(c *Config, v string) error {
+			if v != BackendJSON && c.Encryption != EncryptionOff {
+				return fmt.Errorf("%s journals cannot be encrypted; set encryption = off first", v)
+			}
+			return oneOf(&c.Backend, v, BackendJSON, BackendBolt, BackendEvents)
+		},
+	},
+	{
+		key:     "encryption",
+		comment: "Encrypt the journal at rest: off, passphrase (from $TOGO_PASSPHRASE) or keyfile. Requires the json backend.",
+		get:     func(c *Config) string { return c.Encryption },
+		set: func(c *Config, v string) error {
+			if v != EncryptionOff && c.Backend != BackendJSON {
+				return fmt.Errorf("only json journals can be encrypted, not %s ones", c.Backend)
+			}
+			return oneOf(&c.Encryption, v, EncryptionOff, EncryptionPassphrase, EncryptionKeyFile)
+		},
+	},
+	{
+		key:     "encryption_keyfile",
+		comment: "age key file for keyfile encryption, created on first use; back it up. Empty uses journal.key beside this file.",
+		get:     func(c *Config) string { return c.EncryptionKeyFile },
+		set: func(c *Config, v string) error {
+			c.EncryptionKeyFile = v
+			return nil
+		},
+	},
+	{
+		key:     "backup_keep",
+		comment: "Keep this many of the most recent backups of json journals. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.BackupKeep) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.BackupKeep, v)
+		},
+	},
+	{
+		key:     "backup_daily",
+		comment: "Also keep the newest backup of each of this many days. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.BackupDaily) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.BackupDaily, v)
+		},
+	},
+	{
+		key:     "backup_weekly",
+		comment: "Also keep the newest backup of each of this many weeks. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.BackupWeekly) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.BackupWeekly, v)
+		},
+	},
+	{
+		key:     "theme",
+		comment: "Color theme: auto, dark or light.",
+		get:     func(c *Config) string { return c.Theme },
+		set: func(c *Config, v string) error {
+			return oneOf(&c.Theme, v, ThemeAuto, ThemeDark, ThemeLight)
+		},
+	},
+	{
+		key:     "keymap",
+		comment: "Key binding profile: vim (hjkl) or arrows.",
+		get:     func(c *Config) string { return c.Keymap },
+		set: func(c *Config, v string) error {
+			return oneOf(&c.Keymap, v, KeymapVim, KeymapArrows)
+		},
+	},
+	{
+		key:     "id_display",
+		comment: "How task IDs are shown: uuid, short or sequence.",
+		get:     func(c *Config) string { return c.IDDisplay.String() },
+		set: func(c *Config, v string) error {
+			mode, err := model.ParseIDDisplayMode(v)
+			if err != nil {
+				return err
+			}
+			c.IDDisplay = mode
+			return nil
+		},
+	},
+	{
+		key:     "defer_warn_threshold",
+		comment: "Warn once a task has been deferred more than this many times. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.DeferWarnThreshold) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.DeferWarnThreshold, v)
+		},
+	},
+	{
+		key:     "max_title_length",
+		comment: "Reject task titles longer than this many characters. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.MaxTitleLength) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.MaxTitleLength, v)
+		},
+	},
+	{
+		key:     "max_notes_length",
+		comment: "Reject task notes longer than this many characters. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.MaxNotesLength) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.MaxNotesLength, v)
+		},
+	},
+	{
+		key:     "max_tags",
+		comment: "Reject tasks with more than this many tags. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.MaxTags) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.MaxTags, v)
+		},
+	},
+	{
+		key:     "max_tag_length",
+		comment: "Reject tags longer than this many characters. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.MaxTagLength) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.MaxTagLength, v)
+		},
+	},
+	{
+		key:     "stale_after_days",
+		comment: "Badge pool tasks untouched for more than this many days. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.StaleAfterDays) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.StaleAfterDays, v)
+		},
+	},
+	{
+		key:     "archive_after_days",
+		comment: "Move done tasks completed more than this many days ago into yearly archive files when the TUI opens the journal. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.ArchiveAfterDays) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.ArchiveAfterDays, v)
+		},
+	},
+	{
+		key:     "rollover",
+		comment: "Return yesterday's unfinished today list to the pool and pull in tasks scheduled for today: auto when the TUI opens, or manual with togo rollover.",
+		get:     func(c *Config) string { return c.Rollover },
+		set: func(c *Config, v string) error {
+			return oneOf(&c.Rollover, v, RolloverAuto, RolloverManual)
+		},
+	},
+	{
+		key:     "notifications",
+		comment: "Show desktop notifications for reminders and due times while the TUI runs: on or off. togo remind --daemon shows them regardless.",
+		get:     func(c *Config) string { return c.Notifications },
+		set: func(c *Config, v string) error {
+			return oneOf(&c.Notifications, v, NotificationsOn, NotificationsOff)
+		},
+	},
+	{
+		key:     "today_limit",
+		comment: "How many tasks the today list may hold; the TUI shows how full it is. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.TodayLimit) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.TodayLimit, v)
+		},
+	},
+	{
+		key:     "today_limit_mode",
+		comment: "What moving a task to a full today list does: warn and move it, or block the move.",
+		get:     func(c *Config) string { return c.TodayLimitMode },
+		set: func(c *Config, v string) error {
+			return oneOf(&c.TodayLimitMode, v, TodayLimitWarn, TodayLimitBlock)
+		},
+	},
+	{
+		key:     "daily_capacity",
+		comment: "How much estimated effort, like 6h or 7h30m, a plan for the day fills today's list with. 0 disables the bound.",
+		get:     func(c *Config) string { return formatDuration(c.DailyCapacity) },
+		set: func(c *Config, v string) error {
+			return duration(&c.DailyCapacity, v)
+		},
+	},
+	{
+		key:     "review_interval_days",
+		comment: "Suggest a review when none was done for this many days. 0 disables.",
+		get:     func(c *Config) string { return strconv.Itoa(c.ReviewIntervalDays) },
+		set: func(c *Config, v string) error {
+			return nonNegative(&c.ReviewIntervalDays, v)
+		},
+	},
+	{
+		key:     "empty_today_nudge",
+		comment: "Point out an empty today list after this time of day (HH:MM). Empty disables.",
+		get:     func(c *Config) string { return formatClock(c.EmptyTodayNudge) },
+		set: func(c *Config, v string) error {
+			return timeOfDay(&c.EmptyTodayNudge, v)
+		},
+	},
+	{
+		key:     "urgency_due",
+		comment: "Urgency weight of an approaching or passed due date.",
+		get:     func(c *Config) string { return formatFloat(c.UrgencyDue) },
+		set: func(c *Config, v string) error {
+			return number(&c.UrgencyDue, v)
+		},
+	},
+	{
+		key:     "urgency_priority",
+		comment: "Urgency weight of high priority (medium and low count less).",
+		get:     func(c *Config) string { return formatFloat(c.UrgencyPriority) },
+		set: func(c *Config, v string) error {
+			return number(&c.UrgencyPriority, v)
+		},
+	},
+	{
+		key:     "urgency_age",
+		comment: "Urgency weight of a task a year old or older.",
+		get:     func(c *Config) string { return formatFloat(c.UrgencyAge) },
+		set: func(c *Config, v string) error {
+			return number(&c.UrgencyAge, v)
+		},
+	},
+	{
+		key:     "urgency_deferred",
+		comment: "Urgency weight of a task deferred five or more times.",
+		get:     func(c *Config) string { return formatFloat(c.UrgencyDeferred) },
+		set: func(c *Config, v string) error {
+			return number(&c.UrgencyDeferred, v)
+		},
+	},
+	{
+		key:     "urgency_tags",
+		comment: `Extra urgency per tag as "tag:weight" pairs, e.g. "next:15, someday:-3".`,
+		get:     func(c *Config) string { return c.UrgencyTags },
+		set: func(c *Config, v string) error {
+			if _, err := parseTagWeights(v); err != nil {
+				return err
+			}
+			c.UrgencyTags = v
+			return nil
+		},
+	},
+	{
+		key:     "collation",
+		comment: `Locale for sorting titles, e.g. "de" or "sv". Empty sorts by byte order.`,
+		get:     func(c *Config) string { return c.Collation },
+		set: func(c *Config, v string) error {
+			if _, err := collation.New(v); err != nil {
+				return err
+			}
+			c.Collation = v
+			return nil
+		},
+	},
+	{
+		key:     "journal_collation",
+		comment: `Per-journal locales for sorting titles, overriding collation, as "journal=locale" pairs, e.g. "work=en, home=sv".`,
+		get:     func(c *Config) string { return c.JournalCollation },
+		set: func(c *Config, v string) error {
+			if _, err := parseJournalCollation(v); err != nil {
+				return err
+			}
+			c.JournalCollation = v
+			return nil
+		},
+	},
+	{
+		key:     "ref_urls",
+		comment: `Links for imported tasks' records as "system=URL" pairs, "%s" standing for the ID, e.g. "github=https://github.com/%s".`,
+		get:     func(c *Config) string { return c.RefURLs },
+		set: func(c *Config, v string) error {
+			if _, err := parseRefURLs(v); err != nil {
+				return err
+			}
+			c.RefURLs = v
+			return nil
+		},
+	},
+	{
+		key:     "celebration",
+		comment: "Feedback on completing a task: off, bell, confetti or command.",
+		get:     func(c *Config) string { return string(c.Celebration) },
+		set: func(c *Config, v string) error {
+			mode, err := celebrate.ParseMode(v)
+			if err != nil {
+				return err
+			}
+			c.Celebration = mode
+			return nil
+		},
+	},
+	{
+		key:     "celebration_command",
+		comment: "Shell command run on completion when celebration = command.",
+		get:     func(c *Config) string { return c.CelebrationCommand },
+		set: func(c *Config, v string) error {
+			c.CelebrationCommand = v
+			return nil
+		},
+	},
+}
+
+// Load reads the configuration at path. A missing file is not an error and
+// yields Default().
+func Load(path string) (Config, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a configuration from r, starting from Default() so that
+// omitted keys keep their default values.
+func Parse(r io.Reader) (Config, error) {
+	cfg := Default()
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("config line %d: expected key = value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = unquote(strings.TrimSpace(value))
+		if err := cfg.Set(key, value); err != nil {
+			return Config{}, fmt.Errorf("config line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Set applies a single key/value pair, validating the value.
+func (c *Config) Set(key, value string) error {
+	for _, s := range settings {
+		if s.key == key {
+			if err := s.set(c, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key %q", key)
+}
+
+// Write serializes the configuration to w as a commented file in the format
+// understood by Parse.
+func (c Config) Write(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# togo configuration\n")
+	b.WriteString("# Lines starting with '#' are comments; values may be double-quoted.\n")
+	for _, s := range settings {
+		fmt.Fprintf(&b, "\n# %s\n%s = %s\n", s.comment, s.key, strconv.Quote(s.get(&c)))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Save writes the configuration to path, creating parent directories as
+// needed. The file is written to a temporary sibling and renamed into place
+// so a crash never leaves a truncated configuration behind.
+func Save(path string, c Config) error {
+	return atomicfile.Write(path, c.Write)
+}
+
+// DefaultPath returns the location of the configuration file inside
+// togo's configuration directory (see paths.Dir).
+func DefaultPath() (string, error) {
+	dir, err := paths.Dir(paths.Config)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config"), nil
+}
+
+// oneOf assigns value to dst if it is one of allowed.
+func oneOf(dst *string, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			*dst = value
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// nonNegative parses a non-negative integer setting into dst.
+func nonNegative(dst *int, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("expected a whole number, got %q", value)
+	}
+	if n < 0 {
+		return fmt.Errorf("must not be negative, got %d", n)
+	}
+	*dst = n
+	return nil
+}
+
+// number parses a decimal setting into dst.
+func number(dst *float64, value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("expected a number, got %q", value)
+	}
+	*dst = f
+	return nil
+}
+
+// formatFloat renders a decimal setting without superfluous digits.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// parseTagWeights parses comma-separated "tag:weight" pairs. An empty value
+// yields a nil map.
+func parseTagWeights(value string) (map[string]float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		tag, weight, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		tag = strings.TrimSpace(tag)
+		if !ok || tag == "" {
+			return nil, fmt.Errorf("expected tag:weight, got %q", strings.TrimSpace(pair))
+		}
+		var w float64
+		if err := number(&w, strings.TrimSpace(weight)); err != nil {
+			return nil, fmt.Errorf("tag %q: %w", tag, err)
+		}
+		weights[tag] = w
+	}
+	return weights, nil
+}
+
+// parseRefURLs parses a "system=template" list, keyed by lowercase system
+// as termlink.Linker expects. An empty value yields a nil map.
+func parseRefURLs(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	urls := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		system, template, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		system, template = strings.ToLower(strings.TrimSpace(system)), strings.TrimSpace(template)
+		if !ok || system == "" {
+			return nil, fmt.Errorf("expected system=URL, got %q", strings.TrimSpace(pair))
+		}
+		if !strings.Contains(template, "%s") {
+			return nil, fmt.Errorf("system %q: URL %q has no %%s for the ID", system, template)
+		}
+		urls[system] = template
+	}
+	return urls, nil
+}
+
+// parseJournalCollation parses a "journal=locale" list, checking each
+// journal name and locale. An empty value yields a nil map.
+func parseJournalCollation(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	locales := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		journal, locale, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		journal, locale = strings.TrimSpace(journal), strings.TrimSpace(locale)
+		if !ok {
+			return nil, fmt.Errorf("expected journal=locale, got %q", strings.TrimSpace(pair))
+		}
+		if err := journals.ValidateName(journal); err != nil {
+			return nil, err
+		}
+		if _, err := collation.New(locale); err != nil {
+			return nil, fmt.Errorf("journal %q: %w", journal, err)
+		}
+		locales[journal] = locale
+	}
+	return locales, nil
+}
+
+// timeOfDay parses an "HH:MM" setting into an offset from midnight. An empty
+// value stores zero.
+func timeOfDay(dst *time.Duration, value string) error {
+	if value == "" {
+		*dst = 0
+		return nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return fmt.Errorf("expected a time of day like 09:00, got %q", value)
+	}
+	*dst = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return nil
+}
+
+// formatClock renders an offset from midnight as "HH:MM", or "" for zero.
+func formatClock(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// duration parses a non-negative duration setting like 6h or 45m into
+// dst. An empty value or 0 stores zero.
+func duration(dst *time.Duration, value string) error {
+	if value == "" || value == "0" {
+		*dst = 0
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return fmt.Errorf("expected a duration like 6h or 7h30m, got %q", value)
+	}
+	*dst = d
+	return nil
+}
+
+// formatDuration renders a duration setting in hours and minutes, or "0".
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0"
+	}
+	return model.FormatEstimate(d)
+}
+
+// unquote strips one pair of double quotes from value, interpreting Go
+// escape sequences inside them.
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,481 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/celebrate"
+	"togo/internal/model"
+)
+
+// TestLoad_MissingFile_ReturnsDefault verifies that a missing configuration
+// file is treated as an empty configuration.
+func TestLoad_MissingFile_ReturnsDefault(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.conf"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg != Default() {
+		t.Errorf("expected default config, got %+v", cfg)
+	}
+}
+
+// TestParse verifies comment handling, quoting and error reporting.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Config
+		wantErr string
+	}{
+		{
+			name:  "empty input yields default",
+			input: "",
+			want:  Default(),
+		},
+		{
+			name:  "comments and blank lines ignored",
+			input: "# how task IDs are shown\n\nid_display = sequence\n",
+			want:  withDefaults(func(c *Config) { c.IDDisplay = model.IDDisplaySequence }),
+		},
+		{
+			name:  "quoted value",
+			input: `id_display = "uuid"`,
+			want:  withDefaults(func(c *Config) { c.IDDisplay = model.IDDisplayUUID }),
+		},
+		{
+			name:  "defer threshold",
+			input: "defer_warn_threshold = 5",
+			want:  withDefaults(func(c *Config) { c.DeferWarnThreshold = 5 }),
+		},
+		{
+			name:  "title limit",
+			input: "max_title_length = 80",
+			want:  withDefaults(func(c *Config) { c.MaxTitleLength = 80 }),
+		},
+		{
+			name:  "stale threshold",
+			input: "stale_after_days = 30",
+			want:  withDefaults(func(c *Config) { c.StaleAfterDays = 30 }),
+		},
+		{
+			name:  "archive age",
+			input: "archive_after_days = 0",
+			want:  withDefaults(func(c *Config) { c.ArchiveAfterDays = 0 }),
+		},
+		{
+			name:  "manual rollover",
+			input: "rollover = manual",
+			want:  withDefaults(func(c *Config) { c.Rollover = RolloverManual }),
+		},
+		{
+			name:    "unknown rollover",
+			input:   "rollover = weekly",
+			wantErr: "rollover: expected one of auto, manual",
+		},
+		{
+			name:  "notifications off",
+			input: "notifications = off",
+			want:  withDefaults(func(c *Config) { c.Notifications = NotificationsOff }),
+		},
+		{
+			name:    "unknown notifications",
+			input:   "notifications = loud",
+			wantErr: "notifications: expected one of on, off",
+		},
+		{
+			name:  "blocking today limit",
+			input: "today_limit = 7\ntoday_limit_mode = block",
+			want: withDefaults(func(c *Config) {
+				c.TodayLimit = 7
+				c.TodayLimitMode = TodayLimitBlock
+			}),
+		},
+		{
+			name:    "unknown today limit mode",
+			input:   "today_limit_mode = nag",
+			wantErr: "today_limit_mode: expected one of warn, block",
+		},
+		{
+			name:  "daily capacity",
+			input: "daily_capacity = 7h30m",
+			want:  withDefaults(func(c *Config) { c.DailyCapacity = 7*time.Hour + 30*time.Minute }),
+		},
+		{
+			name:    "negative daily capacity",
+			input:   "daily_capacity = -2h",
+			wantErr: "daily_capacity: expected a duration like 6h or 7h30m, got \"-2h\"",
+		},
+		{
+			name:  "size limits",
+			input: "max_notes_length = 0\nmax_tags = 10\nmax_tag_length = 32",
+			want: withDefaults(func(c *Config) {
+				c.MaxNotesLength = 0
+				c.MaxTags = 10
+				c.MaxTagLength = 32
+			}),
+		},
+		{
+			name:  "celebration command",
+			input: "celebration = command\ncelebration_command = \"notify-send 'Nice!'\"",
+			want: withDefaults(func(c *Config) {
+				c.Celebration = celebrate.ModeCommand
+				c.CelebrationCommand = "notify-send 'Nice!'"
+			}),
+		},
+		{
+			name:  "all interactive settings",
+			input: "data_dir = \"/home/me/Sync/togo\"\nbackend = json\ntheme = dark\nkeymap = arrows",
+			want: withDefaults(func(c *Config) {
+				c.DataDir = "/home/me/Sync/togo"
+				c.Theme = ThemeDark
+				c.Keymap = KeymapArrows
+			}),
+		},
+		{
+			name:  "review nudges",
+			input: "review_interval_days = 14\nempty_today_nudge = 10:30",
+			want: withDefaults(func(c *Config) {
+				c.ReviewIntervalDays = 14
+				c.EmptyTodayNudge = 10*time.Hour + 30*time.Minute
+			}),
+		},
+		{
+			name:  "empty today nudge disabled",
+			input: `empty_today_nudge = ""`,
+			want:  withDefaults(func(c *Config) { c.EmptyTodayNudge = 0 }),
+		},
+		{
+			name:    "malformed time of day",
+			input:   "empty_today_nudge = 9am",
+			wantErr: "time of day",
+		},
+		{
+			name:  "default journal",
+			input: "journal = work",
+			want:  withDefaults(func(c *Config) { c.Journal = "work" }),
+		},
+		{
+			name:    "invalid journal name",
+			input:   "journal = My Work",
+			wantErr: "invalid journal name",
+		},
+		{
+			name:  "key file encryption",
+			input: "encryption = keyfile\nencryption_keyfile = /home/me/keys/togo.key",
+			want: withDefaults(func(c *Config) {
+				c.Encryption = EncryptionKeyFile
+				c.EncryptionKeyFile = "/home/me/keys/togo.key"
+			}),
+		},
+		{
+			name:    "unknown encryption",
+			input:   "encryption = rot13",
+			wantErr: "encryption: expected one of off, passphrase, keyfile",
+		},
+		{
+			name:  "backup retention",
+			input: "backup_keep = 3\nbackup_daily = 0\nbackup_weekly = 12",
+			want: withDefaults(func(c *Config) {
+				c.BackupKeep = 3
+				c.BackupDaily = 0
+				c.BackupWeekly = 12
+			}),
+		},
+		{
+			name:  "notes split threshold",
+			input: "notes_split_threshold = 2048",
+			want:  withDefaults(func(c *Config) { c.NotesSplitThreshold = 2048 }),
+		},
+		{
+			name:    "unknown theme",
+			input:   "theme = neon",
+			wantErr: "theme: expected one of auto, dark, light",
+		},
+		{
+			name:  "urgency weights",
+			input: "urgency_due = 8.5\nurgency_tags = \"next:15, someday:-3\"",
+			want: withDefaults(func(c *Config) {
+				c.UrgencyDue = 8.5
+				c.UrgencyTags = "next:15, someday:-3"
+			}),
+		},
+		{
+			name:    "malformed urgency tags",
+			input:   "urgency_tags = next=15",
+			wantErr: `urgency_tags: expected tag:weight, got "next=15"`,
+		},
+		{
+			name:    "non-numeric urgency weight",
+			input:   "urgency_age = lots",
+			wantErr: "expected a number",
+		},
+		{
+			name:  "collation locale",
+			input: "collation = pt-BR",
+			want:  withDefaults(func(c *Config) { c.Collation = "pt-BR" }),
+		},
+		{
+			name:    "malformed collation locale",
+			input:   "collation = not a locale",
+			wantErr: "collation: unknown locale",
+		},
+		{
+			name:  "journal collation",
+			input: `journal_collation = "work=en, home=sv"`,
+			want:  withDefaults(func(c *Config) { c.JournalCollation = "work=en, home=sv" }),
+		},
+		{
+			name:    "malformed journal collation",
+			input:   "journal_collation = work",
+			wantErr: `journal_collation: expected journal=locale, got "work"`,
+		},
+		{
+			name:    "journal collation locale",
+			input:   `journal_collation = "work=not a locale"`,
+			wantErr: `journal_collation: journal "work": unknown locale`,
+		},
+		{
+			name:  "ref URLs",
+			input: `ref_urls = "jira=https://example.atlassian.net/browse/%s, github=https://github.com/%s"`,
+			want: withDefaults(func(c *Config) {
+				c.RefURLs = "jira=https://example.atlassian.net/browse/%s, github=https://github.com/%s"
+			}),
+		},
+		{
+			name:    "ref URL without the ID",
+			input:   "ref_urls = jira=https://example.atlassian.net/browse/",
+			wantErr: `ref_urls: system "jira": URL "https://example.atlassian.net/browse/" has no %s for the ID`,
+		},
+		{
+			name:    "unknown celebration",
+			input:   "celebration = fireworks",
+			wantErr: `celebration: unknown celebration "fireworks"`,
+		},
+		{
+			name:  "bolt backend",
+			input: "backend = bolt",
+			want:  withDefaults(func(c *Config) { c.Backend = BackendBolt }),
+		},
+		{
+			name:    "unknown backend",
+			input:   "backend = sqlite",
+			wantErr: "backend",
+		},
+		{
+			name:    "encrypted bolt journal",
+			input:   "backend = bolt\nencryption = passphrase",
+			wantErr: "encryption: only json journals can be encrypted, not bolt ones",
+		},
+		{
+			name:    "encrypted events journal, encryption first",
+			input:   "encryption = keyfile\nbackend = events",
+			wantErr: "backend: events journals cannot be encrypted",
+		},
+		{
+			name:    "negative defer threshold",
+			input:   "defer_warn_threshold = -1",
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "non-numeric defer threshold",
+			input:   "defer_warn_threshold = many",
+			wantErr: "whole number",
+		},
+		{
+			name:    "unknown key",
+			input:   "colour = blue",
+			wantErr: `line 1: unknown key "colour"`,
+		},
+		{
+			name:    "missing separator",
+			input:   "# ok\nid_display",
+			wantErr: "line 2",
+		},
+		{
+			name:    "invalid display mode",
+			input:   "id_display = hex",
+			wantErr: "id_display",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWrite_RoundTrip verifies that written configuration parses back to the
+// same values.
+func TestWrite_RoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.IDDisplay = model.IDDisplaySequence
+	cfg.DeferWarnThreshold = 7
+	cfg.DataDir = `C:\Users\me\togo "data"`
+	cfg.Keymap = KeymapArrows
+	cfg.EmptyTodayNudge = 8*time.Hour + 5*time.Minute
+	cfg.UrgencyAge = 0.25
+	cfg.UrgencyTags = "next:15, someday:-3"
+
+	var buf bytes.Buffer
+	if err := cfg.Write(&buf); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "# Color theme") {
+		t.Errorf("expected written config to be commented, got:\n%s", buf.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "togo.conf")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got != cfg {
+		t.Errorf("round trip = %+v, want %+v", got, cfg)
+	}
+}
+
+// TestSave_CreatesDirectories verifies that Save creates missing parents and
+// produces a loadable file.
+func TestSave_CreatesDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "togo", "config")
+	cfg := Default()
+	cfg.Theme = ThemeLight
+
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got != cfg {
+		t.Errorf("Load() = %+v, want %+v", got, cfg)
+	}
+}
+
+// withDefaults returns Default() modified by fn.
+func withDefaults(fn func(*Config)) Config {
+	c := Default()
+	fn(&c)
+	return c
+}
+
+// TestConfig_NudgePolicy verifies conversion of reminder settings.
+func TestConfig_NudgePolicy(t *testing.T) {
+	cfg := Default()
+	cfg.ReviewIntervalDays = 3
+
+	got := cfg.NudgePolicy()
+	if got.ReviewInterval != 72*time.Hour || got.EmptyTodayAfter != 9*time.Hour {
+		t.Errorf("NudgePolicy() = %+v", got)
+	}
+}
+
+// TestConfig_UrgencyWeights verifies conversion of urgency settings,
+// including per-tag weights.
+func TestConfig_UrgencyWeights(t *testing.T) {
+	cfg := Default()
+	cfg.UrgencyTags = "next:15, someday:-3.5"
+
+	got := cfg.UrgencyWeights()
+	want := model.DefaultUrgencyWeights()
+	if got.Due != want.Due || got.Priority != want.Priority || got.Age != want.Age || got.Deferred != want.Deferred {
+		t.Errorf("UrgencyWeights() = %+v, want defaults %+v", got, want)
+	}
+	if len(got.Tags) != 2 || got.Tags["next"] != 15 || got.Tags["someday"] != -3.5 {
+		t.Errorf("UrgencyWeights().Tags = %v", got.Tags)
+	}
+}
+
+// TestConfig_Linker verifies ref URLs are keyed by lowercase system and
+// hyperlinks follow the terminal.
+func TestConfig_Linker(t *testing.T) {
+	cfg := Default()
+	cfg.RefURLs = "JIRA=https://example.atlassian.net/browse/%s"
+	getenv := func(key string) string {
+		return map[string]string{"TERM": "xterm-kitty"}[key]
+	}
+
+	got := cfg.Linker(getenv)
+	if !got.Enabled || got.RefURLs["jira"] != "https://example.atlassian.net/browse/%s" {
+		t.Errorf("Linker() = %+v", got)
+	}
+	if Default().Linker(func(string) string { return "" }).Enabled {
+		t.Error("Linker() enabled without a terminal")
+	}
+}
+
+// TestConfig_TitleOrder verifies titles sort as the collation settings
+// say for each journal.
+func TestConfig_TitleOrder(t *testing.T) {
+	tests := []struct {
+		collation  string
+		perJournal string
+		journal    string
+		want       int
+	}{
+		{collation: "", journal: "default", want: 1},
+		{collation: "de", journal: "default", want: -1},
+		{collation: "de", perJournal: "work=", journal: "work", want: 1},
+		{collation: "", perJournal: "work=de", journal: "work", want: -1},
+		{collation: "", perJournal: "work=de", journal: "home", want: 1},
+	}
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.Collation, cfg.JournalCollation = tt.collation, tt.perJournal
+		if got := cfg.TitleOrder(tt.journal).Compare("Äpfel", "Zucker"); got != tt.want {
+			t.Errorf("collation %q, %q in %s: Compare(Äpfel, Zucker) = %d, want %d", tt.collation, tt.perJournal, tt.journal, got, tt.want)
+		}
+	}
+}
+
+// TestConfig_Limits verifies conversion of size limit settings.
+func TestConfig_Limits(t *testing.T) {
+	if got := Default().Limits(); got != model.DefaultLimits() {
+		t.Errorf("Default().Limits() = %+v, want %+v", got, model.DefaultLimits())
+	}
+}
+
+// TestConfig_StaleThreshold verifies the default matches the model's.
+func TestConfig_StaleThreshold(t *testing.T) {
+	if got := Default().StaleThreshold(); got != model.DefaultStaleAfter {
+		t.Errorf("StaleThreshold() = %v, want %v", got, model.DefaultStaleAfter)
+	}
+}
+
+// TestConfig_JournalDir verifies an explicit data directory wins over the
+// platform default.
+func TestConfig_JournalDir(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", xdg)
+	cfg := Default()
+	if got, err := cfg.JournalDir(); err != nil || got != filepath.Join(xdg, "togo") {
+		t.Errorf("JournalDir() = %q, %v", got, err)
+	}
+	cfg.DataDir = "/home/me/Sync/togo"
+	if got, _ := cfg.JournalDir(); got != cfg.DataDir {
+		t.Errorf("JournalDir() = %q, want %q", got, cfg.DataDir)
+	}
+}
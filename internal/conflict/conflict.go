@@ -0,0 +1,270 @@
+// Package conflict reconciles copies of a task that diverged, such as a
+// sync tool's conflict copy of the journal or a save rejected as stale,
+// with model.MergeTasks, and keeps what the merge could not settle in a
+// sidecar file beside the journal until the user resolves it.
+//
+// The merged task provisionally holds the local value of each unresolved
+// field; resolving a field either confirms that or takes the remote one.
+package conflict
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"togo/internal/atomicfile"
+	"togo/internal/encryption"
+	"togo/internal/filelock"
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Ext is appended to the journal's path to name its sidecar.
+const Ext = ".conflict"
+
+// SidecarPath returns the path of the sidecar for the journal at path.
+func SidecarPath(journal string) string {
+	return journal + Ext
+}
+
+// Entry is a task whose merge left fields unresolved.
+type Entry struct {
+	ID    model.TaskID `json:"id"`
+	Title string       `json:"title"`
+	// Source says where the remote copy came from: a conflict copy's file
+	// name, or "save" for a save rejected as stale.
+	Source     string    `json:"source"`
+	DetectedAt time.Time `json:"detected_at"`
+	// Fields names the unresolved fields, as in model.Conflict.
+	Fields []string    `json:"fields"`
+	Local  *model.Task `json:"local"`
+	Remote *model.Task `json:"remote"`
+}
+
+// Changes returns the unresolved fields as changes from the local value
+// to the remote one, for display.
+func (e Entry) Changes() []model.FieldChange {
+	var out []model.FieldChange
+	for _, c := range e.Local.Diff(e.Remote) {
+		if slices.Contains(e.Fields, c.Field) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Side picks which copy's value settles a conflict.
+type Side int
+
+const (
+	// Local keeps the value the merge provisionally chose.
+	Local Side = iota
+	// Remote takes the other copy's value.
+	Remote
+)
+
+// Merge merges remote into local against base, their common ancestor if
+// known, and returns the merged task with an Entry for its unresolved
+// conflicts, or a nil Entry when there are none. The merged task is at a
+// version after both, so a copy of either is stale.
+func Merge(base, local, remote *model.Task, source string) (*model.Task, *Entry, error) {
+	merged, conflicts, err := model.MergeTasks(base, local, remote)
+	if err != nil {
+		return nil, nil, err
+	}
+	merged.Version = max(local.Version, remote.Version) + 1
+	var fields []string
+	for _, c := range conflicts {
+		if !c.Resolved {
+			fields = append(fields, c.Field)
+		}
+	}
+	if len(fields) == 0 {
+		return merged, nil, nil
+	}
+	return merged, &Entry{
+		ID:         local.ID,
+		Title:      merged.Title,
+		Source:     source,
+		DetectedAt: model.Now(),
+		Fields:     fields,
+		Local:      local.Clone(),
+		Remote:     remote.Clone(),
+	}, nil
+}
+
+// Save saves task to repo. If the save is rejected as stale, task is
+// merged, against base as it was read, with the stored task that another
+// writer saved meanwhile; the merge is saved instead and copied into
+// task, and its unresolved conflicts are added to sidecar.
+func Save(repo repository.TaskRepository, sidecar *Sidecar, base, task *model.Task) error {
+	err := repo.Save(task)
+	if !errors.Is(err, model.ErrStaleTask) {
+		return err
+	}
+	stored, err := repo.Get(task.ID)
+	if err != nil {
+		return err
+	}
+	merged, entry, err := Merge(base, task, stored, "save")
+	if err != nil {
+		return err
+	}
+	merged.Version = stored.Version
+	if err := repo.Save(merged); err != nil {
+		return err
+	}
+	*task = *merged
+	if entry == nil {
+		return nil
+	}
+	return sidecar.Add(*entry)
+}
+
+// Sidecar is the file listing a journal's unresolved conflicts. Its
+// methods take an advisory lock, so processes sharing the journal can
+// use it together.
+type Sidecar struct {
+	path string
+	// keyring encrypts the sidecar as the journal is, when set, since it
+	// holds copies of tasks.
+	keyring *encryption.Keyring
+}
+
+// Open returns the sidecar at path, encrypted with keyring if it is not
+// nil. Nothing is read until it is used, and a missing file holds no
+// conflicts.
+func Open(path string, keyring *encryption.Keyring) *Sidecar {
+	return &Sidecar{path: path, keyring: keyring}
+}
+
+// Path returns the sidecar's location.
+func (s *Sidecar) Path() string {
+	return s.path
+}
+
+// Entries returns the unresolved conflicts, oldest first.
+func (s *Sidecar) Entries() ([]Entry, error) {
+	lock, err := filelock.Acquire(s.lockPath(), filelock.Shared)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+	return s.read()
+}
+
+// Add records entries, replacing any recorded for the same tasks, whose
+// later merges supersede them.
+func (s *Sidecar) Add(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.update(func(current []Entry) []Entry {
+		for _, e := range entries {
+			current = slices.DeleteFunc(current, func(c Entry) bool { return c.ID == e.ID })
+			current = append(current, e)
+		}
+		return current
+	})
+}
+
+// Resolve settles the conflict on field of the task with the given ID by
+// keeping side's value, saving the task to repo if it changes, and drops
+// it from the sidecar. A task deleted since is dropped without saving.
+func (s *Sidecar) Resolve(repo repository.TaskRepository, id model.TaskID, field string, side Side) error {
+	entries, err := s.Entries()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id && slices.Contains(e.Fields, field) })
+	if i < 0 {
+		return &model.TaskError{ID: id, Op: "resolve", Err: fmt.Errorf("no conflict on %s", field)}
+	}
+	if side == Remote {
+		task, err := repo.Get(id)
+		switch {
+		case errors.Is(err, model.ErrTaskNotFound):
+		case err != nil:
+			return err
+		default:
+			task.TakeField(field, entries[i].Remote)
+			task.UpdatedAt = model.Now()
+			if err := repo.Save(task); err != nil {
+				return err
+			}
+		}
+	}
+	return s.update(func(current []Entry) []Entry {
+		for j := range current {
+			if current[j].ID == id {
+				current[j].Fields = slices.DeleteFunc(slices.Clone(current[j].Fields), func(f string) bool { return f == field })
+			}
+		}
+		return slices.DeleteFunc(current, func(e Entry) bool { return len(e.Fields) == 0 })
+	})
+}
+
+// update rewrites the sidecar with change applied to its entries, removing
+// the file once none are left.
+func (s *Sidecar) update(change func([]Entry) []Entry) error {
+	lock, err := filelock.Acquire(s.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries = change(entries)
+	if len(entries) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if s.keyring != nil {
+		if data, err = s.keyring.Encrypt(data); err != nil {
+			return err
+		}
+	}
+	return atomicfile.WriteFile(s.path, data)
+}
+
+// read decodes the sidecar. The lock must be held.
+func (s *Sidecar) read() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(data) {
+		if s.keyring == nil {
+			return nil, fmt.Errorf("%s: sidecar is encrypted but no key is configured", s.path)
+		}
+		if data, err = s.keyring.Decrypt(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.path, err)
+		}
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// lockPath returns the file locked while the sidecar is read or written.
+func (s *Sidecar) lockPath() string {
+	return s.path + ".lock"
+}
@@ -0,0 +1,138 @@
+package conflict
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestSave verifies a save rejected as stale is merged with the stored
+// task, and only a tie between the two edits is left for the user.
+func TestSave(t *testing.T) {
+	tests := []struct {
+		name string
+		// theirs and mine edit copies read at the same version; theirs is
+		// saved first.
+		theirs, mine func(*model.Task)
+		wantTitle    string
+		wantNotes    string
+		wantFields   []string
+	}{
+		{
+			name:      "different fields",
+			theirs:    func(t *model.Task) { t.Notes = "bring photos" },
+			mine:      func(t *model.Task) { t.Title = "Renew passports" },
+			wantTitle: "Renew passports",
+			wantNotes: "bring photos",
+		},
+		{
+			name:      "later edit wins",
+			theirs:    func(t *model.Task) { t.Title = "Theirs"; t.UpdatedAt = t.UpdatedAt.Add(time.Minute) },
+			mine:      func(t *model.Task) { t.Title = "Mine"; t.UpdatedAt = t.UpdatedAt.Add(time.Hour) },
+			wantTitle: "Mine",
+		},
+		{
+			name:       "tie",
+			theirs:     func(t *model.Task) { t.Title = "Theirs" },
+			mine:       func(t *model.Task) { t.Title = "Mine" },
+			wantTitle:  "Mine",
+			wantFields: []string{"title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			sidecar := Open(filepath.Join(t.TempDir(), "tasks.json"+Ext), nil)
+			task := testutil.NewTask().WithTitle("Renew passport").Build()
+			testutil.MustSeed(t, repo, task)
+			base := task.Clone()
+			theirs, mine := task.Clone(), task.Clone()
+			tt.theirs(theirs)
+			if err := repo.Save(theirs); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.mine(mine)
+			if err := Save(repo, sidecar, base, mine); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+			got, err := repo.Get(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != tt.wantTitle || got.Notes != tt.wantNotes || got.Version != mine.Version {
+				t.Errorf("stored %q, %q at version %d; want %q, %q at %d", got.Title, got.Notes, got.Version, tt.wantTitle, tt.wantNotes, mine.Version)
+			}
+			entries, err := sidecar.Entries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields []string
+			for _, e := range entries {
+				fields = append(fields, e.Fields...)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("sidecar holds conflicts on %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+// TestSidecar_Resolve verifies each side of a conflict can be kept, and
+// the sidecar is removed once nothing is left to resolve.
+func TestSidecar_Resolve(t *testing.T) {
+	tests := []struct {
+		side      Side
+		wantTitle string
+	}{
+		{side: Local, wantTitle: "Mine"},
+		{side: Remote, wantTitle: "Theirs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantTitle, func(t *testing.T) {
+			repo := memstore.New()
+			sidecar := Open(filepath.Join(t.TempDir(), "tasks.json"+Ext), nil)
+			mine := testutil.NewTask().WithTitle("Mine").WithNotes("from the phone").Build()
+			theirs := mine.Clone()
+			theirs.Title, theirs.Notes = "Theirs", "from the laptop"
+			merged, entry, err := Merge(nil, mine, theirs, "tasks.sync-conflict-20240615-101500-ABCDEFG.json")
+			if err != nil || entry == nil {
+				t.Fatalf("Merge() = %v, %v; want a conflict", entry, err)
+			}
+			if !slices.Equal(entry.Fields, []string{"title", "notes"}) {
+				t.Fatalf("Merge() left %v unresolved, want title and notes", entry.Fields)
+			}
+			testutil.MustSeed(t, repo, merged)
+			if err := sidecar.Add(*entry); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := sidecar.Resolve(repo, mine.ID, "title", tt.side); err != nil {
+				t.Fatalf("Resolve() error: %v", err)
+			}
+			if got, _ := repo.Get(mine.ID); got.Title != tt.wantTitle {
+				t.Errorf("title after Resolve = %q, want %q", got.Title, tt.wantTitle)
+			}
+			entries, err := sidecar.Entries()
+			if err != nil || len(entries) != 1 || !slices.Equal(entries[0].Fields, []string{"notes"}) {
+				t.Fatalf("Entries() after Resolve = %v, %v; want only notes left", entries, err)
+			}
+			if err := sidecar.Resolve(repo, mine.ID, "title", tt.side); err == nil {
+				t.Error("resolving the same conflict twice succeeded")
+			}
+			if err := sidecar.Resolve(repo, mine.ID, "notes", tt.side); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(sidecar.Path()); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("sidecar left behind after resolving everything: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package display fits task data into the space a view has for it, so that
+// oversized titles, notes or tag lists degrade gracefully instead of
+// wrapping across the screen.
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Ellipsis marks truncated text.
+const Ellipsis = "…"
+
+// Truncate shortens s to at most width terminal cells, ending it with
+// Ellipsis when anything was cut. It never splits a grapheme cluster and
+// counts wide characters as two cells. Line breaks are shown as spaces.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+	if uniseg.StringWidth(s) <= width {
+		return s
+	}
+
+	limit := width - uniseg.StringWidth(Ellipsis)
+	var b strings.Builder
+	used := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		w := g.Width()
+		if used+w > limit {
+			break
+		}
+		b.WriteString(g.Str())
+		used += w
+	}
+	return b.String() + Ellipsis
+}
+
+// Pad fits s to exactly width terminal cells, truncating it as Truncate
+// does or filling it with spaces, so columns of text line up.
+func Pad(s string, width int) string {
+	s = Truncate(s, width)
+	return s + strings.Repeat(" ", max(width-uniseg.StringWidth(s), 0))
+}
+
+// FirstLine returns the first line of s, truncated to width, for previews
+// of multi-line notes. An ellipsis also marks dropped lines.
+func FirstLine(s string, width int) string {
+	first, rest, more := strings.Cut(strings.TrimSpace(s), "\n")
+	first = strings.TrimRight(first, "\r")
+	if !more || strings.TrimSpace(rest) == "" {
+		return Truncate(first, width)
+	}
+	if uniseg.StringWidth(first)+uniseg.StringWidth(Ellipsis) <= width {
+		return first + Ellipsis
+	}
+	return Truncate(first, width)
+}
+
+// Tags renders tags as "#a #b" within width cells, replacing those that do
+// not fit with a "+N" count so a task with thousands of tags still renders
+// on one line.
+func Tags(tags []string, width int) string {
+	var b strings.Builder
+	for i, tag := range tags {
+		item := "#" + tag
+		if i > 0 {
+			item = " " + item
+		}
+		remaining := len(tags) - i - 1
+		reserve := 0
+		if remaining > 0 {
+			reserve = len(fmt.Sprintf(" +%d", remaining))
+		}
+		if uniseg.StringWidth(b.String()+item)+reserve > width {
+			more := fmt.Sprintf("+%d", len(tags)-i)
+			if i > 0 {
+				more = " " + more
+			}
+			if uniseg.StringWidth(b.String()+more) > width {
+				return Truncate(b.String(), width)
+			}
+			return b.String() + more
+		}
+		b.WriteString(item)
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/uniseg"
+)
+
+// TestTruncate verifies width-aware truncation that never splits clusters.
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "Buy milk", 8, "Buy milk"},
+		{"cut with ellipsis", "Buy milk and eggs", 8, "Buy mil…"},
+		{"wide characters", "日本語のタイトル", 7, "日本語…"},
+		{"no split cluster", "ab👩‍💻cd", 4, "ab…"},
+		{"line breaks flattened", "one\ntwo", 20, "one two"},
+		{"zero width", "anything", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.width)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+			if w := uniseg.StringWidth(got); w > tt.width {
+				t.Errorf("result is %d cells wide, limit %d", w, tt.width)
+			}
+		})
+	}
+}
+
+// TestTruncate_HugeInput verifies megabyte strings are cut to the width.
+func TestTruncate_HugeInput(t *testing.T) {
+	got := Truncate(strings.Repeat("x", 1<<20), 40)
+	if uniseg.StringWidth(got) != 40 || !strings.HasSuffix(got, Ellipsis) {
+		t.Errorf("Truncate() = %q", got)
+	}
+}
+
+// TestPad verifies text is cut or filled to exactly the width.
+func TestPad(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"filled", "Buy milk", 10, "Buy milk  "},
+		{"exact", "Buy milk", 8, "Buy milk"},
+		{"cut", "Buy milk and eggs", 8, "Buy mil…"},
+		{"wide characters", "日本語", 8, "日本語  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pad(tt.input, tt.width); got != tt.want {
+				t.Errorf("Pad(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFirstLine verifies multi-line notes preview as one line.
+func TestFirstLine(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"single", 20, "single"},
+		{"first\nsecond", 20, "first…"},
+		{"first\r\n\n", 20, "first"},
+		{"a long first line\nmore", 8, "a long …"},
+	}
+
+	for _, tt := range tests {
+		if got := FirstLine(tt.input, tt.width); got != tt.want {
+			t.Errorf("FirstLine(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestTags verifies tags that do not fit collapse into a count.
+func TestTags(t *testing.T) {
+	many := make([]string, 1000)
+	for i := range many {
+		many[i] = "tag"
+	}
+
+	tests := []struct {
+		name  string
+		tags  []string
+		width int
+		want  string
+	}{
+		{"all fit", []string{"work", "home"}, 20, "#work #home"},
+		{"count replaces rest", []string{"work", "home", "errands"}, 13, "#work +2"},
+		{"thousands", many, 20, "#tag #tag #tag +997"},
+		{"nothing fits", []string{"verylongtag"}, 5, "+1"},
+		{"none", nil, 10, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tags(tt.tags, tt.width)
+			if got != tt.want {
+				t.Errorf("Tags() = %q, want %q", got, tt.want)
+			}
+			if w := uniseg.StringWidth(got); w > tt.width {
+				t.Errorf("result is %d cells wide, limit %d", w, tt.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package doctor checks stored tasks for damage that loading a journal
+// would refuse or hide, such as from hand edits, sync conflicts or bugs,
+// and repairs what it safely can.
+//
+// Tasks have no parent or dependency links; the one reference a task
+// holds is its notes file, which is checked against the notes directory.
+package doctor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"togo/internal/model"
+	"togo/internal/notestore"
+)
+
+// Kind classifies a Problem.
+type Kind string
+
+const (
+	// Invalid is a task failing Task.Validate, such as one with a status
+	// togo does not know.
+	Invalid Kind = "invalid"
+	// DuplicateID is a task sharing its ID with an earlier one.
+	DuplicateID Kind = "duplicate-id"
+	// DuplicateSeq is a task sharing its display number with another.
+	DuplicateSeq Kind = "duplicate-seq"
+	// MissingNotes is a task whose notes file does not exist.
+	MissingNotes Kind = "missing-notes"
+)
+
+// Problem is one thing wrong with a task.
+type Problem struct {
+	// Index is the task's position in the checked slice.
+	Index int
+	ID    model.TaskID
+	Title string
+	Kind  Kind
+	// Detail says what is wrong.
+	Detail string
+	// Fix says what Repair would do, or is empty when the problem must be
+	// fixed by hand.
+	Fix string
+
+	// repair applies the fix to a copy of the task, reporting whether to
+	// keep it.
+	repair func(t *model.Task) (keep bool)
+}
+
+// Fixable reports whether Repair can fix p.
+func (p Problem) Fixable() bool {
+	return p.repair != nil
+}
+
+// String describes p on one line.
+func (p Problem) String() string {
+	return fmt.Sprintf("task %d (%s %q): %s: %s", p.Index+1, p.ID.Short(), p.Title, p.Kind, p.Detail)
+}
+
+// Check returns the problems with tasks, as stored, in task order. notes
+// is the store their notes files live in, or nil to skip that check.
+func Check(tasks []*model.Task, notes *notestore.Store) ([]Problem, error) {
+	var problems []Problem
+	add := func(i int, kind Kind, detail, fix string, repair func(*model.Task) bool) {
+		problems = append(problems, Problem{
+			Index: i, ID: tasks[i].ID, Title: tasks[i].Title,
+			Kind: kind, Detail: detail, Fix: fix, repair: repair,
+		})
+	}
+
+	missing := map[model.TaskID]bool{}
+	if notes != nil {
+		report, err := notes.Check(tasks)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range report.Missing {
+			missing[id] = true
+		}
+	}
+
+	firstID := map[model.TaskID]int{}
+	seqOwner := map[int]int{}
+	for i, t := range tasks {
+		if !t.ID.IsEmpty() {
+			if first, dup := firstID[t.ID]; !dup {
+				firstID[t.ID] = i
+			} else if identical(tasks[first], t) {
+				// Whatever else is wrong with the copy is reported for the
+				// original.
+				add(i, DuplicateID, fmt.Sprintf("identical copy of task %d", first+1), "remove the copy", func(*model.Task) bool { return false })
+				continue
+			} else {
+				add(i, DuplicateID, fmt.Sprintf("same ID as task %d", first+1), "give it a new ID", func(t *model.Task) bool {
+					t.ID = model.NewTaskID()
+					return true
+				})
+			}
+		}
+
+		var errs model.ValidationErrors
+		if errors.As(t.Validate(), &errs) {
+			for _, e := range errs {
+				fix, repair := fieldFix(t, e.Field)
+				add(i, Invalid, e.Field+" "+e.Reason, fix, repair)
+			}
+		}
+
+		if t.Seq > 0 {
+			if owner, dup := seqOwner[t.Seq]; !dup {
+				seqOwner[t.Seq] = i
+			} else {
+				add(i, DuplicateSeq, fmt.Sprintf("#%d is also task %d's number", t.Seq, owner+1), "give it a new number", func(t *model.Task) bool {
+					t.Seq = 0
+					return true
+				})
+			}
+		}
+
+		if t.NotesFile != "" && missing[t.ID] {
+			add(i, MissingNotes, "notes file "+t.NotesFile+" does not exist", "drop the reference", func(t *model.Task) bool {
+				t.NotesFile = ""
+				return true
+			})
+		}
+	}
+	return problems, nil
+}
+
+// fieldFix returns how to repair a task failing validation on field, or
+// nothing when the task's content is at fault and only the user can say
+// what it should be.
+func fieldFix(t *model.Task, field string) (string, func(*model.Task) bool) {
+	switch field {
+	case "id":
+		return "give it a new ID", func(t *model.Task) bool {
+			t.ID = model.NewTaskID()
+			return true
+		}
+	case "created_at":
+		return "set it to when the task was last updated", func(t *model.Task) bool {
+			t.CreatedAt = t.UpdatedAt
+			if t.CreatedAt.IsZero() {
+				t.CreatedAt = model.Now()
+			}
+			return true
+		}
+	case "status":
+		return "move the task to the pool", func(t *model.Task) bool {
+			t.Status = model.StatusPool
+			t.CompletedAt = nil
+			return true
+		}
+	case "title":
+		if strings.TrimSpace(t.Title) == "" {
+			return `title it "(untitled)"`, func(t *model.Task) bool {
+				t.Title = "(untitled)"
+				return true
+			}
+		}
+	case "deferred_count":
+		return "reset it to 0", func(t *model.Task) bool {
+			t.DeferredCount = 0
+			return true
+		}
+	case "notes_file":
+		return "drop the reference", func(t *model.Task) bool {
+			t.NotesFile = ""
+			return true
+		}
+	case "priority":
+		return "clear it", func(t *model.Task) bool {
+			t.Priority = ""
+			return true
+		}
+	case "energy":
+		return "clear it", func(t *model.Task) bool {
+			t.Energy = ""
+			return true
+		}
+	case "completed_at":
+		if t.Status == model.StatusDone {
+			return "set it to when the task was last updated", func(t *model.Task) bool {
+				completed := t.UpdatedAt
+				if completed.IsZero() {
+					completed = t.CreatedAt
+				}
+				t.CompletedAt = &completed
+				return true
+			}
+		}
+		return "clear it", func(t *model.Task) bool {
+			t.CompletedAt = nil
+			return true
+		}
+	}
+	return "", nil
+}
+
+// Repair returns tasks with the fixes for problems applied, leaving tasks
+// untouched. problems must come from Check on the same tasks; pass a
+// subset to apply only those fixes. Tasks losing a duplicate display
+// number get new ones after the highest in use.
+func Repair(tasks []*model.Task, problems []Problem) []*model.Task {
+	fixed := make([]*model.Task, len(tasks))
+	for i, t := range tasks {
+		fixed[i] = t.Clone()
+	}
+	keep := make([]bool, len(tasks))
+	for i := range keep {
+		keep[i] = true
+	}
+	var renumber []*model.Task
+	for _, p := range problems {
+		if p.repair == nil || !keep[p.Index] {
+			continue
+		}
+		keep[p.Index] = p.repair(fixed[p.Index])
+		if p.Kind == DuplicateSeq {
+			renumber = append(renumber, fixed[p.Index])
+		}
+	}
+
+	var out []*model.Task
+	last := 0
+	for i, t := range fixed {
+		if keep[i] {
+			out = append(out, t)
+			last = max(last, t.Seq)
+		}
+	}
+	seq := model.NewSequenceAllocator(last)
+	for _, t := range renumber {
+		t.Seq = seq.Next()
+	}
+	return out
+}
+
+// identical reports whether a and b hold the same data.
+func identical(a, b *model.Task) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
@@ -0,0 +1,169 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/notestore"
+	"togo/internal/testutil"
+)
+
+func kinds(problems []Problem) []Kind {
+	var out []Kind
+	for _, p := range problems {
+		out = append(out, p.Kind)
+	}
+	return out
+}
+
+// TestCheck verifies each kind of damage is reported, and that Repair
+// leaves tasks a journal would load.
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	present := testutil.NewTask().Build()
+	present.NotesFile = model.NotesFileName(present.ID)
+	if err := os.WriteFile(filepath.Join(dir, present.NotesFile), []byte("notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	notes := notestore.New(dir, 0)
+
+	tests := []struct {
+		name  string
+		tasks func() []*model.Task
+		want  []Kind
+		// left is how many tasks remain after Repair.
+		left int
+	}{
+		{
+			name:  "healthy",
+			tasks: func() []*model.Task { return testutil.Tasks(testutil.NewTask(), testutil.NewTask()) },
+			left:  2,
+		},
+		{
+			name: "unknown status",
+			tasks: func() []*model.Task {
+				task := testutil.NewTask().Build()
+				task.Status = "archived"
+				return []*model.Task{task}
+			},
+			want: []Kind{Invalid},
+			left: 1,
+		},
+		{
+			name: "done without completion time",
+			tasks: func() []*model.Task {
+				task := testutil.NewTask().Build()
+				task.Status = model.StatusDone
+				task.DeferredCount = -1
+				return []*model.Task{task}
+			},
+			want: []Kind{Invalid, Invalid},
+			left: 1,
+		},
+		{
+			name: "identical copy",
+			tasks: func() []*model.Task {
+				task := testutil.NewTask().Build()
+				task.Seq = 4
+				return []*model.Task{task, task.Clone()}
+			},
+			want: []Kind{DuplicateID},
+			left: 1,
+		},
+		{
+			name: "conflicting copies",
+			tasks: func() []*model.Task {
+				task := testutil.NewTask().Build()
+				task.Seq = 4
+				edited := task.Clone()
+				edited.Title = "Edited elsewhere"
+				return []*model.Task{task, edited}
+			},
+			want: []Kind{DuplicateID, DuplicateSeq},
+			left: 2,
+		},
+		{
+			name: "notes files",
+			tasks: func() []*model.Task {
+				missing := testutil.NewTask().Build()
+				missing.NotesFile = model.NotesFileName(missing.ID)
+				return []*model.Task{present.Clone(), missing}
+			},
+			want: []Kind{MissingNotes},
+			left: 2,
+		},
+		{
+			name: "foreign notes file",
+			tasks: func() []*model.Task {
+				task := testutil.NewTask().Build()
+				task.NotesFile = "../../secrets.txt"
+				return []*model.Task{task}
+			},
+			want: []Kind{Invalid},
+			left: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := tt.tasks()
+			problems, err := Check(tasks, notes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := kinds(problems); !slices.Equal(got, tt.want) {
+				t.Fatalf("Check() kinds = %v, want %v (%v)", got, tt.want, problems)
+			}
+			for _, p := range problems {
+				if !p.Fixable() {
+					t.Errorf("%v is not fixable", p)
+				}
+			}
+
+			fixed := Repair(tasks, problems)
+			if len(fixed) != tt.left {
+				t.Fatalf("Repair() left %d tasks, want %d", len(fixed), tt.left)
+			}
+			if again, err := Check(fixed, notes); err != nil || len(again) > 0 {
+				t.Errorf("Check() after Repair = %v, %v; want no problems", again, err)
+			}
+		})
+	}
+}
+
+// TestRepair_Subset verifies only the chosen fixes are applied, and the
+// checked tasks are left alone.
+func TestRepair_Subset(t *testing.T) {
+	a, b := testutil.NewTask().Build(), testutil.NewTask().Build()
+	a.Priority, b.Priority = "urgent", "someday"
+	tasks := []*model.Task{a, b}
+	problems, err := Check(tasks, nil)
+	if err != nil || len(problems) != 2 {
+		t.Fatalf("Check() = %v, %v; want 2 problems", problems, err)
+	}
+
+	fixed := Repair(tasks, problems[1:])
+	if fixed[0].Priority != "urgent" || fixed[1].Priority != "" {
+		t.Errorf("priorities after Repair = %q, %q; want urgent, empty", fixed[0].Priority, fixed[1].Priority)
+	}
+	if b.Priority != "someday" {
+		t.Errorf("Repair changed its input")
+	}
+}
+
+// TestCheck_HandFixes verifies problems only the user can settle are
+// reported without a fix.
+func TestCheck_HandFixes(t *testing.T) {
+	task := testutil.NewTask().Build()
+	task.Title = strings.Repeat("x", model.DefaultMaxTitleLength+1)
+	problems, err := Check([]*model.Task{task}, nil)
+	if err != nil || len(problems) != 1 {
+		t.Fatalf("Check() = %v, %v; want 1 problem", problems, err)
+	}
+	if problems[0].Fixable() || problems[0].Fix != "" {
+		t.Errorf("%v offers fix %q, want none", problems[0], problems[0].Fix)
+	}
+}
@@ -0,0 +1,132 @@
+// Package editlock shares a journal's advisory edit locks between the
+// processes using it, in a file beside the journal, so a TUI can show that
+// another is changing a task rather than silently overwrite its changes.
+package editlock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"togo/internal/atomicfile"
+	"togo/internal/filelock"
+	"togo/internal/model"
+)
+
+// Ext is appended to the journal's path to name its lock table.
+const Ext = ".locks"
+
+// Path returns the path of the lock table for the journal at path.
+func Path(journal string) string {
+	return journal + Ext
+}
+
+// Device names this process to other devices in lock notices: the host
+// name and process ID, since two TUIs may run on one machine.
+func Device() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "another device"
+	}
+	return fmt.Sprintf("%s (pid %d)", host, os.Getpid())
+}
+
+// Table is the file holding a journal's edit locks. Its methods take an
+// advisory lock, so processes sharing the journal can use it together.
+type Table struct {
+	path  string
+	lease time.Duration
+}
+
+// Open returns the table at path, whose locks last lease unless renewed;
+// a non-positive lease uses model.DefaultEditLease. Nothing is read until
+// it is used, and a missing file holds no locks.
+func Open(path string, lease time.Duration) *Table {
+	return &Table{path: path, lease: lease}
+}
+
+// Claim makes the task with the given ID the only one device holds the
+// lock on, taking or renewing it, and returns the locks other devices
+// hold, by task. A zero ID releases the device's lock. A task another
+// device holds is left to it, and listed with the others.
+func (t *Table) Claim(id model.TaskID, device string) (map[model.TaskID]model.EditLock, error) {
+	others := make(map[model.TaskID]model.EditLock)
+	err := t.update(func(locks *model.EditLocks) {
+		for _, held := range locks.Held() {
+			if held.Device == device && held.TaskID != id {
+				locks.Release(held.TaskID, device)
+			}
+		}
+		if !id.IsEmpty() {
+			locks.Acquire(id, device)
+		}
+		for _, held := range locks.Held() {
+			if held.Device != device {
+				others[held.TaskID] = held
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return others, nil
+}
+
+// Release drops every lock device holds.
+func (t *Table) Release(device string) error {
+	_, err := t.Claim(model.TaskID{}, device)
+	return err
+}
+
+// update rewrites the table with change applied to its unexpired locks,
+// removing the file once none are left.
+func (t *Table) update(change func(locks *model.EditLocks)) error {
+	lock, err := filelock.Acquire(t.path+".lock", filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	held, err := t.read()
+	if err != nil {
+		return err
+	}
+	locks := model.NewEditLocks(t.lease)
+	locks.Restore(held)
+	change(locks)
+
+	after := locks.Held()
+	if slices.Equal(after, held) {
+		return nil
+	}
+	if len(after) == 0 {
+		if err := os.Remove(t.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(after, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(t.path, append(data, '\n'))
+}
+
+// read decodes the table. The lock must be held.
+func (t *Table) read() ([]model.EditLock, error) {
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var locks []model.EditLock
+	if err := json.Unmarshal(data, &locks); err != nil {
+		return nil, fmt.Errorf("%s: %w", t.path, err)
+	}
+	return locks, nil
+}
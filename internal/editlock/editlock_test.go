@@ -0,0 +1,66 @@
+package editlock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// TestTable_Claim verifies that claims made through separate tables on one
+// file see each other, and that a device holds one task at a time.
+func TestTable_Claim(t *testing.T) {
+	clk := model.NewFixedClock(time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC))
+	defer model.SetClock(clk)()
+	path := Path(filepath.Join(t.TempDir(), "tasks.json"))
+	laptop, desktop := Open(path, time.Minute), Open(path, time.Minute)
+	taxes, milk := model.NewTaskID(), model.NewTaskID()
+
+	tests := []struct {
+		name   string
+		table  *Table
+		device string
+		id     model.TaskID
+		// want lists the tasks other devices hold after the claim, and
+		// the devices holding them.
+		want map[model.TaskID]string
+	}{
+		{name: "first claim", table: laptop, device: "laptop", id: taxes, want: map[model.TaskID]string{}},
+		{name: "held elsewhere", table: desktop, device: "desktop", id: taxes, want: map[model.TaskID]string{taxes: "laptop"}},
+		{name: "move on", table: laptop, device: "laptop", id: milk, want: map[model.TaskID]string{}},
+		{name: "taken after release", table: desktop, device: "desktop", id: taxes, want: map[model.TaskID]string{milk: "laptop"}},
+		{name: "release", table: laptop, device: "laptop", want: map[model.TaskID]string{taxes: "desktop"}},
+	}
+	for _, tt := range tests {
+		others, err := tt.table.Claim(tt.id, tt.device)
+		if err != nil {
+			t.Fatalf("%s: Claim() error = %v", tt.name, err)
+		}
+		got := make(map[model.TaskID]string)
+		for id, lock := range others {
+			got[id] = lock.Device
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: others = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for id, device := range tt.want {
+			if got[id] != device {
+				t.Errorf("%s: others = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+
+	clk.Advance(time.Minute)
+	if others, err := laptop.Claim(taxes, "laptop"); err != nil || len(others) != 0 {
+		t.Errorf("Claim() after the lease = %v, %v; want the expired lock taken over", others, err)
+	}
+	if err := laptop.Release("laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("table left behind with no locks: %v", err)
+	}
+}
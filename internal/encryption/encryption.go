@@ -0,0 +1,137 @@
+// Package encryption protects the journal at rest with age
+// (https://age-encryption.org), keyed either by a passphrase or by an age
+// key file, for users who keep work tasks on shared machines.
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+)
+
+// header starts every age-encrypted file.
+var header = []byte("age-encryption.org/v1\n")
+
+// ErrWrongKey is returned when data was encrypted with a different key.
+var ErrWrongKey = errors.New("encryption: data was encrypted with a different key or passphrase")
+
+// scryptWorkFactor is the scrypt cost used for passphrases; tests lower it.
+var scryptWorkFactor = 18
+
+// Keyring encrypts and decrypts with one key.
+type Keyring struct {
+	recipient age.Recipient
+	identity  age.Identity
+}
+
+// Passphrase returns a keyring deriving its key from passphrase.
+func Passphrase(passphrase string) (*Keyring, error) {
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	r.SetWorkFactor(scryptWorkFactor)
+	id, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	return &Keyring{recipient: r, identity: id}, nil
+}
+
+// LoadKeyFile returns a keyring using the first X25519 identity in the age
+// key file at path, as written by age-keygen or GenerateKeyFile.
+func LoadKeyFile(path string) (*Keyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ids, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, id := range ids {
+		if x, ok := id.(*age.X25519Identity); ok {
+			return &Keyring{recipient: x.Recipient(), identity: x}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: no X25519 identity", path)
+}
+
+// GenerateKeyFile writes a new age key file to path, readable only by its
+// owner. It refuses to overwrite an existing file, since that would lose
+// the only key to data encrypted with it.
+func GenerateKeyFile(path string) error {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "# public key: %s\n%s\n", id.Recipient(), id)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Encrypt returns plaintext encrypted for the keyring.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, k.recipient)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt returns the plaintext of data produced by Encrypt. It returns
+// ErrWrongKey if the keyring cannot open data.
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	r, err := k.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NewReader returns a reader of the plaintext of src, which holds the
+// output of Encrypt, decrypting it as it is read so large files need not
+// be held in memory. It returns ErrWrongKey if the keyring cannot open
+// src.
+func (k *Keyring) NewReader(src io.Reader) (io.Reader, error) {
+	r, err := age.Decrypt(src, k.identity)
+	var noMatch *age.NoIdentityMatchError
+	if errors.As(err, &noMatch) {
+		return nil, ErrWrongKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	return r, nil
+}
+
+// IsEncrypted reports whether data looks like the output of Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
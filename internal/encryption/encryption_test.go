@@ -0,0 +1,83 @@
+package encryption
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func init() {
+	// Keep passphrase tests fast; real use keeps the default cost.
+	scryptWorkFactor = 10
+}
+
+func keyFile(t *testing.T) *Keyring {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys", "togo.key")
+	if err := GenerateKeyFile(path); err != nil {
+		t.Fatalf("GenerateKeyFile() error: %v", err)
+	}
+	k, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyFile() error: %v", err)
+	}
+	return k
+}
+
+// TestKeyring_RoundTrip verifies both kinds of key decrypt what they
+// encrypt, and refuse data sealed with another key.
+func TestKeyring_RoundTrip(t *testing.T) {
+	pass, err := Passphrase("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPass, _ := Passphrase("battery staple")
+
+	tests := []struct {
+		name       string
+		key, other *Keyring
+	}{
+		{"passphrase", pass, otherPass},
+		{"key file", keyFile(t), keyFile(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext := []byte(`{"version": 1, "tasks": []}`)
+			sealed, err := tt.key.Encrypt(plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt() error: %v", err)
+			}
+			if !IsEncrypted(sealed) || IsEncrypted(plaintext) {
+				t.Error("IsEncrypted() misclassified the data")
+			}
+
+			got, err := tt.key.Decrypt(sealed)
+			if err != nil || string(got) != string(plaintext) {
+				t.Errorf("Decrypt() = %q, %v", got, err)
+			}
+			if _, err := tt.other.Decrypt(sealed); !errors.Is(err, ErrWrongKey) {
+				t.Errorf("Decrypt() with another key error = %v, want ErrWrongKey", err)
+			}
+		})
+	}
+}
+
+// TestGenerateKeyFile_KeepsExisting verifies an existing key is never
+// overwritten.
+func TestGenerateKeyFile_KeepsExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "togo.key")
+	if err := GenerateKeyFile(path); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+	if err := GenerateKeyFile(path); !errors.Is(err, os.ErrExist) {
+		t.Errorf("second GenerateKeyFile() error = %v, want ErrExist", err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("key file was overwritten")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("key file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,58 @@
+// Package filelock provides advisory locks between processes sharing a
+// file, such as the TUI and a CLI command writing the same journal.
+//
+// Locks are taken on a separate lock file rather than the data file itself,
+// because atomic writes replace the data file and with it any lock held on
+// the old one.
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Mode says whether a lock may be shared with other holders.
+type Mode int
+
+const (
+	// Shared locks may be held by many readers at once.
+	Shared Mode = iota
+	// Exclusive locks exclude every other holder, shared or exclusive.
+	Exclusive
+)
+
+// Lock is a held lock. Release it when done.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire blocks until it holds a lock of the given mode on path, creating
+// the file and its directory if needed.
+//
+// Locks are advisory: they only exclude other Acquire calls, and on Unix
+// they are owned by the open file, so two Acquire calls in one process
+// exclude each other just as two processes would.
+func Acquire(path string, mode Mode) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lock(f, mode); err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "lock", Path: path, Err: err}
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release gives up the lock. The lock file is left in place: removing it
+// would let a waiter lock a file no one else can see.
+func (l *Lock) Release() error {
+	err := unlock(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// acquireAsync starts Acquire in the background and reports the lock once
+// it is held.
+func acquireAsync(t *testing.T, path string, mode Mode) <-chan *Lock {
+	t.Helper()
+	got := make(chan *Lock, 1)
+	go func() {
+		l, err := Acquire(path, mode)
+		if err != nil {
+			t.Error(err)
+		}
+		got <- l
+	}()
+	return got
+}
+
+// TestAcquire verifies which lock modes exclude each other.
+func TestAcquire(t *testing.T) {
+	tests := []struct {
+		name        string
+		held, want  Mode
+		wantBlocked bool
+	}{
+		{"shared with shared", Shared, Shared, false},
+		{"shared blocks exclusive", Shared, Exclusive, true},
+		{"exclusive blocks shared", Exclusive, Shared, true},
+		{"exclusive blocks exclusive", Exclusive, Exclusive, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "nested", "tasks.lock")
+			held, err := Acquire(path, tt.held)
+			if err != nil {
+				t.Fatalf("Acquire() error: %v", err)
+			}
+
+			got := acquireAsync(t, path, tt.want)
+			var second *Lock
+			select {
+			case second = <-got:
+				if tt.wantBlocked {
+					t.Error("second lock acquired while the first was held")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if !tt.wantBlocked {
+					t.Error("second lock blocked")
+				}
+			}
+
+			if err := held.Release(); err != nil {
+				t.Fatalf("Release() error: %v", err)
+			}
+			if second == nil {
+				select {
+				case second = <-got:
+				case <-time.After(5 * time.Second):
+					t.Fatal("second lock not acquired after release")
+				}
+			}
+			if second != nil {
+				second.Release()
+			}
+		})
+	}
+}
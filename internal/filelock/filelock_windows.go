@@ -0,0 +1,26 @@
+//go:build windows
+
+package filelock
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// allBytes locks the whole file, however large it grows.
+const allBytes = ^uint32(0)
+
+func lock(f *os.File, mode Mode) error {
+	var flags uint32
+	if mode == Exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	var ol windows.Overlapped
+	return windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, allBytes, allBytes, &ol)
+}
+
+func unlock(f *os.File) error {
+	var ol windows.Overlapped
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, allBytes, allBytes, &ol)
+}
@@ -0,0 +1,182 @@
+// Package hooks runs the user's executables when tasks change, so togo can
+// be wired into other automation without changing it. A hook is a file in
+// the hooks directory named for an event, such as hooks/on-complete; it
+// gets the task as JSON on stdin and the event's name in $TOGO_EVENT.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"togo/internal/service"
+)
+
+// DirName is the hooks directory's name beside the configuration file.
+const DirName = "hooks"
+
+// DefaultTimeout bounds how long a hook may run before it is killed.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultDir returns the hooks directory for the configuration file at
+// configPath.
+func DefaultDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), DirName)
+}
+
+// names maps each event to the hook run for it.
+var names = map[service.EventType]string{
+	service.TaskCreated:      "on-create",
+	service.TaskCompleted:    "on-complete",
+	service.TaskDeferred:     "on-defer",
+	service.TaskMovedToToday: "on-today",
+	service.TaskEdited:       "on-edit",
+	service.TaskDeleted:      "on-delete",
+}
+
+// Name returns the file name of the hook run for events of type t.
+func Name(t service.EventType) string {
+	return names[t]
+}
+
+// Runner runs the hooks in a directory for the events it handles, one at
+// a time in the order the events happened, without holding up the use
+// case that published them: events wait in a queue as long as the hooks
+// take.
+type Runner struct {
+	dir     string
+	timeout time.Duration
+	// wake tells the worker that queue grew or the runner closed.
+	wake chan struct{}
+	done chan struct{}
+
+	mu     sync.Mutex
+	queue  []service.Event
+	closed bool
+	errs   []error
+}
+
+// New returns a runner for the hooks in dir, each allowed timeout to run.
+// Call Close once no more events will be handled.
+func New(dir string, timeout time.Duration) *Runner {
+	r := &Runner{
+		dir:     dir,
+		timeout: timeout,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go r.work()
+	return r
+}
+
+// Handle queues the hook for e, if the user has one; subscribe it to a
+// service.Bus.
+func (r *Runner) Handle(e service.Event) {
+	name := Name(e.Type)
+	if name == "" {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(r.dir, name)); errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.queue = append(r.queue, e)
+	r.signal()
+}
+
+// Close waits for the queued hooks to finish and returns their failures.
+func (r *Runner) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.signal()
+	r.mu.Unlock()
+	<-r.done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(r.errs...)
+}
+
+// signal wakes the worker unless it is already due to wake. r.mu must be
+// held.
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// work runs queued hooks until Close.
+func (r *Runner) work() {
+	defer close(r.done)
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			closed := r.closed
+			r.mu.Unlock()
+			if closed {
+				return
+			}
+			<-r.wake
+			continue
+		}
+		e := r.queue[0]
+		r.queue[0] = service.Event{}
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+
+		if err := r.run(e); err != nil {
+			r.mu.Lock()
+			r.errs = append(r.errs, err)
+			r.mu.Unlock()
+		}
+	}
+}
+
+// run runs the hook for e, feeding it the task.
+func (r *Runner) run(e service.Event) error {
+	name := Name(e.Type)
+	path := filepath.Join(r.dir, name)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("hook %s: %w", name, err)
+	}
+	if info.IsDir() || runtime.GOOS != "windows" && info.Mode()&0o111 == 0 {
+		return fmt.Errorf("hook %s: %s is not executable", name, path)
+	}
+	task, err := json.Marshal(e.Task)
+	if err != nil {
+		return fmt.Errorf("hook %s: %w", name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(task)
+	cmd.Env = append(os.Environ(), "TOGO_EVENT="+string(e.Type))
+	// A hook killed on timeout may leave children holding its output open.
+	cmd.WaitDelay = time.Second
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", r.timeout)
+		}
+		return fmt.Errorf("hook %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+// writeHook creates the named hook in dir running script with mode.
+func writeHook(t *testing.T, dir, name, script string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRunner verifies each event runs its hook with the task on stdin and
+// the event in the environment, in the order the events happened.
+func TestRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "out")
+	script := `printf '%s ' "$TOGO_EVENT" >> ` + out + ` && cat >> ` + out + ` && echo >> ` + out
+	writeHook(t, dir, "on-complete", script, 0o755)
+	writeHook(t, dir, "on-create", script, 0o755)
+
+	r := New(dir, DefaultTimeout)
+	task := testutil.NewTask().WithTitle("Water the plants").Build()
+	for _, typ := range []service.EventType{service.TaskCreated, service.TaskEdited, service.TaskCompleted} {
+		r.Handle(service.Event{Type: typ, Task: task})
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("hooks wrote %q, want two runs", data)
+	}
+	for i, want := range []string{"created", "completed"} {
+		event, body, _ := strings.Cut(lines[i], " ")
+		if event != want {
+			t.Errorf("run %d got event %q, want %q", i, event, want)
+		}
+		var got model.Task
+		if err := json.Unmarshal([]byte(body), &got); err != nil || got.ID != task.ID {
+			t.Errorf("run %d got task %q: %v", i, body, err)
+		}
+	}
+}
+
+// TestRunner_Failures verifies a hook that fails, hangs or cannot run is
+// reported when the runner closes.
+func TestRunner_Failures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	tests := []struct {
+		name   string
+		script string
+		mode   os.FileMode
+		want   string
+	}{
+		{name: "fails", script: "echo no network; exit 3", mode: 0o755, want: "hook on-complete: exit status 3: no network"},
+		{name: "hangs", script: "sleep 5", mode: 0o755, want: "timed out"},
+		{name: "not executable", script: "true", mode: 0o644, want: "is not executable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeHook(t, dir, "on-complete", tt.script, tt.mode)
+			r := New(dir, 100*time.Millisecond)
+			r.Handle(service.Event{Type: service.TaskCompleted, Task: testutil.NewTask().Build()})
+			err := r.Close()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Close() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunner_Burst verifies a burst of events is queued without waiting
+// for a slow hook, and every hook still runs.
+func TestRunner_Burst(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+	dir, tmp := t.TempDir(), t.TempDir()
+	release, out := filepath.Join(tmp, "release"), filepath.Join(tmp, "out")
+	writeHook(t, dir, "on-create", `while [ ! -e `+release+` ]; do sleep 0.01; done; echo >> `+out, 0o755)
+
+	r := New(dir, DefaultTimeout)
+	const events = 200
+	handled := make(chan struct{})
+	go func() {
+		for range events {
+			r.Handle(service.Event{Type: service.TaskCreated, Task: testutil.NewTask().Build()})
+		}
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Handle() blocked while a hook was running")
+	}
+
+	if err := os.WriteFile(release, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "\n"); runs != events {
+		t.Errorf("hook ran %d times, want %d", runs, events)
+	}
+}
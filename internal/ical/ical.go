@@ -0,0 +1,141 @@
+// Package ical converts between tasks and iCalendar (RFC 5545) VTODO
+// components, so tasks with due dates show up in calendar clients that
+// support todos:
+//
+//	BEGIN:VTODO
+//	UID:0190a5c4-…@togo
+//	SUMMARY:Renew passport
+//	DUE;VALUE=DATE:20240630
+//	STATUS:NEEDS-ACTION
+//	CATEGORIES:admin,travel
+//	END:VTODO
+//
+// Pool tasks are NEEDS-ACTION, tasks planned for today IN-PROCESS and done
+// tasks COMPLETED. Priorities map to 1 (high), 5 (medium) and 9 (low), as
+// RFC 5545 suggests, and tags become CATEGORIES. A due time of midnight is
+// written as an all-day date.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/model"
+)
+
+// prodID names togo as the calendar's producer.
+const prodID = "-//togo//togo//EN"
+
+// uidDomain qualifies task IDs to make them globally unique UIDs.
+const uidDomain = "@togo"
+
+// Layouts of iCalendar DATE and UTC DATE-TIME values.
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// statuses maps task statuses to VTODO statuses.
+var statuses = map[model.TaskStatus]string{
+	model.StatusPool:  "NEEDS-ACTION",
+	model.StatusToday: "IN-PROCESS",
+	model.StatusDone:  "COMPLETED",
+}
+
+// priorities maps priorities to VTODO PRIORITY values.
+var priorities = map[model.Priority]int{
+	model.PriorityHigh:   1,
+	model.PriorityMedium: 5,
+	model.PriorityLow:    9,
+}
+
+// Write writes a calendar holding a VTODO for each task with a due date;
+// tasks without one have no place on a calendar and are left out. stamp
+// is the DTSTAMP of every component, normally the current time.
+func Write(w io.Writer, tasks []*model.Task, stamp time.Time) error {
+	cw := &writer{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	for _, t := range tasks {
+		if t.DueDate != nil {
+			writeTodo(cw, t, stamp)
+		}
+	}
+	cw.line("END", "VCALENDAR")
+	return cw.w.Flush()
+}
+
+// writeTodo writes one VTODO component.
+func writeTodo(cw *writer, t *model.Task, stamp time.Time) {
+	cw.line("BEGIN", "VTODO")
+	cw.line("UID", t.ID.String()+uidDomain)
+	cw.line("DTSTAMP", stamp.UTC().Format(dateTimeLayout))
+	cw.line("CREATED", t.CreatedAt.UTC().Format(dateTimeLayout))
+	cw.line("LAST-MODIFIED", t.UpdatedAt.UTC().Format(dateTimeLayout))
+	cw.line("SUMMARY", escape(t.Title))
+	if t.Notes != "" {
+		cw.line("DESCRIPTION", escape(t.Notes))
+	}
+	if due := *t.DueDate; due.Equal(model.StartOfDay(due)) {
+		cw.line("DUE;VALUE=DATE", due.Format(dateLayout))
+	} else {
+		cw.line("DUE", due.UTC().Format(dateTimeLayout))
+	}
+	cw.line("STATUS", statuses[t.Status])
+	if t.CompletedAt != nil {
+		cw.line("COMPLETED", t.CompletedAt.UTC().Format(dateTimeLayout))
+	}
+	if p, ok := priorities[t.Priority]; ok {
+		cw.line("PRIORITY", strconv.Itoa(p))
+	}
+	if len(t.Tags) > 0 {
+		escaped := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			escaped[i] = escape(tag)
+		}
+		cw.line("CATEGORIES", strings.Join(escaped, ","))
+	}
+	cw.line("END", "VTODO")
+}
+
+// escape escapes a TEXT value (RFC 5545 §3.3.11).
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// maxLine is the longest content line, in octets, before folding.
+const maxLine = 75
+
+// writer writes content lines, folding long ones (RFC 5545 §3.1).
+type writer struct {
+	w *bufio.Writer
+}
+
+// line writes "name:value", folded onto continuation lines that begin with
+// a space, never splitting a UTF-8 sequence.
+func (cw *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLine
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		cw.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts toward its length.
+		limit = maxLine - 1
+	}
+	cw.w.WriteString(s + "\r\n")
+}
+
+// isRuneStart reports whether b begins a UTF-8 sequence.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestWrite verifies tasks with due dates become VTODO components.
+func TestWrite(t *testing.T) {
+	created := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	stamp := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle("Renew passport; bring photos, forms").WithTags("admin", "travel").
+			WithCreatedAt(created).WithDue(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)).WithPriority(model.PriorityHigh),
+		testutil.NewTask().WithTitle("No due date").WithCreatedAt(created),
+		testutil.NewTask().WithTitle("Call the dentist").WithCreatedAt(created).
+			WithDue(time.Date(2024, 6, 12, 17, 30, 0, 0, time.FixedZone("CEST", 2*60*60))),
+	)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, stamp); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	id := tasks[0].ID.String()
+	want := "BEGIN:VTODO\r\n" +
+		"UID:" + id + "@togo\r\n" +
+		"DTSTAMP:20240610T120000Z\r\n" +
+		"CREATED:20240601T090000Z\r\n" +
+		"LAST-MODIFIED:20240601T090000Z\r\n" +
+		`SUMMARY:Renew passport\; bring photos\, forms` + "\r\n" +
+		"DUE;VALUE=DATE:20240630\r\n" +
+		"STATUS:NEEDS-ACTION\r\n" +
+		"PRIORITY:1\r\n" +
+		"CATEGORIES:admin,travel\r\n" +
+		"END:VTODO\r\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("output lacks\n%s\ngot\n%s", want, out)
+	}
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("output is not a calendar:\n%s", out)
+	}
+	if strings.Contains(out, "No due date") {
+		t.Error("task without a due date was exported")
+	}
+	if !strings.Contains(out, "DUE:20240612T153000Z\r\n") {
+		t.Errorf("timed due date not written in UTC:\n%s", out)
+	}
+}
+
+// TestWriter_Folds verifies long lines are folded at 75 octets without
+// splitting characters.
+func TestWriter_Folds(t *testing.T) {
+	var buf bytes.Buffer
+	tasks := testutil.Tasks(testutil.NewTask().WithTitle(strings.Repeat("é", 100)).WithDue(time.Now()))
+	if err := Write(&buf, tasks, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	var summary strings.Builder
+	inSummary := false
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > maxLine {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		switch {
+		case strings.HasPrefix(line, "SUMMARY:"):
+			inSummary = true
+			summary.WriteString(strings.TrimPrefix(line, "SUMMARY:"))
+		case inSummary && strings.HasPrefix(line, " "):
+			summary.WriteString(line[1:])
+		default:
+			inSummary = false
+		}
+	}
+	if summary.String() != strings.Repeat("é", 100) {
+		t.Errorf("unfolded summary = %q", summary.String())
+	}
+}
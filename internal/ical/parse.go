@@ -0,0 +1,288 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// System is the ExternalRef system of tasks imported from iCalendar.
+const System = "ical"
+
+// contentLine is one unfolded iCalendar property.
+type contentLine struct {
+	// line is the 1-based line the property starts on.
+	line   int
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VTODO components of a calendar as drafts; other
+// components, such as events, are ignored. Each draft's ExternalRef ID is
+// the todo's UID, so importing the same calendar again adds nothing new.
+// Floating times, and those in unknown time zones, are read in loc.
+//
+// Cancelled todos and those without a summary are returned as skipped.
+// Recurrence rules cannot be represented yet, so a todo's RRULE is kept in
+// its notes.
+func Parse(r io.Reader, loc *time.Location) (drafts []importer.Draft, skipped []*importer.LineError, err error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		todo      []contentLine
+		start     int
+		inTodo    bool
+		nested    int
+		sawHeader bool
+	)
+	for _, cl := range lines {
+		switch {
+		case cl.name == "BEGIN" && strings.EqualFold(cl.value, "VCALENDAR"):
+			sawHeader = true
+		case cl.name == "BEGIN" && strings.EqualFold(cl.value, "VTODO") && !inTodo:
+			inTodo, start, todo = true, cl.line, nil
+		case !inTodo:
+		case cl.name == "BEGIN":
+			// Alarms and other subcomponents of a todo are not imported.
+			nested++
+		case cl.name == "END" && nested > 0:
+			nested--
+		case cl.name == "END" && strings.EqualFold(cl.value, "VTODO"):
+			inTodo = false
+			d, err := readTodo(todo, loc)
+			if err != nil {
+				skipped = append(skipped, &importer.LineError{Line: start, Err: err})
+				continue
+			}
+			drafts = append(drafts, d)
+		case nested == 0:
+			todo = append(todo, cl)
+		}
+	}
+	if !sawHeader {
+		return nil, nil, errors.New("not an iCalendar file: no BEGIN:VCALENDAR")
+	}
+	return drafts, skipped, nil
+}
+
+// readTodo builds a draft from the properties of one VTODO.
+func readTodo(props []contentLine, loc *time.Location) (importer.Draft, error) {
+	var d importer.Draft
+	var rules []string
+	for _, p := range props {
+		var err error
+		switch p.name {
+		case "UID":
+			d.Ref = model.ExternalRef{System: System, ID: p.value}
+		case "SUMMARY":
+			d.Title = strings.TrimSpace(unescape(p.value))
+		case "DESCRIPTION":
+			d.Notes = unescape(p.value)
+		case "DUE":
+			var t time.Time
+			t, err = parseValue(p, loc)
+			d.Due = &t
+		case "CREATED":
+			d.Created, err = parseValue(p, loc)
+		case "COMPLETED":
+			var t time.Time
+			t, err = parseValue(p, loc)
+			d.Completed = &t
+			d.Status = model.StatusDone
+		case "STATUS":
+			switch strings.ToUpper(p.value) {
+			case "COMPLETED":
+				d.Status = model.StatusDone
+			case "IN-PROCESS":
+				d.Status = model.StatusToday
+			case "CANCELLED":
+				return d, errors.New("todo was cancelled")
+			}
+		case "PRIORITY":
+			d.Priority, err = parsePriority(p.value)
+		case "CATEGORIES":
+			for _, c := range splitList(p.value) {
+				if tag := strings.Join(strings.Fields(unescape(c)), "-"); tag != "" {
+					d.Tags = append(d.Tags, tag)
+				}
+			}
+		case "RRULE":
+			rules = append(rules, p.value)
+		}
+		if err != nil {
+			return d, fmt.Errorf("%s: %w", p.name, err)
+		}
+	}
+	if d.Title == "" {
+		return d, errors.New("todo has no SUMMARY")
+	}
+	if d.Ref.IsZero() {
+		return d, errors.New("todo has no UID")
+	}
+	for _, rule := range rules {
+		if d.Notes != "" {
+			d.Notes += "\n"
+		}
+		d.Notes += "Repeats: " + rule
+	}
+	return d, nil
+}
+
+// parsePriority maps RFC 5545 priorities onto togo's: 1-4 high, 5 medium,
+// 6-9 low and 0 undefined.
+func parsePriority(value string) (model.Priority, error) {
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil || n < 0 || n > 9:
+		return model.PriorityNone, fmt.Errorf("%q is not 0-9", value)
+	case n == 0:
+		return model.PriorityNone, nil
+	case n < 5:
+		return model.PriorityHigh, nil
+	case n == 5:
+		return model.PriorityMedium, nil
+	default:
+		return model.PriorityLow, nil
+	}
+}
+
+// parseValue reads a DATE or DATE-TIME property. UTC times end in Z;
+// others are in the zone named by TZID, or in loc when that is absent or
+// unknown.
+func parseValue(p contentLine, loc *time.Location) (time.Time, error) {
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	switch {
+	case strings.EqualFold(p.params["VALUE"], "DATE") || len(p.value) == len(dateLayout):
+		return time.ParseInLocation(dateLayout, p.value, loc)
+	case strings.HasSuffix(p.value, "Z"):
+		return time.Parse(dateTimeLayout, p.value)
+	default:
+		return time.ParseInLocation(strings.TrimSuffix(dateTimeLayout, "Z"), p.value, loc)
+	}
+}
+
+// unfold reads content lines, joining folded continuations and splitting
+// each into name, parameters and value. Names and parameter names are
+// upper-cased.
+func unfold(r io.Reader) ([]contentLine, error) {
+	var lines []contentLine
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	var cur strings.Builder
+	curLine := 0
+	flush := func() error {
+		if cur.Len() == 0 {
+			return nil
+		}
+		cl, err := splitLine(cur.String())
+		if err != nil {
+			return &importer.LineError{Line: curLine, Err: err}
+		}
+		cl.line = curLine
+		lines = append(lines, cl)
+		cur.Reset()
+		return nil
+	}
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimSuffix(sc.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			cur.WriteString(text[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		cur.WriteString(text)
+		curLine = n
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitLine splits "NAME;PARAM=value:VALUE", honouring quoted parameter
+// values, which may contain colons.
+func splitLine(s string) (contentLine, error) {
+	cl := contentLine{params: map[string]string{}}
+	quoted := false
+	colon := -1
+	for i := 0; i < len(s) && colon < 0; i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return cl, fmt.Errorf("%q has no value", s)
+	}
+	head, value := s[:colon], s[colon+1:]
+	parts := strings.Split(head, ";")
+	cl.name, cl.value = strings.ToUpper(parts[0]), value
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		cl.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return cl, nil
+}
+
+// splitList splits a comma-separated TEXT list, leaving escaped commas in
+// place for unescape.
+func splitList(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			out = append(out, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, s[start:])
+}
+
+// unescape reverses escape.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
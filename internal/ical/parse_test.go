@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestParse verifies VTODO properties are read into drafts and unusable
+// todos are skipped.
+func TestParse(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Mozilla.org/NONSGML Mozilla Calendar V1.1//EN",
+		"BEGIN:VEVENT",
+		"UID:event-1",
+		"SUMMARY:Not a todo",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:todo-1",
+		"SUMMARY:Renew passport\\, again",
+		"DESCRIPTION:bring photos\\nand forms; also the old one",
+		"DUE;TZID=Europe/Berlin:20240630T170000",
+		"CREATED:20240601T090000Z",
+		"PRIORITY:3",
+		"CATEGORIES:admin,Travel Plans",
+		"RRULE:FREQ=YEARLY",
+		"BEGIN:VALARM",
+		"DESCRIPTION:Alarm text",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo-2",
+		"SUMMARY:Call the d",
+		" entist",
+		"STATUS:COMPLETED",
+		"COMPLETED:20240602T100000Z",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo-3",
+		"SUMMARY:Dropped",
+		"STATUS:CANCELLED",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:todo-4",
+		"DUE;VALUE=DATE:2024063",
+		"SUMMARY:Bad date",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	drafts, skipped, err := Parse(strings.NewReader(input), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 2 {
+		t.Fatalf("Parse() = %d drafts, want 2: %+v", len(drafts), drafts)
+	}
+
+	d := drafts[0]
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if d.Title != "Renew passport, again" || d.Ref != (model.ExternalRef{System: System, ID: "todo-1"}) {
+		t.Errorf("first draft = %+v", d)
+	}
+	if d.Notes != "bring photos\nand forms; also the old one\nRepeats: FREQ=YEARLY" {
+		t.Errorf("notes = %q", d.Notes)
+	}
+	if d.Due == nil || !d.Due.Equal(time.Date(2024, 6, 30, 17, 0, 0, 0, berlin)) {
+		t.Errorf("due = %v", d.Due)
+	}
+	if strings.Join(d.Tags, ",") != "admin,Travel-Plans" || d.Priority != model.PriorityHigh {
+		t.Errorf("tags = %v, priority = %v", d.Tags, d.Priority)
+	}
+	if got := drafts[1]; got.Title != "Call the dentist" || got.Status != model.StatusDone || got.Completed == nil {
+		t.Errorf("second draft = %+v", got)
+	}
+
+	if len(skipped) != 2 || skipped[0].Line != 27 || !strings.Contains(skipped[0].Error(), "cancelled") ||
+		!strings.Contains(skipped[1].Error(), "DUE") {
+		t.Errorf("skipped = %v", skipped)
+	}
+}
+
+// TestParse_NotCalendar verifies other files are rejected.
+func TestParse_NotCalendar(t *testing.T) {
+	if _, _, err := Parse(strings.NewReader("SUMMARY:hello\n"), time.UTC); err == nil {
+		t.Error("Parse() accepted a file without a calendar")
+	}
+}
+
+// TestParse_RoundTrip verifies Write's output reads back to the same tasks.
+func TestParse_RoundTrip(t *testing.T) {
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle(`Back\slash; semi, comma`).WithNotes("two\nlines").
+			WithTags("admin", "travel").WithDue(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)).
+			WithPriority(model.PriorityMedium),
+	)
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	drafts, skipped, err := Parse(&buf, time.UTC)
+	if err != nil || len(skipped) != 0 || len(drafts) != 1 {
+		t.Fatalf("Parse() = %v, %v, %v", drafts, skipped, err)
+	}
+	got, want := drafts[0], tasks[0]
+	if got.Title != want.Title || got.Notes != want.Notes || !got.Due.Equal(*want.DueDate) ||
+		got.Priority != want.Priority || strings.Join(got.Tags, ",") != "admin,travel" {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
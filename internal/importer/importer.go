@@ -0,0 +1,166 @@
+// Package importer turns records from other tools into tasks. Format
+// packages parse their input into Drafts; Draft.Task validates them and
+// gives each a deterministic ID derived from its source, and Plan decides
+// which tasks a journal is still missing and which the source has changed
+// since they were imported, so importing the same file twice creates
+// nothing new.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Draft is a task as read from another tool, before validation.
+type Draft struct {
+	Title     string
+	Notes     string
+	Tags      []string
+	Status    model.TaskStatus // empty means pool
+	Priority  model.Priority
+	Energy    model.Energy
+	Estimate  time.Duration
+	Due       *time.Time
+	Created   time.Time  // zero means now
+	Completed *time.Time // for done tasks; nil means Created
+
+	// Ref identifies the record in its source. Its ID should stay the same
+	// across exports of the same record.
+	Ref model.ExternalRef
+}
+
+// Task builds and validates the task described by d. Its ID is derived
+// from d.Ref (see model.TaskIDFromExternal).
+func (d Draft) Task() (*model.Task, error) {
+	task, err := model.NewTask(d.Title, dedupe(d.Tags))
+	if err != nil {
+		return nil, err
+	}
+	task.ID = model.ExternalIDGenerator{}.NewID(d.Ref)
+	if !d.Created.IsZero() {
+		task.CreatedAt, task.UpdatedAt = d.Created, d.Created
+	}
+	task.Notes = d.Notes
+	task.Priority = d.Priority
+	task.Energy = d.Energy
+	task.Estimate = d.Estimate
+	task.DueDate = d.Due
+	if d.Status != "" {
+		task.Status = d.Status
+	}
+	if task.Status == model.StatusDone {
+		completed := task.CreatedAt
+		if d.Completed != nil {
+			completed = *d.Completed
+		}
+		task.CompletedAt = &completed
+	}
+	if !d.Ref.IsZero() {
+		ref := d.Ref
+		task.ExternalRef = &ref
+	}
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// dedupe drops repeated and empty tags, keeping the first occurrence.
+func dedupe(tags []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, t := range tags {
+		if t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// LineError reports a record that could not be imported.
+type LineError struct {
+	// Line is the 1-based line or record number in the input.
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Plan splits tasks into those repo does not hold yet, those imported
+// earlier that the source has changed since, returned as Refresh updates
+// the stored tasks, and those unchanged. Only the first of tasks with the
+// same ID counts; the rest are unchanged.
+func Plan(repo repository.TaskRepository, tasks []*model.Task) (create, update, unchanged []*model.Task, err error) {
+	seen := map[model.TaskID]bool{}
+	for _, t := range tasks {
+		if seen[t.ID] {
+			unchanged = append(unchanged, t)
+			continue
+		}
+		seen[t.ID] = true
+		stored, err := repo.Get(t.ID)
+		if errors.Is(err, model.ErrTaskNotFound) {
+			create = append(create, t)
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if refreshed := Refresh(stored, t); len(stored.Diff(refreshed)) > 0 {
+			update = append(update, refreshed)
+		} else {
+			unchanged = append(unchanged, t)
+		}
+	}
+	return create, update, unchanged, nil
+}
+
+// Refresh returns a copy of stored, a task imported earlier, with the
+// fields its source sets in imported: the title, and the notes, tags,
+// priority, energy, estimate and due date when imported has them, and the
+// completion when imported is done. Fields the source leaves empty keep
+// the journal's values, and a task completed here stays completed.
+func Refresh(stored, imported *model.Task) *model.Task {
+	fields := []string{"title"}
+	if imported.Notes != "" {
+		fields = append(fields, "notes")
+	}
+	if len(imported.Tags) > 0 {
+		fields = append(fields, "tags")
+	}
+	if imported.Priority != model.PriorityNone {
+		fields = append(fields, "priority")
+	}
+	if imported.Energy != model.EnergyNone {
+		fields = append(fields, "energy")
+	}
+	if imported.Estimate != 0 {
+		fields = append(fields, "estimate")
+	}
+	if imported.DueDate != nil {
+		fields = append(fields, "due_date")
+	}
+	if imported.Status == model.StatusDone && stored.Status != model.StatusDone {
+		fields = append(fields, "status", "completed_at")
+	}
+
+	task := stored.Clone()
+	for _, f := range fields {
+		task.TakeField(f, imported)
+	}
+	if len(stored.Diff(task)) > 0 {
+		task.UpdatedAt = model.Now()
+	}
+	return task
+}
@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestDraft_Task verifies drafts become valid tasks with stable IDs.
+func TestDraft_Task(t *testing.T) {
+	created := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	ref := model.ExternalRef{System: "todotxt", ID: "abc"}
+
+	tests := []struct {
+		name    string
+		draft   Draft
+		check   func(t *testing.T, task *model.Task)
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			draft: Draft{Title: "  Renew passport ", Tags: []string{"admin", "", "admin"}},
+			check: func(t *testing.T, task *model.Task) {
+				if task.Title != "Renew passport" || task.Status != model.StatusPool || len(task.Tags) != 1 || task.ExternalRef != nil {
+					t.Errorf("Task() = %+v", task)
+				}
+			},
+		},
+		{
+			name:  "done without completion date uses creation",
+			draft: Draft{Title: "Filed", Status: model.StatusDone, Created: created, Ref: ref},
+			check: func(t *testing.T, task *model.Task) {
+				if task.ID != model.TaskIDFromExternal(ref) || !task.CompletedAt.Equal(created) || *task.ExternalRef != ref {
+					t.Errorf("Task() = %+v", task)
+				}
+			},
+		},
+		{name: "empty title", draft: Draft{Title: " "}, wantErr: true},
+		{name: "invalid priority", draft: Draft{Title: "x", Priority: "urgent"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := tt.draft.Task()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Task() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, task)
+			}
+		})
+	}
+}
+
+// TestPlan verifies already imported and repeated tasks are not created
+// again, and changed ones are updated.
+func TestPlan(t *testing.T) {
+	repo := memstore.New()
+	stored := testutil.NewTask().WithTitle("Renew passport").Build()
+	renamed := testutil.NewTask().WithTitle("Call the dentist").Build()
+	testutil.MustSeed(t, repo, stored, renamed)
+	fresh := testutil.NewTask().Build()
+	changed := renamed.Clone()
+	changed.Title = "Call the dentist again"
+
+	create, update, unchanged, err := Plan(repo, []*model.Task{stored, fresh, fresh, changed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(create) != 1 || create[0].ID != fresh.ID || len(unchanged) != 2 {
+		t.Errorf("Plan() = create %v, unchanged %v", create, unchanged)
+	}
+	if len(update) != 1 || update[0].ID != renamed.ID || update[0].Title != "Call the dentist again" {
+		t.Errorf("Plan() update = %v, want the renamed task", update)
+	}
+}
+
+// TestRefresh verifies an imported task takes the fields its source sets
+// and keeps the journal's values of the rest.
+func TestRefresh(t *testing.T) {
+	due := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	stored := testutil.NewTask().WithTitle("Renew passport").WithNotes("Photos in the drawer").
+		WithTags("admin").WithPriority(model.PriorityHigh).Build()
+
+	tests := []struct {
+		name     string
+		imported *model.Task
+		check    func(t *testing.T, got *model.Task)
+	}{
+		{
+			name:     "empty fields keep local values",
+			imported: testutil.NewTask().WithTitle("Renew passports").WithDue(due).Build(),
+			check: func(t *testing.T, got *model.Task) {
+				if got.Title != "Renew passports" || got.Notes != "Photos in the drawer" || got.Priority != model.PriorityHigh ||
+					len(got.Tags) != 1 || got.DueDate == nil || !got.DueDate.Equal(due) {
+					t.Errorf("Refresh() = %+v", got)
+				}
+			},
+		},
+		{
+			name:     "completion",
+			imported: testutil.NewTask().WithTitle("Renew passport").WithStatus(model.StatusDone).Build(),
+			check: func(t *testing.T, got *model.Task) {
+				if got.Status != model.StatusDone || got.CompletedAt == nil {
+					t.Errorf("Refresh() = %+v, want it done", got)
+				}
+			},
+		},
+		{
+			name:     "unchanged",
+			imported: testutil.NewTask().WithTitle("Renew passport").Build(),
+			check: func(t *testing.T, got *model.Task) {
+				if diff := stored.Diff(got); len(diff) > 0 || !got.UpdatedAt.Equal(stored.UpdatedAt) {
+					t.Errorf("Refresh() changed %v", diff)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Refresh(stored, tt.imported)
+			tt.check(t, got)
+			if stored.Title != "Renew passport" {
+				t.Error("Refresh() changed the stored task")
+			}
+		})
+	}
+}
+
+// TestLineError verifies the wrapped cause stays reachable.
+func TestLineError(t *testing.T) {
+	err := error(&LineError{Line: 3, Err: model.ErrEmptyTitle})
+	if err.Error() != "line 3: task title cannot be empty" || !errors.Is(err, model.ErrEmptyTitle) {
+		t.Errorf("LineError = %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Package journals names and locates separate task journals, such as
+// "work" and "personal", so unrelated contexts never share a file.
+//
+// The default journal lives directly in the data directory, where togo has
+// always kept it; every other journal has a directory of its own under
+// "journals", holding its storage file, backups and notes.
+package journals
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// Default names the journal used when no other is chosen.
+const Default = "default"
+
+// subdir holds the named journals inside the data directory.
+const subdir = "journals"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateName checks that name can name a journal: lowercase letters,
+// digits, hyphens and underscores, starting with a letter or digit.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid journal name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// Dir returns the directory holding the named journal's files inside
+// dataDir.
+func Dir(dataDir, name string) string {
+	if name == Default {
+		return dataDir
+	}
+	return filepath.Join(dataDir, subdir, name)
+}
+
+// List returns the journals found in dataDir, the default first and the
+// rest by name. The default journal is always listed, even before it is
+// first written.
+func List(dataDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dataDir, subdir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	names := []string{Default}
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != Default && ValidateName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names[1:])
+	return names, nil
+}
@@ -0,0 +1,53 @@
+package journals
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestValidateName verifies which names are accepted.
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"work", true},
+		{"side-project_2", true},
+		{"", false},
+		{"Work", false},
+		{"-work", false},
+		{"../work", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateName(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+// TestDirAndList verifies journal locations and discovery.
+func TestDirAndList(t *testing.T) {
+	data := t.TempDir()
+	if got := Dir(data, Default); got != data {
+		t.Errorf("Dir(default) = %q, want the data directory", got)
+	}
+
+	if got, err := List(data); err != nil || !slices.Equal(got, []string{Default}) {
+		t.Errorf("List() of an empty data directory = %v, %v", got, err)
+	}
+
+	for _, name := range []string{"work", "personal", "Not A Journal"} {
+		if err := os.MkdirAll(Dir(data, name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(data, "journals", "stray.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{Default, "personal", "work"}
+	if got, err := List(data); err != nil || !slices.Equal(got, want) {
+		t.Errorf("List() = %v, %v; want %v", got, err, want)
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Clock supplies the current time to the model package. All time-dependent
+// behavior (creation and completion timestamps, overdue checks, rollover)
+// reads the time through a Clock so it can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always reports the same instant until moved with Set or Advance.
+// It is intended for tests.
+type FixedClock struct {
+	t time.Time
+}
+
+// NewFixedClock returns a FixedClock stopped at t.
+func NewFixedClock(t time.Time) *FixedClock {
+	return &FixedClock{t: t}
+}
+
+func (c *FixedClock) Now() time.Time {
+	return c.t
+}
+
+// Set moves the clock to t.
+func (c *FixedClock) Set(t time.Time) {
+	c.t = t
+}
+
+// Advance moves the clock forward by d.
+func (c *FixedClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// clock is the package-level default used by constructors and transitions.
+var clock Clock = SystemClock{}
+
+// SetClock replaces the package clock and returns a function restoring the
+// previous one. Passing nil restores the system clock. SetClock is not safe
+// for concurrent use; tests overriding the clock must not run in parallel.
+//
+// Example:
+//
+//	restore := model.SetClock(model.NewFixedClock(noon))
+//	defer restore()
+func SetClock(c Clock) (restore func()) {
+	prev := clock
+	if c == nil {
+		c = SystemClock{}
+	}
+	clock = c
+	return func() { clock = prev }
+}
+
+// Now returns the current time according to the package clock.
+func Now() time.Time {
+	return clock.Now()
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFixedClock_SetAndAdvance verifies the test clock only moves when told to.
+func TestFixedClock_SetAndAdvance(t *testing.T) {
+	start := time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC)
+	c := NewFixedClock(start)
+
+	if !c.Now().Equal(start) {
+		t.Fatalf("Now() = %v, want %v", c.Now(), start)
+	}
+
+	c.Advance(90 * time.Minute)
+	if want := start.Add(90 * time.Minute); !c.Now().Equal(want) {
+		t.Errorf("after Advance, Now() = %v, want %v", c.Now(), want)
+	}
+
+	later := start.AddDate(0, 0, 3)
+	c.Set(later)
+	if !c.Now().Equal(later) {
+		t.Errorf("after Set, Now() = %v, want %v", c.Now(), later)
+	}
+}
+
+// TestSetClock_OverridesAndRestores verifies that NewTask reads the injected
+// clock and that the restore function reinstates the previous clock.
+func TestSetClock_OverridesAndRestores(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	restore := SetClock(NewFixedClock(fixed))
+
+	task, err := NewTask("Deterministic", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.CreatedAt.Equal(fixed) {
+		t.Errorf("CreatedAt = %v, want %v", task.CreatedAt, fixed)
+	}
+
+	restore()
+	if _, ok := clock.(SystemClock); !ok {
+		t.Errorf("expected SystemClock after restore, got %T", clock)
+	}
+}
+
+// TestSetClock_NilUsesSystemClock verifies that nil falls back to the wall clock.
+func TestSetClock_NilUsesSystemClock(t *testing.T) {
+	restore := SetClock(nil)
+	defer restore()
+
+	before := time.Now()
+	got := Now()
+	if got.Before(before) {
+		t.Errorf("Now() = %v, expected at or after %v", got, before)
+	}
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IDDisplayMode selects how task identities are rendered to users and which
+// forms are expected on input. Every mode is accepted by ResolveTaskID, so a
+// user can always paste a full UUID even when short IDs are displayed.
+type IDDisplayMode string
+
+const (
+	// IDDisplayUUID renders the full canonical UUID.
+	IDDisplayUUID IDDisplayMode = "uuid"
+	// IDDisplayShort renders the first ShortIDLength hex characters of the UUID.
+	IDDisplayShort IDDisplayMode = "short"
+	// IDDisplaySequence renders the per-journal sequence number as "#N".
+	IDDisplaySequence IDDisplayMode = "sequence"
+)
+
+// ShortIDLength is the number of leading UUID characters shown in short mode.
+const ShortIDLength = 8
+
+// DefaultIDDisplayMode is used when no display strategy is configured.
+const DefaultIDDisplayMode = IDDisplayShort
+
+func (m IDDisplayMode) Valid() bool {
+	switch m {
+	case IDDisplayUUID, IDDisplayShort, IDDisplaySequence:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m IDDisplayMode) String() string {
+	return string(m)
+}
+
+// ParseIDDisplayMode converts a configuration value into an IDDisplayMode.
+// Matching is case-insensitive; an empty string yields DefaultIDDisplayMode.
+func ParseIDDisplayMode(s string) (IDDisplayMode, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return DefaultIDDisplayMode, nil
+	}
+	mode := IDDisplayMode(s)
+	if !mode.Valid() {
+		return "", &ValidationError{Field: "id_display", Reason: fmt.Sprintf("unknown mode %q", s)}
+	}
+	return mode, nil
+}
+
+// Format renders the task's identity according to the display mode.
+// Tasks without a sequence number fall back to the short form in sequence
+// mode so that every task remains addressable.
+func (m IDDisplayMode) Format(t *Task) string {
+	switch m {
+	case IDDisplayUUID:
+		return t.ID.String()
+	case IDDisplaySequence:
+		if t.Seq > 0 {
+			return "#" + strconv.Itoa(t.Seq)
+		}
+		return t.ID.Short()
+	default:
+		return t.ID.Short()
+	}
+}
+
+// ResolveTaskID finds the task referenced by user input in any display form:
+// a full UUID, a UUID prefix (short ID), or a sequence number ("#12" or "12").
+//
+// Returns:
+//   - ErrTaskNotFound if no task matches
+//   - ErrAmbiguousID if a prefix matches more than one task
+func ResolveTaskID(input string, tasks []*Task) (TaskID, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return TaskID{}, ErrTaskNotFound
+	}
+
+	if id, err := ParseTaskID(input); err == nil {
+		for _, t := range tasks {
+			if t.ID.Equals(id) {
+				return id, nil
+			}
+		}
+		return TaskID{}, ErrTaskNotFound
+	}
+
+	if seq, ok := parseSequence(input); ok {
+		for _, t := range tasks {
+			if t.Seq == seq {
+				return t.ID, nil
+			}
+		}
+		if strings.HasPrefix(input, "#") {
+			return TaskID{}, ErrTaskNotFound
+		}
+	}
+
+	match, err := FindByIDPrefix(tasks, input)
+	if err != nil {
+		return TaskID{}, err
+	}
+	return match.ID, nil
+}
+
+// FindByIDPrefix returns the single task whose UUID starts with prefix,
+// ignoring case. It is the lookup behind TaskFilter.IDPrefix for callers
+// that need exactly one task.
+//
+// Returns:
+//   - ErrTaskNotFound if no task matches or prefix is empty
+//   - ErrAmbiguousID if more than one task matches
+func FindByIDPrefix(tasks []*Task, prefix string) (*Task, error) {
+	if prefix == "" {
+		return nil, ErrTaskNotFound
+	}
+	var match *Task
+	for _, t := range tasks {
+		if t.ID.HasPrefix(prefix) {
+			if match != nil {
+				return nil, ErrAmbiguousID
+			}
+			match = t
+		}
+	}
+	if match == nil {
+		return nil, ErrTaskNotFound
+	}
+	return match, nil
+}
+
+// parseSequence interprets "#N" or "N" as a positive sequence number.
+func parseSequence(s string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// SequenceAllocator hands out per-journal sequence numbers.
+//
+// Numbers are never reused: the allocator tracks a high-water mark that the
+// journal persists, so archiving or purging tasks cannot cause a later task
+// to inherit a number the user may still remember.
+type SequenceAllocator struct {
+	last int
+}
+
+// NewSequenceAllocator creates an allocator resuming after the persisted
+// high-water mark.
+func NewSequenceAllocator(last int) *SequenceAllocator {
+	if last < 0 {
+		last = 0
+	}
+	return &SequenceAllocator{last: last}
+}
+
+// Last returns the highest sequence number handed out so far.
+func (a *SequenceAllocator) Last() int {
+	return a.last
+}
+
+// Next returns a fresh sequence number.
+func (a *SequenceAllocator) Next() int {
+	a.last++
+	return a.last
+}
+
+// Assign gives sequence numbers to tasks that lack one and resolves
+// collisions, such as those caused by restoring archived tasks or merging
+// journals. When two tasks share a number, the earlier-created task keeps it
+// and the other receives a fresh one.
+func (a *SequenceAllocator) Assign(tasks []*Task) {
+	for _, t := range tasks {
+		if t.Seq > a.last {
+			a.last = t.Seq
+		}
+	}
+
+	owners := make(map[int]*Task, len(tasks))
+	var pending []*Task
+	for _, t := range tasks {
+		if t.Seq <= 0 {
+			pending = append(pending, t)
+			continue
+		}
+		owner, taken := owners[t.Seq]
+		if !taken {
+			owners[t.Seq] = t
+			continue
+		}
+		if t.CreatedAt.Before(owner.CreatedAt) {
+			owners[t.Seq] = t
+			pending = append(pending, owner)
+		} else {
+			pending = append(pending, t)
+		}
+	}
+
+	for _, t := range pending {
+		t.Seq = a.Next()
+	}
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseIDDisplayMode verifies parsing of configured display modes,
+// including the empty default and rejection of unknown values.
+func TestParseIDDisplayMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    IDDisplayMode
+		wantErr bool
+	}{
+		{name: "uuid", input: "uuid", want: IDDisplayUUID},
+		{name: "short", input: "short", want: IDDisplayShort},
+		{name: "sequence", input: "sequence", want: IDDisplaySequence},
+		{name: "case insensitive", input: " Sequence ", want: IDDisplaySequence},
+		{name: "empty yields default", input: "", want: DefaultIDDisplayMode},
+		{name: "unknown rejected", input: "hex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIDDisplayMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIDDisplayMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseIDDisplayMode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIDDisplayMode_Format verifies each display strategy renders the
+// expected form, with sequence mode falling back to short IDs.
+func TestIDDisplayMode_Format(t *testing.T) {
+	id, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+	numbered := &Task{ID: id, Seq: 42}
+	unnumbered := &Task{ID: id}
+
+	tests := []struct {
+		name string
+		mode IDDisplayMode
+		task *Task
+		want string
+	}{
+		{name: "uuid", mode: IDDisplayUUID, task: numbered, want: "550e8400-e29b-41d4-a716-446655440000"},
+		{name: "short", mode: IDDisplayShort, task: numbered, want: "550e8400"},
+		{name: "sequence", mode: IDDisplaySequence, task: numbered, want: "#42"},
+		{name: "sequence without number falls back to short", mode: IDDisplaySequence, task: unnumbered, want: "550e8400"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.Format(tt.task); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolveTaskID verifies that every display form is accepted on input.
+func TestResolveTaskID(t *testing.T) {
+	a, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+	b, _ := ParseTaskID("550e9999-e29b-41d4-a716-446655440000")
+	c, _ := ParseTaskID("12345678-e29b-41d4-a716-446655440000")
+	tasks := []*Task{{ID: a, Seq: 1}, {ID: b, Seq: 2}, {ID: c}}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    TaskID
+		wantErr error
+	}{
+		{name: "full uuid", input: a.String(), want: a},
+		{name: "uppercase uuid", input: "550E8400-E29B-41D4-A716-446655440000", want: a},
+		{name: "short prefix", input: "550e8400", want: a},
+		{name: "hash sequence", input: "#2", want: b},
+		{name: "bare sequence", input: "1", want: a},
+		{name: "numeric prefix when no sequence matches", input: "1234", want: c},
+		{name: "ambiguous prefix", input: "550e", wantErr: ErrAmbiguousID},
+		{name: "unknown sequence", input: "#9", wantErr: ErrTaskNotFound},
+		{name: "unknown uuid", input: "00000000-0000-0000-0000-000000000001", wantErr: ErrTaskNotFound},
+		{name: "empty input", input: "  ", wantErr: ErrTaskNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTaskID(tt.input, tasks)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ResolveTaskID(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveTaskID(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equals(tt.want) {
+				t.Errorf("ResolveTaskID(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindByIDPrefix verifies unique, ambiguous and missing prefixes.
+func TestFindByIDPrefix(t *testing.T) {
+	a, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+	b, _ := ParseTaskID("550e9999-e29b-41d4-a716-446655440000")
+	tasks := []*Task{{ID: a}, {ID: b}}
+
+	tests := []struct {
+		prefix  string
+		want    *Task
+		wantErr error
+	}{
+		{"550E84", tasks[0], nil},
+		{"550e9", tasks[1], nil},
+		{"550e", nil, ErrAmbiguousID},
+		{"abc", nil, ErrTaskNotFound},
+		{"", nil, ErrTaskNotFound},
+	}
+
+	for _, tt := range tests {
+		got, err := FindByIDPrefix(tasks, tt.prefix)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("FindByIDPrefix(%q) = %v, %v, want %v, %v", tt.prefix, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestSequenceAllocator_NeverReusesNumbers verifies that numbers freed by
+// archiving or purging are not handed out again.
+func TestSequenceAllocator_NeverReusesNumbers(t *testing.T) {
+	alloc := NewSequenceAllocator(0)
+	first := alloc.Next()
+	second := alloc.Next()
+
+	// Simulate a restart after both tasks were purged.
+	restarted := NewSequenceAllocator(alloc.Last())
+	third := restarted.Next()
+
+	if first != 1 || second != 2 || third != 3 {
+		t.Errorf("got sequence %d, %d, %d; want 1, 2, 3", first, second, third)
+	}
+}
+
+// TestSequenceAllocator_Assign verifies numbering of new tasks and collision
+// resolution in favor of the earliest-created task.
+func TestSequenceAllocator_Assign(t *testing.T) {
+	base := time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC)
+	older := &Task{ID: NewTaskID(), Seq: 3, CreatedAt: base}
+	newer := &Task{ID: NewTaskID(), Seq: 3, CreatedAt: base.Add(time.Hour)}
+	fresh := &Task{ID: NewTaskID(), CreatedAt: base.Add(2 * time.Hour)}
+
+	alloc := NewSequenceAllocator(1)
+	alloc.Assign([]*Task{newer, older, fresh})
+
+	if older.Seq != 3 {
+		t.Errorf("older task Seq = %d, want 3", older.Seq)
+	}
+	if newer.Seq == 3 || newer.Seq <= 3 {
+		t.Errorf("newer task Seq = %d, want a fresh number above 3", newer.Seq)
+	}
+	if fresh.Seq <= 3 || fresh.Seq == newer.Seq {
+		t.Errorf("fresh task Seq = %d, want a unique number above 3", fresh.Seq)
+	}
+	if alloc.Last() != 5 {
+		t.Errorf("Last() = %d, want 5", alloc.Last())
+	}
+}
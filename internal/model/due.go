@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// StartOfDay returns midnight at the beginning of t's calendar day in t's
+// location.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// dayIn converts t into loc and truncates it to the start of that day.
+func dayIn(t time.Time, loc *time.Location) time.Time {
+	return StartOfDay(t.In(loc))
+}
+
+// IsOverdue reports whether the task's due date falls on a calendar day
+// before now's day. Days are evaluated in now's location, so a task due
+// "today" is never overdue regardless of the time component stored.
+// Completed tasks and tasks without a due date are never overdue.
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.DueDate == nil || t.Status == StatusDone {
+		return false
+	}
+	return dayIn(*t.DueDate, now.Location()).Before(StartOfDay(now))
+}
+
+// IsDueToday reports whether the task's due date falls on now's calendar day
+// in now's location.
+func (t *Task) IsDueToday(now time.Time) bool {
+	if t.DueDate == nil {
+		return false
+	}
+	return dayIn(*t.DueDate, now.Location()).Equal(StartOfDay(now))
+}
+
+// IsDueWithin reports whether the task is due between today and the calendar
+// day containing now+d, inclusive. Overdue tasks are not considered "due
+// within" a window; use IsOverdue for those.
+func (t *Task) IsDueWithin(d time.Duration, now time.Time) bool {
+	if t.DueDate == nil {
+		return false
+	}
+	due := dayIn(*t.DueDate, now.Location())
+	return !due.Before(StartOfDay(now)) && !due.After(StartOfDay(now.Add(d)))
+}
+
+// IsScheduledBy reports whether the task is scheduled for now's calendar
+// day or an earlier one, in now's location.
+func (t *Task) IsScheduledBy(now time.Time) bool {
+	if t.ScheduledFor == nil {
+		return false
+	}
+	return !dayIn(*t.ScheduledFor, now.Location()).After(StartOfDay(now))
+}
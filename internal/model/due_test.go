@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTask_DuePredicates verifies calendar-day semantics of the due date
+// helpers, including time-of-day and timezone edge cases.
+func TestTask_DuePredicates(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, loc) // Wednesday morning
+	at := func(day, hour int) *time.Time {
+		d := time.Date(2025, 11, day, hour, 0, 0, 0, loc)
+		return &d
+	}
+	// 02:00 UTC on the 12th is still the evening of the 11th in UTC-5.
+	lateYesterdayUTC := time.Date(2025, 11, 12, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		task       *Task
+		overdue    bool
+		dueToday   bool
+		within3Day bool
+	}{
+		{name: "no due date", task: &Task{}, overdue: false, dueToday: false, within3Day: false},
+		{name: "due earlier today", task: &Task{DueDate: at(12, 1)}, overdue: false, dueToday: true, within3Day: true},
+		{name: "due later today", task: &Task{DueDate: at(12, 23)}, overdue: false, dueToday: true, within3Day: true},
+		{name: "due yesterday", task: &Task{DueDate: at(11, 23)}, overdue: true, dueToday: false, within3Day: false},
+		{name: "due yesterday in local zone", task: &Task{DueDate: &lateYesterdayUTC}, overdue: true, dueToday: false, within3Day: false},
+		{name: "due in three days", task: &Task{DueDate: at(15, 23)}, overdue: false, dueToday: false, within3Day: true},
+		{name: "due in four days", task: &Task{DueDate: at(16, 0)}, overdue: false, dueToday: false, within3Day: false},
+		{name: "done task is never overdue", task: &Task{Status: StatusDone, DueDate: at(1, 0)}, overdue: false, dueToday: false, within3Day: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.IsOverdue(now); got != tt.overdue {
+				t.Errorf("IsOverdue() = %v, want %v", got, tt.overdue)
+			}
+			if got := tt.task.IsDueToday(now); got != tt.dueToday {
+				t.Errorf("IsDueToday() = %v, want %v", got, tt.dueToday)
+			}
+			if got := tt.task.IsDueWithin(3*24*time.Hour, now); got != tt.within3Day {
+				t.Errorf("IsDueWithin(3d) = %v, want %v", got, tt.within3Day)
+			}
+		})
+	}
+}
+
+// TestStartOfDay verifies truncation to local midnight.
+func TestStartOfDay(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	in := time.Date(2025, 11, 12, 17, 45, 30, 99, loc)
+	want := time.Date(2025, 11, 12, 0, 0, 0, 0, loc)
+
+	if got := StartOfDay(in); !got.Equal(want) {
+		t.Errorf("StartOfDay() = %v, want %v", got, want)
+	}
+}
+
+// TestTask_IsScheduledBy verifies a task counts as scheduled from the
+// start of its scheduled day in now's location, and stays so afterwards.
+func TestTask_IsScheduledBy(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, loc)
+	at := func(tm time.Time) *time.Time { return &tm }
+
+	tests := []struct {
+		name      string
+		scheduled *time.Time
+		want      bool
+	}{
+		{name: "unscheduled", want: false},
+		{name: "later today", scheduled: at(time.Date(2025, 11, 12, 23, 0, 0, 0, loc)), want: true},
+		{name: "last week", scheduled: at(time.Date(2025, 11, 5, 0, 0, 0, 0, loc)), want: true},
+		{name: "tomorrow", scheduled: at(time.Date(2025, 11, 13, 0, 0, 0, 0, loc)), want: false},
+		{name: "tomorrow in UTC, today here", scheduled: at(time.Date(2025, 11, 13, 2, 0, 0, 0, time.UTC)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{ScheduledFor: tt.scheduled}
+			if got := task.IsScheduledBy(now); got != tt.want {
+				t.Errorf("IsScheduledBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEditLease is how long an edit lock stays valid without renewal.
+const DefaultEditLease = 2 * time.Minute
+
+// EditLock records that a device is editing a task. Locks are advisory: they
+// let cooperating clients warn before clobbering each other's edits, and they
+// expire on their own so a crashed client never blocks a task forever.
+type EditLock struct {
+	TaskID     TaskID    `json:"task_id"`
+	Device     string    `json:"device"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the lease has run out at now.
+func (l EditLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Describe returns the user-facing notice shown while the lock is held.
+func (l EditLock) Describe() string {
+	return fmt.Sprintf("being edited on %s", l.Device)
+}
+
+// LockedError reports that another device holds the edit lock on a task.
+// It matches ErrTaskLocked with errors.Is.
+type LockedError struct {
+	Lock EditLock
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("task %s is %s", e.Lock.TaskID.Short(), e.Lock.Describe())
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrTaskLocked
+}
+
+// EditLocks is a thread-safe table of advisory edit locks keyed by task.
+type EditLocks struct {
+	mu    sync.Mutex
+	lease time.Duration
+	locks map[TaskID]EditLock
+}
+
+// NewEditLocks creates an empty lock table. A non-positive lease uses
+// DefaultEditLease.
+func NewEditLocks(lease time.Duration) *EditLocks {
+	if lease <= 0 {
+		lease = DefaultEditLease
+	}
+	return &EditLocks{lease: lease, locks: make(map[TaskID]EditLock)}
+}
+
+// Acquire takes or renews the edit lock on id for device.
+//
+// Returns a *LockedError if a different device holds an unexpired lock.
+func (l *EditLocks) Acquire(id TaskID, device string) (EditLock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := Now()
+	if held, ok := l.locks[id]; ok && held.Device != device && !held.Expired(now) {
+		return EditLock{}, &LockedError{Lock: held}
+	}
+
+	lock := EditLock{TaskID: id, Device: device, AcquiredAt: now, ExpiresAt: now.Add(l.lease)}
+	if held, ok := l.locks[id]; ok && held.Device == device && !held.Expired(now) {
+		lock.AcquiredAt = held.AcquiredAt
+	}
+	l.locks[id] = lock
+	return lock, nil
+}
+
+// Release drops the lock on id if device holds it. Releasing a lock held by
+// another device is a no-op.
+func (l *EditLocks) Release(id TaskID, device string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if held, ok := l.locks[id]; ok && held.Device == device {
+		delete(l.locks, id)
+	}
+}
+
+// Holder returns the unexpired lock on id, if any. Expired locks are pruned.
+func (l *EditLocks) Holder(id TaskID) (EditLock, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	held, ok := l.locks[id]
+	if !ok {
+		return EditLock{}, false
+	}
+	if held.Expired(Now()) {
+		delete(l.locks, id)
+		return EditLock{}, false
+	}
+	return held, true
+}
+
+// Held returns the unexpired locks, ordered by when they were taken, so they
+// can be shared with another process and restored there with Restore.
+// Expired locks are pruned.
+func (l *EditLocks) Held() []EditLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := Now()
+	held := make([]EditLock, 0, len(l.locks))
+	for id, lock := range l.locks {
+		if lock.Expired(now) {
+			delete(l.locks, id)
+			continue
+		}
+		held = append(held, lock)
+	}
+	slices.SortFunc(held, func(a, b EditLock) int {
+		return cmp.Or(a.AcquiredAt.Compare(b.AcquiredAt), strings.Compare(a.TaskID.String(), b.TaskID.String()))
+	})
+	return held
+}
+
+// Restore adds locks, such as Held returned, to the table, replacing any on
+// the same tasks.
+func (l *EditLocks) Restore(locks []EditLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, lock := range locks {
+		l.locks[lock.TaskID] = lock
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestEditLocks_AcquireConflict verifies that a second device is refused while
+// the first holds an unexpired lock, and that the error names the holder.
+func TestEditLocks_AcquireConflict(t *testing.T) {
+	restore := SetClock(NewFixedClock(time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC)))
+	defer restore()
+
+	locks := NewEditLocks(time.Minute)
+	id := NewTaskID()
+
+	if _, err := locks.Acquire(id, "laptop"); err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	_, err := locks.Acquire(id, "desktop")
+	if !errors.Is(err, ErrTaskLocked) {
+		t.Fatalf("expected ErrTaskLocked, got %v", err)
+	}
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected *LockedError, got %T", err)
+	}
+	if got := locked.Lock.Describe(); got != "being edited on laptop" {
+		t.Errorf("Describe() = %q, want %q", got, "being edited on laptop")
+	}
+}
+
+// TestEditLocks_LeaseExpiry verifies that an abandoned lock can be taken over
+// once its lease has expired.
+func TestEditLocks_LeaseExpiry(t *testing.T) {
+	clk := NewFixedClock(time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC))
+	restore := SetClock(clk)
+	defer restore()
+
+	locks := NewEditLocks(time.Minute)
+	id := NewTaskID()
+	if _, err := locks.Acquire(id, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(time.Minute)
+	if _, ok := locks.Holder(id); ok {
+		t.Error("expected expired lock to have no holder")
+	}
+	if _, err := locks.Acquire(id, "desktop"); err != nil {
+		t.Errorf("expected takeover after expiry, got %v", err)
+	}
+}
+
+// TestEditLocks_RenewKeepsAcquiredAt verifies that re-acquiring by the same
+// device extends the lease without resetting AcquiredAt.
+func TestEditLocks_RenewKeepsAcquiredAt(t *testing.T) {
+	start := time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC)
+	clk := NewFixedClock(start)
+	restore := SetClock(clk)
+	defer restore()
+
+	locks := NewEditLocks(time.Minute)
+	id := NewTaskID()
+	if _, err := locks.Acquire(id, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(30 * time.Second)
+	renewed, err := locks.Acquire(id, "laptop")
+	if err != nil {
+		t.Fatalf("renew failed: %v", err)
+	}
+	if !renewed.AcquiredAt.Equal(start) {
+		t.Errorf("AcquiredAt = %v, want %v", renewed.AcquiredAt, start)
+	}
+	if want := start.Add(90 * time.Second); !renewed.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", renewed.ExpiresAt, want)
+	}
+}
+
+// TestEditLocks_Release verifies that only the holder can release a lock.
+func TestEditLocks_Release(t *testing.T) {
+	locks := NewEditLocks(0)
+	id := NewTaskID()
+	if _, err := locks.Acquire(id, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	locks.Release(id, "desktop")
+	if _, ok := locks.Holder(id); !ok {
+		t.Fatal("release by non-holder should not drop the lock")
+	}
+
+	locks.Release(id, "laptop")
+	if _, ok := locks.Holder(id); ok {
+		t.Error("expected lock to be released by its holder")
+	}
+}
+
+// TestEditLocks_HeldRestore verifies that the unexpired locks carry over to
+// another table, oldest first, and still exclude other devices there.
+func TestEditLocks_HeldRestore(t *testing.T) {
+	clk := NewFixedClock(time.Date(2025, 11, 9, 9, 0, 0, 0, time.UTC))
+	restore := SetClock(clk)
+	defer restore()
+
+	locks := NewEditLocks(time.Minute)
+	expiring, first, second := NewTaskID(), NewTaskID(), NewTaskID()
+	for _, id := range []TaskID{expiring, first, second} {
+		if _, err := locks.Acquire(id, "laptop"); err != nil {
+			t.Fatal(err)
+		}
+		clk.Advance(20 * time.Second)
+	}
+
+	held := locks.Held()
+	if len(held) != 2 || held[0].TaskID != first || held[1].TaskID != second {
+		t.Fatalf("Held() = %v, want the locks on %s then %s", held, first.Short(), second.Short())
+	}
+	other := NewEditLocks(time.Minute)
+	other.Restore(held)
+	if _, err := other.Acquire(first, "desktop"); !errors.Is(err, ErrTaskLocked) {
+		t.Errorf("Acquire() on a restored lock error = %v, want ErrTaskLocked", err)
+	}
+}
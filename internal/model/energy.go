@@ -0,0 +1,36 @@
+package model
+
+// Energy describes how much focus a task demands, so low-energy work can be
+// picked out at the end of the day. The zero value means unspecified.
+type Energy string
+
+const (
+	EnergyNone   Energy = ""
+	EnergyLow    Energy = "low"
+	EnergyMedium Energy = "medium"
+	EnergyHigh   Energy = "high"
+)
+
+// Valid reports whether e is a known energy level, including EnergyNone.
+func (e Energy) Valid() bool {
+	switch e {
+	case EnergyNone, EnergyLow, EnergyMedium, EnergyHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e Energy) String() string {
+	return string(e)
+}
+
+// ParseEnergy accepts an energy level name or its first letter, in any case.
+// The empty string yields EnergyNone.
+func ParseEnergy(s string) (Energy, error) {
+	level, ok := parseLevel(s)
+	if !ok {
+		return EnergyNone, levelError("energy", s)
+	}
+	return Energy(level), nil
+}
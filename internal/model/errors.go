@@ -3,6 +3,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -20,6 +21,19 @@ var (
 
 	// ErrDuplicateTaskID indicates a task with the same ID already exists.
 	ErrDuplicateTaskID = errors.New("task with this ID already exists")
+
+	// ErrAmbiguousID indicates a partial ID matches more than one task.
+	ErrAmbiguousID = errors.New("ambiguous task ID")
+
+	// ErrTaskLocked indicates another device holds the edit lock on a task.
+	ErrTaskLocked = errors.New("task is locked for editing")
+
+	// ErrStaleTask indicates a task was saved elsewhere since this copy of
+	// it was read, so saving the copy would discard that change.
+	ErrStaleTask = errors.New("task was changed since it was read")
+
+	// ErrNotRecurring indicates an operation needs a recurring template.
+	ErrNotRecurring = errors.New("task is not recurring")
 )
 
 // ValidationError wraps validation failures with field and reason information.
@@ -31,3 +45,67 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation failed for %s: %s", e.Field, e.Reason)
 }
+
+// ValidationErrors aggregates every validation failure found in one pass, so
+// callers can report all problems with a task at once.
+//
+// errors.As can extract either the aggregate or, through Unwrap, the first
+// individual *ValidationError.
+type ValidationErrors []*ValidationError
+
+// Append records a failure for field.
+func (v *ValidationErrors) Append(field, reason string) {
+	*v = append(*v, &ValidationError{Field: field, Reason: reason})
+}
+
+// HasErrors reports whether any failure was recorded.
+func (v ValidationErrors) HasErrors() bool {
+	return len(v) > 0
+}
+
+// Err returns v as an error, or nil when no failure was recorded. Use it
+// instead of returning v directly to avoid a non-nil error holding an empty
+// slice.
+func (v ValidationErrors) Err() error {
+	if !v.HasErrors() {
+		return nil
+	}
+	return v
+}
+
+func (v ValidationErrors) Error() string {
+	if len(v) == 1 {
+		return v[0].Error()
+	}
+	parts := make([]string, len(v))
+	for i, e := range v {
+		parts[i] = fmt.Sprintf("%s: %s", e.Field, e.Reason)
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(parts, "; "))
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As.
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, e := range v {
+		errs[i] = e
+	}
+	return errs
+}
+
+// TaskError records which task and which operation an error came from, so
+// messages that reach the UI identify the task. It unwraps to the underlying
+// error, so errors.Is still matches sentinels such as ErrTaskNotFound.
+type TaskError struct {
+	ID  TaskID
+	Op  string
+	Err error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("%s task %s: %v", e.Op, e.ID.Short(), e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -84,6 +85,8 @@ func TestDomainErrors_AreUnique(t *testing.T) {
 		ErrInvalidStateTransition,
 		ErrEmptyTitle,
 		ErrDuplicateTaskID,
+		ErrAmbiguousID,
+		ErrTaskLocked,
 	}
 
 	// Compare each error with every other error
@@ -252,6 +255,8 @@ func TestDomainErrors_AllDefined(t *testing.T) {
 		{"ErrInvalidStateTransition", ErrInvalidStateTransition},
 		{"ErrEmptyTitle", ErrEmptyTitle},
 		{"ErrDuplicateTaskID", ErrDuplicateTaskID},
+		{"ErrAmbiguousID", ErrAmbiguousID},
+		{"ErrTaskLocked", ErrTaskLocked},
 	}
 
 	for _, tt := range tests {
@@ -291,3 +296,83 @@ func TestValidationError_CanBeUsedAsError(t *testing.T) {
 		}
 	})
 }
+
+// TestValidationErrors_AppendAndHasErrors verifies accumulation and the nil
+// conversion performed by Err.
+func TestValidationErrors_AppendAndHasErrors(t *testing.T) {
+	var errs ValidationErrors
+	if errs.HasErrors() {
+		t.Error("empty ValidationErrors reports errors")
+	}
+	if errs.Err() != nil {
+		t.Error("Err() on empty ValidationErrors should be nil")
+	}
+
+	errs.Append("title", "must not be empty")
+	errs.Append("tags", "too many")
+
+	if !errs.HasErrors() || len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(errs))
+	}
+	if errs.Err() == nil {
+		t.Error("Err() should be non-nil after Append")
+	}
+}
+
+// TestValidationErrors_Error verifies single and multiple error messages.
+func TestValidationErrors_Error(t *testing.T) {
+	single := ValidationErrors{{Field: "title", Reason: "must not be empty"}}
+	if got, want := single.Error(), "validation failed for title: must not be empty"; got != want {
+		t.Errorf("single Error() = %q, want %q", got, want)
+	}
+
+	multi := ValidationErrors{
+		{Field: "title", Reason: "must not be empty"},
+		{Field: "status", Reason: "unknown"},
+	}
+	if got, want := multi.Error(), "validation failed: title: must not be empty; status: unknown"; got != want {
+		t.Errorf("multi Error() = %q, want %q", got, want)
+	}
+}
+
+// TestValidationErrors_ErrorsAs verifies both the aggregate and individual
+// failures can be extracted, including through wrapping.
+func TestValidationErrors_ErrorsAs(t *testing.T) {
+	var errs ValidationErrors
+	errs.Append("title", "must not be empty")
+	errs.Append("status", "unknown")
+	wrapped := fmt.Errorf("importing line 3: %w", errs.Err())
+
+	var all ValidationErrors
+	if !errors.As(wrapped, &all) || len(all) != 2 {
+		t.Fatalf("errors.As(ValidationErrors) failed, got %v", all)
+	}
+
+	var first *ValidationError
+	if !errors.As(wrapped, &first) {
+		t.Fatal("errors.As(*ValidationError) failed")
+	}
+	if first.Field != "title" {
+		t.Errorf("first failure Field = %q, want %q", first.Field, "title")
+	}
+}
+
+// TestTaskError verifies that TaskError names the operation and task and
+// unwraps to the underlying sentinel.
+func TestTaskError(t *testing.T) {
+	id := NewTaskID()
+	err := fmt.Errorf("service: %w", &TaskError{ID: id, Op: "complete", Err: ErrInvalidStateTransition})
+
+	want := "service: complete task " + id.Short() + ": invalid state transition"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if !errors.Is(err, ErrInvalidStateTransition) {
+		t.Error("errors.Is(err, ErrInvalidStateTransition) = false")
+	}
+
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.ID != id || taskErr.Op != "complete" {
+		t.Errorf("errors.As extracted %+v", taskErr)
+	}
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// externalIDNamespace is the UUIDv5 namespace for IDs derived from other
+// systems. It must never change: doing so would make re-imports duplicate
+// every previously imported task.
+var externalIDNamespace = uuid.MustParse("6f1c0d2e-5b7a-4c35-9f44-2d8e3c1a7b90")
+
+// ExternalRef records where an imported task came from.
+type ExternalRef struct {
+	// System identifies the source, e.g. "todoist", "jira" or "todotxt".
+	System string `json:"system"`
+	// ID is the task's identifier in the source system.
+	ID string `json:"id"`
+}
+
+// IsZero reports whether the reference is unset.
+func (r ExternalRef) IsZero() bool {
+	return r.System == "" && r.ID == ""
+}
+
+func (r ExternalRef) String() string {
+	return r.System + ":" + r.ID
+}
+
+// key returns r with its system name normalized, for comparing references
+// as TaskIDFromExternal does: the system without regard to case, the ID
+// exactly.
+func (r ExternalRef) key() ExternalRef {
+	return ExternalRef{System: strings.ToLower(strings.TrimSpace(r.System)), ID: r.ID}
+}
+
+// IDGenerator produces identities for new tasks. Importers that preserve
+// external identities use ExternalIDGenerator so that importing the same
+// source twice updates tasks instead of duplicating them.
+type IDGenerator interface {
+	NewID(ref ExternalRef) TaskID
+}
+
+// RandomIDGenerator issues a fresh random UUID for every task, ignoring the
+// external reference.
+type RandomIDGenerator struct{}
+
+func (RandomIDGenerator) NewID(ExternalRef) TaskID {
+	return NewTaskID()
+}
+
+// ExternalIDGenerator derives deterministic IDs from external references and
+// falls back to random IDs for tasks without one.
+type ExternalIDGenerator struct{}
+
+func (ExternalIDGenerator) NewID(ref ExternalRef) TaskID {
+	if ref.IsZero() {
+		return NewTaskID()
+	}
+	return TaskIDFromExternal(ref)
+}
+
+// TaskIDFromExternal derives a deterministic UUIDv5 TaskID from an external
+// reference. The system name is case-insensitive; the external ID is not,
+// since many systems use case-sensitive identifiers.
+func TaskIDFromExternal(ref ExternalRef) TaskID {
+	ref = ref.key()
+	name := ref.System + "\x00" + ref.ID
+	return TaskID(uuid.NewSHA1(externalIDNamespace, []byte(name)))
+}
+
+// XRefEntry maps an external reference to the task imported from it.
+type XRefEntry struct {
+	Ref    ExternalRef
+	TaskID TaskID
+}
+
+// XRefTable indexes imported tasks by their external references. System
+// names are compared without regard to case, as TaskIDFromExternal does.
+type XRefTable struct {
+	entries []XRefEntry
+	byRef   map[ExternalRef]TaskID
+}
+
+// BuildXRefTable indexes every task carrying an external reference.
+func BuildXRefTable(tasks []*Task) *XRefTable {
+	table := &XRefTable{byRef: make(map[ExternalRef]TaskID)}
+	for _, t := range tasks {
+		if t.ExternalRef == nil || t.ExternalRef.IsZero() {
+			continue
+		}
+		ref := *t.ExternalRef
+		table.entries = append(table.entries, XRefEntry{Ref: ref, TaskID: t.ID})
+		table.byRef[ref.key()] = t.ID
+	}
+	return table
+}
+
+// Get returns the task imported from ref, if any.
+func (x *XRefTable) Get(ref ExternalRef) (TaskID, bool) {
+	id, ok := x.byRef[ref.key()]
+	return id, ok
+}
+
+// Lookup finds entries by external ID across all systems. The query may be
+// qualified as "system:id" to restrict the match to one system.
+func (x *XRefTable) Lookup(query string) []XRefEntry {
+	system, id, qualified := strings.Cut(query, ":")
+	if !qualified {
+		id, system = query, ""
+	}
+
+	var matches []XRefEntry
+	for _, e := range x.entries {
+		if e.Ref.ID != id {
+			continue
+		}
+		if qualified && !strings.EqualFold(e.Ref.System, system) {
+			continue
+		}
+		matches = append(matches, e)
+	}
+	return matches
+}
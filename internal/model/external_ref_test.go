@@ -0,0 +1,75 @@
+package model
+
+import "testing"
+
+// TestTaskIDFromExternal_Deterministic verifies that the same reference always
+// yields the same ID and that different references do not collide.
+func TestTaskIDFromExternal_Deterministic(t *testing.T) {
+	ref := ExternalRef{System: "todoist", ID: "8812"}
+
+	first := TaskIDFromExternal(ref)
+	second := TaskIDFromExternal(ExternalRef{System: " Todoist ", ID: "8812"})
+	if !first.Equals(second) {
+		t.Errorf("expected stable ID, got %s and %s", first, second)
+	}
+	if first.IsEmpty() {
+		t.Error("expected non-empty ID")
+	}
+
+	others := []ExternalRef{
+		{System: "jira", ID: "8812"},
+		{System: "todoist", ID: "8813"},
+		{System: "todoist", ID: "8812 "},
+	}
+	for _, other := range others {
+		if TaskIDFromExternal(other).Equals(first) {
+			t.Errorf("reference %v collides with %v", other, ref)
+		}
+	}
+}
+
+// TestIDGenerators verifies random and external-preserving generation.
+func TestIDGenerators(t *testing.T) {
+	ref := ExternalRef{System: "jira", ID: "PROJ-1"}
+
+	if a, b := (RandomIDGenerator{}).NewID(ref), (RandomIDGenerator{}).NewID(ref); a.Equals(b) {
+		t.Error("RandomIDGenerator returned the same ID twice")
+	}
+
+	gen := ExternalIDGenerator{}
+	if got, want := gen.NewID(ref), TaskIDFromExternal(ref); !got.Equals(want) {
+		t.Errorf("ExternalIDGenerator.NewID() = %s, want %s", got, want)
+	}
+	if a, b := gen.NewID(ExternalRef{}), gen.NewID(ExternalRef{}); a.Equals(b) || a.IsEmpty() {
+		t.Error("ExternalIDGenerator should fall back to random IDs for empty references")
+	}
+}
+
+// TestXRefTable_Lookup verifies lookup by bare and system-qualified IDs.
+func TestXRefTable_Lookup(t *testing.T) {
+	jira := &Task{ID: NewTaskID(), ExternalRef: &ExternalRef{System: "jira", ID: "42"}}
+	trello := &Task{ID: NewTaskID(), ExternalRef: &ExternalRef{System: "trello", ID: "42"}}
+	native := &Task{ID: NewTaskID()}
+	table := BuildXRefTable([]*Task{jira, trello, native})
+
+	if got := table.Lookup("42"); len(got) != 2 {
+		t.Errorf("Lookup(42) returned %d entries, want 2", len(got))
+	}
+	got := table.Lookup("JIRA:42")
+	if len(got) != 1 || !got[0].TaskID.Equals(jira.ID) {
+		t.Errorf("Lookup(JIRA:42) = %v, want the jira task", got)
+	}
+	if got := table.Lookup("99"); len(got) != 0 {
+		t.Errorf("Lookup(99) = %v, want no entries", got)
+	}
+
+	for _, ref := range []ExternalRef{{System: "trello", ID: "42"}, {System: " Trello", ID: "42"}} {
+		id, ok := table.Get(ref)
+		if !ok || !id.Equals(trello.ID) {
+			t.Errorf("Get(%v) = %s, %v; want trello task", ref, id, ok)
+		}
+	}
+	if _, ok := table.Get(ExternalRef{System: "trello", ID: "42 "}); ok {
+		t.Error("Get() matched an external ID differing in spacing")
+	}
+}
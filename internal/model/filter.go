@@ -0,0 +1,67 @@
+package model
+
+// Filter selects tasks. TaskFilter covers the common criteria; And, Or and
+// Not combine filters into queries the struct cannot express, such as
+// "tagged work, or due today and not tagged someday":
+//
+//	f := Or(
+//		TaskFilter{Tags: []string{"work"}},
+//		And(dueToday, Not(TaskFilter{Tags: []string{"someday"}})),
+//	)
+type Filter interface {
+	Matches(t *Task) bool
+}
+
+var _ Filter = TaskFilter{}
+
+// FilterFunc adapts an ordinary function to the Filter interface.
+type FilterFunc func(t *Task) bool
+
+// Matches calls fn(t).
+func (fn FilterFunc) Matches(t *Task) bool {
+	return fn(t)
+}
+
+// And returns a filter matching tasks that match every one of filters. With
+// no filters it matches every task.
+func And(filters ...Filter) Filter {
+	return FilterFunc(func(t *Task) bool {
+		for _, f := range filters {
+			if !f.Matches(t) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Or returns a filter matching tasks that match at least one of filters.
+// With no filters it matches no task.
+func Or(filters ...Filter) Filter {
+	return FilterFunc(func(t *Task) bool {
+		for _, f := range filters {
+			if f.Matches(t) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not returns a filter matching exactly the tasks f does not.
+func Not(f Filter) Filter {
+	return FilterFunc(func(t *Task) bool {
+		return !f.Matches(t)
+	})
+}
+
+// Select returns the tasks matching f, in order.
+func Select(tasks []*Task, f Filter) []*Task {
+	var out []*Task
+	for _, t := range tasks {
+		if f.Matches(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package model_test
+
+import (
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestFilterCombinators verifies And, Or and Not, including their empty
+// forms.
+func TestFilterCombinators(t *testing.T) {
+	work := model.TaskFilter{Tags: []string{"work"}}
+	someday := model.TaskFilter{Tags: []string{"someday"}}
+	titled := model.FilterFunc(func(t *model.Task) bool { return t.Title == "Plan" })
+
+	task := testutil.NewTask().WithTitle("Plan").WithTags("work").Build()
+
+	tests := []struct {
+		name   string
+		filter model.Filter
+		want   bool
+	}{
+		{"and all match", model.And(work, titled), true},
+		{"and one fails", model.And(work, someday), false},
+		{"empty and matches", model.And(), true},
+		{"or one matches", model.Or(someday, titled), true},
+		{"or none match", model.Or(someday, model.Not(work)), false},
+		{"empty or rejects", model.Or(), false},
+		{"not inverts", model.Not(someday), true},
+		{"nested", model.Or(someday, model.And(work, model.Not(someday))), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(task); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSelect verifies matching tasks are returned in order.
+func TestSelect(t *testing.T) {
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle("a").WithTags("work"),
+		testutil.NewTask().WithTitle("b"),
+		testutil.NewTask().WithTitle("c").WithTags("work"),
+	)
+
+	got := model.Select(tasks, model.TaskFilter{Tags: []string{"work"}})
+	if len(got) != 2 || got[0].Title != "a" || got[1].Title != "c" {
+		t.Errorf("Select() = %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scoring constants for FuzzyScore, loosely following fzf: every matched
+// character earns a base score, matches at word boundaries and runs of
+// consecutive matches earn bonuses, and gaps between matches cost points.
+// A match starting the string gets a small extra bonus so prefixes rank
+// first among otherwise equal matches.
+const (
+	fuzzyMatch       = 16
+	fuzzyBoundary    = 8
+	fuzzyFirstChar   = 8
+	fuzzyPrefix      = 4
+	fuzzyConsecutive = 4
+	fuzzyGapStart    = 3
+	fuzzyGapExtend   = 1
+)
+
+// FuzzyScore reports whether every rune of pattern appears in s in order,
+// ignoring case, and if so how good the match is. Higher scores mean tighter
+// matches: "rp" scores higher against "Renew passport" than against
+// "Reorder paper", because both letters start words in the former.
+//
+// An empty pattern matches everything with a score of zero.
+func FuzzyScore(pattern, s string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	p := []rune(foldString(pattern))
+	r := []rune(foldString(s))
+
+	// Find the earliest end of a full match, then walk backwards from there
+	// to the latest start, which yields the shortest window containing it.
+	end, pi := -1, 0
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if r[i] == p[pi] {
+			pi++
+			if pi == len(p) {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+	start, pi := end, len(p)-1
+	for ; start >= 0; start-- {
+		if r[start] == p[pi] {
+			if pi == 0 {
+				break
+			}
+			pi--
+		}
+	}
+
+	orig := []rune(s)
+	score, pi, prev := 0, 0, -1
+	for i := start; i <= end && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+		score += fuzzyMatch
+		if i == 0 {
+			score += fuzzyPrefix
+		}
+		if i == 0 || isWordBoundary(orig, i) {
+			score += fuzzyBoundary
+			if pi == 0 {
+				score += fuzzyFirstChar
+			}
+		}
+		if prev >= 0 {
+			if gap := i - prev - 1; gap == 0 {
+				score += fuzzyConsecutive
+			} else {
+				score -= fuzzyGapStart + (gap-1)*fuzzyGapExtend
+			}
+		}
+		prev = i
+		pi++
+	}
+	return score, true
+}
+
+// isWordBoundary reports whether rs[i] starts a word: it follows a
+// non-letter, non-digit, or is an upper-case letter after a lower-case one.
+func isWordBoundary(rs []rune, i int) bool {
+	before, cur := rs[i-1], rs[i]
+	if !unicode.IsLetter(before) && !unicode.IsDigit(before) {
+		return true
+	}
+	return unicode.IsLower(before) && unicode.IsUpper(cur)
+}
+
+// foldString lower-cases s rune by rune so rune offsets line up with s.
+func foldString(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = utf8.AppendRune(b, unicode.ToLower(r))
+	}
+	return string(b)
+}
@@ -0,0 +1,53 @@
+package model
+
+import "testing"
+
+// TestFuzzyScore_Matching verifies subsequence matching rules.
+func TestFuzzyScore_Matching(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"rp", "Renew passport", true},
+		{"RNW", "renew", true},
+		{"tn", "Renew passport", false},
+		{"renewal", "renew", false},
+		{"éc", "École", true},
+		{"x", "", false},
+	}
+
+	for _, tt := range tests {
+		if _, got := FuzzyScore(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("FuzzyScore(%q, %q) matched = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestFuzzyScore_Ranking verifies tighter matches outscore looser ones.
+func TestFuzzyScore_Ranking(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		better, worse string
+	}{
+		{"word starts beat mid-word", "rp", "Renew passport", "Reorder paper"},
+		{"consecutive beats scattered", "pass", "Renew passport", "Plan a summer sale"},
+		{"shorter gap wins", "ab", "a b", "a       b"},
+		{"prefix beats later word", "tax", "Taxes due", "File taxes"},
+		{"camel case boundary", "fb", "fooBar", "foobar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hi, ok1 := FuzzyScore(tt.pattern, tt.better)
+			lo, ok2 := FuzzyScore(tt.pattern, tt.worse)
+			if !ok1 || !ok2 {
+				t.Fatalf("expected both to match (%v, %v)", ok1, ok2)
+			}
+			if hi <= lo {
+				t.Errorf("FuzzyScore(%q): %q = %d, want more than %q = %d", tt.pattern, tt.better, hi, tt.worse, lo)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Default size limits. They are generous for real use and exist to stop
+// pathological input, such as a pasted log file as a title or thousands of
+// generated tags, from bloating the journal and breaking rendering.
+const (
+	DefaultMaxTitleLength = 200
+	DefaultMaxNotesLength = 100_000
+	DefaultMaxTags        = 50
+	DefaultMaxTagLength   = 64
+)
+
+// Limits bounds the size of task data. Lengths count characters, not bytes.
+// A non-positive value disables that limit.
+type Limits struct {
+	TitleLength int
+	NotesLength int
+	Tags        int
+	TagLength   int
+}
+
+// DefaultLimits returns the limits used unless configured otherwise.
+func DefaultLimits() Limits {
+	return Limits{
+		TitleLength: DefaultMaxTitleLength,
+		NotesLength: DefaultMaxNotesLength,
+		Tags:        DefaultMaxTags,
+		TagLength:   DefaultMaxTagLength,
+	}
+}
+
+var limits = DefaultLimits()
+
+// SetLimits configures the limits enforced by NewTask and Validate,
+// returning a function that restores the previous limits. Like SetClock, it
+// is not safe for concurrent use.
+func SetLimits(l Limits) (restore func()) {
+	prev := limits
+	limits = l
+	return func() { limits = prev }
+}
+
+// titleTooLong reports whether title exceeds the configured maximum, with
+// the reason to put in a ValidationError.
+func titleTooLong(title string) (reason string, tooLong bool) {
+	return exceeds(title, limits.TitleLength)
+}
+
+// checkSizes appends a violation for every configured limit the task's
+// notes or tags exceed. The title is checked separately because Validate
+// reports an empty title instead.
+func checkSizes(t *Task, errs *ValidationErrors) {
+	if reason, tooLong := exceeds(t.Notes, limits.NotesLength); tooLong {
+		errs.Append("notes", reason)
+	}
+	if reason, tooMany := tooManyTags(t.Tags); tooMany {
+		errs.Append("tags", reason)
+	}
+}
+
+// tooManyTags reports whether tags exceed the count or per-tag length limit.
+func tooManyTags(tags []string) (reason string, violated bool) {
+	if limits.Tags > 0 && len(tags) > limits.Tags {
+		return fmt.Sprintf("has %d tags, more than %d", len(tags), limits.Tags), true
+	}
+	for _, tag := range tags {
+		if reason, tooLong := exceeds(tag, limits.TagLength); tooLong {
+			return "a tag " + reason, true
+		}
+	}
+	return "", false
+}
+
+// exceeds reports whether s has more than max characters.
+func exceeds(s string, max int) (reason string, tooLong bool) {
+	if max > 0 && utf8.RuneCountInString(s) > max {
+		return fmt.Sprintf("exceeds %d characters", max), true
+	}
+	return "", false
+}
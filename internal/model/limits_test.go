@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestTask_Validate_Limits verifies every size limit is reported against its
+// field, and that disabled limits accept anything.
+func TestTask_Validate_Limits(t *testing.T) {
+	defer SetLimits(Limits{TitleLength: 20, NotesLength: 20, Tags: 3, TagLength: 5})()
+
+	tests := []struct {
+		name   string
+		mutate func(*Task)
+		field  string
+	}{
+		{"long title", func(t *Task) { t.Title = strings.Repeat("x", 21) }, "title"},
+		{"long notes", func(t *Task) { t.Notes = strings.Repeat("ü", 21) }, "notes"},
+		{"too many tags", func(t *Task) { t.Tags = []string{"a", "b", "c", "d"} }, "tags"},
+		{"long tag", func(t *Task) { t.Tags = []string{"ok", "toolong"} }, "tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := validTask()
+			tt.mutate(task)
+
+			var verrs ValidationErrors
+			if err := task.Validate(); !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != tt.field {
+				t.Fatalf("Validate() = %v, want one %s violation", err, tt.field)
+			}
+
+			restore := SetLimits(Limits{})
+			defer restore()
+			if err := task.Validate(); err != nil {
+				t.Errorf("Validate() with limits disabled = %v", err)
+			}
+		})
+	}
+}
+
+// TestNewTask_TagLimits verifies NewTask rejects oversized tag lists.
+func TestNewTask_TagLimits(t *testing.T) {
+	tags := make([]string, DefaultMaxTags+1)
+	for i := range tags {
+		tags[i] = "t"
+	}
+
+	_, err := NewTask("Tag storm", tags)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "tags" {
+		t.Errorf("NewTask() error = %v, want tags ValidationError", err)
+	}
+
+	if _, err := NewTask("Fine", tags[:DefaultMaxTags]); err != nil {
+		t.Errorf("NewTask() at the limit = %v", err)
+	}
+}
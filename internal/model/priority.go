@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority ranks how important a task is. The zero value means no priority
+// has been assigned.
+type Priority string
+
+const (
+	PriorityNone   Priority = ""
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+// Valid reports whether p is a known priority, including PriorityNone.
+func (p Priority) Valid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p Priority) String() string {
+	return string(p)
+}
+
+// ParsePriority accepts a priority name or its first letter, in any case:
+// "high", "H", "m", "low". The empty string yields PriorityNone.
+func ParsePriority(s string) (Priority, error) {
+	level, ok := parseLevel(s)
+	if !ok {
+		return PriorityNone, levelError("priority", s)
+	}
+	return Priority(level), nil
+}
+
+// parseLevel normalizes the low/medium/high scale shared by Priority and
+// Energy, accepting full names or initials in any case. The empty string is
+// a valid, unset level.
+func parseLevel(s string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", true
+	case "l", "low":
+		return "low", true
+	case "m", "medium":
+		return "medium", true
+	case "h", "high":
+		return "high", true
+	default:
+		return "", false
+	}
+}
+
+// levelError reports an unknown low/medium/high value for field.
+func levelError(field, value string) error {
+	return &ValidationError{
+		Field:  field,
+		Reason: fmt.Sprintf("must be one of low, medium, high, got %q", value),
+	}
+}
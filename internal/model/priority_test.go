@@ -0,0 +1,36 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParsePriority verifies names, initials and case are accepted and
+// unknown values rejected with a ValidationError.
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Priority
+		wantErr bool
+	}{
+		{"", PriorityNone, false},
+		{"high", PriorityHigh, false},
+		{"H", PriorityHigh, false},
+		{" Medium ", PriorityMedium, false},
+		{"l", PriorityLow, false},
+		{"urgent", PriorityNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePriority(tt.input)
+			var verr *ValidationError
+			if tt.wantErr != errors.As(err, &verr) {
+				t.Fatalf("ParsePriority(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePriority(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
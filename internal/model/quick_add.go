@@ -0,0 +1,149 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ParseQuickAdd builds a task from a single line of quick-add input. Words
+// are collected into the title, except for these tokens:
+//
+//	#tag               adds a tag (nested tags like #work/backend allowed)
+//	!h, !high          sets the priority (l, m, h or the full name)
+//	energy:low, e:l    sets the energy level
+//	due:2025-11-14     sets the due date; due:today and due:tomorrow also work
+//	est:1h30m          sets the estimate
+//	remind:15:30       sets a reminder; remind:2025-11-14T09:00 on another day
+//	every:week, every:2d  makes the task recurring from today (see ParseRecurrence)
+//	missed:catch-up    creates every missed occurrence of a recurring task
+//	                   rather than only the latest; missed:skip is the default
+//
+// Prefix a word with a backslash to keep it in the title verbatim, e.g.
+// `\#1`. Words with other colons, such as URLs, are left in the title.
+//
+// Example:
+//
+//	task, err := ParseQuickAdd("Review PR #work !h e:low due:tomorrow")
+//
+// Returns the errors of NewTask, or a *ValidationError naming the token's
+// field when a token value is malformed.
+func ParseQuickAdd(input string) (*Task, error) {
+	var (
+		words    []string
+		tags     []string
+		priority Priority
+		energy   Energy
+		due      *time.Time
+		estimate time.Duration
+		remind   *time.Time
+		recur    *Recurrence
+		missed   *MissedPolicy
+	)
+
+	for _, word := range strings.Fields(input) {
+		if literal, ok := strings.CutPrefix(word, `\`); ok {
+			words = append(words, literal)
+			continue
+		}
+		if tag, ok := strings.CutPrefix(word, "#"); ok && tag != "" {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		if level, ok := strings.CutPrefix(word, "!"); ok && level != "" {
+			p, err := ParsePriority(level)
+			if err != nil {
+				return nil, err
+			}
+			priority = p
+			continue
+		}
+
+		key, value, _ := strings.Cut(word, ":")
+		if value == "" {
+			words = append(words, word)
+			continue
+		}
+		var err error
+		switch strings.ToLower(key) {
+		case "energy", "e":
+			energy, err = ParseEnergy(value)
+		case "due":
+			due, err = parseQuickDate(value)
+		case "est":
+			estimate, err = time.ParseDuration(value)
+			if err != nil || estimate < 0 {
+				err = &ValidationError{Field: "estimate", Reason: fmt.Sprintf("expected a duration like 45m or 1h30m, got %q", value)}
+			}
+		case "remind":
+			remind, err = parseQuickRemind(value)
+		case "every":
+			recur, err = ParseRecurrence(value, Now())
+		case "missed":
+			var p MissedPolicy
+			p, err = ParseMissedPolicy(value)
+			missed = &p
+		default:
+			words = append(words, word)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if missed != nil {
+		if recur == nil {
+			return nil, &ValidationError{Field: "recurrence", Reason: "missed: needs every: as well"}
+		}
+		recur.Missed = *missed
+	}
+
+	task, err := NewTask(strings.Join(words, " "), tags)
+	if err != nil {
+		return nil, err
+	}
+	task.Priority = priority
+	task.Energy = energy
+	task.DueDate = due
+	task.Estimate = estimate
+	task.RemindAt = remind
+	task.Recurrence = recur
+	return task, nil
+}
+
+// parseQuickDate parses a quick-add due date in the clock's location.
+func parseQuickDate(value string) (*time.Time, error) {
+	now := Now()
+	var day time.Time
+	switch strings.ToLower(value) {
+	case "today":
+		day = StartOfDay(now)
+	case "tomorrow":
+		day = StartOfDay(now).AddDate(0, 0, 1)
+	default:
+		parsed, err := time.ParseInLocation(time.DateOnly, value, now.Location())
+		if err != nil {
+			return nil, &ValidationError{Field: "due_date", Reason: fmt.Sprintf("expected YYYY-MM-DD, today or tomorrow, got %q", value)}
+		}
+		day = parsed
+	}
+	return &day, nil
+}
+
+// parseQuickRemind parses a quick-add reminder in the clock's location: a
+// time of day today, or a date and time.
+func parseQuickRemind(value string) (*time.Time, error) {
+	now := Now()
+	if at, err := time.ParseInLocation("2006-01-02T15:04", value, now.Location()); err == nil {
+		return &at, nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return nil, &ValidationError{Field: "remind_at", Reason: fmt.Sprintf("expected HH:MM or YYYY-MM-DDTHH:MM, got %q", value)}
+	}
+	at := StartOfDay(now).Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	return &at, nil
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestParseQuickAdd verifies tokens are extracted into fields and the
+// remaining words form the title.
+func TestParseQuickAdd(t *testing.T) {
+	defer SetClock(NewFixedClock(time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)))()
+	tomorrow := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+	friday := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+	evening := time.Date(2025, 11, 12, 18, 30, 0, 0, time.UTC)
+	fridayMorning := time.Date(2025, 11, 14, 9, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+		want  Task
+	}{
+		{
+			name:  "plain title",
+			input: "Buy milk",
+			want:  Task{Title: "Buy milk"},
+		},
+		{
+			name:  "all tokens",
+			input: "Review PR #work/backend !h e:low due:tomorrow est:45m",
+			want: Task{
+				Title:    "Review PR",
+				Tags:     []string{"work/backend"},
+				Priority: PriorityHigh,
+				Energy:   EnergyLow,
+				DueDate:  &tomorrow,
+				Estimate: 45 * time.Minute,
+			},
+		},
+		{
+			name:  "tokens anywhere and long forms",
+			input: "energy:Medium File #admin taxes due:2025-11-14 #admin !low",
+			want: Task{
+				Title:    "File taxes",
+				Tags:     []string{"admin"},
+				Priority: PriorityLow,
+				Energy:   EnergyMedium,
+				DueDate:  &friday,
+			},
+		},
+		{
+			name:  "reminders",
+			input: "Call mum remind:18:30 #family",
+			want:  Task{Title: "Call mum", Tags: []string{"family"}, RemindAt: &evening},
+		},
+		{
+			name:  "reminder on another day",
+			input: "Dentist remind:2025-11-14T09:15",
+			want:  Task{Title: "Dentist", RemindAt: &fridayMorning},
+		},
+		{
+			name:  "recurring",
+			input: "Pay rent #home every:month missed:catch-up",
+			want: Task{
+				Title:      "Pay rent",
+				Tags:       []string{"home"},
+				Recurrence: &Recurrence{Every: 1, Unit: RecurMonthly, Next: today, Missed: MissedCatchUp},
+			},
+		},
+		{
+			name:  "escapes, URLs and bare symbols stay in the title",
+			input: `Fix \#12 per https://example.com/x re: # !`,
+			want:  Task{Title: "Fix #12 per https://example.com/x re: # !"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuickAdd(tt.input)
+			if err != nil {
+				t.Fatalf("ParseQuickAdd() error = %v", err)
+			}
+			if got.Title != tt.want.Title || !slices.Equal(got.Tags, tt.want.Tags) ||
+				got.Priority != tt.want.Priority || got.Energy != tt.want.Energy ||
+				!timePtrEqual(got.DueDate, tt.want.DueDate) || got.Estimate != tt.want.Estimate ||
+				!timePtrEqual(got.RemindAt, tt.want.RemindAt) || !recurrenceEqual(got.Recurrence, tt.want.Recurrence) {
+				t.Errorf("ParseQuickAdd() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseQuickAdd_Errors verifies malformed tokens report their field.
+func TestParseQuickAdd_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		field string
+	}{
+		{"Nap e:exhausted", "energy"},
+		{"Ship !urgent", "priority"},
+		{"Report due:friday", "due_date"},
+		{"Call est:soon", "estimate"},
+		{"Call mum remind:evening", "remind_at"},
+		{"Water plants every:fortnight", "recurrence"},
+		{"Water plants every:day missed:never", "recurrence"},
+		{"Water plants missed:skip", "recurrence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseQuickAdd(tt.input)
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Errorf("ParseQuickAdd(%q) error = %v, want %s ValidationError", tt.input, err, tt.field)
+			}
+		})
+	}
+
+	if _, err := ParseQuickAdd("#only #tags"); err != ErrEmptyTitle {
+		t.Errorf("tags-only input error = %v, want ErrEmptyTitle", err)
+	}
+}
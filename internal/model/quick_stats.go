@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuickStats summarizes a set of tasks for the one-line footer shown under
+// each list view. It is accumulated incrementally so storage backends can
+// compute it in the same pass that counts matching tasks, instead of the UI
+// rescanning every task on each render.
+type QuickStats struct {
+	Count          int
+	Overdue        int
+	CompletedToday int
+	Estimated      time.Duration
+}
+
+// Add folds a single task into the statistics. Estimated time only counts
+// work that is still open.
+func (s *QuickStats) Add(t *Task, now time.Time) {
+	s.Count++
+	if t.IsOverdue(now) {
+		s.Overdue++
+	}
+	if t.Status == StatusDone {
+		if t.CompletedAt != nil && dayIn(*t.CompletedAt, now.Location()).Equal(StartOfDay(now)) {
+			s.CompletedToday++
+		}
+		return
+	}
+	s.Estimated += t.Estimate
+}
+
+// ComputeQuickStats accumulates statistics over tasks.
+func ComputeQuickStats(tasks []*Task, now time.Time) QuickStats {
+	var s QuickStats
+	for _, t := range tasks {
+		s.Add(t, now)
+	}
+	return s
+}
+
+// Footer renders the statistics as a compact footer line, omitting zero
+// counters other than the task count.
+//
+// Example: "12 tasks · 3 overdue · ~4h30m estimated · 2 done today"
+func (s QuickStats) Footer() string {
+	noun := "tasks"
+	if s.Count == 1 {
+		noun = "task"
+	}
+	parts := []string{fmt.Sprintf("%d %s", s.Count, noun)}
+	if s.Overdue > 0 {
+		parts = append(parts, fmt.Sprintf("%d overdue", s.Overdue))
+	}
+	if s.Estimated > 0 {
+		parts = append(parts, fmt.Sprintf("~%s estimated", FormatEstimate(s.Estimated)))
+	}
+	if s.CompletedToday > 0 {
+		parts = append(parts, fmt.Sprintf("%d done today", s.CompletedToday))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatEstimate renders an estimate in hours and minutes, e.g. "1h30m".
+func FormatEstimate(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d / time.Hour)
+	m := int((d % time.Hour) / time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestComputeQuickStats verifies each counter over a mixed task set.
+func TestComputeQuickStats(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	earlierToday := now.Add(-2 * time.Hour)
+
+	tasks := []*Task{
+		{Status: StatusPool, DueDate: &yesterday, Estimate: 30 * time.Minute},
+		{Status: StatusToday, Estimate: 2 * time.Hour},
+		{Status: StatusDone, CompletedAt: &earlierToday, Estimate: time.Hour},
+		{Status: StatusDone, CompletedAt: &yesterday},
+	}
+
+	got := ComputeQuickStats(tasks, now)
+	want := QuickStats{Count: 4, Overdue: 1, CompletedToday: 1, Estimated: 150 * time.Minute}
+	if got != want {
+		t.Errorf("ComputeQuickStats() = %+v, want %+v", got, want)
+	}
+}
+
+// TestQuickStats_Footer verifies the rendered footer line.
+func TestQuickStats_Footer(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats QuickStats
+		want  string
+	}{
+		{name: "empty", stats: QuickStats{}, want: "0 tasks"},
+		{name: "singular", stats: QuickStats{Count: 1}, want: "1 task"},
+		{
+			name:  "all counters",
+			stats: QuickStats{Count: 12, Overdue: 3, CompletedToday: 2, Estimated: 270 * time.Minute},
+			want:  "12 tasks · 3 overdue · ~4h30m estimated · 2 done today",
+		},
+		{name: "whole hours", stats: QuickStats{Count: 2, Estimated: 2 * time.Hour}, want: "2 tasks · ~2h estimated"},
+		{name: "minutes only", stats: QuickStats{Count: 2, Estimated: 45 * time.Minute}, want: "2 tasks · ~45m estimated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.Footer(); got != tt.want {
+				t.Errorf("Footer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurUnit is the calendar unit a recurring task repeats in.
+type RecurUnit string
+
+const (
+	RecurDaily   RecurUnit = "day"
+	RecurWeekly  RecurUnit = "week"
+	RecurMonthly RecurUnit = "month"
+	RecurYearly  RecurUnit = "year"
+)
+
+// Valid reports whether u is a known unit.
+func (u RecurUnit) Valid() bool {
+	switch u {
+	case RecurDaily, RecurWeekly, RecurMonthly, RecurYearly:
+		return true
+	default:
+		return false
+	}
+}
+
+// MissedPolicy says what happens to the occurrences of a recurring task
+// that passed while togo was not running. The zero value is MissedSkip.
+type MissedPolicy string
+
+const (
+	// MissedSkip creates only the latest missed occurrence, for chores
+	// where doing it once makes up for every time it was missed.
+	MissedSkip MissedPolicy = ""
+	// MissedCatchUp creates every missed occurrence, for work that must
+	// happen each period, such as paying a bill.
+	MissedCatchUp MissedPolicy = "catch-up"
+)
+
+// Valid reports whether p is a known policy, including MissedSkip.
+func (p MissedPolicy) Valid() bool {
+	return p == MissedSkip || p == MissedCatchUp
+}
+
+// ParseMissedPolicy accepts "skip" or "catch-up", in any case.
+func ParseMissedPolicy(s string) (MissedPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "skip":
+		return MissedSkip, nil
+	case "catch-up", "catchup":
+		return MissedCatchUp, nil
+	default:
+		return MissedSkip, &ValidationError{Field: "recurrence", Reason: fmt.Sprintf("expected skip or catch-up, got %q", s)}
+	}
+}
+
+// Recurrence makes a task a template for a series: the template itself is
+// not worked on, and instead an instance of it is created for each
+// occurrence (see Task.Recur). Templates are excluded from TaskFilter
+// unless IncludeRecurring is set.
+type Recurrence struct {
+	// Every and Unit give the period, as in every 2 weeks.
+	Every int       `json:"every"`
+	Unit  RecurUnit `json:"unit"`
+	// Next is the start of the day of the next occurrence not yet created.
+	Next time.Time `json:"next"`
+	// Missed chooses what happens to occurrences that have passed.
+	Missed MissedPolicy `json:"missed,omitempty"`
+}
+
+// String describes the period and policy, such as "every 2 weeks" or
+// "every day, catch-up".
+func (r Recurrence) String() string {
+	s := "every " + string(r.Unit)
+	if r.Every != 1 {
+		s = fmt.Sprintf("every %d %ss", r.Every, r.Unit)
+	}
+	if r.Missed == MissedCatchUp {
+		s += ", catch-up"
+	}
+	return s
+}
+
+// validate reports why r cannot drive a series, or "".
+func (r Recurrence) validate() string {
+	switch {
+	case r.Every <= 0:
+		return "every must be positive"
+	case !r.Unit.Valid():
+		return "unit must be one of day, week, month, year"
+	case r.Next.IsZero():
+		return "next must be set"
+	case !r.Missed.Valid():
+		return "missed must be empty or catch-up"
+	}
+	return ""
+}
+
+// after returns the occurrence one period after day.
+func (r Recurrence) after(day time.Time) time.Time {
+	switch r.Unit {
+	case RecurWeekly:
+		return day.AddDate(0, 0, 7*r.Every)
+	case RecurMonthly:
+		return day.AddDate(0, r.Every, 0)
+	case RecurYearly:
+		return day.AddDate(r.Every, 0, 0)
+	default:
+		return day.AddDate(0, 0, r.Every)
+	}
+}
+
+// ParseRecurrence reads a period such as "day", "week", "2w", "3d",
+// "month" or "1y", starting with the occurrence on now's day.
+func ParseRecurrence(s string, now time.Time) (*Recurrence, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	invalid := &ValidationError{
+		Field:  "recurrence",
+		Reason: fmt.Sprintf("expected a period like day, week, 2w or 3m, got %q", s),
+	}
+	units := map[string]RecurUnit{
+		"d": RecurDaily, "day": RecurDaily,
+		"w": RecurWeekly, "week": RecurWeekly,
+		"m": RecurMonthly, "month": RecurMonthly,
+		"y": RecurYearly, "year": RecurYearly,
+	}
+	digits := len(s) - len(strings.TrimLeft(s, "0123456789"))
+	every := 1
+	if digits > 0 {
+		n, err := strconv.Atoi(s[:digits])
+		if err != nil || n <= 0 {
+			return nil, invalid
+		}
+		every = n
+	}
+	unit, ok := units[s[digits:]]
+	if !ok {
+		return nil, invalid
+	}
+	return &Recurrence{Every: every, Unit: unit, Next: StartOfDay(now)}, nil
+}
+
+// IsRecurring reports whether the task is a recurring template.
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != nil
+}
+
+// Recur creates the instances of a recurring template whose occurrences
+// fall on now's day or before, as the template's MissedPolicy allows, and
+// advances its Next past now's day. Each instance is a fresh pool task
+// copying the template's title, notes, tags, priority, energy and
+// estimate, scheduled for its occurrence.
+//
+// Returns nil when nothing is due, or a *TaskError wrapping ErrNotRecurring
+// or a *ValidationError when the task is no valid template.
+func (t *Task) Recur(now time.Time) ([]*Task, error) {
+	if t.Recurrence == nil {
+		return nil, &TaskError{ID: t.ID, Op: "recur", Err: ErrNotRecurring}
+	}
+	if reason := t.Recurrence.validate(); reason != "" {
+		return nil, &TaskError{ID: t.ID, Op: "recur", Err: &ValidationError{Field: "recurrence", Reason: reason}}
+	}
+	today := StartOfDay(now)
+	var due []time.Time
+	for r := t.Recurrence; !dayIn(r.Next, now.Location()).After(today); r.Next = r.after(r.Next) {
+		due = append(due, r.Next)
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	if t.Recurrence.Missed == MissedSkip {
+		due = due[len(due)-1:]
+	}
+
+	created := Now()
+	instances := make([]*Task, len(due))
+	for i, day := range due {
+		instances[i] = &Task{
+			ID:           NewTaskID(),
+			CreatedAt:    created,
+			UpdatedAt:    created,
+			Title:        t.Title,
+			Notes:        t.Notes,
+			Status:       StatusPool,
+			Tags:         slices.Clone(t.Tags),
+			ScheduledFor: &day,
+			Priority:     t.Priority,
+			Energy:       t.Energy,
+			Estimate:     t.Estimate,
+		}
+	}
+	t.UpdatedAt = created
+	return instances, nil
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseRecurrence verifies periods parse with an optional count and
+// start on now's day.
+func TestParseRecurrence(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 11, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    Recurrence
+		wantErr bool
+	}{
+		{input: "day", want: Recurrence{Every: 1, Unit: RecurDaily, Next: today}},
+		{input: "Week", want: Recurrence{Every: 1, Unit: RecurWeekly, Next: today}},
+		{input: "2w", want: Recurrence{Every: 2, Unit: RecurWeekly, Next: today}},
+		{input: "3month", want: Recurrence{Every: 3, Unit: RecurMonthly, Next: today}},
+		{input: "1y", want: Recurrence{Every: 1, Unit: RecurYearly, Next: today}},
+		{input: "0d", wantErr: true},
+		{input: "2", wantErr: true},
+		{input: "fortnight", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRecurrence(%q) = %+v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil || !recurrenceEqual(got, &tt.want) {
+				t.Errorf("ParseRecurrence(%q) = %+v, %v; want %+v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestTask_Recur verifies a template creates the occurrences due by today
+// as its missed policy allows and moves its next occurrence past today.
+func TestTask_Recur(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		recur    Recurrence
+		wantDays []time.Time
+		wantNext time.Time
+	}{
+		{
+			name:     "due today",
+			recur:    Recurrence{Every: 1, Unit: RecurDaily, Next: day(12)},
+			wantDays: []time.Time{day(12)},
+			wantNext: day(13),
+		},
+		{
+			name:     "not yet due",
+			recur:    Recurrence{Every: 1, Unit: RecurWeekly, Next: day(13)},
+			wantNext: day(13),
+		},
+		{
+			name:     "missed periods skipped",
+			recur:    Recurrence{Every: 2, Unit: RecurDaily, Next: day(5)},
+			wantDays: []time.Time{day(11)},
+			wantNext: day(13),
+		},
+		{
+			name:     "missed periods caught up",
+			recur:    Recurrence{Every: 2, Unit: RecurDaily, Next: day(5), Missed: MissedCatchUp},
+			wantDays: []time.Time{day(5), day(7), day(9), day(11)},
+			wantNext: day(13),
+		},
+		{
+			name:     "monthly",
+			recur:    Recurrence{Every: 1, Unit: RecurMonthly, Next: time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC), Missed: MissedCatchUp},
+			wantDays: []time.Time{time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC), day(12)},
+			wantNext: time.Date(2025, 12, 12, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, _ := NewTask("Water the plants", []string{"home"})
+			template.Priority = PriorityHigh
+			recur := tt.recur
+			template.Recurrence = &recur
+
+			got, err := template.Recur(now)
+			if err != nil {
+				t.Fatalf("Recur() error: %v", err)
+			}
+			if len(got) != len(tt.wantDays) {
+				t.Fatalf("Recur() created %d instances, want %d", len(got), len(tt.wantDays))
+			}
+			for i, inst := range got {
+				if !inst.ScheduledFor.Equal(tt.wantDays[i]) {
+					t.Errorf("instance %d scheduled for %v, want %v", i, inst.ScheduledFor, tt.wantDays[i])
+				}
+				if inst.ID == template.ID || inst.IsRecurring() || inst.Status != StatusPool ||
+					inst.Title != template.Title || inst.Priority != PriorityHigh || len(inst.Tags) != 1 {
+					t.Errorf("instance %d = %+v, want a fresh pool copy of the template", i, inst)
+				}
+				if err := inst.Validate(); err != nil {
+					t.Errorf("instance %d invalid: %v", i, err)
+				}
+			}
+			if !template.Recurrence.Next.Equal(tt.wantNext) {
+				t.Errorf("Next = %v, want %v", template.Recurrence.Next, tt.wantNext)
+			}
+			if (TaskFilter{}).Matches(template) || !(TaskFilter{IncludeRecurring: true}).Matches(template) {
+				t.Error("template should be hidden unless IncludeRecurring is set")
+			}
+		})
+	}
+
+	plain, _ := NewTask("Once", nil)
+	if _, err := plain.Recur(now); !errors.Is(err, ErrNotRecurring) {
+		t.Errorf("Recur() on a plain task error = %v, want ErrNotRecurring", err)
+	}
+	broken, _ := NewTask("Never", nil)
+	broken.Recurrence = &Recurrence{Unit: RecurDaily, Next: now}
+	var verr *ValidationError
+	if _, err := broken.Recur(now); !errors.As(err, &verr) {
+		t.Errorf("Recur() with every 0 error = %v, want a ValidationError", err)
+	}
+}
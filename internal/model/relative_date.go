@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relativeDateHelp lists the forms accepted by ParseRelativeDate.
+const relativeDateHelp = "expected YYYY-MM-DD, today, tomorrow, yesterday, eow, eom, eoy, a weekday, or an offset like +3d, -1w or +2m"
+
+// ParseRelativeDate resolves a date token to the start of that day in now's
+// location. It accepts:
+//
+//	2025-11-14                a calendar date
+//	today, tomorrow, yesterday
+//	eow, eom, eoy             the last day of this week (Sunday), month or year
+//	mon, monday, …            the next such day, counting today
+//	+3d, -1w, +2m, +1y, 5d    an offset in days, weeks, months or years
+//
+// Matching ignores case. Malformed tokens yield a *ValidationError for the
+// "date" field.
+func ParseRelativeDate(token string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(token))
+	today := StartOfDay(now)
+
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "eow":
+		return today.AddDate(0, 0, (7-int(today.Weekday()))%7), nil
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), nil
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), nil
+	}
+	if wd, ok := ParseWeekday(s); ok {
+		return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), nil
+	}
+	if day, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return day, nil
+	}
+	if day, ok := offsetDate(s, today); ok {
+		return day, nil
+	}
+	return time.Time{}, &ValidationError{Field: "date", Reason: fmt.Sprintf("%s, got %q", relativeDateHelp, token)}
+}
+
+// offsetDate applies an offset such as +3d or -2w to today.
+func offsetDate(s string, today time.Time) (time.Time, bool) {
+	if len(s) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch s[len(s)-1] {
+	case 'd':
+		return today.AddDate(0, 0, n), true
+	case 'w':
+		return today.AddDate(0, 0, 7*n), true
+	case 'm':
+		return today.AddDate(0, n, 0), true
+	case 'y':
+		return today.AddDate(n, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// weekdayNames maps full and three-letter weekday names to weekdays.
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseWeekday recognizes a full or three-letter weekday name in any case.
+func ParseWeekday(s string) (time.Weekday, bool) {
+	wd, ok := weekdayNames[strings.ToLower(s)]
+	return wd, ok
+}
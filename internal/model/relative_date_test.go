@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseRelativeDate verifies each token form against a fixed Wednesday.
+func TestParseRelativeDate(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2024, 6, 12, 15, 30, 0, 0, loc) // Wednesday
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, loc) }
+
+	tests := []struct {
+		token string
+		want  time.Time
+	}{
+		{"today", day(6, 12)},
+		{"Tomorrow", day(6, 13)},
+		{"yesterday", day(6, 11)},
+		{"eow", day(6, 16)},
+		{"eom", day(6, 30)},
+		{"eoy", day(12, 31)},
+		{"wed", day(6, 12)},
+		{"mon", day(6, 17)},
+		{"Friday", day(6, 14)},
+		{"+3d", day(6, 15)},
+		{"3d", day(6, 15)},
+		{"-1w", day(6, 5)},
+		{"+2m", day(8, 12)},
+		{"+1y", time.Date(2025, 6, 12, 0, 0, 0, 0, loc)},
+		{"2024-07-01", day(7, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			got, err := ParseRelativeDate(tt.token, now)
+			if err != nil {
+				t.Fatalf("ParseRelativeDate(%q) error: %v", tt.token, err)
+			}
+			if !got.Equal(tt.want) || got.Location() != loc {
+				t.Errorf("ParseRelativeDate(%q) = %v, want %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseRelativeDate_EndOfWeekOnSunday verifies eow is today on Sundays.
+func TestParseRelativeDate_EndOfWeekOnSunday(t *testing.T) {
+	sunday := time.Date(2024, 6, 16, 20, 0, 0, 0, time.UTC)
+	got, err := ParseRelativeDate("eow", sunday)
+	if err != nil || !got.Equal(StartOfDay(sunday)) {
+		t.Errorf("ParseRelativeDate(eow) = %v, %v, want %v", got, err, StartOfDay(sunday))
+	}
+}
+
+// TestParseRelativeDate_Invalid verifies malformed tokens are rejected.
+func TestParseRelativeDate_Invalid(t *testing.T) {
+	for _, token := range []string{"", "someday", "+d", "+3x", "2024-13-01", "next week"} {
+		_, err := ParseRelativeDate(token, time.Now())
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Field != "date" {
+			t.Errorf("ParseRelativeDate(%q) error = %v, want ValidationError for date", token, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// NudgeKind identifies why a review nudge was raised.
+type NudgeKind string
+
+const (
+	// NudgeReviewOverdue means no review has been done within the interval.
+	NudgeReviewOverdue NudgeKind = "review_overdue"
+	// NudgeEmptyToday means the day is underway and nothing is picked.
+	NudgeEmptyToday NudgeKind = "empty_today"
+)
+
+// Nudge is a gentle, locally computed prompt for the TUI header and widget
+// output.
+type Nudge struct {
+	Kind    NudgeKind
+	Message string
+}
+
+// NudgePolicy configures when nudges are raised. Zero values disable the
+// corresponding nudge.
+type NudgePolicy struct {
+	// ReviewInterval is how long may pass after the last review before
+	// suggesting another.
+	ReviewInterval time.Duration
+	// EmptyTodayAfter is the time of day, as an offset from local midnight,
+	// after which an empty today list is pointed out.
+	EmptyTodayAfter time.Duration
+}
+
+// DefaultNudgePolicy suggests a weekly review and flags an empty today list
+// from 9am.
+func DefaultNudgePolicy() NudgePolicy {
+	return NudgePolicy{
+		ReviewInterval:  7 * 24 * time.Hour,
+		EmptyTodayAfter: 9 * time.Hour,
+	}
+}
+
+// ReviewNudges inspects the journal state and returns any nudges due at now.
+// lastReview is the time of the most recent completed review; a zero value
+// means no review has ever been done, in which case the review nudge waits
+// until the oldest task is older than the interval.
+func ReviewNudges(policy NudgePolicy, lastReview time.Time, tasks []*Task, now time.Time) []Nudge {
+	var nudges []Nudge
+
+	if policy.ReviewInterval > 0 {
+		if lastReview.IsZero() {
+			if oldest, ok := oldestCreated(tasks); ok && now.Sub(oldest) >= policy.ReviewInterval {
+				nudges = append(nudges, Nudge{
+					Kind:    NudgeReviewOverdue,
+					Message: "You haven't done a review yet — try togo review",
+				})
+			}
+		} else if since := now.Sub(lastReview); since >= policy.ReviewInterval {
+			days := int(since / (24 * time.Hour))
+			nudges = append(nudges, Nudge{
+				Kind:    NudgeReviewOverdue,
+				Message: fmt.Sprintf("You haven't done a review in %d days", days),
+			})
+		}
+	}
+
+	if policy.EmptyTodayAfter > 0 && now.Sub(StartOfDay(now)) >= policy.EmptyTodayAfter {
+		if !hasStatus(tasks, StatusToday) && hasStatus(tasks, StatusPool) {
+			nudges = append(nudges, Nudge{
+				Kind:    NudgeEmptyToday,
+				Message: "Your today list is empty — pick something from the pool",
+			})
+		}
+	}
+
+	return nudges
+}
+
+// oldestCreated returns the earliest CreatedAt among tasks.
+func oldestCreated(tasks []*Task) (time.Time, bool) {
+	var oldest time.Time
+	for _, t := range tasks {
+		if oldest.IsZero() || t.CreatedAt.Before(oldest) {
+			oldest = t.CreatedAt
+		}
+	}
+	return oldest, !oldest.IsZero()
+}
+
+// hasStatus reports whether any task has the given status.
+func hasStatus(tasks []*Task, status TaskStatus) bool {
+	for _, t := range tasks {
+		if t.Status == status {
+			return true
+		}
+	}
+	return false
+}
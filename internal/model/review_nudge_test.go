@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReviewNudges verifies each nudge fires only under its conditions.
+func TestReviewNudges(t *testing.T) {
+	policy := DefaultNudgePolicy()
+	morning := time.Date(2025, 11, 12, 8, 30, 0, 0, time.UTC)
+	afterNine := time.Date(2025, 11, 12, 9, 15, 0, 0, time.UTC)
+	pool := &Task{Status: StatusPool, CreatedAt: afterNine.AddDate(0, 0, -30)}
+	today := &Task{Status: StatusToday, CreatedAt: afterNine.AddDate(0, 0, -1)}
+	fresh := &Task{Status: StatusPool, CreatedAt: afterNine.AddDate(0, 0, -2)}
+
+	tests := []struct {
+		name       string
+		policy     NudgePolicy
+		lastReview time.Time
+		tasks      []*Task
+		now        time.Time
+		want       []NudgeKind
+	}{
+		{
+			name:       "recent review and picked tasks",
+			policy:     policy,
+			lastReview: afterNine.AddDate(0, 0, -3),
+			tasks:      []*Task{pool, today},
+			now:        afterNine,
+			want:       nil,
+		},
+		{
+			name:       "review overdue",
+			policy:     policy,
+			lastReview: afterNine.AddDate(0, 0, -8),
+			tasks:      []*Task{pool, today},
+			now:        afterNine,
+			want:       []NudgeKind{NudgeReviewOverdue},
+		},
+		{
+			name:   "never reviewed with old tasks",
+			policy: policy,
+			tasks:  []*Task{pool, today},
+			now:    afterNine,
+			want:   []NudgeKind{NudgeReviewOverdue},
+		},
+		{
+			name:   "never reviewed with young journal",
+			policy: policy,
+			tasks:  []*Task{fresh},
+			now:    morning,
+			want:   nil,
+		},
+		{
+			name:       "empty today after nine",
+			policy:     policy,
+			lastReview: afterNine,
+			tasks:      []*Task{pool},
+			now:        afterNine,
+			want:       []NudgeKind{NudgeEmptyToday},
+		},
+		{
+			name:       "empty today before nine",
+			policy:     policy,
+			lastReview: morning,
+			tasks:      []*Task{pool},
+			now:        morning,
+			want:       nil,
+		},
+		{
+			name:       "empty pool is not nagged",
+			policy:     policy,
+			lastReview: afterNine,
+			tasks:      nil,
+			now:        afterNine,
+			want:       nil,
+		},
+		{
+			name:       "disabled policy",
+			policy:     NudgePolicy{},
+			lastReview: afterNine.AddDate(-1, 0, 0),
+			tasks:      []*Task{pool},
+			now:        afterNine,
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReviewNudges(tt.policy, tt.lastReview, tt.tasks, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReviewNudges() = %v, want kinds %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Kind != tt.want[i] {
+					t.Errorf("nudge[%d].Kind = %q, want %q", i, got[i].Kind, tt.want[i])
+				}
+				if got[i].Message == "" {
+					t.Errorf("nudge[%d] has empty message", i)
+				}
+			}
+		})
+	}
+}
+
+// TestReviewNudges_MessageCountsDays verifies the overdue message wording.
+func TestReviewNudges_MessageCountsDays(t *testing.T) {
+	now := time.Date(2025, 11, 12, 8, 0, 0, 0, time.UTC)
+
+	got := ReviewNudges(NudgePolicy{ReviewInterval: 24 * time.Hour}, now.AddDate(0, 0, -10), nil, now)
+	if len(got) != 1 || got[0].Message != "You haven't done a review in 10 days" {
+		t.Errorf("unexpected nudges %v", got)
+	}
+}
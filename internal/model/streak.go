@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// CompletionStreak returns the number of consecutive calendar days, in now's
+// location, on which at least one task was completed. A streak that has not
+// been extended yet today still counts if yesterday had a completion, so the
+// number does not drop to zero every morning.
+func CompletionStreak(tasks []*Task, now time.Time) int {
+	days := make(map[time.Time]bool)
+	for _, t := range tasks {
+		if t.Status == StatusDone && t.CompletedAt != nil {
+			days[dayIn(*t.CompletedAt, now.Location())] = true
+		}
+	}
+
+	day := StartOfDay(now)
+	if !days[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for days[day] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
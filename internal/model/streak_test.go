@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCompletionStreak verifies consecutive completion days are counted and
+// a gap ends the streak.
+func TestCompletionStreak(t *testing.T) {
+	now := time.Date(2025, 11, 12, 18, 0, 0, 0, time.UTC)
+	doneOn := func(daysAgo int) *Task {
+		at := now.AddDate(0, 0, -daysAgo).Add(-time.Hour)
+		return &Task{Status: StatusDone, CompletedAt: &at}
+	}
+
+	tests := []struct {
+		name  string
+		tasks []*Task
+		want  int
+	}{
+		{"no completions", nil, 0},
+		{"today only", []*Task{doneOn(0)}, 1},
+		{"three days running", []*Task{doneOn(0), doneOn(1), doneOn(2), doneOn(2)}, 3},
+		{"not yet extended today", []*Task{doneOn(1), doneOn(2)}, 2},
+		{"broken by gap", []*Task{doneOn(0), doneOn(2), doneOn(3)}, 1},
+		{"lapsed", []*Task{doneOn(2)}, 0},
+		{"open tasks ignored", []*Task{{Status: StatusToday}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompletionStreak(tt.tasks, now); got != tt.want {
+				t.Errorf("CompletionStreak() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package model
 
-import (
-	"strings"
-	"time"
-)
+import "time"
 
 // Task represents a unit of work in the bullet journal system.
 // It is the core aggregate root with identity (ID) and lifecycle management.
@@ -14,6 +11,11 @@ import (
 //   - Status must be a valid TaskStatus value
 //   - Title must not be empty (after trimming whitespace)
 //   - DeferredCount must be >= 0
+//   - Priority must be empty or a valid Priority value
+//   - Energy must be empty or a valid Energy value
+//   - CompletedAt must be set when Status is done, and nil otherwise
+//
+// Validate() checks all of these for tasks not created through NewTask.
 //
 // The Task entity follows these design principles:
 //   - Immutable Identity: ID and CreatedAt never change after construction
@@ -22,22 +24,58 @@ import (
 //   - Value Object Composition: Uses TaskID and TaskStatus value objects
 type Task struct {
 	ID            TaskID     `json:"id"`
+	Seq           int        `json:"seq,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at,omitzero"`
 	Title         string     `json:"title"`
 	Notes         string     `json:"notes,omitempty"`
+	NotesFile     string     `json:"notes_file,omitempty"`
 	Status        TaskStatus `json:"status"`
 	Tags          []string   `json:"tags,omitempty"`
 	DueDate       *time.Time `json:"due_date,omitempty"`
+	ScheduledFor  *time.Time `json:"scheduled_for,omitempty"`
 	CompletedAt   *time.Time `json:"completed_at,omitempty"`
 	DeferredCount int        `json:"deferred_count"`
+
+	// SnoozedUntil hides the task from default views until that moment.
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+
+	// RemindAt is when to remind the user of the task, if ever.
+	RemindAt *time.Time `json:"remind_at,omitempty"`
+
+	// Pinned keeps the task at the top of every view it appears in.
+	Pinned bool `json:"pinned,omitempty"`
+
+	// Priority ranks the task's importance; empty means unprioritized.
+	Priority Priority `json:"priority,omitempty"`
+
+	// Energy is how much focus the task demands; empty means unspecified.
+	Energy Energy `json:"energy,omitempty"`
+
+	// Estimate is the expected effort for the task; zero means unestimated.
+	Estimate time.Duration `json:"estimate,omitempty"`
+
+	// ExternalRef identifies the record this task was imported from, if any.
+	ExternalRef *ExternalRef `json:"external_ref,omitempty"`
+
+	// Recurrence, if set, makes the task the template of a recurring
+	// series rather than work to do itself.
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+
+	// Version counts the task's saves. A repository refuses to save a copy
+	// whose Version is not the stored task's, with ErrStaleTask, so two
+	// frontends editing the task cannot overwrite each other's changes.
+	Version int `json:"version,omitempty"`
 }
 
 // NewTask creates a new Task with the given title and tags.
-// The task is initialized with a newly generated UUID as ID, current timestamp
-// as CreatedAt, StatusPool as initial status, and zero values for optional fields.
+// The task is initialized with a newly generated UUID as ID, the package
+// clock's current time as CreatedAt, StatusPool as initial status, and zero values for optional fields.
 //
-// The title is trimmed of leading/trailing whitespace before validation.
-// If the trimmed title is empty, returns ErrEmptyTitle.
+// The title is cleaned with NormalizeTitle before validation. If the result
+// is empty, returns ErrEmptyTitle; if it is longer than the configured
+// maximum (see SetLimits), returns a *ValidationError; so do tags exceeding
+// the configured count or length.
 //
 // The tags slice is defensively copied to prevent external mutation.
 // If tags is nil or empty, the Task.Tags field will be nil (for JSON omitempty).
@@ -52,11 +90,19 @@ type Task struct {
 // Returns:
 //   - A pointer to the newly created Task
 //   - ErrEmptyTitle if the title is empty or whitespace-only
+//   - a *ValidationError for the "title" or "tags" field if either exceeds
+//     the configured limits
 func NewTask(title string, tags []string) (*Task, error) {
-	trimmedTitle := strings.TrimSpace(title)
+	trimmedTitle := NormalizeTitle(title)
 	if trimmedTitle == "" {
 		return nil, ErrEmptyTitle
 	}
+	if reason, tooLong := titleTooLong(trimmedTitle); tooLong {
+		return nil, &ValidationError{Field: "title", Reason: reason}
+	}
+	if reason, violated := tooManyTags(tags); violated {
+		return nil, &ValidationError{Field: "tags", Reason: reason}
+	}
 
 	id := NewTaskID()
 
@@ -66,9 +112,11 @@ func NewTask(title string, tags []string) (*Task, error) {
 		copy(taskTags, tags)
 	}
 
+	createdAt := Now()
 	task := &Task{
 		ID:            id,
-		CreatedAt:     time.Now(),
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
 		Title:         trimmedTitle,
 		Notes:         "",
 		Status:        StatusPool,
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// DefaultStaleAfter is how long a pool task may go untouched before it is
+// considered stale.
+const DefaultStaleAfter = 14 * 24 * time.Hour
+
+var staleAfter = DefaultStaleAfter
+
+// SetStaleThreshold configures how long a pool task may go untouched before
+// IsStale reports it, returning a function that restores the previous value.
+// A non-positive d disables staleness. Like SetClock, it is not safe for
+// concurrent use.
+func SetStaleThreshold(d time.Duration) (restore func()) {
+	prev := staleAfter
+	staleAfter = d
+	return func() { staleAfter = prev }
+}
+
+// Age returns how long ago the task was created.
+func (t *Task) Age(now time.Time) time.Duration {
+	return now.Sub(t.CreatedAt)
+}
+
+// LastTouched returns when the task last changed, falling back to its
+// creation time for tasks that predate UpdatedAt.
+func (t *Task) LastTouched() time.Time {
+	if t.UpdatedAt.After(t.CreatedAt) {
+		return t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
+// IsStale reports whether the task has sat in the pool untouched for longer
+// than the configured threshold, as of the package clock's current time.
+// Tasks on today's list and completed tasks are never stale.
+func (t *Task) IsStale() bool {
+	if t.Status != StatusPool || staleAfter <= 0 {
+		return false
+	}
+	return Now().Sub(t.LastTouched()) > staleAfter
+}
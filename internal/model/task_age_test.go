@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTask_Age verifies Age measures from creation.
+func TestTask_Age(t *testing.T) {
+	created := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+	task := &Task{CreatedAt: created}
+
+	if got := task.Age(created.Add(36 * time.Hour)); got != 36*time.Hour {
+		t.Errorf("Age() = %v, want 36h", got)
+	}
+}
+
+// TestTask_IsStale verifies staleness uses the last change, applies only to
+// the pool, and follows the configured threshold.
+func TestTask_IsStale(t *testing.T) {
+	now := time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC)
+	defer SetClock(NewFixedClock(now))()
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	tests := []struct {
+		name string
+		task Task
+		want bool
+	}{
+		{"fresh", Task{Status: StatusPool, CreatedAt: days(3)}, false},
+		{"old and untouched", Task{Status: StatusPool, CreatedAt: days(20)}, true},
+		{"old but recently edited", Task{Status: StatusPool, CreatedAt: days(20), UpdatedAt: days(2)}, false},
+		{"on today's list", Task{Status: StatusToday, CreatedAt: days(20)}, false},
+		{"done", Task{Status: StatusDone, CreatedAt: days(20)}, false},
+		{"exactly at threshold", Task{Status: StatusPool, CreatedAt: days(14)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.IsStale(); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	old := &Task{Status: StatusPool, CreatedAt: days(20)}
+	restore := SetStaleThreshold(30 * 24 * time.Hour)
+	if old.IsStale() {
+		t.Error("task should not be stale under a 30-day threshold")
+	}
+	restore()
+
+	defer SetStaleThreshold(0)()
+	if old.IsStale() {
+		t.Error("staleness should be disabled by a zero threshold")
+	}
+}
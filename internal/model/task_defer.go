@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultDeferWarnThreshold is the number of deferrals tolerated before
+// Defer starts returning a DeferWarning.
+const DefaultDeferWarnThreshold = 3
+
+var deferWarnThreshold = DefaultDeferWarnThreshold
+
+// SetDeferWarnThreshold configures how many deferrals are tolerated before
+// Defer warns, returning a function that restores the previous value.
+// A non-positive n disables warnings. Like SetClock, it is not safe for
+// concurrent use.
+func SetDeferWarnThreshold(n int) (restore func()) {
+	prev := deferWarnThreshold
+	deferWarnThreshold = n
+	return func() { deferWarnThreshold = prev }
+}
+
+// DeferWarning signals that a task has been deferred more often than the
+// configured threshold. It is advisory: the deferral still happened, and the
+// UI can use it to nag about perpetually postponed work.
+type DeferWarning struct {
+	TaskID    TaskID
+	Count     int
+	Threshold int
+}
+
+func (w *DeferWarning) Error() string {
+	return fmt.Sprintf("task %s has been deferred %d times (threshold %d)", w.TaskID.Short(), w.Count, w.Threshold)
+}
+
+// Defer postpones the task by moving it back to the pool and incrementing
+// DeferredCount. If until is non-nil it becomes the task's ScheduledFor date;
+// a nil until leaves any existing schedule untouched.
+//
+// Returns:
+//   - a *TaskError wrapping ErrInvalidStateTransition if the task is
+//     already done
+//   - a *DeferWarning (with a nil error) once DeferredCount exceeds the
+//     configured threshold
+func (t *Task) Defer(until *time.Time) (*DeferWarning, error) {
+	if t.Status == StatusDone {
+		return nil, &TaskError{ID: t.ID, Op: "defer", Err: ErrInvalidStateTransition}
+	}
+
+	t.Status = StatusPool
+	t.DeferredCount++
+	t.UpdatedAt = Now()
+	if until != nil {
+		scheduled := *until
+		t.ScheduledFor = &scheduled
+	}
+
+	if deferWarnThreshold > 0 && t.DeferredCount > deferWarnThreshold {
+		return &DeferWarning{TaskID: t.ID, Count: t.DeferredCount, Threshold: deferWarnThreshold}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTask_Defer_MovesToPoolAndCounts verifies the state change and counter.
+func TestTask_Defer_MovesToPoolAndCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial TaskStatus
+	}{
+		{name: "defer from today", initial: StatusToday},
+		{name: "defer from pool", initial: StatusPool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{ID: NewTaskID(), Status: tt.initial}
+
+			warning, err := task.Defer(nil)
+			if err != nil {
+				t.Fatalf("Defer() error = %v", err)
+			}
+			if warning != nil {
+				t.Errorf("expected no warning on first deferral, got %v", warning)
+			}
+			if task.Status != StatusPool {
+				t.Errorf("Status = %q, want %q", task.Status, StatusPool)
+			}
+			if task.DeferredCount != 1 {
+				t.Errorf("DeferredCount = %d, want 1", task.DeferredCount)
+			}
+		})
+	}
+}
+
+// TestTask_Defer_DoneTask_ReturnsError verifies completed tasks cannot be deferred.
+func TestTask_Defer_DoneTask_ReturnsError(t *testing.T) {
+	task := &Task{Status: StatusDone}
+
+	_, err := task.Defer(nil)
+	if !errors.Is(err, ErrInvalidStateTransition) {
+		t.Fatalf("expected ErrInvalidStateTransition, got %v", err)
+	}
+	if task.DeferredCount != 0 {
+		t.Errorf("DeferredCount = %d, want unchanged 0", task.DeferredCount)
+	}
+}
+
+// TestTask_Defer_SetsSchedule verifies that until is copied into ScheduledFor
+// and that a nil until keeps the existing schedule.
+func TestTask_Defer_SetsSchedule(t *testing.T) {
+	until := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	task := &Task{Status: StatusToday}
+
+	if _, err := task.Defer(&until); err != nil {
+		t.Fatal(err)
+	}
+	if task.ScheduledFor == nil || !task.ScheduledFor.Equal(until) {
+		t.Fatalf("ScheduledFor = %v, want %v", task.ScheduledFor, until)
+	}
+
+	until = until.AddDate(0, 0, 1)
+	if task.ScheduledFor.Equal(until) {
+		t.Error("ScheduledFor aliases the caller's time value")
+	}
+
+	if _, err := task.Defer(nil); err != nil {
+		t.Fatal(err)
+	}
+	if task.ScheduledFor == nil {
+		t.Error("expected nil until to keep the existing schedule")
+	}
+}
+
+// TestTask_Defer_WarnsPastThreshold verifies the warning is returned once the
+// count exceeds the configured threshold, and never when disabled.
+func TestTask_Defer_WarnsPastThreshold(t *testing.T) {
+	restore := SetDeferWarnThreshold(2)
+	defer restore()
+
+	task := &Task{ID: NewTaskID(), Status: StatusToday}
+	for i := 1; i <= 2; i++ {
+		warning, err := task.Defer(nil)
+		if err != nil || warning != nil {
+			t.Fatalf("deferral %d: warning = %v, err = %v; want neither", i, warning, err)
+		}
+	}
+
+	warning, err := task.Defer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warning == nil {
+		t.Fatal("expected warning on third deferral")
+	}
+	if warning.Count != 3 || warning.Threshold != 2 || !warning.TaskID.Equals(task.ID) {
+		t.Errorf("unexpected warning contents: %+v", warning)
+	}
+
+	SetDeferWarnThreshold(0)
+	if warning, _ := task.Defer(nil); warning != nil {
+		t.Errorf("expected no warning when threshold disabled, got %v", warning)
+	}
+}
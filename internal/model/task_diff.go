@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldChange describes one field whose value differs between two versions of
+// a task. Field uses the task's JSON field name; Old and New hold the values
+// from the receiver and the other task respectively.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// String renders the change for "what changed" summaries, e.g.
+// `status: pool → today`.
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Field, formatChangeValue(c.Old), formatChangeValue(c.New))
+}
+
+// Diff compares t with other and returns the mutable fields that differ, in
+// declaration order. Identity fields (ID, CreatedAt) are not compared since
+// they never change for a given task.
+//
+// The returned values are copies, so later mutation of either task does not
+// alter the diff. Time pointers are reported as *time.Time (nil when unset).
+func (t *Task) Diff(other *Task) []FieldChange {
+	var changes []FieldChange
+	for _, f := range taskFields {
+		if !f.equal(t, other) {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.value(t), New: f.value(other)})
+		}
+	}
+	return changes
+}
+
+// formatChangeValue renders a FieldChange value compactly.
+func formatChangeValue(v any) string {
+	switch v := v.(type) {
+	case *time.Time:
+		if v == nil {
+			return "(none)"
+		}
+		return v.Format(time.RFC3339)
+	case *ExternalRef:
+		if v == nil {
+			return "(none)"
+		}
+		return v.String()
+	case []string:
+		if len(v) == 0 {
+			return "(none)"
+		}
+		return strings.Join(v, ", ")
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTask_Diff_IdenticalTasks_ReturnsNil verifies no changes are reported
+// for equal tasks, even when time pointers differ but instants match.
+func TestTask_Diff_IdenticalTasks_ReturnsNil(t *testing.T) {
+	due := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	sameInstant := due.In(time.FixedZone("UTC+1", 3600))
+	a := &Task{Title: "Same", Tags: []string{"x"}, DueDate: &due}
+	b := &Task{Title: "Same", Tags: []string{"x"}, DueDate: &sameInstant}
+
+	if changes := a.Diff(b); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+// TestTask_Diff_ReportsChangedFields verifies each mutable field is compared.
+func TestTask_Diff_ReportsChangedFields(t *testing.T) {
+	due := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	done := time.Date(2025, 11, 13, 8, 0, 0, 0, time.UTC)
+
+	before := &Task{
+		Title:  "Draft report",
+		Status: StatusToday,
+		Tags:   []string{"work"},
+	}
+	after := &Task{
+		Title:         "Final report",
+		Notes:         "sent to boss",
+		Status:        StatusDone,
+		Tags:          []string{"work", "q4"},
+		DueDate:       &due,
+		CompletedAt:   &done,
+		DeferredCount: 1,
+		Estimate:      time.Hour,
+	}
+
+	changes := before.Diff(after)
+
+	want := []string{"title", "notes", "status", "tags", "due_date", "completed_at", "deferred_count", "estimate"}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %v", len(want), len(changes), changes)
+	}
+	for i, field := range want {
+		if changes[i].Field != field {
+			t.Errorf("changes[%d].Field = %q, want %q", i, changes[i].Field, field)
+		}
+	}
+	if changes[2].Old != StatusToday || changes[2].New != StatusDone {
+		t.Errorf("status change = %v → %v, want today → done", changes[2].Old, changes[2].New)
+	}
+}
+
+// TestTask_Diff_ValuesAreCopies verifies later mutation does not alter a diff.
+func TestTask_Diff_ValuesAreCopies(t *testing.T) {
+	a := &Task{Tags: []string{"a"}}
+	b := &Task{Tags: []string{"b"}}
+
+	changes := a.Diff(b)
+	b.Tags[0] = "mutated"
+
+	if got := changes[0].New.([]string)[0]; got != "b" {
+		t.Errorf("diff aliases task tags: got %q", got)
+	}
+}
+
+// TestFieldChange_String verifies the human-readable rendering.
+func TestFieldChange_String(t *testing.T) {
+	due := time.Date(2025, 11, 14, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		change FieldChange
+		want   string
+	}{
+		{name: "status", change: FieldChange{"status", StatusPool, StatusToday}, want: "status: pool → today"},
+		{name: "title", change: FieldChange{"title", "a", "b"}, want: `title: "a" → "b"`},
+		{name: "empty notes", change: FieldChange{"notes", "", "hi"}, want: `notes: "" → "hi"`},
+		{name: "due date set", change: FieldChange{"due_date", (*time.Time)(nil), &due}, want: "due_date: (none) → 2025-11-14T09:00:00Z"},
+		{name: "tags", change: FieldChange{"tags", []string(nil), []string{"a", "b"}}, want: "tags: (none) → a, b"},
+		{name: "count", change: FieldChange{"deferred_count", 1, 2}, want: "deferred_count: 1 → 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
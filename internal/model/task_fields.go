@@ -0,0 +1,199 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// taskField describes one mutable Task field for the generic comparison
+// machinery shared by Diff and MergeTasks. Identity fields (ID, CreatedAt)
+// and bookkeeping (UpdatedAt) are deliberately absent.
+//
+// When adding a field to Task, add it here so diffs and merges see it.
+type taskField struct {
+	// name is the JSON field name, used in FieldChange and Conflict.
+	name string
+	// equal reports whether the field holds the same value on a and b.
+	equal func(a, b *Task) bool
+	// value returns a copy of the field that does not alias the task.
+	value func(t *Task) any
+	// assign copies the field from src to dst.
+	assign func(dst, src *Task)
+	// merge optionally performs a conflict-free three-way merge into dst.
+	// Fields without it fall back to per-field last-writer-wins.
+	merge func(dst, base, local, remote *Task)
+}
+
+var taskFields = []taskField{
+	{
+		name:   "seq",
+		equal:  func(a, b *Task) bool { return a.Seq == b.Seq },
+		value:  func(t *Task) any { return t.Seq },
+		assign: func(dst, src *Task) { dst.Seq = src.Seq },
+	},
+	{
+		name:   "title",
+		equal:  func(a, b *Task) bool { return a.Title == b.Title },
+		value:  func(t *Task) any { return t.Title },
+		assign: func(dst, src *Task) { dst.Title = src.Title },
+	},
+	{
+		name:   "notes",
+		equal:  func(a, b *Task) bool { return a.Notes == b.Notes },
+		value:  func(t *Task) any { return t.Notes },
+		assign: func(dst, src *Task) { dst.Notes = src.Notes },
+	},
+	{
+		name:   "notes_file",
+		equal:  func(a, b *Task) bool { return a.NotesFile == b.NotesFile },
+		value:  func(t *Task) any { return t.NotesFile },
+		assign: func(dst, src *Task) { dst.NotesFile = src.NotesFile },
+	},
+	{
+		name:   "status",
+		equal:  func(a, b *Task) bool { return a.Status == b.Status },
+		value:  func(t *Task) any { return t.Status },
+		assign: func(dst, src *Task) { dst.Status = src.Status },
+	},
+	{
+		name:   "tags",
+		equal:  func(a, b *Task) bool { return slices.Equal(a.Tags, b.Tags) },
+		value:  func(t *Task) any { return slices.Clone(t.Tags) },
+		assign: func(dst, src *Task) { dst.Tags = slices.Clone(src.Tags) },
+		merge: func(dst, base, local, remote *Task) {
+			dst.Tags = mergeTagSets(base.Tags, local.Tags, remote.Tags)
+		},
+	},
+	{
+		name:   "due_date",
+		equal:  func(a, b *Task) bool { return timePtrEqual(a.DueDate, b.DueDate) },
+		value:  func(t *Task) any { return cloneTime(t.DueDate) },
+		assign: func(dst, src *Task) { dst.DueDate = cloneTime(src.DueDate) },
+	},
+	{
+		name:   "scheduled_for",
+		equal:  func(a, b *Task) bool { return timePtrEqual(a.ScheduledFor, b.ScheduledFor) },
+		value:  func(t *Task) any { return cloneTime(t.ScheduledFor) },
+		assign: func(dst, src *Task) { dst.ScheduledFor = cloneTime(src.ScheduledFor) },
+	},
+	{
+		name:   "completed_at",
+		equal:  func(a, b *Task) bool { return timePtrEqual(a.CompletedAt, b.CompletedAt) },
+		value:  func(t *Task) any { return cloneTime(t.CompletedAt) },
+		assign: func(dst, src *Task) { dst.CompletedAt = cloneTime(src.CompletedAt) },
+	},
+	{
+		name:   "deferred_count",
+		equal:  func(a, b *Task) bool { return a.DeferredCount == b.DeferredCount },
+		value:  func(t *Task) any { return t.DeferredCount },
+		assign: func(dst, src *Task) { dst.DeferredCount = src.DeferredCount },
+	},
+	{
+		name:   "snoozed_until",
+		equal:  func(a, b *Task) bool { return timePtrEqual(a.SnoozedUntil, b.SnoozedUntil) },
+		value:  func(t *Task) any { return cloneTime(t.SnoozedUntil) },
+		assign: func(dst, src *Task) { dst.SnoozedUntil = cloneTime(src.SnoozedUntil) },
+	},
+	{
+		name:   "remind_at",
+		equal:  func(a, b *Task) bool { return timePtrEqual(a.RemindAt, b.RemindAt) },
+		value:  func(t *Task) any { return cloneTime(t.RemindAt) },
+		assign: func(dst, src *Task) { dst.RemindAt = cloneTime(src.RemindAt) },
+	},
+	{
+		name:   "pinned",
+		equal:  func(a, b *Task) bool { return a.Pinned == b.Pinned },
+		value:  func(t *Task) any { return t.Pinned },
+		assign: func(dst, src *Task) { dst.Pinned = src.Pinned },
+	},
+	{
+		name:   "priority",
+		equal:  func(a, b *Task) bool { return a.Priority == b.Priority },
+		value:  func(t *Task) any { return t.Priority },
+		assign: func(dst, src *Task) { dst.Priority = src.Priority },
+	},
+	{
+		name:   "energy",
+		equal:  func(a, b *Task) bool { return a.Energy == b.Energy },
+		value:  func(t *Task) any { return t.Energy },
+		assign: func(dst, src *Task) { dst.Energy = src.Energy },
+	},
+	{
+		name:   "estimate",
+		equal:  func(a, b *Task) bool { return a.Estimate == b.Estimate },
+		value:  func(t *Task) any { return t.Estimate },
+		assign: func(dst, src *Task) { dst.Estimate = src.Estimate },
+	},
+	{
+		name:   "external_ref",
+		equal:  func(a, b *Task) bool { return externalRefEqual(a.ExternalRef, b.ExternalRef) },
+		value:  func(t *Task) any { return cloneExternalRef(t.ExternalRef) },
+		assign: func(dst, src *Task) { dst.ExternalRef = cloneExternalRef(src.ExternalRef) },
+	},
+	{
+		name:   "recurrence",
+		equal:  func(a, b *Task) bool { return recurrenceEqual(a.Recurrence, b.Recurrence) },
+		value:  func(t *Task) any { return cloneRecurrence(t.Recurrence) },
+		assign: func(dst, src *Task) { dst.Recurrence = cloneRecurrence(src.Recurrence) },
+	},
+}
+
+// Clone returns a deep copy of the task that shares no mutable state with it.
+func (t *Task) Clone() *Task {
+	c := *t
+	for _, f := range taskFields {
+		f.assign(&c, t)
+	}
+	return &c
+}
+
+// timePtrEqual compares optional times by instant, treating two nils as equal.
+func timePtrEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// cloneTime copies an optional time so the copy does not alias the original.
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// externalRefEqual compares optional external references by value.
+func externalRefEqual(a, b *ExternalRef) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// cloneExternalRef copies an optional external reference.
+func cloneExternalRef(r *ExternalRef) *ExternalRef {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	return &c
+}
+
+// recurrenceEqual compares optional recurrences by value, Next by instant.
+func recurrenceEqual(a, b *Recurrence) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Every == b.Every && a.Unit == b.Unit && a.Next.Equal(b.Next) && a.Missed == b.Missed
+}
+
+// cloneRecurrence copies an optional recurrence.
+func cloneRecurrence(r *Recurrence) *Recurrence {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	return &c
+}
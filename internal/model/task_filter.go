@@ -1,43 +1,197 @@
 package model
 
-import "time"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"time"
+)
 
 // TaskFilter encapsulates criteria for filtering tasks in queries.
-// It supports filtering by status, tags (AND semantics), and due date ranges.
+// It supports filtering by status, tags (AND semantics via Tags, OR semantics
+// via TagsAny, exclusion via ExcludeTags), free text, and due date ranges.
 // A nil or zero value for a field means no filtering on that criterion.
+//
+// When DueByDay is set, DueAfter and DueBefore are compared at calendar-day
+// granularity in the bound's location, so "due before Friday" includes tasks
+// due at any time on Friday.
+//
+// When TagMatchesPrefix is set, tags are treated as hierarchical paths
+// separated by TagSeparator, and a filter tag also matches its descendants:
+// "work" matches "work/projectA/backend".
+//
+// When Fuzzy is set, Text is matched against titles fzf-style rather than
+// as a substring, and Apply orders results best match first.
+//
+// TaskFilter round-trips through JSON (see MarshalJSON) so filters can be
+// stored verbatim.
+//
+// Snoozed tasks are excluded unless IncludeSnoozed is set, and recurring
+// templates unless IncludeRecurring is set. Snoozes and
+// OverdueOnly are evaluated against the package clock (see Now).
 type TaskFilter struct {
-	Status    *TaskStatus
-	Tags      []string
-	DueAfter  *time.Time
-	DueBefore *time.Time
-	Limit     int
+	IDPrefix         string         `json:"id_prefix,omitempty"`
+	Status           *TaskStatus    `json:"status,omitempty"`
+	Energy           *Energy        `json:"energy,omitempty"`
+	Tags             []string       `json:"tags,omitempty"`
+	TagsAny          []string       `json:"tags_any,omitempty"`
+	ExcludeTags      []string       `json:"exclude_tags,omitempty"`
+	NoTags           bool           `json:"no_tags,omitempty"`
+	TagMatchesPrefix bool           `json:"tag_matches_prefix,omitempty"`
+	Text             string         `json:"text,omitempty"`
+	Fuzzy            bool           `json:"fuzzy,omitempty"`
+	TitleRegex       *regexp.Regexp `json:"-"`
+	NotesRegex       *regexp.Regexp `json:"-"`
+	DueAfter         *time.Time     `json:"due_after,omitempty"`
+	DueBefore        *time.Time     `json:"due_before,omitempty"`
+	DueByDay         bool           `json:"due_by_day,omitempty"`
+	OverdueOnly      bool           `json:"overdue_only,omitempty"`
+	HasDueDate       Presence       `json:"has_due_date,omitempty"`
+	HasNotes         Presence       `json:"has_notes,omitempty"`
+	DeferredAtLeast  int            `json:"deferred_at_least,omitempty"`
+	CreatedAfter     *time.Time     `json:"created_after,omitempty"`
+	CreatedBefore    *time.Time     `json:"created_before,omitempty"`
+	CompletedAfter   *time.Time     `json:"completed_after,omitempty"`
+	CompletedBefore  *time.Time     `json:"completed_before,omitempty"`
+	IncludeSnoozed   bool           `json:"include_snoozed,omitempty"`
+	IncludeRecurring bool           `json:"include_recurring,omitempty"`
+	Limit            int            `json:"limit,omitempty"`
+	Offset           int            `json:"offset,omitempty"`
+}
+
+// Presence is a tri-state criterion on whether a task field is set.
+type Presence int
+
+const (
+	// PresenceAny ignores the field; it is the zero value.
+	PresenceAny Presence = iota
+	// PresenceRequired matches tasks that have the field set.
+	PresenceRequired
+	// PresenceAbsent matches tasks that do not have the field set.
+	PresenceAbsent
+)
+
+// matches reports whether a field that is (or is not) set satisfies p.
+func (p Presence) matches(set bool) bool {
+	switch p {
+	case PresenceRequired:
+		return set
+	case PresenceAbsent:
+		return !set
+	default:
+		return true
+	}
+}
+
+// TagSeparator separates the levels of a hierarchical tag.
+const TagSeparator = "/"
+
+// TagWithin reports whether tag equals parent or is nested below it. Only
+// whole path segments match, so "work" does not contain "workshop".
+func TagWithin(tag, parent string) bool {
+	return tag == parent || strings.HasPrefix(tag, parent+TagSeparator)
 }
 
 // Matches returns true if the task satisfies all filter criteria.
 // Uses fail-fast pattern: returns false as soon as any criterion fails.
 //
 // Filtering semantics:
+//   - IDPrefix: empty matches any task; otherwise the task's UUID must start with it, ignoring case
 //   - Status: nil matches any status; non-nil requires exact match
+//   - Energy: nil matches any energy; non-nil requires exact match (unset energy only matches EnergyNone)
 //   - Tags: nil or empty matches any tags; non-empty requires task to have ALL filter tags (AND semantics)
+//   - TagsAny: nil or empty matches any tags; non-empty requires task to have AT LEAST ONE of them (OR semantics)
+//   - ExcludeTags: rejects any task carrying one of these tags
+//   - NoTags: requires the task to have no tags at all
+//   - TagMatchesPrefix: a filter tag in Tags, TagsAny or ExcludeTags is also satisfied by any descendant tag (see TagWithin)
+//   - Text: empty matches any task; otherwise Title or Notes must contain it, ignoring case
+//     (notes split out to a NotesFile are not searched)
+//   - Fuzzy: Text instead matches the Title as a subsequence (see FuzzyScore); Apply ranks by score
+//   - TitleRegex, NotesRegex: nil matches any task; otherwise the Title or Notes must match
 //   - DueAfter: nil matches any date; non-nil requires task.DueDate >= DueAfter (inclusive, rejects nil DueDate)
 //   - DueBefore: nil matches any date; non-nil requires task.DueDate <= DueBefore (inclusive, rejects nil DueDate)
-//   - Limit: completely ignored by Matches (caller's responsibility to apply limit)
+//   - DueByDay: truncates both sides of the DueAfter/DueBefore comparisons to calendar days
+//   - OverdueOnly: requires Task.IsOverdue, i.e. due before today and not done
+//   - HasDueDate, HasNotes: PresenceAny matches any task; otherwise the due date or notes
+//     (inline or in a NotesFile) must be present or absent
+//   - DeferredAtLeast: zero matches any task; otherwise DeferredCount must be at least this
+//   - CreatedAfter, CreatedBefore: nil matches any task; non-nil bound CreatedAt inclusively
+//   - CompletedAfter, CompletedBefore: nil matches any task; non-nil bound CompletedAt inclusively
+//     and reject tasks that are not completed
+//   - IncludeSnoozed: false rejects tasks whose SnoozedUntil is still in the future
+//   - IncludeRecurring: false rejects recurring templates (see Task.IsRecurring)
+//   - Limit, Offset: completely ignored by Matches (applied by Apply, or the caller)
 func (f TaskFilter) Matches(t *Task) bool {
+	if f.IDPrefix != "" && !t.ID.HasPrefix(f.IDPrefix) {
+		return false
+	}
+
 	if f.Status != nil && t.Status != *f.Status {
 		return false
 	}
 
+	if !f.IncludeSnoozed && t.IsSnoozed(Now()) {
+		return false
+	}
+
+	if !f.IncludeRecurring && t.IsRecurring() {
+		return false
+	}
+
+	if f.Energy != nil && t.Energy != *f.Energy {
+		return false
+	}
+
 	if len(f.Tags) > 0 {
-		if !containsAllTags(t.Tags, f.Tags) {
+		if f.TagMatchesPrefix {
+			if !containsAllTagPrefixes(t.Tags, f.Tags) {
+				return false
+			}
+		} else if !containsAllTags(t.Tags, f.Tags) {
 			return false
 		}
 	}
 
+	if len(f.TagsAny) > 0 && !containsAnyTag(t.Tags, f.TagsAny, f.TagMatchesPrefix) {
+		return false
+	}
+
+	if f.NoTags && len(t.Tags) > 0 {
+		return false
+	}
+
+	if len(f.ExcludeTags) > 0 && containsAnyTag(t.Tags, f.ExcludeTags, f.TagMatchesPrefix) {
+		return false
+	}
+
+	if f.Text != "" {
+		if f.Fuzzy {
+			if _, ok := FuzzyScore(f.Text, t.Title); !ok {
+				return false
+			}
+		} else if !containsText(t, f.Text) {
+			return false
+		}
+	}
+
+	if f.TitleRegex != nil && !f.TitleRegex.MatchString(t.Title) {
+		return false
+	}
+
+	if f.NotesRegex != nil && !f.NotesRegex.MatchString(t.Notes) {
+		return false
+	}
+
 	if f.DueAfter != nil {
 		if t.DueDate == nil {
 			return false
 		}
-		if t.DueDate.Before(*f.DueAfter) {
+		due, bound := f.dueBounds(*t.DueDate, *f.DueAfter)
+		if due.Before(bound) {
 			return false
 		}
 	}
@@ -46,7 +200,34 @@ func (f TaskFilter) Matches(t *Task) bool {
 		if t.DueDate == nil {
 			return false
 		}
-		if t.DueDate.After(*f.DueBefore) {
+		due, bound := f.dueBounds(*t.DueDate, *f.DueBefore)
+		if due.After(bound) {
+			return false
+		}
+	}
+
+	if f.OverdueOnly && !t.IsOverdue(Now()) {
+		return false
+	}
+
+	if !f.HasDueDate.matches(t.DueDate != nil) {
+		return false
+	}
+
+	if !f.HasNotes.matches(t.Notes != "" || t.NotesFile != "") {
+		return false
+	}
+
+	if t.DeferredCount < f.DeferredAtLeast {
+		return false
+	}
+
+	if !inRange(t.CreatedAt, f.CreatedAfter, f.CreatedBefore) {
+		return false
+	}
+
+	if f.CompletedAfter != nil || f.CompletedBefore != nil {
+		if t.CompletedAt == nil || !inRange(*t.CompletedAt, f.CompletedAfter, f.CompletedBefore) {
 			return false
 		}
 	}
@@ -54,6 +235,87 @@ func (f TaskFilter) Matches(t *Task) bool {
 	return true
 }
 
+// NewOverdueFilter returns a filter for tasks that are past due and not done,
+// the definition shared by the TUI's overdue section and the CLI.
+func NewOverdueFilter() TaskFilter {
+	return TaskFilter{OverdueOnly: true}
+}
+
+// inRange reports whether at lies within the inclusive bounds; a nil bound
+// is open.
+func inRange(at time.Time, after, before *time.Time) bool {
+	if after != nil && at.Before(*after) {
+		return false
+	}
+	if before != nil && at.After(*before) {
+		return false
+	}
+	return true
+}
+
+// CompileRegex compiles pattern for the TitleRegex or NotesRegex criterion
+// named by field, reporting a malformed pattern as a *ValidationError so it
+// is rejected when the filter is built rather than when it is used.
+func CompileRegex(field, pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		var reErr *syntax.Error
+		reason := err.Error()
+		if errors.As(err, &reErr) {
+			reason = fmt.Sprintf("%s: %q", reErr.Code, reErr.Expr)
+		}
+		return nil, &ValidationError{Field: field, Reason: reason}
+	}
+	return re, nil
+}
+
+// Apply returns the tasks that match f, skipping the first Offset matches
+// and truncating to Limit when it is positive, so successive offsets page
+// through the results. Order is preserved, except that a fuzzy text search
+// ranks the results by descending FuzzyScore, keeping the input order
+// between ties; paging applies after ranking.
+func (f TaskFilter) Apply(tasks []*Task) []*Task {
+	out := Select(tasks, f)
+
+	if f.Fuzzy && f.Text != "" {
+		scores := make(map[*Task]int, len(out))
+		for _, t := range out {
+			scores[t], _ = FuzzyScore(f.Text, t.Title)
+		}
+		slices.SortStableFunc(out, func(a, b *Task) int {
+			return cmp.Compare(scores[b], scores[a])
+		})
+	}
+
+	if f.Offset > 0 {
+		if f.Offset >= len(out) {
+			return nil
+		}
+		out = out[f.Offset:]
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out
+}
+
+// dueBounds returns the due date and bound to compare, truncated to calendar
+// days in the bound's location when DueByDay is set.
+func (f TaskFilter) dueBounds(due, bound time.Time) (time.Time, time.Time) {
+	if !f.DueByDay {
+		return due, bound
+	}
+	return dayIn(due, bound.Location()), StartOfDay(bound)
+}
+
+// containsText reports whether the task's title or notes contain text,
+// ignoring case.
+func containsText(t *Task, text string) bool {
+	needle := strings.ToLower(text)
+	return strings.Contains(strings.ToLower(t.Title), needle) ||
+		strings.Contains(strings.ToLower(t.Notes), needle)
+}
+
 // containsAllTags returns true if taskTags contains all tags in filterTags.
 // Uses map-based lookup for O(n) performance.
 // Empty filterTags always returns true.
@@ -75,3 +337,34 @@ func containsAllTags(taskTags, filterTags []string) bool {
 
 	return true
 }
+
+// containsAllTagPrefixes returns true if every filter tag is equal to or an
+// ancestor of at least one task tag.
+func containsAllTagPrefixes(taskTags, filterTags []string) bool {
+	for _, parent := range filterTags {
+		found := false
+		for _, tag := range taskTags {
+			if TagWithin(tag, parent) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAnyTag returns true if at least one filter tag is present in
+// taskTags, or with prefix set, is equal to or an ancestor of a task tag.
+func containsAnyTag(taskTags, filterTags []string, prefix bool) bool {
+	for _, want := range filterTags {
+		for _, tag := range taskTags {
+			if tag == want || (prefix && TagWithin(tag, want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// taskFilterFields has TaskFilter's fields without its methods, so the JSON
+// methods below can delegate to the default encoding without recursing.
+type taskFilterFields TaskFilter
+
+// taskFilterJSON is the wire form of TaskFilter. Compiled regular
+// expressions are stored as their source patterns.
+type taskFilterJSON struct {
+	taskFilterFields
+	TitleRegex string `json:"title_regex,omitempty"`
+	NotesRegex string `json:"notes_regex,omitempty"`
+}
+
+// MarshalJSON encodes f with snake_case keys, omitting unset criteria.
+// Times use RFC 3339 and regular expressions their source pattern.
+func (f TaskFilter) MarshalJSON() ([]byte, error) {
+	w := taskFilterJSON{taskFilterFields: taskFilterFields(f)}
+	if f.TitleRegex != nil {
+		w.TitleRegex = f.TitleRegex.String()
+	}
+	if f.NotesRegex != nil {
+		w.NotesRegex = f.NotesRegex.String()
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes a filter written by MarshalJSON. Invalid statuses
+// fail as for tasks, and invalid energies or patterns are reported as a
+// *ValidationError.
+func (f *TaskFilter) UnmarshalJSON(data []byte) error {
+	var w taskFilterJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	out := TaskFilter(w.taskFilterFields)
+
+	if out.Energy != nil && !out.Energy.Valid() {
+		return &ValidationError{Field: "energy", Reason: fmt.Sprintf("unknown energy %q", *out.Energy)}
+	}
+	var err error
+	if w.TitleRegex != "" {
+		if out.TitleRegex, err = CompileRegex("title_regex", w.TitleRegex); err != nil {
+			return err
+		}
+	}
+	if w.NotesRegex != "" {
+		if out.NotesRegex, err = CompileRegex("notes_regex", w.NotesRegex); err != nil {
+			return err
+		}
+	}
+	*f = out
+	return nil
+}
+
+// presenceNames are the JSON names of Presence values.
+var presenceNames = map[Presence]string{
+	PresenceAny:      "any",
+	PresenceRequired: "required",
+	PresenceAbsent:   "absent",
+}
+
+// MarshalText encodes p as "any", "required" or "absent".
+func (p Presence) MarshalText() ([]byte, error) {
+	name, ok := presenceNames[p]
+	if !ok {
+		return nil, fmt.Errorf("invalid presence %d", int(p))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a name written by MarshalText.
+func (p *Presence) UnmarshalText(text []byte) error {
+	for v, name := range presenceNames {
+		if string(text) == name {
+			*p = v
+			return nil
+		}
+	}
+	return &ValidationError{Field: "presence", Reason: fmt.Sprintf("expected any, required or absent, got %q", text)}
+}
@@ -0,0 +1,110 @@
+package model_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// TestTaskFilter_JSONRoundTrip verifies every kind of criterion survives
+// encoding and decoding.
+func TestTaskFilter_JSONRoundTrip(t *testing.T) {
+	status := model.StatusToday
+	energy := model.EnergyLow
+	due := time.Date(2024, 6, 14, 0, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	created := time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)
+
+	want := model.TaskFilter{
+		Status:          &status,
+		Energy:          &energy,
+		Tags:            []string{"work"},
+		TagsAny:         []string{"a", "b"},
+		ExcludeTags:     []string{"someday"},
+		Text:            "release notes",
+		Fuzzy:           true,
+		TitleRegex:      regexp.MustCompile(`^fix\b`),
+		DueBefore:       &due,
+		DueByDay:        true,
+		HasNotes:        model.PresenceAbsent,
+		DeferredAtLeast: 3,
+		CreatedAfter:    &created,
+		Limit:           10,
+		Offset:          20,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	for _, key := range []string{`"status":"today"`, `"title_regex":"^fix\\b"`, `"has_notes":"absent"`, `"due_before":"2024-06-14T00:00:00+02:00"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("encoded filter missing %s: %s", key, data)
+		}
+	}
+	if strings.Contains(string(data), "has_due_date") {
+		t.Errorf("unset presence should be omitted: %s", data)
+	}
+
+	var got model.TaskFilter
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if got.TitleRegex == nil || got.TitleRegex.String() != want.TitleRegex.String() {
+		t.Errorf("TitleRegex = %v, want %v", got.TitleRegex, want.TitleRegex)
+	}
+	if !got.DueBefore.Equal(due) || !got.CreatedAfter.Equal(created) {
+		t.Errorf("times = %v, %v, want %v, %v", got.DueBefore, got.CreatedAfter, due, created)
+	}
+	got.TitleRegex, want.TitleRegex = nil, nil
+	got.DueBefore, want.DueBefore = nil, nil
+	got.CreatedAfter, want.CreatedAfter = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+// TestTaskFilter_JSONZeroValue verifies an empty filter encodes as {}.
+func TestTaskFilter_JSONZeroValue(t *testing.T) {
+	data, err := json.Marshal(model.TaskFilter{})
+	if err != nil || string(data) != "{}" {
+		t.Errorf("Marshal(TaskFilter{}) = %s, %v, want {}", data, err)
+	}
+}
+
+// TestTaskFilter_UnmarshalJSONErrors verifies malformed filters are rejected.
+func TestTaskFilter_UnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+		field   string
+	}{
+		{"invalid status", `{"status":"later"}`, model.ErrInvalidStatus, ""},
+		{"invalid energy", `{"energy":"max"}`, nil, "energy"},
+		{"invalid regex", `{"title_regex":"("}`, nil, "title_regex"},
+		{"invalid presence", `{"has_notes":"maybe"}`, nil, "presence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f model.TaskFilter
+			err := json.Unmarshal([]byte(tt.input), &f)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			var verr *model.ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Fatalf("Unmarshal() error = %v, want ValidationError for %s", err, tt.field)
+			}
+		})
+	}
+}
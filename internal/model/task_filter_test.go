@@ -1,15 +1,21 @@
-package model
+package model_test
 
 import (
+	"errors"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
 )
 
 // Test fixtures - reusable test data
 var (
-	statusPool  = StatusPool
-	statusToday = StatusToday
-	statusDone  = StatusDone
+	statusPool  = model.StatusPool
+	statusToday = model.StatusToday
+	statusDone  = model.StatusDone
 
 	now       = time.Now()
 	yesterday = now.Add(-24 * time.Hour)
@@ -20,44 +26,44 @@ var (
 func TestTaskFilter_Matches_StatusFilter(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "nil status filter matches any status",
-			filter: TaskFilter{Status: nil},
-			task:   &Task{Status: StatusPool},
+			filter: model.TaskFilter{Status: nil},
+			task:   testutil.NewTask().WithStatus(model.StatusPool).Build(),
 			want:   true,
 		},
 		{
 			name:   "status filter matches exact status - pool",
-			filter: TaskFilter{Status: &statusPool},
-			task:   &Task{Status: StatusPool},
+			filter: model.TaskFilter{Status: &statusPool},
+			task:   testutil.NewTask().WithStatus(model.StatusPool).Build(),
 			want:   true,
 		},
 		{
 			name:   "status filter matches exact status - today",
-			filter: TaskFilter{Status: &statusToday},
-			task:   &Task{Status: StatusToday},
+			filter: model.TaskFilter{Status: &statusToday},
+			task:   testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want:   true,
 		},
 		{
 			name:   "status filter matches exact status - done",
-			filter: TaskFilter{Status: &statusDone},
-			task:   &Task{Status: StatusDone},
+			filter: model.TaskFilter{Status: &statusDone},
+			task:   testutil.NewTask().WithStatus(model.StatusDone).Build(),
 			want:   true,
 		},
 		{
 			name:   "status filter rejects non-matching status",
-			filter: TaskFilter{Status: &statusPool},
-			task:   &Task{Status: StatusToday},
+			filter: model.TaskFilter{Status: &statusPool},
+			task:   testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want:   false,
 		},
 		{
 			name:   "status filter rejects different status",
-			filter: TaskFilter{Status: &statusDone},
-			task:   &Task{Status: StatusPool},
+			filter: model.TaskFilter{Status: &statusDone},
+			task:   testutil.NewTask().WithStatus(model.StatusPool).Build(),
 			want:   false,
 		},
 	}
@@ -76,80 +82,80 @@ func TestTaskFilter_Matches_StatusFilter(t *testing.T) {
 func TestTaskFilter_Matches_TagsFilter(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "nil tags filter matches any tags",
-			filter: TaskFilter{Tags: nil},
-			task:   &Task{Tags: []string{"work", "urgent"}},
+			filter: model.TaskFilter{Tags: nil},
+			task:   testutil.NewTask().WithTags("work", "urgent").Build(),
 			want:   true,
 		},
 		{
 			name:   "empty tags filter matches any tags",
-			filter: TaskFilter{Tags: []string{}},
-			task:   &Task{Tags: []string{"work", "urgent"}},
+			filter: model.TaskFilter{Tags: []string{}},
+			task:   testutil.NewTask().WithTags("work", "urgent").Build(),
 			want:   true,
 		},
 		{
 			name:   "single tag filter matches task with that tag",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: []string{"work"}},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().WithTags("work").Build(),
 			want:   true,
 		},
 		{
 			name:   "single tag filter matches task with multiple tags including that tag",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: []string{"work", "urgent", "important"}},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().WithTags("work", "urgent", "important").Build(),
 			want:   true,
 		},
 		{
 			name:   "multiple tag filter matches task with all those tags (AND semantics)",
-			filter: TaskFilter{Tags: []string{"work", "urgent"}},
-			task:   &Task{Tags: []string{"work", "urgent"}},
+			filter: model.TaskFilter{Tags: []string{"work", "urgent"}},
+			task:   testutil.NewTask().WithTags("work", "urgent").Build(),
 			want:   true,
 		},
 		{
 			name:   "multiple tag filter matches task with all filter tags plus more",
-			filter: TaskFilter{Tags: []string{"work", "urgent"}},
-			task:   &Task{Tags: []string{"work", "urgent", "important", "personal"}},
+			filter: model.TaskFilter{Tags: []string{"work", "urgent"}},
+			task:   testutil.NewTask().WithTags("work", "urgent", "important", "personal").Build(),
 			want:   true,
 		},
 		{
 			name:   "tag filter rejects task missing one required tag",
-			filter: TaskFilter{Tags: []string{"work", "urgent"}},
-			task:   &Task{Tags: []string{"work"}},
+			filter: model.TaskFilter{Tags: []string{"work", "urgent"}},
+			task:   testutil.NewTask().WithTags("work").Build(),
 			want:   false,
 		},
 		{
 			name:   "tag filter rejects task with no matching tags",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: []string{"personal", "home"}},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().WithTags("personal", "home").Build(),
 			want:   false,
 		},
 		{
 			name:   "tag filter rejects task with no tags",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: []string{}},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().WithTags().Build(),
 			want:   false,
 		},
 		{
 			name:   "tag filter rejects task with nil tags",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: nil},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().Build(),
 			want:   false,
 		},
 		{
 			name:   "empty filter matches task with no tags",
-			filter: TaskFilter{Tags: []string{}},
-			task:   &Task{Tags: []string{}},
+			filter: model.TaskFilter{Tags: []string{}},
+			task:   testutil.NewTask().WithTags().Build(),
 			want:   true,
 		},
 		{
 			name:   "nil filter matches task with nil tags",
-			filter: TaskFilter{Tags: nil},
-			task:   &Task{Tags: nil},
+			filter: model.TaskFilter{Tags: nil},
+			task:   testutil.NewTask().Build(),
 			want:   true,
 		},
 	}
@@ -168,104 +174,104 @@ func TestTaskFilter_Matches_TagsFilter(t *testing.T) {
 func TestTaskFilter_Matches_DueDateRange(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "nil DueAfter and DueBefore matches any due date",
-			filter: TaskFilter{DueAfter: nil, DueBefore: nil},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueAfter: nil, DueBefore: nil},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "DueAfter matches task due after that date",
-			filter: TaskFilter{DueAfter: &yesterday},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueAfter: &yesterday},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "DueAfter matches task due exactly on that date (inclusive)",
-			filter: TaskFilter{DueAfter: &now},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueAfter: &now},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "DueAfter rejects task due before that date",
-			filter: TaskFilter{DueAfter: &now},
-			task:   &Task{DueDate: &yesterday},
+			filter: model.TaskFilter{DueAfter: &now},
+			task:   testutil.NewTask().WithDue(yesterday).Build(),
 			want:   false,
 		},
 		{
 			name:   "DueBefore matches task due before that date",
-			filter: TaskFilter{DueBefore: &tomorrow},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueBefore: &tomorrow},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "DueBefore matches task due exactly on that date (inclusive)",
-			filter: TaskFilter{DueBefore: &now},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueBefore: &now},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "DueBefore rejects task due after that date",
-			filter: TaskFilter{DueBefore: &now},
-			task:   &Task{DueDate: &tomorrow},
+			filter: model.TaskFilter{DueBefore: &now},
+			task:   testutil.NewTask().WithDue(tomorrow).Build(),
 			want:   false,
 		},
 		{
 			name:   "DueAfter and DueBefore together define inclusive range",
-			filter: TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
-			task:   &Task{DueDate: &now},
+			filter: model.TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
+			task:   testutil.NewTask().WithDue(now).Build(),
 			want:   true,
 		},
 		{
 			name:   "date range matches task at lower boundary (inclusive)",
-			filter: TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
-			task:   &Task{DueDate: &yesterday},
+			filter: model.TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
+			task:   testutil.NewTask().WithDue(yesterday).Build(),
 			want:   true,
 		},
 		{
 			name:   "date range matches task at upper boundary (inclusive)",
-			filter: TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
-			task:   &Task{DueDate: &tomorrow},
+			filter: model.TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
+			task:   testutil.NewTask().WithDue(tomorrow).Build(),
 			want:   true,
 		},
 		{
 			name:   "date range rejects task before range",
-			filter: TaskFilter{DueAfter: &now, DueBefore: &nextWeek},
-			task:   &Task{DueDate: &yesterday},
+			filter: model.TaskFilter{DueAfter: &now, DueBefore: &nextWeek},
+			task:   testutil.NewTask().WithDue(yesterday).Build(),
 			want:   false,
 		},
 		{
 			name:   "date range rejects task after range",
-			filter: TaskFilter{DueAfter: &yesterday, DueBefore: &now},
-			task:   &Task{DueDate: &tomorrow},
+			filter: model.TaskFilter{DueAfter: &yesterday, DueBefore: &now},
+			task:   testutil.NewTask().WithDue(tomorrow).Build(),
 			want:   false,
 		},
 		{
 			name:   "DueAfter filter rejects task with nil DueDate",
-			filter: TaskFilter{DueAfter: &now},
-			task:   &Task{DueDate: nil},
+			filter: model.TaskFilter{DueAfter: &now},
+			task:   testutil.NewTask().Build(),
 			want:   false,
 		},
 		{
 			name:   "DueBefore filter rejects task with nil DueDate",
-			filter: TaskFilter{DueBefore: &now},
-			task:   &Task{DueDate: nil},
+			filter: model.TaskFilter{DueBefore: &now},
+			task:   testutil.NewTask().Build(),
 			want:   false,
 		},
 		{
 			name:   "date range filter rejects task with nil DueDate",
-			filter: TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
-			task:   &Task{DueDate: nil},
+			filter: model.TaskFilter{DueAfter: &yesterday, DueBefore: &tomorrow},
+			task:   testutil.NewTask().Build(),
 			want:   false,
 		},
 		{
 			name:   "nil date filters match task with nil DueDate",
-			filter: TaskFilter{DueAfter: nil, DueBefore: nil},
-			task:   &Task{DueDate: nil},
+			filter: model.TaskFilter{DueAfter: nil, DueBefore: nil},
+			task:   testutil.NewTask().Build(),
 			want:   true,
 		},
 	}
@@ -287,100 +293,76 @@ func TestTaskFilter_Matches_MultipleFilters_AllMustMatch(t *testing.T) {
 
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name: "task matches all filters - status and tags",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Tags:   []string{"work"},
 			},
-			task: &Task{
-				Status: StatusToday,
-				Tags:   []string{"work", "urgent"},
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work", "urgent").Build(),
 			want: true,
 		},
 		{
 			name: "task matches all filters - status, tags, and due date",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status:    &statusToday,
 				Tags:      []string{"work"},
 				DueAfter:  &yesterday,
 				DueBefore: &tomorrow,
 			},
-			task: &Task{
-				Status:  StatusToday,
-				Tags:    []string{"work", "urgent"},
-				DueDate: &now,
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work", "urgent").WithDue(now).Build(),
 			want: true,
 		},
 		{
 			name: "task fails when status doesn't match",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusPool,
 				Tags:   []string{"work"},
 			},
-			task: &Task{
-				Status: StatusToday,
-				Tags:   []string{"work"},
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work").Build(),
 			want: false,
 		},
 		{
 			name: "task fails when tags don't match",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Tags:   []string{"work", "urgent"},
 			},
-			task: &Task{
-				Status: StatusToday,
-				Tags:   []string{"work"},
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work").Build(),
 			want: false,
 		},
 		{
 			name: "task fails when due date doesn't match",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status:    &statusToday,
 				Tags:      []string{"work"},
 				DueBefore: &yesterday,
 			},
-			task: &Task{
-				Status:  StatusToday,
-				Tags:    []string{"work"},
-				DueDate: &now,
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work").WithDue(now).Build(),
 			want: false,
 		},
 		{
 			name: "task fails when multiple filters don't match",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusPool,
 				Tags:   []string{"urgent"},
 			},
-			task: &Task{
-				Status: StatusToday,
-				Tags:   []string{"work"},
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work").Build(),
 			want: false,
 		},
 		{
 			name: "task matches with some nil filters",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status:    &statusToday,
 				Tags:      nil, // nil = match any
 				DueAfter:  nil, // nil = no filter
 				DueBefore: &tomorrow,
 			},
-			task: &Task{
-				Status:  StatusToday,
-				Tags:    []string{workTag, urgentTag},
-				DueDate: &now,
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags(workTag, urgentTag).WithDue(now).Build(),
 			want: true,
 		},
 	}
@@ -398,52 +380,38 @@ func TestTaskFilter_Matches_MultipleFilters_AllMustMatch(t *testing.T) {
 func TestTaskFilter_Matches_NoFilters_MatchesAll(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "empty filter matches task with all fields",
-			filter: TaskFilter{},
-			task: &Task{
-				Status:  StatusToday,
-				Tags:    []string{"work", "urgent"},
-				DueDate: &now,
-			},
-			want: true,
+			filter: model.TaskFilter{},
+			task:   testutil.NewTask().WithStatus(model.StatusToday).WithTags("work", "urgent").WithDue(now).Build(),
+			want:   true,
 		},
 		{
 			name:   "empty filter matches task with minimal fields",
-			filter: TaskFilter{},
-			task: &Task{
-				Status: StatusPool,
-			},
-			want: true,
+			filter: model.TaskFilter{},
+			task:   testutil.NewTask().WithStatus(model.StatusPool).Build(),
+			want:   true,
 		},
 		{
 			name:   "empty filter matches task with nil fields",
-			filter: TaskFilter{},
-			task: &Task{
-				Status:  StatusDone,
-				Tags:    nil,
-				DueDate: nil,
-			},
-			want: true,
+			filter: model.TaskFilter{},
+			task:   testutil.NewTask().WithStatus(model.StatusDone).Build(),
+			want:   true,
 		},
 		{
 			name: "filter with all nil/empty values matches any task",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status:    nil,
 				Tags:      []string{},
 				DueAfter:  nil,
 				DueBefore: nil,
 				Limit:     0,
 			},
-			task: &Task{
-				Status:  StatusToday,
-				Tags:    []string{"work"},
-				DueDate: &now,
-			},
+			task: testutil.NewTask().WithStatus(model.StatusToday).WithTags("work").WithDue(now).Build(),
 			want: true,
 		},
 	}
@@ -461,50 +429,50 @@ func TestTaskFilter_Matches_NoFilters_MatchesAll(t *testing.T) {
 func TestTaskFilter_Matches_TagEdgeCases(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "filter matches task with duplicate tags",
-			filter: TaskFilter{Tags: []string{"work"}},
-			task:   &Task{Tags: []string{"work", "work", "urgent"}},
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			task:   testutil.NewTask().WithTags("work", "work", "urgent").Build(),
 			want:   true,
 		},
 		{
 			name:   "filter with duplicate tags still uses AND semantics",
-			filter: TaskFilter{Tags: []string{"work", "work"}},
-			task:   &Task{Tags: []string{"work", "urgent"}},
+			filter: model.TaskFilter{Tags: []string{"work", "work"}},
+			task:   testutil.NewTask().WithTags("work", "urgent").Build(),
 			want:   true,
 		},
 		{
 			name:   "filter matches with case-sensitive tag comparison",
-			filter: TaskFilter{Tags: []string{"Work"}},
-			task:   &Task{Tags: []string{"Work"}},
+			filter: model.TaskFilter{Tags: []string{"Work"}},
+			task:   testutil.NewTask().WithTags("Work").Build(),
 			want:   true,
 		},
 		{
 			name:   "filter rejects with different case tags",
-			filter: TaskFilter{Tags: []string{"Work"}},
-			task:   &Task{Tags: []string{"work"}},
+			filter: model.TaskFilter{Tags: []string{"Work"}},
+			task:   testutil.NewTask().WithTags("work").Build(),
 			want:   false,
 		},
 		{
 			name:   "filter matches task with tags in different order",
-			filter: TaskFilter{Tags: []string{"work", "urgent"}},
-			task:   &Task{Tags: []string{"urgent", "work"}},
+			filter: model.TaskFilter{Tags: []string{"work", "urgent"}},
+			task:   testutil.NewTask().WithTags("urgent", "work").Build(),
 			want:   true,
 		},
 		{
 			name:   "filter with empty string tag matches task with empty string tag",
-			filter: TaskFilter{Tags: []string{""}},
-			task:   &Task{Tags: []string{"", "work"}},
+			filter: model.TaskFilter{Tags: []string{""}},
+			task:   testutil.NewTask().WithTags("", "work").Build(),
 			want:   true,
 		},
 		{
 			name:   "filter with empty string tag rejects task without empty string tag",
-			filter: TaskFilter{Tags: []string{""}},
-			task:   &Task{Tags: []string{"work"}},
+			filter: model.TaskFilter{Tags: []string{""}},
+			task:   testutil.NewTask().WithTags("work").Build(),
 			want:   false,
 		},
 	}
@@ -528,62 +496,62 @@ func TestTaskFilter_Matches_TimeEdgeCases(t *testing.T) {
 
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name:   "times with different nanoseconds are treated as different",
-			filter: TaskFilter{DueAfter: &timeWithNanos},
-			task:   &Task{DueDate: &timeWithoutNanos},
+			filter: model.TaskFilter{DueAfter: &timeWithNanos},
+			task:   testutil.NewTask().WithDue(timeWithoutNanos).Build(),
 			want:   false,
 		},
 		{
 			name:   "exact time comparison includes nanoseconds",
-			filter: TaskFilter{DueAfter: &timeWithNanos, DueBefore: &timeWithNanos},
-			task:   &Task{DueDate: &timeWithNanos},
+			filter: model.TaskFilter{DueAfter: &timeWithNanos, DueBefore: &timeWithNanos},
+			task:   testutil.NewTask().WithDue(timeWithNanos).Build(),
 			want:   true,
 		},
 		{
 			name:   "different nanoseconds in same second compare correctly",
-			filter: TaskFilter{DueAfter: &timeWithNanos},
-			task:   &Task{DueDate: &sameSecondDiffNanos},
+			filter: model.TaskFilter{DueAfter: &timeWithNanos},
+			task:   testutil.NewTask().WithDue(sameSecondDiffNanos).Build(),
 			want:   true,
 		},
 		{
 			name: "inverted range (DueAfter > DueBefore) rejects all tasks",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				DueAfter:  &tomorrow,
 				DueBefore: &yesterday,
 			},
-			task: &Task{DueDate: &now},
+			task: testutil.NewTask().WithDue(now).Build(),
 			want: false,
 		},
 		{
 			name: "inverted range rejects task even at boundaries",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				DueAfter:  &tomorrow,
 				DueBefore: &yesterday,
 			},
-			task: &Task{DueDate: &yesterday},
+			task: testutil.NewTask().WithDue(yesterday).Build(),
 			want: false,
 		},
 		{
 			name: "equal DueAfter and DueBefore creates single-instant range",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				DueAfter:  &now,
 				DueBefore: &now,
 			},
-			task: &Task{DueDate: &now},
+			task: testutil.NewTask().WithDue(now).Build(),
 			want: true,
 		},
 		{
 			name: "single-instant range rejects different time",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				DueAfter:  &now,
 				DueBefore: &now,
 			},
-			task: &Task{DueDate: &tomorrow},
+			task: testutil.NewTask().WithDue(tomorrow).Build(),
 			want: false,
 		},
 	}
@@ -601,53 +569,53 @@ func TestTaskFilter_Matches_TimeEdgeCases(t *testing.T) {
 func TestTaskFilter_LimitIsIgnoredByMatches(t *testing.T) {
 	tests := []struct {
 		name   string
-		filter TaskFilter
-		task   *Task
+		filter model.TaskFilter
+		task   *model.Task
 		want   bool
 	}{
 		{
 			name: "Limit=0 does not affect matching",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Limit:  0,
 			},
-			task: &Task{Status: StatusToday},
+			task: testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want: true,
 		},
 		{
 			name: "Limit=1 does not affect matching",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Limit:  1,
 			},
-			task: &Task{Status: StatusToday},
+			task: testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want: true,
 		},
 		{
 			name: "Limit=100 does not affect matching",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Limit:  100,
 			},
-			task: &Task{Status: StatusToday},
+			task: testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want: true,
 		},
 		{
 			name: "negative Limit does not affect matching",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusToday,
 				Limit:  -1,
 			},
-			task: &Task{Status: StatusToday},
+			task: testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want: true,
 		},
 		{
 			name: "Limit does not cause match to fail",
-			filter: TaskFilter{
+			filter: model.TaskFilter{
 				Status: &statusPool,
 				Limit:  5,
 			},
-			task: &Task{Status: StatusToday},
+			task: testutil.NewTask().WithStatus(model.StatusToday).Build(),
 			want: false, // Fails because of status, not limit
 		},
 	}
@@ -662,3 +630,576 @@ func TestTaskFilter_LimitIsIgnoredByMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskFilter_Matches_DueByDay(t *testing.T) {
+	friday := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	fridayEvening := time.Date(2025, 11, 14, 18, 30, 0, 0, time.UTC)
+	thursdayNoon := time.Date(2025, 11, 13, 12, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 11, 15, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{
+			name:   "exact comparison rejects later time on boundary day",
+			filter: model.TaskFilter{DueBefore: &friday},
+			task:   testutil.NewTask().WithDue(fridayEvening).Build(),
+			want:   false,
+		},
+		{
+			name:   "day granularity includes whole boundary day for DueBefore",
+			filter: model.TaskFilter{DueBefore: &friday, DueByDay: true},
+			task:   testutil.NewTask().WithDue(fridayEvening).Build(),
+			want:   true,
+		},
+		{
+			name:   "day granularity still rejects the following day",
+			filter: model.TaskFilter{DueBefore: &friday, DueByDay: true},
+			task:   testutil.NewTask().WithDue(saturday).Build(),
+			want:   false,
+		},
+		{
+			name:   "day granularity includes whole boundary day for DueAfter",
+			filter: model.TaskFilter{DueAfter: &fridayEvening, DueByDay: true},
+			task:   testutil.NewTask().WithDue(friday).Build(),
+			want:   true,
+		},
+		{
+			name:   "day granularity rejects earlier day for DueAfter",
+			filter: model.TaskFilter{DueAfter: &fridayEvening, DueByDay: true},
+			task:   testutil.NewTask().WithDue(thursdayNoon).Build(),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_TagMatchesPrefix(t *testing.T) {
+	task := testutil.NewTask().WithTags("work/projectA/backend", "home").Build()
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   bool
+	}{
+		{
+			name:   "exact matching ignores descendants",
+			filter: model.TaskFilter{Tags: []string{"work"}},
+			want:   false,
+		},
+		{
+			name:   "root matches descendant",
+			filter: model.TaskFilter{Tags: []string{"work"}, TagMatchesPrefix: true},
+			want:   true,
+		},
+		{
+			name:   "intermediate level matches descendant",
+			filter: model.TaskFilter{Tags: []string{"work/projectA"}, TagMatchesPrefix: true},
+			want:   true,
+		},
+		{
+			name:   "full path matches itself",
+			filter: model.TaskFilter{Tags: []string{"work/projectA/backend"}, TagMatchesPrefix: true},
+			want:   true,
+		},
+		{
+			name:   "partial segment does not match",
+			filter: model.TaskFilter{Tags: []string{"work/project"}, TagMatchesPrefix: true},
+			want:   false,
+		},
+		{
+			name:   "deeper filter than task tag does not match",
+			filter: model.TaskFilter{Tags: []string{"home/garden"}, TagMatchesPrefix: true},
+			want:   false,
+		},
+		{
+			name:   "all filter tags still required",
+			filter: model.TaskFilter{Tags: []string{"work", "errands"}, TagMatchesPrefix: true},
+			want:   false,
+		},
+		{
+			name:   "several prefixes satisfied",
+			filter: model.TaskFilter{Tags: []string{"work", "home"}, TagMatchesPrefix: true},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagWithin(t *testing.T) {
+	tests := []struct {
+		tag, parent string
+		want        bool
+	}{
+		{"work", "work", true},
+		{"work/a", "work", true},
+		{"workshop", "work", false},
+		{"work", "work/a", false},
+	}
+
+	for _, tt := range tests {
+		if got := model.TagWithin(tt.tag, tt.parent); got != tt.want {
+			t.Errorf("TagWithin(%q, %q) = %v, want %v", tt.tag, tt.parent, got, tt.want)
+		}
+	}
+}
+
+func TestTaskFilter_Matches_Energy(t *testing.T) {
+	low := model.EnergyLow
+	none := model.EnergyNone
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{"nil matches unset", model.TaskFilter{}, testutil.NewTask().Build(), true},
+		{"low matches low", model.TaskFilter{Energy: &low}, testutil.NewTask().WithEnergy(model.EnergyLow).Build(), true},
+		{"low rejects high", model.TaskFilter{Energy: &low}, testutil.NewTask().WithEnergy(model.EnergyHigh).Build(), false},
+		{"low rejects unset", model.TaskFilter{Energy: &low}, testutil.NewTask().Build(), false},
+		{"none matches unset", model.TaskFilter{Energy: &none}, testutil.NewTask().Build(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_TagsAny(t *testing.T) {
+	work := testutil.NewTask().WithTags("work", "urgent").Build()
+	errands := testutil.NewTask().WithTags("errands").Build()
+	nested := testutil.NewTask().WithTags("work/projectA").Build()
+	untagged := testutil.NewTask().Build()
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{"first alternative", model.TaskFilter{TagsAny: []string{"work", "errands"}}, work, true},
+		{"second alternative", model.TaskFilter{TagsAny: []string{"work", "errands"}}, errands, true},
+		{"no alternative", model.TaskFilter{TagsAny: []string{"home", "garden"}}, work, false},
+		{"untagged task", model.TaskFilter{TagsAny: []string{"work"}}, untagged, false},
+		{"empty matches all", model.TaskFilter{TagsAny: []string{}}, untagged, true},
+		{"combined with AND tags", model.TaskFilter{Tags: []string{"urgent"}, TagsAny: []string{"work", "errands"}}, work, true},
+		{"AND tags still required", model.TaskFilter{Tags: []string{"urgent"}, TagsAny: []string{"work", "errands"}}, errands, false},
+		{"exact ignores descendants", model.TaskFilter{TagsAny: []string{"work", "errands"}}, nested, false},
+		{"prefix matches descendants", model.TaskFilter{TagsAny: []string{"work", "errands"}, TagMatchesPrefix: true}, nested, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_ExcludeTags(t *testing.T) {
+	someday := testutil.NewTask().WithTags("home", "someday").Build()
+	home := testutil.NewTask().WithTags("home").Build()
+	nested := testutil.NewTask().WithTags("someday/maybe").Build()
+	untagged := testutil.NewTask().Build()
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{"excluded tag rejects", model.TaskFilter{ExcludeTags: []string{"someday"}}, someday, false},
+		{"task without excluded tag", model.TaskFilter{ExcludeTags: []string{"someday"}}, home, true},
+		{"untagged task", model.TaskFilter{ExcludeTags: []string{"someday"}}, untagged, true},
+		{"any excluded tag rejects", model.TaskFilter{ExcludeTags: []string{"work", "home"}}, home, false},
+		{"exclusion beats inclusion", model.TaskFilter{Tags: []string{"home"}, ExcludeTags: []string{"someday"}}, someday, false},
+		{"exact ignores descendants", model.TaskFilter{ExcludeTags: []string{"someday"}}, nested, true},
+		{"prefix excludes descendants", model.TaskFilter{ExcludeTags: []string{"someday"}, TagMatchesPrefix: true}, nested, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_Text(t *testing.T) {
+	task := testutil.NewTask().WithTitle("Renew Passport").WithNotes("Bring two photos").Build()
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"empty matches", "", true},
+		{"title substring", "passport", true},
+		{"title ignores case", "RENEW", true},
+		{"notes substring", "photos", true},
+		{"spanning title and notes", "passport bring", false},
+		{"absent", "visa", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.TaskFilter{Text: tt.text}.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_Fuzzy(t *testing.T) {
+	task := testutil.NewTask().WithTitle("Renew passport").WithNotes("photos").Build()
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"subsequence", "rnwpsp", true},
+		{"ignores case", "RP", true},
+		{"out of order", "tn", false},
+		{"notes not searched", "photos", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.TaskFilter{Text: tt.text, Fuzzy: true}.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Apply(t *testing.T) {
+	paper := testutil.NewTask().WithTitle("Reorder paper").Build()
+	passport := testutil.NewTask().WithTitle("Renew passport").Build()
+	groceries := testutil.NewTask().WithTitle("Groceries").Build()
+	tasks := []*model.Task{paper, groceries, passport}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []*model.Task
+	}{
+		{"no criteria keeps order", model.TaskFilter{}, tasks},
+		{"substring keeps order", model.TaskFilter{Text: "re"}, []*model.Task{paper, passport}},
+		{"fuzzy ranks best first", model.TaskFilter{Text: "rp", Fuzzy: true}, []*model.Task{passport, paper}},
+		{"limit applied after ranking", model.TaskFilter{Text: "rp", Fuzzy: true, Limit: 1}, []*model.Task{passport}},
+		{"no matches", model.TaskFilter{Text: "xyz"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(tasks)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() returned %d tasks, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Apply()[%d] = %q, want %q", i, got[i].Title, tt.want[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_Regex(t *testing.T) {
+	task := testutil.NewTask().WithTitle("Fix bug #1234").WithNotes("see OPS-7").Build()
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   bool
+	}{
+		{"title matches", model.TaskFilter{TitleRegex: regexp.MustCompile(`#\d+$`)}, true},
+		{"title does not match", model.TaskFilter{TitleRegex: regexp.MustCompile(`^bug`)}, false},
+		{"notes match", model.TaskFilter{NotesRegex: regexp.MustCompile(`[A-Z]+-\d+`)}, true},
+		{"title regex ignores notes", model.TaskFilter{TitleRegex: regexp.MustCompile(`OPS`)}, false},
+		{"both required", model.TaskFilter{TitleRegex: regexp.MustCompile(`Fix`), NotesRegex: regexp.MustCompile(`JIRA`)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRegex(t *testing.T) {
+	re, err := model.CompileRegex("title_regex", `(?i)^renew`)
+	if err != nil {
+		t.Fatalf("CompileRegex() unexpected error: %v", err)
+	}
+	if !re.MatchString("RENEW passport") {
+		t.Errorf("compiled regex did not match")
+	}
+
+	_, err = model.CompileRegex("title_regex", `fix (bug`)
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Field != "title_regex" {
+		t.Fatalf("CompileRegex() error = %v, want ValidationError for title_regex", err)
+	}
+	if !strings.Contains(verr.Reason, "missing closing )") {
+		t.Errorf("Reason = %q, want the syntax problem", verr.Reason)
+	}
+}
+
+func TestTaskFilter_Apply_Pagination(t *testing.T) {
+	var tasks []*model.Task
+	for _, title := range []string{"a", "b", "c", "d", "e"} {
+		tasks = append(tasks, testutil.NewTask().WithTitle(title).Build())
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   string
+	}{
+		{"first page", model.TaskFilter{Limit: 2}, "ab"},
+		{"second page", model.TaskFilter{Limit: 2, Offset: 2}, "cd"},
+		{"last partial page", model.TaskFilter{Limit: 2, Offset: 4}, "e"},
+		{"past the end", model.TaskFilter{Limit: 2, Offset: 5}, ""},
+		{"offset without limit", model.TaskFilter{Offset: 3}, "de"},
+		{"negative offset ignored", model.TaskFilter{Offset: -1}, "abcde"},
+		{"offset counts matches only", model.TaskFilter{TitleRegex: regexp.MustCompile(`^[^a]$`), Offset: 1}, "cde"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			for _, task := range tt.filter.Apply(tasks) {
+				got += task.Title
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_CreatedRange(t *testing.T) {
+	task := testutil.NewTask().WithCreatedAt(now).Build()
+	justBefore := now.Add(-time.Second)
+	justAfter := now.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   bool
+	}{
+		{"open range", model.TaskFilter{}, true},
+		{"within range", model.TaskFilter{CreatedAfter: &yesterday, CreatedBefore: &tomorrow}, true},
+		{"after bound inclusive", model.TaskFilter{CreatedAfter: &now}, true},
+		{"before bound inclusive", model.TaskFilter{CreatedBefore: &now}, true},
+		{"created too early", model.TaskFilter{CreatedAfter: &justAfter}, false},
+		{"created too late", model.TaskFilter{CreatedBefore: &justBefore}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_CompletedRange(t *testing.T) {
+	done := testutil.NewTask().WithStatus(model.StatusDone).Build()
+	done.CompletedAt = &now
+	open := testutil.NewTask().Build()
+	justAfter := now.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{"open range matches open task", model.TaskFilter{}, open, true},
+		{"within range", model.TaskFilter{CompletedAfter: &yesterday, CompletedBefore: &tomorrow}, done, true},
+		{"bounds inclusive", model.TaskFilter{CompletedAfter: &now, CompletedBefore: &now}, done, true},
+		{"completed too early", model.TaskFilter{CompletedAfter: &justAfter}, done, false},
+		{"completed too late", model.TaskFilter{CompletedBefore: &yesterday}, done, false},
+		{"open task rejected by after", model.TaskFilter{CompletedAfter: &yesterday}, open, false},
+		{"open task rejected by before", model.TaskFilter{CompletedBefore: &tomorrow}, open, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_OverdueOnly(t *testing.T) {
+	wednesday := time.Date(2024, 6, 12, 9, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(wednesday))()
+	monday := wednesday.AddDate(0, 0, -2)
+	lateTonight := time.Date(2024, 6, 12, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		task *model.Task
+		want bool
+	}{
+		{"past due", testutil.NewTask().WithDue(monday).Build(), true},
+		{"due later today", testutil.NewTask().WithDue(lateTonight).Build(), false},
+		{"no due date", testutil.NewTask().Build(), false},
+		{"done", testutil.NewTask().WithDue(monday).WithStatus(model.StatusDone).Build(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.NewOverdueFilter().Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_Presence(t *testing.T) {
+	bare := testutil.NewTask().Build()
+	due := testutil.NewTask().WithDue(tomorrow).Build()
+	noted := testutil.NewTask().WithNotes("call first").Build()
+	split := testutil.NewTask().Build()
+	split.NotesFile = "notes/abc.md"
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		task   *model.Task
+		want   bool
+	}{
+		{"any due date", model.TaskFilter{}, bare, true},
+		{"requires due date", model.TaskFilter{HasDueDate: model.PresenceRequired}, due, true},
+		{"requires due date, missing", model.TaskFilter{HasDueDate: model.PresenceRequired}, bare, false},
+		{"forbids due date", model.TaskFilter{HasDueDate: model.PresenceAbsent}, bare, true},
+		{"forbids due date, present", model.TaskFilter{HasDueDate: model.PresenceAbsent}, due, false},
+		{"requires notes", model.TaskFilter{HasNotes: model.PresenceRequired}, noted, true},
+		{"notes file counts as notes", model.TaskFilter{HasNotes: model.PresenceRequired}, split, true},
+		{"forbids notes", model.TaskFilter{HasNotes: model.PresenceAbsent}, noted, false},
+		{"unscheduled without notes", model.TaskFilter{HasDueDate: model.PresenceAbsent, HasNotes: model.PresenceAbsent}, bare, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_DeferredAtLeast(t *testing.T) {
+	tests := []struct {
+		name     string
+		atLeast  int
+		deferred int
+		want     bool
+	}{
+		{"zero matches never deferred", 0, 0, true},
+		{"below threshold", 3, 2, false},
+		{"at threshold", 3, 3, true},
+		{"above threshold", 3, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := testutil.NewTask().Build()
+			task.DeferredCount = tt.deferred
+			got := model.TaskFilter{DeferredAtLeast: tt.atLeast}.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_NoTags(t *testing.T) {
+	tests := []struct {
+		name string
+		task *model.Task
+		want bool
+	}{
+		{"untagged", testutil.NewTask().Build(), true},
+		{"empty tag slice", testutil.NewTask().WithTags().Build(), true},
+		{"tagged", testutil.NewTask().WithTags("work").Build(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.TaskFilter{NoTags: true}.Matches(tt.task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilter_Matches_IDPrefix(t *testing.T) {
+	id, _ := model.ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+	task := testutil.NewTask().WithID(id).Build()
+
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"", true},
+		{"550e", true},
+		{"550E8400", true},
+		{"550f", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got := model.TaskFilter{IDPrefix: tt.prefix}.Matches(task)
+			if got != tt.want {
+				t.Errorf("TaskFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
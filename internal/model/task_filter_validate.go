@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Validate reports criteria that are malformed or contradict each other, so
+// a filter that can never match is flagged to the user instead of silently
+// returning nothing.
+//
+// Returns nil when the filter is usable; otherwise ValidationErrors holding
+// one *ValidationError per problem, keyed by the JSON name of the field.
+func (f TaskFilter) Validate() error {
+	var errs ValidationErrors
+
+	if strings.Trim(strings.ToLower(f.IDPrefix), "0123456789abcdef-") != "" {
+		errs.Append("id_prefix", "must contain only hexadecimal digits and hyphens")
+	}
+	if f.Status != nil && !f.Status.Valid() {
+		errs.Append("status", "must be one of pool, today, done")
+	}
+	if f.Energy != nil && !f.Energy.Valid() {
+		errs.Append("energy", "must be empty or one of low, medium, high")
+	}
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"limit", f.Limit},
+		{"offset", f.Offset},
+		{"deferred_at_least", f.DeferredAtLeast},
+	} {
+		if field.value < 0 {
+			errs.Append(field.name, "must not be negative")
+		}
+	}
+	for _, p := range []struct {
+		name  string
+		value Presence
+	}{
+		{"has_due_date", f.HasDueDate},
+		{"has_notes", f.HasNotes},
+	} {
+		if _, ok := presenceNames[p.value]; !ok {
+			errs.Append(p.name, "must be any, required or absent")
+		}
+	}
+
+	for _, tag := range f.ExcludeTags {
+		if slices.Contains(f.Tags, tag) {
+			errs.Append("exclude_tags", fmt.Sprintf("%q is also required", tag))
+		}
+	}
+
+	if f.NoTags && (len(f.Tags) > 0 || len(f.TagsAny) > 0) {
+		errs.Append("no_tags", "excludes tags that other criteria require")
+	}
+
+	if f.DueAfter != nil && f.DueBefore != nil {
+		after, before := *f.DueAfter, *f.DueBefore
+		if f.DueByDay {
+			after, before = StartOfDay(after), dayIn(before, after.Location())
+		}
+		if after.After(before) {
+			errs.Append("due_after", "must not be later than due_before")
+		}
+	}
+	if f.HasDueDate == PresenceAbsent && (f.DueAfter != nil || f.DueBefore != nil || f.OverdueOnly) {
+		errs.Append("has_due_date", "excludes due dates that other criteria require")
+	}
+	checkRange(&errs, "created", f.CreatedAfter, f.CreatedBefore)
+	checkRange(&errs, "completed", f.CompletedAfter, f.CompletedBefore)
+
+	completed := f.CompletedAfter != nil || f.CompletedBefore != nil
+	if f.Status != nil && *f.Status != StatusDone && completed {
+		errs.Append("status", "only done tasks have a completion time")
+	}
+	if f.Status != nil && *f.Status == StatusDone && f.OverdueOnly {
+		errs.Append("overdue_only", "done tasks are never overdue")
+	}
+
+	return errs.Err()
+}
+
+// checkRange flags an inclusive range on field whose lower bound is later
+// than its upper bound.
+func checkRange(errs *ValidationErrors, field string, after, before *time.Time) {
+	if after != nil && before != nil && after.After(*before) {
+		errs.Append(field+"_after", "must not be later than "+field+"_before")
+	}
+}
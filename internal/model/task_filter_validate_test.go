@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTaskFilter_Validate verifies each contradiction is reported against
+// the right field, and that usable filters pass.
+func TestTaskFilter_Validate(t *testing.T) {
+	monday := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	mondayNoon := monday.Add(3 * time.Hour)
+	friday := monday.AddDate(0, 0, 4)
+	bogus := TaskStatus("later")
+	done, today := StatusDone, StatusToday
+	bad := Energy("max")
+
+	tests := []struct {
+		name   string
+		filter TaskFilter
+		fields []string
+	}{
+		{"zero value", TaskFilter{}, nil},
+		{"ordered due range", TaskFilter{DueAfter: &monday, DueBefore: &friday}, nil},
+		{"same day by day", TaskFilter{DueAfter: &mondayNoon, DueBefore: &monday, DueByDay: true}, nil},
+		{"done in range", TaskFilter{Status: &done, CompletedAfter: &monday}, nil},
+		{"inverted due range", TaskFilter{DueAfter: &friday, DueBefore: &monday}, []string{"due_after"}},
+		{"inverted instants", TaskFilter{DueAfter: &mondayNoon, DueBefore: &monday}, []string{"due_after"}},
+		{"inverted created range", TaskFilter{CreatedAfter: &friday, CreatedBefore: &monday}, []string{"created_after"}},
+		{"inverted completed range", TaskFilter{CompletedAfter: &friday, CompletedBefore: &monday}, []string{"completed_after"}},
+		{"id prefix", TaskFilter{IDPrefix: "550E-84"}, nil},
+		{"non-hex id prefix", TaskFilter{IDPrefix: "#12"}, []string{"id_prefix"}},
+		{"unknown status", TaskFilter{Status: &bogus}, []string{"status"}},
+		{"unknown energy", TaskFilter{Energy: &bad}, []string{"energy"}},
+		{"negative numbers", TaskFilter{Limit: -1, Offset: -2, DeferredAtLeast: -3}, []string{"limit", "offset", "deferred_at_least"}},
+		{"unknown presence", TaskFilter{HasNotes: Presence(7)}, []string{"has_notes"}},
+		{"tag required and excluded", TaskFilter{Tags: []string{"work"}, ExcludeTags: []string{"work"}}, []string{"exclude_tags"}},
+		{"untagged but tagged", TaskFilter{NoTags: true, TagsAny: []string{"work"}}, []string{"no_tags"}},
+		{"no due date but due range", TaskFilter{HasDueDate: PresenceAbsent, DueBefore: &friday}, []string{"has_due_date"}},
+		{"no due date but overdue", TaskFilter{HasDueDate: PresenceAbsent, OverdueOnly: true}, []string{"has_due_date"}},
+		{"open task completed", TaskFilter{Status: &today, CompletedBefore: &friday}, []string{"status"}},
+		{"done and overdue", TaskFilter{Status: &done, OverdueOnly: true}, []string{"overdue_only"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("Validate() = %v, want failures for %v", err, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if errs[i].Field != field {
+					t.Errorf("failure %d is for %q, want %q", i, errs[i].Field, field)
+				}
+			}
+		})
+	}
+}
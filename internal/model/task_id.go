@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/google/uuid"
 )
 
@@ -22,6 +25,34 @@ func ParseTaskID(id string) (TaskID, error) {
 	return TaskID(uid), nil
 }
 
+// MarshalText encodes the ID in its canonical UUID form, so IDs read as
+// strings in JSON journals and exports.
+func (t TaskID) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes an ID written by MarshalText.
+func (t *TaskID) UnmarshalText(text []byte) error {
+	id, err := ParseTaskID(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid task ID %q: %w", text, err)
+	}
+	*t = id
+	return nil
+}
+
+// Short returns the leading ShortIDLength characters of the UUID, suitable
+// for compact display and prefix lookups.
+func (t TaskID) Short() string {
+	return t.String()[:ShortIDLength]
+}
+
+// HasPrefix reports whether the UUID's string form starts with prefix,
+// ignoring case.
+func (t TaskID) HasPrefix(prefix string) bool {
+	return strings.HasPrefix(t.String(), strings.ToLower(prefix))
+}
+
 func (t TaskID) IsEmpty() bool {
 	return t == TaskID(uuid.Nil)
 }
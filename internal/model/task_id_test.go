@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -68,3 +69,44 @@ func TestParseTaskID_EmptyUUID(t *testing.T) {
 		t.Fatalf("expected parsed TaskID to be empty")
 	}
 }
+
+func TestShort_ReturnsPrefix(t *testing.T) {
+	taskID, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+
+	if got := taskID.Short(); got != "550e8400" {
+		t.Fatalf("expected short ID 550e8400, got %s", got)
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	taskID, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+
+	for prefix, want := range map[string]bool{
+		"":              true,
+		"550e":          true,
+		"550E8400-E29B": true,
+		"550f":          false,
+		"e29b":          false,
+	} {
+		if got := taskID.HasPrefix(prefix); got != want {
+			t.Errorf("HasPrefix(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+}
+
+func TestTaskID_JSON(t *testing.T) {
+	taskID, _ := ParseTaskID("550e8400-e29b-41d4-a716-446655440000")
+
+	data, err := json.Marshal(taskID)
+	if err != nil || string(data) != `"550e8400-e29b-41d4-a716-446655440000"` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	var got TaskID
+	if err := json.Unmarshal(data, &got); err != nil || got != taskID {
+		t.Errorf("Unmarshal() = %s, %v, want %s", got, err, taskID)
+	}
+	if err := json.Unmarshal([]byte(`"not-a-uuid"`), &got); err == nil {
+		t.Error("Unmarshal() accepted a malformed ID")
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Conflict records a field changed differently on both sides of a merge.
+//
+// Resolved conflicts were settled by last-writer-wins using each side's
+// UpdatedAt; the merged task already holds the winning value. Unresolved
+// conflicts (equal timestamps) keep the local value provisionally and should
+// be surfaced to the user.
+type Conflict struct {
+	Field    string
+	Base     any
+	Local    any
+	Remote   any
+	Resolved bool
+}
+
+func (c Conflict) String() string {
+	state := "unresolved"
+	if c.Resolved {
+		state = "resolved"
+	}
+	return fmt.Sprintf("%s (%s): local %s, remote %s",
+		c.Field, state, formatChangeValue(c.Local), formatChangeValue(c.Remote))
+}
+
+// MergeTasks performs a field-level three-way merge of two divergent versions
+// of the same task against their common ancestor.
+//
+// For each field: if only one side changed it, that change wins; if both made
+// the same change, it is kept; if both changed it differently, the side with
+// the later UpdatedAt wins and a Conflict is reported. Tags are merged as sets
+// (additions and removals from both sides apply) and never conflict.
+//
+// A nil base means no common ancestor is known, so every difference between
+// local and remote is treated as a concurrent change.
+//
+// The merged task's UpdatedAt is the later of the two sides. Status and
+// CompletedAt are reconciled so the result always satisfies Validate's
+// completion invariant.
+//
+// Returns a *ValidationError if local or remote is nil or their IDs differ.
+func MergeTasks(base, local, remote *Task) (*Task, []Conflict, error) {
+	if local == nil || remote == nil {
+		return nil, nil, &ValidationError{Field: "task", Reason: "local and remote versions are required"}
+	}
+	if local.ID != remote.ID || (base != nil && base.ID != local.ID) {
+		return nil, nil, &ValidationError{Field: "id", Reason: "cannot merge versions of different tasks"}
+	}
+	if base == nil {
+		base = &Task{ID: local.ID, CreatedAt: local.CreatedAt}
+	}
+
+	remoteWins := remote.UpdatedAt.After(local.UpdatedAt)
+	tie := remote.UpdatedAt.Equal(local.UpdatedAt)
+
+	merged := local.Clone()
+	if remoteWins {
+		merged.UpdatedAt = remote.UpdatedAt
+	}
+
+	var conflicts []Conflict
+	for _, f := range taskFields {
+		switch {
+		case f.equal(local, remote):
+			// Both sides agree; merged already holds the local value.
+		case f.equal(base, local):
+			f.assign(merged, remote)
+		case f.equal(base, remote):
+			// Only local changed; keep it.
+		case f.merge != nil:
+			f.merge(merged, base, local, remote)
+		default:
+			if remoteWins {
+				f.assign(merged, remote)
+			}
+			conflicts = append(conflicts, Conflict{
+				Field:    f.name,
+				Base:     f.value(base),
+				Local:    f.value(local),
+				Remote:   f.value(remote),
+				Resolved: !tie,
+			})
+		}
+	}
+
+	reconcileCompletion(merged, local, remote)
+	return merged, conflicts, nil
+}
+
+// TakeField settles a conflict on the named field, as named in Conflict,
+// in src's favour by copying it to t, reconciling Status and CompletedAt
+// as MergeTasks does. It reports false, changing nothing, for a name that
+// is not a mergeable field.
+func (t *Task) TakeField(name string, src *Task) bool {
+	for _, f := range taskFields {
+		if f.name == name {
+			f.assign(t, src)
+			reconcileCompletion(t, t, src)
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileCompletion restores the Status/CompletedAt invariant when the two
+// fields were taken from different sides.
+func reconcileCompletion(merged, local, remote *Task) {
+	if merged.Status != StatusDone {
+		merged.CompletedAt = nil
+		return
+	}
+	if merged.CompletedAt != nil {
+		return
+	}
+	for _, side := range []*Task{local, remote} {
+		if side.CompletedAt != nil {
+			merged.CompletedAt = cloneTime(side.CompletedAt)
+			return
+		}
+	}
+	merged.CompletedAt = cloneTime(&merged.UpdatedAt)
+}
+
+// mergeTagSets applies both sides' additions and removals relative to base,
+// preserving local order followed by remote-only additions.
+func mergeTagSets(base, local, remote []string) []string {
+	removed := make(map[string]bool)
+	for _, tag := range base {
+		if !slices.Contains(local, tag) || !slices.Contains(remote, tag) {
+			removed[tag] = true
+		}
+	}
+
+	var merged []string
+	seen := make(map[string]bool)
+	for _, tag := range append(slices.Clone(local), remote...) {
+		if removed[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
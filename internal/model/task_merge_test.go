@@ -0,0 +1,224 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+var mergeBase = time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC)
+
+// mergeFixture returns a base task and independent local/remote copies.
+func mergeFixture() (base, local, remote *Task) {
+	base = &Task{
+		ID:        NewTaskID(),
+		CreatedAt: mergeBase,
+		UpdatedAt: mergeBase,
+		Title:     "Plan sprint",
+		Status:    StatusPool,
+		Tags:      []string{"work", "planning"},
+	}
+	return base, base.Clone(), base.Clone()
+}
+
+// TestMergeTasks_NonOverlappingChanges verifies that edits to different
+// fields on each side are combined without conflicts.
+func TestMergeTasks_NonOverlappingChanges(t *testing.T) {
+	base, local, remote := mergeFixture()
+	local.Title = "Plan sprint 12"
+	local.UpdatedAt = mergeBase.Add(time.Hour)
+	remote.Notes = "bring velocity chart"
+	remote.Status = StatusToday
+	remote.UpdatedAt = mergeBase.Add(2 * time.Hour)
+
+	merged, conflicts, err := MergeTasks(base, local, remote)
+	if err != nil {
+		t.Fatalf("MergeTasks() error = %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if merged.Title != "Plan sprint 12" || merged.Notes != "bring velocity chart" || merged.Status != StatusToday {
+		t.Errorf("unexpected merge result: %+v", merged)
+	}
+	if !merged.UpdatedAt.Equal(remote.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want latest %v", merged.UpdatedAt, remote.UpdatedAt)
+	}
+}
+
+// TestMergeTasks_ConcurrentChange_LastWriterWins verifies conflicting edits are
+// resolved by UpdatedAt and reported.
+func TestMergeTasks_ConcurrentChange_LastWriterWins(t *testing.T) {
+	tests := []struct {
+		name      string
+		localAt   time.Time
+		remoteAt  time.Time
+		wantTitle string
+		resolved  bool
+	}{
+		{name: "remote newer", localAt: mergeBase.Add(time.Hour), remoteAt: mergeBase.Add(2 * time.Hour), wantTitle: "remote", resolved: true},
+		{name: "local newer", localAt: mergeBase.Add(3 * time.Hour), remoteAt: mergeBase.Add(2 * time.Hour), wantTitle: "local", resolved: true},
+		{name: "tie keeps local unresolved", localAt: mergeBase.Add(time.Hour), remoteAt: mergeBase.Add(time.Hour), wantTitle: "local", resolved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, local, remote := mergeFixture()
+			local.Title, local.UpdatedAt = "local", tt.localAt
+			remote.Title, remote.UpdatedAt = "remote", tt.remoteAt
+
+			merged, conflicts, err := MergeTasks(base, local, remote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if merged.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", merged.Title, tt.wantTitle)
+			}
+			if len(conflicts) != 1 {
+				t.Fatalf("expected 1 conflict, got %v", conflicts)
+			}
+			c := conflicts[0]
+			if c.Field != "title" || c.Base != "Plan sprint" || c.Local != "local" || c.Remote != "remote" {
+				t.Errorf("unexpected conflict %+v", c)
+			}
+			if c.Resolved != tt.resolved {
+				t.Errorf("Resolved = %v, want %v", c.Resolved, tt.resolved)
+			}
+		})
+	}
+}
+
+// TestMergeTasks_TagsMergeAsSets verifies both sides' additions and removals
+// are honored without conflict.
+func TestMergeTasks_TagsMergeAsSets(t *testing.T) {
+	base, local, remote := mergeFixture()
+	local.Tags = []string{"work", "q4"}                // removed planning, added q4
+	remote.Tags = []string{"work", "planning", "team"} // added team
+
+	merged, conflicts, err := MergeTasks(base, local, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+	if want := []string{"work", "q4", "team"}; !slices.Equal(merged.Tags, want) {
+		t.Errorf("Tags = %v, want %v", merged.Tags, want)
+	}
+}
+
+// TestMergeTasks_ReconcilesCompletion verifies the merged task never violates
+// the Status/CompletedAt invariant.
+func TestMergeTasks_ReconcilesCompletion(t *testing.T) {
+	base, local, remote := mergeFixture()
+	completed := mergeBase.Add(time.Hour)
+	local.Status = StatusDone
+	local.CompletedAt = &completed
+	local.UpdatedAt = completed
+	remote.Status = StatusToday
+	remote.UpdatedAt = mergeBase.Add(2 * time.Hour)
+
+	merged, _, err := MergeTasks(base, local, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Status != StatusToday || merged.CompletedAt != nil {
+		t.Errorf("expected remote's later reopen to clear completion, got status %q completed %v",
+			merged.Status, merged.CompletedAt)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("merged task invalid: %v", err)
+	}
+}
+
+// TestMergeTasks_NilBase_TreatsDifferencesAsConflicts verifies two-way merges.
+func TestMergeTasks_NilBase_TreatsDifferencesAsConflicts(t *testing.T) {
+	_, local, remote := mergeFixture()
+	local.Notes = "a"
+	remote.Notes = "b"
+	remote.UpdatedAt = mergeBase.Add(time.Minute)
+
+	merged, conflicts, err := MergeTasks(nil, local, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Notes != "b" || len(conflicts) != 1 {
+		t.Errorf("expected remote notes with one conflict, got %q and %v", merged.Notes, conflicts)
+	}
+}
+
+// TestMergeTasks_Errors verifies invalid inputs are rejected.
+func TestMergeTasks_Errors(t *testing.T) {
+	base, local, _ := mergeFixture()
+	other := &Task{ID: NewTaskID()}
+
+	cases := map[string][3]*Task{
+		"nil local":           {base, nil, local},
+		"nil remote":          {base, local, nil},
+		"different IDs":       {base, local, other},
+		"base from elsewhere": {other, local, local},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := MergeTasks(c[0], c[1], c[2])
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("expected *ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestTask_Clone_IsDeep verifies that clones share no mutable state.
+func TestTask_Clone_IsDeep(t *testing.T) {
+	due := mergeBase
+	original := &Task{ID: NewTaskID(), Tags: []string{"a"}, DueDate: &due, ExternalRef: &ExternalRef{System: "s", ID: "1"}}
+
+	clone := original.Clone()
+	clone.Tags[0] = "b"
+	*clone.DueDate = due.Add(time.Hour)
+	clone.ExternalRef.ID = "2"
+
+	if original.Tags[0] != "a" || !original.DueDate.Equal(mergeBase) || original.ExternalRef.ID != "1" {
+		t.Errorf("Clone shares state with original: %+v", original)
+	}
+	if len(original.Diff(original.Clone())) != 0 {
+		t.Error("Clone differs from original")
+	}
+}
+
+// TestTask_TakeField verifies a conflict can be settled field by field in
+// the other side's favour, keeping the completion invariant.
+func TestTask_TakeField(t *testing.T) {
+	_, local, remote := mergeFixture()
+	remote.Title = "remote"
+	remote.Status = StatusDone
+	done := mergeBase.Add(time.Hour)
+	remote.CompletedAt = &done
+
+	tests := []struct {
+		field      string
+		ok         bool
+		wantTitle  string
+		wantStatus TaskStatus
+	}{
+		{field: "title", ok: true, wantTitle: "remote", wantStatus: StatusPool},
+		{field: "status", ok: true, wantTitle: "Plan sprint", wantStatus: StatusDone},
+		{field: "updated_at", ok: false, wantTitle: "Plan sprint", wantStatus: StatusPool},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			task := local.Clone()
+			if ok := task.TakeField(tt.field, remote); ok != tt.ok {
+				t.Fatalf("TakeField(%q) = %v, want %v", tt.field, ok, tt.ok)
+			}
+			if task.Title != tt.wantTitle || task.Status != tt.wantStatus {
+				t.Errorf("after TakeField(%q): title %q, status %s; want %q, %s", tt.field, task.Title, task.Status, tt.wantTitle, tt.wantStatus)
+			}
+			if err := task.Validate(); err != nil {
+				t.Errorf("after TakeField(%q): %v", tt.field, err)
+			}
+		})
+	}
+}
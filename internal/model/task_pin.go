@@ -0,0 +1,30 @@
+package model
+
+import "slices"
+
+// TogglePin pins an unpinned task or unpins a pinned one and returns the new
+// state.
+func (t *Task) TogglePin() bool {
+	t.Pinned = !t.Pinned
+	t.UpdatedAt = Now()
+	return t.Pinned
+}
+
+// SortPinnedFirst moves pinned tasks to the front while keeping the existing
+// order within the pinned and unpinned groups, so any view's ordering can be
+// applied first and pins layered on top.
+func SortPinnedFirst(tasks []*Task) {
+	slices.SortStableFunc(tasks, comparePinned)
+}
+
+// comparePinned orders pinned tasks before unpinned ones.
+func comparePinned(a, b *Task) int {
+	switch {
+	case a.Pinned == b.Pinned:
+		return 0
+	case a.Pinned:
+		return -1
+	default:
+		return 1
+	}
+}
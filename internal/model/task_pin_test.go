@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTask_TogglePin verifies the pin flips and the task is marked updated.
+func TestTask_TogglePin(t *testing.T) {
+	at := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer SetClock(NewFixedClock(at))()
+
+	task := &Task{}
+	if !task.TogglePin() || !task.Pinned {
+		t.Fatal("first toggle should pin")
+	}
+	if !task.UpdatedAt.Equal(at) {
+		t.Errorf("UpdatedAt = %v, want %v", task.UpdatedAt, at)
+	}
+	if task.TogglePin() || task.Pinned {
+		t.Error("second toggle should unpin")
+	}
+}
+
+// TestSortPinnedFirst verifies pinned tasks lead and relative order is kept.
+func TestSortPinnedFirst(t *testing.T) {
+	a := &Task{Title: "a"}
+	b := &Task{Title: "b", Pinned: true}
+	c := &Task{Title: "c"}
+	d := &Task{Title: "d", Pinned: true}
+
+	tasks := []*Task{a, b, c, d}
+	SortPinnedFirst(tasks)
+
+	want := []*Task{b, d, a, c}
+	for i := range want {
+		if tasks[i] != want[i] {
+			t.Errorf("position %d = %q, want %q", i, tasks[i].Title, want[i].Title)
+		}
+	}
+}
+
+// TestSortByUrgency_PinnedFirst verifies pins outrank urgency.
+func TestSortByUrgency_PinnedFirst(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	urgent := &Task{Title: "urgent", Priority: PriorityHigh}
+	pinned := &Task{Title: "pinned", Pinned: true}
+
+	tasks := []*Task{urgent, pinned}
+	SortByUrgency(tasks, now)
+	if tasks[0] != pinned {
+		t.Errorf("first task = %q, want pinned", tasks[0].Title)
+	}
+}
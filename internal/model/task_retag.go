@@ -0,0 +1,48 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ValidateTag checks that tag can be stored: a non-empty word without
+// spaces whose path segments, separated by TagSeparator, are not empty.
+//
+// Returns a *ValidationError for the "tag" field otherwise.
+func ValidateTag(tag string) error {
+	switch {
+	case tag == "":
+		return &ValidationError{Field: "tag", Reason: "is empty"}
+	case strings.ContainsFunc(tag, unicode.IsSpace):
+		return &ValidationError{Field: "tag", Reason: "contains spaces"}
+	case slices.Contains(strings.Split(tag, TagSeparator), ""):
+		return &ValidationError{Field: "tag", Reason: "has an empty " + TagSeparator + " segment"}
+	}
+	return nil
+}
+
+// Retag replaces each of the task's tags within one of from (see
+// TagWithin) by to, keeping the nested part, so retagging "work" as "job"
+// turns "work/backend" into "job/backend". Tags that become the same are
+// kept once, in the place of the first. It reports whether the tags
+// changed, and leaves UpdatedAt to the caller.
+func (t *Task) Retag(from []string, to string) bool {
+	var tags []string
+	for _, tag := range t.Tags {
+		for _, old := range from {
+			if TagWithin(tag, old) {
+				tag = to + tag[len(old):]
+				break
+			}
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if slices.Equal(tags, t.Tags) {
+		return false
+	}
+	t.Tags = tags
+	return true
+}
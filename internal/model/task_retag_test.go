@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestTask_Retag verifies tags within the old ones are renamed with their
+// nested parts, and that duplicates collapse.
+func TestTask_Retag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		from        []string
+		to          string
+		want        []string
+		wantChanged bool
+	}{
+		{name: "rename", tags: []string{"home", "work"}, from: []string{"work"}, to: "job", want: []string{"home", "job"}, wantChanged: true},
+		{name: "nested", tags: []string{"work/backend", "workshop"}, from: []string{"work"}, to: "job", want: []string{"job/backend", "workshop"}, wantChanged: true},
+		{name: "merge collapses", tags: []string{"todo", "errand", "chores"}, from: []string{"errand", "chores"}, to: "todo", want: []string{"todo"}, wantChanged: true},
+		{name: "untouched", tags: []string{"home"}, from: []string{"work"}, to: "job", want: []string{"home"}},
+		{name: "no tags", from: []string{"work"}, to: "job"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Tags: slices.Clone(tt.tags)}
+			if changed := task.Retag(tt.from, tt.to); changed != tt.wantChanged {
+				t.Errorf("Retag() = %v, want %v", changed, tt.wantChanged)
+			}
+			if !slices.Equal(task.Tags, tt.want) {
+				t.Errorf("tags = %q, want %q", task.Tags, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateTag verifies tags must be single words with non-empty
+// segments.
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		wantErr bool
+	}{
+		{tag: "work"},
+		{tag: "work/backend"},
+		{tag: "", wantErr: true},
+		{tag: "deep work", wantErr: true},
+		{tag: "work/", wantErr: true},
+		{tag: "/work", wantErr: true},
+	}
+	for _, tt := range tests {
+		var verr *ValidationError
+		if err := ValidateTag(tt.tag); (err != nil) != tt.wantErr || err != nil && !errors.As(err, &verr) {
+			t.Errorf("ValidateTag(%q) = %v, want error %v", tt.tag, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Snooze hides the task from default views until the given moment. Done
+// tasks cannot be snoozed.
+func (t *Task) Snooze(until time.Time) error {
+	if t.Status == StatusDone {
+		return &TaskError{ID: t.ID, Op: "snooze", Err: ErrInvalidStateTransition}
+	}
+	t.SnoozedUntil = &until
+	t.UpdatedAt = Now()
+	return nil
+}
+
+// Unsnooze makes a snoozed task visible again immediately.
+func (t *Task) Unsnooze() {
+	if t.SnoozedUntil == nil {
+		return
+	}
+	t.SnoozedUntil = nil
+	t.UpdatedAt = Now()
+}
+
+// IsSnoozed reports whether the task is hidden at now. A snooze that has
+// passed no longer hides the task, so tasks reappear without any write.
+func (t *Task) IsSnoozed(now time.Time) bool {
+	return t.SnoozedUntil != nil && now.Before(*t.SnoozedUntil)
+}
+
+// ParseSnoozeUntil resolves a snooze length relative to now. It accepts a
+// count with a unit of m (minutes), h (hours), d (days) or w (weeks), such
+// as "2d" or "90m"; "tomorrow", meaning the start of the next day; or a date
+// in YYYY-MM-DD form, meaning the start of that day in now's location.
+// Day and week snoozes keep now's time of day across DST changes.
+func ParseSnoozeUntil(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "tomorrow" {
+		return StartOfDay(now).AddDate(0, 0, 1), nil
+	}
+	if day, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return day, nil
+	}
+
+	invalid := &ValidationError{
+		Field:  "snoozed_until",
+		Reason: fmt.Sprintf("expected a length like 2d, 3h or 1w, tomorrow, or YYYY-MM-DD, got %q", s),
+	}
+	if len(s) < 2 {
+		return time.Time{}, invalid
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, invalid
+	}
+	switch s[len(s)-1] {
+	case 'm':
+		return now.Add(time.Duration(n) * time.Minute), nil
+	case 'h':
+		return now.Add(time.Duration(n) * time.Hour), nil
+	case 'd':
+		return now.AddDate(0, 0, n), nil
+	case 'w':
+		return now.AddDate(0, 0, 7*n), nil
+	default:
+		return time.Time{}, invalid
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTask_Snooze verifies snoozing hides a task until the time passes.
+func TestTask_Snooze(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	clock := NewFixedClock(now)
+	defer SetClock(clock)()
+
+	task := &Task{Status: StatusPool}
+	if err := task.Snooze(now.Add(48 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if !task.UpdatedAt.Equal(now) {
+		t.Errorf("UpdatedAt = %v, want %v", task.UpdatedAt, now)
+	}
+
+	if !task.IsSnoozed(now) || (TaskFilter{}).Matches(task) {
+		t.Error("snoozed task should be hidden from the default filter")
+	}
+	if !(TaskFilter{IncludeSnoozed: true}).Matches(task) {
+		t.Error("IncludeSnoozed should show the snoozed task")
+	}
+
+	clock.Advance(48 * time.Hour)
+	if task.IsSnoozed(clock.Now()) || !(TaskFilter{}).Matches(task) {
+		t.Error("task should reappear once the snooze passes")
+	}
+
+	task.Unsnooze()
+	if task.SnoozedUntil != nil {
+		t.Error("Unsnooze left SnoozedUntil set")
+	}
+
+	done := &Task{Status: StatusDone}
+	if err := done.Snooze(now); !errors.Is(err, ErrInvalidStateTransition) {
+		t.Errorf("Snooze() on done task = %v, want ErrInvalidStateTransition", err)
+	}
+}
+
+// TestParseSnoozeUntil verifies the accepted snooze lengths.
+func TestParseSnoozeUntil(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "2d", want: time.Date(2025, 11, 14, 15, 30, 0, 0, time.UTC)},
+		{input: "1w", want: time.Date(2025, 11, 19, 15, 30, 0, 0, time.UTC)},
+		{input: "3h", want: time.Date(2025, 11, 12, 18, 30, 0, 0, time.UTC)},
+		{input: "90m", want: time.Date(2025, 11, 12, 17, 0, 0, 0, time.UTC)},
+		{input: "Tomorrow", want: time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)},
+		{input: "2025-12-01", want: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "0d", wantErr: true},
+		{input: "2y", wantErr: true},
+		{input: "d", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSnoozeUntil(tt.input, now)
+			if tt.wantErr {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("ParseSnoozeUntil(%q) error = %v, want ValidationError", tt.input, err)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("ParseSnoozeUntil(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package model
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type TaskStatus string
 
 const (
@@ -20,3 +25,38 @@ func (s TaskStatus) Valid() bool {
 func (s TaskStatus) String() string {
 	return string(s)
 }
+
+// lenientStatus controls whether UnmarshalJSON maps unknown statuses to
+// StatusPool instead of failing.
+var lenientStatus = false
+
+// SetLenientStatusDecoding switches JSON decoding of TaskStatus between strict
+// (the default) and lenient mode, returning a function that restores the
+// previous mode. Lenient mode is meant for recovery tools salvaging damaged
+// journals. Like SetClock, it is not safe for concurrent use.
+func SetLenientStatusDecoding(lenient bool) (restore func()) {
+	prev := lenientStatus
+	lenientStatus = lenient
+	return func() { lenientStatus = prev }
+}
+
+// UnmarshalJSON decodes a status string, rejecting values other than pool,
+// today and done with an error wrapping ErrInvalidStatus so that corrupted or
+// hand-edited journals fail loudly. In lenient mode unknown values decode as
+// StatusPool.
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, data)
+	}
+
+	status := TaskStatus(raw)
+	if !status.Valid() {
+		if !lenientStatus {
+			return fmt.Errorf("%w: %q", ErrInvalidStatus, raw)
+		}
+		status = StatusPool
+	}
+	*s = status
+	return nil
+}
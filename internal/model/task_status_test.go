@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -312,3 +313,63 @@ func TestTaskStatus_Constants_HaveCorrectValues(t *testing.T) {
 		})
 	}
 }
+
+// TestTaskStatus_UnmarshalJSON_RejectsUnknown verifies strict decoding fails
+// with ErrInvalidStatus for anything but the three known statuses.
+func TestTaskStatus_UnmarshalJSON_RejectsUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "unknown string", json: `"archived"`},
+		{name: "wrong case", json: `"Today"`},
+		{name: "empty string", json: `""`},
+		{name: "number", json: `3`},
+		{name: "null", json: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := StatusToday
+			err := json.Unmarshal([]byte(tt.json), &status)
+			if !errors.Is(err, ErrInvalidStatus) {
+				t.Fatalf("expected ErrInvalidStatus, got %v", err)
+			}
+			if status != StatusToday {
+				t.Errorf("status modified on error: %q", status)
+			}
+		})
+	}
+}
+
+// TestTaskStatus_UnmarshalJSON_InTask verifies a corrupted journal record
+// fails to decode.
+func TestTaskStatus_UnmarshalJSON_InTask(t *testing.T) {
+	var task Task
+	err := json.Unmarshal([]byte(`{"title":"x","status":"doing"}`), &task)
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+// TestTaskStatus_UnmarshalJSON_LenientMode verifies unknown statuses map to
+// pool in lenient mode while valid ones are preserved.
+func TestTaskStatus_UnmarshalJSON_LenientMode(t *testing.T) {
+	restore := SetLenientStatusDecoding(true)
+	defer restore()
+
+	var unknown, known TaskStatus
+	if err := json.Unmarshal([]byte(`"archived"`), &unknown); err != nil {
+		t.Fatalf("unexpected error in lenient mode: %v", err)
+	}
+	if unknown != StatusPool {
+		t.Errorf("unknown status decoded as %q, want %q", unknown, StatusPool)
+	}
+	if err := json.Unmarshal([]byte(`"done"`), &known); err != nil || known != StatusDone {
+		t.Errorf("known status decoded as %q (err %v), want %q", known, err, StatusDone)
+	}
+
+	if err := json.Unmarshal([]byte(`42`), &unknown); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("non-string status should still fail in lenient mode, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeTitle prepares user input for storage as a title: it applies NFC
+// normalization so visually identical titles compare equal, turns line
+// breaks into spaces, drops other control characters except tabs (which
+// could inject terminal escape sequences), and trims surrounding whitespace.
+func NormalizeTitle(title string) string {
+	title = strings.ToValidUTF8(title, "")
+	title = norm.NFC.String(title)
+	title = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r':
+			return ' '
+		case r != '\t' && unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, title)
+	return strings.TrimSpace(title)
+}
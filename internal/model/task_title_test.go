@@ -0,0 +1,71 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNormalizeTitle verifies NFC normalization and control-character
+// handling.
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"decomposed accent composed", "Café run", "Café run"},
+		{"escape sequence stripped", "Pay \x1b[31mrent\x1b[0m", "Pay [31mrent[0m"},
+		{"newlines become spaces", "Line one\nline two\r\n", "Line one line two"},
+		{"tab kept", "a\tb", "a\tb"},
+		{"C1 control stripped", "bell\u0085ring", "bellring"},
+		{"invalid UTF-8 dropped", "ok\xffay", "okay"},
+		{"CJK untouched", "  買い物に行く  ", "買い物に行く"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTitle(tt.input); got != tt.want {
+				t.Errorf("NormalizeTitle(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewTask_NormalizesTitle verifies NewTask stores the normalized title
+// and rejects titles that are only control characters.
+func TestNewTask_NormalizesTitle(t *testing.T) {
+	task, err := NewTask("Café\x07", nil)
+	if err != nil {
+		t.Fatalf("NewTask() error = %v", err)
+	}
+	if task.Title != "Café" {
+		t.Errorf("Title = %q, want %q", task.Title, "Café")
+	}
+
+	if _, err := NewTask("\x1b\x07\n", nil); err != ErrEmptyTitle {
+		t.Errorf("control-only title error = %v, want ErrEmptyTitle", err)
+	}
+}
+
+// TestNewTask_TitleLengthLimit verifies the configurable maximum counts
+// characters rather than bytes and can be disabled.
+func TestNewTask_TitleLengthLimit(t *testing.T) {
+	defer SetLimits(Limits{TitleLength: 5})()
+
+	if _, err := NewTask("ééééé", nil); err != nil {
+		t.Errorf("5-character title rejected: %v", err)
+	}
+
+	_, err := NewTask("abcdef", nil)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "title" || verr.Reason != "exceeds 5 characters" {
+		t.Errorf("NewTask() error = %v, want title ValidationError", err)
+	}
+
+	restore := SetLimits(Limits{})
+	defer restore()
+	if _, err := NewTask(strings.Repeat("x", 10*DefaultMaxTitleLength), nil); err != nil {
+		t.Errorf("unlimited title rejected: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+// MoveToToday commits the task to today's list, clearing any snooze so it
+// shows there at once. Done tasks cannot be moved.
+func (t *Task) MoveToToday() error {
+	if t.Status == StatusDone {
+		return &TaskError{ID: t.ID, Op: "move to today", Err: ErrInvalidStateTransition}
+	}
+	t.Status = StatusToday
+	t.SnoozedUntil = nil
+	t.UpdatedAt = Now()
+	return nil
+}
+
+// Complete marks the task done now. A task already done cannot be
+// completed again, which would move its completion time.
+func (t *Task) Complete() error {
+	if t.Status == StatusDone {
+		return &TaskError{ID: t.ID, Op: "complete", Err: ErrInvalidStateTransition}
+	}
+	now := Now()
+	t.Status = StatusDone
+	t.CompletedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Reopen returns a done task to the pool, as it turned out not to be done,
+// and forgets when it was completed. Only done tasks can be reopened.
+func (t *Task) Reopen() error {
+	if t.Status != StatusDone {
+		return &TaskError{ID: t.ID, Op: "reopen", Err: ErrInvalidStateTransition}
+	}
+	t.Status = StatusPool
+	t.CompletedAt = nil
+	t.UpdatedAt = Now()
+	return nil
+}
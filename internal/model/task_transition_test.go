@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTask_Transitions verifies moving to today, completing and reopening
+// set the status and times, and refuse tasks they do not apply to.
+func TestTask_Transitions(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer SetClock(NewFixedClock(now))()
+	snoozed := now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		from       TaskStatus
+		apply      func(*Task) error
+		wantStatus TaskStatus
+		wantErr    error
+	}{
+		{name: "today from pool", from: StatusPool, apply: (*Task).MoveToToday, wantStatus: StatusToday},
+		{name: "today from done", from: StatusDone, apply: (*Task).MoveToToday, wantStatus: StatusDone, wantErr: ErrInvalidStateTransition},
+		{name: "complete from today", from: StatusToday, apply: (*Task).Complete, wantStatus: StatusDone},
+		{name: "complete from done", from: StatusDone, apply: (*Task).Complete, wantStatus: StatusDone, wantErr: ErrInvalidStateTransition},
+		{name: "reopen from done", from: StatusDone, apply: (*Task).Reopen, wantStatus: StatusPool},
+		{name: "reopen from today", from: StatusToday, apply: (*Task).Reopen, wantStatus: StatusToday, wantErr: ErrInvalidStateTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{ID: NewTaskID(), Status: tt.from, SnoozedUntil: &snoozed}
+			err := tt.apply(task)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if task.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", task.Status, tt.wantStatus)
+			}
+			if err != nil {
+				return
+			}
+			if !task.UpdatedAt.Equal(now) {
+				t.Errorf("UpdatedAt = %v, want %v", task.UpdatedAt, now)
+			}
+			if done := task.Status == StatusDone; done != (task.CompletedAt != nil) {
+				t.Errorf("CompletedAt = %v with status %s", task.CompletedAt, task.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package model
+
+import "strings"
+
+// Validate checks every documented Task invariant and reports all violations
+// at once rather than stopping at the first.
+//
+// It is intended for data that did not come through NewTask, such as tasks
+// decoded from a journal file or produced by an importer.
+//
+// Returns nil when the task is valid; otherwise ValidationErrors holding one
+// *ValidationError per violated invariant.
+func (t *Task) Validate() error {
+	var errs ValidationErrors
+
+	if t.ID.IsEmpty() {
+		errs.Append("id", "must not be empty")
+	}
+	if t.CreatedAt.IsZero() {
+		errs.Append("created_at", "must be set")
+	}
+	if !t.Status.Valid() {
+		errs.Append("status", "must be one of pool, today, done")
+	}
+	if strings.TrimSpace(t.Title) == "" {
+		errs.Append("title", "must not be empty")
+	} else if reason, tooLong := titleTooLong(t.Title); tooLong {
+		errs.Append("title", reason)
+	}
+	checkSizes(t, &errs)
+	if t.DeferredCount < 0 {
+		errs.Append("deferred_count", "must not be negative")
+	}
+	if !t.Priority.Valid() {
+		errs.Append("priority", "must be empty or one of low, medium, high")
+	}
+	if !t.Energy.Valid() {
+		errs.Append("energy", "must be empty or one of low, medium, high")
+	}
+	if t.Status == StatusDone && t.CompletedAt == nil {
+		errs.Append("completed_at", "must be set when status is done")
+	}
+	if t.Status != StatusDone && t.CompletedAt != nil {
+		errs.Append("completed_at", "must be empty unless status is done")
+	}
+	if t.NotesFile != "" && t.NotesFile != NotesFileName(t.ID) {
+		errs.Append("notes_file", "must be empty or "+NotesFileName(t.ID))
+	}
+	if t.Recurrence != nil {
+		if reason := t.Recurrence.validate(); reason != "" {
+			errs.Append("recurrence", reason)
+		}
+	}
+
+	return errs.Err()
+}
+
+// NotesFileName returns the name of the file the task with the given ID
+// keeps long notes in, the only name Task.NotesFile may hold.
+func NotesFileName(id TaskID) string {
+	return id.String() + ".md"
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validTask returns a task satisfying every invariant, for tests to mutate.
+func validTask() *Task {
+	return &Task{
+		ID:        NewTaskID(),
+		CreatedAt: time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC),
+		Title:     "Write tests",
+		Status:    StatusPool,
+	}
+}
+
+// TestTask_Validate_ValidTask_ReturnsNil verifies that tasks satisfying all
+// invariants pass validation, including a completed task.
+func TestTask_Validate_ValidTask_ReturnsNil(t *testing.T) {
+	task := validTask()
+	if err := task.Validate(); err != nil {
+		t.Errorf("expected nil error for valid task, got %v", err)
+	}
+
+	done := validTask()
+	completed := done.CreatedAt.Add(time.Hour)
+	done.Status = StatusDone
+	done.CompletedAt = &completed
+	if err := done.Validate(); err != nil {
+		t.Errorf("expected nil error for valid done task, got %v", err)
+	}
+
+	created, err := NewTask("From factory", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := created.Validate(); err != nil {
+		t.Errorf("expected task from NewTask to be valid, got %v", err)
+	}
+}
+
+// TestTask_Validate_SingleViolation verifies that each invariant is checked
+// and reported against the right field.
+func TestTask_Validate_SingleViolation(t *testing.T) {
+	completed := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		mutate func(*Task)
+		field  string
+	}{
+		{name: "empty ID", mutate: func(t *Task) { t.ID = TaskID{} }, field: "id"},
+		{name: "zero CreatedAt", mutate: func(t *Task) { t.CreatedAt = time.Time{} }, field: "created_at"},
+		{name: "invalid status", mutate: func(t *Task) { t.Status = "archived" }, field: "status"},
+		{name: "empty title", mutate: func(t *Task) { t.Title = "" }, field: "title"},
+		{name: "whitespace title", mutate: func(t *Task) { t.Title = " \t " }, field: "title"},
+		{name: "overlong title", mutate: func(t *Task) { t.Title = strings.Repeat("x", DefaultMaxTitleLength+1) }, field: "title"},
+		{name: "unknown priority", mutate: func(t *Task) { t.Priority = "urgent" }, field: "priority"},
+		{name: "unknown energy", mutate: func(t *Task) { t.Energy = "sleepy" }, field: "energy"},
+		{name: "negative deferred count", mutate: func(t *Task) { t.DeferredCount = -1 }, field: "deferred_count"},
+		{name: "done without CompletedAt", mutate: func(t *Task) { t.Status = StatusDone }, field: "completed_at"},
+		{name: "CompletedAt while not done", mutate: func(t *Task) { t.CompletedAt = &completed }, field: "completed_at"},
+		{name: "foreign notes file", mutate: func(t *Task) { t.NotesFile = "../../x" }, field: "notes_file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := validTask()
+			tt.mutate(task)
+
+			err := task.Validate()
+			if err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ValidationError, got %T", err)
+			}
+			if verr.Field != tt.field {
+				t.Errorf("Field = %q, want %q", verr.Field, tt.field)
+			}
+		})
+	}
+}
+
+// TestTask_Validate_ReportsAllViolations verifies that validation does not
+// stop at the first failure.
+func TestTask_Validate_ReportsAllViolations(t *testing.T) {
+	task := &Task{Status: "bogus", DeferredCount: -2}
+
+	err := task.Validate()
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+
+	var all ValidationErrors
+	if !errors.As(err, &all) {
+		t.Fatalf("expected ValidationErrors, got %T", err)
+	}
+	fields := make(map[string]bool)
+	for _, verr := range all {
+		fields[verr.Field] = true
+	}
+	for _, want := range []string{"id", "created_at", "status", "title", "deferred_count"} {
+		if !fields[want] {
+			t.Errorf("expected violation for %q, got %v", want, fields)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// UrgencyWeights scales each factor that contributes to a task's urgency.
+// Every factor is normalized to the range 0..1 (tags contribute their weight
+// directly), so a weight is the most that factor can add.
+type UrgencyWeights struct {
+	// Due weights proximity of the due date, saturating one week overdue.
+	Due float64
+	// Priority weights the task's priority; medium and low count 65% and
+	// 30% of high.
+	Priority float64
+	// Age weights time since creation, saturating after UrgencyAgeHorizon.
+	Age float64
+	// Deferred weights how often the task was deferred, saturating at
+	// UrgencyDeferredCap deferrals.
+	Deferred float64
+	// Tags adds a fixed weight for tasks carrying a tag or one of its
+	// descendants (see TagWithin). Negative weights push tasks down.
+	Tags map[string]float64
+}
+
+const (
+	// UrgencyAgeHorizon is the age at which the age factor reaches 1.
+	UrgencyAgeHorizon = 365 * 24 * time.Hour
+
+	// UrgencyDeferredCap is the deferral count at which the deferred factor
+	// reaches 1.
+	UrgencyDeferredCap = 5
+)
+
+// DefaultUrgencyWeights returns weights modelled on taskwarrior's defaults:
+// due dates dominate, followed by priority.
+func DefaultUrgencyWeights() UrgencyWeights {
+	return UrgencyWeights{Due: 12, Priority: 6, Age: 2, Deferred: 3}
+}
+
+var urgencyWeights = DefaultUrgencyWeights()
+
+// SetUrgencyWeights configures the weights used by Task.Urgency, returning a
+// function that restores the previous weights. Like SetClock, it is not safe
+// for concurrent use.
+func SetUrgencyWeights(w UrgencyWeights) (restore func()) {
+	prev := urgencyWeights
+	urgencyWeights = w
+	return func() { urgencyWeights = prev }
+}
+
+// priorityFactor maps priorities onto 0..1.
+var priorityFactor = map[Priority]float64{
+	PriorityHigh:   1,
+	PriorityMedium: 0.65,
+	PriorityLow:    0.3,
+}
+
+// Urgency scores how pressing the task is at now using the configured
+// weights; higher is more urgent. Completed tasks score 0.
+func (t *Task) Urgency(now time.Time) float64 {
+	return t.UrgencyWith(urgencyWeights, now)
+}
+
+// UrgencyWith scores the task using w instead of the configured weights.
+func (t *Task) UrgencyWith(w UrgencyWeights, now time.Time) float64 {
+	if t.Status == StatusDone {
+		return 0
+	}
+
+	score := w.Due*t.dueFactor(now) + w.Priority*priorityFactor[t.Priority]
+
+	if !t.CreatedAt.IsZero() && now.After(t.CreatedAt) {
+		score += w.Age * math.Min(float64(now.Sub(t.CreatedAt))/float64(UrgencyAgeHorizon), 1)
+	}
+	if t.DeferredCount > 0 {
+		score += w.Deferred * math.Min(float64(t.DeferredCount)/UrgencyDeferredCap, 1)
+	}
+	for tag, weight := range w.Tags {
+		if slices.ContainsFunc(t.Tags, func(have string) bool { return TagWithin(have, tag) }) {
+			score += weight
+		}
+	}
+	return score
+}
+
+// dueFactor rises linearly from 0.2 two weeks before the due day to 1.0 a
+// week after it, by calendar day in now's location. Tasks without a due date
+// score 0.
+func (t *Task) dueFactor(now time.Time) float64 {
+	if t.DueDate == nil {
+		return 0
+	}
+	due := dayIn(*t.DueDate, now.Location())
+	overdue := math.Round(StartOfDay(now).Sub(due).Hours() / 24)
+	switch {
+	case overdue >= 7:
+		return 1
+	case overdue <= -14:
+		return 0.2
+	default:
+		return (overdue+14)*0.8/21 + 0.2
+	}
+}
+
+// SortByUrgency orders tasks from most to least urgent at now, after the
+// pinned tasks (see SortPinnedFirst). Ties keep the older task first so the
+// order is stable across runs.
+func SortByUrgency(tasks []*Task, now time.Time) {
+	scores := make(map[*Task]float64, len(tasks))
+	for _, t := range tasks {
+		scores[t] = t.Urgency(now)
+	}
+	slices.SortStableFunc(tasks, func(a, b *Task) int {
+		if c := comparePinned(a, b); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
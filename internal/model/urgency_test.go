@@ -0,0 +1,69 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestTask_UrgencyWith verifies each factor's contribution in isolation.
+func TestTask_UrgencyWith(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	days := func(n int) *time.Time {
+		d := now.AddDate(0, 0, n)
+		return &d
+	}
+	w := UrgencyWeights{Due: 10, Priority: 10, Age: 10, Deferred: 10, Tags: map[string]float64{"work": 2, "someday": -5}}
+
+	tests := []struct {
+		name string
+		task Task
+		want float64
+	}{
+		{"nothing set", Task{}, 0},
+		{"done tasks score zero", Task{Status: StatusDone, Priority: PriorityHigh}, 0},
+		{"due a week overdue saturates", Task{DueDate: days(-10)}, 10},
+		{"due today", Task{DueDate: days(0)}, 10 * (14*0.8/21 + 0.2)},
+		{"due far away floors", Task{DueDate: days(60)}, 2},
+		{"high priority", Task{Priority: PriorityHigh}, 10},
+		{"low priority", Task{Priority: PriorityLow}, 3},
+		{"half a year old", Task{CreatedAt: now.Add(-UrgencyAgeHorizon / 2)}, 5},
+		{"deferral cap", Task{DeferredCount: 9}, 10},
+		{"tag descendant", Task{Tags: []string{"work/projectA"}}, 2},
+		{"negative tag", Task{Tags: []string{"someday", "work"}}, -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.task.UrgencyWith(w, now)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("UrgencyWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSortByUrgency verifies the configured weights drive the order and ties
+// fall back to creation time.
+func TestSortByUrgency(t *testing.T) {
+	now := time.Date(2025, 11, 12, 15, 0, 0, 0, time.UTC)
+	due := now.AddDate(0, 0, 1)
+	old := now.AddDate(0, 0, -3)
+	newer := now.AddDate(0, 0, -2)
+
+	plainOld := &Task{Title: "plain old", CreatedAt: old}
+	plainNew := &Task{Title: "plain new", CreatedAt: newer}
+	important := &Task{Title: "important", CreatedAt: newer, Priority: PriorityHigh}
+	dueSoon := &Task{Title: "due soon", CreatedAt: newer, DueDate: &due}
+
+	tasks := []*Task{plainNew, important, plainOld, dueSoon}
+	defer SetUrgencyWeights(UrgencyWeights{Due: 12, Priority: 6})()
+	SortByUrgency(tasks, now)
+
+	want := []*Task{dueSoon, important, plainOld, plainNew}
+	for i := range want {
+		if tasks[i] != want[i] {
+			t.Errorf("position %d = %q, want %q", i, tasks[i].Title, want[i].Title)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Package notestore keeps long task notes as individual Markdown files next
+// to the journal, so they can be edited and searched with external tools.
+//
+// A task whose notes are stored externally carries the file name in
+// Task.NotesFile and an empty Notes field in the persisted record. Loading
+// the journal resolves the reference back into Notes.
+package notestore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"togo/internal/atomicfile"
+	"togo/internal/model"
+)
+
+// ErrForeignFile is returned for a task whose notes file reference names
+// a file other than the one the store keeps its notes in, such as from a
+// hand edit.
+var ErrForeignFile = errors.New("notes file is not the task's own")
+
+// DirName is the subdirectory of the data directory holding note files.
+const DirName = "notes"
+
+// fileExt is the extension of note files.
+const fileExt = ".md"
+
+// Store reads and writes external note files.
+type Store struct {
+	dir       string
+	threshold int
+}
+
+// New returns a Store keeping note files in dir. Notes at least threshold
+// bytes long are split into files; a non-positive threshold disables
+// splitting, though existing references are still resolved.
+func New(dir string, threshold int) *Store {
+	return &Store{dir: dir, threshold: threshold}
+}
+
+// Dir returns the directory holding note files.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Externalize prepares t for persistence. Long notes are written to a file
+// and replaced by a reference in the returned copy; notes that have shrunk
+// below the threshold are inlined again and their file removed. t itself is
+// never modified.
+func (s *Store) Externalize(t *model.Task) (*model.Task, error) {
+	out := t.Clone()
+
+	if s.threshold > 0 && len(t.Notes) >= s.threshold {
+		name := fileName(t.ID)
+		if err := atomicfile.WriteFile(filepath.Join(s.dir, name), []byte(t.Notes)); err != nil {
+			return nil, fmt.Errorf("writing notes for task %s: %w", t.ID.Short(), err)
+		}
+		out.NotesFile = name
+		out.Notes = ""
+		return out, nil
+	}
+
+	if t.NotesFile != "" {
+		if t.NotesFile != fileName(t.ID) {
+			return nil, fmt.Errorf("removing notes for task %s: %q: %w", t.ID.Short(), t.NotesFile, ErrForeignFile)
+		}
+		err := os.Remove(filepath.Join(s.dir, t.NotesFile))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("removing notes for task %s: %w", t.ID.Short(), err)
+		}
+		out.NotesFile = ""
+	}
+	return out, nil
+}
+
+// Resolve loads externally stored notes into t.Notes. Tasks without a
+// reference are left unchanged. A missing file yields an error wrapping
+// os.ErrNotExist, and a reference to a file other than the task's own one
+// wrapping ErrForeignFile.
+func (s *Store) Resolve(t *model.Task) error {
+	if t.NotesFile == "" {
+		return nil
+	}
+	if t.NotesFile != fileName(t.ID) {
+		return fmt.Errorf("reading notes for task %s: %q: %w", t.ID.Short(), t.NotesFile, ErrForeignFile)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, t.NotesFile))
+	if err != nil {
+		return fmt.Errorf("reading notes for task %s: %w", t.ID.Short(), err)
+	}
+	t.Notes = string(data)
+	return nil
+}
+
+// Report lists inconsistencies between note files and tasks.
+type Report struct {
+	// Orphans are note files no task references. Only files named as the
+	// store names them count; other files in the directory are the user's.
+	Orphans []string
+	// Missing are tasks referencing a note file that does not exist.
+	Missing []model.TaskID
+}
+
+// OK reports whether no problems were found.
+func (r Report) OK() bool {
+	return len(r.Orphans) == 0 && len(r.Missing) == 0
+}
+
+// Check compares the note files on disk with the references held by tasks.
+// References to files other than the task's own are left to
+// Task.Validate to report.
+func (s *Store) Check(tasks []*model.Task) (Report, error) {
+	var report Report
+
+	referenced := make(map[string]bool)
+	for _, t := range tasks {
+		if t.NotesFile == "" || t.NotesFile != fileName(t.ID) {
+			continue
+		}
+		referenced[t.NotesFile] = true
+		if _, err := os.Stat(filepath.Join(s.dir, t.NotesFile)); errors.Is(err, os.ErrNotExist) {
+			report.Missing = append(report.Missing, t.ID)
+		} else if err != nil {
+			return Report{}, err
+		}
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Report{}, err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !isNoteFile(e.Name()) {
+			continue
+		}
+		if !referenced[e.Name()] {
+			report.Orphans = append(report.Orphans, e.Name())
+		}
+	}
+	sort.Strings(report.Orphans)
+	return report, nil
+}
+
+// RemoveOrphans deletes the orphaned files listed in report.
+func (s *Store) RemoveOrphans(report Report) error {
+	for _, name := range report.Orphans {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileName returns the note file name for a task.
+func fileName(id model.TaskID) string {
+	return model.NotesFileName(id)
+}
+
+// isNoteFile reports whether name is a note file name, one fileName
+// returns.
+func isNoteFile(name string) bool {
+	id, err := model.ParseTaskID(strings.TrimSuffix(name, fileExt))
+	return err == nil && fileName(id) == name
+}
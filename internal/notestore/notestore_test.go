@@ -0,0 +1,187 @@
+package notestore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"togo/internal/model"
+)
+
+func newTask(t *testing.T, notes string) *model.Task {
+	t.Helper()
+	task, err := model.NewTask("Write design doc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Notes = notes
+	return task
+}
+
+// TestStore_Externalize_SplitsLongNotes verifies long notes move to a file and
+// the persisted copy only carries the reference.
+func TestStore_Externalize_SplitsLongNotes(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir, 10)
+	task := newTask(t, "# Design\n\nA long enough body.")
+
+	out, err := store.Externalize(task)
+	if err != nil {
+		t.Fatalf("Externalize() error = %v", err)
+	}
+	if out.Notes != "" || out.NotesFile != task.ID.String()+".md" {
+		t.Errorf("expected reference only, got Notes=%q NotesFile=%q", out.Notes, out.NotesFile)
+	}
+	if task.Notes == "" || task.NotesFile != "" {
+		t.Error("Externalize modified the original task")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, out.NotesFile))
+	if err != nil || string(data) != task.Notes {
+		t.Errorf("note file content = %q (err %v), want %q", data, err, task.Notes)
+	}
+
+	if err := store.Resolve(out); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if out.Notes != task.Notes {
+		t.Errorf("resolved Notes = %q, want %q", out.Notes, task.Notes)
+	}
+}
+
+// TestStore_Externalize_ShortNotesStayInline verifies notes under the threshold
+// are untouched and previously split notes are inlined again.
+func TestStore_Externalize_ShortNotesStayInline(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir, 100)
+	task := newTask(t, strings.Repeat("x", 200))
+
+	split, err := store.Externalize(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The user trims the notes below the threshold.
+	task.NotesFile = split.NotesFile
+	task.Notes = "short now"
+	out, err := store.Externalize(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Notes != "short now" || out.NotesFile != "" {
+		t.Errorf("expected inline notes, got Notes=%q NotesFile=%q", out.Notes, out.NotesFile)
+	}
+	if _, err := os.Stat(filepath.Join(dir, split.NotesFile)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected stale note file to be removed, stat err = %v", err)
+	}
+}
+
+// TestStore_Externalize_Disabled verifies a zero threshold never splits.
+func TestStore_Externalize_Disabled(t *testing.T) {
+	store := New(t.TempDir(), 0)
+	task := newTask(t, strings.Repeat("x", 10000))
+
+	out, err := store.Externalize(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NotesFile != "" || out.Notes != task.Notes {
+		t.Error("expected notes to stay inline when splitting is disabled")
+	}
+}
+
+// TestStore_Resolve_MissingFile verifies a dangling reference is reported.
+func TestStore_Resolve_MissingFile(t *testing.T) {
+	store := New(t.TempDir(), 10)
+	task := newTask(t, "")
+	task.NotesFile = fileName(task.ID)
+
+	if err := store.Resolve(task); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+// TestStore_ForeignFile verifies a reference to a file other than the
+// task's own is neither read nor removed.
+func TestStore_ForeignFile(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, DirName)
+	outside := filepath.Join(root, "secrets.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := New(dir, 100)
+	task := newTask(t, "")
+	task.NotesFile = filepath.Join("..", "secrets.txt")
+
+	if err := store.Resolve(task); !errors.Is(err, ErrForeignFile) || task.Notes != "" {
+		t.Errorf("Resolve() = %v with notes %q, want ErrForeignFile and no notes", err, task.Notes)
+	}
+	task.Notes = "short"
+	if _, err := store.Externalize(task); !errors.Is(err, ErrForeignFile) {
+		t.Errorf("Externalize() error = %v, want ErrForeignFile", err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the notes directory: %v, want it left alone", err)
+	}
+}
+
+// TestStore_Check verifies detection and cleanup of orphaned and missing
+// files, leaving files the store did not name alone.
+func TestStore_Check(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir, 5)
+
+	kept := newTask(t, "long enough notes")
+	persisted, err := store.Externalize(kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dangling := newTask(t, "")
+	dangling.NotesFile = fileName(dangling.ID)
+	orphan := fileName(newTask(t, "").ID)
+	for _, name := range []string{orphan, "ideas.md", "README.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := store.Check([]*model.Task{persisted, dangling})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if report.OK() {
+		t.Fatal("expected problems to be reported")
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0] != orphan {
+		t.Errorf("Orphans = %v, want [%s]", report.Orphans, orphan)
+	}
+	if len(report.Missing) != 1 || !report.Missing[0].Equals(dangling.ID) {
+		t.Errorf("Missing = %v, want [%s]", report.Missing, dangling.ID)
+	}
+
+	if err := store.RemoveOrphans(report); err != nil {
+		t.Fatal(err)
+	}
+	after, err := store.Check([]*model.Task{persisted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.OK() {
+		t.Errorf("expected clean report after removing orphans, got %+v", after)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ideas.md")); err != nil {
+		t.Errorf("user's Markdown file removed: %v", err)
+	}
+}
+
+// TestStore_Check_NoDirectory verifies a missing notes directory is not an error.
+func TestStore_Check_NoDirectory(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "absent"), 5)
+
+	report, err := store.Check(nil)
+	if err != nil || !report.OK() {
+		t.Errorf("Check() = %+v, %v; want clean report", report, err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package paths locates togo's directories following each platform's
+// conventions: the XDG base directory specification on Linux and other
+// Unix systems, ~/Library on macOS and %AppData% on Windows.
+//
+// The XDG_CONFIG_HOME, XDG_DATA_HOME, XDG_CACHE_HOME and XDG_STATE_HOME
+// variables override the defaults on every platform, so users of XDG tools
+// on macOS or Windows can keep everything in one place.
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// app names the subdirectory togo uses inside each base directory.
+const app = "togo"
+
+// Kind identifies one of togo's directories.
+type Kind int
+
+const (
+	// Config holds settings and saved filters.
+	Config Kind = iota
+	// Data holds the journal, its backups and note files.
+	Data
+	// Cache holds files that can be rebuilt, such as search indexes.
+	Cache
+	// State holds small records of past sessions, such as the last version
+	// whose changes were shown.
+	State
+)
+
+// xdgVar is the environment variable overriding each kind's base directory.
+var xdgVar = map[Kind]string{
+	Config: "XDG_CONFIG_HOME",
+	Data:   "XDG_DATA_HOME",
+	Cache:  "XDG_CACHE_HOME",
+	State:  "XDG_STATE_HOME",
+}
+
+// Dir returns togo's directory of the given kind, creating it (private to
+// the user) if it does not exist yet.
+func Dir(kind Kind) (string, error) {
+	home, _ := os.UserHomeDir()
+	dir, err := resolve(kind, runtime.GOOS, os.Getenv, home)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// resolve computes the directory of the given kind on goos without
+// touching the file system.
+func resolve(kind Kind, goos string, getenv func(string) string, home string) (string, error) {
+	if base := getenv(xdgVar[kind]); filepath.IsAbs(base) {
+		return filepath.Join(base, app), nil
+	}
+
+	switch goos {
+	case "windows":
+		// Settings and data roam with the user's profile; caches and state
+		// are specific to the machine.
+		v := "APPDATA"
+		if kind == Cache || kind == State {
+			v = "LOCALAPPDATA"
+		}
+		base := getenv(v)
+		if base == "" {
+			return "", errors.New("paths: %" + v + "% is not set")
+		}
+		if kind == Cache {
+			return filepath.Join(base, app, "cache"), nil
+		}
+		if kind == State {
+			return filepath.Join(base, app, "state"), nil
+		}
+		return filepath.Join(base, app), nil
+	case "darwin", "ios":
+		if home == "" {
+			return "", errors.New("paths: home directory unknown")
+		}
+		switch kind {
+		case Cache:
+			return filepath.Join(home, "Library", "Caches", app), nil
+		case State:
+			return filepath.Join(home, "Library", "Application Support", app, "state"), nil
+		default:
+			return filepath.Join(home, "Library", "Application Support", app), nil
+		}
+	default:
+		if home == "" {
+			return "", errors.New("paths: home directory unknown")
+		}
+		rel := map[Kind]string{
+			Config: ".config",
+			Data:   filepath.Join(".local", "share"),
+			Cache:  ".cache",
+			State:  filepath.Join(".local", "state"),
+		}[kind]
+		return filepath.Join(home, rel, app), nil
+	}
+}
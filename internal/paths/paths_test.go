@@ -0,0 +1,72 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestResolve verifies each platform's conventions and the XDG overrides.
+func TestResolve(t *testing.T) {
+	home := filepath.FromSlash("/home/me")
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	none := env(nil)
+	windows := env(map[string]string{
+		"APPDATA":      filepath.FromSlash("/users/me/AppData/Roaming"),
+		"LOCALAPPDATA": filepath.FromSlash("/users/me/AppData/Local"),
+	})
+
+	tests := []struct {
+		name   string
+		kind   Kind
+		goos   string
+		getenv func(string) string
+		home   string
+		want   string
+	}{
+		{"linux config", Config, "linux", none, home, "/home/me/.config/togo"},
+		{"linux data", Data, "linux", none, home, "/home/me/.local/share/togo"},
+		{"linux cache", Cache, "linux", none, home, "/home/me/.cache/togo"},
+		{"linux state", State, "linux", none, home, "/home/me/.local/state/togo"},
+		{"xdg override", Data, "linux", env(map[string]string{"XDG_DATA_HOME": filepath.FromSlash("/srv/data")}), home, "/srv/data/togo"},
+		{"relative xdg ignored", Data, "linux", env(map[string]string{"XDG_DATA_HOME": "data"}), home, "/home/me/.local/share/togo"},
+		{"xdg honored on macOS", Config, "darwin", env(map[string]string{"XDG_CONFIG_HOME": filepath.FromSlash("/home/me/.config")}), home, "/home/me/.config/togo"},
+		{"macOS data", Data, "darwin", none, home, "/home/me/Library/Application Support/togo"},
+		{"macOS cache", Cache, "darwin", none, home, "/home/me/Library/Caches/togo"},
+		{"windows data roams", Data, "windows", windows, home, "/users/me/AppData/Roaming/togo"},
+		{"windows cache is local", Cache, "windows", windows, home, "/users/me/AppData/Local/togo/cache"},
+		{"windows without appdata", Config, "windows", none, home, ""},
+		{"no home", Config, "linux", none, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolve(tt.kind, tt.goos, tt.getenv, tt.home)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("resolve() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != filepath.FromSlash(tt.want) {
+				t.Errorf("resolve() = %q, %v; want %q", got, err, filepath.FromSlash(tt.want))
+			}
+		})
+	}
+}
+
+// TestDir_Creates verifies the directory exists once returned.
+func TestDir_Creates(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", base)
+	dir, err := Dir(Cache)
+	if err != nil {
+		t.Fatalf("Dir() error: %v", err)
+	}
+	if want := filepath.Join(base, "togo"); dir != want {
+		t.Errorf("Dir() = %q, want %q", dir, want)
+	}
+	if entries, err := filepath.Glob(dir); err != nil || len(entries) != 1 {
+		t.Errorf("directory %s was not created", dir)
+	}
+}
@@ -0,0 +1,146 @@
+// Package persist holds helpers shared by the storage backends for deciding
+// when data reaches the disk.
+package persist
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Default debounce timings. A burst of keystrokes or a bulk action finishes
+// well within DefaultDelay; DefaultMaxWait bounds how stale the file can get
+// during a continuous stream of edits.
+const (
+	DefaultDelay   = 250 * time.Millisecond
+	DefaultMaxWait = 2 * time.Second
+)
+
+// ErrClosed is returned by Mark after Close.
+var ErrClosed = errors.New("debouncer is closed")
+
+// timer is the part of *time.Timer the Debouncer uses, so tests can drive
+// it without sleeping.
+type timer interface {
+	Stop() bool
+}
+
+// Debouncer coalesces many mutations into a single flush. Each Mark
+// postpones the flush until no mutation has happened for the delay, but
+// never longer than the max wait after the first unflushed mutation.
+//
+// Callers must Flush or Close on quit so nothing pending is lost; durability
+// across crashes between a mutation and its flush is the write-ahead log's
+// job, not the Debouncer's.
+type Debouncer struct {
+	delay   time.Duration
+	maxWait time.Duration
+	flush   func() error
+	onError func(error)
+
+	// afterFunc and now are replaced in tests.
+	afterFunc func(time.Duration, func()) timer
+	now       func() time.Time
+
+	mu      sync.Mutex
+	timer   timer
+	since   time.Time // first unflushed Mark; zero when nothing is pending
+	closed  bool
+	flushMu sync.Mutex // serializes calls to flush
+}
+
+// NewDebouncer returns a Debouncer calling flush after bursts of Mark calls.
+// Errors from background flushes go to onError, which may be nil; errors
+// from explicit Flush and Close calls are returned to the caller.
+func NewDebouncer(delay, maxWait time.Duration, flush func() error, onError func(error)) *Debouncer {
+	return &Debouncer{
+		delay:   delay,
+		maxWait: maxWait,
+		flush:   flush,
+		onError: onError,
+		afterFunc: func(d time.Duration, f func()) timer {
+			return time.AfterFunc(d, f)
+		},
+		now: time.Now,
+	}
+}
+
+// Mark records that data changed and schedules a flush.
+func (d *Debouncer) Mark() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return ErrClosed
+	}
+
+	now := d.now()
+	if d.since.IsZero() {
+		d.since = now
+	}
+	wait := d.delay
+	if deadline := d.since.Add(d.maxWait); now.Add(wait).After(deadline) {
+		wait = max(deadline.Sub(now), 0)
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = d.afterFunc(wait, d.fire)
+	return nil
+}
+
+// Pending reports whether changes are waiting to be flushed.
+func (d *Debouncer) Pending() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !d.since.IsZero()
+}
+
+// Flush writes pending changes immediately. It is a no-op when nothing is
+// pending.
+func (d *Debouncer) Flush() error {
+	return d.run()
+}
+
+// Close flushes pending changes and rejects further marks.
+func (d *Debouncer) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	return d.run()
+}
+
+// fire is the timer callback.
+func (d *Debouncer) fire() {
+	if err := d.run(); err != nil && d.onError != nil {
+		d.onError(err)
+	}
+}
+
+// run flushes if anything is pending. A failed flush leaves the changes
+// pending so the next Mark, Flush or Close retries.
+func (d *Debouncer) run() error {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.mu.Lock()
+	if d.since.IsZero() {
+		d.mu.Unlock()
+		return nil
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.since = time.Time{}
+	d.mu.Unlock()
+
+	if err := d.flush(); err != nil {
+		d.mu.Lock()
+		if d.since.IsZero() {
+			d.since = d.now()
+		}
+		d.mu.Unlock()
+		return err
+	}
+	return nil
+}
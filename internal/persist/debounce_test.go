@@ -0,0 +1,133 @@
+package persist
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTimers records scheduled callbacks so tests can fire them by hand.
+type fakeTimers struct {
+	waits   []time.Duration
+	pending func()
+}
+
+type fakeTimer struct{ t *fakeTimers }
+
+func (f fakeTimer) Stop() bool {
+	stopped := f.t.pending != nil
+	f.t.pending = nil
+	return stopped
+}
+
+func (f *fakeTimers) afterFunc(d time.Duration, fn func()) timer {
+	f.waits = append(f.waits, d)
+	f.pending = fn
+	return fakeTimer{f}
+}
+
+func (f *fakeTimers) fire() {
+	if fn := f.pending; fn != nil {
+		f.pending = nil
+		fn()
+	}
+}
+
+// newTestDebouncer returns a Debouncer on fake timers and a settable clock.
+func newTestDebouncer(flush func() error, onError func(error)) (*Debouncer, *fakeTimers, *time.Time) {
+	timers := &fakeTimers{}
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	d := NewDebouncer(100*time.Millisecond, time.Second, flush, onError)
+	d.afterFunc = timers.afterFunc
+	d.now = func() time.Time { return now }
+	return d, timers, &now
+}
+
+// TestDebouncer_CoalescesBurst verifies many marks produce one flush.
+func TestDebouncer_CoalescesBurst(t *testing.T) {
+	flushes := 0
+	d, timers, _ := newTestDebouncer(func() error { flushes++; return nil }, nil)
+
+	for range 50 {
+		if err := d.Mark(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if flushes != 0 || !d.Pending() {
+		t.Fatalf("flushed %d times before the delay elapsed", flushes)
+	}
+
+	timers.fire()
+	if flushes != 1 || d.Pending() {
+		t.Errorf("flushes = %d, pending = %v; want one flush and nothing pending", flushes, d.Pending())
+	}
+}
+
+// TestDebouncer_MaxWait verifies continuous marks cannot postpone the flush
+// past the max wait.
+func TestDebouncer_MaxWait(t *testing.T) {
+	d, timers, now := newTestDebouncer(func() error { return nil }, nil)
+
+	d.Mark()
+	*now = now.Add(950 * time.Millisecond)
+	d.Mark()
+	*now = now.Add(50 * time.Millisecond)
+	d.Mark()
+
+	want := []time.Duration{100 * time.Millisecond, 50 * time.Millisecond, 0}
+	for i, w := range want {
+		if timers.waits[i] != w {
+			t.Errorf("wait %d = %v, want %v", i, timers.waits[i], w)
+		}
+	}
+}
+
+// TestDebouncer_FlushAndClose verifies explicit flushes run immediately,
+// are no-ops when idle, and Close rejects later marks.
+func TestDebouncer_FlushAndClose(t *testing.T) {
+	flushes := 0
+	d, timers, _ := newTestDebouncer(func() error { flushes++; return nil }, nil)
+
+	if err := d.Flush(); err != nil || flushes != 0 {
+		t.Fatalf("idle Flush() = %v after %d flushes", err, flushes)
+	}
+
+	d.Mark()
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if flushes != 1 {
+		t.Errorf("flushes after Close = %d, want 1", flushes)
+	}
+	if timers.pending != nil {
+		t.Error("timer still scheduled after Close")
+	}
+	if err := d.Mark(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Mark() after Close = %v, want ErrClosed", err)
+	}
+}
+
+// TestDebouncer_FailedFlushRetries verifies a failed background flush is
+// reported and the changes stay pending for the next attempt.
+func TestDebouncer_FailedFlushRetries(t *testing.T) {
+	diskFull := errors.New("disk full")
+	fail := true
+	var reported error
+	d, timers, _ := newTestDebouncer(func() error {
+		if fail {
+			return diskFull
+		}
+		return nil
+	}, func(err error) { reported = err })
+
+	d.Mark()
+	timers.fire()
+	if !errors.Is(reported, diskFull) || !d.Pending() {
+		t.Fatalf("reported = %v, pending = %v", reported, d.Pending())
+	}
+
+	fail = false
+	if err := d.Close(); err != nil || d.Pending() {
+		t.Errorf("Close() = %v, pending = %v", err, d.Pending())
+	}
+}
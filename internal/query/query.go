@@ -0,0 +1,293 @@
+// Package query parses the filter language shared by the CLI and the TUI
+// filter bar into a model.TaskFilter.
+//
+// A query is a whitespace-separated list of terms, all of which must hold:
+//
+//	id:550e84             ID starts with the prefix
+//	status:today          status is pool, today or done
+//	energy:low            energy is low, medium or high
+//	+work  tag:work       tagged work
+//	-someday              not tagged someday
+//	any:work,errands      tagged work or errands
+//	no:tags               not tagged at all
+//	due.before:friday     due on or before Friday (also due<friday)
+//	due.after:2024-06-01  due on or after the date (also due>2024-06-01)
+//	created.after:monday  created on or after Monday (also created>monday)
+//	created.before:DATE   created on or before the date (also created<DATE)
+//	completed.after:DATE  completed on or after the date (also completed>DATE)
+//	completed.before:DATE completed on or before the date (also completed<DATE)
+//	is:overdue            due before today and not done
+//	has:due  no:due       with or without a due date
+//	has:notes  no:notes   with or without notes
+//	deferred:3            deferred at least three times
+//	regex:^fix            title matches the regular expression
+//	limit:10              at most ten results
+//	offset:20             skip the first twenty results
+//	"release notes"       title or notes contain the phrase
+//
+// Bare words are joined into a single text search. Dates take any form
+// accepted by model.ParseRelativeDate, such as tomorrow, eow, +3d, fri or
+// 2024-06-01, and compare by calendar day. A weekday means the next such day for due dates, which lie ahead, and
+// the most recent one for creation and completion dates, which lie behind; both count today.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/model"
+)
+
+// SyntaxError reports a term that could not be understood.
+type SyntaxError struct {
+	Term   string
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: %q: %s", e.Term, e.Reason)
+}
+
+// Parse compiles input into a filter, resolving relative dates against the
+// model clock (see model.Now).
+func Parse(input string) (model.TaskFilter, error) {
+	var f model.TaskFilter
+	terms, err := split(input)
+	if err != nil {
+		return f, err
+	}
+
+	var text []string
+	for _, term := range terms {
+		if term.quoted {
+			text = append(text, term.value)
+			continue
+		}
+		if err := apply(&f, term.value, &text); err != nil {
+			return model.TaskFilter{}, err
+		}
+	}
+	f.Text = strings.Join(text, " ")
+	return f, nil
+}
+
+// apply adds the criterion expressed by one unquoted term to f. Terms that
+// are not criteria are collected as free text.
+func apply(f *model.TaskFilter, term string, text *[]string) error {
+	switch {
+	case len(term) > 1 && term[0] == '+':
+		f.Tags = append(f.Tags, term[1:])
+		return nil
+	case len(term) > 1 && term[0] == '-':
+		f.ExcludeTags = append(f.ExcludeTags, term[1:])
+		return nil
+	}
+
+	if field, op, value, ok := cutComparison(term); ok {
+		return applyDate(f, term, field, op, value)
+	}
+
+	key, value, ok := strings.Cut(term, ":")
+	if !ok {
+		*text = append(*text, term)
+		return nil
+	}
+	key = strings.ToLower(key)
+	if !keys[key] {
+		// Not a criterion: treat it as text, so URLs and times still search.
+		*text = append(*text, term)
+		return nil
+	}
+	if value == "" {
+		return &SyntaxError{Term: term, Reason: "missing value"}
+	}
+
+	switch key {
+	case "id":
+		f.IDPrefix = value
+	case "status":
+		s := model.TaskStatus(strings.ToLower(value))
+		if !s.Valid() {
+			return &SyntaxError{Term: term, Reason: "status must be pool, today or done"}
+		}
+		f.Status = &s
+	case "energy":
+		e, err := model.ParseEnergy(value)
+		if err != nil {
+			return &SyntaxError{Term: term, Reason: "energy must be low, medium or high"}
+		}
+		f.Energy = &e
+	case "tag":
+		f.Tags = append(f.Tags, value)
+	case "any":
+		f.TagsAny = append(f.TagsAny, strings.Split(value, ",")...)
+	case "due.before", "created.before", "completed.before":
+		return applyDate(f, term, strings.TrimSuffix(key, ".before"), "<", value)
+	case "due.after", "created.after", "completed.after":
+		return applyDate(f, term, strings.TrimSuffix(key, ".after"), ">", value)
+	case "is":
+		if strings.ToLower(value) != "overdue" {
+			return &SyntaxError{Term: term, Reason: "expected is:overdue"}
+		}
+		f.OverdueOnly = true
+	case "has", "no":
+		presence := model.PresenceRequired
+		if key == "no" {
+			presence = model.PresenceAbsent
+		}
+		switch strings.ToLower(value) {
+		case "due":
+			f.HasDueDate = presence
+		case "notes":
+			f.HasNotes = presence
+		case "tags":
+			if key == "has" {
+				return &SyntaxError{Term: term, Reason: "use any:TAG,… to require some tag"}
+			}
+			f.NoTags = true
+		default:
+			return &SyntaxError{Term: term, Reason: "expected due, notes or tags"}
+		}
+	case "regex":
+		re, err := model.CompileRegex("regex", value)
+		var verr *model.ValidationError
+		if errors.As(err, &verr) {
+			return &SyntaxError{Term: term, Reason: verr.Reason}
+		}
+		f.TitleRegex = re
+	case "deferred", "limit", "offset":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return &SyntaxError{Term: term, Reason: key + " must be a whole number"}
+		}
+		switch key {
+		case "deferred":
+			f.DeferredAtLeast = n
+		case "limit":
+			f.Limit = n
+		default:
+			f.Offset = n
+		}
+	}
+	return nil
+}
+
+// keys lists the criteria written as key:value.
+var keys = map[string]bool{
+	"id": true, "status": true, "energy": true, "tag": true, "any": true,
+	"due.before": true, "due.after": true, "created.before": true, "created.after": true,
+	"completed.before": true, "completed.after": true,
+	"is": true, "has": true, "no": true, "deferred": true, "regex": true, "limit": true, "offset": true,
+}
+
+// dateFields lists the fields that accept date comparisons.
+var dateFields = []string{"due", "created", "completed"}
+
+// cutComparison splits terms such as due<x, due<=x, created>x and
+// created>=x into field, operator and value. The inclusive and exclusive
+// forms are equivalent, since dates compare by whole days.
+func cutComparison(term string) (field, op, value string, ok bool) {
+	lower := strings.ToLower(term)
+	for _, field := range dateFields {
+		rest, ok := strings.CutPrefix(lower, field)
+		if !ok || rest == "" || (rest[0] != '<' && rest[0] != '>') {
+			continue
+		}
+		op, value = rest[:1], strings.TrimPrefix(rest[1:], "=")
+		return field, op, term[len(term)-len(value):], true
+	}
+	return "", "", "", false
+}
+
+// applyDate sets the bound on field named by op ("<" or ">") to the day
+// given by value.
+func applyDate(f *model.TaskFilter, term, field, op, value string) error {
+	day, err := parseDate(value, model.Now(), field == "due")
+	if err != nil {
+		return &SyntaxError{Term: term, Reason: err.Error()}
+	}
+
+	if field == "due" {
+		f.DueByDay = true
+		if op == "<" {
+			f.DueBefore = &day
+		} else {
+			f.DueAfter = &day
+		}
+		return nil
+	}
+
+	// Other fields compare exact instants, so an upper bound must reach the
+	// end of its day.
+	if op == "<" {
+		day = endOfDay(day)
+	}
+	switch {
+	case field == "created" && op == "<":
+		f.CreatedBefore = &day
+	case field == "created":
+		f.CreatedAfter = &day
+	case op == "<":
+		f.CompletedBefore = &day
+	default:
+		f.CompletedAfter = &day
+	}
+	return nil
+}
+
+// endOfDay returns the last representable instant of the day starting at day.
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// parseDate resolves a query date to the start of that day in now's
+// location using model.ParseRelativeDate, except that weekday names resolve
+// backward from today unless ahead is set.
+func parseDate(value string, now time.Time, ahead bool) (time.Time, error) {
+	if wd, ok := model.ParseWeekday(value); ok && !ahead {
+		today := model.StartOfDay(now)
+		return today.AddDate(0, 0, -((int(today.Weekday()) - int(wd) + 7) % 7)), nil
+	}
+	day, err := model.ParseRelativeDate(value, now)
+	var verr *model.ValidationError
+	if errors.As(err, &verr) {
+		return time.Time{}, errors.New(verr.Reason)
+	}
+	return day, err
+}
+
+// term is one lexical unit of a query.
+type term struct {
+	value  string
+	quoted bool
+}
+
+// split breaks input into terms on whitespace, keeping double-quoted
+// phrases together.
+func split(input string) ([]term, error) {
+	var terms []term
+	rest := strings.TrimSpace(input)
+	for rest != "" {
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, &SyntaxError{Term: rest, Reason: "unterminated quote"}
+			}
+			if phrase := rest[1 : end+1]; phrase != "" {
+				terms = append(terms, term{value: phrase, quoted: true})
+			}
+			rest = strings.TrimSpace(rest[end+2:])
+			continue
+		}
+		end := strings.IndexAny(rest, " \t\n")
+		if end < 0 {
+			end = len(rest)
+		}
+		terms = append(terms, term{value: rest[:end]})
+		rest = strings.TrimSpace(rest[end:])
+	}
+	return terms, nil
+}
@@ -0,0 +1,167 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// wednesday is the reference "now" for relative dates.
+var wednesday = time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC)
+
+func day(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// TestParse verifies each kind of term compiles to the expected criteria.
+func TestParse(t *testing.T) {
+	defer model.SetClock(model.NewFixedClock(wednesday))()
+
+	tests := []struct {
+		name  string
+		input string
+		want  model.TaskFilter
+	}{
+		{"empty", "  ", model.TaskFilter{}},
+		{"id prefix", "id:550e84", model.TaskFilter{IDPrefix: "550e84"}},
+		{"status", "status:today", model.TaskFilter{Status: ptr(model.StatusToday)}},
+		{"energy", "energy:LOW", model.TaskFilter{Energy: ptr(model.EnergyLow)}},
+		{"tags", "+work tag:urgent", model.TaskFilter{Tags: []string{"work", "urgent"}}},
+		{"excluded tag", "-someday", model.TaskFilter{ExcludeTags: []string{"someday"}}},
+		{"any tags", "any:work,errands", model.TaskFilter{TagsAny: []string{"work", "errands"}}},
+		{"due before weekday", "due.before:friday", model.TaskFilter{DueBefore: day(2024, 6, 14), DueByDay: true}},
+		{"due before today's weekday", "due<wed", model.TaskFilter{DueBefore: day(2024, 6, 12), DueByDay: true}},
+		{"due after date", "due>=2024-07-01", model.TaskFilter{DueAfter: day(2024, 7, 1), DueByDay: true}},
+		{"due after tomorrow", "due.after:tomorrow", model.TaskFilter{DueAfter: day(2024, 6, 13), DueByDay: true}},
+		{"created since weekday", "created.after:monday", model.TaskFilter{CreatedAfter: day(2024, 6, 10)}},
+		{"created before date", "created<2024-06-01", model.TaskFilter{CreatedBefore: ptr(time.Date(2024, 6, 1, 23, 59, 59, 999999999, time.UTC))}},
+		{"completed this week", "completed>=mon completed<today", model.TaskFilter{
+			CompletedAfter:  day(2024, 6, 10),
+			CompletedBefore: ptr(time.Date(2024, 6, 12, 23, 59, 59, 999999999, time.UTC)),
+		}},
+		{"overdue", "is:overdue", model.NewOverdueFilter()},
+		{"triage", "no:due has:notes", model.TaskFilter{HasDueDate: model.PresenceAbsent, HasNotes: model.PresenceRequired}},
+		{"chronically deferred", "deferred:3", model.TaskFilter{DeferredAtLeast: 3}},
+		{"due by end of month", "due<eom", model.TaskFilter{DueBefore: day(2024, 6, 30), DueByDay: true}},
+		{"due within three days", "due.before:+3d", model.TaskFilter{DueBefore: day(2024, 6, 15), DueByDay: true}},
+		{"untagged", "no:tags", model.TaskFilter{NoTags: true}},
+		{"limit", "limit:5", model.TaskFilter{Limit: 5}},
+		{"page", "limit:5 offset:10", model.TaskFilter{Limit: 5, Offset: 10}},
+		{"quoted phrase", `"release notes"`, model.TaskFilter{Text: "release notes"}},
+		{"bare words", "renew passport", model.TaskFilter{Text: "renew passport"}},
+		{"unknown key is text", "https://example.com", model.TaskFilter{Text: "https://example.com"}},
+		{
+			name:  "combined",
+			input: `status:today +work -someday due.before:friday "release notes"`,
+			want: model.TaskFilter{
+				Status:      ptr(model.StatusToday),
+				Tags:        []string{"work"},
+				ExcludeTags: []string{"someday"},
+				DueBefore:   day(2024, 6, 14),
+				DueByDay:    true,
+				Text:        "release notes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParse_Regex verifies regex terms compile into TitleRegex.
+func TestParse_Regex(t *testing.T) {
+	f, err := Parse(`regex:^fix\b`)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if f.TitleRegex == nil || !f.TitleRegex.MatchString("fix login") || f.TitleRegex.MatchString("prefix") {
+		t.Errorf("TitleRegex = %v", f.TitleRegex)
+	}
+}
+
+// TestParse_Errors verifies malformed terms are reported with the term.
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"status:later", `"status:later": status must be pool, today or done`},
+		{"energy:max", "energy must be"},
+		{"due<someday", "expected YYYY-MM-DD"},
+		{"limit:ten", "whole number"},
+		{"is:late", "expected is:overdue"},
+		{"has:tags", "use any:"},
+		{"no:project", "expected due, notes or tags"},
+		{"offset:-1", "offset must be a whole number"},
+		{"regex:(", "missing closing )"},
+		{"status:", "missing value"},
+		{`"release notes`, "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var serr *SyntaxError
+			if !errors.As(err, &serr) {
+				t.Fatalf("Parse(%q) error = %v, want *SyntaxError", tt.input, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %q, want containing %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParse_MatchesTasks verifies a parsed query selects the expected tasks
+// end to end.
+func TestParse_MatchesTasks(t *testing.T) {
+	defer model.SetClock(model.NewFixedClock(wednesday))()
+
+	f, err := Parse("+work -someday due<friday")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	friday := time.Date(2024, 6, 14, 18, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+	tests := []struct {
+		name string
+		tags []string
+		due  time.Time
+		want bool
+	}{
+		{"due friday evening", []string{"work"}, friday, true},
+		{"due saturday", []string{"work"}, saturday, false},
+		{"excluded tag", []string{"work", "someday"}, friday, false},
+		{"missing tag", []string{"home"}, friday, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := model.NewTask("task", tt.tags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task.DueDate = &tt.due
+			if got := f.Matches(task); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
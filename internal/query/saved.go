@@ -0,0 +1,144 @@
+package query
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"togo/internal/atomicfile"
+)
+
+// MaxHotkeys is how many saved filters can be recalled by number key.
+const MaxHotkeys = 9
+
+// Saved is a query stored under a name such as "inbox" or "this-week".
+type Saved struct {
+	Name  string
+	Query string
+}
+
+// SavedFilters is an ordered collection of saved queries. The first nine
+// are bound to the number keys 1-9 in the TUI, in order.
+type SavedFilters []Saved
+
+// namePattern restricts names to what is easy to type on a command line.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Lookup returns the filter saved under name.
+func (s SavedFilters) Lookup(name string) (Saved, bool) {
+	for _, f := range s {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Saved{}, false
+}
+
+// Hotkey returns the filter bound to number key n (1-9).
+func (s SavedFilters) Hotkey(n int) (Saved, bool) {
+	if n < 1 || n > MaxHotkeys || n > len(s) {
+		return Saved{}, false
+	}
+	return s[n-1], true
+}
+
+// Set saves q under name, replacing an existing filter of that name in place
+// so its hotkey is kept. The name must be lowercase letters, digits, "-" or
+// "_", and q must parse.
+func (s *SavedFilters) Set(name, q string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid filter name %q: use lowercase letters, digits, - and _", name)
+	}
+	q = strings.TrimSpace(q)
+	if _, err := Parse(q); err != nil {
+		return err
+	}
+	for i := range *s {
+		if (*s)[i].Name == name {
+			(*s)[i].Query = q
+			return nil
+		}
+	}
+	*s = append(*s, Saved{Name: name, Query: q})
+	return nil
+}
+
+// Delete removes the filter saved under name and reports whether it existed.
+func (s *SavedFilters) Delete(name string) bool {
+	for i, f := range *s {
+		if f.Name == name {
+			*s = append((*s)[:i], (*s)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSaved reads saved filters, one "name = query" per line. Blank lines
+// and lines starting with # are ignored.
+func ParseSaved(r io.Reader) (SavedFilters, error) {
+	var s SavedFilters
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, q, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected name = query", n)
+		}
+		if err := s.Set(strings.TrimSpace(name), q); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return s, scanner.Err()
+}
+
+// Write writes s in the format read by ParseSaved.
+func (s SavedFilters) Write(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "# Saved togo filters: name = query. The first nine are bound to keys 1-9."); err != nil {
+		return err
+	}
+	for _, f := range s {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", f.Name, f.Query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSaved reads saved filters from path. A missing file yields none.
+func LoadSaved(path string) (SavedFilters, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s, err := ParseSaved(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// StoreSaved atomically writes s to path, creating parent directories as
+// needed.
+func StoreSaved(path string, s SavedFilters) error {
+	return atomicfile.Write(path, s.Write)
+}
+
+// DefaultSavedPath is the saved filters file beside the configuration file
+// at configPath.
+func DefaultSavedPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "filters")
+}
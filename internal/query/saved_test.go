@@ -0,0 +1,112 @@
+package query
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSavedFilters_Set verifies validation and in-place replacement.
+func TestSavedFilters_Set(t *testing.T) {
+	var s SavedFilters
+	if err := s.Set("inbox", "status:pool"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("this-week", "due<sunday"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("inbox", " status:pool -someday "); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s) != 2 || s[0].Name != "inbox" || s[0].Query != "status:pool -someday" {
+		t.Errorf("replacing should keep position, got %+v", s)
+	}
+
+	tests := []struct {
+		name, query, wantErr string
+	}{
+		{"Inbox", "status:pool", "invalid filter name"},
+		{"my filter", "status:pool", "invalid filter name"},
+		{"broken", "status:later", "status must be"},
+	}
+	for _, tt := range tests {
+		if err := s.Set(tt.name, tt.query); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Set(%q, %q) error = %v, want containing %q", tt.name, tt.query, err, tt.wantErr)
+		}
+	}
+}
+
+// TestSavedFilters_Hotkey verifies number keys map to filters in order.
+func TestSavedFilters_Hotkey(t *testing.T) {
+	s := SavedFilters{{Name: "inbox", Query: "status:pool"}, {Name: "work", Query: "+work"}}
+
+	tests := []struct {
+		key  int
+		want string
+	}{
+		{1, "inbox"},
+		{2, "work"},
+		{3, ""},
+		{0, ""},
+		{10, ""},
+	}
+	for _, tt := range tests {
+		got, ok := s.Hotkey(tt.key)
+		if ok != (tt.want != "") || got.Name != tt.want {
+			t.Errorf("Hotkey(%d) = %+v, %v, want %q", tt.key, got, ok, tt.want)
+		}
+	}
+}
+
+// TestSavedFilters_Delete verifies removal by name.
+func TestSavedFilters_Delete(t *testing.T) {
+	s := SavedFilters{{Name: "inbox"}, {Name: "work"}}
+	if !s.Delete("inbox") || len(s) != 1 || s[0].Name != "work" {
+		t.Errorf("Delete(inbox) left %+v", s)
+	}
+	if s.Delete("missing") {
+		t.Error("Delete(missing) reported success")
+	}
+}
+
+// TestParseSaved verifies the file format and its errors.
+func TestParseSaved(t *testing.T) {
+	input := "# comment\n\ninbox = status:pool -someday\nreleases = \"release notes\" +work\n"
+	s, err := ParseSaved(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSaved() error: %v", err)
+	}
+	if f, ok := s.Lookup("releases"); !ok || f.Query != `"release notes" +work` {
+		t.Errorf("Lookup(releases) = %+v, %v", f, ok)
+	}
+
+	if _, err := ParseSaved(strings.NewReader("inbox status:pool")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected line 1 error, got %v", err)
+	}
+	if _, err := ParseSaved(strings.NewReader("\ninbox = status:nope")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected line 2 error, got %v", err)
+	}
+}
+
+// TestStoreSaved_RoundTrip verifies stored filters load back unchanged and
+// that a missing file yields no filters.
+func TestStoreSaved_RoundTrip(t *testing.T) {
+	path := DefaultSavedPath(filepath.Join(t.TempDir(), "togo", "config"))
+
+	if s, err := LoadSaved(path); err != nil || len(s) != 0 {
+		t.Fatalf("LoadSaved() on missing file = %v, %v", s, err)
+	}
+
+	want := SavedFilters{{Name: "inbox", Query: "status:pool"}, {Name: "this-week", Query: `due<sunday "q3 report"`}}
+	if err := StoreSaved(path, want); err != nil {
+		t.Fatalf("StoreSaved() error: %v", err)
+	}
+	got, err := LoadSaved(path)
+	if err != nil {
+		t.Fatalf("LoadSaved() error: %v", err)
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
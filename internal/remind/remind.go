@@ -0,0 +1,192 @@
+// Package remind tells the user when a task's reminder or due time is
+// reached, with a desktop notification: notify-send on Linux and the BSDs,
+// osascript on macOS and a PowerShell toast on Windows. The TUI checks for
+// reminders while it runs, and "togo remind --daemon" does so in the
+// background.
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"togo/internal/model"
+)
+
+// Kind says what a reminder is for.
+type Kind string
+
+const (
+	// KindReminder is a task's RemindAt.
+	KindReminder Kind = "reminder"
+	// KindDue is a task's due date. Due dates without a time of day are
+	// reached at the start of their day.
+	KindDue Kind = "due"
+)
+
+// Reminder is a reminder or due time of one task.
+type Reminder struct {
+	Task *model.Task
+	Kind Kind
+	At   time.Time
+}
+
+// Title returns the notification's title.
+func (r Reminder) Title() string {
+	if r.Kind == KindDue {
+		return "togo: due now"
+	}
+	return "togo: reminder"
+}
+
+// Between returns the reminders and due times of tasks reached after
+// after and no later than upTo, earliest first. Done tasks and tasks
+// snoozed at upTo are left out.
+func Between(tasks []*model.Task, after, upTo time.Time) []Reminder {
+	var due []Reminder
+	in := func(at *time.Time) bool {
+		return at != nil && at.After(after) && !at.After(upTo)
+	}
+	for _, t := range tasks {
+		if t.Status == model.StatusDone || t.IsSnoozed(upTo) {
+			continue
+		}
+		if in(t.RemindAt) {
+			due = append(due, Reminder{Task: t, Kind: KindReminder, At: *t.RemindAt})
+		}
+		if in(t.DueDate) {
+			due = append(due, Reminder{Task: t, Kind: KindDue, At: *t.DueDate})
+		}
+	}
+	slices.SortStableFunc(due, func(a, b Reminder) int { return a.At.Compare(b.At) })
+	return due
+}
+
+// Notifier shows a notification.
+type Notifier interface {
+	Notify(ctx context.Context, title, body string) error
+}
+
+// NotifierFunc adapts a function to Notifier.
+type NotifierFunc func(ctx context.Context, title, body string) error
+
+// Notify implements Notifier.
+func (f NotifierFunc) Notify(ctx context.Context, title, body string) error {
+	return f(ctx, title, body)
+}
+
+// Desktop returns the notifier of the operating system togo runs on.
+func Desktop() Notifier {
+	return NotifierFunc(func(ctx context.Context, title, body string) error {
+		name, args := command(runtime.GOOS, title, body)
+		if out, err := exec.CommandContext(ctx, name, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("notify with %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+}
+
+// command returns the program and arguments showing a notification on
+// goos.
+func command(goos, title, body string) (string, []string) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}
+	case "windows":
+		script := `$t = [Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02); ` +
+			`$x = $t.GetElementsByTagName('text'); ` +
+			`$x.Item(0).AppendChild($t.CreateTextNode(` + powerShellString(title) + `)) > $null; ` +
+			`$x.Item(1).AppendChild($t.CreateTextNode(` + powerShellString(body) + `)) > $null; ` +
+			`[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('togo').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return "notify-send", []string{"--app-name=togo", "--", title, body}
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellString quotes s as a verbatim PowerShell string literal.
+func powerShellString(s string) string {
+	return "'" + powerShellQuotes.Replace(s) + "'"
+}
+
+// powerShellQuotes doubles the characters that end a verbatim PowerShell
+// string: the ASCII single quote and its typographic variants.
+var powerShellQuotes = strings.NewReplacer(
+	"'", "''",
+	"‘", "‘‘",
+	"’", "’’",
+	"‚", "‚‚",
+	"‛", "‛‛",
+)
+
+// Scheduler notifies the user of the reminders reached since it last
+// checked. It is safe for concurrent use.
+type Scheduler struct {
+	notify Notifier
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewScheduler returns a scheduler notifying with n of the reminders
+// reached from since onwards. Reminders reached before since, while
+// nothing was checking, are not shown.
+func NewScheduler(n Notifier, since time.Time) *Scheduler {
+	return &Scheduler{notify: n, last: since}
+}
+
+// Check notifies the user of the reminders of tasks reached since the
+// last check, up to now, and returns them. A failed notification does not
+// stop the others; the failures are returned together.
+func (s *Scheduler) Check(ctx context.Context, tasks []*model.Task, now time.Time) ([]Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !now.After(s.last) {
+		return nil, nil
+	}
+	due := Between(tasks, s.last, now)
+	s.last = now
+
+	var errs []error
+	for _, r := range due {
+		if err := s.notify.Notify(ctx, r.Title(), r.Task.Title); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return due, errors.Join(errs...)
+}
+
+// Run checks the tasks listed by tasks every interval until ctx is done,
+// passing each check's outcome to report, which may be nil.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, tasks func() ([]*model.Task, error), report func([]Reminder, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := model.Now()
+			listed, err := tasks()
+			var due []Reminder
+			if err == nil {
+				due, err = s.Check(ctx, listed, now)
+			}
+			if report != nil && (len(due) > 0 || err != nil) {
+				report(due, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestBetween verifies reminders and due times are picked from the window,
+// earliest first, skipping done and snoozed tasks.
+func TestBetween(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 11, 12, h, m, 0, 0, time.UTC) }
+	remindAt := func(b *testutil.TaskBuilder, when time.Time) *model.Task {
+		task := b.Build()
+		task.RemindAt = &when
+		return task
+	}
+	snoozed := remindAt(testutil.NewTask().WithTitle("snoozed"), at(9, 30))
+	until := at(12, 0)
+	snoozed.SnoozedUntil = &until
+	tasks := []*model.Task{
+		remindAt(testutil.NewTask().WithTitle("call"), at(9, 30)),
+		testutil.NewTask().WithTitle("report").WithDue(at(9, 15)).Build(),
+		remindAt(testutil.NewTask().WithTitle("earlier"), at(9, 0)),
+		remindAt(testutil.NewTask().WithTitle("later"), at(10, 1)),
+		remindAt(testutil.NewTask().WithTitle("done").WithStatus(model.StatusDone), at(9, 30)),
+		snoozed,
+		testutil.NewTask().WithTitle("plain").Build(),
+	}
+
+	got := Between(tasks, at(9, 0), at(10, 0))
+	var titles []string
+	for _, r := range got {
+		titles = append(titles, string(r.Kind)+" "+r.Task.Title)
+	}
+	want := []string{"due report", "reminder call"}
+	if !slices.Equal(titles, want) {
+		t.Errorf("Between() = %q, want %q", titles, want)
+	}
+}
+
+// TestCommand verifies each platform's notifier quotes the text it shows.
+func TestCommand(t *testing.T) {
+	const body = `Say "hi" \ 'bye'`
+	tests := []struct {
+		name     string
+		goos     string
+		body     string
+		wantName string
+		wantArg  string
+	}{
+		{name: "linux", goos: "linux", body: body, wantName: "notify-send", wantArg: "--\ntogo: reminder\n" + body},
+		{name: "linux option-like body", goos: "linux", body: "-u critical", wantName: "notify-send", wantArg: "--\ntogo: reminder\n-u critical"},
+		{name: "darwin", goos: "darwin", body: body, wantName: "osascript", wantArg: `display notification "Say \"hi\" \\ 'bye'" with title "togo: reminder"`},
+		{name: "windows", goos: "windows", body: body, wantName: "powershell", wantArg: `CreateTextNode('Say "hi" \ ''bye''')`},
+		{name: "windows typographic quotes", goos: "windows", body: "Don’t ‘quote’ ‚low‛", wantName: "powershell", wantArg: "CreateTextNode('Don’’t ‘‘quote’’ ‚‚low‛‛')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args := command(tt.goos, "togo: reminder", tt.body)
+			if name != tt.wantName || !strings.Contains(strings.Join(args, "\n"), tt.wantArg) {
+				t.Errorf("command() = %s %q, want %s with %q", name, args, tt.wantName, tt.wantArg)
+			}
+		})
+	}
+}
+
+// TestScheduler_Check verifies each reminder is notified once, from the
+// scheduler's start on, and that notification failures are returned.
+func TestScheduler_Check(t *testing.T) {
+	start := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	early, soon := start.Add(-time.Minute), start.Add(5*time.Minute)
+	tasks := []*model.Task{testutil.NewTask().WithTitle("missed").Build(), testutil.NewTask().WithTitle("call").Build()}
+	tasks[0].RemindAt, tasks[1].RemindAt = &early, &soon
+
+	var shown []string
+	fail := false
+	n := NotifierFunc(func(_ context.Context, title, body string) error {
+		if fail {
+			return errors.New("no display")
+		}
+		shown = append(shown, body)
+		return nil
+	})
+	s := NewScheduler(n, start)
+
+	for _, now := range []time.Time{start.Add(time.Minute), start.Add(6 * time.Minute), start.Add(7 * time.Minute)} {
+		if _, err := s.Check(context.Background(), tasks, now); err != nil {
+			t.Fatalf("Check(%v) error: %v", now, err)
+		}
+	}
+	if !slices.Equal(shown, []string{"call"}) {
+		t.Errorf("notified %q, want only the reminder after the start, once", shown)
+	}
+
+	later := start.Add(time.Hour)
+	tasks[0].RemindAt = &later
+	fail = true
+	if due, err := s.Check(context.Background(), tasks, later); len(due) != 1 || err == nil {
+		t.Errorf("Check() with a failing notifier = %d reminders, %v; want 1 and an error", len(due), err)
+	}
+}
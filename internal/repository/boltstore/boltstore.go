@@ -0,0 +1,270 @@
+// Package boltstore keeps tasks in a bbolt database, an embedded pure-Go
+// key/value store. Unlike the JSON journal it updates single records in
+// place and reads only the statuses a query asks for, so it scales to
+// journals too large to rewrite on every save.
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// FileName is the database's name inside the data directory.
+const FileName = "tasks.db"
+
+// ErrInUse reports that another process has the database open. bbolt
+// locks the file for as long as a process holds it open, so only one
+// process at a time can use the database.
+var ErrInUse = errors.New("database is in use by another process")
+
+// openTimeout bounds how long Open waits for another process holding the
+// database.
+const openTimeout = time.Second
+
+// statuses lists the statuses that have a bucket, in listing order.
+var statuses = []model.TaskStatus{model.StatusPool, model.StatusToday, model.StatusDone}
+
+// indexBucket maps each task ID to the name of the status bucket holding it.
+var indexBucket = []byte("index")
+
+// Repository is a repository.TaskRepository backed by a bbolt database with
+// one bucket of JSON-encoded tasks per status, plus an index from task ID to
+// status bucket. It is safe for concurrent use.
+type Repository struct {
+	db *bolt.DB
+}
+
+var _ repository.TaskRepository = (*Repository)(nil)
+
+// Open opens or creates the database at path. Only one process may have it
+// open; Open gives up after a second if another does.
+func Open(path string) (*Repository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		err = ErrInUse
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range append(statusBuckets(), indexBucket) {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Repository{db: db}, nil
+}
+
+// Close releases the database.
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+// Save implements repository.TaskRepository.
+func (r *Repository) Save(task *model.Task) error {
+	if err := repository.CheckSave("save", task); err != nil {
+		return err
+	}
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		return put(tx, task)
+	})
+	if err == nil {
+		task.Version++
+	}
+	return err
+}
+
+// Get implements repository.TaskRepository.
+func (r *Repository) Get(id model.TaskID) (*model.Task, error) {
+	var task *model.Task
+	err := r.db.View(func(tx *bolt.Tx) error {
+		status := tx.Bucket(indexBucket).Get(id[:])
+		if status == nil {
+			return repository.NotFound("get", id)
+		}
+		data := tx.Bucket(status).Get(id[:])
+		if data == nil {
+			return &model.TaskError{ID: id, Op: "get", Err: errors.New("index points at a missing record")}
+		}
+		var err error
+		task, err = decode(id[:], data)
+		return err
+	})
+	return task, err
+}
+
+// Delete implements repository.TaskRepository.
+func (r *Repository) Delete(id model.TaskID) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return remove(tx, id)
+	})
+}
+
+// SaveAll implements repository.TaskRepository, in one transaction.
+func (r *Repository) SaveAll(tasks []*model.Task) error {
+	if err := repository.CheckBatch("save", tasks); err != nil {
+		return err
+	}
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		for _, t := range tasks {
+			if err := put(tx, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for _, t := range tasks {
+			t.Version++
+		}
+	}
+	return err
+}
+
+// DeleteMany implements repository.TaskRepository, in one transaction.
+func (r *Repository) DeleteMany(ids []model.TaskID) error {
+	if err := repository.CheckIDs("delete", ids); err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		for _, id := range ids {
+			if err := remove(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// put stores task at its next version in the bucket of its status,
+// moving it out of the bucket of its previous one. The caller updates
+// task.Version once the transaction commits.
+func put(tx *bolt.Tx, task *model.Task) error {
+	key, status := task.ID[:], []byte(task.Status)
+	index := tx.Bucket(indexBucket)
+	old := index.Get(key)
+	if old != nil {
+		stored, err := decode(key, tx.Bucket(old).Get(key))
+		if err != nil {
+			return err
+		}
+		if err := repository.CheckVersion("save", stored, task); err != nil {
+			return err
+		}
+	}
+	next := *task
+	next.Version++
+	data, err := json.Marshal(&next)
+	if err != nil {
+		return &model.TaskError{ID: task.ID, Op: "save", Err: err}
+	}
+	if old != nil && string(old) != string(status) {
+		if err := tx.Bucket(old).Delete(key); err != nil {
+			return err
+		}
+	}
+	if err := tx.Bucket(status).Put(key, data); err != nil {
+		return err
+	}
+	return index.Put(key, status)
+}
+
+// remove deletes the task with the given ID.
+func remove(tx *bolt.Tx, id model.TaskID) error {
+	index := tx.Bucket(indexBucket)
+	status := index.Get(id[:])
+	if status == nil {
+		return repository.NotFound("delete", id)
+	}
+	if err := tx.Bucket(status).Delete(id[:]); err != nil {
+		return err
+	}
+	return index.Delete(id[:])
+}
+
+// List implements repository.TaskRepository. A status filter reads only
+// that status's bucket.
+func (r *Repository) List(filter model.TaskFilter) ([]*model.Task, error) {
+	tasks, err := r.scan(filter)
+	if err != nil {
+		return nil, err
+	}
+	return repository.Query(tasks, filter), nil
+}
+
+// Count implements repository.TaskRepository.
+func (r *Repository) Count(filter model.TaskFilter) (int, error) {
+	tasks, err := r.scan(filter)
+	if err != nil {
+		return 0, err
+	}
+	return repository.CountMatching(tasks, filter), nil
+}
+
+// scan decodes every task in the buckets filter can match.
+func (r *Repository) scan(filter model.TaskFilter) ([]*model.Task, error) {
+	buckets := statusBuckets()
+	if filter.Status != nil {
+		if !filter.Status.Valid() {
+			return nil, nil
+		}
+		buckets = [][]byte{[]byte(*filter.Status)}
+	}
+
+	var tasks []*model.Task
+	err := r.db.View(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			err := tx.Bucket(name).ForEach(func(k, v []byte) error {
+				t, err := decode(k, v)
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, t)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return tasks, err
+}
+
+// decode unmarshals the task stored under key.
+func decode(key, data []byte) (*model.Task, error) {
+	var t model.Task
+	if err := json.Unmarshal(data, &t); err != nil {
+		var id model.TaskID
+		copy(id[:], key)
+		return nil, &model.TaskError{ID: id, Op: "decode", Err: err}
+	}
+	return &t, nil
+}
+
+// statusBuckets returns the bucket names for every status.
+func statusBuckets() [][]byte {
+	names := make([][]byte, len(statuses))
+	for i, s := range statuses {
+		names[i] = []byte(s)
+	}
+	return names
+}
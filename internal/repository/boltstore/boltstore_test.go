@@ -0,0 +1,125 @@
+package boltstore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/repositorytest"
+	"togo/internal/testutil"
+)
+
+func openTemp(t *testing.T) *Repository {
+	t.Helper()
+	repo, err := Open(filepath.Join(t.TempDir(), "data", FileName))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// TestRepository_Contract runs the shared repository contract.
+func TestRepository_Contract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.TaskRepository {
+		return openTemp(t)
+	})
+}
+
+// TestRepository_StatusChangeMovesBucket verifies a task lives in exactly
+// one status bucket and the index follows it.
+func TestRepository_StatusChangeMovesBucket(t *testing.T) {
+	repo := openTemp(t)
+	task := testutil.NewTask().Build()
+	if err := repo.Save(task); err != nil {
+		t.Fatal(err)
+	}
+	task.Status = model.StatusToday
+	if err := repo.Save(task); err != nil {
+		t.Fatal(err)
+	}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(model.StatusPool)).Get(task.ID[:]) != nil {
+			t.Error("task left behind in the pool bucket")
+		}
+		if tx.Bucket([]byte(model.StatusToday)).Get(task.ID[:]) == nil {
+			t.Error("task missing from the today bucket")
+		}
+		if got := string(tx.Bucket(indexBucket).Get(task.ID[:])); got != "today" {
+			t.Errorf("index = %q, want today", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRepository_StatusFilter verifies status-filtered queries only see
+// their bucket.
+func TestRepository_StatusFilter(t *testing.T) {
+	repo := openTemp(t)
+	for _, b := range []*testutil.TaskBuilder{
+		testutil.NewTask().WithTitle("p"),
+		testutil.NewTask().WithTitle("t").WithStatus(model.StatusToday),
+		testutil.NewTask().WithTitle("d").WithStatus(model.StatusDone),
+	} {
+		if err := repo.Save(b.Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	today := model.StatusToday
+	tasks, err := repo.List(model.TaskFilter{Status: &today})
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "t" {
+		t.Errorf("List(status:today) = %v, %v", tasks, err)
+	}
+	bogus := model.TaskStatus("later")
+	if n, err := repo.Count(model.TaskFilter{Status: &bogus}); err != nil || n != 0 {
+		t.Errorf("Count(status:later) = %d, %v", n, err)
+	}
+}
+
+// TestRepository_Reopen verifies data survives closing the database.
+func TestRepository_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := testutil.NewTask().WithTags("kept").Build()
+	if err := repo.Save(task); err != nil {
+		t.Fatal(err)
+	}
+	repo.Close()
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	got, err := reopened.Get(task.ID)
+	if err != nil || got.Tags[0] != "kept" {
+		t.Errorf("Get() after reopen = %v, %v", got, err)
+	}
+}
+
+// TestOpen_InUse verifies a database held open elsewhere is reported as in
+// use rather than as a bare lock timeout.
+func TestOpen_InUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	if _, err := Open(path); !errors.Is(err, ErrInUse) {
+		t.Errorf("second Open() error = %v, want ErrInUse", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package cachestore keeps a copy of every task of another repository in
+// memory, so that listing and filtering never wait on a slow backend. The
+// TUI filters on every keystroke; through the cache, that costs a scan of
+// memory instead of a read of the journal.
+package cachestore
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Repository is a repository.TaskRepository caching a backend. The cache
+// is filled from the backend by the first read and kept current by writes
+// made through it; changes made to the backend by anyone else, such as
+// another togo process, are only seen after Invalidate. It is safe for
+// concurrent use.
+type Repository struct {
+	backend repository.TaskRepository
+
+	mu sync.Mutex
+	// tasks is the cached task set, indexed by index; nil until loaded or
+	// after Invalidate.
+	tasks map[model.TaskID]*model.Task
+	index *repository.Index
+}
+
+var _ repository.TaskRepository = (*Repository)(nil)
+
+// New returns a cache over backend. Nothing is read until the first call.
+func New(backend repository.TaskRepository) *Repository {
+	return &Repository{backend: backend}
+}
+
+// Backend returns the wrapped repository.
+func (r *Repository) Backend() repository.TaskRepository {
+	return r.backend
+}
+
+// Save implements repository.TaskRepository. The task is written to the
+// backend before the cache, so a failed write leaves both unchanged, but
+// for a write refused as stale, which drops the cache so the next read
+// sees the backend's newer copy.
+func (r *Repository) Save(task *model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.backend.Save(task); err != nil {
+		r.dropIfStale(err)
+		return err
+	}
+	if r.tasks != nil {
+		r.tasks[task.ID] = task.Clone()
+		r.index.Put(task)
+	}
+	return nil
+}
+
+// Get implements repository.TaskRepository.
+func (r *Repository) Get(id model.TaskID) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.NotFound("get", id)
+	}
+	return t.Clone(), nil
+}
+
+// Delete implements repository.TaskRepository.
+func (r *Repository) Delete(id model.TaskID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.backend.Delete(id); err != nil {
+		return err
+	}
+	if r.tasks != nil {
+		delete(r.tasks, id)
+		r.index.Remove(id)
+	}
+	return nil
+}
+
+// SaveAll implements repository.TaskRepository, dropping the cache like
+// Save for a write refused as stale.
+func (r *Repository) SaveAll(tasks []*model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.backend.SaveAll(tasks); err != nil {
+		r.dropIfStale(err)
+		return err
+	}
+	if r.tasks != nil {
+		for _, t := range tasks {
+			r.tasks[t.ID] = t.Clone()
+			r.index.Put(t)
+		}
+	}
+	return nil
+}
+
+// DeleteMany implements repository.TaskRepository.
+func (r *Repository) DeleteMany(ids []model.TaskID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.backend.DeleteMany(ids); err != nil {
+		return err
+	}
+	if r.tasks != nil {
+		for _, id := range ids {
+			delete(r.tasks, id)
+			r.index.Remove(id)
+		}
+	}
+	return nil
+}
+
+// List implements repository.TaskRepository.
+func (r *Repository) List(filter model.TaskFilter) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return repository.Query(r.index.Narrow(r.tasks, filter), filter), nil
+}
+
+// Count implements repository.TaskRepository.
+func (r *Repository) Count(filter model.TaskFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return 0, err
+	}
+	return repository.CountMatching(r.index.Narrow(r.tasks, filter), filter), nil
+}
+
+// IndexStats describes the cache's index, for debugging. It is empty
+// until the cache is loaded.
+func (r *Repository) IndexStats() repository.IndexStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index == nil {
+		return repository.NewIndex().Stats()
+	}
+	return r.index.Stats()
+}
+
+// Invalidate drops the cache, so the next read reloads it from the
+// backend. Call it when the backend may have been changed by someone else.
+func (r *Repository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks, r.index = nil, nil
+}
+
+// dropIfStale drops the cache when err says the backend holds a newer copy
+// of a task than the one saved, which came from the cache: someone else
+// changed the backend, and a retry must not read the same stale copy.
+// r.mu must be held.
+func (r *Repository) dropIfStale(err error) {
+	if errors.Is(err, model.ErrStaleTask) {
+		r.tasks, r.index = nil, nil
+	}
+}
+
+// Close closes the backend if it holds resources.
+func (r *Repository) Close() error {
+	if c, ok := r.backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// load fills the cache from the backend unless it is filled. r.mu must be
+// held.
+func (r *Repository) load() error {
+	if r.tasks != nil {
+		return nil
+	}
+	// The cache holds every task, however the default filter hides it.
+	all, err := r.backend.List(model.TaskFilter{IncludeSnoozed: true, IncludeRecurring: true})
+	if err != nil {
+		return err
+	}
+	r.tasks = make(map[model.TaskID]*model.Task, len(all))
+	r.index = repository.NewIndex()
+	for _, t := range all {
+		r.tasks[t.ID] = t
+		r.index.Put(t)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cachestore
+
+import (
+	"errors"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/memstore"
+	"togo/internal/repository/repositorytest"
+	"togo/internal/service"
+	"togo/internal/testutil"
+)
+
+// TestRepository_Contract runs the shared repository contract.
+func TestRepository_Contract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.TaskRepository {
+		return New(memstore.New())
+	})
+}
+
+// countingBackend counts the reads that reach it and can be made to fail.
+type countingBackend struct {
+	*memstore.Repository
+	lists int
+	fail  error
+}
+
+func (b *countingBackend) List(filter model.TaskFilter) ([]*model.Task, error) {
+	b.lists++
+	return b.Repository.List(filter)
+}
+
+func (b *countingBackend) Save(task *model.Task) error {
+	if b.fail != nil {
+		return b.fail
+	}
+	return b.Repository.Save(task)
+}
+
+// TestRepository_ReadsThrough verifies the backend is read once, kept
+// current by writes through the cache, and reread after Invalidate.
+func TestRepository_ReadsThrough(t *testing.T) {
+	backend := &countingBackend{Repository: memstore.New()}
+	kept := testutil.NewTask().WithTitle("kept").Build()
+	testutil.MustSeed(t, backend.Repository, kept)
+	cache := New(backend)
+
+	added := testutil.NewTask().WithTitle("added").Build()
+	outside := testutil.NewTask().WithTitle("outside").Build()
+	steps := []struct {
+		name      string
+		do        func() error
+		wantCount int
+		wantLists int
+	}{
+		{name: "first read", do: func() error { return nil }, wantCount: 1, wantLists: 1},
+		{name: "save", do: func() error { return cache.Save(added) }, wantCount: 2, wantLists: 1},
+		{name: "delete", do: func() error { return cache.Delete(kept.ID) }, wantCount: 1, wantLists: 1},
+		{name: "failed save", do: func() error {
+			backend.fail = errors.New("disk full")
+			defer func() { backend.fail = nil }()
+			if err := cache.Save(testutil.NewTask().Build()); err == nil {
+				return errors.New("Save() succeeded")
+			}
+			return nil
+		}, wantCount: 1, wantLists: 1},
+		{name: "changed elsewhere", do: func() error { return backend.Repository.Save(outside) }, wantCount: 1, wantLists: 1},
+		{name: "invalidated", do: func() error { cache.Invalidate(); return nil }, wantCount: 2, wantLists: 2},
+	}
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		n, err := cache.Count(model.TaskFilter{})
+		if err != nil {
+			t.Fatalf("%s: Count() error: %v", step.name, err)
+		}
+		if n != step.wantCount || backend.lists != step.wantLists {
+			t.Errorf("%s: Count() = %d after %d backend reads, want %d after %d", step.name, n, backend.lists, step.wantCount, step.wantLists)
+		}
+	}
+}
+
+// TestRepository_StaleSave verifies a save refused as stale drops the
+// cache, so the service's retry reads the backend's newer copy and
+// succeeds.
+func TestRepository_StaleSave(t *testing.T) {
+	backend := memstore.New()
+	task := testutil.NewTask().WithTitle("Water the plants").Build()
+	testutil.MustSeed(t, backend, task)
+	cache := New(backend)
+	if _, err := cache.Get(task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process changes the task behind the cache's back.
+	theirs, err := backend.Get(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs.Tags = []string{"garden"}
+	if err := backend.Save(theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := service.New(cache, nil)
+	edited, err := tasks.EditTask(task.ID, func(t *model.Task) error {
+		t.Title = "Water the ferns"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EditTask() error: %v", err)
+	}
+	if edited.Title != "Water the ferns" || len(edited.Tags) != 1 {
+		t.Errorf("EditTask() = %q %v, want the new title over the other process's tags", edited.Title, edited.Tags)
+	}
+}
@@ -0,0 +1,514 @@
+// Package eventstore keeps tasks as an append-only log of immutable
+// events, one JSON object per line, and rebuilds the current tasks by
+// replaying it. Nothing is ever overwritten, so the log is a complete
+// history of every task, and logs written independently on two machines
+// merge without conflicts by taking the union of their events.
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"togo/internal/filelock"
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// FileName is the log's name inside the data directory.
+const FileName = "events.jsonl"
+
+// EventType says what an event did to its task.
+type EventType string
+
+// Event types.
+const (
+	// EventCreated records a new task; its Fields hold the whole task.
+	EventCreated EventType = "created"
+	// EventStatusChanged records an edit that changed the task's status.
+	EventStatusChanged EventType = "status_changed"
+	// EventEdited records any other edit.
+	EventEdited EventType = "edited"
+	// EventDeleted records the task's removal.
+	EventDeleted EventType = "deleted"
+)
+
+// Event is one immutable change to a task.
+type Event struct {
+	// ID identifies the event across logs, so merging a log with a copy of
+	// itself changes nothing.
+	ID     string       `json:"id"`
+	Time   time.Time    `json:"time"`
+	Type   EventType    `json:"type"`
+	TaskID model.TaskID `json:"task_id"`
+	// Fields maps the JSON names of the task fields the event set to their
+	// new values; null clears a field. Deletions set none.
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// Repository is a repository.TaskRepository backed by an event log. Saves
+// append the fields that changed; the log is replayed on first use and
+// events appended since, such as by another togo process, are read before
+// every operation. It is safe for concurrent use; across processes,
+// access holds an advisory lock on a sibling ".lock" file.
+type Repository struct {
+	path string
+
+	mu     sync.Mutex
+	tasks  map[model.TaskID]*model.Task
+	events []Event
+	// offset is how much of the log has been read: the end of its last
+	// complete line.
+	offset int64
+}
+
+var _ repository.TaskRepository = (*Repository)(nil)
+
+// New returns a repository logging to path. Nothing is read until the
+// first call.
+func New(path string) *Repository {
+	return &Repository{path: path, tasks: map[model.TaskID]*model.Task{}}
+}
+
+// Path returns the log file's location.
+func (r *Repository) Path() string {
+	return r.path
+}
+
+// Save implements repository.TaskRepository. Saving a task unchanged
+// appends nothing.
+func (r *Repository) Save(task *model.Task) error {
+	if err := repository.CheckSave("save", task); err != nil {
+		return err
+	}
+	return r.save([]*model.Task{task})
+}
+
+// Get implements repository.TaskRepository.
+func (r *Repository) Get(id model.TaskID) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.NotFound("get", id)
+	}
+	return t.Clone(), nil
+}
+
+// Delete implements repository.TaskRepository.
+func (r *Repository) Delete(id model.TaskID) error {
+	return r.append(func() ([]*Event, error) {
+		return deleteEvents([]model.TaskID{id}, r.tasks)
+	})
+}
+
+// SaveAll implements repository.TaskRepository. The events are appended
+// with one write and one sync; a crash during it may keep the events of
+// some of the tasks, each whole.
+func (r *Repository) SaveAll(tasks []*model.Task) error {
+	if err := repository.CheckBatch("save", tasks); err != nil {
+		return err
+	}
+	return r.save(tasks)
+}
+
+// DeleteMany implements repository.TaskRepository, appending the events
+// as SaveAll does.
+func (r *Repository) DeleteMany(ids []model.TaskID) error {
+	if err := repository.CheckIDs("delete", ids); err != nil {
+		return err
+	}
+	return r.append(func() ([]*Event, error) {
+		return deleteEvents(ids, r.tasks)
+	})
+}
+
+// save appends the events saving tasks, then moves each task that
+// changed to its next version.
+func (r *Repository) save(tasks []*model.Task) error {
+	var changed []*model.Task
+	err := r.append(func() ([]*Event, error) {
+		var events []*Event
+		var err error
+		events, changed, err = saveEvents(tasks, r.tasks)
+		return events, err
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range changed {
+		t.Version++
+	}
+	return nil
+}
+
+// saveEvents returns the events saving tasks over current, and the tasks
+// they change. A task saved unchanged needs no event and keeps its version.
+func saveEvents(tasks []*model.Task, current map[model.TaskID]*model.Task) ([]*Event, []*model.Task, error) {
+	for _, t := range tasks {
+		if err := repository.CheckVersion("save", current[t.ID], t); err != nil {
+			return nil, nil, err
+		}
+	}
+	var events []*Event
+	var changed []*model.Task
+	for _, t := range tasks {
+		next := *t
+		next.Version++
+		e, err := changeEvent(current[t.ID], &next)
+		if err != nil {
+			return nil, nil, &model.TaskError{ID: t.ID, Op: "save", Err: err}
+		}
+		if e != nil {
+			events = append(events, e)
+			changed = append(changed, t)
+		}
+	}
+	return events, changed, nil
+}
+
+// deleteEvents returns the events deleting the tasks with the given IDs
+// from current, failing if any is not there.
+func deleteEvents(ids []model.TaskID, current map[model.TaskID]*model.Task) ([]*Event, error) {
+	events := make([]*Event, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := current[id]; !ok {
+			return nil, repository.NotFound("delete", id)
+		}
+		events = append(events, newEvent(EventDeleted, id, nil))
+	}
+	return events, nil
+}
+
+// List implements repository.TaskRepository.
+func (r *Repository) List(filter model.TaskFilter) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	return repository.Query(r.all(), filter), nil
+}
+
+// Count implements repository.TaskRepository.
+func (r *Repository) Count(filter model.TaskFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refresh(); err != nil {
+		return 0, err
+	}
+	return repository.CountMatching(r.all(), filter), nil
+}
+
+// History returns the events recorded for the task with the given ID,
+// oldest first, including those of a deleted task.
+func (r *Repository) History(id model.TaskID) ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	var out []Event
+	for _, e := range r.events {
+		if e.TaskID == id {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// Events returns the whole log, oldest first.
+func (r *Repository) Events() ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	return slices.Clone(r.events), nil
+}
+
+// all returns the current tasks in no particular order. r.mu must be held.
+func (r *Repository) all() []*model.Task {
+	out := make([]*model.Task, 0, len(r.tasks))
+	for _, t := range r.tasks {
+		out = append(out, t)
+	}
+	return out
+}
+
+// refresh reads the events appended since the last read under a shared
+// lock. r.mu must be held.
+func (r *Repository) refresh() error {
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Shared)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return r.readNew()
+}
+
+// append reads new events, asks next for the events to record, if any,
+// and appends them to the log under an exclusive lock, syncing them to
+// disk before applying them.
+func (r *Repository) append(next func() ([]*Event, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	if err := r.readNew(); err != nil {
+		return err
+	}
+
+	events, err := next()
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	var lines []byte
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// Drop any partial line a crash left behind, so the event starts on a
+	// line of its own.
+	if err := f.Truncate(r.offset); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(lines, r.offset); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	r.offset += int64(len(lines))
+	for _, e := range events {
+		if err := r.apply(*e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readNew reads and applies the complete lines after r.offset. A partial
+// last line, left by a crash mid-append, is ignored. r.mu and the file
+// lock must be held.
+func (r *Repository) readNew() error {
+	f, err := os.Open(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(r.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(f)
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var e Event
+			if err := json.Unmarshal(trimmed, &e); err != nil {
+				return fmt.Errorf("%s: at byte %d: %w", r.path, r.offset, err)
+			}
+			if err := r.apply(e); err != nil {
+				return fmt.Errorf("%s: %w", r.path, err)
+			}
+		}
+		r.offset += int64(len(line))
+	}
+}
+
+// apply records e and updates the current tasks. r.mu must be held.
+func (r *Repository) apply(e Event) error {
+	if err := applyEvent(r.tasks, e); err != nil {
+		return err
+	}
+	r.events = append(r.events, e)
+	return nil
+}
+
+// lockPath returns the file locked around log reads and writes.
+func (r *Repository) lockPath() string {
+	return r.path + ".lock"
+}
+
+// newEvent returns an event of type typ for the task id, stamped now.
+func newEvent(typ EventType, id model.TaskID, fields map[string]json.RawMessage) *Event {
+	return &Event{
+		ID:     uuid.Must(uuid.NewV7()).String(),
+		Time:   model.Now(),
+		Type:   typ,
+		TaskID: id,
+		Fields: fields,
+	}
+}
+
+// changeEvent returns the event turning old into task: a creation when
+// old is nil, otherwise an edit of the fields that differ, or nil when
+// nothing does.
+func changeEvent(old, task *model.Task) (*Event, error) {
+	fields, err := fieldsOf(task)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return newEvent(EventCreated, task.ID, fields), nil
+	}
+
+	changes := old.Diff(task)
+	if len(changes) == 0 && old.UpdatedAt.Equal(task.UpdatedAt) {
+		return nil, nil
+	}
+	typ := EventEdited
+	changed := map[string]json.RawMessage{}
+	for _, c := range append(changes, model.FieldChange{Field: "updated_at"}, model.FieldChange{Field: "version"}) {
+		if c.Field == "status" {
+			typ = EventStatusChanged
+		}
+		value, ok := fields[c.Field]
+		if !ok {
+			// Omitted from the task's JSON, so now empty.
+			value = json.RawMessage("null")
+		}
+		changed[c.Field] = value
+	}
+	return newEvent(typ, task.ID, changed), nil
+}
+
+// fieldsOf returns task's JSON fields.
+func fieldsOf(task *model.Task) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// applyEvent updates tasks with e. Events are applied leniently so that
+// merged logs always replay: an edit or deletion of a task that no longer
+// exists is ignored, and a repeated creation replaces the task.
+func applyEvent(tasks map[model.TaskID]*model.Task, e Event) error {
+	switch e.Type {
+	case EventCreated:
+		t, err := overlay(nil, e.Fields)
+		if err != nil {
+			return fmt.Errorf("event %s: %w", e.ID, err)
+		}
+		tasks[e.TaskID] = t
+	case EventEdited, EventStatusChanged:
+		old, ok := tasks[e.TaskID]
+		if !ok {
+			return nil
+		}
+		t, err := overlay(old, e.Fields)
+		if err != nil {
+			return fmt.Errorf("event %s: %w", e.ID, err)
+		}
+		tasks[e.TaskID] = t
+	case EventDeleted:
+		delete(tasks, e.TaskID)
+	default:
+		return fmt.Errorf("event %s: unknown type %q", e.ID, e.Type)
+	}
+	return nil
+}
+
+// overlay returns a copy of base, which may be nil, with fields set.
+func overlay(base *model.Task, fields map[string]json.RawMessage) (*model.Task, error) {
+	merged := map[string]json.RawMessage{}
+	if base != nil {
+		var err error
+		if merged, err = fieldsOf(base); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range fields {
+		if string(value) == "null" {
+			delete(merged, name)
+		} else {
+			merged[name] = value
+		}
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	t := &model.Task{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Materialize replays events, in order, into the tasks they describe.
+func Materialize(events []Event) (map[model.TaskID]*model.Task, error) {
+	tasks := map[model.TaskID]*model.Task{}
+	for _, e := range events {
+		if err := applyEvent(tasks, e); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// Merge combines two logs, such as those of two synced machines, into one
+// ordered by time. Events present in both are kept once. Concurrent edits
+// of different fields both survive; edits of the same field resolve to the
+// later one.
+func Merge(a, b []Event) []Event {
+	seen := map[string]bool{}
+	var out []Event
+	for _, e := range slices.Concat(a, b) {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			out = append(out, e)
+		}
+	}
+	slices.SortStableFunc(out, func(x, y Event) int {
+		if c := x.Time.Compare(y.Time); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+	return out
+}
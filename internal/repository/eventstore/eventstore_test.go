@@ -0,0 +1,185 @@
+package eventstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/repositorytest"
+	"togo/internal/testutil"
+)
+
+// TestRepository_Contract runs the shared repository contract.
+func TestRepository_Contract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.TaskRepository {
+		return New(filepath.Join(t.TempDir(), FileName))
+	})
+}
+
+// TestRepository_History verifies each change appends one event holding
+// only what changed, and that the log replays to the same tasks.
+func TestRepository_History(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo := New(path)
+	task := testutil.NewTask().WithTitle("Renew passport").WithDue(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)).Build()
+
+	steps := []struct {
+		name   string
+		change func(t *model.Task)
+		want   EventType
+		fields []string
+	}{
+		{name: "create", want: EventCreated},
+		{name: "edit", change: func(t *model.Task) { t.Title = "Renew passports" }, want: EventEdited, fields: []string{"title", "updated_at", "version"}},
+		{name: "unchanged", change: func(*model.Task) {}},
+		{name: "status", change: func(t *model.Task) { t.Status = model.StatusToday }, want: EventStatusChanged, fields: []string{"status", "updated_at", "version"}},
+		{name: "clear", change: func(t *model.Task) { t.DueDate = nil }, want: EventEdited, fields: []string{"due_date", "updated_at", "version"}},
+	}
+	var count int
+	for _, step := range steps {
+		if step.change != nil {
+			step.change(task)
+			if step.want != "" {
+				task.UpdatedAt = task.UpdatedAt.Add(time.Minute)
+			}
+		}
+		if err := repo.Save(task); err != nil {
+			t.Fatalf("%s: Save() error: %v", step.name, err)
+		}
+		history, err := repo.History(task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step.want == "" {
+			if len(history) != count {
+				t.Errorf("%s: appended an event", step.name)
+			}
+			continue
+		}
+		count++
+		if len(history) != count {
+			t.Fatalf("%s: history holds %d events, want %d", step.name, len(history), count)
+		}
+		e := history[count-1]
+		if e.Type != step.want {
+			t.Errorf("%s: event type %q, want %q", step.name, e.Type, step.want)
+		}
+		if step.fields != nil && len(e.Fields) != len(step.fields) {
+			t.Errorf("%s: event fields %v, want %v", step.name, e.Fields, step.fields)
+		}
+	}
+	if err := repo.Delete(task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := New(path)
+	if _, err := reopened.Get(task.ID); err == nil {
+		t.Error("deleted task is back after reload")
+	}
+	events, err := reopened.Events()
+	if err != nil || len(events) != count+1 {
+		t.Fatalf("Events() = %d events, %v; want %d", len(events), err, count+1)
+	}
+	tasks, err := Materialize(events[:count])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := task.Diff(tasks[task.ID]); len(diff) > 0 {
+		t.Errorf("replayed task differs: %v", diff)
+	}
+}
+
+// TestRepository_ReadsOtherWriters verifies events appended by another
+// instance, such as a second togo process, are seen without reopening.
+func TestRepository_ReadsOtherWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	a, b := New(path), New(path)
+	first := testutil.NewTask().WithTitle("first").Build()
+	second := testutil.NewTask().WithTitle("second").Build()
+
+	if err := a.Save(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(first.ID); err != nil {
+		t.Fatalf("b does not see a's task: %v", err)
+	}
+	if err := b.Save(second); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := a.Count(model.TaskFilter{}); err != nil || n != 2 {
+		t.Errorf("a.Count() = %d, %v; want 2", n, err)
+	}
+}
+
+// TestRepository_PartialLine verifies a line cut short by a crash is
+// ignored and overwritten by the next event.
+func TestRepository_PartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	kept := testutil.NewTask().WithTitle("kept").Build()
+	if err := New(path).Save(kept); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"torn","type":"crea`)
+	f.Close()
+
+	repo := New(path)
+	if n, err := repo.Count(model.TaskFilter{}); err != nil || n != 1 {
+		t.Fatalf("Count() = %d, %v; want 1", n, err)
+	}
+	if err := repo.Save(testutil.NewTask().WithTitle("after").Build()); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := New(path).Count(model.TaskFilter{}); err != nil || n != 2 {
+		t.Errorf("Count() after reload = %d, %v; want 2", n, err)
+	}
+}
+
+// TestMerge verifies logs edited apart merge without losing either side's
+// changes.
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	base := New(filepath.Join(dir, "base"))
+	task := testutil.NewTask().WithTitle("Plan trip").Build()
+	if err := base.Save(task); err != nil {
+		t.Fatal(err)
+	}
+	shared, _ := base.Events()
+
+	edit := func(name string, change func(*model.Task)) []Event {
+		path := filepath.Join(dir, name)
+		data, _ := os.ReadFile(base.Path())
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		repo := New(path)
+		got, _ := repo.Get(task.ID)
+		change(got)
+		got.UpdatedAt = got.UpdatedAt.Add(time.Minute)
+		if err := repo.Save(got); err != nil {
+			t.Fatal(err)
+		}
+		events, _ := repo.Events()
+		return events
+	}
+	laptop := edit("laptop", func(t *model.Task) { t.Title = "Plan summer trip" })
+	phone := edit("phone", func(t *model.Task) { t.Notes = "ask about dates" })
+
+	merged := Merge(laptop, phone)
+	if len(merged) != len(shared)+2 {
+		t.Fatalf("Merge() = %d events, want %d", len(merged), len(shared)+2)
+	}
+	tasks, err := Materialize(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tasks[task.ID]; got.Title != "Plan summer trip" || got.Notes != "ask about dates" {
+		t.Errorf("merged task = %q / %q", got.Title, got.Notes)
+	}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"togo/internal/model"
+)
+
+// Index maps statuses and tags to the tasks that have them, and each run
+// of three characters in a title or notes to the tasks containing it, so
+// backends holding their tasks in memory can answer a List or Count with a
+// status, tag or text criterion without testing every task; the TUI's
+// search bar runs one on every keystroke. It only narrows the search: the
+// tasks it returns must still be matched against the filter.
+//
+// Index is not safe for concurrent mutation; backends guard it with the
+// lock that guards their tasks. Narrow may run concurrently with itself.
+type Index struct {
+	byStatus map[model.TaskStatus]idSet
+	byTag    map[string]idSet
+	byGram   map[string]idSet
+	// entries remembers what each task was indexed under, so that Put and
+	// Remove can drop stale entries.
+	entries map[model.TaskID]indexEntry
+
+	narrowed, scanned atomic.Int64
+}
+
+// idSet is a set of task IDs.
+type idSet map[model.TaskID]struct{}
+
+// indexEntry is what a task was indexed under.
+type indexEntry struct {
+	status model.TaskStatus
+	tags   []string
+	grams  []string
+}
+
+// IndexStats describes an Index, for debugging.
+type IndexStats struct {
+	// Tasks is how many tasks are indexed.
+	Tasks int
+	// Statuses counts the indexed tasks of each status.
+	Statuses map[model.TaskStatus]int
+	// Tags counts the indexed tasks carrying each tag.
+	Tags map[string]int
+	// Grams counts the distinct trigrams of the indexed titles and notes.
+	Grams int
+	// Narrowed and Scanned count the queries the index narrowed and those
+	// it could not help with, which tested every task.
+	Narrowed, Scanned int64
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{
+		byStatus: map[model.TaskStatus]idSet{},
+		byTag:    map[string]idSet{},
+		byGram:   map[string]idSet{},
+		entries:  map[model.TaskID]indexEntry{},
+	}
+}
+
+// Put indexes t, replacing what was indexed for its ID before.
+func (x *Index) Put(t *model.Task) {
+	x.Remove(t.ID)
+	e := indexEntry{status: t.Status, tags: slices.Clone(t.Tags), grams: textGrams(t)}
+	x.entries[t.ID] = e
+	add(x.byStatus, e.status, t.ID)
+	for _, tag := range e.tags {
+		add(x.byTag, tag, t.ID)
+	}
+	for _, g := range e.grams {
+		add(x.byGram, g, t.ID)
+	}
+}
+
+// Remove drops the task with the given ID from the index, if indexed.
+func (x *Index) Remove(id model.TaskID) {
+	e, ok := x.entries[id]
+	if !ok {
+		return
+	}
+	delete(x.entries, id)
+	remove(x.byStatus, e.status, id)
+	for _, tag := range e.tags {
+		remove(x.byTag, tag, id)
+	}
+	for _, g := range e.grams {
+		remove(x.byGram, g, id)
+	}
+}
+
+// Narrow returns the tasks of all that can match filter, in no particular
+// order: all of them unless filter has a status or tag criterion, or a
+// text criterion of at least three characters that is not fuzzy.
+func (x *Index) Narrow(all map[model.TaskID]*model.Task, filter model.TaskFilter) []*model.Task {
+	var sets []idSet
+	if filter.Status != nil {
+		sets = append(sets, x.byStatus[*filter.Status])
+	}
+	for _, tag := range filter.Tags {
+		sets = append(sets, x.tagged([]string{tag}, filter.TagMatchesPrefix))
+	}
+	if len(filter.TagsAny) > 0 {
+		sets = append(sets, x.tagged(filter.TagsAny, filter.TagMatchesPrefix))
+	}
+	if grams := trigrams(strings.ToLower(filter.Text)); len(grams) > 0 && !filter.Fuzzy {
+		for _, g := range grams {
+			sets = append(sets, x.byGram[g])
+		}
+	}
+
+	if len(sets) == 0 {
+		x.scanned.Add(1)
+		out := make([]*model.Task, 0, len(all))
+		for _, t := range all {
+			out = append(out, t)
+		}
+		return out
+	}
+	x.narrowed.Add(1)
+	slices.SortFunc(sets, func(a, b idSet) int { return len(a) - len(b) })
+	var out []*model.Task
+	for id := range sets[0] {
+		if inAll(sets[1:], id) {
+			if t, ok := all[id]; ok {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// Stats returns a snapshot of the index's contents and use.
+func (x *Index) Stats() IndexStats {
+	s := IndexStats{
+		Tasks:    len(x.entries),
+		Statuses: map[model.TaskStatus]int{},
+		Tags:     map[string]int{},
+		Grams:    len(x.byGram),
+		Narrowed: x.narrowed.Load(),
+		Scanned:  x.scanned.Load(),
+	}
+	for status, ids := range x.byStatus {
+		s.Statuses[status] = len(ids)
+	}
+	for tag, ids := range x.byTag {
+		s.Tags[tag] = len(ids)
+	}
+	return s
+}
+
+// tagged returns the tasks carrying any of tags or, with prefix, a tag
+// nested below one of them (see model.TagWithin).
+func (x *Index) tagged(tags []string, prefix bool) idSet {
+	if len(tags) == 1 && !prefix {
+		return x.byTag[tags[0]]
+	}
+	out := idSet{}
+	for tag, ids := range x.byTag {
+		if !slices.ContainsFunc(tags, func(want string) bool {
+			return tag == want || (prefix && model.TagWithin(tag, want))
+		}) {
+			continue
+		}
+		for id := range ids {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
+
+// textGrams returns the distinct trigrams of t's title and notes, folded
+// as model.TaskFilter folds them for a text search. Runs spanning the two
+// are left out, since no search matches across them.
+func textGrams(t *model.Task) []string {
+	grams := append(trigrams(strings.ToLower(t.Title)), trigrams(strings.ToLower(t.Notes))...)
+	slices.Sort(grams)
+	return slices.Compact(grams)
+}
+
+// trigrams returns each run of three characters in s, or none when s is
+// shorter.
+func trigrams(s string) []string {
+	r := []rune(s)
+	var out []string
+	for i := 0; i+3 <= len(r); i++ {
+		out = append(out, string(r[i:i+3]))
+	}
+	return out
+}
+
+// inAll reports whether every set holds id.
+func inAll(sets []idSet, id model.TaskID) bool {
+	for _, s := range sets {
+		if _, ok := s[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func add[K comparable](m map[K]idSet, key K, id model.TaskID) {
+	if m[key] == nil {
+		m[key] = idSet{}
+	}
+	m[key][id] = struct{}{}
+}
+
+func remove[K comparable](m map[K]idSet, key K, id model.TaskID) {
+	delete(m[key], id)
+	if len(m[key]) == 0 {
+		delete(m, key)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestIndex_Narrow verifies the index returns exactly the tasks whose
+// status, tags and text can match, and every task for other filters.
+func TestIndex_Narrow(t *testing.T) {
+	today := model.StatusToday
+	done := model.StatusDone
+	tasks := map[model.TaskID]*model.Task{}
+	x := NewIndex()
+	for _, b := range []*testutil.TaskBuilder{
+		testutil.NewTask().WithTitle("report").WithStatus(model.StatusToday).WithTags("work", "work/q3"),
+		testutil.NewTask().WithTitle("slides").WithNotes("Export the Report as PDF").WithTags("work"),
+		testutil.NewTask().WithTitle("groceries").WithStatus(model.StatusToday).WithTags("home"),
+		testutil.NewTask().WithTitle("taxes").WithStatus(model.StatusDone),
+	} {
+		task := b.Build()
+		tasks[task.ID] = task
+		x.Put(task)
+	}
+
+	tests := []struct {
+		name   string
+		filter model.TaskFilter
+		want   []string
+	}{
+		{name: "status", filter: model.TaskFilter{Status: &today}, want: []string{"groceries", "report"}},
+		{name: "tag", filter: model.TaskFilter{Tags: []string{"work"}}, want: []string{"report", "slides"}},
+		{name: "status and tag", filter: model.TaskFilter{Status: &today, Tags: []string{"work"}}, want: []string{"report"}},
+		{name: "all tags", filter: model.TaskFilter{Tags: []string{"work", "home"}}, want: nil},
+		{name: "any tag", filter: model.TaskFilter{TagsAny: []string{"home", "work/q3"}}, want: []string{"groceries", "report"}},
+		{name: "tag prefix", filter: model.TaskFilter{Tags: []string{"work"}, TagMatchesPrefix: true}, want: []string{"report", "slides"}},
+		{name: "unknown tag", filter: model.TaskFilter{Tags: []string{"garden"}}, want: nil},
+		{name: "no indexed criterion", filter: model.TaskFilter{Text: "s"}, want: []string{"groceries", "report", "slides", "taxes"}},
+		{name: "done", filter: model.TaskFilter{Status: &done}, want: []string{"taxes"}},
+		{name: "text in title or notes", filter: model.TaskFilter{Text: "REPORT"}, want: []string{"report", "slides"}},
+		{name: "text and status", filter: model.TaskFilter{Text: "port", Status: &today}, want: []string{"report"}},
+		{name: "text across words", filter: model.TaskFilter{Text: "the rep"}, want: []string{"slides"}},
+		{name: "unknown text", filter: model.TaskFilter{Text: "garden"}, want: nil},
+		{name: "fuzzy text", filter: model.TaskFilter{Text: "grc", Fuzzy: true}, want: []string{"groceries", "report", "slides", "taxes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, task := range x.Narrow(tasks, tt.filter) {
+				got = append(got, task.Title)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Narrow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if s := x.Stats(); s.Narrowed != 12 || s.Scanned != 2 {
+		t.Errorf("Stats() counted %d narrowed and %d scanned queries, want 12 and 2", s.Narrowed, s.Scanned)
+	}
+}
+
+// TestIndex_PutRemove verifies re-indexing a changed task drops its old
+// entries and removing it drops it entirely.
+func TestIndex_PutRemove(t *testing.T) {
+	x := NewIndex()
+	task := testutil.NewTask().WithTitle("mow").WithTags("work").Build()
+	x.Put(task)
+
+	task.Status = model.StatusToday
+	task.Tags = []string{"home"}
+	task.Title = "Rake"
+	x.Put(task)
+	s := x.Stats()
+	if s.Tasks != 1 || s.Statuses[model.StatusToday] != 1 || s.Statuses[model.StatusPool] != 0 || s.Tags["home"] != 1 || s.Tags["work"] != 0 || s.Grams != 2 {
+		t.Errorf("Stats() after update = %+v", s)
+	}
+	all := map[model.TaskID]*model.Task{task.ID: task}
+	if got := x.Narrow(all, model.TaskFilter{Text: "mow"}); len(got) != 0 {
+		t.Errorf("Narrow() by the old title = %v, want none", got)
+	}
+
+	x.Remove(task.ID)
+	x.Remove(task.ID)
+	if s := x.Stats(); s.Tasks != 0 || len(s.Statuses) != 0 || len(s.Tags) != 0 || s.Grams != 0 {
+		t.Errorf("Stats() after removal = %+v, want empty", s)
+	}
+}
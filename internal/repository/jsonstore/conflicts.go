@@ -0,0 +1,78 @@
+package jsonstore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"togo/internal/conflict"
+	"togo/internal/encryption"
+	"togo/internal/model"
+	"togo/internal/notestore"
+)
+
+// syncConflictInfix marks the copies Syncthing leaves beside a file two
+// devices changed at once, such as
+// tasks.sync-conflict-20240615-101500-ABCDEFG.json.
+const syncConflictInfix = ".sync-conflict-"
+
+// conflictCopies returns the sync conflict copies of the journal at path.
+func conflictCopies(path string) ([]string, error) {
+	dir, name := filepath.Split(path)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + syncConflictInfix
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var copies []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) && strings.HasSuffix(e.Name(), ext) {
+			copies = append(copies, filepath.Join(dir, e.Name()))
+		}
+	}
+	return copies, nil
+}
+
+// mergeCopies merges the tasks of each conflict copy into tasks, and
+// returns the conflicts left unresolved. No common ancestor is known, so
+// a field both sides hold differently goes to the later edit, or is left
+// unresolved with the local value when the edits tie. A task only in a
+// copy is kept, since it was either added on the other device or deleted
+// on this one, and dropping it could lose work. The copies' notes kept in
+// files are read from notes, the files being shared.
+func mergeCopies(tasks map[model.TaskID]*model.Task, copies []string, keyring *encryption.Keyring, notes *notestore.Store) ([]conflict.Entry, error) {
+	var entries []conflict.Entry
+	for _, path := range copies {
+		remote, _, err := readJournal(path, keyring, notes, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sync conflict copy: %w", err)
+		}
+		for id, theirs := range remote {
+			ours, ok := tasks[id]
+			switch {
+			case !ok:
+				tasks[id] = theirs
+				continue
+			case len(ours.Diff(theirs)) == 0 && ours.UpdatedAt.Equal(theirs.UpdatedAt):
+				ours.Version = max(ours.Version, theirs.Version)
+				continue
+			}
+			merged, entry, err := conflict.Merge(nil, ours, theirs, filepath.Base(path))
+			if err != nil {
+				return nil, err
+			}
+			tasks[id] = merged
+			if entry != nil {
+				entries = append(entries, *entry)
+			}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,184 @@
+package jsonstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"togo/internal/model"
+)
+
+// decodeJournal reads journal data in the current or an earlier format
+// and returns its tasks for which keep, if not nil, reports true, and
+// whether they were upgraded. It decodes one task at a time, so memory
+// grows with the tasks kept rather than the size of the journal.
+//
+// Format 0 is the journal object without a version, or a plain JSON array
+// of tasks, as written by hand or by scripts. Its tasks may lack an ID or
+// creation time, give "done": true instead of a status, or use the field
+// names "due", "created" and "completed". Upgraded tasks must all pass
+// Task.Validate, or the journal is reported as unreadable.
+func decodeJournal(r io.Reader, keep func(*model.Task) bool) (tasks []*model.Task, upgraded bool, err error) {
+	d := &journalDecoder{dec: json.NewDecoder(r), keep: keep, version: -1, now: model.Now()}
+	if err := d.decode(); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			err = fmt.Errorf("unexpected end of journal: %w", err)
+		}
+		return nil, false, err
+	}
+	return d.tasks, d.version < formatVersion, nil
+}
+
+// journalDecoder holds the state of one decodeJournal call.
+type journalDecoder struct {
+	dec  *json.Decoder
+	keep func(*model.Task) bool
+	now  time.Time
+
+	// version is the journal's format, or -1 until its key is read.
+	version int
+	// pending holds the tasks read before the version, which decide how
+	// they are decoded.
+	pending []json.RawMessage
+	tasks   []*model.Task
+	// index counts the tasks read, for error messages.
+	index int
+}
+
+// decode reads the whole journal.
+func (d *journalDecoder) decode() error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('['):
+		d.version = 0
+		if err := d.elements(); err != nil {
+			return err
+		}
+	case json.Delim('{'):
+		if err := d.fields(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("journal must be a JSON object, not %v", tok)
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after the journal")
+	}
+	return nil
+}
+
+// fields reads the journal object's members after its opening brace.
+func (d *journalDecoder) fields() error {
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case "version":
+			var v int
+			if err := d.dec.Decode(&v); err != nil {
+				return err
+			}
+			if v > formatVersion {
+				return fmt.Errorf("journal format %d is newer than this version of togo supports (%d)", v, formatVersion)
+			}
+			d.version = v
+		case "tasks":
+			open, err := d.dec.Token()
+			if err != nil {
+				return err
+			}
+			if open == nil {
+				continue
+			}
+			if open != json.Delim('[') {
+				return fmt.Errorf("journal tasks must be a JSON array, not %v", open)
+			}
+			if err := d.elements(); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := d.dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return err
+	}
+	if d.version < 0 {
+		d.version = 0
+	}
+	for _, raw := range d.pending {
+		if err := d.add(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elements reads the tasks array after its opening bracket, up to and
+// including its closing one.
+func (d *journalDecoder) elements() error {
+	for d.dec.More() {
+		if d.version == formatVersion {
+			var t *model.Task
+			if err := d.dec.Decode(&t); err != nil {
+				return err
+			}
+			d.keepTask(t)
+			continue
+		}
+		var raw json.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return err
+		}
+		if d.version < 0 {
+			d.pending = append(d.pending, raw)
+			continue
+		}
+		if err := d.add(raw); err != nil {
+			return err
+		}
+	}
+	_, err := d.dec.Token()
+	return err
+}
+
+// add decodes a task read before the journal's version was known, or
+// upgrades one of an earlier format.
+func (d *journalDecoder) add(raw json.RawMessage) error {
+	d.index++
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil
+	}
+	if d.version == formatVersion {
+		var t *model.Task
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return err
+		}
+		d.keepTask(t)
+		return nil
+	}
+	t, err := upgradeTask(raw, d.now)
+	if err != nil {
+		return fmt.Errorf("upgrading format %d journal: task %d: %w", d.version, d.index, err)
+	}
+	d.keepTask(t)
+	return nil
+}
+
+// keepTask adds t to the result if it is wanted.
+func (d *journalDecoder) keepTask(t *model.Task) {
+	if t != nil && (d.keep == nil || d.keep(t)) {
+		d.tasks = append(d.tasks, t)
+	}
+}
@@ -0,0 +1,710 @@
+// Package jsonstore persists tasks to a single JSON file, which keeps the
+// journal human-readable and easy to sync or put under version control.
+//
+// Every change is first appended to a write-ahead log beside the journal
+// and synced to disk before it is acknowledged. The log is emptied once
+// the journal has been rewritten, and replayed when the journal is next
+// opened if a crash interrupted the rewrite, so an accepted change
+// survives a power loss even while debounced writes are pending.
+//
+// Journals written in an earlier format are upgraded when opened and
+// rewritten in the current one, but only once every task in them passes
+// validation; with backups enabled, the original is backed up first.
+//
+// The journal is decoded as a stream, one task at a time, so Scan can
+// answer a query over a very large journal holding only the tasks that
+// match.
+//
+// Conflict copies a sync tool such as Syncthing leaves beside the journal
+// are merged into it task by task when it is next opened, and removed;
+// fields both copies changed at the same moment are recorded in a sidecar
+// (see package conflict) for the user to settle.
+//
+// With note files enabled, long notes are kept as Markdown files beside
+// the journal (see package notestore) and read back into the tasks as the
+// journal is read.
+package jsonstore
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"togo/internal/atomicfile"
+	"togo/internal/backup"
+	"togo/internal/conflict"
+	"togo/internal/encryption"
+	"togo/internal/filelock"
+	"togo/internal/model"
+	"togo/internal/notestore"
+	"togo/internal/persist"
+	"togo/internal/repository"
+)
+
+// FileName is the journal's name inside the data directory.
+const FileName = "tasks.json"
+
+// formatVersion is written to every journal so later releases can migrate
+// older files.
+const formatVersion = 1
+
+// journal is the on-disk layout.
+type journal struct {
+	Version int           `json:"version"`
+	Tasks   []*model.Task `json:"tasks"`
+}
+
+// Repository is a repository.TaskRepository backed by one JSON file. The
+// file is read on first access, and reread when another process changes
+// it, and rewritten in full, atomically, after each change, or after a
+// burst of changes when debounced; changes are logged durably in between
+// (see the package documentation). It is safe
+// for concurrent use within one process; across processes, reads and
+// writes hold an advisory lock on a sibling ".lock" file so they never
+// interleave.
+type Repository struct {
+	path string
+
+	// debounce coalesces writes when set; nil writes through on every change.
+	debounce *persist.Debouncer
+
+	// backupDir receives a copy of the journal before the first write, when
+	// set; backups then holds the retention policy applied afterwards.
+	backupDir string
+	backups   backup.Policy
+	backedUp  bool
+
+	// keyring encrypts the journal at rest when set.
+	keyring *encryption.Keyring
+
+	// notes keeps long notes in files when set; noteFiles holds the notes
+	// last read from or written to each task's file, so notes unchanged
+	// here are not written back over edits made with other tools.
+	notes     *notestore.Store
+	noteFiles map[model.TaskID]string
+
+	mu     sync.Mutex
+	loaded bool
+	tasks  map[model.TaskID]*model.Task
+	// seen is the journal and log as last read or written, to notice
+	// changes made to them by other processes.
+	seen version
+}
+
+var _ repository.TaskRepository = (*Repository)(nil)
+
+// New returns a repository stored at path that writes through on every
+// change. Nothing is read until the first call.
+func New(path string) *Repository {
+	return &Repository{path: path}
+}
+
+// NewDebounced returns a repository stored at path that coalesces bursts
+// of changes into one write (see persist.Debouncer). Background write
+// errors go to onError. Callers must Close it to write pending changes.
+func NewDebounced(path string, delay, maxWait time.Duration, onError func(error)) *Repository {
+	r := New(path)
+	r.debounce = persist.NewDebouncer(delay, maxWait, r.write, onError)
+	return r
+}
+
+// EnableBackups makes the repository copy the journal into dir before its
+// first write, then prune dir according to policy. Call it before use.
+func (r *Repository) EnableBackups(dir string, policy backup.Policy) {
+	r.backupDir = dir
+	r.backups = policy
+}
+
+// EnableEncryption makes the repository store the journal encrypted with
+// keyring. A plaintext journal is still read, and encrypted on the next
+// write. Call it before use.
+func (r *Repository) EnableEncryption(keyring *encryption.Keyring) {
+	r.keyring = keyring
+}
+
+// EnableNoteFiles makes the repository keep notes as long as notes asks
+// in files, and read the notes of tasks referencing a file back into them.
+// Call it before use.
+func (r *Repository) EnableNoteFiles(notes *notestore.Store) {
+	r.notes = notes
+}
+
+// Conflicts returns the sidecar listing the conflicts left unresolved by
+// merging sync conflict copies of the journal.
+func (r *Repository) Conflicts() *conflict.Sidecar {
+	return conflict.Open(conflict.SidecarPath(r.path), r.keyring)
+}
+
+// Path returns the journal file's location.
+func (r *Repository) Path() string {
+	return r.path
+}
+
+// Save implements repository.TaskRepository.
+func (r *Repository) Save(task *model.Task) error {
+	if err := repository.CheckSave("save", task); err != nil {
+		return err
+	}
+	saved := task.Clone()
+	if err := r.update(walRecord{Op: opSave, ID: task.ID, Task: saved}); err != nil {
+		return err
+	}
+	task.Version = saved.Version
+	return nil
+}
+
+// Get implements repository.TaskRepository.
+func (r *Repository) Get(id model.TaskID) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.NotFound("get", id)
+	}
+	return t.Clone(), nil
+}
+
+// Delete implements repository.TaskRepository.
+func (r *Repository) Delete(id model.TaskID) error {
+	return r.update(walRecord{Op: opDelete, ID: id})
+}
+
+// SaveAll implements repository.TaskRepository. The batch is logged as
+// one record and the journal rewritten once.
+func (r *Repository) SaveAll(tasks []*model.Task) error {
+	if err := repository.CheckBatch("save", tasks); err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	batch := make([]walRecord, len(tasks))
+	for i, t := range tasks {
+		batch[i] = walRecord{Op: opSave, ID: t.ID, Task: t.Clone()}
+	}
+	if err := r.update(walRecord{Op: opBatch, Batch: batch}); err != nil {
+		return err
+	}
+	for i, t := range tasks {
+		t.Version = batch[i].Task.Version
+	}
+	return nil
+}
+
+// DeleteMany implements repository.TaskRepository, logging the batch as
+// SaveAll does.
+func (r *Repository) DeleteMany(ids []model.TaskID) error {
+	if err := repository.CheckIDs("delete", ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	batch := make([]walRecord, len(ids))
+	for i, id := range ids {
+		batch[i] = walRecord{Op: opDelete, ID: id}
+	}
+	return r.update(walRecord{Op: opBatch, Batch: batch})
+}
+
+// List implements repository.TaskRepository.
+func (r *Repository) List(filter model.TaskFilter) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return repository.Query(r.all(), filter), nil
+}
+
+// Scan implements repository.Scanner. Unless the journal is already
+// loaded, or has sync conflict copies to merge, it is streamed from disk
+// keeping only the tasks matching filter, so duplicate IDs are reported
+// only among those.
+func (r *Repository) Scan(filter model.TaskFilter) ([]*model.Task, error) {
+	r.mu.Lock()
+	loaded := r.loaded
+	r.mu.Unlock()
+	copies, err := conflictCopies(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if loaded || len(copies) > 0 {
+		return r.List(filter)
+	}
+
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Shared)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+	tasks, _, err := readJournal(r.path, r.keyring, r.notes, filter.Matches)
+	if err != nil {
+		return nil, err
+	}
+	records, err := readWAL(r.walPath(), r.keyring)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		if rec.Op == opSave && !filter.Matches(rec.Task) {
+			delete(tasks, rec.ID)
+			continue
+		}
+		rec.apply(tasks)
+	}
+	return repository.Query(slices.Collect(maps.Values(tasks)), filter), nil
+}
+
+// Count implements repository.TaskRepository.
+func (r *Repository) Count(filter model.TaskFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.load(); err != nil {
+		return 0, err
+	}
+	return repository.CountMatching(r.all(), filter), nil
+}
+
+// Flush writes debounced changes now. It is a no-op for write-through
+// repositories.
+func (r *Repository) Flush() error {
+	if r.debounce == nil {
+		return nil
+	}
+	return r.debounce.Flush()
+}
+
+// Close writes debounced changes and stops accepting new ones.
+func (r *Repository) Close() error {
+	if r.debounce == nil {
+		return nil
+	}
+	return r.debounce.Close()
+}
+
+// update logs rec, applies it to the loaded tasks and persists the
+// result. If rec cannot be applied, nothing changes.
+func (r *Repository) update(rec walRecord) error {
+	r.mu.Lock()
+	if err := r.load(); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	if err := r.logChange(rec); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	rec.apply(r.tasks)
+	if r.debounce == nil {
+		defer r.mu.Unlock()
+		return r.writeLocked()
+	}
+	// The debouncer's flush takes the lock itself.
+	r.mu.Unlock()
+	return r.debounce.Mark()
+}
+
+// load reads the journal on first use, and again whenever another process
+// has changed it, recovering changes left in the write-ahead log by a
+// crash and upgrading a journal of an earlier format. r.mu must be held.
+func (r *Repository) load() error {
+	if r.loaded && r.onDisk().equal(r.seen) {
+		return nil
+	}
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Shared)
+	if err != nil {
+		return err
+	}
+	r.seen = r.onDisk()
+	tasks, stale, err := r.read()
+	lock.Release()
+	if err != nil {
+		return err
+	}
+	if stale {
+		if tasks, err = r.rewrite(); err != nil {
+			return err
+		}
+	}
+	r.tasks = tasks
+	r.loaded = true
+	return nil
+}
+
+// read returns the journal with the write-ahead log replayed over it, and
+// whether the file needs rewriting: because changes were replayed, the
+// journal was upgraded or sync conflict copies of it wait to be merged.
+// The journal lock must be held.
+func (r *Repository) read() (map[model.TaskID]*model.Task, bool, error) {
+	tasks, upgraded, err := readJournal(r.path, r.keyring, r.notes, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	r.noteFiles = map[model.TaskID]string{}
+	for id, t := range tasks {
+		if t.NotesFile != "" {
+			r.noteFiles[id] = t.Notes
+		}
+	}
+	records, err := readWAL(r.walPath(), r.keyring)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, rec := range records {
+		rec.apply(tasks)
+	}
+	copies, err := conflictCopies(r.path)
+	if err != nil {
+		return nil, false, err
+	}
+	return tasks, upgraded || len(records) > 0 || len(copies) > 0, nil
+}
+
+// rewrite writes the replayed or upgraded journal, with any sync conflict
+// copies merged in, and empties the write-ahead log. It rereads both under
+// an exclusive lock, since another process may have rewritten or changed
+// them meanwhile. Conflicts the merge leaves unresolved are recorded in
+// the journal's sidecar, and the copies removed.
+func (r *Repository) rewrite() (map[model.TaskID]*model.Task, error) {
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+	r.seen = r.onDisk()
+	tasks, stale, err := r.read()
+	if err != nil || !stale {
+		return tasks, err
+	}
+	copies, err := conflictCopies(r.path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := mergeCopies(tasks, copies, r.keyring, r.notes)
+	if err != nil {
+		return nil, err
+	}
+	r.tasks = tasks
+	if err := r.persistLocked(); err != nil {
+		return nil, err
+	}
+	if err := r.Conflicts().Add(entries...); err != nil {
+		return nil, err
+	}
+	for _, path := range copies {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+// all returns the loaded tasks in no particular order. r.mu must be held.
+func (r *Repository) all() []*model.Task {
+	out := make([]*model.Task, 0, len(r.tasks))
+	for _, t := range r.tasks {
+		out = append(out, t)
+	}
+	return out
+}
+
+// write persists the loaded tasks; it is the debouncer's flush function.
+func (r *Repository) write() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.writeLocked()
+}
+
+// writeLocked persists the loaded tasks, first catching up with changes
+// other processes made meanwhile. r.mu must be held.
+func (r *Repository) writeLocked() error {
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	if err := r.catchUp(); err != nil {
+		return err
+	}
+	return r.persistLocked()
+}
+
+// persistLocked writes the loaded tasks while the journal lock is held.
+// r.mu must be held.
+func (r *Repository) persistLocked() error {
+	data, err := r.encode()
+	if err != nil {
+		return err
+	}
+	return r.persistData(data)
+}
+
+// persistData replaces the journal with data, then empties the write-ahead
+// log, whose changes data includes. The journal lock must be held.
+func (r *Repository) persistData(data []byte) error {
+	if err := r.backupOnce(); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(r.path, data); err != nil {
+		return err
+	}
+	if err := os.Remove(r.walPath()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	r.seen = r.onDisk()
+	return nil
+}
+
+// encode renders the loaded tasks as the journal file, encrypted if
+// enabled, first writing the notes kept in files that changed. r.mu must
+// be held.
+func (r *Repository) encode() ([]byte, error) {
+	tasks := r.all()
+	repository.SortByCreation(tasks)
+	if r.notes != nil {
+		for i, t := range tasks {
+			stored, err := r.storeNotes(t)
+			if err != nil {
+				return nil, err
+			}
+			tasks[i] = stored
+		}
+	}
+	return r.encodeTasks(tasks)
+}
+
+// storeNotes returns t as the journal holds it: with its notes replaced
+// by a reference to their file if they are long, writing the file unless
+// the notes are as last read from or written to it. The loaded task keeps
+// its notes and takes the reference. r.mu must be held.
+func (r *Repository) storeNotes(t *model.Task) (*model.Task, error) {
+	if notes, ok := r.noteFiles[t.ID]; ok && t.NotesFile != "" && notes == t.Notes {
+		stored := t.Clone()
+		stored.Notes = ""
+		return stored, nil
+	}
+	stored, err := r.notes.Externalize(t)
+	if err != nil {
+		return nil, err
+	}
+	t.NotesFile = stored.NotesFile
+	if r.noteFiles == nil {
+		r.noteFiles = map[model.TaskID]string{}
+	}
+	delete(r.noteFiles, t.ID)
+	if t.NotesFile != "" {
+		r.noteFiles[t.ID] = t.Notes
+	}
+	return stored, nil
+}
+
+// encodeTasks renders tasks, in order, as the journal file, encrypted if
+// enabled.
+func (r *Repository) encodeTasks(tasks []*model.Task) ([]byte, error) {
+	data, err := json.MarshalIndent(journal{Version: formatVersion, Tasks: tasks}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, '\n')
+	if r.keyring != nil {
+		return r.keyring.Encrypt(data)
+	}
+	return data, nil
+}
+
+// logChange appends rec to the write-ahead log and syncs it to disk, first
+// catching up with changes other processes made since the journal was
+// read. Deleting a task that is not stored, or saving a stale copy of
+// one, even within a batch, logs nothing and fails; saves are logged at
+// the task's next version. r.mu must be held.
+func (r *Repository) logChange(rec walRecord) error {
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	if err := r.catchUp(); err != nil {
+		return err
+	}
+	for _, c := range rec.changes() {
+		stored, ok := r.tasks[c.ID]
+		switch {
+		case c.Op == opDelete && !ok:
+			return repository.NotFound("delete", c.ID)
+		case c.Op == opSave:
+			if err := repository.CheckVersion("save", stored, c.Task); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range rec.changes() {
+		if c.Op == opSave {
+			c.Task.Version++
+		}
+	}
+	if err := appendWAL(r.walPath(), rec, r.keyring); err != nil {
+		return err
+	}
+	r.seen = r.onDisk()
+	return nil
+}
+
+// catchUp rereads the journal and log if another process changed them
+// since they were last read or written. Changes of this repository not yet
+// written to the journal are in the log, so they survive. r.mu and the
+// journal lock must be held.
+func (r *Repository) catchUp() error {
+	now := r.onDisk()
+	if now.equal(r.seen) {
+		return nil
+	}
+	tasks, _, err := r.read()
+	if err != nil {
+		return err
+	}
+	r.tasks, r.seen = tasks, now
+	return nil
+}
+
+// backupOnce backs up the journal if backups are enabled and this is the
+// repository's first write. The journal lock must be held.
+func (r *Repository) backupOnce() error {
+	if r.backupDir == "" || r.backedUp {
+		return nil
+	}
+	now := model.Now()
+	if _, _, err := backup.Create(r.path, r.backupDir, now); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	if _, err := backup.Prune(r.backupDir, r.backups, now); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	r.backedUp = true
+	return nil
+}
+
+// version identifies the journal and log files on disk; nil means missing.
+type version struct {
+	journal, wal fs.FileInfo
+}
+
+// onDisk returns the current version of the journal and log.
+func (r *Repository) onDisk() version {
+	var v version
+	v.journal, _ = os.Stat(r.path)
+	v.wal, _ = os.Stat(r.walPath())
+	return v
+}
+
+// equal reports whether v and o are the same version.
+func (v version) equal(o version) bool {
+	return sameFile(v.journal, o.journal) && sameFile(v.wal, o.wal)
+}
+
+// sameFile reports whether a and b describe the same file, unmodified.
+func sameFile(a, b fs.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+// lockPath returns the file locked around journal reads and writes.
+func (r *Repository) lockPath() string {
+	return r.path + ".lock"
+}
+
+// walPath returns the journal's write-ahead log.
+func (r *Repository) walPath() string {
+	return LogPath(r.path)
+}
+
+// LogPath returns the write-ahead log of the journal at path. Tools that
+// replace the journal wholesale, such as a backup restore, must remove it
+// while holding the journal's lock.
+func LogPath(path string) string {
+	return path + ".wal"
+}
+
+// readJournal decodes the journal at path, decrypting it with keyring if it
+// is encrypted, and reports whether it was upgraded from an earlier format
+// (see decodeJournal). Notes kept in files of notes, if not nil, are read
+// into the tasks before keep sees them. Only tasks for which keep reports
+// true are returned, or all of them when keep is nil. A missing file is an
+// empty journal.
+func readJournal(path string, keyring *encryption.Keyring, notes *notestore.Store, keep func(*model.Task) bool) (map[model.TaskID]*model.Task, bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[model.TaskID]*model.Task{}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	src, err := plaintext(f, keyring)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var notesErr error
+	list, upgraded, err := decodeJournal(src, func(t *model.Task) bool {
+		if err := resolveNotes(notes, t); err != nil {
+			notesErr = cmp.Or(notesErr, err)
+		}
+		return keep == nil || keep(t)
+	})
+	if err == nil {
+		err = notesErr
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	tasks := make(map[model.TaskID]*model.Task, len(list))
+	for _, t := range list {
+		if _, dup := tasks[t.ID]; dup {
+			return nil, false, fmt.Errorf("%s: %w", path, &model.TaskError{ID: t.ID, Op: "load", Err: model.ErrDuplicateTaskID})
+		}
+		tasks[t.ID] = t
+	}
+	return tasks, upgraded, nil
+}
+
+// resolveNotes reads t's notes from their file in notes, if it has one and
+// notes is not nil. A missing file, or a reference to another file than
+// the task's own, leaves the notes empty for togo doctor to report.
+func resolveNotes(notes *notestore.Store, t *model.Task) error {
+	if notes == nil {
+		return nil
+	}
+	if err := notes.Resolve(t); err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, notestore.ErrForeignFile) {
+		return err
+	}
+	return nil
+}
+
+// plaintext returns a reader of the journal read from r, decrypting it as
+// it is read if it is encrypted.
+func plaintext(r io.Reader, keyring *encryption.Keyring) (io.Reader, error) {
+	buf := bufio.NewReader(r)
+	head, _ := buf.Peek(64)
+	if !encryption.IsEncrypted(head) {
+		return buf, nil
+	}
+	if keyring == nil {
+		return nil, errors.New("journal is encrypted but no key is configured")
+	}
+	return keyring.NewReader(buf)
+}
@@ -0,0 +1,751 @@
+package jsonstore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/backup"
+	"togo/internal/encryption"
+	"togo/internal/filelock"
+	"togo/internal/model"
+	"togo/internal/notestore"
+	"togo/internal/repository"
+	"togo/internal/repository/repositorytest"
+	"togo/internal/testutil"
+)
+
+// TestRepository_Contract runs the shared repository contract.
+func TestRepository_Contract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.TaskRepository {
+		return New(filepath.Join(t.TempDir(), FileName))
+	})
+}
+
+// TestRepository_PersistsAcrossInstances verifies every field survives a
+// reload from disk.
+func TestRepository_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", FileName)
+	due := time.Date(2024, 6, 14, 17, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	want := testutil.NewTask().
+		WithTitle("Renew passport").
+		WithNotes("bring photos").
+		WithTags("admin").
+		WithDue(due).
+		WithPriority(model.PriorityHigh).
+		WithEstimate(time.Hour).
+		Build()
+	want.Seq = 7
+	want.SnoozedUntil = &due
+
+	if err := New(path).Save(want); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	got, err := New(path).Get(want.ID)
+	if err != nil {
+		t.Fatalf("Get() after reload error: %v", err)
+	}
+	if diff := want.Diff(got); len(diff) > 0 {
+		t.Errorf("reloaded task differs: %v", diff)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("timestamps = %v/%v, want %v/%v", got.CreatedAt, got.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+	}
+}
+
+// TestRepository_NoteFiles verifies long notes are kept in files, read
+// back on load, not written over edits made elsewhere, and inlined again
+// once short.
+func TestRepository_NoteFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	open := func() *Repository {
+		r := New(path)
+		r.EnableNoteFiles(notestore.New(filepath.Join(dir, notestore.DirName), 20))
+		return r
+	}
+	long := "# Plan\n\nA note long enough for a file."
+	task := testutil.NewTask().WithTitle("Write design doc").WithNotes(long).Build()
+	other := testutil.NewTask().WithTitle("Buy milk").Build()
+	repo := open()
+	if err := repo.Save(task); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	file := filepath.Join(dir, notestore.DirName, task.ID.String()+".md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "A note long enough") || !strings.Contains(string(data), `"notes_file"`) {
+		t.Errorf("journal holds the notes instead of a reference:\n%s", data)
+	}
+	if notes, err := os.ReadFile(file); err != nil || string(notes) != long {
+		t.Fatalf("notes file = %q, %v; want %q", notes, err, long)
+	}
+
+	for name, list := range map[string]func(*Repository) ([]*model.Task, error){
+		"List": func(r *Repository) ([]*model.Task, error) { return r.List(model.TaskFilter{}) },
+		"Scan": func(r *Repository) ([]*model.Task, error) { return r.Scan(model.TaskFilter{}) },
+	} {
+		tasks, err := list(open())
+		if err != nil || len(tasks) != 1 || tasks[0].Notes != long {
+			t.Errorf("%s() after reload = %v, %v; want the task with its notes", name, tasks, err)
+		}
+	}
+
+	edited := long + "\nEdited in another tool."
+	if err := os.WriteFile(file, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(other); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if notes, _ := os.ReadFile(file); string(notes) != edited {
+		t.Errorf("notes file = %q after saving another task, want the edit kept", notes)
+	}
+
+	short, err := open().Get(task.ID)
+	if err != nil || short.Notes != edited {
+		t.Fatalf("Get() = %v, %v; want the edited notes", short, err)
+	}
+	short.Notes = "short now"
+	if err := repo.Save(short); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("notes file still there after the notes shrank: %v", err)
+	}
+	if got, err := open().Get(task.ID); err != nil || got.Notes != "short now" || got.NotesFile != "" {
+		t.Errorf("Get() = %+v, %v; want the short notes inline", got, err)
+	}
+}
+
+// TestRepository_LoadsLazily verifies the file is not read until first use
+// and that a missing file is an empty journal.
+func TestRepository_LoadsLazily(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo := New(path)
+
+	if err := os.WriteFile(path, []byte(`{"version":1,"tasks":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := repo.Count(model.TaskFilter{}); err != nil || n != 0 {
+		t.Fatalf("Count() = %d, %v", n, err)
+	}
+
+	empty := New(filepath.Join(t.TempDir(), "missing.json"))
+	if tasks, err := empty.List(model.TaskFilter{}); err != nil || len(tasks) != 0 {
+		t.Errorf("List() on missing file = %v, %v", tasks, err)
+	}
+}
+
+// TestRepository_LoadErrors verifies corrupt journals are reported.
+func TestRepository_LoadErrors(t *testing.T) {
+	id := model.NewTaskID().String()
+	task := `{"id":"` + id + `","created_at":"2024-06-10T09:00:00Z","title":"t","status":"pool","deferred_count":0}`
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+		wantMsg string
+	}{
+		{"malformed", `{"tasks":[`, nil, "unexpected end"},
+		{"unknown status", `{"version":1,"tasks":[{"id":"` + id + `","status":"later"}]}`, model.ErrInvalidStatus, ""},
+		{"duplicate id", `{"version":1,"tasks":[` + task + `,` + task + `]}`, model.ErrDuplicateTaskID, ""},
+		{"newer format", `{"version":99,"tasks":[]}`, nil, "newer than this version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := New(path).List(model.TaskFilter{})
+			if err == nil || !strings.Contains(err.Error(), path) {
+				t.Fatalf("List() error = %v, want one naming %s", err, path)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("List() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("List() error = %v, want containing %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestRepository_UpgradesLegacyFormats verifies journals of earlier
+// formats are read, rewritten in the current format, and left untouched
+// when any task in them is invalid.
+func TestRepository_UpgradesLegacyFormats(t *testing.T) {
+	id := model.NewTaskID().String()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string // title:status of each task, by creation
+		wantErr string
+	}{
+		{
+			name:    "plain array",
+			content: `[{"title":"Renew passport","created":"2024-06-10T09:00:00Z"},{"title":"File taxes","done":true,"created":"2024-06-11T09:00:00Z"}]`,
+			want:    "Renew passport:pool,File taxes:done",
+		},
+		{
+			name:    "unversioned object",
+			content: `{"tasks":[{"id":"` + id + `","title":"Call plumber","status":"today","due":"2024-06-12T00:00:00Z","created_at":"2024-06-10T09:00:00Z"}]}`,
+			want:    "Call plumber:today",
+		},
+		{
+			name:    "invalid task",
+			content: `[{"title":"Renew passport"},{"title":"  "}]`,
+			wantErr: "task 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := New(path).List(model.TaskFilter{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("List() error = %v, want containing %q", err, tt.wantErr)
+				}
+				if data, _ := os.ReadFile(path); string(data) != tt.content {
+					t.Errorf("journal rewritten despite the error: %s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.Title+":"+string(task.Status))
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("tasks = %v, want %s", got, tt.want)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `"version": 1`) {
+				t.Errorf("journal not rewritten in the current format:\n%s", data)
+			}
+			again, err := New(path).List(model.TaskFilter{})
+			if err != nil || len(again) != len(tasks) || again[0].ID != tasks[0].ID {
+				t.Errorf("reopened journal = %v, %v; want the upgraded tasks", again, err)
+			}
+		})
+	}
+}
+
+// TestRepository_SalvageAndReplace verifies a journal that fails to load
+// can still be read for repair, and replaced with the repaired tasks.
+func TestRepository_SalvageAndReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	id := model.NewTaskID().String()
+	content := `{"version":1,"tasks":[
+		{"id":"` + id + `","title":"Renew passport","status":"pool","created_at":"2024-06-10T09:00:00Z"},
+		{"id":"` + id + `","title":"Renew passport","status":"archived","created_at":"2024-06-10T09:00:00Z"}]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logged := testutil.NewTask().WithTitle("Call plumber").Build()
+	if err := appendWAL(LogPath(path), walRecord{Op: opSave, ID: logged.ID, Task: logged}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := New(path)
+	if _, err := repo.List(model.TaskFilter{}); err == nil {
+		t.Fatal("List() succeeded on a journal with duplicate IDs")
+	}
+	tasks, err := repo.Salvage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 || tasks[1].Status != "archived" || tasks[2].Title != "Call plumber" {
+		t.Fatalf("Salvage() = %v, want both copies and the logged task", tasks)
+	}
+
+	if err := repo.Replace(tasks[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(tasks[2].ID); !errors.Is(err, model.ErrInvalidStatus) {
+		t.Errorf("Get() after replacing with an invalid task: %v, want %v", err, model.ErrInvalidStatus)
+	}
+	if err := repo.Replace([]*model.Task{tasks[0], tasks[2]}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := New(path).Count(model.TaskFilter{}); err != nil || n != 2 {
+		t.Errorf("Count() after Replace = %d, %v; want 2", n, err)
+	}
+	if _, err := os.Stat(LogPath(path)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("write-ahead log left after Replace: %v", err)
+	}
+}
+
+// TestDecodeJournal verifies the streaming decoder accepts the layouts
+// Unmarshal did, keeps only wanted tasks, and rejects trailing data.
+func TestDecodeJournal(t *testing.T) {
+	task := func(title, status string) string {
+		return `{"id":"` + model.NewTaskID().String() + `","created_at":"2024-06-10T09:00:00Z","title":"` + title + `","status":"` + status + `"}`
+	}
+	today := func(t *model.Task) bool { return t.Status == model.StatusToday }
+
+	tests := []struct {
+		name         string
+		content      string
+		keep         func(*model.Task) bool
+		want         string // titles, comma-separated
+		wantUpgraded bool
+		wantErr      string
+	}{
+		{name: "current", content: `{"version":1,"tasks":[` + task("a", "pool") + `,null,` + task("b", "today") + `]}`, want: "a,b"},
+		{name: "kept only", content: `{"version":1,"tasks":[` + task("a", "pool") + `,` + task("b", "today") + `]}`, keep: today, want: "b"},
+		{name: "version last", content: `{"tasks":[` + task("a", "pool") + `],"version":1}`, want: "a"},
+		{name: "unknown members", content: `{"version":1,"synced":{"at":"x"},"tasks":[` + task("a", "pool") + `]}`, want: "a"},
+		{name: "no tasks", content: `{"version":1,"tasks":null}`},
+		{name: "format 0", content: `[{"title":"a","done":true}]`, want: "a", wantUpgraded: true},
+		{name: "trailing data", content: `{"version":1,"tasks":[]} {}`, wantErr: "after the journal"},
+		{name: "truncated", content: `{"version":1,"tasks":[` + task("a", "pool"), wantErr: "unexpected end"},
+		{name: "not an object", content: `"tasks"`, wantErr: "JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, upgraded, err := decodeJournal(strings.NewReader(tt.content), tt.keep)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decodeJournal() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			if got := strings.Join(titles, ","); got != tt.want || upgraded != tt.wantUpgraded {
+				t.Errorf("decodeJournal() = %q, upgraded %v; want %q, %v", got, upgraded, tt.want, tt.wantUpgraded)
+			}
+		})
+	}
+}
+
+// TestRepository_Scan verifies Scan matches List, including changes still
+// in the write-ahead log, without loading the journal.
+func TestRepository_Scan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	moved := testutil.NewTask().WithTitle("Call plumber").WithStatus(model.StatusToday).Build()
+	testutil.MustSeed(t, New(path), testutil.Tasks(
+		testutil.NewTask().WithTitle("Renew passport").WithStatus(model.StatusToday),
+		testutil.NewTask().WithTitle("File taxes"),
+	)...)
+	testutil.MustSeed(t, New(path), moved)
+	moved.Status = model.StatusPool
+	if err := appendWAL(LogPath(path), walRecord{Op: opSave, ID: moved.ID, Task: moved}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	today := model.StatusToday
+	for _, filter := range []model.TaskFilter{{}, {Status: &today}, {Text: "a", Limit: 1}} {
+		repo := New(path)
+		scanned, err := repo.Scan(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.loaded {
+			t.Errorf("Scan(%+v) loaded the journal", filter)
+		}
+		listed, err := New(path).List(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scanned) != len(listed) {
+			t.Fatalf("Scan(%+v) = %d tasks, List() = %d", filter, len(scanned), len(listed))
+		}
+		for i := range scanned {
+			if scanned[i].Title != listed[i].Title {
+				t.Errorf("Scan(%+v)[%d] = %q, List() has %q", filter, i, scanned[i].Title, listed[i].Title)
+			}
+		}
+	}
+}
+
+// TestRepository_AtomicWrite verifies no temporary files are left behind
+// beside the journal and its lock file, and the journal is versioned.
+func TestRepository_AtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	if err := New(path).Save(testutil.NewTask().Build()); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name() != FileName || entries[1].Name() != FileName+".lock" {
+		t.Errorf("data directory holds %v, want only %s and its lock", entries, FileName)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "{\n  \"version\": 1,") {
+		t.Errorf("journal does not start with its version:\n%s", data)
+	}
+}
+
+// TestRepository_WaitsForLock verifies a write blocks while another holder,
+// such as a second togo process, has the journal locked.
+func TestRepository_WaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	held, err := filelock.Acquire(path+".lock", filelock.Exclusive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- New(path).Save(testutil.NewTask().Build()) }()
+	select {
+	case err := <-done:
+		t.Fatalf("Save() returned %v while the journal was locked", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	held.Release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Save() still blocked after the lock was released")
+	}
+}
+
+// TestRepository_Backups verifies the journal is backed up once, before
+// the first write of a session.
+func TestRepository_Backups(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, FileName)
+	dir := filepath.Join(root, "backups")
+	if err := New(path).Save(testutil.NewTask().Build()); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+
+	repo := New(path)
+	repo.EnableBackups(dir, backup.DefaultPolicy())
+	for range 2 {
+		if err := repo.Save(testutil.NewTask().Build()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := backup.List(dir)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("backups = %v, %v; want one", backups, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, backups[0].Name))
+	if string(got) != string(before) {
+		t.Errorf("backup holds\n%s\nwant the journal before the session\n%s", got, before)
+	}
+}
+
+// TestRepository_Encrypted verifies an encrypted journal hides its tasks,
+// needs the key to be read back and replaces a plaintext journal.
+func TestRepository_Encrypted(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, FileName)
+	keyPath := filepath.Join(root, "togo.key")
+	if err := encryption.GenerateKeyFile(keyPath); err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := encryption.LoadKeyFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := testutil.NewTask().WithTitle("Written before encryption").Build()
+	if err := New(path).Save(plain); err != nil {
+		t.Fatal(err)
+	}
+	repo := New(path)
+	repo.EnableEncryption(keyring)
+	secret := testutil.NewTask().WithTitle("Quarterly salary review").Build()
+	if err := repo.Save(secret); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !encryption.IsEncrypted(data) || strings.Contains(string(data), "salary") {
+		t.Fatalf("journal is not encrypted:\n%s", data)
+	}
+
+	reopened := New(path)
+	reopened.EnableEncryption(keyring)
+	if n, err := reopened.Count(model.TaskFilter{}); err != nil || n != 2 {
+		t.Errorf("Count() with the key = %d, %v; want 2", n, err)
+	}
+	if _, err := New(path).Get(secret.ID); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("Get() without the key error = %v, want an encrypted journal error", err)
+	}
+
+	wal := filepath.Join(root, "encrypted.wal")
+	if err := appendWAL(wal, walRecord{Op: opSave, ID: secret.ID, Task: secret}, keyring); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(wal); strings.Contains(string(data), "salary") {
+		t.Errorf("write-ahead log holds plaintext:\n%s", data)
+	}
+	if records, err := readWAL(wal, keyring); err != nil || len(records) != 1 || records[0].Task.Title != secret.Title {
+		t.Errorf("readWAL() = %v, %v", records, err)
+	}
+}
+
+// TestRepository_ReplaysWAL verifies changes logged before a crash
+// interrupted the journal rewrite are recovered on the next open, and a
+// record cut short by the crash is ignored.
+func TestRepository_ReplaysWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	kept := testutil.NewTask().WithTitle("kept").Build()
+	gone := testutil.NewTask().WithTitle("gone").Build()
+	if err := New(path).Save(kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(path).Save(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash after logging, before rewriting the journal.
+	added := testutil.NewTask().WithTitle("accepted before the crash").Build()
+	for _, rec := range []walRecord{{Op: opSave, ID: added.ID, Task: added}, {Op: opDelete, ID: gone.ID}} {
+		if err := appendWAL(path+".wal", rec, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, _ := os.OpenFile(path+".wal", os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"op":"save","id":`)
+	f.Close()
+
+	tasks, err := New(path).List(model.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	if strings.Join(titles, ",") != "kept,accepted before the crash" {
+		t.Errorf("tasks after recovery = %v", titles)
+	}
+	if _, err := os.Stat(path + ".wal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("log not emptied after recovery: %v", err)
+	}
+	if n, err := New(path).Count(model.TaskFilter{}); err != nil || n != 2 {
+		t.Errorf("journal after recovery holds %d tasks, %v; want 2", n, err)
+	}
+}
+
+// TestRepository_BatchesAreAtomic verifies a batch is logged as one record,
+// so a crash while logging it loses all of it or none.
+func TestRepository_BatchesAreAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo := NewDebounced(path, time.Hour, time.Hour, nil)
+	tasks := testutil.Tasks(testutil.NewTask(), testutil.NewTask(), testutil.NewTask())
+	if err := repo.SaveAll(tasks); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(LogPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Fatalf("SaveAll() logged %d lines, want 1", lines)
+	}
+
+	// Cut the record short, as a crash mid-append would.
+	if err := os.WriteFile(LogPath(path), data[:len(data)/2], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := New(path).Count(model.TaskFilter{}); err != nil || n != 0 {
+		t.Errorf("torn batch recovered %d tasks, %v; want 0", n, err)
+	}
+	if err := os.WriteFile(LogPath(path), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := New(path).Count(model.TaskFilter{}); err != nil || n != 3 {
+		t.Errorf("logged batch recovered %d tasks, %v; want 3", n, err)
+	}
+}
+
+// TestRepository_SeesOtherWriters verifies a repository picks up changes
+// another process made to the journal instead of overwriting them, even
+// with its own changes pending.
+func TestRepository_SeesOtherWriters(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(path string) *Repository
+	}{
+		{name: "write-through", open: New},
+		{name: "debounced", open: func(path string) *Repository {
+			return NewDebounced(path, time.Hour, time.Hour, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			mine, other := tt.open(path), New(path)
+			first := testutil.NewTask().WithTitle("first").Build()
+			if err := mine.Save(first); err != nil {
+				t.Fatal(err)
+			}
+			if err := other.Save(testutil.NewTask().WithTitle("elsewhere").Build()); err != nil {
+				t.Fatal(err)
+			}
+			if n, err := mine.Count(model.TaskFilter{}); err != nil || n != 2 {
+				t.Errorf("Count() = %d, %v; want the other writer's task too", n, err)
+			}
+			if err := other.Delete(first.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := mine.Save(testutil.NewTask().WithTitle("second").Build()); err != nil {
+				t.Fatal(err)
+			}
+			if err := mine.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			tasks, err := New(path).List(model.TaskFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, task := range tasks {
+				titles = append(titles, task.Title)
+			}
+			if strings.Join(titles, ",") != "elsewhere,second" {
+				t.Errorf("journal holds %v, want elsewhere,second", titles)
+			}
+		})
+	}
+}
+
+// TestRepository_RejectsStaleAcrossWriters verifies a save from another
+// process is not overwritten by a copy read before it.
+func TestRepository_RejectsStaleAcrossWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	mine, other := New(path), New(path)
+	task := testutil.NewTask().WithTitle("Draft").Build()
+	if err := mine.Save(task); err != nil {
+		t.Fatal(err)
+	}
+	theirs, err := other.Get(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	theirs.Title = "Theirs"
+	if err := other.Save(theirs); err != nil {
+		t.Fatal(err)
+	}
+
+	task.Title = "Mine"
+	if err := mine.Save(task); !errors.Is(err, model.ErrStaleTask) {
+		t.Fatalf("Save() error = %v, want ErrStaleTask", err)
+	}
+	got, err := New(path).Get(task.ID)
+	if err != nil || got.Title != "Theirs" {
+		t.Errorf("Get() = %v, %v; want the other writer's title", got, err)
+	}
+}
+
+// TestRepository_MergesSyncConflictCopies verifies a sync tool's conflict
+// copy of the journal is merged in when the journal is opened, with ties
+// recorded in the sidecar instead of settled either way.
+func TestRepository_MergesSyncConflictCopies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	tied := testutil.NewTask().WithTitle("Renew passport").Build()
+	later := testutil.NewTask().WithTitle("Call plumber").Build()
+	testutil.MustSeed(t, New(path), tied, later)
+
+	theirTied, theirLater := tied.Clone(), later.Clone()
+	theirTied.Title = "Renew passports"
+	theirLater.Title = "Call the plumber"
+	theirLater.UpdatedAt = theirLater.UpdatedAt.Add(time.Hour)
+	added := testutil.NewTask().WithTitle("Added on the laptop").Build()
+	data, err := json.Marshal(journal{Version: formatVersion, Tasks: []*model.Task{theirTied, theirLater, added}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyPath := filepath.Join(filepath.Dir(path), "tasks.sync-conflict-20240615-101500-ABCDEFG.json")
+	if err := os.WriteFile(copyPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := New(path)
+	tests := []struct {
+		id    model.TaskID
+		title string
+	}{
+		{id: tied.ID, title: "Renew passport"},
+		{id: later.ID, title: "Call the plumber"},
+		{id: added.ID, title: "Added on the laptop"},
+	}
+	for _, tt := range tests {
+		got, err := repo.Get(tt.id)
+		if err != nil || got.Title != tt.title {
+			t.Errorf("Get() = %v, %v; want %q", got, err, tt.title)
+		}
+	}
+	if _, err := os.Stat(copyPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("conflict copy left behind: %v", err)
+	}
+	entries, err := repo.Conflicts().Entries()
+	if err != nil || len(entries) != 1 || entries[0].ID != tied.ID {
+		t.Fatalf("Conflicts().Entries() = %v, %v; want the tied title", entries, err)
+	}
+	if entries[0].Source != filepath.Base(copyPath) {
+		t.Errorf("conflict source = %q, want the copy's name", entries[0].Source)
+	}
+}
+
+// TestRepository_Debounced verifies changes reach the file on Flush and
+// Close rather than on every save.
+func TestRepository_Debounced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	repo := NewDebounced(path, time.Hour, time.Hour, nil)
+
+	task := testutil.NewTask().Build()
+	if err := repo.Save(task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no write before flush, stat error = %v", err)
+	}
+	if got, err := repo.Get(task.ID); err != nil || got.ID != task.ID {
+		t.Fatalf("Get() before flush = %v, %v", got, err)
+	}
+	if _, err := New(path).Get(task.ID); err != nil {
+		t.Fatalf("pending change not recoverable from the log: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if _, err := New(path).Get(task.ID); err != nil {
+		t.Errorf("task not written on Close: %v", err)
+	}
+}
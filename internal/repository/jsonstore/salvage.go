@@ -0,0 +1,133 @@
+package jsonstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"togo/internal/encryption"
+	"togo/internal/filelock"
+	"togo/internal/model"
+)
+
+// Salvage reads the journal as it is, for a checker to inspect, where
+// loading it would fail: tasks are returned in file order, duplicates and
+// invalid tasks included, with the write-ahead log replayed over them. A
+// status togo does not know is kept, so Validate reports it.
+func (r *Repository) Salvage() ([]*model.Task, error) {
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Shared)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+	tasks, err := salvageJournal(r.path, r.keyring)
+	if err != nil {
+		return nil, err
+	}
+	records, err := readWAL(r.walPath(), r.keyring)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		tasks = replay(tasks, rec)
+	}
+	return tasks, nil
+}
+
+// Replace stores tasks, in order, as the whole journal, such as a
+// checker's repairs of what Salvage returned. They are written as given,
+// so problems the caller left unrepaired are still reported when the
+// journal is next loaded.
+func (r *Repository) Replace(tasks []*model.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lock, err := filelock.Acquire(r.lockPath(), filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	data, err := r.encodeTasks(tasks)
+	if err != nil {
+		return err
+	}
+	if err := r.persistData(data); err != nil {
+		return err
+	}
+	r.tasks, r.loaded = nil, false
+	return nil
+}
+
+// salvageJournal decodes the journal at path without validating its
+// tasks. Journals of an earlier format go through the usual upgrade.
+func salvageJournal(path string, keyring *encryption.Keyring) ([]*model.Task, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(data) {
+		if keyring == nil {
+			return nil, fmt.Errorf("%s: journal is encrypted but no key is configured", path)
+		}
+		if data, err = keyring.Decrypt(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var j struct {
+		Version int               `json:"version"`
+		Tasks   []json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil || j.Version != formatVersion {
+		tasks, _, err := decodeJournal(bytes.NewReader(data), nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return tasks, nil
+	}
+
+	defer model.SetLenientStatusDecoding(true)()
+	var tasks []*model.Task
+	for i, raw := range j.Tasks {
+		var t *model.Task
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, fmt.Errorf("%s: task %d: %w", path, i+1, err)
+		}
+		if t == nil {
+			continue
+		}
+		var status struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal(raw, &status) == nil {
+			t.Status = model.TaskStatus(status.Status)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// replay applies rec to tasks in file order: a save replaces every task
+// with its ID, or is appended, and a delete removes them all.
+func replay(tasks []*model.Task, rec walRecord) []*model.Task {
+	out := tasks[:0]
+	saved := false
+	for _, t := range tasks {
+		switch {
+		case t.ID != rec.ID:
+			out = append(out, t)
+		case rec.Op == opSave && !saved:
+			out = append(out, rec.Task.Clone())
+			saved = true
+		}
+	}
+	if rec.Op == opSave && !saved {
+		out = append(out, rec.Task.Clone())
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package jsonstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"togo/internal/model"
+)
+
+// legacyNames maps format 0 field names to their current ones.
+var legacyNames = map[string]string{
+	"due":       "due_date",
+	"created":   "created_at",
+	"completed": "completed_at",
+}
+
+// upgradeTask converts a format 0 task, filling in what the format did not
+// require with values that keep the task valid.
+func upgradeTask(raw json.RawMessage, now time.Time) (*model.Task, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for old, name := range legacyNames {
+		if value, ok := fields[old]; ok {
+			if _, clash := fields[name]; !clash {
+				fields[name] = value
+			}
+			delete(fields, old)
+		}
+	}
+	if done, ok := fields["done"]; ok {
+		if _, clash := fields["status"]; !clash {
+			status := model.StatusPool
+			if string(done) == "true" {
+				status = model.StatusDone
+			}
+			fields["status"], _ = json.Marshal(status)
+		}
+		delete(fields, "done")
+	}
+	if _, ok := fields["status"]; !ok {
+		fields["status"], _ = json.Marshal(model.StatusPool)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	t := &model.Task{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+
+	if t.ID.IsEmpty() {
+		t.ID = model.NewTaskID()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	if t.Status == model.StatusDone && t.CompletedAt == nil {
+		completed := t.CreatedAt
+		if !t.UpdatedAt.IsZero() {
+			completed = t.UpdatedAt
+		}
+		t.CompletedAt = &completed
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,154 @@
+package jsonstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"togo/internal/encryption"
+	"togo/internal/model"
+)
+
+// Write-ahead log operations.
+const (
+	opSave   = "save"
+	opDelete = "delete"
+	// opBatch groups changes logged, and so replayed, all or not at all.
+	opBatch = "batch"
+)
+
+// walRecord is one logged change: a task saved in full, or deleted, or a
+// batch of such changes.
+type walRecord struct {
+	Op    string       `json:"op"`
+	ID    model.TaskID `json:"id,omitzero"`
+	Task  *model.Task  `json:"task,omitempty"`
+	Batch []walRecord  `json:"batch,omitempty"`
+}
+
+// changes returns the single changes rec makes.
+func (rec walRecord) changes() []walRecord {
+	if rec.Op == opBatch {
+		return rec.Batch
+	}
+	return []walRecord{rec}
+}
+
+// apply makes the change to tasks. Records are idempotent, so replaying a
+// log whose changes already reached the journal is harmless.
+func (rec walRecord) apply(tasks map[model.TaskID]*model.Task) {
+	for _, c := range rec.changes() {
+		switch c.Op {
+		case opSave:
+			tasks[c.ID] = c.Task.Clone()
+		case opDelete:
+			delete(tasks, c.ID)
+		}
+	}
+}
+
+// valid reports whether rec is well formed.
+func (rec walRecord) valid() bool {
+	switch rec.Op {
+	case opSave:
+		return rec.Task != nil
+	case opDelete:
+		return true
+	case opBatch:
+		for _, c := range rec.Batch {
+			if c.Op == opBatch || !c.valid() {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// appendWAL appends rec to the log at path as one line and syncs it. With
+// a keyring, the line is the record encrypted and base64-encoded, so the
+// log never holds a plaintext task the journal would encrypt.
+func appendWAL(path string, rec walRecord, keyring *encryption.Keyring) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if keyring != nil {
+		sealed, err := keyring.Encrypt(line)
+		if err != nil {
+			return err
+		}
+		line = []byte(base64.StdEncoding.EncodeToString(sealed))
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readWAL returns the changes logged at path, oldest first, with batches
+// flattened into their changes. A missing log is empty. A last line without a line break was cut short by a crash
+// before its change was acknowledged, and is ignored.
+func readWAL(path string, keyring *encryption.Keyring) ([]walRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i+1 < len(data) {
+		data = data[:i+1]
+	}
+
+	var records []walRecord
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] != '{' {
+			if keyring == nil {
+				return nil, fmt.Errorf("%s: log is encrypted but no key is configured", path)
+			}
+			sealed, err := base64.StdEncoding.DecodeString(string(line))
+			if err != nil {
+				return nil, fmt.Errorf("%s: line %d: %w", path, n, err)
+			}
+			if line, err = keyring.Decrypt(sealed); err != nil {
+				return nil, fmt.Errorf("%s: line %d: %w", path, n, err)
+			}
+		}
+		var rec walRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", path, n, err)
+		}
+		if !rec.valid() {
+			return nil, fmt.Errorf("%s: line %d: malformed record", path, n)
+		}
+		records = append(records, rec.changes()...)
+	}
+	return records, sc.Err()
+}
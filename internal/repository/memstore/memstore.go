@@ -0,0 +1,128 @@
+// Package memstore keeps tasks in memory only. It backs unit tests and the
+// --demo sandbox, where nothing should reach the disk.
+package memstore
+
+import (
+	"sync"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Repository is a repository.TaskRepository holding tasks in a map,
+// indexed by status and tag. The zero value is not usable; call New. It is
+// safe for concurrent use.
+type Repository struct {
+	mu    sync.RWMutex
+	tasks map[model.TaskID]*model.Task
+	index *repository.Index
+}
+
+var _ repository.TaskRepository = (*Repository)(nil)
+
+// New returns an empty repository.
+func New() *Repository {
+	return &Repository{tasks: make(map[model.TaskID]*model.Task), index: repository.NewIndex()}
+}
+
+// Save implements repository.TaskRepository.
+func (r *Repository) Save(task *model.Task) error {
+	if err := repository.CheckSave("save", task); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := repository.CheckVersion("save", r.tasks[task.ID], task); err != nil {
+		return err
+	}
+	r.put(task)
+	return nil
+}
+
+// Get implements repository.TaskRepository.
+func (r *Repository) Get(id model.TaskID) (*model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, repository.NotFound("get", id)
+	}
+	return t.Clone(), nil
+}
+
+// Delete implements repository.TaskRepository.
+func (r *Repository) Delete(id model.TaskID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tasks[id]; !ok {
+		return repository.NotFound("delete", id)
+	}
+	delete(r.tasks, id)
+	r.index.Remove(id)
+	return nil
+}
+
+// SaveAll implements repository.TaskRepository.
+func (r *Repository) SaveAll(tasks []*model.Task) error {
+	if err := repository.CheckBatch("save", tasks); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range tasks {
+		if err := repository.CheckVersion("save", r.tasks[t.ID], t); err != nil {
+			return err
+		}
+	}
+	for _, t := range tasks {
+		r.put(t)
+	}
+	return nil
+}
+
+// put stores a copy of task at its next version. r.mu must be held.
+func (r *Repository) put(task *model.Task) {
+	task.Version++
+	r.tasks[task.ID] = task.Clone()
+	r.index.Put(task)
+}
+
+// DeleteMany implements repository.TaskRepository.
+func (r *Repository) DeleteMany(ids []model.TaskID) error {
+	if err := repository.CheckIDs("delete", ids); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := r.tasks[id]; !ok {
+			return repository.NotFound("delete", id)
+		}
+	}
+	for _, id := range ids {
+		delete(r.tasks, id)
+		r.index.Remove(id)
+	}
+	return nil
+}
+
+// List implements repository.TaskRepository.
+func (r *Repository) List(filter model.TaskFilter) ([]*model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return repository.Query(r.index.Narrow(r.tasks, filter), filter), nil
+}
+
+// Count implements repository.TaskRepository.
+func (r *Repository) Count(filter model.TaskFilter) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return repository.CountMatching(r.index.Narrow(r.tasks, filter), filter), nil
+}
+
+// IndexStats describes the repository's index, for debugging.
+func (r *Repository) IndexStats() repository.IndexStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.index.Stats()
+}
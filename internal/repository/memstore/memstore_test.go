@@ -0,0 +1,47 @@
+package memstore
+
+import (
+	"sync"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/repositorytest"
+	"togo/internal/testutil"
+)
+
+// TestRepository_Contract runs the shared repository contract.
+func TestRepository_Contract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.TaskRepository {
+		return New()
+	})
+}
+
+// TestRepository_Concurrent verifies simultaneous readers and writers do
+// not race; run with -race to make it meaningful.
+func TestRepository_Concurrent(t *testing.T) {
+	repo := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := testutil.NewTask().Build()
+			if err := repo.Save(task); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := repo.Get(task.ID); err != nil {
+				t.Error(err)
+			}
+			if _, err := repo.List(model.TaskFilter{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n, _ := repo.Count(model.TaskFilter{}); n != 8 {
+		t.Errorf("Count() = %d, want 8", n)
+	}
+}
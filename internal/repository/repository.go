@@ -0,0 +1,183 @@
+// Package repository defines how tasks are stored and retrieved, so storage
+// backends and the UI can evolve independently.
+package repository
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"togo/internal/model"
+)
+
+// TaskRepository stores tasks by ID.
+//
+// Tasks passed in and handed out are never shared with the repository:
+// implementations store copies and return copies, so callers may mutate
+// what they get back without affecting stored state until they Save it.
+//
+// Errors about a particular task are *model.TaskError values naming the
+// task and operation; they unwrap to the sentinels below, so callers test
+// them with errors.Is:
+//
+//   - model.ErrTaskNotFound: Get or Delete of an ID that is not stored
+//   - model.ErrDuplicateTaskID: stored data, or a batch being written,
+//     holds more than one task with the same ID
+//   - model.ValidationErrors: Save of a task that fails Task.Validate
+//   - model.ErrStaleTask: Save of a copy of a task that was saved again
+//     after the copy was read
+//
+// Any other error means the backend itself failed (I/O, decoding) and
+// should be reported rather than handled.
+type TaskRepository interface {
+	// Save stores task, inserting it or replacing the stored task with the
+	// same ID. Invalid tasks are rejected and nothing is stored, as are
+	// tasks whose Version is not the stored task's. Once stored, the task's
+	// Version is one more than before, and task.Version is updated to
+	// match, so the caller can keep editing and saving it.
+	Save(task *model.Task) error
+
+	// Get returns the task with the given ID.
+	Get(id model.TaskID) (*model.Task, error)
+
+	// Delete removes the task with the given ID.
+	Delete(id model.TaskID) error
+
+	// SaveAll saves every task in one atomic write: either all are stored
+	// or, if any is invalid, stale or two share an ID, none is. Bulk edits and
+	// imports use it instead of a write per task.
+	SaveAll(tasks []*model.Task) error
+
+	// DeleteMany removes the tasks with the given IDs in one atomic write.
+	// If any is not stored, or listed twice, none is removed.
+	DeleteMany(ids []model.TaskID) error
+
+	// List returns the tasks matching filter in creation order, with paging
+	// and fuzzy ranking applied as by TaskFilter.Apply.
+	List(filter model.TaskFilter) ([]*model.Task, error)
+
+	// Count returns how many tasks match filter, ignoring Limit and Offset.
+	Count(filter model.TaskFilter) (int, error)
+}
+
+// Scanner is implemented by backends that can find matching tasks without
+// loading every stored task into memory, such as a large journal file
+// read once by a command.
+type Scanner interface {
+	// Scan returns what List would, holding only the matching tasks.
+	Scan(filter model.TaskFilter) ([]*model.Task, error)
+}
+
+// Scan returns the tasks in repo matching filter, as List does, through
+// Scanner when repo implements it.
+func Scan(repo TaskRepository, filter model.TaskFilter) ([]*model.Task, error) {
+	if s, ok := repo.(Scanner); ok {
+		return s.Scan(filter)
+	}
+	return repo.List(filter)
+}
+
+// GetByPrefix returns the task in repo whose UUID starts with prefix,
+// ignoring case, so a task can be named by its short ID.
+//
+// Returns an error wrapping model.ErrTaskNotFound when no task matches or
+// prefix is empty, or model.ErrAmbiguousID when several do.
+func GetByPrefix(repo TaskRepository, prefix string) (*model.Task, error) {
+	tasks, err := Scan(repo, model.TaskFilter{IDPrefix: prefix, IncludeSnoozed: true, IncludeRecurring: true})
+	if err != nil {
+		return nil, err
+	}
+	t, err := model.FindByIDPrefix(tasks, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("task ID %q: %w", prefix, err)
+	}
+	return t, nil
+}
+
+// Query implements List for backends that hold their tasks in memory: it
+// orders tasks by creation, applies filter and returns copies.
+func Query(tasks []*model.Task, filter model.TaskFilter) []*model.Task {
+	ordered := slices.Clone(tasks)
+	SortByCreation(ordered)
+	matched := filter.Apply(ordered)
+	out := make([]*model.Task, len(matched))
+	for i, t := range matched {
+		out[i] = t.Clone()
+	}
+	return out
+}
+
+// CountMatching implements Count for backends that hold their tasks in
+// memory.
+func CountMatching(tasks []*model.Task, filter model.TaskFilter) int {
+	n := 0
+	for _, t := range tasks {
+		if filter.Matches(t) {
+			n++
+		}
+	}
+	return n
+}
+
+// SortByCreation orders tasks oldest first, breaking ties by ID so the
+// order is stable across backends.
+func SortByCreation(tasks []*model.Task) {
+	slices.SortFunc(tasks, func(a, b *model.Task) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID.String(), b.ID.String())
+	})
+}
+
+// NotFound returns the error for an operation on a task that is not stored.
+func NotFound(op string, id model.TaskID) error {
+	return &model.TaskError{ID: id, Op: op, Err: model.ErrTaskNotFound}
+}
+
+// CheckSave validates task before it is stored, wrapping failures for op.
+func CheckSave(op string, task *model.Task) error {
+	if err := task.Validate(); err != nil {
+		return &model.TaskError{ID: task.ID, Op: op, Err: err}
+	}
+	return nil
+}
+
+// CheckVersion rejects saving task over stored, the copy a backend holds,
+// unless task was read at stored's version, wrapping the failure for op.
+// stored is nil for a task being inserted, which any version may be.
+func CheckVersion(op string, stored, task *model.Task) error {
+	if stored == nil || stored.Version == task.Version {
+		return nil
+	}
+	return &model.TaskError{ID: task.ID, Op: op, Err: fmt.Errorf("%w: stored at version %d, saving version %d", model.ErrStaleTask, stored.Version, task.Version)}
+}
+
+// CheckBatch validates a batch of tasks before any is stored, wrapping
+// failures for op; a batch may not hold two tasks with the same ID.
+func CheckBatch(op string, tasks []*model.Task) error {
+	seen := make(map[model.TaskID]bool, len(tasks))
+	for _, t := range tasks {
+		if err := CheckSave(op, t); err != nil {
+			return err
+		}
+		if seen[t.ID] {
+			return &model.TaskError{ID: t.ID, Op: op, Err: model.ErrDuplicateTaskID}
+		}
+		seen[t.ID] = true
+	}
+	return nil
+}
+
+// CheckIDs rejects a batch of IDs naming a task twice, wrapping the
+// failure for op.
+func CheckIDs(op string, ids []model.TaskID) error {
+	seen := make(map[model.TaskID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return &model.TaskError{ID: id, Op: op, Err: model.ErrDuplicateTaskID}
+		}
+		seen[id] = true
+	}
+	return nil
+}
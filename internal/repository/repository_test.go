@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestQuery verifies creation ordering, filtering and copying.
+func TestQuery(t *testing.T) {
+	base := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	older := testutil.NewTask().WithTitle("older").WithCreatedAt(base).WithTags("work").Build()
+	newer := testutil.NewTask().WithTitle("newer").WithCreatedAt(base.Add(time.Hour)).WithTags("work").Build()
+	other := testutil.NewTask().WithTitle("other").WithCreatedAt(base).Build()
+	tasks := []*model.Task{newer, other, older}
+
+	got := Query(tasks, model.TaskFilter{Tags: []string{"work"}})
+	if len(got) != 2 || got[0].Title != "older" || got[1].Title != "newer" {
+		t.Fatalf("Query() = %v, want older then newer", got)
+	}
+	if got[0] == older {
+		t.Error("Query() returned a stored task instead of a copy")
+	}
+	if tasks[0] != newer {
+		t.Error("Query() reordered its input")
+	}
+	if n := CountMatching(tasks, model.TaskFilter{Tags: []string{"work"}, Limit: 1}); n != 2 {
+		t.Errorf("CountMatching() = %d, want 2 regardless of Limit", n)
+	}
+}
+
+// TestGetByPrefix verifies a partial UUID names exactly one stored task.
+func TestGetByPrefix(t *testing.T) {
+	id := func(s string) model.TaskID {
+		id, err := model.ParseTaskID(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	first := testutil.NewTask().WithID(id("3f2a0000-0000-4000-8000-000000000001")).Build()
+	second := testutil.NewTask().WithID(id("3f2b0000-0000-4000-8000-000000000002")).Build()
+	repo := scanned{tasks: []*model.Task{first, second}}
+
+	tests := []struct {
+		prefix  string
+		want    model.TaskID
+		wantErr error
+	}{
+		{prefix: "3F2A", want: first.ID},
+		{prefix: second.ID.String(), want: second.ID},
+		{prefix: "3f2", wantErr: model.ErrAmbiguousID},
+		{prefix: "ffff", wantErr: model.ErrTaskNotFound},
+		{prefix: "", wantErr: model.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		got, err := GetByPrefix(repo, tt.prefix)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("GetByPrefix(%q) error = %v, want %v", tt.prefix, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.ID != tt.want {
+			t.Errorf("GetByPrefix(%q) = %v, want %v", tt.prefix, got.ID, tt.want)
+		}
+	}
+}
+
+// scanned is a repository holding tasks that only implements Scanner.
+type scanned struct {
+	TaskRepository
+	tasks []*model.Task
+}
+
+func (r scanned) Scan(filter model.TaskFilter) ([]*model.Task, error) {
+	return Query(r.tasks, filter), nil
+}
+
+// TestCheckSave verifies invalid tasks are wrapped with the operation.
+func TestCheckSave(t *testing.T) {
+	task := testutil.NewTask().Build()
+	if err := CheckSave("save", task); err != nil {
+		t.Fatalf("CheckSave() on valid task: %v", err)
+	}
+
+	task.DeferredCount = -1
+	err := CheckSave("save", task)
+	var terr *model.TaskError
+	var verrs model.ValidationErrors
+	if !errors.As(err, &terr) || terr.Op != "save" || !errors.As(err, &verrs) {
+		t.Errorf("CheckSave() = %v, want TaskError wrapping ValidationErrors", err)
+	}
+}
@@ -0,0 +1,318 @@
+// Package repositorytest checks that a repository.TaskRepository honors the
+// documented contract. Each backend's tests call Run with a constructor for
+// an empty repository.
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/testutil"
+)
+
+// Run exercises repo returned by newRepo against the TaskRepository
+// contract. newRepo must return a fresh, empty repository on every call.
+func Run(t *testing.T, newRepo func(t *testing.T) repository.TaskRepository) {
+	t.Helper()
+
+	t.Run("SaveAndGet", func(t *testing.T) { testSaveAndGet(t, newRepo(t)) })
+	t.Run("SaveReplaces", func(t *testing.T) { testSaveReplaces(t, newRepo(t)) })
+	t.Run("SaveRejectsInvalid", func(t *testing.T) { testSaveRejectsInvalid(t, newRepo(t)) })
+	t.Run("SaveRejectsStale", func(t *testing.T) { testSaveRejectsStale(t, newRepo(t)) })
+	t.Run("ReturnsCopies", func(t *testing.T) { testReturnsCopies(t, newRepo(t)) })
+	t.Run("GetMissing", func(t *testing.T) { testGetMissing(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, newRepo(t)) })
+	t.Run("ListAndCount", func(t *testing.T) { testListAndCount(t, newRepo(t)) })
+	t.Run("SaveAll", func(t *testing.T) { testSaveAll(t, newRepo(t)) })
+	t.Run("DeleteMany", func(t *testing.T) { testDeleteMany(t, newRepo(t)) })
+}
+
+func mustSave(t *testing.T, repo repository.TaskRepository, tasks ...*model.Task) {
+	t.Helper()
+	for _, task := range tasks {
+		if err := repo.Save(task); err != nil {
+			t.Fatalf("Save(%s) error: %v", task.ID.Short(), err)
+		}
+	}
+}
+
+func testSaveAndGet(t *testing.T, repo repository.TaskRepository) {
+	due := time.Date(2024, 6, 14, 17, 0, 0, 0, time.UTC)
+	want := testutil.NewTask().
+		WithTitle("Renew passport").
+		WithNotes("bring photos").
+		WithStatus(model.StatusDone).
+		WithTags("admin", "errands").
+		WithDue(due).
+		WithPriority(model.PriorityHigh).
+		WithEnergy(model.EnergyLow).
+		WithEstimate(90 * time.Minute).
+		Pinned().
+		Build()
+	want.DeferredCount = 2
+	want.ExternalRef = &model.ExternalRef{System: "github", ID: "togo#1"}
+	mustSave(t, repo, want)
+
+	got, err := repo.Get(want.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if diff := want.Diff(got); len(diff) > 0 {
+		t.Errorf("Get() differs from saved task: %v", diff)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+}
+
+func testSaveReplaces(t *testing.T, repo repository.TaskRepository) {
+	task := testutil.NewTask().WithTitle("Draft").Build()
+	mustSave(t, repo, task)
+	task.Title = "Final"
+	mustSave(t, repo, task)
+
+	got, err := repo.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got.Title != "Final" {
+		t.Errorf("Title = %q, want Final", got.Title)
+	}
+	if n, _ := repo.Count(model.TaskFilter{}); n != 1 {
+		t.Errorf("Count() = %d after replacing, want 1", n)
+	}
+}
+
+func testSaveRejectsInvalid(t *testing.T, repo repository.TaskRepository) {
+	task := testutil.NewTask().Build()
+	task.Title = "  "
+
+	err := repo.Save(task)
+	var verrs model.ValidationErrors
+	var terr *model.TaskError
+	if !errors.As(err, &verrs) || !errors.As(err, &terr) || terr.ID != task.ID {
+		t.Fatalf("Save() error = %v, want TaskError wrapping ValidationErrors", err)
+	}
+	if _, err := repo.Get(task.ID); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Errorf("invalid task was stored: Get() error = %v", err)
+	}
+}
+
+func testSaveRejectsStale(t *testing.T, repo repository.TaskRepository) {
+	task := testutil.NewTask().WithTitle("Draft").Build()
+	mustSave(t, repo, task)
+	if task.Version != 1 {
+		t.Fatalf("Version after the first Save = %d, want 1", task.Version)
+	}
+	mine, err := repo.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	theirs := mine.Clone()
+
+	mine.Title = "Mine"
+	mustSave(t, repo, mine)
+	theirs.Title = "Theirs"
+	err = repo.Save(theirs)
+	var terr *model.TaskError
+	if !errors.Is(err, model.ErrStaleTask) || !errors.As(err, &terr) || terr.ID != task.ID {
+		t.Fatalf("Save() of a stale copy error = %v, want TaskError wrapping ErrStaleTask", err)
+	}
+	added := testutil.NewTask().Build()
+	if err := repo.SaveAll([]*model.Task{added, theirs}); !errors.Is(err, model.ErrStaleTask) {
+		t.Fatalf("SaveAll() with a stale copy error = %v, want ErrStaleTask", err)
+	}
+	if _, err := repo.Get(added.ID); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Errorf("part of a stale batch was stored: Get() error = %v", err)
+	}
+
+	got, err := repo.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got.Title != "Mine" || got.Version != 2 || mine.Version != 2 {
+		t.Errorf("stored %q at version %d, saved copy at %d; want Mine at 2", got.Title, got.Version, mine.Version)
+	}
+}
+
+func testReturnsCopies(t *testing.T, repo repository.TaskRepository) {
+	task := testutil.NewTask().WithTitle("Original").WithTags("a").Build()
+	mustSave(t, repo, task)
+	task.Title = "Changed after save"
+	task.Tags[0] = "changed"
+
+	got, err := repo.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if got.Title != "Original" || got.Tags[0] != "a" {
+		t.Fatalf("stored task aliased the saved one: %+v", got)
+	}
+	got.Tags[0] = "mutated"
+
+	listed, err := repo.List(model.TaskFilter{})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if listed[0].Tags[0] != "a" {
+		t.Errorf("stored task aliased the returned one: %+v", listed[0])
+	}
+}
+
+func testGetMissing(t *testing.T, repo repository.TaskRepository) {
+	id := model.NewTaskID()
+	_, err := repo.Get(id)
+	var terr *model.TaskError
+	if !errors.Is(err, model.ErrTaskNotFound) || !errors.As(err, &terr) || terr.ID != id {
+		t.Errorf("Get() error = %v, want TaskError wrapping ErrTaskNotFound", err)
+	}
+}
+
+func testDelete(t *testing.T, repo repository.TaskRepository) {
+	keep := testutil.NewTask().Build()
+	drop := testutil.NewTask().Build()
+	mustSave(t, repo, keep, drop)
+
+	if err := repo.Delete(drop.ID); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := repo.Get(drop.ID); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := repo.Get(keep.ID); err != nil {
+		t.Errorf("Delete removed the wrong task: %v", err)
+	}
+	if err := repo.Delete(drop.ID); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func testSaveAll(t *testing.T, repo repository.TaskRepository) {
+	existing := testutil.NewTask().WithTitle("Draft").Build()
+	mustSave(t, repo, existing)
+	existing.Title = "Final"
+	added := testutil.NewTask().WithTitle("Added").Build()
+	if err := repo.SaveAll([]*model.Task{existing, added}); err != nil {
+		t.Fatalf("SaveAll() error: %v", err)
+	}
+	if n, _ := repo.Count(model.TaskFilter{}); n != 2 {
+		t.Errorf("Count() after SaveAll = %d, want 2", n)
+	}
+	if got, err := repo.Get(existing.ID); err != nil || got.Title != "Final" {
+		t.Errorf("Get() after SaveAll = %v, %v; want the replaced task", got, err)
+	}
+
+	invalid := testutil.NewTask().Build()
+	invalid.Title = ""
+	rejected := testutil.NewTask().WithTitle("Rejected").Build()
+	tests := []struct {
+		name    string
+		batch   []*model.Task
+		wantErr error
+	}{
+		// A nil wantErr stands for ValidationErrors.
+		{"invalid task", []*model.Task{rejected, invalid}, nil},
+		{"duplicate ID", []*model.Task{rejected, rejected}, model.ErrDuplicateTaskID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.SaveAll(tt.batch)
+			var terr *model.TaskError
+			var verrs model.ValidationErrors
+			if !errors.As(err, &terr) {
+				t.Fatalf("SaveAll() error = %v, want a TaskError", err)
+			}
+			switch {
+			case tt.wantErr == nil && !errors.As(err, &verrs):
+				t.Errorf("SaveAll() error = %v, want one wrapping ValidationErrors", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("SaveAll() error = %v, want one wrapping %v", err, tt.wantErr)
+			}
+			if _, err := repo.Get(rejected.ID); !errors.Is(err, model.ErrTaskNotFound) {
+				t.Errorf("part of a rejected batch was stored: Get() error = %v", err)
+			}
+		})
+	}
+}
+
+func testDeleteMany(t *testing.T, repo repository.TaskRepository) {
+	a, b, c := testutil.NewTask().Build(), testutil.NewTask().Build(), testutil.NewTask().Build()
+	mustSave(t, repo, a, b, c)
+
+	if err := repo.DeleteMany([]model.TaskID{a.ID, model.NewTaskID()}); !errors.Is(err, model.ErrTaskNotFound) {
+		t.Fatalf("DeleteMany() with a missing ID error = %v, want ErrTaskNotFound", err)
+	}
+	if err := repo.DeleteMany([]model.TaskID{a.ID, a.ID}); !errors.Is(err, model.ErrDuplicateTaskID) {
+		t.Fatalf("DeleteMany() with a repeated ID error = %v, want ErrDuplicateTaskID", err)
+	}
+	if n, _ := repo.Count(model.TaskFilter{}); n != 3 {
+		t.Fatalf("a rejected DeleteMany removed tasks: %d left, want 3", n)
+	}
+
+	if err := repo.DeleteMany([]model.TaskID{a.ID, c.ID}); err != nil {
+		t.Fatalf("DeleteMany() error: %v", err)
+	}
+	left, err := repo.List(model.TaskFilter{})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(left) != 1 || left[0].ID != b.ID {
+		t.Errorf("List() after DeleteMany = %v, want only %s", left, b.ID.Short())
+	}
+	if err := repo.DeleteMany(nil); err != nil {
+		t.Errorf("DeleteMany(nil) error: %v", err)
+	}
+}
+
+func testListAndCount(t *testing.T, repo repository.TaskRepository) {
+	base := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	var want []*model.Task
+	for i, title := range []string{"a", "b", "c", "d"} {
+		b := testutil.NewTask().WithTitle(title).WithCreatedAt(base.Add(time.Duration(i) * time.Hour))
+		if i%2 == 0 {
+			b = b.WithTags("work")
+		}
+		want = append(want, b.Build())
+	}
+	// Save out of order; List must still return creation order.
+	mustSave(t, repo, want[2], want[0], want[3], want[1])
+
+	tests := []struct {
+		name      string
+		filter    model.TaskFilter
+		wantList  string
+		wantCount int
+	}{
+		{"everything", model.TaskFilter{}, "abcd", 4},
+		{"tagged", model.TaskFilter{Tags: []string{"work"}}, "ac", 2},
+		{"paged", model.TaskFilter{Limit: 2, Offset: 1}, "bc", 4},
+		{"none", model.TaskFilter{Text: "zzz"}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := repo.List(tt.filter)
+			if err != nil {
+				t.Fatalf("List() error: %v", err)
+			}
+			var got string
+			for _, task := range tasks {
+				got += task.Title
+			}
+			if got != tt.wantList {
+				t.Errorf("List() = %q, want %q", got, tt.wantList)
+			}
+
+			n, err := repo.Count(tt.filter)
+			if err != nil {
+				t.Fatalf("Count() error: %v", err)
+			}
+			if n != tt.wantCount {
+				t.Errorf("Count() = %d, want %d", n, tt.wantCount)
+			}
+		})
+	}
+}
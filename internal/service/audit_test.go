@@ -0,0 +1,141 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/audit"
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_Audit verifies every stored change, including undoing
+// and archiving, is logged with its action, source and operation.
+func TestTaskService_Audit(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(now))()
+	log := audit.Open(filepath.Join(t.TempDir(), "tasks.json.audit"), nil)
+	s := New(memstore.New(), nil)
+	s.SetAudit(log, audit.SourceCLI, nil)
+	s.SetArchive(&archiveStub{})
+
+	task, err := s.AddTask("Water the plants", nil)
+	if err != nil {
+		t.Fatalf("AddTask() error: %v", err)
+	}
+	if _, err := s.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error: %v", err)
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if err := s.ArchiveTask(task.ID); err != nil {
+		t.Fatalf("ArchiveTask() error: %v", err)
+	}
+
+	entries, err := log.Read(now)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		if e.TaskID != task.ID || e.Source != audit.SourceCLI || len(e.Changes) == 0 {
+			t.Errorf("entry %+v, want a change to the task from the CLI", e)
+		}
+		got = append(got, e.Action+": "+string(e.Op))
+	}
+	want := []string{"add: create", "complete: update", "undo complete: update", "archive: delete"}
+	if !slices.Equal(got, want) {
+		t.Errorf("audit log = %q, want %q", got, want)
+	}
+}
+
+// TestTaskService_AuditBulk verifies imports, archiving of done tasks and
+// repairs are logged, one entry per task they change.
+func TestTaskService_AuditBulk(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(now))()
+	log := audit.Open(filepath.Join(t.TempDir(), "tasks.json.audit"), nil)
+	s := New(memstore.New(), nil)
+	s.SetArchive(&archiveStub{})
+
+	kept, err := s.AddTask("Water the plants", nil)
+	if err != nil {
+		t.Fatalf("AddTask() error: %v", err)
+	}
+	s.SetAudit(log, audit.SourceCLI, nil)
+
+	renamed := kept.Clone()
+	renamed.Title = "Water the ferns"
+	create, update, _, err := s.PlanImport([]*model.Task{renamed, testutil.NewTask().WithTitle("Call mum").Build()})
+	if err != nil {
+		t.Fatalf("PlanImport() error: %v", err)
+	}
+	if err := s.ImportTasks(create, update); err != nil {
+		t.Fatalf("ImportTasks() error: %v", err)
+	}
+
+	stored, err := s.StoredTasks()
+	if err != nil {
+		t.Fatalf("StoredTasks() error: %v", err)
+	}
+	var fixed []*model.Task
+	for _, task := range stored {
+		if task.ID == kept.ID {
+			done := task.Clone()
+			done.Status, done.CompletedAt = model.StatusDone, &now
+			fixed = append(fixed, done)
+		}
+	}
+	if err := s.RepairTasks(stored, fixed); err != nil {
+		t.Fatalf("RepairTasks() error: %v", err)
+	}
+
+	if n, err := s.ArchiveDone(now.Add(time.Hour)); n != 1 || err != nil {
+		t.Fatalf("ArchiveDone() = %d, %v; want 1, nil", n, err)
+	}
+
+	entries, err := log.Read(now)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Action+": "+string(e.Op))
+	}
+	slices.Sort(got)
+	want := []string{"archive: delete", "import: create", "import: update", "repair: delete", "repair: update"}
+	if !slices.Equal(got, want) {
+		t.Errorf("audit log = %q, want %q", got, want)
+	}
+}
+
+// TestTaskService_AuditFailure verifies a change the audit log cannot take
+// is stored all the same and the failure reported.
+func TestTaskService_AuditFailure(t *testing.T) {
+	blocked := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocked, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var reported []error
+	repo := memstore.New()
+	s := New(repo, nil)
+	s.SetAudit(audit.Open(filepath.Join(blocked, "tasks.json.audit"), nil), audit.SourceCLI, func(err error) {
+		reported = append(reported, err)
+	})
+
+	task, err := s.AddTask("Water the plants", nil)
+	if err != nil {
+		t.Fatalf("AddTask() error: %v", err)
+	}
+	if _, err := repo.Get(task.ID); err != nil {
+		t.Errorf("Get() error: %v, want the task stored", err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("reported %v, want one audit log failure", reported)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// PlanImport splits imported tasks into those to create, those to update
+// and those unchanged, as importer.Plan does against the repository.
+func (s *TaskService) PlanImport(tasks []*model.Task) (create, update, unchanged []*model.Task, err error) {
+	return importer.Plan(s.repo, tasks)
+}
+
+// ImportTasks stores the tasks an import creates and those it updates,
+// as PlanImport split them, in one write, numbering the created tasks
+// after the journal's. Like archiving, an import cannot be undone and runs
+// no hooks; it is recorded in the audit log.
+func (s *TaskService) ImportTasks(create, update []*model.Task) error {
+	if err := s.number(create...); err != nil {
+		return err
+	}
+	changes := make([]Change, 0, len(create)+len(update))
+	for _, t := range create {
+		changes = append(changes, Change{After: t})
+	}
+	for _, t := range update {
+		before, err := s.repo.Get(t.ID)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, Change{Before: before, After: t})
+	}
+	if err := s.repo.SaveAll(append(create, update...)); err != nil {
+		return err
+	}
+	s.auditChanges("import", changes)
+	return nil
+}
+
+// Salvager is a repository that can read and replace a journal it would
+// refuse to load, such as one holding two tasks with the same ID.
+type Salvager interface {
+	Salvage() ([]*model.Task, error)
+	Replace(tasks []*model.Task) error
+}
+
+// StoredTasks returns every task the repository holds, as stored. A
+// repository that is not a Salvager is read through List, which sees
+// only what it could load.
+func (s *TaskService) StoredTasks() ([]*model.Task, error) {
+	if r, ok := s.repo.(Salvager); ok {
+		return r.Salvage()
+	}
+	return s.repo.List(model.TaskFilter{})
+}
+
+// RepairTasks stores fixed, the repaired tasks, in place of checked, the
+// tasks StoredTasks returned, deleting those fixed leaves out. Like an
+// import, a repair cannot be undone and runs no hooks; each task it
+// changes is recorded in the audit log.
+func (s *TaskService) RepairTasks(checked, fixed []*model.Task) error {
+	before := make(map[model.TaskID]*model.Task, len(checked))
+	for _, t := range checked {
+		if _, ok := before[t.ID]; !ok {
+			before[t.ID] = t
+		}
+	}
+	kept := make(map[model.TaskID]bool, len(fixed))
+	var changes []Change
+	for _, t := range fixed {
+		kept[t.ID] = true
+		if b := before[t.ID]; b == nil || len(b.Diff(t)) > 0 {
+			changes = append(changes, Change{Before: b, After: t})
+		}
+	}
+	var dropped []model.TaskID
+	for _, t := range checked {
+		if !kept[t.ID] && before[t.ID] == t {
+			dropped = append(dropped, t.ID)
+			changes = append(changes, Change{Before: t})
+		}
+	}
+
+	if r, ok := s.repo.(Salvager); ok {
+		if err := r.Replace(fixed); err != nil {
+			return err
+		}
+	} else {
+		if err := s.repo.SaveAll(fixed); err != nil {
+			return err
+		}
+		if err := s.repo.DeleteMany(dropped); err != nil {
+			return err
+		}
+	}
+	s.auditChanges("repair", changes)
+	return nil
+}
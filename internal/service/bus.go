@@ -0,0 +1,53 @@
+package service
+
+import (
+	"slices"
+	"sync"
+)
+
+// Bus delivers the events use cases publish to whoever subscribed, such as
+// the TUI, notifications or hook scripts, so a use case need not know what
+// reacts to it. It is safe for concurrent use.
+type Bus struct {
+	mu   sync.Mutex
+	subs []*subscription
+}
+
+type subscription struct {
+	handle func(Event)
+}
+
+// NewBus returns a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls handle with each event published from now on, until the
+// returned function is called. Handlers run synchronously, in the order
+// they subscribed, on the goroutine publishing; one with slow work to do
+// should hand it off.
+func (b *Bus) Subscribe(handle func(Event)) (unsubscribe func()) {
+	sub := &subscription{handle: handle}
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.subs = slices.DeleteFunc(b.subs, func(s *subscription) bool { return s == sub })
+	}
+}
+
+// Publish delivers e to every subscriber, each with its own copy of the
+// task. A nil bus has none.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	subs := slices.Clone(b.subs)
+	b.mu.Unlock()
+	for _, s := range subs {
+		s.handle(Event{Type: e.Type, At: e.At, Task: e.Task.Clone()})
+	}
+}
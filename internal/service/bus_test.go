@@ -0,0 +1,35 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"togo/internal/testutil"
+)
+
+// TestBus verifies subscribers get each event in the order they
+// subscribed, with their own copy of the task, until they unsubscribe.
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	unsubscribe := bus.Subscribe(func(e Event) {
+		got = append(got, "first "+string(e.Type))
+		e.Task.Title = "changed by a subscriber"
+	})
+	bus.Subscribe(func(e Event) { got = append(got, "second "+string(e.Type)+" "+e.Task.Title) })
+
+	task := testutil.NewTask().WithTitle("Water the plants").Build()
+	bus.Publish(Event{Type: TaskCreated, Task: task})
+	unsubscribe()
+	bus.Publish(Event{Type: TaskDeleted, Task: task})
+
+	want := []string{"first created", "second created Water the plants", "second deleted Water the plants"}
+	if !slices.Equal(got, want) {
+		t.Errorf("delivered %q, want %q", got, want)
+	}
+	if task.Title != "Water the plants" {
+		t.Errorf("subscriber changed the published task: %q", task.Title)
+	}
+	var none *Bus
+	none.Publish(Event{Type: TaskCreated, Task: task})
+}
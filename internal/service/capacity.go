@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"togo/internal/model"
+)
+
+// TodayLimit caps how many tasks today's list holds. A zero Max leaves it
+// uncapped.
+type TodayLimit struct {
+	// Max is how many tasks the list may hold.
+	Max int
+	// Block makes MoveToToday refuse a task the list has no room for,
+	// instead of moving it and warning.
+	Block bool
+}
+
+// ErrTodayFull indicates today's list holds as many tasks as its limit
+// allows, so no task can join it.
+var ErrTodayFull = errors.New("today's list is full")
+
+// CapacityWarning signals that a task joined today's list although it was
+// full. It is advisory: the task was moved all the same.
+type CapacityWarning struct {
+	Count int
+	Limit int
+}
+
+func (w *CapacityWarning) Error() string {
+	return fmt.Sprintf("today's list holds %d tasks, over its limit of %d", w.Count, w.Limit)
+}
+
+// SetTodayLimit caps today's list at l from now on.
+func (s *TaskService) SetTodayLimit(l TodayLimit) {
+	s.todayLimit = l
+}
+
+// TodayCapacity returns how many tasks today's list holds and how many it
+// may hold, zero when it is uncapped.
+func (s *TaskService) TodayCapacity() (count, limit int, err error) {
+	today := model.StatusToday
+	count, err = s.repo.Count(model.TaskFilter{Status: &today, IncludeSnoozed: true})
+	return count, s.todayLimit.Max, err
+}
+
+// checkCapacity returns the warning to give, or the error to refuse with,
+// when t joins today's list.
+func (s *TaskService) checkCapacity(t *model.Task) (*CapacityWarning, error) {
+	if s.todayLimit.Max <= 0 || t.Status == model.StatusToday {
+		return nil, nil
+	}
+	count, _, err := s.TodayCapacity()
+	if err != nil || count < s.todayLimit.Max {
+		return nil, err
+	}
+	if s.todayLimit.Block {
+		return nil, &model.TaskError{ID: t.ID, Op: "move to today", Err: fmt.Errorf("%w (%d of %d)", ErrTodayFull, count, s.todayLimit.Max)}
+	}
+	return &CapacityWarning{Count: count + 1, Limit: s.todayLimit.Max}, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_TodayLimit verifies MoveToToday warns or refuses once
+// today's list is full, as the limit says, and that tasks already on it
+// do not count against it twice.
+func TestTaskService_TodayLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       TodayLimit
+		onToday     int
+		status      model.TaskStatus
+		wantWarning *CapacityWarning
+		wantErr     error
+		wantCount   int
+	}{
+		{name: "uncapped", onToday: 5, status: model.StatusPool, wantCount: 6},
+		{name: "room left", limit: TodayLimit{Max: 3}, onToday: 2, status: model.StatusPool, wantCount: 3},
+		{name: "full warns", limit: TodayLimit{Max: 2}, onToday: 2, status: model.StatusPool, wantWarning: &CapacityWarning{Count: 3, Limit: 2}, wantCount: 3},
+		{name: "full blocks", limit: TodayLimit{Max: 2, Block: true}, onToday: 2, status: model.StatusPool, wantErr: ErrTodayFull, wantCount: 2},
+		{name: "already today", limit: TodayLimit{Max: 2, Block: true}, onToday: 1, status: model.StatusToday, wantCount: 2},
+		{name: "done", limit: TodayLimit{Max: 3}, onToday: 0, status: model.StatusDone, wantErr: model.ErrInvalidStateTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			for range tt.onToday {
+				testutil.MustSeed(t, repo, testutil.NewTask().WithStatus(model.StatusToday).Build())
+			}
+			task := testutil.NewTask().WithStatus(tt.status).Build()
+			testutil.MustSeed(t, repo, task)
+			s := New(repo, nil)
+			s.SetTodayLimit(tt.limit)
+
+			_, warning, err := s.MoveToToday(task.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MoveToToday() error = %v, want %v", err, tt.wantErr)
+			}
+			if (warning == nil) != (tt.wantWarning == nil) || warning != nil && *warning != *tt.wantWarning {
+				t.Errorf("MoveToToday() warning = %v, want %v", warning, tt.wantWarning)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			count, limit, err := s.TodayCapacity()
+			if err != nil || count != tt.wantCount || limit != tt.limit.Max {
+				t.Errorf("TodayCapacity() = %d, %d, %v; want %d, %d", count, limit, err, tt.wantCount, tt.limit.Max)
+			}
+		})
+	}
+}
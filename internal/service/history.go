@@ -0,0 +1,256 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"togo/internal/atomicfile"
+	"togo/internal/encryption"
+	"togo/internal/filelock"
+	"togo/internal/model"
+)
+
+// DefaultHistoryLimit is how many steps a history keeps to undo.
+const DefaultHistoryLimit = 100
+
+// HistoryExt is appended to the journal's path to name its history file.
+const HistoryExt = ".history"
+
+var (
+	// ErrNothingToUndo is returned by Undo when no step is left to undo.
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned by Redo when no undone step is left.
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// Change is one task as a step found and left it. A nil Before means the
+// step created the task; a nil After means it deleted it.
+type Change struct {
+	Before *model.Task `json:"before,omitempty"`
+	After  *model.Task `json:"after,omitempty"`
+}
+
+// id returns the ID of the task changed.
+func (c Change) id() model.TaskID {
+	if c.After != nil {
+		return c.After.ID
+	}
+	return c.Before.ID
+}
+
+// Step is a mutation a use case made, recorded so it can be undone.
+type Step struct {
+	// Action names the use case, such as "complete".
+	Action  string    `json:"action"`
+	At      time.Time `json:"at"`
+	Changes []Change  `json:"changes"`
+}
+
+// Inverse returns the step that reverts s: each change with Before and
+// After swapped.
+func (s Step) Inverse() Step {
+	inv := Step{Action: s.Action, At: s.At, Changes: make([]Change, len(s.Changes))}
+	for i, c := range s.Changes {
+		inv.Changes[i] = Change{Before: c.After, After: c.Before}
+	}
+	return inv
+}
+
+// String describes the step for the user, such as complete "Pay rent".
+func (s Step) String() string {
+	if len(s.Changes) != 1 {
+		return fmt.Sprintf("%s %d tasks", s.Action, len(s.Changes))
+	}
+	c := s.Changes[0]
+	task := c.After
+	if task == nil {
+		task = c.Before
+	}
+	return fmt.Sprintf("%s %q", s.Action, task.Title)
+}
+
+// History is the bounded list of steps that can be undone, and of undone
+// steps that can be redone. Recording a step forgets the undone ones.
+//
+// A history opened on a file is shared by every process using the
+// journal, so "togo undo" reverts what the TUI did; its methods take an
+// advisory lock. Otherwise it lives only as long as the service.
+type History struct {
+	path string
+	// keyring encrypts the file as the journal is, when set, since it
+	// holds copies of tasks.
+	keyring *encryption.Keyring
+	limit   int
+
+	mu sync.Mutex
+	// state holds the steps of a history without a file.
+	state historyState
+}
+
+// historyState is the content of a history file.
+type historyState struct {
+	Undo []Step `json:"undo"`
+	Redo []Step `json:"redo,omitempty"`
+}
+
+// NewHistory returns a history held in memory, keeping up to limit steps.
+func NewHistory(limit int) *History {
+	return &History{limit: limit}
+}
+
+// OpenHistory returns the history kept in the file at path, encrypted with
+// keyring if it is not nil, keeping up to limit steps. Nothing is read
+// until it is used, and a missing file holds no steps.
+func OpenHistory(path string, limit int, keyring *encryption.Keyring) *History {
+	return &History{path: path, keyring: keyring, limit: limit}
+}
+
+// HistoryPath returns the path of the history file for the journal at path.
+func HistoryPath(journal string) string {
+	return journal + HistoryExt
+}
+
+// Record adds step as the latest to undo, dropping the oldest beyond the
+// limit and any undone steps.
+func (h *History) Record(step Step) error {
+	return h.update(func(st *historyState) error {
+		st.Undo = append(st.Undo, step)
+		if over := len(st.Undo) - h.limit; over > 0 {
+			st.Undo = st.Undo[over:]
+		}
+		st.Redo = nil
+		return nil
+	})
+}
+
+// undo pops the latest step, applies its inverse with apply and moves it
+// to the redo list. A step apply rejects is dropped, since it can never
+// apply again, and apply's error is returned.
+func (h *History) undo(apply func(Step) error) (Step, error) {
+	return h.move(func(st *historyState) (*[]Step, *[]Step) { return &st.Undo, &st.Redo }, ErrNothingToUndo, func(s Step) error {
+		return apply(s.Inverse())
+	})
+}
+
+// redo pops the latest undone step, applies it with apply and moves it
+// back to the undo list, as undo does.
+func (h *History) redo(apply func(Step) error) (Step, error) {
+	return h.move(func(st *historyState) (*[]Step, *[]Step) { return &st.Redo, &st.Undo }, ErrNothingToRedo, apply)
+}
+
+// move pops a step from one list, applies it and pushes it on the other.
+// The history stays locked meanwhile, so two processes cannot undo the
+// same step.
+func (h *History) move(lists func(*historyState) (from, to *[]Step), empty error, apply func(Step) error) (Step, error) {
+	var step Step
+	err := h.update(func(st *historyState) error {
+		from, to := lists(st)
+		if len(*from) == 0 {
+			return empty
+		}
+		step = (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		if err := apply(step); err != nil {
+			return &droppedError{err}
+		}
+		*to = append(*to, step)
+		return nil
+	})
+	var dropped *droppedError
+	if errors.As(err, &dropped) {
+		return step, dropped.err
+	}
+	return step, err
+}
+
+// droppedError carries an error from applying a step whose removal from
+// the history is still saved.
+type droppedError struct{ err error }
+
+func (e *droppedError) Error() string { return e.err.Error() }
+
+// update applies change to the history's steps and saves them, unless
+// change fails. A droppedError still saves.
+func (h *History) update(change func(*historyState) error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.path == "" {
+		st := historyState{Undo: slices.Clone(h.state.Undo), Redo: slices.Clone(h.state.Redo)}
+		err := change(&st)
+		var dropped *droppedError
+		if err == nil || errors.As(err, &dropped) {
+			h.state = st
+		}
+		return err
+	}
+
+	lock, err := filelock.Acquire(h.path+".lock", filelock.Exclusive)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	st, err := h.read()
+	if err != nil {
+		return err
+	}
+	err = change(&st)
+	var dropped *droppedError
+	if err != nil && !errors.As(err, &dropped) {
+		return err
+	}
+	if werr := h.write(st); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// read decodes the history file. The lock must be held.
+func (h *History) read() (historyState, error) {
+	var st historyState
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if encryption.IsEncrypted(data) {
+		if h.keyring == nil {
+			return st, fmt.Errorf("%s: history is encrypted but no key is configured", h.path)
+		}
+		if data, err = h.keyring.Decrypt(data); err != nil {
+			return st, fmt.Errorf("%s: %w", h.path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", h.path, err)
+	}
+	return st, nil
+}
+
+// write replaces the history file with st, removing it once it holds no
+// steps. The lock must be held.
+func (h *History) write(st historyState) error {
+	if len(st.Undo) == 0 && len(st.Redo) == 0 {
+		if err := os.Remove(h.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if h.keyring != nil {
+		if data, err = h.keyring.Encrypt(data); err != nil {
+			return err
+		}
+	}
+	return atomicfile.WriteFile(h.path, data)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestHistory_Record verifies a history keeps only its newest steps, and
+// recording a step forgets the undone ones, in memory and in a file
+// shared by two processes.
+func TestHistory_Record(t *testing.T) {
+	tests := []struct {
+		name string
+		// open returns two handles on the same history.
+		open func(t *testing.T) (*History, *History)
+	}{
+		{
+			name: "memory",
+			open: func(t *testing.T) (*History, *History) {
+				h := NewHistory(2)
+				return h, h
+			},
+		},
+		{
+			name: "file",
+			open: func(t *testing.T) (*History, *History) {
+				path := HistoryPath(filepath.Join(t.TempDir(), "tasks.json"))
+				return OpenHistory(path, 2, nil), OpenHistory(path, 2, nil)
+			},
+		},
+	}
+	step := func(title string) Step {
+		task := testutil.NewTask().WithTitle(title).Build()
+		return Step{Action: "add", Changes: []Change{{After: task}}}
+	}
+	noop := func(Step) error { return nil }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.open(t)
+			for _, title := range []string{"one", "two", "three"} {
+				if err := a.Record(step(title)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var undone []string
+			for {
+				s, err := b.undo(noop)
+				if errors.Is(err, ErrNothingToUndo) {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				undone = append(undone, s.Changes[0].After.Title)
+			}
+			if len(undone) != 2 || undone[0] != "three" || undone[1] != "two" {
+				t.Errorf("undid %v, want [three two]", undone)
+			}
+
+			if err := a.Record(step("four")); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := b.redo(noop); !errors.Is(err, ErrNothingToRedo) {
+				t.Errorf("redo after recording a step error = %v, want ErrNothingToRedo", err)
+			}
+		})
+	}
+}
+
+// TestHistory_RemovesEmptyFile verifies the history file goes away once
+// it holds no steps.
+func TestHistory_RemovesEmptyFile(t *testing.T) {
+	path := HistoryPath(filepath.Join(t.TempDir(), "tasks.json"))
+	h := OpenHistory(path, DefaultHistoryLimit, nil)
+	task := testutil.NewTask().Build()
+	if err := h.Record(Step{Action: "add", Changes: []Change{{After: task}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("history file after Record: %v", err)
+	}
+	refused := &model.TaskError{ID: task.ID, Op: "undo", Err: model.ErrStaleTask}
+	if _, err := h.undo(func(Step) error { return refused }); !errors.Is(err, model.ErrStaleTask) {
+		t.Fatalf("undo() error = %v, want the refusal", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("history file left behind with no steps: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"togo/internal/atomicfile"
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// SequencePath returns where the highest sequence number handed out in
+// the journal stored at journal is kept.
+func SequencePath(journal string) string {
+	return journal + ".seq"
+}
+
+// SetSequence keeps the highest sequence number handed out at path, one
+// of SequencePath's, so numbers are not reused after tasks are archived
+// or deleted. Without it, numbering resumes after the highest number
+// among the journal's tasks.
+func (s *TaskService) SetSequence(path string) {
+	s.seqPath = path
+}
+
+// number gives each of tasks without a sequence number, or with one
+// another task in the journal already has, the next one after the highest
+// ever handed out, so every task can be addressed as "#N".
+func (s *TaskService) number(tasks ...*model.Task) error {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+	last, err := s.lastSequence()
+	if err != nil {
+		return err
+	}
+	all, err := s.allTasks()
+	if err != nil {
+		return err
+	}
+	taken := make(map[int]bool, len(all))
+	for _, t := range all {
+		last = max(last, t.Seq)
+		taken[t.Seq] = true
+	}
+	seq := model.NewSequenceAllocator(last)
+	for _, t := range tasks {
+		if t.Seq <= 0 || taken[t.Seq] {
+			t.Seq = seq.Next()
+		}
+		taken[t.Seq] = true
+	}
+	if s.seqPath == "" || seq.Last() == last {
+		return nil
+	}
+	return atomicfile.WriteFile(s.seqPath, []byte(strconv.Itoa(seq.Last())+"\n"))
+}
+
+// lastSequence returns the highest sequence number kept at seqPath, or 0
+// if none is.
+func (s *TaskService) lastSequence() (int, error) {
+	if s.seqPath == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(s.seqPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("read %s: %w", s.seqPath, err)
+	}
+	return last, nil
+}
+
+// ResolveTask returns the task input refers to in any form
+// model.ResolveTaskID accepts: a full UUID, a UUID prefix or a sequence
+// number such as "#12". Input other than digits is looked up by prefix in
+// the repository, without reading every task.
+//
+// Returns an error wrapping model.ErrTaskNotFound when no task matches, or
+// model.ErrAmbiguousID when a prefix matches several.
+func (s *TaskService) ResolveTask(input string) (*model.Task, error) {
+	input = strings.TrimSpace(input)
+	if _, err := strconv.Atoi(strings.TrimPrefix(input, "#")); err != nil {
+		return repository.GetByPrefix(s.repo, input)
+	}
+	all, err := s.allTasks()
+	if err != nil {
+		return nil, err
+	}
+	id, err := model.ResolveTaskID(input, all)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", input, err)
+	}
+	return s.repo.Get(id)
+}
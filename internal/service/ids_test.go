@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_ResolveTask verifies added tasks are numbered after the
+// journal's highest number, and found by number, UUID or UUID prefix.
+func TestTaskService_ResolveTask(t *testing.T) {
+	repo := memstore.New()
+	old := testutil.NewTask().WithTitle("old").Build()
+	old.Seq = 7
+	testutil.MustSeed(t, repo, old)
+	s := New(repo, nil)
+	added, err := s.AddTask("new", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Seq != 8 {
+		t.Errorf("AddTask() numbered the task %d, want 8", added.Seq)
+	}
+
+	tests := []struct {
+		input   string
+		want    *model.Task
+		wantErr error
+	}{
+		{input: "#8", want: added},
+		{input: "7", want: old},
+		{input: old.ID.String(), want: old},
+		{input: added.ID.String()[:8], want: added},
+		{input: "#9", wantErr: model.ErrTaskNotFound},
+		{input: "", wantErr: model.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := s.ResolveTask(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveTask(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if tt.want != nil && got.ID != tt.want.ID {
+				t.Errorf("ResolveTask(%q) = %q, want %q", tt.input, got.Title, tt.want.Title)
+			}
+		})
+	}
+}
+
+// TestTaskService_Number verifies imported and recurring tasks are
+// numbered like added ones, and that numbers are not reused after tasks
+// are deleted, even by a service opened later on the same journal.
+func TestTaskService_Number(t *testing.T) {
+	now := time.Date(2025, 11, 12, 8, 0, 0, 0, time.Local)
+	defer model.SetClock(model.NewFixedClock(now))()
+	repo := memstore.New()
+	path := SequencePath(filepath.Join(t.TempDir(), "tasks.json"))
+	s := New(repo, nil)
+	s.SetSequence(path)
+
+	added, err := s.AddTask("Call mum", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create, _, _, err := s.PlanImport([]*model.Task{testutil.NewTask().WithTitle("File taxes").WithSeq(added.Seq).Build()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ImportTasks(create, nil); err != nil {
+		t.Fatal(err)
+	}
+	daily := testutil.NewTask().WithTitle("Water the plants").WithSeq(3).Build()
+	daily.Recurrence = &model.Recurrence{Every: 1, Unit: model.RecurDaily, Next: model.StartOfDay(now)}
+	testutil.MustSeed(t, repo, daily)
+	created, err := s.Recur(now)
+	if err != nil || len(created) != 1 {
+		t.Fatalf("Recur() = %v, %v; want one instance", created, err)
+	}
+
+	want := map[string]int{"Call mum": 1, "File taxes": 2, "Water the plants": 4}
+	for _, task := range append(create, created...) {
+		if task.Seq != want[task.Title] {
+			t.Errorf("%q numbered %d, want %d", task.Title, task.Seq, want[task.Title])
+		}
+	}
+
+	if err := s.DeleteTask(created[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	reopened := New(repo, nil)
+	reopened.SetSequence(path)
+	next, err := reopened.AddTask("Book flights", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Seq != 5 {
+		t.Errorf("AddTask() after deleting #4 numbered the task %d, want 5", next.Seq)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// UnestimatedEffort is the effort a plan assumes for a task without an
+// estimate.
+const UnestimatedEffort = 30 * time.Minute
+
+// PlanPolicy chooses what a plan for the day proposes.
+type PlanPolicy struct {
+	// Capacity is how much estimated effort a day holds, counting the
+	// tasks already on today's list. Zero leaves effort unbounded.
+	Capacity time.Duration
+	// Weights ranks the pool by urgency.
+	Weights model.UrgencyWeights
+}
+
+// PlanItem is a task a plan proposes for today.
+type PlanItem struct {
+	Task *model.Task
+	// Effort is the task's estimate, or UnestimatedEffort.
+	Effort time.Duration
+	// Due is set for tasks due today or overdue, which are proposed ahead
+	// of the others even beyond the day's capacity.
+	Due bool
+	// Urgency is the task's urgency score.
+	Urgency float64
+}
+
+// Plan is a proposed today list.
+type Plan struct {
+	// Items are the proposed pool tasks, due ones first, then by urgency.
+	Items []PlanItem
+	// Committed is the effort of the tasks already on today's list, and
+	// Capacity the policy's.
+	Committed time.Duration
+	Capacity  time.Duration
+}
+
+// Effort returns the effort the day holds if the plan is accepted.
+func (p Plan) Effort() time.Duration {
+	total := p.Committed
+	for _, item := range p.Items {
+		total += item.Effort
+	}
+	return total
+}
+
+// effort returns the effort a plan assumes for t.
+func effort(t *model.Task) time.Duration {
+	if t.Estimate > 0 {
+		return t.Estimate
+	}
+	return UnestimatedEffort
+}
+
+// PlanDay proposes pool tasks to add to today's list at now: those due
+// today or overdue first, then the most urgent ones that still fit the
+// day's capacity, skipping any too big for what is left. Tasks snoozed or
+// scheduled for a later day are not proposed, and neither are more than
+// today's limit allows. Nothing is changed until the plan is accepted.
+func (s *TaskService) PlanDay(policy PlanPolicy, now time.Time) (Plan, error) {
+	plan := Plan{Capacity: policy.Capacity}
+	today, pool := model.StatusToday, model.StatusPool
+	picked, err := repository.Scan(s.repo, model.TaskFilter{Status: &today, IncludeSnoozed: true})
+	if err != nil {
+		return plan, err
+	}
+	waiting, err := repository.Scan(s.repo, model.TaskFilter{Status: &pool})
+	if err != nil {
+		return plan, err
+	}
+	for _, t := range picked {
+		plan.Committed += effort(t)
+	}
+
+	var candidates []PlanItem
+	for _, t := range waiting {
+		if t.ScheduledFor != nil && !t.IsScheduledBy(now) {
+			continue
+		}
+		candidates = append(candidates, PlanItem{
+			Task:    t,
+			Effort:  effort(t),
+			Due:     t.IsOverdue(now) || t.IsDueToday(now),
+			Urgency: t.UrgencyWith(policy.Weights, now),
+		})
+	}
+	slices.SortStableFunc(candidates, func(a, b PlanItem) int {
+		if a.Due != b.Due {
+			if a.Due {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(b.Urgency, a.Urgency), a.Task.CreatedAt.Compare(b.Task.CreatedAt))
+	})
+
+	room := len(candidates)
+	if s.todayLimit.Max > 0 {
+		room = max(s.todayLimit.Max-len(picked), 0)
+	}
+	total := plan.Committed
+	for _, c := range candidates {
+		if len(plan.Items) == room {
+			break
+		}
+		if !c.Due && policy.Capacity > 0 && total+c.Effort > policy.Capacity {
+			continue
+		}
+		plan.Items = append(plan.Items, c)
+		total += c.Effort
+	}
+	return plan, nil
+}
+
+// AcceptPlan moves the tasks with the given IDs, as chosen from a plan, to
+// today's list. The moves are saved together and recorded as one step, so
+// one Undo reverts them. Today's limit is not checked again: the plan
+// respected it, and adding to it is the user's choice.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound, moving
+// nothing, when an ID is unknown, or model.ErrInvalidStateTransition for
+// a done task.
+func (s *TaskService) AcceptPlan(ids []model.TaskID) ([]*model.Task, error) {
+	for attempt := 1; ; attempt++ {
+		moved, err := s.acceptPlan(ids)
+		if !errors.Is(err, model.ErrStaleTask) || attempt == maxAttempts {
+			return moved, err
+		}
+	}
+}
+
+// acceptPlan makes one attempt at AcceptPlan.
+func (s *TaskService) acceptPlan(ids []model.TaskID) ([]*model.Task, error) {
+	var moved []*model.Task
+	var changes []Change
+	for _, id := range ids {
+		t, err := s.repo.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		before := t.Clone()
+		if err := t.MoveToToday(); err != nil {
+			return nil, err
+		}
+		changes = append(changes, Change{Before: before, After: t})
+		moved = append(moved, t)
+	}
+	if len(moved) == 0 {
+		return nil, nil
+	}
+	if err := s.repo.SaveAll(moved); err != nil {
+		return nil, err
+	}
+	for i, c := range changes {
+		changes[i].After = c.After.Clone()
+	}
+	s.commit(Step{Action: "plan the day", At: model.Now(), Changes: changes})
+	for _, t := range moved {
+		s.emit(TaskMovedToToday, t)
+	}
+	return moved, nil
+}
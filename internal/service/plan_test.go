@@ -0,0 +1,83 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_PlanDay verifies a plan proposes due tasks first, then
+// the most urgent that fit the day's capacity and today's limit, and that
+// accepting it is one undoable step.
+func TestTaskService_PlanDay(t *testing.T) {
+	now := time.Date(2025, 11, 12, 8, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(now))()
+	tomorrow := now.AddDate(0, 0, 1)
+	later := testutil.NewTask().WithTitle("later").Build()
+	later.ScheduledFor = &tomorrow
+	seed := []*model.Task{
+		testutil.NewTask().WithTitle("picked").WithStatus(model.StatusToday).WithEstimate(time.Hour).Build(),
+		testutil.NewTask().WithTitle("due").WithDue(now.AddDate(0, 0, -1)).WithEstimate(3 * time.Hour).Build(),
+		testutil.NewTask().WithTitle("high").WithPriority(model.PriorityHigh).WithEstimate(30 * time.Minute).Build(),
+		testutil.NewTask().WithTitle("medium").WithPriority(model.PriorityMedium).WithEstimate(2 * time.Hour).Build(),
+		testutil.NewTask().WithTitle("low").WithPriority(model.PriorityLow).Build(),
+		later,
+	}
+	weights := model.DefaultUrgencyWeights()
+
+	tests := []struct {
+		name       string
+		policy     PlanPolicy
+		limit      int
+		want       []string
+		wantEffort time.Duration
+	}{
+		{name: "capacity", policy: PlanPolicy{Capacity: 6 * time.Hour, Weights: weights}, want: []string{"due", "high", "low"}, wantEffort: 5 * time.Hour},
+		{name: "unbounded", policy: PlanPolicy{Weights: weights}, want: []string{"due", "high", "medium", "low"}, wantEffort: 7 * time.Hour},
+		{name: "today limit", policy: PlanPolicy{Weights: weights}, limit: 3, want: []string{"due", "high"}, wantEffort: 4*time.Hour + 30*time.Minute},
+		{name: "due beyond capacity", policy: PlanPolicy{Capacity: 2 * time.Hour, Weights: weights}, want: []string{"due"}, wantEffort: 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			for _, task := range seed {
+				testutil.MustSeed(t, repo, task.Clone())
+			}
+			s := New(repo, nil)
+			s.SetTodayLimit(TodayLimit{Max: tt.limit})
+
+			plan, err := s.PlanDay(tt.policy, now)
+			if err != nil {
+				t.Fatalf("PlanDay() error: %v", err)
+			}
+			var got []string
+			var ids []model.TaskID
+			for _, item := range plan.Items {
+				got = append(got, item.Task.Title)
+				ids = append(ids, item.Task.ID)
+			}
+			if !slices.Equal(got, tt.want) || plan.Effort() != tt.wantEffort {
+				t.Fatalf("PlanDay() = %q taking %v, want %q taking %v", got, plan.Effort(), tt.want, tt.wantEffort)
+			}
+
+			moved, err := s.AcceptPlan(ids)
+			if err != nil || len(moved) != len(ids) {
+				t.Fatalf("AcceptPlan() = %d tasks, %v; want %d", len(moved), err, len(ids))
+			}
+			today := model.StatusToday
+			if n, _ := repo.Count(model.TaskFilter{Status: &today}); n != len(ids)+1 {
+				t.Errorf("today holds %d tasks after accepting, want %d", n, len(ids)+1)
+			}
+			if _, err := s.Undo(); err != nil {
+				t.Fatalf("Undo() error: %v", err)
+			}
+			if n, _ := repo.Count(model.TaskFilter{Status: &today}); n != 1 {
+				t.Errorf("today holds %d tasks after undoing, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Recur creates the instances of every recurring template that have come
+// due by now's day, as each template's missed policy allows (see
+// model.Task.Recur), and returns them. Each template's next occurrence
+// moves past today, so running it again the same day creates nothing;
+// frontends run it when they start and at each rollover.
+//
+// The instances and templates are saved together and recorded as one
+// step, so one Undo removes the instances again. The instances are
+// numbered like added tasks.
+func (s *TaskService) Recur(now time.Time) ([]*model.Task, error) {
+	for attempt := 1; ; attempt++ {
+		created, err := s.recur(now)
+		if !errors.Is(err, model.ErrStaleTask) || attempt == maxAttempts {
+			return created, err
+		}
+	}
+}
+
+// recur makes one attempt at Recur.
+func (s *TaskService) recur(now time.Time) ([]*model.Task, error) {
+	all, err := repository.Scan(s.repo, model.TaskFilter{IncludeSnoozed: true, IncludeRecurring: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changes []Change
+		created []*model.Task
+	)
+	for _, t := range all {
+		if !t.IsRecurring() || t.Status == model.StatusDone {
+			continue
+		}
+		before := t.Clone()
+		instances, err := t.Recur(now)
+		if err != nil {
+			return nil, err
+		}
+		if len(instances) == 0 {
+			continue
+		}
+		changes = append(changes, Change{Before: before, After: t})
+		for _, inst := range instances {
+			changes = append(changes, Change{After: inst})
+		}
+		created = append(created, instances...)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if err := s.number(created...); err != nil {
+		return nil, err
+	}
+	saves := make([]*model.Task, len(changes))
+	for i, c := range changes {
+		saves[i] = c.After
+	}
+	if err := s.repo.SaveAll(saves); err != nil {
+		return nil, err
+	}
+	for i, c := range changes {
+		changes[i].After = c.After.Clone()
+	}
+	s.commit(Step{Action: "repeat", At: model.Now(), Changes: changes})
+	for _, t := range created {
+		s.emit(TaskCreated, t)
+	}
+	return created, nil
+}
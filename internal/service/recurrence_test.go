@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_Recur verifies due templates create their instances,
+// publish them, leave nothing to do the same day, and that one Undo
+// removes the instances and rewinds the templates.
+func TestTaskService_Recur(t *testing.T) {
+	now := time.Date(2025, 11, 12, 8, 0, 0, 0, time.Local)
+	defer model.SetClock(model.NewFixedClock(now))()
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.Local) }
+
+	daily := testutil.NewTask().WithTitle("Water the plants").Build()
+	daily.Recurrence = &model.Recurrence{Every: 1, Unit: model.RecurDaily, Next: day(10), Missed: model.MissedCatchUp}
+	weekly := testutil.NewTask().WithTitle("Take out the bins").Build()
+	weekly.Recurrence = &model.Recurrence{Every: 1, Unit: model.RecurWeekly, Next: day(14)}
+	plain := testutil.NewTask().Build()
+	repo := memstore.New()
+	for _, task := range []*model.Task{daily, weekly, plain} {
+		testutil.MustSeed(t, repo, task)
+	}
+	bus := NewBus()
+	var events []Event
+	bus.Subscribe(func(e Event) { events = append(events, e) })
+	s := New(repo, bus)
+	s.SetHistory(NewHistory(DefaultHistoryLimit))
+
+	created, err := s.Recur(now)
+	if err != nil {
+		t.Fatalf("Recur() error: %v", err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("Recur() created %d tasks, want 3 (10th to 12th)", len(created))
+	}
+	for _, inst := range created {
+		if _, err := repo.Get(inst.ID); err != nil || inst.Title != "Water the plants" {
+			t.Errorf("instance %+v not stored: %v", inst, err)
+		}
+	}
+	if len(events) != 3 || events[0].Type != TaskCreated {
+		t.Errorf("published %+v, want 3 created events", events)
+	}
+	if got, _ := repo.Get(daily.ID); !got.Recurrence.Next.Equal(day(13)) {
+		t.Errorf("template's next occurrence = %v, want the 13th", got.Recurrence.Next)
+	}
+	if listed, _ := s.ListTasks(model.TaskFilter{}); len(listed) != 4 {
+		t.Errorf("ListTasks() = %d tasks, want the plain task and the instances, not templates", len(listed))
+	}
+
+	if again, err := s.Recur(now.Add(time.Hour)); err != nil || len(again) != 0 {
+		t.Errorf("second Recur() = %d tasks, %v; want none", len(again), err)
+	}
+
+	if _, err := s.Undo(); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if n, _ := repo.Count(model.TaskFilter{IncludeRecurring: true}); n != 3 {
+		t.Errorf("after Undo %d tasks stored, want the 3 seeded", n)
+	}
+	if got, _ := repo.Get(daily.ID); !got.Recurrence.Next.Equal(day(10)) {
+		t.Errorf("after Undo next occurrence = %v, want the 10th", got.Recurrence.Next)
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"togo/internal/archive"
+	"togo/internal/atomicfile"
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// ReviewReason says why a task came up for review.
+type ReviewReason string
+
+const (
+	// ReviewOverdue marks a task whose due date has passed.
+	ReviewOverdue ReviewReason = "overdue"
+	// ReviewDeferred marks a task deferred again and again.
+	ReviewDeferred ReviewReason = "deferred"
+	// ReviewStale marks a pool task nobody touched in a long time.
+	ReviewStale ReviewReason = "stale"
+)
+
+// ReviewPolicy chooses the tasks a review goes through. Zero values leave
+// out the corresponding reason.
+type ReviewPolicy struct {
+	// StaleAfter is how long a pool task may go untouched.
+	StaleAfter time.Duration
+	// DeferredAtLeast is how many deferrals make a task come up.
+	DeferredAtLeast int
+}
+
+// ReviewItem is a task to decide on in a review.
+type ReviewItem struct {
+	Task    *model.Task
+	Reasons []ReviewReason
+}
+
+// ReviewItems returns the open tasks a review at now goes through, as
+// policy chooses them: overdue tasks first, then often deferred ones, then
+// stale ones, oldest first within each.
+func (s *TaskService) ReviewItems(policy ReviewPolicy, now time.Time) ([]ReviewItem, error) {
+	tasks, err := repository.Scan(s.repo, model.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var items []ReviewItem
+	for _, t := range tasks {
+		var reasons []ReviewReason
+		if t.IsOverdue(now) {
+			reasons = append(reasons, ReviewOverdue)
+		}
+		if policy.DeferredAtLeast > 0 && t.DeferredCount >= policy.DeferredAtLeast && t.Status != model.StatusDone {
+			reasons = append(reasons, ReviewDeferred)
+		}
+		if policy.StaleAfter > 0 && t.Status == model.StatusPool && now.Sub(t.LastTouched()) >= policy.StaleAfter {
+			reasons = append(reasons, ReviewStale)
+		}
+		if len(reasons) > 0 {
+			items = append(items, ReviewItem{Task: t, Reasons: reasons})
+		}
+	}
+	rank := map[ReviewReason]int{ReviewOverdue: 0, ReviewDeferred: 1, ReviewStale: 2}
+	slices.SortStableFunc(items, func(a, b ReviewItem) int {
+		return cmp.Or(cmp.Compare(rank[a.Reasons[0]], rank[b.Reasons[0]]), a.Task.CreatedAt.Compare(b.Task.CreatedAt))
+	})
+	return items, nil
+}
+
+// ReviewNudges returns the review reminders policy raises at now for the
+// journal, last reviewed at lastReview or never when that is zero.
+func (s *TaskService) ReviewNudges(policy model.NudgePolicy, lastReview, now time.Time) ([]model.Nudge, error) {
+	tasks, err := repository.Scan(s.repo, model.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return model.ReviewNudges(policy, lastReview, tasks, now), nil
+}
+
+// Describe explains the item's reasons at now, such as "overdue since
+// 2025-11-01, deferred 5 times".
+func (r ReviewItem) Describe(now time.Time) string {
+	parts := make([]string, len(r.Reasons))
+	for i, reason := range r.Reasons {
+		switch reason {
+		case ReviewOverdue:
+			parts[i] = "overdue since " + r.Task.DueDate.Format(time.DateOnly)
+		case ReviewDeferred:
+			parts[i] = fmt.Sprintf("deferred %d times", r.Task.DeferredCount)
+		case ReviewStale:
+			parts[i] = fmt.Sprintf("untouched for %d days", int(now.Sub(r.Task.LastTouched())/(24*time.Hour)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// KeepTask records that the task with the given ID was reviewed and is
+// still wanted as it is, so it is not stale again for a while.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID.
+func (s *TaskService) KeepTask(id model.TaskID) (*model.Task, error) {
+	return s.update("keep", id, func(t *model.Task) error {
+		t.UpdatedAt = model.Now()
+		return nil
+	})
+}
+
+// RescheduleTask defers the task with the given ID to day, moving its due
+// date there too when it has passed.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or wrapping model.ErrInvalidStateTransition for a done task.
+func (s *TaskService) RescheduleTask(id model.TaskID, day time.Time) (*model.Task, error) {
+	task, err := s.update("reschedule", id, func(t *model.Task) error {
+		if _, err := t.Defer(&day); err != nil {
+			return err
+		}
+		if t.IsOverdue(model.Now()) {
+			due := day
+			t.DueDate = &due
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.emit(TaskDeferred, task)
+	return task, nil
+}
+
+// Archiver moves tasks out of a repository into an archive, where they
+// can still be searched.
+type Archiver interface {
+	Move(repo repository.TaskRepository, ids []model.TaskID) error
+}
+
+// ErrNoArchive indicates a task cannot be archived as the service has no
+// archive.
+var ErrNoArchive = errors.New("no archive to move tasks into")
+
+// SetArchive makes ArchiveTask move tasks into a.
+func (s *TaskService) SetArchive(a Archiver) {
+	s.archive = a
+}
+
+// ArchiveTask moves the task with the given ID out of the journal into the
+// archive. Like the automatic archiving of done tasks, this cannot be
+// undone and runs no hooks; the task is still found by searching the
+// archive.
+//
+// Returns ErrNoArchive when no archive was set, or a *model.TaskError
+// wrapping model.ErrTaskNotFound for an unknown ID.
+func (s *TaskService) ArchiveTask(id model.TaskID) error {
+	if s.archive == nil {
+		return ErrNoArchive
+	}
+	task, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.archive.Move(s.repo, []model.TaskID{id}); err != nil {
+		return err
+	}
+	s.auditChanges("archive", []Change{{Before: task}})
+	return nil
+}
+
+// ArchiveDone moves the done tasks finished before cutoff, as
+// archive.Finished judges, into the archive, and returns how many it
+// moved. Like ArchiveTask it cannot be undone and runs no hooks.
+//
+// Returns ErrNoArchive when no archive was set.
+func (s *TaskService) ArchiveDone(cutoff time.Time) (int, error) {
+	if s.archive == nil {
+		return 0, ErrNoArchive
+	}
+	done := model.StatusDone
+	tasks, err := s.repo.List(model.TaskFilter{Status: &done})
+	if err != nil {
+		return 0, err
+	}
+	var ids []model.TaskID
+	var changes []Change
+	for _, t := range tasks {
+		if archive.Finished(t).Before(cutoff) {
+			ids = append(ids, t.ID)
+			changes = append(changes, Change{Before: t})
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := s.archive.Move(s.repo, ids); err != nil {
+		return 0, err
+	}
+	s.auditChanges("archive", changes)
+	return len(ids), nil
+}
+
+// ReviewedPath returns where the time of the last review of the journal
+// stored at journal is kept.
+func ReviewedPath(journal string) string {
+	return journal + ".reviewed"
+}
+
+// LastReviewed returns when the review recorded at path was done, or the
+// zero time if none was.
+func LastReviewed(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+}
+
+// MarkReviewed records at path that a review was done at at.
+func MarkReviewed(path string, at time.Time) error {
+	return atomicfile.WriteFile(path, []byte(at.Format(time.RFC3339)+"\n"))
+}
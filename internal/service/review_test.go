@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// archiveStub takes tasks out of the repository, remembering their IDs.
+type archiveStub struct {
+	moved []model.TaskID
+}
+
+func (a *archiveStub) Move(repo repository.TaskRepository, ids []model.TaskID) error {
+	a.moved = append(a.moved, ids...)
+	return repo.DeleteMany(ids)
+}
+
+// TestTaskService_ReviewItems verifies overdue, often deferred and stale
+// tasks come up, in that order, and that each decision takes effect.
+func TestTaskService_ReviewItems(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(now))()
+	longAgo := now.AddDate(0, -2, 0)
+	tasks := map[string]*model.Task{
+		"overdue":  testutil.NewTask().WithTitle("overdue").WithDue(now.AddDate(0, 0, -3)).Build(),
+		"deferred": testutil.NewTask().WithTitle("deferred").WithHistory(testutil.Deferred(now), testutil.Deferred(now), testutil.Deferred(now)).Build(),
+		"stale":    testutil.NewTask().WithTitle("stale").WithCreatedAt(longAgo).Build(),
+		"both":     testutil.NewTask().WithTitle("both").WithCreatedAt(longAgo).WithDue(now.AddDate(0, 0, -1)).Build(),
+		"fresh":    testutil.NewTask().WithTitle("fresh").Build(),
+		"done":     testutil.NewTask().WithTitle("done").WithCreatedAt(longAgo).WithDue(longAgo).WithHistory(testutil.Completed(longAgo)).Build(),
+	}
+	repo := memstore.New()
+	for _, task := range tasks {
+		testutil.MustSeed(t, repo, task)
+	}
+	s := New(repo, nil)
+	policy := ReviewPolicy{StaleAfter: 30 * 24 * time.Hour, DeferredAtLeast: 3}
+
+	items, err := s.ReviewItems(policy, now)
+	if err != nil {
+		t.Fatalf("ReviewItems() error: %v", err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Task.Title+": "+item.Describe(now))
+	}
+	want := []string{
+		"both: overdue since 2025-11-11, untouched for 61 days",
+		"overdue: overdue since 2025-11-09",
+		"deferred: deferred 3 times",
+		"stale: untouched for 61 days",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReviewItems() = %q, want %q", got, want)
+	}
+
+	if err := s.ArchiveTask(tasks["stale"].ID); !errors.Is(err, ErrNoArchive) {
+		t.Errorf("ArchiveTask() without an archive error = %v, want ErrNoArchive", err)
+	}
+	archive := &archiveStub{}
+	s.SetArchive(archive)
+	friday := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	if _, err := s.KeepTask(tasks["both"].ID); err != nil {
+		t.Fatalf("KeepTask() error: %v", err)
+	}
+	if _, err := s.RescheduleTask(tasks["overdue"].ID, friday); err != nil {
+		t.Fatalf("RescheduleTask() error: %v", err)
+	}
+	if err := s.ArchiveTask(tasks["stale"].ID); err != nil {
+		t.Fatalf("ArchiveTask() error: %v", err)
+	}
+	if err := s.DeleteTask(tasks["deferred"].ID); err != nil {
+		t.Fatalf("DeleteTask() error: %v", err)
+	}
+
+	items, _ = s.ReviewItems(policy, now)
+	if len(items) != 1 || items[0].Task.Title != "both" || !slices.Equal(items[0].Reasons, []ReviewReason{ReviewOverdue}) {
+		t.Errorf("after the decisions ReviewItems() = %+v, want only both, no longer stale", items)
+	}
+	rescheduled, _ := repo.Get(tasks["overdue"].ID)
+	if !rescheduled.DueDate.Equal(friday) || !rescheduled.ScheduledFor.Equal(friday) {
+		t.Errorf("rescheduled task due %v, scheduled %v; want both Friday", rescheduled.DueDate, rescheduled.ScheduledFor)
+	}
+	if !slices.Equal(archive.moved, []model.TaskID{tasks["stale"].ID}) {
+		t.Errorf("archived %v, want the stale task", archive.moved)
+	}
+}
+
+// TestMarkReviewed verifies the review time round-trips and is zero
+// before the first review.
+func TestMarkReviewed(t *testing.T) {
+	path := ReviewedPath(filepath.Join(t.TempDir(), "journal.json"))
+	if at, err := LastReviewed(path); err != nil || !at.IsZero() {
+		t.Fatalf("LastReviewed() before a review = %v, %v; want zero", at, err)
+	}
+	at := time.Date(2025, 11, 12, 9, 30, 0, 0, time.UTC)
+	if err := MarkReviewed(path, at); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LastReviewed(path); err != nil || !got.Equal(at) {
+		t.Errorf("LastReviewed() = %v, %v; want %v", got, err, at)
+	}
+}
+
+// TestTaskService_ReviewNudges verifies the journal's tasks and last review
+// decide which reminders are raised.
+func TestTaskService_ReviewNudges(t *testing.T) {
+	now := time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC)
+	policy := model.DefaultNudgePolicy()
+	tests := []struct {
+		name       string
+		lastReview time.Time
+		want       []model.NudgeKind
+	}{
+		{"reviewed yesterday", now.AddDate(0, 0, -1), []model.NudgeKind{model.NudgeEmptyToday}},
+		{"reviewed two weeks ago", now.AddDate(0, 0, -14), []model.NudgeKind{model.NudgeReviewOverdue, model.NudgeEmptyToday}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			testutil.MustSeed(t, repo, testutil.NewTask().WithCreatedAt(now.AddDate(0, -1, 0)).Build())
+			nudges, err := New(repo, nil).ReviewNudges(policy, tt.lastReview, now)
+			if err != nil {
+				t.Fatalf("ReviewNudges() error: %v", err)
+			}
+			var got []model.NudgeKind
+			for _, n := range nudges {
+				got = append(got, n.Kind)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ReviewNudges() kinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
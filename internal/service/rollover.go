@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// Rollover says what a rollover moved.
+type Rollover struct {
+	// Deferred are the tasks returned from an earlier day's today list to
+	// the pool.
+	Deferred []*model.Task
+	// Pulled are the tasks moved to today because they were scheduled for
+	// it.
+	Pulled []*model.Task
+}
+
+// Rollover migrates the journal into now's day, as a bullet journal is
+// each morning: tasks on the today list untouched since before today go
+// back to the pool, deferred, and pool tasks scheduled for today or
+// earlier move to today, their schedule met. Snoozed tasks wait. Running
+// it again the same day changes nothing, so frontends can run it whenever
+// they start.
+//
+// All the moves are saved together and recorded as one step, so one Undo
+// reverts the rollover.
+func (s *TaskService) Rollover(now time.Time) (Rollover, error) {
+	for attempt := 1; ; attempt++ {
+		r, err := s.rollover(now)
+		if !errors.Is(err, model.ErrStaleTask) || attempt == maxAttempts {
+			return r, err
+		}
+	}
+}
+
+// rollover makes one attempt at Rollover.
+func (s *TaskService) rollover(now time.Time) (Rollover, error) {
+	var r Rollover
+	today, pool := model.StatusToday, model.StatusPool
+	picked, err := repository.Scan(s.repo, model.TaskFilter{Status: &today})
+	if err != nil {
+		return r, err
+	}
+	waiting, err := repository.Scan(s.repo, model.TaskFilter{Status: &pool})
+	if err != nil {
+		return r, err
+	}
+
+	var changes []Change
+	for _, t := range picked {
+		if !t.UpdatedAt.Before(model.StartOfDay(now)) {
+			continue
+		}
+		before := t.Clone()
+		if _, err := t.Defer(nil); err != nil {
+			return Rollover{}, err
+		}
+		changes = append(changes, Change{Before: before, After: t})
+		r.Deferred = append(r.Deferred, t)
+	}
+	for _, t := range waiting {
+		if !t.IsScheduledBy(now) || t.IsSnoozed(now) {
+			continue
+		}
+		before := t.Clone()
+		if err := t.MoveToToday(); err != nil {
+			return Rollover{}, err
+		}
+		t.ScheduledFor = nil
+		changes = append(changes, Change{Before: before, After: t})
+		r.Pulled = append(r.Pulled, t)
+	}
+	if len(changes) == 0 {
+		return r, nil
+	}
+
+	saves := make([]*model.Task, len(changes))
+	for i, c := range changes {
+		saves[i] = c.After
+	}
+	if err := s.repo.SaveAll(saves); err != nil {
+		return Rollover{}, err
+	}
+	for i, c := range changes {
+		changes[i].After = c.After.Clone()
+	}
+	s.commit(Step{Action: "roll over", At: model.Now(), Changes: changes})
+	for _, t := range r.Deferred {
+		s.emit(TaskDeferred, t)
+	}
+	for _, t := range r.Pulled {
+		s.emit(TaskMovedToToday, t)
+	}
+	return r, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_Rollover verifies unfinished tasks picked on an earlier
+// day go back to the pool, tasks scheduled by today come in, a second run
+// the same day changes nothing, and one Undo reverts it all.
+func TestTaskService_Rollover(t *testing.T) {
+	now := time.Date(2025, 11, 12, 8, 0, 0, 0, time.Local)
+	defer model.SetClock(model.NewFixedClock(now))()
+	yesterday, tomorrow := now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)
+	later := now.Add(4 * time.Hour)
+
+	tasks := map[string]*model.Task{
+		"picked yesterday": testutil.NewTask().WithHistory(testutil.Picked(yesterday)).Build(),
+		"picked today":     testutil.NewTask().WithHistory(testutil.Picked(now.Add(-time.Hour))).Build(),
+		"done yesterday":   testutil.NewTask().WithHistory(testutil.Picked(yesterday), testutil.Completed(yesterday)).Build(),
+		"due today":        testutil.NewTask().Build(),
+		"overdue":          testutil.NewTask().Build(),
+		"due tomorrow":     testutil.NewTask().Build(),
+		"snoozed":          testutil.NewTask().Build(),
+	}
+	tasks["due today"].ScheduledFor = &now
+	tasks["overdue"].ScheduledFor = &yesterday
+	tasks["due tomorrow"].ScheduledFor = &tomorrow
+	tasks["snoozed"].ScheduledFor = &now
+	tasks["snoozed"].SnoozedUntil = &later
+	repo := memstore.New()
+	for _, task := range tasks {
+		testutil.MustSeed(t, repo, task)
+	}
+	s := New(repo, nil)
+
+	r, err := s.Rollover(now)
+	if err != nil {
+		t.Fatalf("Rollover() error: %v", err)
+	}
+	if len(r.Deferred) != 1 || len(r.Pulled) != 2 {
+		t.Errorf("Rollover() deferred %d and pulled %d, want 1 and 2", len(r.Deferred), len(r.Pulled))
+	}
+	want := map[string]model.TaskStatus{
+		"picked yesterday": model.StatusPool,
+		"picked today":     model.StatusToday,
+		"done yesterday":   model.StatusDone,
+		"due today":        model.StatusToday,
+		"overdue":          model.StatusToday,
+		"due tomorrow":     model.StatusPool,
+		"snoozed":          model.StatusPool,
+	}
+	check := func(when string, want map[string]model.TaskStatus) {
+		t.Helper()
+		for name, status := range want {
+			got, err := repo.Get(tasks[name].ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != status {
+				t.Errorf("%s: %q is %q, want %q", when, name, got.Status, status)
+			}
+		}
+	}
+	check("after Rollover", want)
+	if got, _ := repo.Get(tasks["picked yesterday"].ID); got.DeferredCount != 1 {
+		t.Errorf("returned task deferred %d times, want 1", got.DeferredCount)
+	}
+	if got, _ := repo.Get(tasks["due today"].ID); got.ScheduledFor != nil {
+		t.Errorf("pulled task still scheduled for %v", got.ScheduledFor)
+	}
+
+	if r, err := s.Rollover(now.Add(time.Hour)); err != nil || len(r.Deferred)+len(r.Pulled) != 0 {
+		t.Errorf("second Rollover() = %+v, %v; want nothing moved", r, err)
+	}
+
+	step, err := s.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if len(step.Changes) != 3 {
+		t.Errorf("Undo() reverted %d changes, want 3", len(step.Changes))
+	}
+	check("after Undo", map[string]model.TaskStatus{
+		"picked yesterday": model.StatusToday,
+		"due today":        model.StatusPool,
+		"overdue":          model.StatusPool,
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"togo/internal/model"
+)
+
+// Stats measures the work done over a range of days.
+type Stats struct {
+	// From and To are the first and last day of the range, inclusive.
+	From, To time.Time
+
+	// Completed counts the tasks completed in the range, PerDay for each
+	// of its days and PerWeek for each week, starting on Monday, that
+	// overlaps it; both oldest first.
+	Completed int
+	PerDay    []DayCount
+	PerWeek   []DayCount
+
+	// Created counts the tasks created in the range, and Open the tasks
+	// not done now.
+	Created int
+	Open    int
+
+	// AverageAge is the mean time from creation to completion of the
+	// tasks completed in the range.
+	AverageAge time.Duration
+
+	// Deferred counts the tasks completed in the range that were deferred
+	// at least once, and Deferrals how often they were deferred in all.
+	Deferred  int
+	Deferrals int
+
+	// Tags counts the tasks completed in the range by tag, most first.
+	// Untagged tasks are not counted.
+	Tags []TagCount
+}
+
+// DayCount is a number of tasks for the day, or week, starting at Day.
+type DayCount struct {
+	Day   time.Time
+	Count int
+}
+
+// TagCount is a number of tasks carrying Tag.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// DeferralRate is the share of the tasks completed in the range that were
+// deferred before they were done, from 0 to 1.
+func (s Stats) DeferralRate() float64 {
+	if s.Completed == 0 {
+		return 0
+	}
+	return float64(s.Deferred) / float64(s.Completed)
+}
+
+// Stats measures the journal's work from the day of from to the day of
+// to, inclusive. A zero from starts the range at the day the oldest task
+// was created. Tasks already moved to the archive are not counted.
+func (s *TaskService) Stats(from, to time.Time) (Stats, error) {
+	tasks, err := s.repo.List(model.TaskFilter{IncludeSnoozed: true})
+	if err != nil {
+		return Stats{}, err
+	}
+	return computeStats(tasks, from, to), nil
+}
+
+// computeStats measures tasks over the days from from to to.
+func computeStats(tasks []*model.Task, from, to time.Time) Stats {
+	loc := to.Location()
+	if from.IsZero() {
+		from = to
+		for _, t := range tasks {
+			if t.CreatedAt.Before(from) {
+				from = t.CreatedAt
+			}
+		}
+	}
+	st := Stats{From: model.StartOfDay(from.In(loc)), To: model.StartOfDay(to)}
+	end := st.To.AddDate(0, 0, 1)
+	in := func(at time.Time) bool { return !at.Before(st.From) && at.Before(end) }
+
+	perDay := make(map[time.Time]int)
+	perTag := make(map[string]int)
+	var age time.Duration
+	for _, t := range tasks {
+		if in(t.CreatedAt) {
+			st.Created++
+		}
+		if t.Status != model.StatusDone {
+			st.Open++
+			continue
+		}
+		if t.CompletedAt == nil || !in(*t.CompletedAt) {
+			continue
+		}
+		st.Completed++
+		perDay[model.StartOfDay(t.CompletedAt.In(loc))]++
+		age += t.CompletedAt.Sub(t.CreatedAt)
+		if t.DeferredCount > 0 {
+			st.Deferred++
+			st.Deferrals += t.DeferredCount
+		}
+		for _, tag := range t.Tags {
+			perTag[tag]++
+		}
+	}
+	if st.Completed > 0 {
+		st.AverageAge = age / time.Duration(st.Completed)
+	}
+
+	for day := st.From; day.Before(end); day = day.AddDate(0, 0, 1) {
+		st.PerDay = append(st.PerDay, DayCount{Day: day, Count: perDay[day]})
+		week := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		if n := len(st.PerWeek); n == 0 || !st.PerWeek[n-1].Day.Equal(week) {
+			st.PerWeek = append(st.PerWeek, DayCount{Day: week})
+		}
+		st.PerWeek[len(st.PerWeek)-1].Count += perDay[day]
+	}
+	for tag, n := range perTag {
+		st.Tags = append(st.Tags, TagCount{Tag: tag, Count: n})
+	}
+	slices.SortFunc(st.Tags, func(a, b TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+	})
+	return st
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_Stats verifies completions are counted per day and week
+// within the range, with the average age, deferrals and tag breakdown of
+// the tasks completed in it.
+func TestTaskService_Stats(t *testing.T) {
+	// Wednesday 12 November 2025; its week started on Monday the 10th.
+	day := func(d, h int) time.Time { return time.Date(2025, 11, d, h, 0, 0, 0, time.UTC) }
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(
+		testutil.NewTask().WithTags("work").WithCreatedAt(day(8, 9)).WithHistory(testutil.Completed(day(10, 9))),
+		testutil.NewTask().WithTags("work", "admin").WithCreatedAt(day(10, 9)).
+			WithHistory(testutil.Deferred(day(10, 18)), testutil.Deferred(day(11, 18)), testutil.Completed(day(12, 9))),
+		testutil.NewTask().WithTags("home").WithCreatedAt(day(11, 9)).WithHistory(testutil.Completed(day(11, 21))),
+		testutil.NewTask().WithTags("old").WithCreatedAt(day(1, 9)).WithHistory(testutil.Completed(day(2, 9))),
+		testutil.NewTask().WithCreatedAt(day(12, 8)),
+	)...)
+	s := New(repo, nil)
+
+	tests := []struct {
+		name          string
+		from          time.Time
+		wantCompleted int
+		wantCreated   int
+		wantDays      []int
+		wantWeeks     []int
+		wantAge       time.Duration
+		wantRate      float64
+		wantTags      []TagCount
+	}{
+		{
+			name:          "this week",
+			from:          day(10, 0),
+			wantCompleted: 3,
+			wantCreated:   3,
+			wantDays:      []int{1, 1, 1},
+			wantWeeks:     []int{3},
+			wantAge:       (48*time.Hour + 48*time.Hour + 12*time.Hour) / 3,
+			wantRate:      1.0 / 3,
+			wantTags:      []TagCount{{"work", 2}, {"admin", 1}, {"home", 1}},
+		},
+		{
+			name:          "everything",
+			wantCompleted: 4,
+			wantCreated:   5,
+			wantDays:      []int{0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1},
+			wantWeeks:     []int{1, 0, 3},
+			wantAge:       (48*time.Hour + 48*time.Hour + 12*time.Hour + 24*time.Hour) / 4,
+			wantRate:      1.0 / 4,
+			wantTags:      []TagCount{{"work", 2}, {"admin", 1}, {"home", 1}, {"old", 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := s.Stats(tt.from, day(12, 20))
+			if err != nil {
+				t.Fatalf("Stats() error: %v", err)
+			}
+			counts := func(dc []DayCount) []int {
+				var n []int
+				for _, c := range dc {
+					n = append(n, c.Count)
+				}
+				return n
+			}
+			if st.Completed != tt.wantCompleted || st.Created != tt.wantCreated || st.Open != 1 {
+				t.Errorf("Stats() completed %d, created %d, open %d; want %d, %d, 1", st.Completed, st.Created, st.Open, tt.wantCompleted, tt.wantCreated)
+			}
+			if got := counts(st.PerDay); !slices.Equal(got, tt.wantDays) {
+				t.Errorf("PerDay = %v, want %v", got, tt.wantDays)
+			}
+			if got := counts(st.PerWeek); !slices.Equal(got, tt.wantWeeks) {
+				t.Errorf("PerWeek = %v, want %v", got, tt.wantWeeks)
+			}
+			if st.AverageAge != tt.wantAge {
+				t.Errorf("AverageAge = %v, want %v", st.AverageAge, tt.wantAge)
+			}
+			if st.DeferralRate() != tt.wantRate || st.Deferrals != 2 {
+				t.Errorf("DeferralRate() = %v with %d deferrals, want %v with 2", st.DeferralRate(), st.Deferrals, tt.wantRate)
+			}
+			if !slices.Equal(st.Tags, tt.wantTags) {
+				t.Errorf("Tags = %v, want %v", st.Tags, tt.wantTags)
+			}
+		})
+	}
+}
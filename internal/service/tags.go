@@ -0,0 +1,115 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// ErrTagNotFound indicates no task carries a tag to rename or merge.
+var ErrTagNotFound = errors.New("no task carries the tag")
+
+// allTasks lists every task of the journal, snoozed ones and recurring
+// templates included.
+func (s *TaskService) allTasks() ([]*model.Task, error) {
+	return repository.Scan(s.repo, model.TaskFilter{IncludeSnoozed: true, IncludeRecurring: true})
+}
+
+// Tags counts the tasks carrying each tag, done ones included, most used
+// first.
+func (s *TaskService) Tags() ([]TagCount, error) {
+	tasks, err := s.allTasks()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]TagCount, 0, len(counts))
+	for tag, n := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: n})
+	}
+	slices.SortFunc(tags, func(a, b TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+	})
+	return tags, nil
+}
+
+// RenameTag renames the tag old to new on every task carrying it or a tag
+// nested below it, as MergeTags does.
+func (s *TaskService) RenameTag(old, new string) ([]*model.Task, error) {
+	return s.retag("rename tag", []string{old}, new)
+}
+
+// MergeTags replaces the tags from, and the tags nested below them, by
+// into on every task carrying them; into need not exist yet. All the
+// changed tasks are saved together and recorded as one step, so one Undo
+// reverts the merge, and none is saved if one fails to validate. It
+// returns the changed tasks.
+//
+// Returns a *model.ValidationError for a malformed tag, a
+// *model.TaskError wrapping the validation errors of a task whose renamed
+// tags grow past the size limits, or ErrTagNotFound when no task carries
+// any of from.
+func (s *TaskService) MergeTags(from []string, into string) ([]*model.Task, error) {
+	return s.retag("merge tags", from, into)
+}
+
+// retag runs RenameTag and MergeTags, recording the step as action.
+func (s *TaskService) retag(action string, from []string, to string) ([]*model.Task, error) {
+	for _, tag := range append(slices.Clone(from), to) {
+		if err := model.ValidateTag(tag); err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		changed, err := s.retagOnce(action, from, to)
+		if !errors.Is(err, model.ErrStaleTask) || attempt == maxAttempts {
+			return changed, err
+		}
+	}
+}
+
+// retagOnce makes one attempt at retag.
+func (s *TaskService) retagOnce(action string, from []string, to string) ([]*model.Task, error) {
+	tasks, err := s.allTasks()
+	if err != nil {
+		return nil, err
+	}
+	var changed []*model.Task
+	var changes []Change
+	for _, t := range tasks {
+		before := t.Clone()
+		if !t.Retag(from, to) {
+			continue
+		}
+		if err := t.Validate(); err != nil {
+			return nil, &model.TaskError{ID: t.ID, Op: action, Err: err}
+		}
+		t.UpdatedAt = model.Now()
+		changes = append(changes, Change{Before: before, After: t})
+		changed = append(changed, t)
+	}
+	if len(changed) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTagNotFound, strings.Join(from, ", "))
+	}
+	if err := s.repo.SaveAll(changed); err != nil {
+		return nil, err
+	}
+	for i, c := range changes {
+		changes[i].After = c.After.Clone()
+	}
+	s.commit(Step{Action: action, At: model.Now(), Changes: changes})
+	for _, t := range changed {
+		s.emit(TaskEdited, t)
+	}
+	return changed, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_RenameAndMergeTags verifies renaming and merging change every
+// affected task in one undoable step, and refuse without changing any.
+func TestTaskService_RenameAndMergeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		retag    func(*TaskService) ([]*model.Task, error)
+		want     [][]string
+		wantN    int
+		wantErr  error
+		wantTags []TagCount
+	}{
+		{
+			name:     "rename",
+			retag:    func(s *TaskService) ([]*model.Task, error) { return s.RenameTag("work", "job") },
+			want:     [][]string{{"job", "home"}, {"job/backend"}, {"errand"}, {"chores", "home"}},
+			wantN:    2,
+			wantTags: []TagCount{{"home", 2}, {"chores", 1}, {"errand", 1}, {"job", 1}, {"job/backend", 1}},
+		},
+		{
+			name:     "merge",
+			retag:    func(s *TaskService) ([]*model.Task, error) { return s.MergeTags([]string{"errand", "chores"}, "home") },
+			want:     [][]string{{"work", "home"}, {"work/backend"}, {"home"}, {"home"}},
+			wantN:    2,
+			wantTags: []TagCount{{"home", 3}, {"work", 1}, {"work/backend", 1}},
+		},
+		{
+			name:    "unknown tag",
+			retag:   func(s *TaskService) ([]*model.Task, error) { return s.RenameTag("play", "fun") },
+			wantErr: ErrTagNotFound,
+		},
+		{
+			name:  "malformed tag",
+			retag: func(s *TaskService) ([]*model.Task, error) { return s.RenameTag("work", "deep work") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			tasks := testutil.Tasks(
+				testutil.NewTask().WithTags("work", "home"),
+				testutil.NewTask().WithTags("work/backend"),
+				testutil.NewTask().WithTags("errand"),
+				testutil.NewTask().WithTags("chores", "home").WithStatus(model.StatusDone),
+			)
+			testutil.MustSeed(t, repo, tasks...)
+			s := New(repo, nil)
+
+			changed, err := tt.retag(s)
+			if tt.want == nil {
+				if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if _, err := s.Undo(); !errors.Is(err, ErrNothingToUndo) {
+					t.Errorf("a refused change was recorded")
+				}
+				return
+			}
+			if err != nil || len(changed) != tt.wantN {
+				t.Fatalf("changed %d tasks, error %v; want %d", len(changed), err, tt.wantN)
+			}
+			for i, task := range tasks {
+				if got, _ := repo.Get(task.ID); !slices.Equal(got.Tags, tt.want[i]) {
+					t.Errorf("task %d tags = %q, want %q", i, got.Tags, tt.want[i])
+				}
+			}
+			if got, _ := s.Tags(); !slices.Equal(got, tt.wantTags) {
+				t.Errorf("Tags() = %v, want %v", got, tt.wantTags)
+			}
+
+			if _, err := s.Undo(); err != nil {
+				t.Fatalf("Undo() error: %v", err)
+			}
+			for _, task := range tasks {
+				if got, _ := repo.Get(task.ID); !slices.Equal(got.Tags, task.Tags) {
+					t.Errorf("after undo tags = %q, want %q", got.Tags, task.Tags)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+// Package service holds togo's use cases: adding, completing, deferring,
+// scheduling, editing and deleting tasks. Each composes the model's
+// validation and state transitions with the repository calls they need,
+// and publishes what happened as an Event on a Bus, so the TUI and the CLI
+// work with tasks without knowing how they are stored, and side effects
+// subscribe to events instead of being wired into use cases.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"togo/internal/audit"
+	"togo/internal/conflict"
+	"togo/internal/model"
+	"togo/internal/repository"
+)
+
+// EventType names what a use case did to a task.
+type EventType string
+
+const (
+	TaskCreated      EventType = "created"
+	TaskCompleted    EventType = "completed"
+	TaskDeferred     EventType = "deferred"
+	TaskMovedToToday EventType = "moved_to_today"
+	TaskEdited       EventType = "edited"
+	TaskDeleted      EventType = "deleted"
+)
+
+// Event reports a change a use case made, once it is stored.
+type Event struct {
+	Type EventType
+	At   time.Time
+	// Task is a copy of the task as stored after the change, or as it was
+	// before a TaskDeleted.
+	Task *model.Task
+}
+
+// maxAttempts bounds how often a change is retried when another writer
+// saved the task between reading and saving it.
+const maxAttempts = 3
+
+// TaskService runs use cases against a repository. It is safe for
+// concurrent use if the repository is.
+type TaskService struct {
+	repo repository.TaskRepository
+	bus  *Bus
+	// history records each change so it can be undone.
+	history *History
+	// archive receives the tasks ArchiveTask moves; nil until SetArchive.
+	archive Archiver
+	// todayLimit caps today's list for MoveToToday.
+	todayLimit TodayLimit
+	// audit, if not nil, receives every change, made from source;
+	// auditFailed, if not nil, is told when it cannot.
+	audit       *audit.Log
+	source      string
+	auditFailed func(error)
+	// seqPath, if not empty, keeps the highest sequence number handed
+	// out; seqMu serializes numbering.
+	seqPath string
+	seqMu   sync.Mutex
+}
+
+// New returns a service storing tasks in repo and publishing each change
+// on bus once it is stored; a nil bus publishes nothing. Changes are
+// recorded in a history held in memory until SetHistory replaces it.
+func New(repo repository.TaskRepository, bus *Bus) *TaskService {
+	return &TaskService{repo: repo, bus: bus, history: NewHistory(DefaultHistoryLimit)}
+}
+
+// SetHistory records changes in h from now on, such as a history file
+// shared with other processes.
+func (s *TaskService) SetHistory(h *History) {
+	s.history = h
+}
+
+// SetAudit appends every change from now on to log, undone and redone
+// ones included, as made from source, one of the audit.Source constants.
+// A change the log cannot take is stored all the same, and the failure
+// passed to onError, if not nil.
+func (s *TaskService) SetAudit(log *audit.Log, source string, onError func(error)) {
+	s.audit, s.source, s.auditFailed = log, source, onError
+}
+
+// AddTask creates a task in the pool with the given title and tags,
+// numbered after the journal's other tasks.
+//
+// Returns the errors of model.NewTask for an empty or oversized title or
+// too many tags.
+func (s *TaskService) AddTask(title string, tags []string) (*model.Task, error) {
+	task, err := model.NewTask(title, tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.number(task); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(task); err != nil {
+		return nil, err
+	}
+	s.record("add", nil, task)
+	s.emit(TaskCreated, task)
+	return task, nil
+}
+
+// CompleteTask marks the task with the given ID done.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or model.ErrInvalidStateTransition for a task already done.
+func (s *TaskService) CompleteTask(id model.TaskID) (*model.Task, error) {
+	task, err := s.update("complete", id, (*model.Task).Complete)
+	if err != nil {
+		return nil, err
+	}
+	s.emit(TaskCompleted, task)
+	return task, nil
+}
+
+// ReopenTask returns the done task with the given ID to the pool, as
+// model.Task.Reopen does.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or model.ErrInvalidStateTransition for a task that is not done.
+func (s *TaskService) ReopenTask(id model.TaskID) (*model.Task, error) {
+	task, err := s.update("reopen", id, (*model.Task).Reopen)
+	if err != nil {
+		return nil, err
+	}
+	s.emit(TaskEdited, task)
+	return task, nil
+}
+
+// DeferTask moves the task with the given ID back to the pool, scheduled
+// for until if it is not nil, as model.Task.Defer does. The returned
+// warning, if any, says the task has been deferred too often; the deferral
+// still happened.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or model.ErrInvalidStateTransition for a done task.
+func (s *TaskService) DeferTask(id model.TaskID, until *time.Time) (*model.Task, *model.DeferWarning, error) {
+	var warning *model.DeferWarning
+	task, err := s.update("defer", id, func(t *model.Task) error {
+		var err error
+		warning, err = t.Defer(until)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.emit(TaskDeferred, task)
+	return task, warning, nil
+}
+
+// MoveToToday commits the task with the given ID to today's list. When
+// the list is already full, as SetTodayLimit set, the returned warning
+// says so and the task was moved all the same, unless the limit blocks.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, model.ErrInvalidStateTransition for a done task, or ErrTodayFull
+// when the list is full and the limit blocks.
+func (s *TaskService) MoveToToday(id model.TaskID) (*model.Task, *CapacityWarning, error) {
+	var warning *CapacityWarning
+	task, err := s.update("move to today", id, func(t *model.Task) error {
+		var err error
+		if warning, err = s.checkCapacity(t); err != nil {
+			return err
+		}
+		return t.MoveToToday()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.emit(TaskMovedToToday, task)
+	return task, warning, nil
+}
+
+// EditTask applies edit to the task with the given ID and saves it, unless
+// edit changed nothing or returned an error. Another writer's save in
+// between is not overwritten: edit is applied again to the task as that
+// writer left it, so it must be safe to repeat.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or wrapping model.ValidationErrors when the edited task is invalid.
+func (s *TaskService) EditTask(id model.TaskID, edit func(*model.Task) error) (*model.Task, error) {
+	task, err := s.update("edit", id, func(t *model.Task) error {
+		before := t.Clone()
+		if err := edit(t); err != nil {
+			return err
+		}
+		if len(before.Diff(t)) == 0 {
+			return errUnchanged
+		}
+		t.UpdatedAt = model.Now()
+		return nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return s.repo.Get(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.emit(TaskEdited, task)
+	return task, nil
+}
+
+// PinTask pins the task with the given ID, keeping it at the top of the
+// views it appears in, or unpins it when pinned is false. A task already
+// so is left as it is.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID.
+func (s *TaskService) PinTask(id model.TaskID, pinned bool) (*model.Task, error) {
+	return s.EditTask(id, func(t *model.Task) error {
+		if t.Pinned != pinned {
+			t.TogglePin()
+		}
+		return nil
+	})
+}
+
+// SnoozeTask hides the task with the given ID from default views until
+// the given moment.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID, or wrapping model.ErrInvalidStateTransition for a done task.
+func (s *TaskService) SnoozeTask(id model.TaskID, until time.Time) (*model.Task, error) {
+	return s.EditTask(id, func(t *model.Task) error { return t.Snooze(until) })
+}
+
+// DeleteTask removes the task with the given ID.
+//
+// Returns a *model.TaskError wrapping model.ErrTaskNotFound for an unknown
+// ID.
+func (s *TaskService) DeleteTask(id model.TaskID) error {
+	task, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.record("delete", task, nil)
+	s.emit(TaskDeleted, task)
+	return nil
+}
+
+// errUnchanged stops update from saving an edit that changed nothing.
+var errUnchanged = errors.New("unchanged")
+
+// ListTasks returns the tasks matching filter, as repository.Scan does.
+func (s *TaskService) ListTasks(filter model.TaskFilter) ([]*model.Task, error) {
+	return repository.Scan(s.repo, filter)
+}
+
+// ResolveConflict settles a sync conflict recorded in sidecar by keeping
+// side's value of field, as conflict.Sidecar.Resolve does. Undoing it
+// restores the task, but not the conflict.
+func (s *TaskService) ResolveConflict(sidecar *conflict.Sidecar, id model.TaskID, field string, side conflict.Side) error {
+	before, _ := s.repo.Get(id)
+	if err := sidecar.Resolve(s.repo, id, field, side); err != nil {
+		return err
+	}
+	if after, err := s.repo.Get(id); err == nil && before != nil && len(before.Diff(after)) > 0 {
+		s.record("resolve", before, after)
+	}
+	return nil
+}
+
+// Undo reverts the latest recorded change and returns it.
+//
+// Returns ErrNothingToUndo when there is none, or a *model.TaskError
+// wrapping model.ErrStaleTask when a task it touched was changed again
+// since; such a change can never be undone, so it is dropped.
+func (s *TaskService) Undo() (Step, error) {
+	return s.history.undo(func(step Step) error { return s.apply("undo", step) })
+}
+
+// Redo makes the latest undone change again and returns it, failing as
+// Undo does, or with ErrNothingToRedo.
+func (s *TaskService) Redo() (Step, error) {
+	return s.history.redo(func(step Step) error { return s.apply("redo", step) })
+}
+
+// apply stores each task step changes as it left it, provided every one
+// is still as the step found it. The tasks count as changed now, so a
+// sync merge does not prefer the copies the step replaces.
+func (s *TaskService) apply(op string, step Step) error {
+	var saves []*model.Task
+	var deletes []model.TaskID
+	var events []Event
+	var changes []Change
+	for _, c := range step.Changes {
+		id := c.id()
+		stored, err := s.repo.Get(id)
+		if errors.Is(err, model.ErrTaskNotFound) {
+			stored, err = nil, nil
+		}
+		if err != nil {
+			return err
+		}
+		if !sameTask(stored, c.Before) {
+			return &model.TaskError{ID: id, Op: op, Err: model.ErrStaleTask}
+		}
+		if c.After == nil {
+			deletes = append(deletes, id)
+			events = append(events, Event{Type: TaskDeleted, Task: stored})
+			changes = append(changes, Change{Before: stored})
+			continue
+		}
+		task := c.After.Clone()
+		task.UpdatedAt = model.Now()
+		typ := TaskCreated
+		task.Version = 0
+		if stored != nil {
+			typ, task.Version = TaskEdited, stored.Version
+		}
+		saves = append(saves, task)
+		events = append(events, Event{Type: typ, Task: task})
+		changes = append(changes, Change{Before: stored, After: task})
+	}
+	if err := s.repo.SaveAll(saves); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteMany(deletes); err != nil {
+		return err
+	}
+	s.auditChanges(op+" "+step.Action, changes)
+	for _, e := range events {
+		s.emit(e.Type, e.Task)
+	}
+	return nil
+}
+
+// sameTask reports whether stored, nil when missing, holds what want does.
+func sameTask(stored, want *model.Task) bool {
+	if stored == nil || want == nil {
+		return stored == want
+	}
+	return len(stored.Diff(want)) == 0
+}
+
+// Invalidate drops whatever the repository caches, so the next read sees
+// changes other processes made.
+func (s *TaskService) Invalidate() {
+	if r, ok := s.repo.(interface{ Invalidate() }); ok {
+		r.Invalidate()
+	}
+}
+
+// update reads the task with the given ID, applies change and saves it,
+// starting over from the stored task if another writer saved it first.
+// The change is recorded as action.
+func (s *TaskService) update(action string, id model.TaskID, change func(*model.Task) error) (*model.Task, error) {
+	for attempt := 1; ; attempt++ {
+		task, err := s.repo.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		before := task.Clone()
+		if err := change(task); err != nil {
+			return nil, err
+		}
+		err = s.repo.Save(task)
+		if err == nil {
+			s.record(action, before, task)
+			return task, nil
+		}
+		if !errors.Is(err, model.ErrStaleTask) || attempt == maxAttempts {
+			return nil, err
+		}
+	}
+}
+
+// record adds a change to one task to the history. Recording is best
+// effort: a change the history cannot hold is stored all the same, and
+// only cannot be undone.
+func (s *TaskService) record(action string, before, after *model.Task) {
+	var change Change
+	if before != nil {
+		change.Before = before.Clone()
+	}
+	if after != nil {
+		change.After = after.Clone()
+	}
+	s.commit(Step{Action: action, At: model.Now(), Changes: []Change{change}})
+}
+
+// commit records a step in the history, so it can be undone, and in the
+// audit log. Like recording, auditing is best effort: a change the log
+// cannot hold is stored all the same, and only reported.
+func (s *TaskService) commit(step Step) {
+	_ = s.history.Record(step)
+	s.auditChanges(step.Action, step.Changes)
+}
+
+// auditChanges appends changes, made by action, to the audit log,
+// reporting a failure to auditFailed.
+func (s *TaskService) auditChanges(action string, changes []Change) {
+	if s.audit == nil || len(changes) == 0 {
+		return
+	}
+	at := model.Now()
+	entries := make([]audit.Entry, len(changes))
+	for i, c := range changes {
+		entries[i] = audit.NewEntry(at, s.source, action, c.Before, c.After)
+	}
+	if err := s.audit.Append(entries...); err != nil && s.auditFailed != nil {
+		s.auditFailed(fmt.Errorf("audit log: %w", err))
+	}
+}
+
+// emit publishes a stored change.
+func (s *TaskService) emit(typ EventType, task *model.Task) {
+	s.bus.Publish(Event{Type: typ, At: model.Now(), Task: task})
+}
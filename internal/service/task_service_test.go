@@ -0,0 +1,424 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/testutil"
+)
+
+// TestTaskService_Transitions verifies each use case stores its change and
+// reports it as an event, and refuses tasks it does not apply to.
+func TestTaskService_Transitions(t *testing.T) {
+	run := map[EventType]func(*TaskService, model.TaskID) (*model.Task, error){
+		TaskCompleted: (*TaskService).CompleteTask,
+		TaskEdited:    (*TaskService).ReopenTask,
+		TaskMovedToToday: func(s *TaskService, id model.TaskID) (*model.Task, error) {
+			task, _, err := s.MoveToToday(id)
+			return task, err
+		},
+		TaskDeferred: func(s *TaskService, id model.TaskID) (*model.Task, error) {
+			task, _, err := s.DeferTask(id, nil)
+			return task, err
+		},
+	}
+	tests := []struct {
+		name       string
+		event      EventType
+		status     model.TaskStatus
+		missing    bool
+		wantStatus model.TaskStatus
+		wantErr    error
+	}{
+		{name: "complete", event: TaskCompleted, status: model.StatusToday, wantStatus: model.StatusDone},
+		{name: "complete done", event: TaskCompleted, status: model.StatusDone, wantErr: model.ErrInvalidStateTransition},
+		{name: "move to today", event: TaskMovedToToday, status: model.StatusPool, wantStatus: model.StatusToday},
+		{name: "move done to today", event: TaskMovedToToday, status: model.StatusDone, wantErr: model.ErrInvalidStateTransition},
+		{name: "defer", event: TaskDeferred, status: model.StatusToday, wantStatus: model.StatusPool},
+		{name: "defer done", event: TaskDeferred, status: model.StatusDone, wantErr: model.ErrInvalidStateTransition},
+		{name: "reopen", event: TaskEdited, status: model.StatusDone, wantStatus: model.StatusPool},
+		{name: "reopen pool", event: TaskEdited, status: model.StatusPool, wantErr: model.ErrInvalidStateTransition},
+		{name: "unknown task", event: TaskCompleted, missing: true, wantErr: model.ErrTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			task := testutil.NewTask().WithStatus(tt.status).Build()
+			if !tt.missing {
+				testutil.MustSeed(t, repo, task)
+			}
+			var events []Event
+			bus := NewBus()
+			bus.Subscribe(func(e Event) { events = append(events, e) })
+			s := New(repo, bus)
+
+			got, err := run[tt.event](s, task.ID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if len(events) != 0 {
+					t.Errorf("failed use case reported %v", events)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored, err := repo.Get(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus || stored.Status != tt.wantStatus {
+				t.Errorf("status = %q, stored %q; want %q", got.Status, stored.Status, tt.wantStatus)
+			}
+			if len(events) != 1 || events[0].Type != tt.event || events[0].Task.Status != tt.wantStatus {
+				t.Errorf("events = %v, want one %q", events, tt.event)
+			}
+		})
+	}
+}
+
+// TestTaskService_AddTask verifies a new task is validated, stored in the
+// pool and reported.
+func TestTaskService_AddTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		wantErr bool
+	}{
+		{name: "valid", title: "Water the plants"},
+		{name: "empty title", title: "  ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			var events []Event
+			bus := NewBus()
+			bus.Subscribe(func(e Event) { events = append(events, e) })
+			s := New(repo, bus)
+
+			task, err := s.AddTask(tt.title, []string{"home"})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("AddTask() succeeded")
+				}
+				if n, _ := repo.Count(model.TaskFilter{}); n != 0 || len(events) != 0 {
+					t.Errorf("failed AddTask stored %d tasks and reported %v", n, events)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored, err := repo.Get(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != model.StatusPool || !slices.Equal(stored.Tags, []string{"home"}) {
+				t.Errorf("stored %+v, want a pool task tagged home", stored)
+			}
+			if len(events) != 1 || events[0].Type != TaskCreated {
+				t.Errorf("events = %v, want one created", events)
+			}
+		})
+	}
+}
+
+// racingRepo saves another writer's edit just before the first save it
+// is asked for, so that save is stale.
+type racingRepo struct {
+	*memstore.Repository
+	race func(*model.Task)
+}
+
+func (r *racingRepo) Save(task *model.Task) error {
+	if r.race != nil {
+		theirs, err := r.Repository.Get(task.ID)
+		if err != nil {
+			return err
+		}
+		r.race(theirs)
+		r.race = nil
+		if err := r.Repository.Save(theirs); err != nil {
+			return err
+		}
+	}
+	return r.Repository.Save(task)
+}
+
+// TestTaskService_EditTask verifies an edit is applied again over another
+// writer's save instead of failing or overwriting it, and an edit that
+// changes nothing is not saved.
+func TestTaskService_EditTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		race        func(*model.Task)
+		edit        func(*model.Task) error
+		wantTitle   string
+		wantNotes   string
+		wantVersion int
+		wantEvents  int
+	}{
+		{
+			name:        "edit",
+			edit:        func(t *model.Task) error { t.Title = "Renew passports"; return nil },
+			wantTitle:   "Renew passports",
+			wantVersion: 2,
+			wantEvents:  1,
+		},
+		{
+			name:        "another writer saved first",
+			race:        func(t *model.Task) { t.Notes = "bring photos" },
+			edit:        func(t *model.Task) error { t.Title = "Renew passports"; return nil },
+			wantTitle:   "Renew passports",
+			wantNotes:   "bring photos",
+			wantVersion: 3,
+			wantEvents:  1,
+		},
+		{
+			name:        "unchanged",
+			edit:        func(t *model.Task) error { t.Title = "Renew passport"; return nil },
+			wantTitle:   "Renew passport",
+			wantVersion: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepo{Repository: memstore.New()}
+			task := testutil.NewTask().WithTitle("Renew passport").Build()
+			testutil.MustSeed(t, repo.Repository, task)
+			repo.race = tt.race
+			var events []Event
+			bus := NewBus()
+			bus.Subscribe(func(e Event) { events = append(events, e) })
+			s := New(repo, bus)
+
+			got, err := s.EditTask(task.ID, tt.edit)
+			if err != nil {
+				t.Fatalf("EditTask() error: %v", err)
+			}
+			stored, err := repo.Get(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Title != tt.wantTitle || stored.Notes != tt.wantNotes || stored.Version != tt.wantVersion {
+				t.Errorf("stored %q, %q at version %d; want %q, %q at %d", stored.Title, stored.Notes, stored.Version, tt.wantTitle, tt.wantNotes, tt.wantVersion)
+			}
+			if got.Version != stored.Version {
+				t.Errorf("returned version %d, stored %d", got.Version, stored.Version)
+			}
+			if len(events) != tt.wantEvents {
+				t.Errorf("reported %d events, want %d", len(events), tt.wantEvents)
+			}
+		})
+	}
+}
+
+// TestTaskService_PinTask verifies pinning sets the wanted state, even
+// when another writer changed it first.
+func TestTaskService_PinTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		race        func(*model.Task)
+		pinned      bool
+		wantVersion int
+	}{
+		{name: "pin", pinned: true, wantVersion: 2},
+		{name: "unpin an unpinned task", pinned: false, wantVersion: 1},
+		{name: "another writer pinned first", race: func(t *model.Task) { t.Pinned = true }, pinned: true, wantVersion: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingRepo{Repository: memstore.New()}
+			task := testutil.NewTask().Build()
+			testutil.MustSeed(t, repo.Repository, task)
+			repo.race = tt.race
+
+			if _, err := New(repo, nil).PinTask(task.ID, tt.pinned); err != nil {
+				t.Fatalf("PinTask() error: %v", err)
+			}
+			stored, err := repo.Get(task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Pinned != tt.pinned || stored.Version != tt.wantVersion {
+				t.Errorf("stored pinned %v at version %d, want %v at %d", stored.Pinned, stored.Version, tt.pinned, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// TestTaskService_SnoozeTask verifies a snoozed task leaves the default
+// listing, and done tasks cannot be snoozed.
+func TestTaskService_SnoozeTask(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer model.SetClock(model.NewFixedClock(now))()
+
+	tests := []struct {
+		name    string
+		status  model.TaskStatus
+		wantErr error
+	}{
+		{name: "open task", status: model.StatusPool},
+		{name: "done task", status: model.StatusDone, wantErr: model.ErrInvalidStateTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			task := testutil.NewTask().WithStatus(tt.status).Build()
+			testutil.MustSeed(t, repo, task)
+			svc := New(repo, nil)
+
+			_, err := svc.SnoozeTask(task.ID, now.Add(48*time.Hour))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SnoozeTask() error = %v, want %v", err, tt.wantErr)
+			}
+			listed, err := svc.ListTasks(model.TaskFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hidden := len(listed) == 0; hidden != (tt.wantErr == nil) {
+				t.Errorf("listed %d tasks after snoozing", len(listed))
+			}
+		})
+	}
+}
+
+// TestTaskService_UndoRedo verifies undoing a change restores the task as
+// it was and redoing makes the change again, and a change overtaken by a
+// later one is refused and dropped.
+func TestTaskService_UndoRedo(t *testing.T) {
+	tests := []struct {
+		name string
+		// do makes the change to undo, returning the task's ID.
+		do          func(*TaskService, model.TaskID) (model.TaskID, error)
+		wantMissing bool
+		wantStatus  model.TaskStatus
+		wantTitle   string
+	}{
+		{
+			name: "complete",
+			do: func(s *TaskService, id model.TaskID) (model.TaskID, error) {
+				_, err := s.CompleteTask(id)
+				return id, err
+			},
+			wantStatus: model.StatusToday,
+			wantTitle:  "Water the plants",
+		},
+		{
+			name: "edit",
+			do: func(s *TaskService, id model.TaskID) (model.TaskID, error) {
+				_, err := s.EditTask(id, func(t *model.Task) error { t.Title = "Water the ferns"; return nil })
+				return id, err
+			},
+			wantStatus: model.StatusToday,
+			wantTitle:  "Water the plants",
+		},
+		{
+			name: "add",
+			do: func(s *TaskService, _ model.TaskID) (model.TaskID, error) {
+				task, err := s.AddTask("Repot the cactus", nil)
+				if err != nil {
+					return model.TaskID{}, err
+				}
+				return task.ID, nil
+			},
+			wantMissing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			task := testutil.NewTask().WithTitle("Water the plants").WithStatus(model.StatusToday).Build()
+			testutil.MustSeed(t, repo, task)
+			s := New(repo, nil)
+			if _, err := s.Undo(); !errors.Is(err, ErrNothingToUndo) {
+				t.Fatalf("Undo() with no history error = %v, want ErrNothingToUndo", err)
+			}
+			id, err := tt.do(s, task.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed, err := repo.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := s.Undo(); err != nil {
+				t.Fatalf("Undo() error: %v", err)
+			}
+			got, err := repo.Get(id)
+			switch {
+			case tt.wantMissing:
+				if !errors.Is(err, model.ErrTaskNotFound) {
+					t.Errorf("Get() after undoing an add = %v, %v; want not found", got, err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case got.Status != tt.wantStatus || got.Title != tt.wantTitle:
+				t.Errorf("after Undo() task is %q, %q; want %q, %q", got.Status, got.Title, tt.wantStatus, tt.wantTitle)
+			}
+
+			if _, err := s.Redo(); err != nil {
+				t.Fatalf("Redo() error: %v", err)
+			}
+			got, err = repo.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := got.Diff(changed); len(diff) != 0 {
+				t.Errorf("after Redo() task differs from the change: %v", diff)
+			}
+			if _, err := s.Redo(); !errors.Is(err, ErrNothingToRedo) {
+				t.Errorf("second Redo() error = %v, want ErrNothingToRedo", err)
+			}
+
+			got.Notes = "changed elsewhere"
+			if err := repo.Save(got); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Undo(); !errors.Is(err, model.ErrStaleTask) {
+				t.Fatalf("Undo() over a later change error = %v, want ErrStaleTask", err)
+			}
+			if _, err := s.Undo(); !errors.Is(err, ErrNothingToUndo) {
+				t.Errorf("refused step was kept: Undo() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestTaskService_DeleteTask verifies a deleted task is published, and
+// undoing the deletion restores it as created.
+func TestTaskService_DeleteTask(t *testing.T) {
+	repo := memstore.New()
+	task := testutil.NewTask().WithTitle("Water the plants").Build()
+	testutil.MustSeed(t, repo, task)
+	var events []EventType
+	bus := NewBus()
+	bus.Subscribe(func(e Event) { events = append(events, e.Type) })
+	s := New(repo, bus)
+
+	if err := s.DeleteTask(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(task.ID); err == nil {
+		t.Error("task still stored after DeleteTask")
+	}
+	if _, err := s.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.Get(task.ID); err != nil || got.Title != "Water the plants" {
+		t.Errorf("Get() after undoing the deletion = %v, %v", got, err)
+	}
+	if want := []EventType{TaskDeleted, TaskCreated}; !slices.Equal(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if err := s.DeleteTask(model.NewTaskID()); err == nil {
+		t.Error("deleting an unknown task succeeded")
+	}
+}
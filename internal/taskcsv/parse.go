@@ -0,0 +1,364 @@
+package taskcsv
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// System is the ExternalRef system of tasks imported from CSV.
+const System = "csv"
+
+// Mapping maps column names, such as "title", to the CSV headers holding
+// them, such as "Summary".
+type Mapping map[string]string
+
+// Importable returns the names of the columns Parse can read, in order.
+func Importable() []string {
+	var names []string
+	for _, c := range columns {
+		if c.set != nil {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// ParseMapping reads a mapping written as "title=Summary,due=Due Date".
+func ParseMapping(spec string) (Mapping, error) {
+	m := Mapping{}
+	for _, pair := range strings.Split(spec, ",") {
+		name, header, ok := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		header = strings.TrimSpace(header)
+		if !ok || header == "" {
+			return nil, fmt.Errorf("mapping %q is not COLUMN=HEADER", strings.TrimSpace(pair))
+		}
+		if c := lookup(name); c == nil || c.set == nil {
+			return nil, fmt.Errorf("cannot import column %q (want %s)", name, strings.Join(Importable(), ", "))
+		}
+		if _, dup := m[name]; dup {
+			return nil, fmt.Errorf("column %q mapped twice", name)
+		}
+		m[name] = header
+	}
+	return m, nil
+}
+
+// Match completes explicit with the importable columns whose names appear
+// in header, compared without regard to case, and checks that every
+// mapped header exists.
+func Match(header []string, explicit Mapping) (Mapping, error) {
+	if err := checkHeaders(header, explicit); err != nil {
+		return nil, err
+	}
+	m := Mapping{}
+	for name, h := range explicit {
+		m[name] = h
+	}
+	for _, name := range Importable() {
+		if _, ok := m[name]; ok {
+			continue
+		}
+		if i := findHeader(header, name); i >= 0 {
+			m[name] = header[i]
+		}
+	}
+	return m, nil
+}
+
+// checkHeaders reports a header in m that header lacks.
+func checkHeaders(header []string, m Mapping) error {
+	for name, h := range m {
+		if findHeader(header, h) < 0 {
+			return fmt.Errorf("no %q column for %s (have %s)", h, name, strings.Join(header, ", "))
+		}
+	}
+	return nil
+}
+
+// findHeader returns the index of name in header, or -1.
+func findHeader(header []string, name string) int {
+	return slices.IndexFunc(header, func(h string) bool {
+		return strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name))
+	})
+}
+
+// Options controls how Parse reads a file.
+type Options struct {
+	// Mapping names the headers of columns that do not share the column's
+	// name; the rest are matched by name.
+	Mapping Mapping
+	// Loc is the time zone of dates and times that carry none.
+	Loc *time.Location
+	// Profile, if set, adapts Parse to another tool's export. Mapping
+	// overrides the profile's own mapping.
+	Profile *Profile
+	// Choose, if set, is shown the header and the mapping Match made and
+	// returns the mapping to use, such as one confirmed by the user.
+	Choose func(header []string, m Mapping) (Mapping, error)
+}
+
+// reading is the state cell parsers share.
+type reading struct {
+	loc *time.Location
+	// layouts are the date formats accepted besides RFC 3339.
+	layouts []string
+}
+
+// Parse reads a CSV file with a header row as drafts. Rows that cannot be
+// read, such as those with a malformed date or no title, are returned as
+// skipped rather than stopping the import; blank rows are ignored.
+//
+// A draft's ExternalRef ID is a hash of its title and creation day, or the
+// record's key under a profile that has one, so importing a file again,
+// or one listing the same task twice, adds each task once. A field mapped
+// to a header that repeats, as JIRA repeats Labels, reads every such
+// column: tags are combined, other fields take the first value.
+func Parse(r io.Reader, opts Options) (drafts []importer.Draft, skipped []*importer.LineError, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("no header row")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// Spreadsheets often start their CSV files with a byte order mark.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	explicit := opts.Mapping
+	if p := opts.Profile; p != nil {
+		explicit = Mapping{}
+		for name, h := range p.Mapping {
+			// Exports vary with the tool's configuration, so a profile's
+			// columns are optional.
+			if findHeader(header, h) >= 0 {
+				explicit[name] = h
+			}
+		}
+		for name, h := range opts.Mapping {
+			explicit[name] = h
+		}
+	}
+	m, err := Match(header, explicit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Choose != nil {
+		if m, err = opts.Choose(header, m); err != nil {
+			return nil, nil, err
+		}
+		if err := checkHeaders(header, m); err != nil {
+			return nil, nil, err
+		}
+	}
+	if _, ok := m["title"]; !ok {
+		return nil, nil, errors.New("no title column; map one with title=HEADER")
+	}
+
+	type field struct {
+		col     *column
+		indexes []int
+		rewrite func(string) string
+	}
+	var fields []field
+	for _, name := range Importable() {
+		h, ok := m[name]
+		if !ok {
+			continue
+		}
+		f := field{col: lookup(name)}
+		for i := range header {
+			if strings.EqualFold(strings.TrimSpace(header[i]), strings.TrimSpace(h)) {
+				f.indexes = append(f.indexes, i)
+			}
+		}
+		if opts.Profile != nil {
+			f.rewrite = opts.Profile.Values[name]
+		}
+		fields = append(fields, f)
+	}
+	key := -1
+	if opts.Profile != nil && opts.Profile.Key != "" {
+		key = findHeader(header, opts.Profile.Key)
+	}
+
+	rd := &reading{loc: opts.Loc, layouts: timeLayouts}
+	if rd.loc == nil {
+		rd.loc = time.Local
+	}
+	if opts.Profile != nil {
+		rd.layouts = append(slices.Clone(opts.Profile.TimeLayouts), rd.layouts...)
+	}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			skipped = append(skipped, &importer.LineError{Line: perr.StartLine, Err: perr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if blank(record) {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+
+		var d importer.Draft
+		for _, f := range fields {
+			value := cell(record, f.indexes, f.col.name == "tags")
+			if f.rewrite != nil {
+				value = f.rewrite(value)
+			}
+			if value == "" {
+				continue
+			}
+			if err = f.col.set(&d, value, rd); err != nil {
+				err = fmt.Errorf("%s: %w", f.col.name, err)
+				break
+			}
+		}
+		if err == nil {
+			d.Ref = ref(d, rd.loc)
+			if k := cell(record, []int{key}, false); k != "" {
+				d.Ref = model.ExternalRef{System: opts.Profile.Name, ID: k}
+			}
+			_, err = d.Task()
+		}
+		if err != nil {
+			skipped = append(skipped, &importer.LineError{Line: line, Err: err})
+			continue
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, skipped, nil
+}
+
+// cell returns the value of the first non-empty column of record among
+// indexes, or with all set every non-empty value joined by commas.
+func cell(record []string, indexes []int, all bool) string {
+	var values []string
+	for _, i := range indexes {
+		if i < 0 || i >= len(record) {
+			continue
+		}
+		if v := strings.TrimSpace(record[i]); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	if !all {
+		return values[0]
+	}
+	return strings.Join(values, ",")
+}
+
+// blank reports whether every field of record is empty.
+func blank(record []string) bool {
+	return !slices.ContainsFunc(record, func(v string) bool { return strings.TrimSpace(v) != "" })
+}
+
+// ref identifies a row by its title and creation day.
+func ref(d importer.Draft, loc *time.Location) model.ExternalRef {
+	var day string
+	if !d.Created.IsZero() {
+		day = d.Created.In(loc).Format(time.DateOnly)
+	}
+	sum := sha256.Sum256([]byte(d.Title + "\x00" + day))
+	return model.ExternalRef{System: System, ID: hex.EncodeToString(sum[:8])}
+}
+
+func setTitle(d *importer.Draft, value string, _ *reading) error {
+	d.Title = value
+	return nil
+}
+
+func setNotes(d *importer.Draft, value string, _ *reading) error {
+	d.Notes = value
+	return nil
+}
+
+func setStatus(d *importer.Draft, value string, _ *reading) error {
+	s := model.TaskStatus(strings.ToLower(value))
+	if !s.Valid() {
+		return fmt.Errorf("%q is not pool, today or done", value)
+	}
+	d.Status = s
+	return nil
+}
+
+func setPriority(d *importer.Draft, value string, _ *reading) (err error) {
+	d.Priority, err = model.ParsePriority(value)
+	return err
+}
+
+func setEnergy(d *importer.Draft, value string, _ *reading) (err error) {
+	d.Energy, err = model.ParseEnergy(value)
+	return err
+}
+
+// setTags accepts tags separated by commas or spaces, as spreadsheets and
+// issue trackers write them.
+func setTags(d *importer.Draft, value string, _ *reading) error {
+	d.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	return nil
+}
+
+func setDue(d *importer.Draft, value string, rd *reading) error {
+	t, err := rd.time(value)
+	d.Due = &t
+	return err
+}
+
+func setCreated(d *importer.Draft, value string, rd *reading) (err error) {
+	d.Created, err = rd.time(value)
+	return err
+}
+
+// setCompleted also marks the task done, since only done tasks have a
+// completion time.
+func setCompleted(d *importer.Draft, value string, rd *reading) error {
+	t, err := rd.time(value)
+	d.Completed = &t
+	d.Status = model.StatusDone
+	return err
+}
+
+func setEstimate(d *importer.Draft, value string, _ *reading) (err error) {
+	d.Estimate, err = time.ParseDuration(value)
+	return err
+}
+
+// timeLayouts are the forms reading.time accepts after RFC 3339.
+var timeLayouts = []string{time.DateTime, "2006-01-02 15:04", time.DateOnly}
+
+// time reads an RFC 3339 timestamp, as Write produces, or a date with an
+// optional time of day in rd.loc.
+func (rd *reading) time(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range rd.layouts {
+		if t, err := time.ParseInLocation(layout, value, rd.loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date such as 2024-06-30 or 2024-06-30T17:00:00Z", value)
+}
@@ -0,0 +1,134 @@
+package taskcsv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestParseMapping verifies FIELD=HEADER lists are read and checked.
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Mapping
+		wantErr string
+	}{
+		{spec: "title=Summary, Due = Due Date", want: Mapping{"title": "Summary", "due": "Due Date"}},
+		{spec: "title", wantErr: "not COLUMN=HEADER"},
+		{spec: "id=Key", wantErr: `cannot import column "id"`},
+		{spec: "title=A,title=B", wantErr: "mapped twice"},
+	}
+	for _, tt := range tests {
+		got, err := ParseMapping(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseMapping(%q) error = %v, want containing %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(got) != len(tt.want) || got["title"] != tt.want["title"] || got["due"] != tt.want["due"] {
+			t.Errorf("ParseMapping(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+// TestParse verifies rows are mapped onto drafts, bad rows are skipped
+// and the same title and creation day give the same reference.
+func TestParse(t *testing.T) {
+	input := "\ufeffSummary,Labels,Due Date,Created,Priority\r\n" +
+		"Renew passport,admin travel,2024-06-30,2024-06-01 09:00,high\r\n" +
+		",,,,\r\n" +
+		"Call the dentist,,next week,,\r\n" +
+		",orphan,,,\r\n" +
+		"Renew passport,,,2024-06-01T18:00:00+02:00,\r\n"
+
+	drafts, skipped, err := Parse(strings.NewReader(input), Options{
+		Mapping: Mapping{"title": "summary", "tags": "Labels", "due": "Due Date"},
+		Loc:     time.UTC,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drafts) != 2 {
+		t.Fatalf("Parse() = %d drafts, want 2: %+v", len(drafts), drafts)
+	}
+	d := drafts[0]
+	if d.Title != "Renew passport" || strings.Join(d.Tags, ",") != "admin,travel" || d.Priority != model.PriorityHigh {
+		t.Errorf("first draft = %+v", d)
+	}
+	if d.Due == nil || !d.Due.Equal(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("due = %v", d.Due)
+	}
+	if drafts[1].Ref != d.Ref {
+		t.Errorf("refs differ for the same title and day: %v, %v", drafts[1].Ref, d.Ref)
+	}
+
+	var got []string
+	for _, e := range skipped {
+		got = append(got, e.Error())
+	}
+	want := []string{`line 4: due: "next week" is not a date`, "line 5: "}
+	if len(got) != len(want) {
+		t.Fatalf("skipped = %q, want %d rows", got, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("skipped[%d] = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestParse_Errors verifies files that cannot be mapped are rejected.
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mapping Mapping
+		wantErr string
+	}{
+		{name: "empty", input: "", wantErr: "no header row"},
+		{name: "no title", input: "Summary,Due\r\n", wantErr: "no title column"},
+		{name: "missing header", input: "title\r\n", mapping: Mapping{"due": "Deadline"}, wantErr: `no "Deadline" column`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(strings.NewReader(tt.input), Options{Mapping: tt.mapping})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParse_RoundTrip verifies a file written by Write with every
+// importable column reads back to the same tasks.
+func TestParse_RoundTrip(t *testing.T) {
+	due := time.Date(2024, 6, 30, 17, 0, 0, 0, time.UTC)
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle("Renew passport").WithNotes("bring photos").WithTags("admin").
+			WithDue(due).WithPriority(model.PriorityHigh).WithEstimate(time.Hour),
+	)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, Importable(), model.DefaultIDDisplayMode); err != nil {
+		t.Fatal(err)
+	}
+	drafts, skipped, err := Parse(&buf, Options{Loc: time.UTC})
+	if err != nil || len(skipped) != 0 || len(drafts) != 1 {
+		t.Fatalf("Parse() = %v, %v, %v", drafts, skipped, err)
+	}
+	got, err := drafts[0].Task()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := tasks[0]
+	if got.Title != want.Title || got.Notes != want.Notes || got.Estimate != want.Estimate ||
+		!got.DueDate.Equal(*want.DueDate) || !got.CreatedAt.Equal(want.CreatedAt.Truncate(time.Second)) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package taskcsv
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"togo/internal/model"
+)
+
+// Profile adapts Parse to the CSV export of another tool.
+type Profile struct {
+	// Name selects the profile and is the ExternalRef system of its tasks.
+	Name string
+	// Mapping names the tool's headers for task fields. Headers missing
+	// from a file are ignored.
+	Mapping Mapping
+	// Key names the header of the tool's record ID, used as the
+	// ExternalRef ID when present.
+	Key string
+	// TimeLayouts are the tool's date formats, tried before the defaults.
+	TimeLayouts []string
+	// Values rewrite a field's cells before they are parsed, such as to
+	// translate the tool's status names.
+	Values map[string]func(string) string
+}
+
+// profiles lists the built-in profiles by name.
+var profiles = map[string]*Profile{
+	"jira": {
+		Name: "jira",
+		Mapping: Mapping{
+			"title":     "Summary",
+			"notes":     "Description",
+			"status":    "Status",
+			"priority":  "Priority",
+			"tags":      "Labels",
+			"due":       "Due Date",
+			"created":   "Created",
+			"completed": "Resolved",
+		},
+		Key:         "Issue key",
+		TimeLayouts: []string{"02/Jan/06 3:04 PM", "02/Jan/06"},
+		Values: map[string]func(string) string{
+			"status": statusNamed(map[string]model.TaskStatus{
+				"in progress": model.StatusToday,
+				"done":        model.StatusDone,
+				"closed":      model.StatusDone,
+				"resolved":    model.StatusDone,
+			}),
+			"priority": priorityNamed(map[string]model.Priority{
+				"highest": model.PriorityHigh,
+				"high":    model.PriorityHigh,
+				"medium":  model.PriorityMedium,
+				"low":     model.PriorityLow,
+				"lowest":  model.PriorityLow,
+			}),
+		},
+	},
+	"trello": {
+		Name: "trello",
+		Mapping: Mapping{
+			"title":  "Card Name",
+			"notes":  "Card Description",
+			"status": "List Name",
+			"tags":   "Labels",
+			"due":    "Due Date",
+		},
+		Key: "Card ID",
+		Values: map[string]func(string) string{
+			"status": statusNamed(map[string]model.TaskStatus{
+				"today":       model.StatusToday,
+				"doing":       model.StatusToday,
+				"in progress": model.StatusToday,
+				"done":        model.StatusDone,
+				"complete":    model.StatusDone,
+				"completed":   model.StatusDone,
+			}),
+			"tags": trelloLabels,
+		},
+	},
+}
+
+// LookupProfile returns the built-in profile called name.
+func LookupProfile(name string) (*Profile, bool) {
+	p, ok := profiles[strings.ToLower(name)]
+	return p, ok
+}
+
+// ProfileNames returns the names of the built-in profiles, sorted.
+func ProfileNames() []string {
+	return slices.Sorted(maps.Keys(profiles))
+}
+
+// statusNamed returns a rewrite translating the tool's status or list
+// names, in any case, to togo statuses. Other names mean pool, since
+// tools let users invent their own.
+func statusNamed(names map[string]model.TaskStatus) func(string) string {
+	return func(v string) string {
+		if s, ok := names[strings.ToLower(v)]; ok {
+			return string(s)
+		}
+		return string(model.StatusPool)
+	}
+}
+
+// priorityNamed returns a rewrite translating the tool's priority names.
+// Unknown names mean no priority.
+func priorityNamed(names map[string]model.Priority) func(string) string {
+	return func(v string) string {
+		return string(names[strings.ToLower(v)])
+	}
+}
+
+// trelloLabels rewrites Trello's "Urgent (red), Needs review (green)" as
+// tags, dropping label colours and hyphenating names.
+func trelloLabels(v string) string {
+	var tags []string
+	for _, label := range strings.Split(v, ",") {
+		label = strings.TrimSpace(label)
+		if i := strings.LastIndex(label, " ("); i > 0 && strings.HasSuffix(label, ")") {
+			label = label[:i]
+		}
+		if tag := strings.Join(strings.Fields(label), "-"); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return strings.Join(tags, ",")
+}
@@ -0,0 +1,75 @@
+package taskcsv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// TestParse_Profiles verifies the built-in profiles read their tools'
+// exports.
+func TestParse_Profiles(t *testing.T) {
+	type draft struct {
+		title, tags string
+		status      model.TaskStatus
+		priority    model.Priority
+		ref         string
+		due         time.Time
+	}
+	tests := []struct {
+		profile string
+		input   string
+		want    []draft
+	}{
+		{
+			profile: "jira",
+			input: "Summary,Issue key,Issue id,Status,Priority,Labels,Labels,Due Date,Created,Resolved\n" +
+				"Fix login,WEB-12,10012,In Progress,Highest,auth,frontend,30/Jun/24 5:00 PM,01/Jun/24 9:15 AM,\n" +
+				"Write docs,WEB-13,10013,Done,Low,,,,01/Jun/24 9:20 AM,03/Jun/24 11:00 AM\n",
+			want: []draft{
+				{"Fix login", "auth,frontend", model.StatusToday, model.PriorityHigh, "WEB-12", time.Date(2024, 6, 30, 17, 0, 0, 0, time.UTC)},
+				{"Write docs", "", model.StatusDone, model.PriorityLow, "WEB-13", time.Time{}},
+			},
+		},
+		{
+			profile: "trello",
+			input: "Card ID,Card Name,Card Description,Labels,Due Date,List Name,Archived\n" +
+				"5f1a,Book flights,window seat,\"Urgent (red), Needs review (green)\",2024-06-30T17:00:00.000Z,Doing,false\n" +
+				"5f1b,Pack,,,,Backlog,false\n",
+			want: []draft{
+				{"Book flights", "Urgent,Needs-review", model.StatusToday, model.PriorityNone, "5f1a", time.Date(2024, 6, 30, 17, 0, 0, 0, time.UTC)},
+				{"Pack", "", model.StatusPool, model.PriorityNone, "5f1b", time.Time{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			p, ok := LookupProfile(tt.profile)
+			if !ok {
+				t.Fatalf("no profile %q", tt.profile)
+			}
+			drafts, skipped, err := Parse(strings.NewReader(tt.input), Options{Profile: p, Loc: time.UTC})
+			if err != nil || len(skipped) != 0 {
+				t.Fatalf("Parse() error = %v, skipped = %v", err, skipped)
+			}
+			if len(drafts) != len(tt.want) {
+				t.Fatalf("Parse() = %d drafts, want %d", len(drafts), len(tt.want))
+			}
+			for i, w := range tt.want {
+				d := drafts[i]
+				if d.Title != w.title || strings.Join(d.Tags, ",") != w.tags || d.Status != w.status || d.Priority != w.priority {
+					t.Errorf("draft %d = %+v, want %+v", i, d, w)
+				}
+				if d.Ref != (model.ExternalRef{System: tt.profile, ID: w.ref}) {
+					t.Errorf("draft %d ref = %v, want %s", i, d.Ref, w.ref)
+				}
+				if (d.Due == nil) != w.due.IsZero() || (d.Due != nil && !d.Due.Equal(w.due)) {
+					t.Errorf("draft %d due = %v, want %v", i, d.Due, w.due)
+				}
+			}
+		})
+	}
+}
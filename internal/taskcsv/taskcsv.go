@@ -0,0 +1,130 @@
+// Package taskcsv writes tasks as RFC 4180 CSV for spreadsheets and
+// reporting, with a caller-chosen set of columns, and reads them back from
+// CSV files whose columns are mapped onto task fields.
+package taskcsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// DefaultColumns are written when no columns are chosen.
+var DefaultColumns = []string{"id", "title", "status", "due", "tags"}
+
+// column is one task attribute. Columns with a set function can be
+// imported.
+type column struct {
+	name string
+	get  func(t *model.Task) string
+	set  func(d *importer.Draft, value string, rd *reading) error
+}
+
+// columns lists every column, in the order Names reports them.
+var columns = []column{
+	{"id", func(t *model.Task) string { return t.ID.String() }, nil},
+	{"ref", model.DefaultIDDisplayMode.Format, nil},
+	{"title", func(t *model.Task) string { return t.Title }, setTitle},
+	{"notes", func(t *model.Task) string { return t.Notes }, setNotes},
+	{"status", func(t *model.Task) string { return t.Status.String() }, setStatus},
+	{"priority", func(t *model.Task) string { return t.Priority.String() }, setPriority},
+	{"energy", func(t *model.Task) string { return t.Energy.String() }, setEnergy},
+	{"tags", func(t *model.Task) string { return strings.Join(t.Tags, ",") }, setTags},
+	{"due", func(t *model.Task) string { return formatTime(t.DueDate) }, setDue},
+	{"created", func(t *model.Task) string { return formatTime(&t.CreatedAt) }, setCreated},
+	{"updated", func(t *model.Task) string { return formatTime(&t.UpdatedAt) }, nil},
+	{"completed", func(t *model.Task) string { return formatTime(t.CompletedAt) }, setCompleted},
+	{"snoozed_until", func(t *model.Task) string { return formatTime(t.SnoozedUntil) }, nil},
+	{"deferred", func(t *model.Task) string { return strconv.Itoa(t.DeferredCount) }, nil},
+	{"estimate", func(t *model.Task) string { return formatDuration(t.Estimate) }, setEstimate},
+	{"pinned", func(t *model.Task) string { return strconv.FormatBool(t.Pinned) }, nil},
+}
+
+// Names returns every column name.
+func Names() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// ParseColumns reads a comma-separated column list such as
+// "id,title,due", rejecting unknown and repeated names.
+func ParseColumns(spec string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if lookup(name) == nil {
+			return nil, fmt.Errorf("unknown column %q (want %s)", name, strings.Join(Names(), ", "))
+		}
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("column %q listed twice", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Write writes a header row naming names, then one row per task, with
+// CRLF line endings as RFC 4180 specifies. Names must come from
+// ParseColumns or DefaultColumns. The ref column renders each task's ID
+// as ids says, where the id column is always the full UUID.
+func Write(w io.Writer, tasks []*model.Task, names []string, ids model.IDDisplayMode) error {
+	cols := make([]*column, len(names))
+	for i, name := range names {
+		if cols[i] = lookup(name); cols[i] == nil {
+			return fmt.Errorf("unknown column %q", name)
+		}
+		if name == "ref" {
+			cols[i] = &column{name: name, get: ids.Format}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	cw.Write(names)
+	row := make([]string, len(cols))
+	for _, t := range tasks {
+		for i, c := range cols {
+			row[i] = c.get(t)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// lookup returns the column called name, or nil.
+func lookup(name string) *column {
+	for i := range columns {
+		if columns[i].name == name {
+			return &columns[i]
+		}
+	}
+	return nil
+}
+
+// formatTime renders an optional instant as RFC 3339, which spreadsheets
+// parse; nil and zero times are empty.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatDuration renders an estimate, empty when unset.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,63 @@
+package taskcsv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestParseColumns verifies column lists are normalized and checked.
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr string
+	}{
+		{spec: "id,title,status,due,tags", want: "id,title,status,due,tags"},
+		{spec: " Title , DUE", want: "title,due"},
+		{spec: "title,colour", wantErr: `unknown column "colour"`},
+		{spec: "title,,due", wantErr: `unknown column ""`},
+		{spec: "title,title", wantErr: "listed twice"},
+	}
+	for _, tt := range tests {
+		got, err := ParseColumns(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseColumns(%q) error = %v, want containing %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(got, ",") != tt.want {
+			t.Errorf("ParseColumns(%q) = %v, %v; want %s", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+// TestWrite verifies the header, quoting and formatting of values.
+func TestWrite(t *testing.T) {
+	due := time.Date(2024, 6, 30, 17, 0, 0, 0, time.UTC)
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle(`Say "hi", then leave`).WithTags("work", "errands").WithDue(due).
+			WithEstimate(90*time.Minute).WithPriority(model.PriorityHigh),
+		testutil.NewTask().WithTitle("Plain").WithNotes("line one\nline two").WithSeq(7),
+	)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks, []string{"title", "tags", "due", "priority", "estimate", "notes", "ref"}, model.IDDisplaySequence); err != nil {
+		t.Fatal(err)
+	}
+	want := "title,tags,due,priority,estimate,notes,ref\r\n" +
+		`"Say ""hi"", then leave","work,errands",2024-06-30T17:00:00Z,high,1h30m0s,,` + tasks[0].ID.Short() + "\r\n" +
+		"Plain,,,,,\"line one\r\nline two\",#7\r\n"
+	if buf.String() != want {
+		t.Errorf("Write() =\n%q\nwant\n%q", buf.String(), want)
+	}
+
+	if err := Write(&buf, tasks, []string{"colour"}, model.IDDisplaySequence); err == nil {
+		t.Error("Write() accepted an unknown column")
+	}
+}
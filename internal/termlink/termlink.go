@@ -0,0 +1,111 @@
+// Package termlink renders clickable OSC 8 hyperlinks for terminals that
+// support them and falls back to plain text everywhere else.
+package termlink
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"togo/internal/model"
+)
+
+// EnvOverride forces hyperlinks on ("1") or off ("0") regardless of detection.
+const EnvOverride = "TOGO_HYPERLINKS"
+
+// hyperlinkTerms lists TERM_PROGRAM values known to support OSC 8.
+var hyperlinkTerms = map[string]bool{
+	"iTerm.app": true,
+	"WezTerm":   true,
+	"vscode":    true,
+	"Hyper":     true,
+	"ghostty":   true,
+	"rio":       true,
+}
+
+// Supported reports whether the terminal described by getenv (normally
+// os.Getenv) renders OSC 8 hyperlinks. Unknown terminals are assumed not to,
+// since some print the raw escape sequence.
+func Supported(getenv func(string) string) bool {
+	switch getenv(EnvOverride) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+
+	term := getenv("TERM")
+	if term == "" || term == "dumb" || strings.HasPrefix(term, "screen") {
+		return false
+	}
+	if hyperlinkTerms[getenv("TERM_PROGRAM")] {
+		return true
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	// GNOME Terminal and other VTE-based terminals gained support in 0.50.
+	if v, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	for _, name := range []string{"kitty", "alacritty", "foot", "ghostty"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Link wraps text in an OSC 8 hyperlink to url.
+func Link(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// Linker renders links either as OSC 8 hyperlinks or, when disabled, as
+// plain text that still shows the target.
+type Linker struct {
+	// Enabled selects OSC 8 output; set it from Supported.
+	Enabled bool
+
+	// RefURLs maps a lowercase external system name to a URL template in
+	// which "%s" is replaced by the external ID, e.g. "github" →
+	// "https://github.com/%s". Refs for unlisted systems are not linked.
+	RefURLs map[string]string
+}
+
+// Format renders text linked to url. Without hyperlink support the URL is
+// appended in angle brackets unless text already is the URL.
+func (l Linker) Format(url, text string) string {
+	if l.Enabled {
+		return Link(url, text)
+	}
+	if text == url {
+		return text
+	}
+	return text + " <" + url + ">"
+}
+
+// Ref renders an external reference, linked when its system has a URL
+// template.
+func (l Linker) Ref(ref model.ExternalRef) string {
+	tmpl, ok := l.RefURLs[strings.ToLower(ref.System)]
+	if !ok {
+		return ref.String()
+	}
+	return l.Format(strings.ReplaceAll(tmpl, "%s", ref.ID), ref.String())
+}
+
+// urlPattern matches http(s) URLs embedded in free text, stopping before
+// trailing punctuation that usually ends a sentence.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]*[^\s<>".,;:!?)\]]`)
+
+// Linkify turns every URL in s into a hyperlink. When links are disabled s
+// is returned unchanged, since the URLs are already visible.
+func (l Linker) Linkify(s string) string {
+	if !l.Enabled {
+		return s
+	}
+	return urlPattern.ReplaceAllStringFunc(s, func(url string) string {
+		return Link(url, url)
+	})
+}
@@ -0,0 +1,96 @@
+package termlink
+
+import (
+	"testing"
+
+	"togo/internal/model"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+// TestSupported verifies terminal detection and the environment override.
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want bool
+	}{
+		{"no terminal", map[string]string{}, false},
+		{"dumb", map[string]string{"TERM": "dumb", "TERM_PROGRAM": "iTerm.app"}, false},
+		{"iterm", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, true},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"windows terminal", map[string]string{"TERM": "xterm-256color", "WT_SESSION": "abc"}, true},
+		{"new vte", map[string]string{"TERM": "xterm-256color", "VTE_VERSION": "6800"}, true},
+		{"old vte", map[string]string{"TERM": "xterm-256color", "VTE_VERSION": "4800"}, false},
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, false},
+		{"tmux", map[string]string{"TERM": "screen-256color", "TERM_PROGRAM": "tmux"}, false},
+		{"forced on", map[string]string{EnvOverride: "1"}, true},
+		{"forced off", map[string]string{"TERM": "xterm-kitty", EnvOverride: "0"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Supported(env(tt.vars)); got != tt.want {
+				t.Errorf("Supported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinker_Format verifies OSC 8 output and the plain-text fallback.
+func TestLinker_Format(t *testing.T) {
+	url := "https://example.com/a"
+
+	tests := []struct {
+		name    string
+		enabled bool
+		text    string
+		want    string
+	}{
+		{"enabled", true, "docs", "\x1b]8;;https://example.com/a\x1b\\docs\x1b]8;;\x1b\\"},
+		{"fallback", false, "docs", "docs <https://example.com/a>"},
+		{"fallback bare url", false, url, url},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Linker{Enabled: tt.enabled}).Format(url, tt.text); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinker_Ref verifies external refs link through their system template.
+func TestLinker_Ref(t *testing.T) {
+	l := Linker{Enabled: true, RefURLs: map[string]string{"jira": "https://jira.example.com/browse/%s"}}
+
+	got := l.Ref(model.ExternalRef{System: "JIRA", ID: "OPS-7"})
+	want := Link("https://jira.example.com/browse/OPS-7", "JIRA:OPS-7")
+	if got != want {
+		t.Errorf("Ref() = %q, want %q", got, want)
+	}
+
+	if got := l.Ref(model.ExternalRef{System: "trello", ID: "x1"}); got != "trello:x1" {
+		t.Errorf("Ref() for unknown system = %q, want plain ref", got)
+	}
+}
+
+// TestLinker_Linkify verifies URLs in text are linked without trailing
+// punctuation.
+func TestLinker_Linkify(t *testing.T) {
+	text := "see https://example.com/x. and (http://a.b/c)"
+
+	got := Linker{Enabled: true}.Linkify(text)
+	want := "see " + Link("https://example.com/x", "https://example.com/x") +
+		". and (" + Link("http://a.b/c", "http://a.b/c") + ")"
+	if got != want {
+		t.Errorf("Linkify() = %q, want %q", got, want)
+	}
+
+	if got := (Linker{}).Linkify(text); got != text {
+		t.Errorf("disabled Linkify() = %q, want unchanged", got)
+	}
+}
@@ -0,0 +1,31 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"togo/internal/model"
+)
+
+// TaskSaver is the part of a task store that seeding needs.
+type TaskSaver interface {
+	Save(task *model.Task) error
+}
+
+// Seed saves tasks into store, stopping at the first failure.
+func Seed(store TaskSaver, tasks ...*model.Task) error {
+	for _, task := range tasks {
+		if err := store.Save(task); err != nil {
+			return fmt.Errorf("seed task %q: %w", task.Title, err)
+		}
+	}
+	return nil
+}
+
+// MustSeed is Seed for tests: it fails t immediately on error.
+func MustSeed(t testing.TB, store TaskSaver, tasks ...*model.Task) {
+	t.Helper()
+	if err := Seed(store, tasks...); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package testutil provides fixtures shared by unit tests, integration tests
+// and the demo-data generator. It is not used by production code paths.
+package testutil
+
+import (
+	"slices"
+	"time"
+
+	"togo/internal/model"
+)
+
+// DefaultTitle is the title of tasks built without WithTitle.
+const DefaultTitle = "Test task"
+
+// TaskBuilder assembles a *model.Task fluently:
+//
+//	task := testutil.NewTask().
+//		WithStatus(model.StatusToday).
+//		WithTags("work").
+//		WithDue(friday).
+//		Build()
+//
+// Each With method returns the builder so calls chain. Build returns an
+// independent copy, so one builder can produce several similar tasks.
+type TaskBuilder struct {
+	task model.Task
+}
+
+// NewTask starts a builder for a pool task titled DefaultTitle with a fresh
+// ID, created at the model clock's current time.
+func NewTask() *TaskBuilder {
+	now := model.Now()
+	return &TaskBuilder{task: model.Task{
+		ID:        model.NewTaskID(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Title:     DefaultTitle,
+		Status:    model.StatusPool,
+	}}
+}
+
+// WithID sets the task ID.
+func (b *TaskBuilder) WithID(id model.TaskID) *TaskBuilder {
+	b.task.ID = id
+	return b
+}
+
+// WithSeq sets the sequence number.
+func (b *TaskBuilder) WithSeq(seq int) *TaskBuilder {
+	b.task.Seq = seq
+	return b
+}
+
+// WithTitle sets the title verbatim, bypassing NewTask's normalization.
+func (b *TaskBuilder) WithTitle(title string) *TaskBuilder {
+	b.task.Title = title
+	return b
+}
+
+// WithNotes sets the notes.
+func (b *TaskBuilder) WithNotes(notes string) *TaskBuilder {
+	b.task.Notes = notes
+	return b
+}
+
+// WithStatus sets the status. Setting StatusDone also sets CompletedAt to
+// the creation time unless it is already set, keeping the task valid.
+func (b *TaskBuilder) WithStatus(status model.TaskStatus) *TaskBuilder {
+	b.task.Status = status
+	if status == model.StatusDone && b.task.CompletedAt == nil {
+		completed := b.task.CreatedAt
+		b.task.CompletedAt = &completed
+	}
+	if status != model.StatusDone {
+		b.task.CompletedAt = nil
+	}
+	return b
+}
+
+// WithTags replaces the tags.
+func (b *TaskBuilder) WithTags(tags ...string) *TaskBuilder {
+	b.task.Tags = slices.Clone(tags)
+	return b
+}
+
+// WithDue sets the due date.
+func (b *TaskBuilder) WithDue(due time.Time) *TaskBuilder {
+	b.task.DueDate = &due
+	return b
+}
+
+// WithCreatedAt sets the creation time, and the update time if it was not
+// moved past creation yet.
+func (b *TaskBuilder) WithCreatedAt(at time.Time) *TaskBuilder {
+	if !b.task.UpdatedAt.After(b.task.CreatedAt) {
+		b.task.UpdatedAt = at
+	}
+	b.task.CreatedAt = at
+	return b
+}
+
+// WithPriority sets the priority.
+func (b *TaskBuilder) WithPriority(p model.Priority) *TaskBuilder {
+	b.task.Priority = p
+	return b
+}
+
+// WithEnergy sets the energy level.
+func (b *TaskBuilder) WithEnergy(e model.Energy) *TaskBuilder {
+	b.task.Energy = e
+	return b
+}
+
+// WithEstimate sets the estimate.
+func (b *TaskBuilder) WithEstimate(d time.Duration) *TaskBuilder {
+	b.task.Estimate = d
+	return b
+}
+
+// WithReminder sets the reminder time.
+func (b *TaskBuilder) WithReminder(at time.Time) *TaskBuilder {
+	b.task.RemindAt = &at
+	return b
+}
+
+// WithRecurrence makes the task a recurring template.
+func (b *TaskBuilder) WithRecurrence(r model.Recurrence) *TaskBuilder {
+	b.task.Recurrence = &r
+	return b
+}
+
+// WithExternalRef records where the task was imported from.
+func (b *TaskBuilder) WithExternalRef(ref model.ExternalRef) *TaskBuilder {
+	b.task.ExternalRef = &ref
+	return b
+}
+
+// Pinned pins the task.
+func (b *TaskBuilder) Pinned() *TaskBuilder {
+	b.task.Pinned = true
+	return b
+}
+
+// Event is one step in a task's life, replayed by WithHistory.
+type Event struct {
+	At time.Time
+	// Status is the status the task moved to.
+	Status model.TaskStatus
+	// Deferred marks the step as a deferral, which also counts towards
+	// DeferredCount; Status is ignored and the task returns to the pool.
+	Deferred bool
+}
+
+// Picked is an Event moving a task onto today's list at at.
+func Picked(at time.Time) Event { return Event{At: at, Status: model.StatusToday} }
+
+// Deferred is an Event deferring a task at at.
+func Deferred(at time.Time) Event { return Event{At: at, Deferred: true} }
+
+// Completed is an Event completing a task at at.
+func Completed(at time.Time) Event { return Event{At: at, Status: model.StatusDone} }
+
+// WithHistory replays events in order, updating status, DeferredCount,
+// CompletedAt and UpdatedAt as the real transitions would.
+func (b *TaskBuilder) WithHistory(events ...Event) *TaskBuilder {
+	for _, e := range events {
+		at := e.At
+		switch {
+		case e.Deferred:
+			b.task.Status = model.StatusPool
+			b.task.DeferredCount++
+			b.task.CompletedAt = nil
+		case e.Status == model.StatusDone:
+			b.task.Status = model.StatusDone
+			b.task.CompletedAt = &at
+		default:
+			b.task.Status = e.Status
+			b.task.CompletedAt = nil
+		}
+		b.task.UpdatedAt = at
+	}
+	return b
+}
+
+// Build returns the task. The builder can keep being used afterwards.
+func (b *TaskBuilder) Build() *model.Task {
+	return b.task.Clone()
+}
+
+// Tasks builds every builder, in order.
+func Tasks(builders ...*TaskBuilder) []*model.Task {
+	tasks := make([]*model.Task, len(builders))
+	for i, b := range builders {
+		tasks[i] = b.Build()
+	}
+	return tasks
+}
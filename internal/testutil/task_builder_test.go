@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// TestTaskBuilder_Build verifies builders produce valid, independent tasks.
+func TestTaskBuilder_Build(t *testing.T) {
+	due := time.Date(2025, 11, 14, 0, 0, 0, 0, time.UTC)
+	b := NewTask().WithTitle("Ship").WithStatus(model.StatusToday).WithTags("work").WithDue(due)
+
+	first := b.Build()
+	second := b.Build()
+	if first == second || &first.Tags[0] == &second.Tags[0] {
+		t.Fatal("Build() returned shared state")
+	}
+	if first.Title != "Ship" || first.Status != model.StatusToday || !first.DueDate.Equal(due) {
+		t.Errorf("Build() = %+v", first)
+	}
+	if err := first.Validate(); err != nil {
+		t.Errorf("built task invalid: %v", err)
+	}
+
+	done := NewTask().WithStatus(model.StatusDone).Build()
+	if err := done.Validate(); err != nil {
+		t.Errorf("done task invalid: %v", err)
+	}
+}
+
+// TestTaskBuilder_WithHistory verifies replayed events update lifecycle
+// fields the way real transitions do.
+func TestTaskBuilder_WithHistory(t *testing.T) {
+	created := time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return created.AddDate(0, 0, n) }
+
+	task := NewTask().WithCreatedAt(created).WithHistory(
+		Picked(day(1)),
+		Deferred(day(2)),
+		Picked(day(3)),
+		Deferred(day(4)),
+		Completed(day(5)),
+	).Build()
+
+	if task.Status != model.StatusDone || task.DeferredCount != 2 {
+		t.Errorf("status = %s, deferred = %d", task.Status, task.DeferredCount)
+	}
+	if !task.CompletedAt.Equal(day(5)) || !task.UpdatedAt.Equal(day(5)) {
+		t.Errorf("CompletedAt = %v, UpdatedAt = %v", task.CompletedAt, task.UpdatedAt)
+	}
+	if err := task.Validate(); err != nil {
+		t.Errorf("task invalid after history: %v", err)
+	}
+}
+
+type recordingStore struct {
+	saved []*model.Task
+	fail  error
+}
+
+func (s *recordingStore) Save(task *model.Task) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	s.saved = append(s.saved, task)
+	return nil
+}
+
+// TestSeed verifies tasks are saved in order and failures name the task.
+func TestSeed(t *testing.T) {
+	store := &recordingStore{}
+	tasks := Tasks(NewTask().WithTitle("a"), NewTask().WithTitle("b"))
+	MustSeed(t, store, tasks...)
+	if len(store.saved) != 2 || store.saved[1].Title != "b" {
+		t.Errorf("saved = %v", store.saved)
+	}
+
+	store.fail = model.ErrDuplicateTaskID
+	err := Seed(store, tasks[0])
+	if !errors.Is(err, model.ErrDuplicateTaskID) {
+		t.Errorf("Seed() error = %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package textinput implements the single-line editor used by the TUI's
+// text fields. Editing operates on grapheme clusters rather than runes, so
+// text composed by an input method (Japanese and Chinese IMEs, dead keys,
+// emoji sequences) moves and deletes as the user sees it, and the cursor
+// column accounts for double-width characters.
+package textinput
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rivo/uniseg"
+)
+
+// Model holds the text being edited and the cursor position. The zero value
+// is an empty field ready for input.
+type Model struct {
+	value string
+	// cursor is a byte offset into value, always on a grapheme boundary.
+	cursor int
+}
+
+// New returns a field holding value with the cursor at the end.
+func New(value string) Model {
+	var m Model
+	m.SetValue(value)
+	return m
+}
+
+// Value returns the current text.
+func (m Model) Value() string {
+	return m.value
+}
+
+// SetValue replaces the text and moves the cursor to the end.
+func (m *Model) SetValue(value string) {
+	m.value = sanitize(value)
+	m.cursor = len(m.value)
+}
+
+// Cursor returns the cursor position in grapheme clusters from the start.
+func (m Model) Cursor() int {
+	return uniseg.GraphemeClusterCount(m.value[:m.cursor])
+}
+
+// CursorColumn returns the terminal column of the cursor, counting wide
+// characters as two cells.
+func (m Model) CursorColumn() int {
+	return uniseg.StringWidth(m.value[:m.cursor])
+}
+
+// Insert adds s at the cursor. s may hold several runes, as delivered by an
+// IME commit or a paste. Combining marks typed on their own (dead keys)
+// join the preceding character.
+func (m *Model) Insert(s string) {
+	s = sanitize(s)
+	if s == "" {
+		return
+	}
+	m.value = m.value[:m.cursor] + s + m.value[m.cursor:]
+	m.cursor = m.snap(m.cursor + len(s))
+}
+
+// Left moves the cursor one grapheme cluster to the left.
+func (m *Model) Left() {
+	m.cursor = m.prev(m.cursor)
+}
+
+// Right moves the cursor one grapheme cluster to the right.
+func (m *Model) Right() {
+	m.cursor = m.next(m.cursor)
+}
+
+// Home moves the cursor to the start.
+func (m *Model) Home() {
+	m.cursor = 0
+}
+
+// End moves the cursor to the end.
+func (m *Model) End() {
+	m.cursor = len(m.value)
+}
+
+// Backspace deletes the grapheme cluster before the cursor.
+func (m *Model) Backspace() {
+	start := m.prev(m.cursor)
+	m.value = m.value[:start] + m.value[m.cursor:]
+	m.cursor = start
+}
+
+// Delete deletes the grapheme cluster under the cursor.
+func (m *Model) Delete() {
+	end := m.next(m.cursor)
+	m.value = m.value[:m.cursor] + m.value[end:]
+}
+
+// DeleteToStart removes everything before the cursor.
+func (m *Model) DeleteToStart() {
+	m.value = m.value[m.cursor:]
+	m.cursor = 0
+}
+
+// DeleteToEnd removes everything after the cursor.
+func (m *Model) DeleteToEnd() {
+	m.value = m.value[:m.cursor]
+}
+
+// Update applies an editing key and reports whether it was consumed. Keys
+// it does not handle, such as enter and esc, are left to the caller.
+func (m *Model) Update(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyRunes:
+		m.Insert(string(msg.Runes))
+	case tea.KeySpace:
+		m.Insert(" ")
+	case tea.KeyLeft, tea.KeyCtrlB:
+		m.Left()
+	case tea.KeyRight, tea.KeyCtrlF:
+		m.Right()
+	case tea.KeyHome, tea.KeyCtrlA:
+		m.Home()
+	case tea.KeyEnd, tea.KeyCtrlE:
+		m.End()
+	case tea.KeyBackspace:
+		m.Backspace()
+	case tea.KeyDelete, tea.KeyCtrlD:
+		m.Delete()
+	case tea.KeyCtrlU:
+		m.DeleteToStart()
+	case tea.KeyCtrlK:
+		m.DeleteToEnd()
+	default:
+		return false
+	}
+	return true
+}
+
+// View renders the text with the grapheme cluster under the cursor passed
+// through cursor, or a trailing space when the cursor is at the end.
+func (m Model) View(cursor func(string) string) string {
+	under := m.value[m.cursor:m.next(m.cursor)]
+	if under == "" {
+		under = " "
+	}
+	return m.value[:m.cursor] + cursor(under) + m.value[m.next(m.cursor):]
+}
+
+// boundaries returns the byte offsets at which grapheme clusters start,
+// followed by len(value).
+func (m Model) boundaries() []int {
+	offsets := []int{0}
+	g := uniseg.NewGraphemes(m.value)
+	for g.Next() {
+		_, end := g.Positions()
+		offsets = append(offsets, end)
+	}
+	return offsets
+}
+
+// prev returns the boundary before offset, or 0.
+func (m Model) prev(offset int) int {
+	b := m.boundaries()
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < offset {
+			return b[i]
+		}
+	}
+	return 0
+}
+
+// next returns the boundary after offset, or len(value).
+func (m Model) next(offset int) int {
+	for _, b := range m.boundaries() {
+		if b > offset {
+			return b
+		}
+	}
+	return len(m.value)
+}
+
+// snap moves offset forward to the nearest boundary, for when an insertion
+// merged into the following cluster.
+func (m Model) snap(offset int) int {
+	for _, b := range m.boundaries() {
+		if b >= offset {
+			return b
+		}
+	}
+	return len(m.value)
+}
+
+// sanitize drops invalid UTF-8 and control characters, turning line breaks
+// and tabs from pastes into spaces.
+func sanitize(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,161 @@
+package textinput
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestModel_GraphemeEditing verifies that cursor movement and deletion treat
+// composed characters as single units.
+func TestModel_GraphemeEditing(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		edit      func(*Model)
+		wantValue string
+		wantPos   int
+	}{
+		{
+			name:      "backspace removes decomposed kana with voiced mark",
+			value:     "\u304b\u3099き",
+			edit:      func(m *Model) { m.Left(); m.Backspace() },
+			wantValue: "き",
+			wantPos:   0,
+		},
+		{
+			name:      "backspace removes whole ZWJ emoji",
+			value:     "go👩‍💻",
+			edit:      func(m *Model) { m.Backspace() },
+			wantValue: "go",
+			wantPos:   2,
+		},
+		{
+			name:      "delete removes flag pair",
+			value:     "🇯🇵x",
+			edit:      func(m *Model) { m.Home(); m.Delete() },
+			wantValue: "x",
+			wantPos:   0,
+		},
+		{
+			name:      "left skips combining accent",
+			value:     "cafe\u0301",
+			edit:      func(m *Model) { m.Left(); m.Insert("s") },
+			wantValue: "cafse\u0301",
+			wantPos:   4,
+		},
+		{
+			name:      "dead key combining mark joins previous letter",
+			value:     "e",
+			edit:      func(m *Model) { m.Insert("\u0301"); m.Backspace() },
+			wantValue: "",
+			wantPos:   0,
+		},
+		{
+			name:      "IME commit of several characters inserts at cursor",
+			value:     "買う",
+			edit:      func(m *Model) { m.Left(); m.Insert("い物に行") },
+			wantValue: "買い物に行う",
+			wantPos:   5,
+		},
+		{
+			name:      "Hangul jamo sequence is one cluster",
+			value:     "\u1112\u1161\u11ab!",
+			edit:      func(m *Model) { m.Home(); m.Right(); m.Backspace() },
+			wantValue: "!",
+			wantPos:   0,
+		},
+		{
+			name:      "movement stops at the edges",
+			value:     "ab",
+			edit:      func(m *Model) { m.Right(); m.Home(); m.Left(); m.Backspace() },
+			wantValue: "ab",
+			wantPos:   0,
+		},
+		{
+			name:  "kill to start and end",
+			value: "日本語テキスト",
+			edit: func(m *Model) {
+				m.Home()
+				m.Right()
+				m.Right()
+				m.Right()
+				m.DeleteToEnd()
+				m.Left()
+				m.DeleteToStart()
+			},
+			wantValue: "語",
+			wantPos:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.value)
+			tt.edit(&m)
+			if m.Value() != tt.wantValue {
+				t.Errorf("Value() = %q, want %q", m.Value(), tt.wantValue)
+			}
+			if m.Cursor() != tt.wantPos {
+				t.Errorf("Cursor() = %d, want %d", m.Cursor(), tt.wantPos)
+			}
+		})
+	}
+}
+
+// TestModel_CursorColumn verifies wide characters occupy two cells.
+func TestModel_CursorColumn(t *testing.T) {
+	m := New("日本a")
+	if got := m.CursorColumn(); got != 5 {
+		t.Errorf("CursorColumn() = %d, want 5", got)
+	}
+	m.Left()
+	m.Left()
+	if got := m.CursorColumn(); got != 2 {
+		t.Errorf("CursorColumn() = %d, want 2", got)
+	}
+}
+
+// TestModel_Update verifies key messages, including multi-rune IME and
+// paste input, are applied and unrelated keys are left alone.
+func TestModel_Update(t *testing.T) {
+	var m Model
+
+	steps := []struct {
+		msg     tea.KeyMsg
+		handled bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("東京")}, true},
+		{tea.KeyMsg{Type: tea.KeySpace}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("line\nbreak"), Paste: true}, true},
+		{tea.KeyMsg{Type: tea.KeyCtrlA}, true},
+		{tea.KeyMsg{Type: tea.KeyDelete}, true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, false},
+	}
+	for i, s := range steps {
+		if got := m.Update(s.msg); got != s.handled {
+			t.Errorf("step %d handled = %v, want %v", i, got, s.handled)
+		}
+	}
+
+	if m.Value() != "京 line break" {
+		t.Errorf("Value() = %q", m.Value())
+	}
+}
+
+// TestModel_View verifies the cursor highlights a whole cluster.
+func TestModel_View(t *testing.T) {
+	mark := func(s string) string { return "[" + s + "]" }
+
+	m := New("ne\u0301e")
+	m.Left()
+	m.Left()
+	if got := m.View(mark); got != "n[e\u0301]e" {
+		t.Errorf("View() = %q", got)
+	}
+
+	m.End()
+	if got := m.View(mark); got != "ne\u0301e[ ]" {
+		t.Errorf("View() at end = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+// Package theme colors the TUI's text to suit a dark or light terminal
+// background, with plain ANSI escapes so it needs no styling library.
+package theme
+
+import "strings"
+
+// Theme holds the SGR parameters, such as "1;36", for each kind of
+// highlighted text; empty ones leave the text plain.
+type Theme struct {
+	accent  string
+	warning string
+	muted   string
+}
+
+// The themes selectable by name. Plain adds no escapes at all.
+var (
+	Plain = Theme{}
+	Dark  = Theme{accent: "1;96", warning: "91", muted: "90"}
+	Light = Theme{accent: "1;34", warning: "31", muted: "2"}
+)
+
+// For returns the theme named name: "dark", "light" or "auto", which
+// guesses the background from $COLORFGBG and assumes a dark one when that
+// is unset. Setting $NO_COLOR (see no-color.org) selects Plain whatever
+// the name. getenv is normally os.Getenv.
+func For(name string, getenv func(string) string) Theme {
+	if getenv("NO_COLOR") != "" {
+		return Plain
+	}
+	switch name {
+	case "dark":
+		return Dark
+	case "light":
+		return Light
+	}
+	// COLORFGBG is "foreground;background" in the 16 ANSI colors, where
+	// 7 (white) and 9 to 15 are light backgrounds.
+	fgbg := getenv("COLORFGBG")
+	switch fgbg[strings.LastIndex(fgbg, ";")+1:] {
+	case "7", "9", "10", "11", "12", "13", "14", "15":
+		return Light
+	}
+	return Dark
+}
+
+// Accent highlights s, such as the cursor.
+func (t Theme) Accent(s string) string {
+	return paint(t.accent, s)
+}
+
+// Warning marks s as needing attention, such as an overdue date.
+func (t Theme) Warning(s string) string {
+	return paint(t.warning, s)
+}
+
+// Muted dims s, such as a finished task.
+func (t Theme) Muted(s string) string {
+	return paint(t.muted, s)
+}
+
+// paint wraps s in the SGR sequence sgr, and a reset after it.
+func paint(sgr, s string) string {
+	if sgr == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
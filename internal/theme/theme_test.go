@@ -0,0 +1,43 @@
+package theme
+
+import "testing"
+
+// TestFor verifies names and the environment select the expected theme.
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Theme
+	}{
+		{name: "dark", want: Dark},
+		{name: "light", want: Light},
+		{name: "auto", want: Dark},
+		{name: "auto", env: map[string]string{"COLORFGBG": "0;15"}, want: Light},
+		{name: "auto", env: map[string]string{"COLORFGBG": "15;default;0"}, want: Dark},
+		{name: "light", env: map[string]string{"NO_COLOR": "1"}, want: Plain},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := For(tt.name, getenv); got != tt.want {
+			t.Errorf("For(%q) with %v = %+v, want %+v", tt.name, tt.env, got, tt.want)
+		}
+	}
+}
+
+// TestTheme_Paint verifies text is wrapped in its color and a reset, and
+// left alone by the plain theme.
+func TestTheme_Paint(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{Dark.Warning("overdue"), "\x1b[91moverdue\x1b[0m"},
+		{Light.Accent(">"), "\x1b[1;34m>\x1b[0m"},
+		{Dark.Muted(""), ""},
+		{Plain.Muted("done"), "done"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("painted %q, want %q", tt.got, tt.want)
+		}
+	}
+}
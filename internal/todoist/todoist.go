@@ -0,0 +1,325 @@
+// Package todoist reads Todoist backups into tasks, to ease migrating to
+// togo. It accepts both of Todoist's export formats:
+//
+//   - the JSON of the Sync API, with "projects" and "items"
+//   - the CSV written for each project by Settings → Backups, whose rows
+//     are tasks, sections and comments
+//
+// togo has no projects, so a task's project becomes a tag named after it,
+// except for the Inbox. Todoist labels become tags too.
+package todoist
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// System is the ExternalRef system of tasks imported from Todoist.
+const System = "todoist"
+
+// inbox is the project whose tasks get no project tag.
+const inbox = "Inbox"
+
+// Parse reads a Todoist backup in either format, telling them apart by
+// their first character. project names the project of a CSV backup,
+// normally its file name; JSON backups name their own projects. Dates
+// without a time zone are read in loc.
+func Parse(r io.Reader, project string, loc *time.Location) ([]importer.Draft, []*importer.LineError, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return nil, nil, errors.New("empty Todoist backup")
+		}
+		switch {
+		case b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n':
+			br.ReadByte()
+			continue
+		case b[0] == '{':
+			return ParseJSON(br, loc)
+		default:
+			return ParseCSV(br, project, loc)
+		}
+	}
+}
+
+// backup is the part of a Sync API response that is imported.
+type backup struct {
+	Projects []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"projects"`
+	Items []item `json:"items"`
+}
+
+// item is a Sync API task.
+type item struct {
+	ID          string   `json:"id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	ProjectID   string   `json:"project_id"`
+	Priority    int      `json:"priority"`
+	Labels      []string `json:"labels"`
+	Checked     bool     `json:"checked"`
+	IsDeleted   bool     `json:"is_deleted"`
+	AddedAt     string   `json:"added_at"`
+	CompletedAt string   `json:"completed_at"`
+	Due         *struct {
+		Date        string `json:"date"`
+		Timezone    string `json:"timezone"`
+		String      string `json:"string"`
+		IsRecurring bool   `json:"is_recurring"`
+	} `json:"due"`
+}
+
+// ParseJSON reads a Sync API backup. Items that cannot be imported are
+// skipped; their Line is their 1-based position among the items.
+func ParseJSON(r io.Reader, loc *time.Location) (drafts []importer.Draft, skipped []*importer.LineError, err error) {
+	var b backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, nil, fmt.Errorf("reading Todoist backup: %w", err)
+	}
+	projects := map[string]string{}
+	for _, p := range b.Projects {
+		projects[p.ID] = p.Name
+	}
+
+	for i, it := range b.Items {
+		if it.IsDeleted {
+			continue
+		}
+		d, err := readItem(it, projects[it.ProjectID], loc)
+		if err != nil {
+			skipped = append(skipped, &importer.LineError{Line: i + 1, Err: err})
+			continue
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, skipped, nil
+}
+
+// readItem converts one Sync API item.
+func readItem(it item, project string, loc *time.Location) (importer.Draft, error) {
+	d := importer.Draft{
+		Title:    strings.TrimSpace(it.Content),
+		Notes:    it.Description,
+		Tags:     append(projectTags(project), it.Labels...),
+		Priority: apiPriority(it.Priority),
+		Ref:      model.ExternalRef{System: System, ID: it.ID},
+	}
+	if d.Title == "" {
+		return d, model.ErrEmptyTitle
+	}
+	if it.AddedAt != "" {
+		t, err := parseDate(it.AddedAt, loc)
+		if err != nil {
+			return d, fmt.Errorf("added_at: %w", err)
+		}
+		d.Created = t
+	}
+	if it.Checked {
+		d.Status = model.StatusDone
+		if it.CompletedAt != "" {
+			t, err := parseDate(it.CompletedAt, loc)
+			if err != nil {
+				return d, fmt.Errorf("completed_at: %w", err)
+			}
+			d.Completed = &t
+		}
+	}
+	if it.Due != nil && it.Due.Date != "" {
+		dueLoc := loc
+		if it.Due.Timezone != "" {
+			if l, err := time.LoadLocation(it.Due.Timezone); err == nil {
+				dueLoc = l
+			}
+		}
+		t, err := parseDate(it.Due.Date, dueLoc)
+		if err != nil {
+			return d, fmt.Errorf("due: %w", err)
+		}
+		d.Due = &t
+		if it.Due.IsRecurring {
+			d.Notes = appendNote(d.Notes, "Todoist due: "+it.Due.String)
+		}
+	}
+	return d, nil
+}
+
+// apiPriority maps the Sync API's priorities, where 4 is Todoist's p1.
+func apiPriority(p int) model.Priority {
+	switch p {
+	case 4:
+		return model.PriorityHigh
+	case 3:
+		return model.PriorityMedium
+	case 2:
+		return model.PriorityLow
+	default:
+		return model.PriorityNone
+	}
+}
+
+// csvPriority maps the CSV backup's priorities, where 1 is Todoist's p1.
+func csvPriority(p int) model.Priority {
+	switch p {
+	case 1:
+		return model.PriorityHigh
+	case 2:
+		return model.PriorityMedium
+	case 3:
+		return model.PriorityLow
+	default:
+		return model.PriorityNone
+	}
+}
+
+// ParseCSV reads one project's CSV backup. Comment rows are added to the
+// notes of the task above them and section rows are ignored. Due dates
+// Todoist stores as text, such as "every monday", cannot be represented
+// and are kept in the notes instead.
+func ParseCSV(r io.Reader, project string, loc *time.Location) (drafts []importer.Draft, skipped []*importer.LineError, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading Todoist backup: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	for _, name := range []string{"TYPE", "CONTENT"} {
+		if _, ok := col[name]; !ok {
+			return nil, nil, fmt.Errorf("not a Todoist backup: no %s column", name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	// last is the index in drafts of the task comments attach to, or -1.
+	last := -1
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		switch strings.ToLower(field(record, "TYPE")) {
+		case "task":
+			d, err := readRow(field, record, project, loc)
+			if err != nil {
+				skipped = append(skipped, &importer.LineError{Line: line, Err: err})
+				last = -1
+				continue
+			}
+			drafts = append(drafts, d)
+			last = len(drafts) - 1
+		case "note":
+			if last >= 0 {
+				drafts[last].Notes = appendNote(drafts[last].Notes, field(record, "CONTENT"))
+			}
+		}
+	}
+	return drafts, skipped, nil
+}
+
+// readRow converts one CSV task row.
+func readRow(field func([]string, string) string, record []string, project string, loc *time.Location) (importer.Draft, error) {
+	var d importer.Draft
+	var title []string
+	for _, w := range strings.Fields(field(record, "CONTENT")) {
+		if len(w) > 1 && w[0] == '@' {
+			d.Tags = append(d.Tags, w[1:])
+		} else {
+			title = append(title, w)
+		}
+	}
+	d.Title = strings.Join(title, " ")
+	if d.Title == "" {
+		return d, model.ErrEmptyTitle
+	}
+	d.Tags = append(projectTags(project), d.Tags...)
+	d.Notes = field(record, "DESCRIPTION")
+	if p := field(record, "PRIORITY"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return d, fmt.Errorf("priority %q is not a number", p)
+		}
+		d.Priority = csvPriority(n)
+	}
+	if due := field(record, "DATE"); due != "" {
+		dueLoc := loc
+		if tz := field(record, "TIMEZONE"); tz != "" {
+			if l, err := time.LoadLocation(tz); err == nil {
+				dueLoc = l
+			}
+		}
+		if t, err := parseDate(due, dueLoc); err == nil {
+			d.Due = &t
+		} else {
+			d.Notes = appendNote(d.Notes, "Todoist due: "+due)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(project + "\x00" + d.Title))
+	d.Ref = model.ExternalRef{System: System, ID: "csv-" + hex.EncodeToString(sum[:8])}
+	return d, nil
+}
+
+// projectTags returns the tag for project, none for the Inbox.
+func projectTags(project string) []string {
+	tag := strings.Join(strings.Fields(project), "-")
+	if tag == "" || strings.EqualFold(project, inbox) {
+		return nil
+	}
+	return []string{tag}
+}
+
+// appendNote adds a paragraph to notes.
+func appendNote(notes, note string) string {
+	if note == "" {
+		return notes
+	}
+	if notes == "" {
+		return note
+	}
+	return notes + "\n\n" + note
+}
+
+// dateLayouts are the forms Todoist writes dates in, tried after RFC 3339:
+// a floating date-time and a date alone.
+var dateLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04:05.999999", time.DateOnly}
+
+// parseDate reads a Todoist date, in loc unless it carries an offset.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date", s)
+}
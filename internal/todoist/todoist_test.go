@@ -0,0 +1,98 @@
+package todoist
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+)
+
+// TestParseJSON verifies Sync API items map onto drafts.
+func TestParseJSON(t *testing.T) {
+	input := `{
+  "projects": [{"id": "p1", "name": "Inbox"}, {"id": "p2", "name": "Home Admin"}],
+  "items": [
+    {"id": "1", "content": "Renew passport", "project_id": "p2", "priority": 4, "labels": ["travel"],
+     "added_at": "2024-06-01T09:00:00.000000Z", "due": {"date": "2024-06-30", "string": "Jun 30"}},
+    {"id": "2", "content": "Water plants", "project_id": "p1", "priority": 1,
+     "due": {"date": "2024-06-10T08:00:00", "timezone": "Europe/Berlin", "string": "every day at 8", "is_recurring": true}},
+    {"id": "3", "content": "Call the dentist", "project_id": "p1", "checked": true,
+     "completed_at": "2024-06-02T10:00:00Z"},
+    {"id": "4", "content": "Gone", "is_deleted": true},
+    {"id": "5", "content": " "}
+  ]
+}`
+	drafts, skipped, err := Parse(strings.NewReader(input), "ignored", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 3 {
+		t.Fatalf("Parse() = %d drafts, want 3: %+v", len(drafts), drafts)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tests := []struct {
+		d        int
+		tags     string
+		priority model.Priority
+		status   model.TaskStatus
+		due      time.Time
+		notes    string
+	}{
+		{d: 0, tags: "Home-Admin,travel", priority: model.PriorityHigh, due: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{d: 1, due: time.Date(2024, 6, 10, 8, 0, 0, 0, berlin), notes: "Todoist due: every day at 8"},
+		{d: 2, status: model.StatusDone},
+	}
+	for _, tt := range tests {
+		d := drafts[tt.d]
+		if strings.Join(d.Tags, ",") != tt.tags || d.Priority != tt.priority || d.Status != tt.status || d.Notes != tt.notes {
+			t.Errorf("draft %d = %+v", tt.d, d)
+		}
+		if (d.Due == nil) != tt.due.IsZero() || (d.Due != nil && !d.Due.Equal(tt.due)) {
+			t.Errorf("draft %d due = %v, want %v", tt.d, d.Due, tt.due)
+		}
+	}
+	if drafts[0].Ref.ID != "1" || !drafts[0].Created.Equal(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("first draft ref %v created %v", drafts[0].Ref, drafts[0].Created)
+	}
+	if len(skipped) != 1 || skipped[0].Line != 5 {
+		t.Errorf("skipped = %v, want item 5", skipped)
+	}
+}
+
+// TestParseCSV verifies a project's CSV backup maps onto drafts.
+func TestParseCSV(t *testing.T) {
+	input := "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+		"section,Paperwork,,,,,,,,\n" +
+		"task,Renew passport @travel,bring photos,1,1,Sam,,2024-06-30,en,UTC\n" +
+		"note,Office opens at 9,,,,,,,,\n" +
+		"task,Water plants,,4,1,Sam,,every day,en,UTC\n" +
+		"task,@orphan,,1,1,Sam,,,en,UTC\n" +
+		"task,Check the boiler,,x,1,Sam,,,en,UTC\n"
+
+	drafts, skipped, err := Parse(strings.NewReader(input), "Home", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts) != 2 {
+		t.Fatalf("Parse() = %d drafts, want 2: %+v", len(drafts), drafts)
+	}
+	d := drafts[0]
+	if d.Title != "Renew passport" || strings.Join(d.Tags, ",") != "Home,travel" || d.Priority != model.PriorityHigh {
+		t.Errorf("first draft = %+v", d)
+	}
+	if d.Notes != "bring photos\n\nOffice opens at 9" || d.Due == nil {
+		t.Errorf("first draft notes %q, due %v", d.Notes, d.Due)
+	}
+	if d := drafts[1]; d.Due != nil || d.Notes != "Todoist due: every day" || d.Priority != model.PriorityNone {
+		t.Errorf("second draft = %+v", d)
+	}
+	if len(skipped) != 2 || skipped[0].Line != 6 || skipped[1].Line != 7 {
+		t.Errorf("skipped = %v, want lines 6 and 7", skipped)
+	}
+
+	if _, _, err := Parse(strings.NewReader("a,b\n"), "Home", time.UTC); err == nil {
+		t.Error("Parse() accepted a CSV file without TYPE and CONTENT")
+	}
+}
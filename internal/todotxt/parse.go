@@ -0,0 +1,120 @@
+package todotxt
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+)
+
+// System is the ExternalRef system of tasks imported from todo.txt.
+const System = "todotxt"
+
+// letterPriorities maps todo.txt's letters back to priorities; letters
+// after C count as low.
+func letterPriority(letter byte) model.Priority {
+	switch letter {
+	case 'A':
+		return model.PriorityHigh
+	case 'B':
+		return model.PriorityMedium
+	default:
+		return model.PriorityLow
+	}
+}
+
+// Parse reads todo.txt lines from r as drafts, skipping blank lines. Dates
+// are read in loc. The first malformed line stops parsing with an
+// *importer.LineError.
+func Parse(r io.Reader, loc *time.Location) ([]importer.Draft, error) {
+	var drafts []importer.Draft
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		d, err := ParseLine(line, loc)
+		if err != nil {
+			return nil, &importer.LineError{Line: n, Err: err}
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, sc.Err()
+}
+
+// ParseLine reads one todo.txt line, the inverse of Format. Its ExternalRef
+// ID is a hash of the title and tags, so re-importing an edited file only
+// adds the tasks whose text changed, not those merely completed or
+// rescheduled.
+func ParseLine(line string, loc *time.Location) (importer.Draft, error) {
+	var d importer.Draft
+	words := strings.Fields(line)
+
+	if len(words) > 0 && words[0] == "x" {
+		d.Status = model.StatusDone
+		words = words[1:]
+		if t, ok := parseDate(words, loc); ok {
+			d.Completed = &t
+			words = words[1:]
+		}
+	} else if len(words) > 0 && isPriority(words[0]) {
+		d.Priority = letterPriority(words[0][1])
+		words = words[1:]
+	}
+	if t, ok := parseDate(words, loc); ok {
+		d.Created = t
+		words = words[1:]
+	}
+
+	var title []string
+	for _, w := range words {
+		key, value, _ := strings.Cut(w, ":")
+		switch {
+		case len(w) > 1 && w[0] == '+':
+			d.Tags = append(d.Tags, w[1:])
+		case len(w) > 1 && w[0] == '@':
+			d.Tags = append(d.Tags, w)
+		case key == "due" && value != "":
+			t, err := time.ParseInLocation(dateLayout, value, loc)
+			if err != nil {
+				return d, fmt.Errorf("due date %q is not YYYY-MM-DD", value)
+			}
+			d.Due = &t
+		case key == "status" && value == string(model.StatusToday) && d.Status == "":
+			d.Status = model.StatusToday
+		case key == "pri" && len(value) == 1 && value[0] >= 'A' && value[0] <= 'Z':
+			d.Priority = letterPriority(value[0])
+		default:
+			title = append(title, w)
+		}
+	}
+	d.Title = strings.Join(title, " ")
+	if d.Title == "" {
+		return d, model.ErrEmptyTitle
+	}
+
+	sum := sha256.Sum256([]byte(d.Title + "\x00" + strings.Join(d.Tags, " ")))
+	d.Ref = model.ExternalRef{System: System, ID: hex.EncodeToString(sum[:8])}
+	return d, nil
+}
+
+// isPriority reports whether w is a priority marker such as "(A)".
+func isPriority(w string) bool {
+	return len(w) == 3 && w[0] == '(' && w[2] == ')' && w[1] >= 'A' && w[1] <= 'Z'
+}
+
+// parseDate reads a leading date from words, if there is one.
+func parseDate(words []string, loc *time.Location) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(dateLayout, words[0], loc)
+	return t, err == nil
+}
@@ -0,0 +1,107 @@
+package todotxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"togo/internal/importer"
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestParseLine verifies each todo.txt element is mapped onto the draft.
+func TestParseLine(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		line    string
+		check   func(t *testing.T, d importer.Draft)
+		wantErr string
+	}{
+		{
+			name: "full open task",
+			line: "(A) 2024-06-01 Renew passport +admin @town due:2024-06-30 status:today",
+			check: func(t *testing.T, d importer.Draft) {
+				if d.Title != "Renew passport" || d.Priority != model.PriorityHigh || !d.Created.Equal(day(1)) ||
+					!d.Due.Equal(day(30)) || d.Status != model.StatusToday ||
+					strings.Join(d.Tags, ",") != "admin,@town" || d.Ref.System != System {
+					t.Errorf("ParseLine() = %+v", d)
+				}
+			},
+		},
+		{
+			name: "done with dates and kept priority",
+			line: "x 2024-06-14 2024-06-01 Renew passport pri:C",
+			check: func(t *testing.T, d importer.Draft) {
+				if d.Status != model.StatusDone || !d.Completed.Equal(day(14)) || !d.Created.Equal(day(1)) || d.Priority != model.PriorityLow {
+					t.Errorf("ParseLine() = %+v", d)
+				}
+			},
+		},
+		{
+			name: "low letters and unknown keys stay",
+			line: "(Q) Read https://example.com/a:b",
+			check: func(t *testing.T, d importer.Draft) {
+				if d.Priority != model.PriorityLow || d.Title != "Read https://example.com/a:b" || !d.Created.IsZero() {
+					t.Errorf("ParseLine() = %+v", d)
+				}
+			},
+		},
+		{name: "bad due date", line: "Pay rent due:friday", wantErr: "due date"},
+		{name: "only tags", line: "(B) +admin @home", wantErr: "title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseLine(tt.line, time.UTC)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseLine() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLine() error: %v", err)
+			}
+			tt.check(t, d)
+		})
+	}
+}
+
+// TestParse_RoundTrip verifies exported tasks read back unchanged and keep
+// their identity when the completion state changes.
+func TestParse_RoundTrip(t *testing.T) {
+	created := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	want := testutil.NewTask().WithTitle("Renew passport").WithTags("admin", "@town").
+		WithCreatedAt(created).WithPriority(model.PriorityMedium).Build()
+
+	drafts, err := Parse(strings.NewReader("\n"+Format(want)+"\n\n"), time.UTC)
+	if err != nil || len(drafts) != 1 {
+		t.Fatalf("Parse() = %v, %v", drafts, err)
+	}
+	got, err := drafts[0].Task()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != want.Title || got.Priority != want.Priority || !got.CreatedAt.Equal(created) || strings.Join(got.Tags, ",") != "admin,@town" {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	completed := testutil.NewTask().WithTitle("Renew passport").WithTags("admin", "@town").
+		WithCreatedAt(created).WithHistory(testutil.Completed(created.AddDate(0, 0, 13))).Build()
+	done, _ := ParseLine(Format(completed), time.UTC)
+	if done.Ref != drafts[0].Ref {
+		t.Errorf("completing a task changed its reference: %v vs %v", done.Ref, drafts[0].Ref)
+	}
+}
+
+// TestParse_ReportsLine verifies errors name the offending line.
+func TestParse_ReportsLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("Fine\n\n(A) +tag-only\n"), time.UTC)
+	var lineErr *importer.LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 3 {
+		t.Errorf("Parse() error = %v, want a LineError for line 3", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Package todotxt converts between tasks and the todo.txt format
+// (https://github.com/todotxt/todo.txt), one task per line:
+//
+//	x (A) 2024-06-14 2024-06-01 Renew passport +admin @town due:2024-06-30
+//
+// Priorities map to (A) high, (B) medium and (C) low. Tags beginning with
+// "@" become contexts and all other tags become projects. Due dates use
+// the common due: extension, and tasks planned for today carry
+// status:today so they keep their place when read back.
+package todotxt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"togo/internal/model"
+)
+
+// dateLayout is the only date format todo.txt allows.
+const dateLayout = "2006-01-02"
+
+// priorityLetters maps priorities to todo.txt's letters.
+var priorityLetters = map[model.Priority]string{
+	model.PriorityHigh:   "A",
+	model.PriorityMedium: "B",
+	model.PriorityLow:    "C",
+}
+
+// Format renders task as one todo.txt line, without a line break.
+func Format(task *model.Task) string {
+	var parts []string
+	letter := priorityLetters[task.Priority]
+	if task.Status == model.StatusDone {
+		parts = append(parts, "x")
+		if task.CompletedAt != nil {
+			parts = append(parts, task.CompletedAt.Format(dateLayout))
+		}
+	} else if letter != "" {
+		parts = append(parts, "("+letter+")")
+	}
+	parts = append(parts, task.CreatedAt.Format(dateLayout))
+	parts = append(parts, strings.Fields(task.Title)...)
+
+	for _, tag := range task.Tags {
+		if strings.HasPrefix(tag, "@") {
+			parts = append(parts, tag)
+		} else {
+			parts = append(parts, "+"+tag)
+		}
+	}
+	if task.DueDate != nil {
+		parts = append(parts, "due:"+task.DueDate.Format(dateLayout))
+	}
+	if task.Status == model.StatusToday {
+		parts = append(parts, "status:today")
+	}
+	if task.Status == model.StatusDone && letter != "" {
+		// Completed tasks lose their leading priority; keep it as a tag.
+		parts = append(parts, "pri:"+letter)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Write renders tasks to w, one line each, in the order given.
+func Write(w io.Writer, tasks []*model.Task) error {
+	bw := bufio.NewWriter(w)
+	for _, t := range tasks {
+		bw.WriteString(Format(t))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,69 @@
+package todotxt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"togo/internal/model"
+	"togo/internal/testutil"
+)
+
+// TestFormat verifies the mapping of each task attribute.
+func TestFormat(t *testing.T) {
+	created := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+	completed := time.Date(2024, 6, 14, 18, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	base := func() *testutil.TaskBuilder {
+		return testutil.NewTask().WithTitle("Renew  passport").WithCreatedAt(created)
+	}
+
+	tests := []struct {
+		name string
+		task *model.Task
+		want string
+	}{
+		{
+			name: "plain",
+			task: base().Build(),
+			want: "2024-06-01 Renew passport",
+		},
+		{
+			name: "priority, projects, contexts and due date",
+			task: base().WithTags("admin", "@town").WithDue(due).WithPriority(model.PriorityHigh).Build(),
+			want: "(A) 2024-06-01 Renew passport +admin @town due:2024-06-30",
+		},
+		{
+			name: "today",
+			task: base().WithStatus(model.StatusToday).Build(),
+			want: "2024-06-01 Renew passport status:today",
+		},
+		{
+			name: "done keeps its priority as a tag",
+			task: base().WithPriority(model.PriorityLow).WithHistory(testutil.Completed(completed)).Build(),
+			want: "x 2024-06-14 2024-06-01 Renew passport pri:C",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.task); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWrite verifies one line is written per task.
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	tasks := testutil.Tasks(
+		testutil.NewTask().WithTitle("One").WithCreatedAt(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		testutil.NewTask().WithTitle("Two").WithCreatedAt(time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)),
+	)
+	if err := Write(&buf, tasks); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024-06-01 One\n2024-06-02 Two\n"; buf.String() != want {
+		t.Errorf("Write() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,99 @@
+// Package watch notices when a journal's files change on disk, such as
+// when another togo process or a sync tool writes them, so a running TUI
+// can reload instead of showing stale tasks.
+package watch
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultSettle is how long Watch waits for a burst of writes to end
+// before reporting it; an atomic rewrite is several events.
+const DefaultSettle = 100 * time.Millisecond
+
+// Watcher reports changes to a set of files.
+type Watcher struct {
+	fw     *fsnotify.Watcher
+	names  []string
+	settle time.Duration
+	notify func()
+	onErr  func(error)
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Watch calls notify after the files at paths, which must share a
+// directory, are created, written, replaced or removed, once per burst of
+// changes that ends with settle of quiet. The directory is watched rather
+// than the files, since journals are replaced by renaming a new file over
+// them. Errors from the watch go to onError, which may be nil.
+func Watch(paths []string, settle time.Duration, notify func(), onError func(error)) (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{fw: fw, settle: settle, notify: notify, onErr: onError, done: make(chan struct{})}
+	for _, p := range paths {
+		w.names = append(w.names, filepath.Base(p))
+	}
+	if len(paths) > 0 {
+		if err := fw.Add(filepath.Dir(paths[0])); err != nil {
+			fw.Close()
+			return nil, err
+		}
+	}
+	w.wg.Add(1)
+	go w.loop()
+	return w, nil
+}
+
+// Close stops watching. No notification is delivered after it returns.
+func (w *Watcher) Close() error {
+	close(w.done)
+	err := w.fw.Close()
+	w.wg.Wait()
+	return err
+}
+
+// loop delivers settled changes until Close.
+func (w *Watcher) loop() {
+	defer w.wg.Done()
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fw.Events:
+			if !ok {
+				return
+			}
+			if slices.Contains(w.names, filepath.Base(ev.Name)) && ev.Op != fsnotify.Chmod {
+				timer.Reset(w.settle)
+			}
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return
+			}
+			if w.onErr != nil {
+				w.onErr(err)
+			}
+		case <-timer.C:
+			select {
+			case <-w.done:
+				return
+			default:
+				w.notify()
+			}
+		}
+	}
+}
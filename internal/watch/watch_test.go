@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatch verifies writes, replacements and removals of the watched
+// files are reported once per burst, and other files are ignored.
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	journal := filepath.Join(dir, "tasks.json")
+	log := journal + ".wal"
+	write := func(path, content string) func() {
+		return func() {
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+		want   bool
+	}{
+		{name: "create", change: write(journal, "{}"), want: true},
+		{name: "burst", change: func() {
+			for i := range 5 {
+				write(log, string(rune('a'+i)))()
+			}
+		}, want: true},
+		{name: "replace", change: func() {
+			tmp := filepath.Join(dir, ".tasks-1")
+			write(tmp, `{"version":1}`)()
+			if err := os.Rename(tmp, journal); err != nil {
+				t.Fatal(err)
+			}
+		}, want: true},
+		{name: "other file", change: write(filepath.Join(dir, "config"), "x"), want: false},
+		{name: "remove", change: func() { os.Remove(log) }, want: true},
+	}
+
+	changes := make(chan struct{}, 10)
+	w, err := Watch([]string{journal, log}, 20*time.Millisecond, func() { changes <- struct{}{} }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for _, tt := range tests {
+		tt.change()
+		select {
+		case <-changes:
+			if !tt.want {
+				t.Errorf("%s: reported a change", tt.name)
+			}
+		case <-time.After(500 * time.Millisecond):
+			if tt.want {
+				t.Errorf("%s: no change reported", tt.name)
+			}
+		}
+		select {
+		case <-changes:
+			t.Errorf("%s: reported more than once", tt.name)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
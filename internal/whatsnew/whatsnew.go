@@ -0,0 +1,148 @@
+// Package whatsnew tells users what changed since the version they last ran.
+// Release notes live in an embedded, structured changelog; the last version
+// a user saw is remembered in a small file under the state directory.
+package whatsnew
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"togo/internal/paths"
+)
+
+// Keybinding describes a key that was added or whose action changed.
+type Keybinding struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+}
+
+// Entry is the changelog for one release.
+type Entry struct {
+	Version     string       `json:"version"`
+	Features    []string     `json:"features,omitempty"`
+	Keybindings []Keybinding `json:"keybindings,omitempty"`
+}
+
+//go:embed changelog.json
+var changelogJSON []byte
+
+// Changelog returns the embedded changelog, newest release first.
+func Changelog() []Entry {
+	var entries []Entry
+	if err := json.Unmarshal(changelogJSON, &entries); err != nil {
+		panic("whatsnew: malformed embedded changelog: " + err.Error())
+	}
+	return entries
+}
+
+// Current is the newest version in the embedded changelog.
+func Current() string {
+	return Changelog()[0].Version
+}
+
+// Since returns the entries newer than lastSeen and no newer than current,
+// newest first. An empty lastSeen means a fresh install, for which there is
+// nothing to announce.
+func Since(entries []Entry, lastSeen, current string) []Entry {
+	if lastSeen == "" {
+		return nil
+	}
+	var out []Entry
+	for _, e := range entries {
+		if Compare(e.Version, lastSeen) > 0 && Compare(e.Version, current) <= 0 {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Compare orders dotted version strings numerically, ignoring a leading "v"
+// and any pre-release or build suffix. It returns -1, 0 or 1.
+func Compare(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// Render formats entries as the body of the what's-new screen.
+func Render(entries []Entry) string {
+	var b strings.Builder
+	b.WriteString("What's new in togo\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n%s\n", e.Version)
+		for _, f := range e.Features {
+			fmt.Fprintf(&b, "  • %s\n", f)
+		}
+		if len(e.Keybindings) > 0 {
+			b.WriteString("  Keys:\n")
+			for _, k := range e.Keybindings {
+				fmt.Fprintf(&b, "    %-6s %s\n", k.Key, k.Action)
+			}
+		}
+	}
+	return b.String()
+}
+
+// LastSeen reads the version recorded at path. A missing file yields "".
+func LastSeen(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// MarkSeen records version at path, creating parent directories as needed.
+func MarkSeen(path, version string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(version+"\n"), 0o644)
+}
+
+// DefaultStatePath is where the last seen version is kept: last_version in
+// togo's state directory (see paths.Dir).
+func DefaultStatePath() (string, error) {
+	dir, err := paths.Dir(paths.State)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last_version"), nil
+}
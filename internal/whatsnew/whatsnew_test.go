@@ -0,0 +1,107 @@
+package whatsnew
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestChangelog_NewestFirst verifies the embedded changelog parses and is
+// ordered newest first.
+func TestChangelog_NewestFirst(t *testing.T) {
+	entries := Changelog()
+	if len(entries) == 0 {
+		t.Fatal("embedded changelog is empty")
+	}
+	for i := 1; i < len(entries); i++ {
+		if Compare(entries[i-1].Version, entries[i].Version) <= 0 {
+			t.Errorf("entry %d (%s) is not newer than entry %d (%s)",
+				i-1, entries[i-1].Version, i, entries[i].Version)
+		}
+	}
+	if Current() != entries[0].Version {
+		t.Errorf("Current() = %q, want %q", Current(), entries[0].Version)
+	}
+}
+
+// TestCompare verifies numeric ordering of version strings.
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.2.0", "0.2.0", 0},
+		{"v0.2.0", "0.2", 0},
+		{"0.10.0", "0.9.0", 1},
+		{"0.2.0", "0.2.1", -1},
+		{"1.0.0-rc1", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestSince verifies which releases are announced.
+func TestSince(t *testing.T) {
+	entries := []Entry{{Version: "0.3.0"}, {Version: "0.2.0"}, {Version: "0.1.0"}}
+
+	tests := []struct {
+		name     string
+		lastSeen string
+		current  string
+		want     []string
+	}{
+		{"fresh install", "", "0.3.0", nil},
+		{"up to date", "0.3.0", "0.3.0", nil},
+		{"one release behind", "0.2.0", "0.3.0", []string{"0.3.0"}},
+		{"two releases behind", "0.1.0", "0.3.0", []string{"0.3.0", "0.2.0"}},
+		{"changelog ahead of binary", "0.1.0", "0.2.0", []string{"0.2.0"}},
+		{"downgrade", "0.3.0", "0.2.0", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Since(entries, tt.lastSeen, tt.current) {
+				got = append(got, e.Version)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Since() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRender verifies features and keybindings appear on the screen.
+func TestRender(t *testing.T) {
+	got := Render([]Entry{{
+		Version:     "0.3.0",
+		Features:    []string{"Pinned tasks"},
+		Keybindings: []Keybinding{{Key: "p", Action: "pin the selected task"}},
+	}})
+
+	for _, want := range []string{"0.3.0", "• Pinned tasks", "p      pin the selected task"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+// TestLastSeen_RoundTrip verifies the recorded version survives a reload and
+// that a missing state file reads as a fresh install.
+func TestLastSeen_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "last_version")
+
+	if got, err := LastSeen(path); err != nil || got != "" {
+		t.Fatalf("LastSeen() on missing file = %q, %v", got, err)
+	}
+	if err := MarkSeen(path, "0.3.0"); err != nil {
+		t.Fatalf("MarkSeen() error: %v", err)
+	}
+	if got, err := LastSeen(path); err != nil || got != "0.3.0" {
+		t.Errorf("LastSeen() = %q, %v, want 0.3.0", got, err)
+	}
+}
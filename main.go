@@ -1,90 +1,1461 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"togo/internal/celebrate"
+	"togo/internal/clipboard"
+	"togo/internal/collation"
+	"togo/internal/config"
+	"togo/internal/conflict"
+	"togo/internal/display"
+	taskmodel "togo/internal/model"
+	"togo/internal/query"
+	"togo/internal/remind"
+	"togo/internal/service"
+	"togo/internal/termlink"
+	"togo/internal/textinput"
+	"togo/internal/theme"
 )
 
 type model struct {
-	choices  []string
-	cursor   int
-	selected map[int]struct{}
+	cursor int
+	opts   uiOptions
+	filter taskmodel.TaskFilter
+
+	// saved are the user's named filters, recalled with keys 1-9.
+	saved query.SavedFilters
+
+	// tasks supplies the listed tasks, those matching filter; it is nil
+	// until a journal is open, and the list empty.
+	tasks *service.TaskService
+	list  []*taskmodel.Task
+
+	// stats summarizes list for the footer, and todayCount and todayLimit
+	// give the size of today's list and its cap, zero when uncapped, for the
+	// header, as of the last refresh.
+	stats                  taskmodel.QuickStats
+	todayCount, todayLimit int
+
+	// idDisplay selects how the list shows task IDs, theme colors it and
+	// keymap, config.KeymapVim or KeymapArrows, says whether the vim keys
+	// move the cursor as the arrow keys do.
+	idDisplay taskmodel.IDDisplayMode
+	theme     theme.Theme
+	keymap    string
+
+	// byTitle sorts the list by title, in titleOrder, instead of the pool
+	// by urgency. titleOrderOf, if not nil, gives each journal's order.
+	byTitle      bool
+	titleOrder   *collation.Order
+	titleOrderOf func(journal string) *collation.Order
+
+	// links renders URLs in titles and imported tasks' refs, as hyperlinks
+	// where the terminal supports them.
+	links termlink.Linker
+
+	// claimEdit, if not nil, takes the edit lock on the task under the
+	// cursor, the one actions change, so other processes leave it alone,
+	// and returns the locks they hold; lockedBy holds those.
+	claimEdit func(id taskmodel.TaskID) (map[taskmodel.TaskID]taskmodel.EditLock, error)
+	lockedBy  map[taskmodel.TaskID]taskmodel.EditLock
+
+	// journal names the open journal, one of journals; openJournal switches
+	// to another. All are empty in demo mode.
+	journal     string
+	journals    []string
+	openJournal func(name string) (*service.TaskService, error)
+
+	// notice reports the outcome of the last action, until the next key.
+	notice string
+
+	// terminal receives what the TUI sends besides its frames: copied
+	// tasks, in the format copyCycle picks, and the bell. Nothing is copied
+	// while it is nil.
+	terminal  io.Writer
+	copyCycle clipboard.Cycler
+
+	// celebrator gives the feedback on completing a task, and confetti
+	// holds the frames of the animation still to show.
+	celebrator celebrate.Celebrator
+	confetti   []string
+
+	// rollover runs the daily rollover, and archiveDone, if not nil, moves
+	// long-completed tasks into the journal's archive, returning how many;
+	// both happen, after recurring tasks come due, when a journal opens and
+	// at each midnight while the TUI runs.
+	rollover    bool
+	archiveDone func() (int, error)
+
+	// reminders shows desktop notifications as the open journal's
+	// reminders and due times are reached; nil when they are off.
+	reminders *remind.Scheduler
+
+	// changes receives a value when another process changed the open
+	// journal; nil when it is not watched. writeErrs receives the failures
+	// of the journal's writes made in the background, if any are.
+	changes   <-chan struct{}
+	writeErrs <-chan error
+
+	// whatsNew is the rendered what's-new screen shown after an upgrade;
+	// empty once dismissed.
+	whatsNew string
+
+	// conflictsOf returns the sidecar listing the named journal's
+	// unresolved sync conflicts, or nil; it is nil itself in demo mode.
+	// conflicts holds the open journal's, and resolving is set while they
+	// are being worked through.
+	conflictsOf func(name string) *conflict.Sidecar
+	conflicts   []conflict.Entry
+	resolving   bool
+
+	// statsRange is the range, one of statsRanges, shown on the statistics
+	// screen while it is open; empty otherwise. rangeStats holds the
+	// statistics over it, or statsErr why they could not be computed.
+	statsRange string
+	rangeStats service.Stats
+	statsErr   error
+
+	// review holds the tasks still to decide on while a review is open,
+	// the first shown, and reviewed counts the decisions so far. The
+	// review goes through the tasks reviewPolicy chooses; markReviewed, if
+	// not nil, records that one was finished.
+	review       []service.ReviewItem
+	reviewed     reviewTally
+	reviewPolicy service.ReviewPolicy
+	markReviewed func() error
+
+	// nudges are the review reminders shown in the header, as of the last
+	// refresh. They follow nudgePolicy, lastReviewed, if not nil, telling
+	// when the journal was last reviewed; there are none without it.
+	nudges       []taskmodel.Nudge
+	nudgePolicy  taskmodel.NudgePolicy
+	lastReviewed func() (time.Time, error)
+
+	// plan is the proposed today list shown while planning; planSkip marks
+	// the items taken out of it and planCursor the one under the cursor.
+	// Proposals follow planPolicy.
+	planning   bool
+	plan       service.Plan
+	planSkip   map[int]bool
+	planCursor int
+	planPolicy service.PlanPolicy
+
+	// tags lists the journal's tags while the tag screen is open; tagCursor
+	// is the one under the cursor and tagMarked those marked for merging.
+	// tagEdit holds the new name while tagEditing.
+	tagging    bool
+	tags       []service.TagCount
+	tagCursor  int
+	tagMarked  map[string]bool
+	tagEditing bool
+	tagEdit    textinput.Model
+
+	// search holds the query typed in the search bar while searching; the
+	// list follows it on every key. searchFrom is the query before the
+	// search started, restored by esc.
+	searching  bool
+	search     textinput.Model
+	searchFrom string
+
+	// boardColumn is the board column in focus, an index into
+	// boardColumns, and boardRows the row under the cursor in each.
+	boardColumn int
+	boardRows   [len(boardColumns)]int
 }
 
+// initializeModel returns the TUI with nothing open: the caller sets its
+// tasks, from the journal or the demo, and refreshes the list.
 func initializeModel() model {
-	return model{
-		choices:  []string{"Eat", "Sleep", "Dream"},
-		selected: make(map[int]struct{}),
-	}
+	return model{opts: uiOptions{view: viewList}}
+}
+
+// journalChangedMsg reports that the open journal changed on disk.
+type journalChangedMsg struct{}
+
+// writeFailedMsg reports that writing the journal in the background failed.
+type writeFailedMsg struct {
+	err error
+}
+
+// dayStartedMsg reports that midnight passed.
+type dayStartedMsg struct{}
+
+// remindTickMsg asks for a check for reminders.
+type remindTickMsg struct{}
+
+// remindedMsg reports the reminders a check notified the user of.
+type remindedMsg struct {
+	due []remind.Reminder
+	err error
+}
+
+// confettiMsg asks for the next frame of the confetti animation.
+type confettiMsg struct{}
+
+// celebratedMsg reports how ringing the bell or running the celebration
+// command went.
+type celebratedMsg struct {
+	err error
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.waitForChange(), m.waitForWriteError(), m.waitForDay(), m.waitForReminder())
+}
+
+// reminderInterval is how often the TUI checks for reminders.
+const reminderInterval = 30 * time.Second
+
+// waitForReminder returns a command delivering the next remindTickMsg, or
+// nil when reminders are off.
+func (m model) waitForReminder() tea.Cmd {
+	if m.reminders == nil {
+		return nil
+	}
+	return tea.Tick(reminderInterval, func(time.Time) tea.Msg {
+		return remindTickMsg{}
+	})
+}
+
+// checkReminders returns a command notifying the user of the reminders of
+// the open journal reached since the last check. The tasks are listed
+// here, while the journal cannot change under the check.
+func (m model) checkReminders() tea.Cmd {
+	if m.reminders == nil || m.tasks == nil {
+		return nil
+	}
+	now := taskmodel.Now()
+	tasks, err := m.tasks.ListTasks(taskmodel.TaskFilter{})
+	if err != nil {
+		return func() tea.Msg { return remindedMsg{err: err} }
+	}
+	reminders := m.reminders
+	return func() tea.Msg {
+		due, err := reminders.Check(context.Background(), tasks, now)
+		return remindedMsg{due: due, err: err}
+	}
+}
+
+// waitForDay returns a command delivering a dayStartedMsg at the next
+// midnight, or nil when there is nothing to do then.
+func (m model) waitForDay() tea.Cmd {
+	if m.tasks == nil {
+		return nil
+	}
+	now := taskmodel.Now()
+	return tea.Tick(taskmodel.StartOfDay(now).AddDate(0, 0, 1).Sub(now), func(time.Time) tea.Msg {
+		return dayStartedMsg{}
+	})
+}
+
+// waitForChange returns a command delivering the next journalChangedMsg,
+// or nil when the journal is not watched.
+func (m model) waitForChange() tea.Cmd {
+	if m.changes == nil {
+		return nil
+	}
+	changes := m.changes
+	return func() tea.Msg {
+		<-changes
+		return journalChangedMsg{}
+	}
+}
+
+// waitForWriteError returns a command delivering the next writeFailedMsg,
+// or nil when the journal is written in the foreground.
+func (m model) waitForWriteError() tea.Cmd {
+	if m.writeErrs == nil {
+		return nil
+	}
+	errs := m.writeErrs
+	return func() tea.Msg {
+		return writeFailedMsg{<-errs}
+	}
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case journalChangedMsg:
+		return m.reload().claim(), m.waitForChange()
+	case writeFailedMsg:
+		m.notice = "Cannot save the journal: " + msg.err.Error()
+		return m, m.waitForWriteError()
+	case dayStartedMsg:
+		return m.startDay(), m.waitForDay()
+	case remindTickMsg:
+		return m, tea.Batch(m.checkReminders(), m.waitForReminder())
+	case confettiMsg:
+		if len(m.confetti) > 0 {
+			m.confetti = m.confetti[1:]
+		}
+		if len(m.confetti) > 0 {
+			return m, nextConfetti()
+		}
+		return m, nil
+	case celebratedMsg:
+		if msg.err != nil {
+			m.notice = "Cannot celebrate: " + msg.err.Error()
+		}
+		return m, nil
+	case remindedMsg:
+		var notices []string
+		for _, r := range msg.due {
+			notices = append(notices, fmt.Sprintf("Reminder: %s.", r.Task.Title))
+		}
+		if msg.err != nil {
+			notices = append(notices, "Notification failed: "+msg.err.Error())
+		}
+		if len(notices) > 0 {
+			m.notice = strings.Join(notices, " ")
+		}
+		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
+		if m.whatsNew != "" {
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.whatsNew = ""
+			return m, nil
+		}
+		if m.resolving {
+			return m.resolveKey(msg.String())
+		}
+		if m.statsRange != "" {
+			return m.statsKey(msg.String())
+		}
+		if len(m.review) > 0 {
+			return m.reviewKey(msg.String())
+		}
+		if m.planning {
+			return m.planKey(msg.String())
+		}
+		if m.tagging {
+			return m.tagKey(msg)
+		}
+		if m.searching {
+			return m.searchKey(msg)
+		}
+		m.notice = ""
+		var cmd tea.Cmd
+		switch m.navKey(msg.String()) {
 		case "ctrl+c", "q":
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
+		case "up":
+			m = m.moveCursor(-1)
+		case "down":
+			m = m.moveCursor(1)
+		case "b":
+			if m.opts.view == viewBoard {
+				m.opts.view = viewList
+			} else {
+				m.opts.view = viewBoard
+			}
+		case "left":
+			m.boardColumn = max(m.boardColumn-1, 0)
+		case "right":
+			m.boardColumn = min(m.boardColumn+1, len(boardColumns)-1)
+		case "shift+left":
+			m = m.moveAcross(-1)
+		case "shift+right":
+			t := m.current()
+			m = m.moveAcross(1)
+			m, cmd = m.celebrate(t)
+		case "J":
+			m = m.nextJournal()
+		case "c":
+			m.resolving = len(m.conflicts) > 0
+		case "s":
+			if m.tasks != nil {
+				m = m.showStats(statsRanges[0])
+			}
+		case "r":
+			m = m.startReview()
+		case "p":
+			m = m.startPlan()
+		case "#":
+			m = m.startTags()
+		case "/":
+			if m.tasks != nil {
+				m.searching, m.search, m.searchFrom = true, textinput.New(m.opts.query), m.opts.query
+			}
+		case "x":
+			t := m.current()
+			m = m.act(m.complete)
+			m, cmd = m.celebrate(t)
+		case "t":
+			m = m.act(m.moveToToday)
+		case "d":
+			m = m.act(m.deferTask)
+		case "*":
+			m = m.act(m.togglePin)
+		case "z":
+			m = m.act(m.snooze)
+		case "o":
+			m.byTitle = !m.byTitle
+			m = m.reload()
+			m.notice = "Sorted by urgency."
+			if m.byTitle {
+				m.notice = "Sorted by title."
 			}
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
-				m.cursor++
+		case "y":
+			if t := m.current(); t != nil {
+				m = m.copyTasks([]*taskmodel.Task{t})
 			}
+		case "Y":
+			m = m.copyTasks(m.list)
+		case "u":
+			m = m.rewind(false)
+		case "ctrl+r":
+			m = m.rewind(true)
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			m = m.applySaved(int(msg.Runes[0] - '0'))
 		case "enter", " ":
-			_, ok := m.selected[m.cursor]
-			if ok {
-				delete(m.selected, m.cursor)
-			} else {
-				m.selected[m.cursor] = struct{}{}
+			t := m.current()
+			m = m.act(func(t *taskmodel.Task) (string, error) {
+				if t.Status == taskmodel.StatusDone {
+					return m.reopen(t)
+				}
+				return m.complete(t)
+			})
+			m, cmd = m.celebrate(t)
+		}
+		return m.claim(), cmd
+	}
+
+	return m, nil
+}
+
+// resolveKey handles a key on the conflicts screen: l keeps this device's
+// value of the first conflict, r takes the other copy's, and esc returns
+// to the list.
+func (m model) resolveKey(key string) (tea.Model, tea.Cmd) {
+	side := conflict.Local
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.resolving = false
+		return m, nil
+	case "l":
+	case "r":
+		side = conflict.Remote
+	default:
+		return m, nil
+	}
+	if len(m.conflicts) == 0 {
+		m.resolving = false
+		return m, nil
+	}
+	e := m.conflicts[0]
+	if err := m.tasks.ResolveConflict(m.conflictsOf(m.journal), e.ID, e.Fields[0], side); err != nil {
+		m.notice = "Cannot resolve the conflict: " + err.Error()
+		return m, nil
+	}
+	m.notice = ""
+	m = m.refresh()
+	m.resolving = len(m.conflicts) > 0
+	return m, nil
+}
+
+// statsKey handles a key on the statistics screen: w, m, y and a show
+// the last week, month, year or everything, and esc returns to the list.
+func (m model) statsKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "s":
+		m.statsRange = ""
+	default:
+		for _, r := range statsRanges {
+			if key == r[:1] {
+				m = m.showStats(r)
 			}
 		}
 	}
+	return m, nil
+}
+
+// showStats opens the statistics screen on the named range, one of
+// statsRanges, computing the statistics over it up to now.
+func (m model) showStats(name string) model {
+	now := taskmodel.Now()
+	from, _ := statsFrom(name, now)
+	m.statsRange = name
+	m.rangeStats, m.statsErr = m.tasks.Stats(from, now)
+	return m
+}
+
+// statsView renders the statistics screen.
+func (m model) statsView() string {
+	s := "Statistics for the last " + m.statsRange
+	if m.statsRange == "all" {
+		s = "Statistics for all time"
+	}
+	s += "\n\n"
+	if m.statsErr != nil {
+		s += m.statsErr.Error() + "\n"
+	} else {
+		s += statsReport(m.rangeStats)
+	}
+	return s + "\nw: week, m: month, y: year, a: all time. Press esc to go back.\n"
+}
 
+// startReview opens the review of the journal's tasks, or reports that
+// there is nothing to review.
+func (m model) startReview() model {
+	if m.tasks == nil {
+		return m
+	}
+	items, err := m.tasks.ReviewItems(m.reviewPolicy, taskmodel.Now())
+	switch {
+	case err != nil:
+		m.notice = err.Error()
+	case len(items) == 0:
+		m.notice = "Nothing to review."
+		m = m.finishReview()
+	default:
+		m.review, m.reviewed = items, reviewTally{}
+	}
+	return m
+}
+
+// reviewKey handles a key in the review: k keeps the task shown, t and w
+// reschedule it to tomorrow or next week, a archives it, d deletes it and
+// s skips it; esc leaves the review unfinished.
+func (m model) reviewKey(key string) (tea.Model, tea.Cmd) {
+	today := taskmodel.StartOfDay(taskmodel.Now())
+	decision, day := "", time.Time{}
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.review = nil
+		m = m.reload()
+		m.notice = m.reviewed.String()
+		return m, nil
+	case "k":
+		decision = "keep"
+	case "t":
+		decision, day = "reschedule", today.AddDate(0, 0, 1)
+	case "w":
+		decision, day = "reschedule", today.AddDate(0, 0, 7)
+	case "a":
+		decision = "archive"
+	case "d":
+		decision = "delete"
+	case "s":
+		decision = "skip"
+	default:
+		return m, nil
+	}
+	if err := m.reviewed.decide(m.tasks, m.review[0].Task.ID, decision, day); err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	m.notice = ""
+	m.review = m.review[1:]
+	if len(m.review) == 0 {
+		m = m.reload()
+		m.notice = m.reviewed.String()
+		m = m.finishReview()
+	}
 	return m, nil
 }
 
-func (m model) View() string {
-	// The header
-	s := "What should we buy at the market?\n\n"
+// finishReview records that a review was finished, adding a failure to
+// do so to the notice.
+func (m model) finishReview() model {
+	if m.markReviewed == nil {
+		return m
+	}
+	if err := m.markReviewed(); err != nil {
+		m.notice += " Cannot record the review: " + err.Error()
+	}
+	return m
+}
+
+// reviewView renders the review, one task at a time.
+func (m model) reviewView() string {
+	item := m.review[0]
+	done := m.reviewed.total()
+	s := fmt.Sprintf("Review: task %d of %d\n\n", done+1, done+len(m.review))
+	s += fmt.Sprintf("  %s\n  %s\n", item.Task.Title, item.Describe(taskmodel.Now()))
+	if m.notice != "" {
+		s += "\n" + m.notice + "\n"
+	}
+	return s + "\nk: keep, t: tomorrow, w: next week, a: archive, d: delete, s: skip. Press esc to stop.\n"
+}
+
+// startPlan opens the plan screen with a proposed today list, or reports
+// that there is nothing to propose.
+func (m model) startPlan() model {
+	if m.tasks == nil {
+		return m
+	}
+	plan, err := m.tasks.PlanDay(m.planPolicy, taskmodel.Now())
+	switch {
+	case err != nil:
+		m.notice = err.Error()
+	case len(plan.Items) == 0:
+		m.notice = "Nothing to plan."
+	default:
+		m.planning, m.plan, m.planSkip, m.planCursor = true, plan, map[int]bool{}, 0
+	}
+	return m
+}
+
+// planKey handles a key on the plan screen: space takes the task under
+// the cursor out of the plan or puts it back, enter moves the planned
+// tasks to today, and esc leaves the plan unaccepted.
+func (m model) planKey(key string) (tea.Model, tea.Cmd) {
+	switch m.navKey(key) {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.planning = false
+	case "up":
+		m.planCursor = max(m.planCursor-1, 0)
+	case "down":
+		m.planCursor = min(m.planCursor+1, len(m.plan.Items)-1)
+	case " ", "x":
+		m.planSkip[m.planCursor] = !m.planSkip[m.planCursor]
+	case "enter":
+		var ids []taskmodel.TaskID
+		for i, item := range m.plan.Items {
+			if !m.planSkip[i] {
+				ids = append(ids, item.Task.ID)
+			}
+		}
+		moved, err := m.tasks.AcceptPlan(ids)
+		m.planning = false
+		m = m.reload()
+		m.notice = fmt.Sprintf("Moved %d %s to today.", len(moved), plural(len(moved), "task"))
+		if err != nil {
+			m.notice = err.Error()
+		}
+	}
+	return m, nil
+}
+
+// planEffort returns the effort the day holds with the plan as adjusted.
+func (m model) planEffort() time.Duration {
+	total := m.plan.Committed
+	for i, item := range m.plan.Items {
+		if !m.planSkip[i] {
+			total += item.Effort
+		}
+	}
+	return total
+}
+
+// planView renders the plan screen.
+func (m model) planView() string {
+	s := "Plan for today: " + taskmodel.FormatEstimate(m.planEffort())
+	if m.plan.Capacity > 0 {
+		s += " of " + taskmodel.FormatEstimate(m.plan.Capacity)
+	}
+	s += " planned\n\n"
+	for i, item := range m.plan.Items {
+		cursor, checked := " ", "x"
+		if m.planCursor == i {
+			cursor = ">"
+		}
+		if m.planSkip[i] {
+			checked = " "
+		}
+		s += fmt.Sprintf("%s [%s] %s (%s", cursor, checked, item.Task.Title, taskmodel.FormatEstimate(item.Effort))
+		if item.Due {
+			s += ", due"
+		}
+		s += ")\n"
+	}
+	return s + "\nspace: take out or put back, enter: move to today. Press esc to cancel.\n"
+}
+
+// startTags opens the tag screen, or reports that no task has tags.
+func (m model) startTags() model {
+	if m.tasks == nil {
+		return m
+	}
+	tags, err := m.tasks.Tags()
+	switch {
+	case err != nil:
+		m.notice = err.Error()
+	case len(tags) == 0:
+		m.notice = "No tags."
+	default:
+		m.tagging, m.tags, m.tagCursor, m.tagMarked = true, tags, 0, map[string]bool{}
+	}
+	return m
+}
+
+// tagKey handles a key on the tag screen: space marks the tag under the
+// cursor for merging, r renames it, or m merges the marked tags, once the
+// new name is typed and enter pressed; esc goes back.
+func (m model) tagKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.tagEditing {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.tagEditing = false
+		case "enter":
+			notice, err := retag(m.tasks, m.tagSources(), strings.TrimSpace(m.tagEdit.Value()))
+			if err != nil {
+				m.notice = err.Error()
+				return m, nil
+			}
+			m = m.reload().startTags()
+			m.tagEditing, m.notice = false, notice
+		default:
+			m.tagEdit.Update(msg)
+		}
+		return m, nil
+	}
+	m.notice = ""
+	switch m.navKey(msg.String()) {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "#":
+		m.tagging = false
+	case "up":
+		m.tagCursor = max(m.tagCursor-1, 0)
+	case "down":
+		m.tagCursor = min(m.tagCursor+1, len(m.tags)-1)
+	case " ":
+		tag := m.tags[m.tagCursor].Tag
+		m.tagMarked[tag] = !m.tagMarked[tag]
+	case "r", "m":
+		m.tagEditing, m.tagEdit = true, textinput.New(m.tags[m.tagCursor].Tag)
+	}
+	return m, nil
+}
+
+// tagSources returns the tags an edit on the tag screen renames: the
+// marked ones, or else the one under the cursor.
+func (m model) tagSources() []string {
+	var marked []string
+	for _, tc := range m.tags {
+		if m.tagMarked[tc.Tag] {
+			marked = append(marked, tc.Tag)
+		}
+	}
+	if len(marked) == 0 {
+		return []string{m.tags[m.tagCursor].Tag}
+	}
+	return marked
+}
+
+// tagView renders the tag screen.
+func (m model) tagView() string {
+	s := "Tags\n\n"
+	for i, tc := range m.tags {
+		cursor, checked := " ", " "
+		if m.tagCursor == i {
+			cursor = ">"
+		}
+		if m.tagMarked[tc.Tag] {
+			checked = "x"
+		}
+		s += fmt.Sprintf("%s [%s] %s (%d)\n", cursor, checked, tc.Tag, tc.Count)
+	}
+	if m.notice != "" {
+		s += "\n" + m.notice + "\n"
+	}
+	if m.tagEditing {
+		sources, verb := m.tagSources(), "Rename"
+		if len(sources) > 1 {
+			verb = "Merge"
+		}
+		s += fmt.Sprintf("\n%s %s to: %s\n", verb, strings.Join(sources, ", "), m.tagEdit.View(reverse))
+		return s + "\nenter: apply, esc: cancel.\n"
+	}
+	return s + "\nspace: mark for merging, r: rename, m: merge marked. Press esc to go back.\n"
+}
+
+// searchKey handles a key in the search bar: editing keys change the
+// query, which filters the list as it is typed, enter keeps it and esc
+// goes back to the query before the search. A query that does not parse
+// yet, such as an unclosed quote, leaves the list as it was.
+func (m model) searchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter":
+		m.searching, m.notice = false, ""
+		return m, nil
+	case "esc":
+		m.searching, m.notice = false, ""
+		return m.searchFor(m.searchFrom), nil
+	}
+	if m.search.Update(msg) {
+		m = m.searchFor(m.search.Value())
+	}
+	return m, nil
+}
+
+// searchFor lists the tasks matching input, or reports why it does not
+// parse.
+func (m model) searchFor(input string) model {
+	filter, err := query.Parse(input)
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	m.filter, m.opts.query, m.notice = filter, input, ""
+	return m.refresh()
+}
+
+// reverse renders s in reverse video, as a cursor.
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[27m"
+}
 
-	// Iterate over our choices
-	for i, choice := range m.choices {
+// claim takes the edit lock on the task under the cursor, if locks are
+// shared, and notes which tasks other processes hold.
+func (m model) claim() model {
+	if m.claimEdit == nil {
+		return m
+	}
+	var id taskmodel.TaskID
+	if t := m.current(); t != nil {
+		id = t.ID
+	}
+	if others, err := m.claimEdit(id); err == nil {
+		m.lockedBy = others
+	}
+	return m
+}
+
+// act runs a use case on the task under the cursor, reporting its outcome
+// in the notice, and rereads the list. A task another process is editing
+// is left alone.
+func (m model) act(do func(t *taskmodel.Task) (string, error)) model {
+	t := m.current()
+	if m.tasks == nil || t == nil {
+		return m
+	}
+	if lock, ok := m.lockedBy[t.ID]; ok {
+		m.notice = fmt.Sprintf("Not changed: %q is %s.", t.Title, lock.Describe())
+		return m
+	}
+	notice, err := do(t)
+	if err != nil {
+		notice = err.Error()
+	}
+	m = m.reload()
+	m.notice = notice
+	return m
+}
+
+// copyTasks puts tasks on the clipboard as Markdown, or as plain text or
+// JSON when the copy quickly follows the previous one, reporting it in
+// the notice.
+func (m model) copyTasks(tasks []*taskmodel.Task) model {
+	if m.terminal == nil || len(tasks) == 0 {
+		return m
+	}
+	format := m.copyCycle.Press(taskmodel.Now())
+	text, err := clipboard.Render(tasks, format)
+	if err == nil {
+		err = clipboard.Write(m.terminal, text)
+	}
+	if err != nil {
+		m.notice = "Cannot copy: " + err.Error()
+		return m
+	}
+	m.notice = fmt.Sprintf("Copied %d %s as %s; press again for another format.", len(tasks), plural(len(tasks), "task"), format)
+	return m
+}
 
-		// Is the cursor pointing at this choice?
-		cursor := " " // no cursor
-		if m.cursor == i {
-			cursor = ">" // cursor!
+// Confetti is drawn confettiWidth by confettiHeight cells, a frame every
+// confettiFrame.
+const (
+	confettiWidth  = 40
+	confettiHeight = 3
+	confettiFrame  = 80 * time.Millisecond
+)
+
+// nextConfetti returns a command asking for the next confetti frame.
+func nextConfetti() tea.Cmd {
+	return tea.Tick(confettiFrame, func(time.Time) tea.Msg { return confettiMsg{} })
+}
+
+// celebrate gives the feedback celebrator describes on completing before,
+// the task under the cursor before a key was handled, if the key took it
+// from open to done. Reaching a streak milestone is added to the notice.
+func (m model) celebrate(before *taskmodel.Task) (model, tea.Cmd) {
+	if !m.celebrator.Enabled() || before == nil || before.Status == taskmodel.StatusDone {
+		return m, nil
+	}
+	status := taskmodel.StatusDone
+	done, err := m.tasks.ListTasks(taskmodel.TaskFilter{Status: &status})
+	if err != nil || !slices.ContainsFunc(done, func(t *taskmodel.Task) bool { return t.ID == before.ID }) {
+		return m, nil
+	}
+	now := taskmodel.Now()
+	e := m.celebrator.Event(before.Title, taskmodel.CompletionStreak(done, now))
+	if e.Milestone {
+		m.notice = strings.TrimSpace(m.notice + " " + e.Message())
+	}
+	if m.celebrator.Mode == celebrate.ModeConfetti {
+		m.confetti = celebrate.Frames(e, confettiWidth, confettiHeight, uint64(now.UnixNano()))
+		return m, nextConfetti()
+	}
+	celebrator, terminal := m.celebrator, m.terminal
+	if terminal == nil {
+		terminal = io.Discard
+	}
+	return m, func() tea.Msg {
+		return celebratedMsg{celebrator.Celebrate(context.Background(), terminal, e)}
+	}
+}
+
+// rewind undoes the latest change, or redoes the latest undone one,
+// reporting it in the notice, and rereads the list.
+func (m model) rewind(redo bool) model {
+	if m.tasks == nil {
+		return m
+	}
+	step, verb, did := m.tasks.Undo, "undo", "Undid"
+	if redo {
+		step, verb, did = m.tasks.Redo, "redo", "Redid"
+	}
+	done, err := step()
+	notice := fmt.Sprintf("%s: %s.", did, done)
+	switch {
+	case errors.Is(err, service.ErrNothingToUndo), errors.Is(err, service.ErrNothingToRedo):
+		notice = "Nothing to " + verb + "."
+	case err != nil:
+		notice = fmt.Sprintf("Cannot %s %s: %v", verb, done, err)
+	}
+	m = m.reload()
+	m.notice = notice
+	return m
+}
+
+// complete, moveToToday, deferTask, reopen and togglePin run the use
+// cases of the same names, or PinTask, on t, for act, and describe their
+// outcome.
+func (m model) complete(t *taskmodel.Task) (string, error) {
+	_, err := m.tasks.CompleteTask(t.ID)
+	return "Completed.", err
+}
+
+func (m model) moveToToday(t *taskmodel.Task) (string, error) {
+	_, warning, err := m.tasks.MoveToToday(t.ID)
+	if warning != nil {
+		return fmt.Sprintf("Moved to today; that makes %d/%d.", warning.Count, warning.Limit), err
+	}
+	return "Moved to today.", err
+}
+
+func (m model) deferTask(t *taskmodel.Task) (string, error) {
+	_, warning, err := m.tasks.DeferTask(t.ID, nil)
+	if warning != nil {
+		return fmt.Sprintf("Deferred; that makes %d times.", warning.Count), err
+	}
+	return "Deferred.", err
+}
+
+func (m model) reopen(t *taskmodel.Task) (string, error) {
+	_, err := m.tasks.ReopenTask(t.ID)
+	return "Reopened.", err
+}
+
+func (m model) togglePin(t *taskmodel.Task) (string, error) {
+	notice := "Pinned."
+	if t.Pinned {
+		notice = "Unpinned."
+	}
+	_, err := m.tasks.PinTask(t.ID, !t.Pinned)
+	return notice, err
+}
+
+// snooze hides t until tomorrow morning.
+func (m model) snooze(t *taskmodel.Task) (string, error) {
+	until := taskmodel.StartOfDay(taskmodel.Now()).AddDate(0, 0, 1)
+	_, err := m.tasks.SnoozeTask(t.ID, until)
+	return "Snoozed until tomorrow.", err
+}
+
+// boardColumns are the columns of the board, one per status.
+var boardColumns = [...]struct {
+	status taskmodel.TaskStatus
+	title  string
+}{
+	{taskmodel.StatusPool, "Pool"},
+	{taskmodel.StatusToday, "Today"},
+	{taskmodel.StatusDone, "Done"},
+}
+
+// boardWidth is the width of a board column in terminal cells.
+const boardWidth = 28
+
+// titleWidth and tagsWidth bound the title and tags of a list row in
+// terminal cells, so oversized tasks stay on one line.
+const (
+	titleWidth = 60
+	tagsWidth  = 40
+)
+
+// column returns the listed tasks of the ith board column.
+func (m model) column(i int) []*taskmodel.Task {
+	var out []*taskmodel.Task
+	for _, t := range m.list {
+		if t.Status == boardColumns[i].status {
+			out = append(out, t)
 		}
+	}
+	return out
+}
+
+// current returns the task under the cursor, in the list or on the board,
+// or nil when there is none.
+func (m model) current() *taskmodel.Task {
+	if m.opts.view == viewBoard {
+		if col := m.column(m.boardColumn); m.boardRows[m.boardColumn] < len(col) {
+			return col[m.boardRows[m.boardColumn]]
+		}
+		return nil
+	}
+	if m.cursor < len(m.list) {
+		return m.list[m.cursor]
+	}
+	return nil
+}
+
+// vimKeys maps the vim movement keys to the arrow keys they stand for.
+var vimKeys = map[string]string{
+	"k": "up", "j": "down", "h": "left", "l": "right",
+	"H": "shift+left", "L": "shift+right",
+}
+
+// keyName spells out vim keys such as "h/l" as the arrow keys they stand
+// for under the arrows key map.
+func (m model) keyName(keys string) string {
+	if m.keymap != config.KeymapArrows {
+		return keys
+	}
+	return strings.NewReplacer("h/l", "←/→", "H/L", "shift+←/→", "k/j", "↑/↓").Replace(keys)
+}
 
-		// Is this choice selected?
-		checked := " " // not selected
-		if _, ok := m.selected[i]; ok {
-			checked = "x" // selected!
+// navKey returns the arrow key that key stands for under the vim key map,
+// or key itself.
+func (m model) navKey(key string) string {
+	if arrow, ok := vimKeys[key]; ok && m.keymap != config.KeymapArrows {
+		return arrow
+	}
+	return key
+}
+
+// moveCursor moves the cursor by delta rows, within the list or the
+// focused board column.
+func (m model) moveCursor(delta int) model {
+	if m.opts.view == viewBoard {
+		row := &m.boardRows[m.boardColumn]
+		*row = max(min(*row+delta, len(m.column(m.boardColumn))-1), 0)
+		return m
+	}
+	m.cursor = max(min(m.cursor+delta, len(m.list)-1), 0)
+	return m
+}
+
+// moveAcross moves the task under the board's cursor to the column in
+// direction dir, -1 for left or 1 for right, and keeps the cursor on it:
+// from the pool to today or from today to done, and back by deferring it
+// or, from done, by reopening it, which returns it to the pool.
+func (m model) moveAcross(dir int) model {
+	t := m.current()
+	if m.opts.view != viewBoard || t == nil {
+		return m
+	}
+	var do func(*taskmodel.Task) (string, error)
+	switch {
+	case dir > 0 && t.Status == taskmodel.StatusPool:
+		do = m.moveToToday
+	case dir > 0 && t.Status == taskmodel.StatusToday:
+		do = m.complete
+	case dir < 0 && t.Status == taskmodel.StatusToday:
+		do = m.deferTask
+	case dir < 0 && t.Status == taskmodel.StatusDone:
+		do = m.reopen
+	default:
+		return m
+	}
+	m = m.act(do)
+	for i := range boardColumns {
+		if row := slices.IndexFunc(m.column(i), func(c *taskmodel.Task) bool { return c.ID == t.ID }); row >= 0 {
+			m.boardColumn, m.boardRows[i] = i, row
 		}
+	}
+	return m
+}
 
-		// Render the row
-		s += fmt.Sprintf("%s [%s] %s\n", cursor, checked, choice)
+// applySaved switches to the saved filter bound to number key n, if any,
+// or reports why its query no longer parses, keeping the current filter.
+func (m model) applySaved(n int) model {
+	f, ok := m.saved.Hotkey(n)
+	if !ok {
+		return m
+	}
+	filter, err := query.Parse(f.Query)
+	if err != nil {
+		m.notice = fmt.Sprintf("Cannot apply the saved filter %q: %v", f.Name, err)
+		return m
+	}
+	m.filter = filter
+	m.opts.query = f.Query
+	return m.refresh()
+}
+
+// nextJournal switches to the journal after the open one, wrapping around,
+// or reports why it cannot be opened, staying on the open one.
+func (m model) nextJournal() model {
+	if m.openJournal == nil || len(m.journals) < 2 {
+		return m
+	}
+	i := (slices.Index(m.journals, m.journal) + 1) % len(m.journals)
+	tasks, err := m.openJournal(m.journals[i])
+	if err != nil {
+		m.notice = fmt.Sprintf("Cannot open the journal %q: %v", m.journals[i], err)
+		return m
+	}
+	m.journal, m.tasks = m.journals[i], tasks
+	if m.titleOrderOf != nil {
+		m.titleOrder = m.titleOrderOf(m.journal)
+	}
+	return m.refresh().startDay()
+}
+
+// startDay runs the daily housekeeping on the open journal, creating the
+// recurring tasks that came due and, where enabled, the rollover and
+// archiving, reporting what it did in the notice, and rereads the list.
+func (m model) startDay() model {
+	if m.tasks == nil {
+		return m
+	}
+	var notices []string
+	created, err := m.tasks.Recur(taskmodel.Now())
+	switch {
+	case err != nil:
+		notices = append(notices, "Creating recurring tasks failed: "+err.Error())
+	case len(created) > 0:
+		notices = append(notices, recurSummary(created))
+	}
+	if m.rollover {
+		r, err := m.tasks.Rollover(taskmodel.Now())
+		switch {
+		case err != nil:
+			notices = append(notices, "Rollover failed: "+err.Error())
+		case len(r.Deferred)+len(r.Pulled) > 0:
+			notices = append(notices, rolloverSummary(r))
+		}
+	}
+	if m.archiveDone != nil {
+		n, err := m.archiveDone()
+		switch {
+		case err != nil:
+			notices = append(notices, "Archiving failed: "+err.Error())
+		case n > 0:
+			notices = append(notices, fmt.Sprintf("Archived %d completed %s.", n, plural(n, "task")))
+		}
+	}
+	if len(notices) == 0 {
+		return m
+	}
+	m = m.reload()
+	m.notice = strings.Join(notices, " ")
+	return m
+}
+
+// refresh reloads the list from the service, if any, with pinned tasks
+// first and the pool by urgency, resetting the cursor, and the journal's
+// unresolved conflicts, leaving the conflicts screen when none are left.
+func (m model) refresh() model {
+	if m.tasks == nil {
+		return m
+	}
+	m.conflicts = nil
+	if m.conflictsOf != nil {
+		if sidecar := m.conflictsOf(m.journal); sidecar != nil {
+			if entries, err := sidecar.Entries(); err == nil {
+				m.conflicts = entries
+			}
+		}
+	}
+	if len(m.conflicts) == 0 {
+		m.resolving = false
+	}
+	tasks, err := m.tasks.ListTasks(m.filter)
+	if err != nil {
+		return m
+	}
+	now := taskmodel.Now()
+	if m.byTitle {
+		m.titleOrder.SortTasks(tasks)
+	} else {
+		sortPool(tasks, now)
+	}
+	taskmodel.SortPinnedFirst(tasks)
+	m.list, m.cursor, m.boardRows = tasks, 0, [len(boardColumns)]int{}
+	m.stats = taskmodel.ComputeQuickStats(tasks, now)
+	m.todayCount, m.todayLimit, err = m.tasks.TodayCapacity()
+	if err != nil {
+		m.todayLimit = 0
+	}
+	if m.statsRange != "" {
+		m = m.showStats(m.statsRange)
+	}
+	m.nudges = nil
+	if m.lastReviewed != nil {
+		if last, err := m.lastReviewed(); err == nil {
+			m.nudges, _ = m.tasks.ReviewNudges(m.nudgePolicy, last, now)
+		}
+	}
+	return m
+}
+
+// sortPool orders the pool tasks among tasks by urgency at now, most
+// urgent first, leaving the other tasks where they are, so the pool reads
+// from its most pressing end in the list and on the board alike.
+func sortPool(tasks []*taskmodel.Task, now time.Time) {
+	var slots []int
+	var pool []*taskmodel.Task
+	for i, t := range tasks {
+		if t.Status == taskmodel.StatusPool {
+			slots, pool = append(slots, i), append(pool, t)
+		}
+	}
+	taskmodel.SortByUrgency(pool, now)
+	for i, slot := range slots {
+		tasks[slot] = pool[i]
+	}
+}
+
+// reload rereads the tasks after they changed, here or in another
+// process. Unlike refresh it keeps the cursor on the same task, or on the
+// same row when that task is no longer listed, and the board's cursors on
+// the same rows.
+func (m model) reload() model {
+	if m.tasks != nil {
+		m.tasks.Invalidate()
+	}
+	cursor, rows, at := m.cursor, m.boardRows, taskmodel.TaskID{}
+	if cursor < len(m.list) {
+		at = m.list[cursor].ID
+	}
+	m = m.refresh()
+	m.cursor = min(cursor, max(len(m.list)-1, 0))
+	if i := slices.IndexFunc(m.list, func(t *taskmodel.Task) bool { return t.ID == at }); i >= 0 {
+		m.cursor = i
+	}
+	for i, row := range rows {
+		m.boardRows[i] = min(row, max(len(m.column(i))-1, 0))
+	}
+	return m
+}
+
+func (m model) View() string {
+	if m.whatsNew != "" {
+		return m.whatsNew + "\nPress any key to continue.\n"
+	}
+	if m.resolving {
+		return m.conflictView()
+	}
+	if m.statsRange != "" {
+		return m.statsView()
+	}
+	if len(m.review) > 0 {
+		return m.reviewView()
+	}
+	if m.planning {
+		return m.planView()
+	}
+	if m.tagging {
+		return m.tagView()
+	}
+
+	// The header
+	s := "Tasks\n"
+	if m.opts.demo {
+		s += "Demo mode: changes are not saved.\n"
+	}
+	if len(m.journals) > 1 {
+		s += fmt.Sprintf("Journal: %s (J to switch)\n", m.journal)
+	}
+	if m.searching {
+		s += fmt.Sprintf("Search: %s\n", m.search.View(reverse))
+	} else if m.opts.query != "" {
+		s += fmt.Sprintf("Filter: %s\n", m.opts.query)
+	}
+	if m.todayLimit > 0 {
+		s += fmt.Sprintf("Today: %d/%d\n", m.todayCount, m.todayLimit)
+	}
+	if n := conflictCount(m.conflicts); n > 0 {
+		s += fmt.Sprintf("%d sync %s to resolve (c to review)\n", n, plural(n, "conflict"))
+	}
+	if len(m.confetti) > 0 {
+		s += m.theme.Accent(m.confetti[0]) + "\n"
+	}
+	for _, n := range m.nudges {
+		s += m.theme.Muted(n.Message) + "\n"
+	}
+	s += "\n"
+
+	// The tasks, ticked when done, or the board
+	now := taskmodel.Now()
+	if m.opts.view == viewBoard {
+		if len(m.list) > 0 {
+			s += m.boardView()
+		}
+	} else {
+		for i, t := range m.list {
+			cursor := " "
+			if m.cursor == i {
+				cursor = m.theme.Accent(">")
+			}
+			s += fmt.Sprintf("%s %s\n", cursor, m.taskRow(t, now))
+		}
+	}
+
+	if m.tasks != nil && len(m.list) == 0 {
+		s += "No tasks.\n"
+	} else if m.tasks != nil {
+		s += "\n" + m.stats.Footer() + "\n"
+	}
+	if m.notice != "" {
+		s += "\n" + m.notice + "\n"
 	}
 
 	// The footer
-	s += "\nPress q to quit.\n"
+	if m.searching {
+		s += "\nenter: keep the search, esc: cancel.\n"
+	} else if m.tasks != nil && m.opts.view == viewBoard {
+		s += fmt.Sprintf("\n%s: column, %s: move task across, space: done/not done, z: snooze, *: pin, o: sort, y/Y: copy task/all, u: undo, b: list, s: stats, r: review, p: plan, #: tags, /: search. Press q to quit.\n", m.keyName("h/l"), m.keyName("H/L"))
+	} else if m.tasks != nil {
+		s += "\nspace: done/not done, x: complete, t: move to today, d: defer, z: snooze, *: pin, o: sort, y/Y: copy task/all, u: undo, b: board, s: stats, r: review, p: plan, #: tags, /: search. Press q to quit.\n"
+	} else {
+		s += "\nPress q to quit.\n"
+	}
 
 	// Send the UI for rendering
 	return s
 }
 
-func main() {
-	p := tea.NewProgram(initializeModel())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Alas, there's been an error: %v", err)
-		os.Exit(1)
+// boardView renders the listed tasks as a board, a column per status side
+// by side.
+func (m model) boardView() string {
+	var columns [len(boardColumns)][]*taskmodel.Task
+	rows := 0
+	for i := range boardColumns {
+		columns[i] = m.column(i)
+		rows = max(rows, len(columns[i]))
+	}
+	var b strings.Builder
+	cells := make([]string, len(columns))
+	line := func() {
+		b.WriteString(strings.TrimRight(strings.Join(cells, " "), " ") + "\n")
+	}
+	for i, c := range boardColumns {
+		cells[i] = display.Pad(fmt.Sprintf("  %s (%d)", c.title, len(columns[i])), boardWidth)
+	}
+	line()
+	for r := range rows {
+		for i, col := range columns {
+			cells[i] = strings.Repeat(" ", boardWidth)
+			if r < len(col) {
+				cursor := " "
+				if i == m.boardColumn && r == m.boardRows[i] {
+					cursor = m.theme.Accent(">")
+				}
+				cells[i] = cursor + " " + display.Pad(col[r].Title, boardWidth-2)
+			}
+		}
+		line()
+	}
+	return b.String()
+}
+
+// taskRow renders t as a row of the list: ticked when done, with its ID
+// shown as idDisplay says, status, whether it is pinned, tags, the record
+// it was imported from, due date and who else is editing it. URLs are
+// linked as links says.
+func (m model) taskRow(t *taskmodel.Task, now time.Time) string {
+	checked := " "
+	if t.Status == taskmodel.StatusDone {
+		checked = "x"
+	}
+	title := m.links.Linkify(display.Truncate(t.Title, titleWidth))
+	row := fmt.Sprintf("[%s] %s %s  %s", checked, m.idDisplay.Format(t), title, t.Status)
+	if t.Pinned {
+		row += "  pinned"
+	}
+	if t.IsStale() {
+		row += "  stale"
+	}
+	if len(t.Tags) > 0 {
+		row += " " + display.Tags(t.Tags, tagsWidth)
+	}
+	if t.ExternalRef != nil {
+		row += "  " + m.links.Ref(*t.ExternalRef)
+	}
+	switch {
+	case t.DueDate == nil:
+	case t.IsOverdue(now):
+		row += "  " + m.theme.Warning("overdue since "+t.DueDate.Format(time.DateOnly))
+	case t.IsDueToday(now):
+		row += "  due today"
+	default:
+		row += "  due " + t.DueDate.Format(time.DateOnly)
+	}
+	if lock, ok := m.lockedBy[t.ID]; ok {
+		row += "  " + lock.Describe()
+	}
+	if t.Status == taskmodel.StatusDone {
+		return m.theme.Muted(row)
+	}
+	return row
+}
+
+// conflictView renders the first unresolved conflict with the choice of
+// values.
+func (m model) conflictView() string {
+	if len(m.conflicts) == 0 {
+		return "No sync conflicts left. Press esc to go back.\n"
 	}
+	e := m.conflicts[0]
+	s := fmt.Sprintf("Sync conflict 1 of %d, in %q (from %s)\n\n", conflictCount(m.conflicts), e.Title, e.Source)
+	for _, c := range e.Changes() {
+		if c.Field == e.Fields[0] {
+			s += fmt.Sprintf("  %s\n", c)
+		}
+	}
+	if m.notice != "" {
+		s += "\n" + m.notice + "\n"
+	}
+	s += "\nl: keep this device's value, r: take the other copy's, esc: back\n"
+	return s
+}
+
+// conflictCount returns how many fields entries leave unresolved.
+func conflictCount(entries []conflict.Entry) int {
+	n := 0
+	for _, e := range entries {
+		n += len(e.Fields)
+	}
+	return n
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -1,10 +1,30 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"togo/internal/celebrate"
+	"togo/internal/collation"
+	"togo/internal/config"
+	"togo/internal/conflict"
+	"togo/internal/display"
+	"togo/internal/editlock"
+	taskmodel "togo/internal/model"
+	"togo/internal/repository/memstore"
+	"togo/internal/service"
+	"togo/internal/termlink"
+	"togo/internal/testutil"
+	"togo/internal/theme"
 )
 
 // helper to build rune-based key messages used in tests (e.g. "j", "k", "q", " ")
@@ -12,18 +32,41 @@ func keyMsg(s string) tea.KeyMsg {
 	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
 }
 
+// titles returns the titles of the listed tasks, in order.
+func titles(m model) []string {
+	var out []string
+	for _, t := range m.list {
+		out = append(out, t.Title)
+	}
+	return out
+}
+
+// listModel returns the TUI listing the tasks builders build.
+func listModel(t *testing.T, builders ...*testutil.TaskBuilder) (model, *memstore.Repository) {
+	t.Helper()
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(builders...)...)
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	return m.refresh(), repo
+}
+
 func TestInitializeModel(t *testing.T) {
 	m := initializeModel()
-	if len(m.choices) != 3 {
-		t.Fatalf("expected 3 choices, got %d", len(m.choices))
+	if m.tasks != nil || len(m.list) != 0 {
+		t.Fatalf("expected nothing open, got %d tasks", len(m.list))
 	}
-	if len(m.selected) != 0 {
-		t.Fatalf("expected selected to be empty, got %v", m.selected)
+	if view := m.View(); !strings.Contains(view, "Press q to quit.") || strings.Contains(view, "No tasks.") {
+		t.Errorf("unexpected view with nothing open:\n%s", view)
 	}
 }
 
 func TestNavigationBounds(t *testing.T) {
-	m := initializeModel()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("Eat"),
+		testutil.NewTask().WithTitle("Sleep"),
+		testutil.NewTask().WithTitle("Dream"),
+	)
 
 	// at the top, pressing 'k' (up) should not move the cursor
 	nm, _ := m.Update(keyMsg("k"))
@@ -53,21 +96,393 @@ func TestNavigationBounds(t *testing.T) {
 	}
 }
 
-func TestToggleSelection(t *testing.T) {
+func TestToggleDone(t *testing.T) {
+	m, repo := listModel(t,
+		testutil.NewTask().WithTitle("Eat").WithStatus(taskmodel.StatusToday),
+		testutil.NewTask().WithTitle("Sleep"),
+	)
+	id := m.list[1].ID
+	m.cursor = 1
+
+	tests := []struct {
+		key        tea.KeyMsg
+		wantStatus taskmodel.TaskStatus
+		wantNotice string
+	}{
+		{key: keyMsg(" "), wantStatus: taskmodel.StatusDone, wantNotice: "Completed."},
+		{key: tea.KeyMsg{Type: tea.KeyEnter}, wantStatus: taskmodel.StatusPool, wantNotice: "Reopened."},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(tt.key)
+		m = nm.(model)
+		if got, _ := repo.Get(id); got.Status != tt.wantStatus {
+			t.Errorf("status after %q = %q, want %q", tt.key, got.Status, tt.wantStatus)
+		}
+		if m.cursor != 1 || !strings.Contains(m.View(), tt.wantNotice) {
+			t.Errorf("after %q: cursor %d, view:\n%s", tt.key, m.cursor, m.View())
+		}
+	}
+}
+
+func TestEditLocks(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, repo := listModel(t,
+		testutil.NewTask().WithTitle("Eat").WithStatus(taskmodel.StatusToday).WithCreatedAt(now.Add(-time.Hour)),
+		testutil.NewTask().WithTitle("Sleep").WithCreatedAt(now),
+	)
+	eat := m.list[0].ID
+	table := editlock.Open(filepath.Join(t.TempDir(), "tasks.json.locks"), time.Minute)
+	m.claimEdit = func(id taskmodel.TaskID) (map[taskmodel.TaskID]taskmodel.EditLock, error) {
+		return table.Claim(id, "laptop")
+	}
+	if _, err := table.Claim(eat, "desktop"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key        string
+		release    bool
+		want       string
+		wantStatus taskmodel.TaskStatus
+	}{
+		{key: "j", want: "Eat  today  being edited on desktop\n", wantStatus: taskmodel.StatusToday},
+		{key: "k", want: "Eat  today  being edited on desktop\n", wantStatus: taskmodel.StatusToday},
+		{key: "x", want: `Not changed: "Eat" is being edited on desktop.`, wantStatus: taskmodel.StatusToday},
+		{key: "x", release: true, want: "Completed.", wantStatus: taskmodel.StatusDone},
+	}
+	for _, tt := range tests {
+		if tt.release {
+			if err := table.Release("desktop"); err != nil {
+				t.Fatal(err)
+			}
+			nm, _ := m.Update(journalChangedMsg{})
+			m = nm.(model)
+		}
+		nm, _ := m.Update(keyMsg(tt.key))
+		m = nm.(model)
+		if !strings.Contains(m.View(), tt.want) {
+			t.Errorf("after %q: view does not contain %q:\n%s", tt.key, tt.want, m.View())
+		}
+		if got, _ := repo.Get(eat); got.Status != tt.wantStatus {
+			t.Errorf("after %q: status = %q, want %q", tt.key, got.Status, tt.wantStatus)
+		}
+	}
+}
+
+func TestKeymap(t *testing.T) {
+	tests := []struct {
+		keymap     string
+		key        tea.KeyMsg
+		wantCursor int
+		wantFooter string
+	}{
+		{keymap: config.KeymapVim, key: keyMsg("j"), wantCursor: 1, wantFooter: "h/l: column, H/L: move task across"},
+		{keymap: config.KeymapVim, key: tea.KeyMsg{Type: tea.KeyDown}, wantCursor: 1},
+		{keymap: config.KeymapArrows, key: keyMsg("j"), wantCursor: 0, wantFooter: "←/→: column, shift+←/→: move task across"},
+		{keymap: config.KeymapArrows, key: tea.KeyMsg{Type: tea.KeyDown}, wantCursor: 1},
+	}
+	for _, tt := range tests {
+		m, _ := listModel(t, testutil.NewTask().WithTitle("Eat"), testutil.NewTask().WithTitle("Sleep"))
+		m.keymap = tt.keymap
+		nm, _ := m.Update(tt.key)
+		if got := nm.(model).cursor; got != tt.wantCursor {
+			t.Errorf("%s: cursor after %q = %d, want %d", tt.keymap, tt.key, got, tt.wantCursor)
+		}
+		m.opts.view = viewBoard
+		if view := m.View(); !strings.Contains(view, tt.wantFooter) {
+			t.Errorf("%s: board footer does not contain %q:\n%s", tt.keymap, tt.wantFooter, view)
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithDue(now.AddDate(0, 0, -2)).WithCreatedAt(now.Add(-time.Hour)),
+		testutil.NewTask().WithTitle("Call the dentist").WithCreatedAt(now).WithStatus(taskmodel.StatusDone),
+	)
+	m.theme = theme.Dark
+
+	view := m.View()
+	for _, want := range []string{
+		theme.Dark.Accent(">") + " [ ]",
+		theme.Dark.Warning("overdue since 2025-11-10"),
+		theme.Dark.Muted("[x] " + m.list[1].ID.Short() + " Call the dentist  done"),
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%q", want, view)
+		}
+	}
+}
+
+func TestReviewNudges(t *testing.T) {
+	now := time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t, testutil.NewTask().WithTitle("File taxes").WithCreatedAt(now.AddDate(0, 0, -1)))
+	m.nudgePolicy = taskmodel.DefaultNudgePolicy()
+	m.lastReviewed = func() (time.Time, error) { return now.AddDate(0, 0, -10), nil }
+	m = m.refresh()
+
+	view := m.View()
+	for _, want := range []string{
+		"Tasks\nYou haven't done a review in 10 days\n",
+		"Your today list is empty — pick something from the pool\n\n",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%q", want, view)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithTags("admin").WithCreatedAt(now.Add(-time.Hour)),
+		testutil.NewTask().WithTitle("Buy milk").WithCreatedAt(now),
+	)
+	var terminal bytes.Buffer
+	m.terminal = &terminal
+
+	tests := []struct {
+		key        string
+		at         time.Time
+		wantText   string
+		wantNotice string
+	}{
+		{key: "y", at: now, wantText: "- [ ] File taxes #admin", wantNotice: "Copied 1 task as markdown; press again for another format."},
+		{key: "y", at: now.Add(time.Second), wantText: "File taxes [admin]", wantNotice: "Copied 1 task as plain; press again for another format."},
+		{key: "Y", at: now.Add(time.Minute), wantText: "- [ ] File taxes #admin\n- [ ] Buy milk", wantNotice: "Copied 2 tasks as markdown; press again for another format."},
+	}
+	for _, tt := range tests {
+		restore := taskmodel.SetClock(taskmodel.NewFixedClock(tt.at))
+		terminal.Reset()
+		nm, _ := m.Update(keyMsg(tt.key))
+		restore()
+		m = nm.(model)
+		want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(tt.wantText)) + "\a"
+		if terminal.String() != want {
+			t.Errorf("%s: terminal got %q, want %q", tt.key, terminal.String(), want)
+		}
+		if m.notice != tt.wantNotice {
+			t.Errorf("%s: notice = %q, want %q", tt.key, m.notice, tt.wantNotice)
+		}
+	}
+}
+
+func TestHyperlinks(t *testing.T) {
+	ref := taskmodel.ExternalRef{System: "jira", ID: "PROJ-12"}
+	m, _ := listModel(t, testutil.NewTask().WithTitle("Read https://go.dev/doc").WithExternalRef(ref))
+	m.links = termlink.Linker{Enabled: true, RefURLs: map[string]string{"jira": "https://example.atlassian.net/browse/%s"}}
+
+	view := m.View()
+	for _, want := range []string{
+		"Read " + termlink.Link("https://go.dev/doc", "https://go.dev/doc") + "  pool",
+		"  " + termlink.Link("https://example.atlassian.net/browse/PROJ-12", "jira:PROJ-12") + "\n",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%q", want, view)
+		}
+	}
+}
+
+func TestOversizedRow(t *testing.T) {
+	tags := make([]string, 40)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag%d", i)
+	}
+	m, _ := listModel(t, testutil.NewTask().WithTitle(strings.Repeat("long ", 30)).WithTags(tags...))
+
+	view := m.View()
+	for _, want := range []string{display.Ellipsis + "  pool", " +"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view does not contain %q:\n%s", want, view)
+		}
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "  pool") && len(line) > 200 {
+			t.Errorf("row is %d bytes long: %q", len(line), line)
+		}
+	}
+}
+
+func TestStaleBadge(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	defer taskmodel.SetStaleThreshold(7 * 24 * time.Hour)()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("Clean the garage").WithCreatedAt(now.AddDate(0, 0, -30)),
+		testutil.NewTask().WithTitle("Buy milk").WithCreatedAt(now.Add(-time.Hour)),
+	)
+
+	view := m.View()
+	if !strings.Contains(view, "Clean the garage  pool  stale") {
+		t.Errorf("old task has no stale badge:\n%s", view)
+	}
+	if strings.Contains(view, "Buy milk  pool  stale") {
+		t.Errorf("new task has a stale badge:\n%s", view)
+	}
+}
+
+func TestCelebration(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+
+	tests := []struct {
+		name         string
+		mode         celebrate.Mode
+		streak       int
+		wantTerminal string
+		wantNotice   string
+		wantConfetti bool
+	}{
+		{name: "off", mode: celebrate.ModeOff, wantNotice: "Completed."},
+		{name: "bell", mode: celebrate.ModeBell, wantTerminal: "\a", wantNotice: "Completed."},
+		{name: "milestone bell", mode: celebrate.ModeBell, streak: 2, wantTerminal: "\a\a", wantNotice: "Completed. 3-day streak! Keep it going."},
+		{name: "confetti", mode: celebrate.ModeConfetti, wantNotice: "Completed.", wantConfetti: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builders := []*testutil.TaskBuilder{testutil.NewTask().WithTitle("File taxes").WithCreatedAt(now.AddDate(0, 0, -5))}
+			for d := 1; d <= tt.streak; d++ {
+				builders = append(builders, testutil.NewTask().WithCreatedAt(now.AddDate(0, 0, -5+d)).WithHistory(testutil.Completed(now.AddDate(0, 0, -d))))
+			}
+			m, _ := listModel(t, builders...)
+			var terminal bytes.Buffer
+			m.terminal, m.celebrator = &terminal, celebrate.Celebrator{Mode: tt.mode}
+
+			nm, cmd := m.Update(keyMsg("x"))
+			m = nm.(model)
+			if cmd != nil && !tt.wantConfetti {
+				if msg := cmd(); msg != (celebratedMsg{}) {
+					t.Errorf("celebration reported %+v", msg)
+				}
+			}
+			if terminal.String() != tt.wantTerminal {
+				t.Errorf("terminal got %q, want %q", terminal.String(), tt.wantTerminal)
+			}
+			if m.notice != tt.wantNotice {
+				t.Errorf("notice = %q, want %q", m.notice, tt.wantNotice)
+			}
+			if got := len(m.confetti) > 0 && cmd != nil; got != tt.wantConfetti {
+				t.Errorf("confetti shown = %v, want %v", got, tt.wantConfetti)
+			}
+		})
+	}
+}
+
+func TestPoolByUrgency(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("Someday").WithCreatedAt(now.Add(-4*time.Hour)),
+		testutil.NewTask().WithTitle("Overdue").WithDue(now.AddDate(0, 0, -2)).WithCreatedAt(now.Add(-3*time.Hour)),
+		testutil.NewTask().WithTitle("Done").WithStatus(taskmodel.StatusDone).WithCreatedAt(now.Add(-2*time.Hour)),
+		testutil.NewTask().WithTitle("Important").WithPriority(taskmodel.PriorityHigh).WithCreatedAt(now.Add(-time.Hour)),
+	)
+
+	if got, want := titles(m), []string{"Overdue", "Important", "Done", "Someday"}; !slices.Equal(got, want) {
+		t.Errorf("list = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFailed(t *testing.T) {
+	errs := make(chan error, 1)
 	m := initializeModel()
+	m.writeErrs = errs
 
-	// toggle select the first item using space
-	nm, _ := m.Update(keyMsg(" "))
-	got := nm.(model)
-	if _, ok := got.selected[0]; !ok {
-		t.Fatalf("expected item 0 to be selected")
+	nm, cmd := m.Update(writeFailedMsg{errors.New("disk full")})
+	if notice := nm.(model).notice; notice != "Cannot save the journal: disk full" {
+		t.Errorf("notice = %q", notice)
+	}
+	errs <- errors.New("still full")
+	if msg, ok := cmd().(writeFailedMsg); !ok || msg.err == nil || msg.err.Error() != "still full" {
+		t.Errorf("next message = %v, want the next failure", msg)
 	}
+}
 
-	// toggle again to deselect
-	nm2, _ := got.Update(keyMsg(" "))
-	got2 := nm2.(model)
-	if _, ok := got2.selected[0]; ok {
-		t.Fatalf("expected item 0 to be deselected")
+func TestPinKey(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithStatus(taskmodel.StatusToday).WithCreatedAt(now.Add(-2*time.Hour)),
+		testutil.NewTask().WithTitle("Call the dentist").WithStatus(taskmodel.StatusToday).WithCreatedAt(now.Add(-time.Hour)),
+	)
+
+	tests := []struct {
+		key        string
+		wantTitles []string
+		wantNotice string
+	}{
+		{key: "j", wantTitles: []string{"File taxes", "Call the dentist"}},
+		{key: "*", wantTitles: []string{"Call the dentist", "File taxes"}, wantNotice: "Pinned."},
+		{key: "*", wantTitles: []string{"File taxes", "Call the dentist"}, wantNotice: "Unpinned."},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(keyMsg(tt.key))
+		m = nm.(model)
+		if got := titles(m); !slices.Equal(got, tt.wantTitles) {
+			t.Errorf("after %s list = %q, want %q", tt.key, got, tt.wantTitles)
+		}
+		if m.notice != tt.wantNotice {
+			t.Errorf("after %s notice = %q, want %q", tt.key, m.notice, tt.wantNotice)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "*: pin") {
+		t.Errorf("footer does not offer the pin key:\n%s", view)
+	}
+}
+
+func TestSnoozeKey(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithCreatedAt(now.Add(-2*time.Hour)),
+		testutil.NewTask().WithTitle("Call the dentist").WithCreatedAt(now.Add(-time.Hour)),
+	)
+
+	nm, _ := m.Update(keyMsg("z"))
+	m = nm.(model)
+	if got, want := titles(m), []string{"Call the dentist"}; !slices.Equal(got, want) {
+		t.Errorf("after z list = %q, want %q", got, want)
+	}
+	if m.notice != "Snoozed until tomorrow." {
+		t.Errorf("notice = %q", m.notice)
+	}
+
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now.AddDate(0, 0, 1)))()
+	if got, want := titles(m.reload()), []string{"File taxes", "Call the dentist"}; !slices.Equal(got, want) {
+		t.Errorf("next day list = %q, want %q", got, want)
+	}
+}
+
+func TestSortKey(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("Zucker kaufen").WithDue(now).WithCreatedAt(now.Add(-3*time.Hour)),
+		testutil.NewTask().WithTitle("Äpfel kaufen").WithCreatedAt(now.Add(-2*time.Hour)),
+		testutil.NewTask().WithTitle("Brot backen").WithCreatedAt(now.Add(-time.Hour)),
+	)
+	m.titleOrder, _ = collation.New("de")
+
+	tests := []struct {
+		wantTitles []string
+		wantNotice string
+	}{
+		{wantTitles: []string{"Äpfel kaufen", "Brot backen", "Zucker kaufen"}, wantNotice: "Sorted by title."},
+		{wantTitles: []string{"Zucker kaufen", "Äpfel kaufen", "Brot backen"}, wantNotice: "Sorted by urgency."},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(keyMsg("o"))
+		m = nm.(model)
+		if got := titles(m); !slices.Equal(got, tt.wantTitles) {
+			t.Errorf("list = %q, want %q", got, tt.wantTitles)
+		}
+		if m.notice != tt.wantNotice {
+			t.Errorf("notice = %q, want %q", m.notice, tt.wantNotice)
+		}
 	}
 }
 
@@ -81,25 +496,359 @@ func TestQuitCommand(t *testing.T) {
 }
 
 func TestViewRendering(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, _ := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithTags("admin", "home").WithDue(now.AddDate(0, 0, -2)).WithCreatedAt(now.Add(-4*time.Hour)).WithSeq(1),
+		testutil.NewTask().WithTitle("Buy milk").WithStatus(taskmodel.StatusToday).WithDue(now).WithCreatedAt(now.Add(-3*time.Hour)).WithSeq(2),
+		testutil.NewTask().WithTitle("Renew passport").WithDue(now.AddDate(0, 0, 14)).WithCreatedAt(now.Add(-2*time.Hour)).WithSeq(3).WithEstimate(90*time.Minute),
+		testutil.NewTask().WithTitle("Call the dentist").WithCreatedAt(now.Add(-time.Hour)).WithStatus(taskmodel.StatusDone).WithSeq(4),
+	)
+	m.idDisplay = taskmodel.IDDisplaySequence
+	nm, _ := m.Update(keyMsg("j"))
+	view := nm.(model).View()
+
+	want := "Tasks\n\n" +
+		"  [ ] #1 File taxes  pool #admin #home  overdue since 2025-11-10\n" +
+		"> [ ] #2 Buy milk  today  due today\n" +
+		"  [ ] #3 Renew passport  pool  due 2025-11-26\n" +
+		"  [x] #4 Call the dentist  done\n" +
+		"\n4 tasks · 1 overdue · ~1h30m estimated · 1 done today\n"
+	if !strings.HasPrefix(view, want) {
+		t.Fatalf("view = %q, want it to start with %q", view, want)
+	}
+}
+
+func TestTaskActions(t *testing.T) {
+	tests := []struct {
+		key        string
+		status     taskmodel.TaskStatus
+		wantStatus taskmodel.TaskStatus
+		wantNotice string
+	}{
+		{key: "x", status: taskmodel.StatusToday, wantStatus: taskmodel.StatusDone, wantNotice: "Completed."},
+		{key: "t", status: taskmodel.StatusPool, wantStatus: taskmodel.StatusToday, wantNotice: "Moved to today."},
+		{key: "d", status: taskmodel.StatusToday, wantStatus: taskmodel.StatusPool, wantNotice: "Deferred."},
+		{key: "x", status: taskmodel.StatusDone, wantStatus: taskmodel.StatusDone, wantNotice: "invalid state transition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+" "+string(tt.status), func(t *testing.T) {
+			repo := memstore.New()
+			task := testutil.NewTask().WithTitle("Water the plants").WithStatus(tt.status).Build()
+			testutil.MustSeed(t, repo, task)
+			m := initializeModel()
+			m.tasks = service.New(repo, nil)
+			m = m.refresh()
+
+			nm, _ := m.Update(keyMsg(tt.key))
+			m = nm.(model)
+			if got, _ := repo.Get(task.ID); got.Status != tt.wantStatus {
+				t.Errorf("status after %q = %q, want %q", tt.key, got.Status, tt.wantStatus)
+			}
+			if view := m.View(); !strings.Contains(view, tt.wantNotice) {
+				t.Errorf("view does not report %q:\n%s", tt.wantNotice, view)
+			}
+			nm, _ = m.Update(keyMsg("j"))
+			if view := nm.(model).View(); strings.Contains(view, tt.wantNotice) {
+				t.Errorf("notice still shown after the next key:\n%s", view)
+			}
+		})
+	}
+}
+
+func TestTodayLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      service.TodayLimit
+		wantStatus taskmodel.TaskStatus
+		wantHeader string
+		wantNotice string
+	}{
+		{name: "uncapped", wantStatus: taskmodel.StatusToday, wantNotice: "Moved to today."},
+		{name: "room left", limit: service.TodayLimit{Max: 3}, wantStatus: taskmodel.StatusToday, wantHeader: "Today: 2/3", wantNotice: "Moved to today."},
+		{name: "warn", limit: service.TodayLimit{Max: 1}, wantStatus: taskmodel.StatusToday, wantHeader: "Today: 2/1", wantNotice: "Moved to today; that makes 2/1."},
+		{name: "block", limit: service.TodayLimit{Max: 1, Block: true}, wantStatus: taskmodel.StatusPool, wantHeader: "Today: 1/1", wantNotice: "today's list is full (1 of 1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memstore.New()
+			task := testutil.NewTask().WithTitle("Water the plants").Build()
+			testutil.MustSeed(t, repo, task, testutil.NewTask().WithTitle("Buy milk").WithStatus(taskmodel.StatusToday).Build())
+			m := initializeModel()
+			m.tasks = service.New(repo, nil)
+			m.tasks.SetTodayLimit(tt.limit)
+			m = m.refresh()
+			m.cursor = slices.Index(titles(m), "Water the plants")
+
+			nm, _ := m.Update(keyMsg("t"))
+			m = nm.(model)
+			if got, _ := repo.Get(task.ID); got.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			view := m.View()
+			if !strings.Contains(view, tt.wantNotice) {
+				t.Errorf("view does not report %q:\n%s", tt.wantNotice, view)
+			}
+			if tt.wantHeader == "" && strings.Contains(view, "Today:") {
+				t.Errorf("uncapped list shows its capacity:\n%s", view)
+			}
+			if !strings.Contains(view, tt.wantHeader+"\n") {
+				t.Errorf("header lacks %q:\n%s", tt.wantHeader, view)
+			}
+		})
+	}
+}
+
+func TestUndoKey(t *testing.T) {
+	repo := memstore.New()
+	task := testutil.NewTask().WithTitle("Water the plants").WithStatus(taskmodel.StatusToday).Build()
+	testutil.MustSeed(t, repo, task)
 	m := initializeModel()
-	// move cursor to second item and select third
-	nm, _ := m.Update(keyMsg("j")) // cursor -> 1
-	got := nm.(model)
-	// select third (move down then press space)
-	nm2, _ := got.Update(keyMsg("j")) // cursor -> 2
-	got2 := nm2.(model)
-	nm3, _ := got2.Update(keyMsg(" ")) // select index 2
-	got3 := nm3.(model)
+	m.tasks = service.New(repo, nil)
+	m = m.refresh()
 
-	view := got3.View()
+	tests := []struct {
+		key        string
+		wantStatus taskmodel.TaskStatus
+		wantNotice string
+	}{
+		{key: "x", wantStatus: taskmodel.StatusDone, wantNotice: "Completed."},
+		{key: "u", wantStatus: taskmodel.StatusToday, wantNotice: `Undid: complete "Water the plants".`},
+		{key: "u", wantStatus: taskmodel.StatusToday, wantNotice: "Nothing to undo."},
+		{key: "ctrl+r", wantStatus: taskmodel.StatusDone, wantNotice: `Redid: complete "Water the plants".`},
+	}
+	for _, tt := range tests {
+		msg := keyMsg(tt.key)
+		if tt.key == "ctrl+r" {
+			msg = tea.KeyMsg{Type: tea.KeyCtrlR}
+		}
+		nm, _ := m.Update(msg)
+		m = nm.(model)
+		if got, _ := repo.Get(task.ID); got.Status != tt.wantStatus {
+			t.Errorf("status after %q = %q, want %q", tt.key, got.Status, tt.wantStatus)
+		}
+		if view := m.View(); !strings.Contains(view, tt.wantNotice) {
+			t.Errorf("view after %q does not report %q:\n%s", tt.key, tt.wantNotice, view)
+		}
+	}
+}
 
-	// expect cursor marker on the last line for cursor==2
-	if !strings.Contains(view, "> [") {
-		t.Fatalf("expected view to contain a cursor marker '>' somewhere; got:\n%s", view)
+// conflictModel returns a model of a journal whose task's title conflicts
+// with a sync copy's, with the repository, sidecar and task's ID.
+func conflictModel(t *testing.T) (model, *memstore.Repository, *conflict.Sidecar, taskmodel.TaskID) {
+	t.Helper()
+	repo := memstore.New()
+	sidecar := conflict.Open(filepath.Join(t.TempDir(), "tasks.json"+conflict.Ext), nil)
+	mine := testutil.NewTask().WithTitle("Renew passport").Build()
+	theirs := mine.Clone()
+	theirs.Title = "Renew passports"
+	merged, entry, err := conflict.Merge(nil, mine, theirs, "tasks.sync-conflict-20240615-101500-ABCDEFG.json")
+	if err != nil || entry == nil {
+		t.Fatalf("Merge() = %v, %v; want a conflict", entry, err)
 	}
+	testutil.MustSeed(t, repo, merged)
+	if err := sidecar.Add(*entry); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m.conflictsOf = func(string) *conflict.Sidecar { return sidecar }
+	m = m.refresh()
+	return m, repo, sidecar, mine.ID
+}
 
-	// expect the selected marker 'x' for the third item
-	if !strings.Contains(view, "[x] Dream") {
-		t.Fatalf("expected view to show selected item for 'Dream'; got:\n%s", view)
+func TestResolveConflicts(t *testing.T) {
+	m, repo, _, id := conflictModel(t)
+	if view := m.View(); !strings.Contains(view, "1 sync conflict to resolve") {
+		t.Fatalf("view does not mention the conflict:\n%s", view)
+	}
+
+	nm, _ := m.Update(keyMsg("c"))
+	m = nm.(model)
+	if view := m.View(); !strings.Contains(view, `title: "Renew passport" → "Renew passports"`) {
+		t.Fatalf("conflict screen does not show both titles:\n%s", view)
+	}
+	nm, _ = m.Update(keyMsg("r"))
+	m = nm.(model)
+	if m.resolving || len(m.conflicts) != 0 {
+		t.Errorf("still resolving after the last conflict: %v", m.conflicts)
+	}
+	if got, _ := repo.Get(id); got.Title != "Renew passports" {
+		t.Errorf("title after taking the other copy's = %q", got.Title)
+	}
+}
+
+func TestResolveConflicts_Error(t *testing.T) {
+	m, repo, sidecar, id := conflictModel(t)
+	nm, _ := m.Update(keyMsg("c"))
+	m = nm.(model)
+	// Another process resolves the conflict while it is on screen.
+	if err := sidecar.Resolve(repo, id, "title", conflict.Local); err != nil {
+		t.Fatal(err)
+	}
+
+	nm, _ = m.Update(keyMsg("l"))
+	m = nm.(model)
+	if view := m.View(); !strings.Contains(view, "Cannot resolve the conflict: ") || !strings.Contains(view, "no conflict on title") {
+		t.Errorf("conflict screen does not report the failure:\n%s", view)
+	}
+}
+
+func TestResolveConflicts_ResolvedElsewhere(t *testing.T) {
+	m, repo, sidecar, id := conflictModel(t)
+	nm, _ := m.Update(keyMsg("c"))
+	m = nm.(model)
+	if err := sidecar.Resolve(repo, id, "title", conflict.Remote); err != nil {
+		t.Fatal(err)
+	}
+
+	nm, _ = m.Update(journalChangedMsg{})
+	m = nm.(model)
+	if m.resolving {
+		t.Errorf("still on the conflicts screen after they were resolved elsewhere")
+	}
+	if view := m.View(); strings.Contains(view, "Sync conflict") {
+		t.Errorf("view after the reload still shows a conflict:\n%s", view)
+	}
+	nm, _ = m.Update(keyMsg("l"))
+	if nm.(model).resolving {
+		t.Errorf("l after the reload reopened the conflicts screen")
+	}
+}
+
+func TestPlanScreen(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(
+		testutil.NewTask().WithTitle("File taxes").WithPriority(taskmodel.PriorityHigh).WithEstimate(2*time.Hour),
+		testutil.NewTask().WithTitle("Water the plants").WithPriority(taskmodel.PriorityLow),
+		testutil.NewTask().WithTitle("Repaint the fence").WithEstimate(8*time.Hour),
+	)...)
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m.planPolicy = service.PlanPolicy{Capacity: 6 * time.Hour, Weights: taskmodel.DefaultUrgencyWeights()}
+	m = m.refresh()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "p", want: "Plan for today: 2h30m of 6h planned\n\n> [x] File taxes (2h)\n  [x] Water the plants (30m)\n"},
+		{key: "j", want: "  [x] File taxes (2h)\n> [x] Water the plants (30m)\n"},
+		{key: " ", want: "Plan for today: 2h of 6h planned\n\n  [x] File taxes (2h)\n> [ ] Water the plants (30m)\n"},
+		{key: "enter", want: "Moved 1 task to today."},
+		{key: "p", want: "Plan for today: 2h30m of 6h planned\n\n> [x] Water the plants (30m)\n"},
+		{key: "esc", want: "p: plan, #: tags, /: search. Press q to quit."},
+	}
+	for _, tt := range tests {
+		msg := keyMsg(tt.key)
+		switch tt.key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace}
+		}
+		nm, _ := m.Update(msg)
+		m = nm.(model)
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("view after %q lacks %q:\n%s", tt.key, tt.want, view)
+		}
+	}
+	today := taskmodel.StatusToday
+	if tasks, _ := repo.List(taskmodel.TaskFilter{Status: &today}); len(tasks) != 1 || tasks[0].Title != "File taxes" {
+		t.Errorf("today holds %v, want only File taxes", tasks)
+	}
+}
+
+func TestSearchBar(t *testing.T) {
+	repo := memstore.New()
+	testutil.MustSeed(t, repo, testutil.Tasks(
+		testutil.NewTask().WithTitle("File taxes").WithTags("admin"),
+		testutil.NewTask().WithTitle("Water the plants").WithNotes("Tax the fern less"),
+		testutil.NewTask().WithTitle("Renew passport").WithTags("admin"),
+	)...)
+	m := initializeModel()
+	m.tasks = service.New(repo, nil)
+	m = m.refresh()
+
+	tests := []struct {
+		key      tea.KeyMsg
+		want     []string
+		wantNone string
+	}{
+		{key: keyMsg("/"), want: []string{"Search: ", "enter: keep the search, esc: cancel."}},
+		{key: keyMsg("tax"), want: []string{"Search: tax", "File taxes", "Water the plants"}, wantNone: "Renew passport"},
+		{key: keyMsg(` "`), want: []string{"query:", "File taxes", "Water the plants"}, wantNone: "Renew passport"},
+		{key: tea.KeyMsg{Type: tea.KeyBackspace}, want: []string{"File taxes"}, wantNone: "query:"},
+		{key: keyMsg("+admin"), want: []string{"File taxes"}, wantNone: "Water the plants"},
+		{key: tea.KeyMsg{Type: tea.KeyEnter}, want: []string{"Filter: tax +admin", "/: search. Press q to quit."}},
+		{key: keyMsg("/"), want: []string{"Search: tax +admin"}},
+		{key: tea.KeyMsg{Type: tea.KeyCtrlU}, want: []string{"File taxes", "Water the plants", "Renew passport"}},
+		{key: tea.KeyMsg{Type: tea.KeyEsc}, want: []string{"Filter: tax +admin", "File taxes"}, wantNone: "Renew passport"},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(tt.key)
+		m = nm.(model)
+		view := m.View()
+		for _, want := range tt.want {
+			if !strings.Contains(view, want) {
+				t.Errorf("view after %q lacks %q:\n%s", tt.key, want, view)
+			}
+		}
+		if tt.wantNone != "" && strings.Contains(view, tt.wantNone) {
+			t.Errorf("view after %q shows %q:\n%s", tt.key, tt.wantNone, view)
+		}
+	}
+}
+
+func TestBoardView(t *testing.T) {
+	now := time.Date(2025, 11, 12, 9, 0, 0, 0, time.UTC)
+	defer taskmodel.SetClock(taskmodel.NewFixedClock(now))()
+	m, repo := listModel(t,
+		testutil.NewTask().WithTitle("File taxes").WithCreatedAt(now.Add(-4*time.Hour)).WithSeq(1),
+		testutil.NewTask().WithTitle("Renew passport").WithCreatedAt(now.Add(-3*time.Hour)),
+		testutil.NewTask().WithTitle("Buy milk").WithStatus(taskmodel.StatusToday).WithCreatedAt(now.Add(-2*time.Hour)),
+		testutil.NewTask().WithTitle("Call the dentist").WithStatus(taskmodel.StatusDone).WithCreatedAt(now.Add(-time.Hour)),
+	)
+	taxes := m.list[0].ID
+	m.idDisplay = taskmodel.IDDisplaySequence
+
+	tests := []struct {
+		key        string
+		want       string
+		wantStatus taskmodel.TaskStatus
+	}{
+		{key: "b", wantStatus: taskmodel.StatusPool, want: "" +
+			"  Pool (2)                     Today (1)                    Done (1)\n" +
+			"> File taxes                   Buy milk                     Call the dentist\n" +
+			"  Renew passport\n"},
+		{key: "L", wantStatus: taskmodel.StatusToday, want: "" +
+			"  Pool (1)                     Today (2)                    Done (1)\n" +
+			"  Renew passport             > File taxes                   Call the dentist\n" +
+			"                               Buy milk\n"},
+		{key: "L", wantStatus: taskmodel.StatusDone, want: "" +
+			"  Pool (1)                     Today (1)                    Done (2)\n" +
+			"  Renew passport               Buy milk                   > File taxes\n" +
+			"                                                            Call the dentist\n"},
+		{key: "L", wantStatus: taskmodel.StatusDone, want: "> File taxes\n"},
+		{key: "H", wantStatus: taskmodel.StatusPool, want: "" +
+			"  Pool (2)                     Today (1)                    Done (1)\n" +
+			"> File taxes                   Buy milk                     Call the dentist\n" +
+			"  Renew passport\n"},
+		{key: "j", wantStatus: taskmodel.StatusPool, want: "> Renew passport\n"},
+		{key: "l", wantStatus: taskmodel.StatusPool, want: "  File taxes                 > Buy milk                     Call the dentist\n"},
+		{key: "b", wantStatus: taskmodel.StatusPool, want: "> [ ] #1 File taxes  pool\n"},
+	}
+	for _, tt := range tests {
+		nm, _ := m.Update(keyMsg(tt.key))
+		m = nm.(model)
+		if view := m.View(); !strings.Contains(view, tt.want) {
+			t.Errorf("view after %q lacks %q:\n%s", tt.key, tt.want, view)
+		}
+		if got, _ := repo.Get(taxes); got.Status != tt.wantStatus {
+			t.Errorf("status after %q = %q, want %q", tt.key, got.Status, tt.wantStatus)
+		}
 	}
 }